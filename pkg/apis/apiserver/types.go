@@ -166,3 +166,74 @@ type TracingConfiguration struct {
 	// Defaults to 0.
 	SamplingRatePerMillion *int32
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuthenticationConfiguration provides versioned configuration for authentication.
+type AuthenticationConfiguration struct {
+	metav1.TypeMeta
+
+	// JWT is a list of authenticators for JWT bearer tokens issued by OIDC providers.
+	JWT []JWTAuthenticator
+}
+
+// JWTAuthenticator provides the configuration for a single JWT authenticator.
+type JWTAuthenticator struct {
+	// Issuer is the basic OIDC provider connection options.
+	Issuer Issuer
+
+	// ClaimValidationRules are rules that are applied to validate token claims to authenticate users.
+	// +optional
+	ClaimValidationRules []ClaimValidationRule
+
+	// ClaimMappings points claims of a token to be treated as user attributes.
+	ClaimMappings ClaimMappings
+}
+
+// Issuer provides the configuration for an external provider specific settings.
+type Issuer struct {
+	// URL points to the issuer URL in a format https://url/path.
+	// This must match the "iss" claim in the presented JWT, and the issuer returned from discovery.
+	URL string
+
+	// Audiences is the set of acceptable audiences the JWT must be issued to.
+	// At least one of the entries must match the "aud" claim in presented JWTs.
+	Audiences []string
+
+	// CertificateAuthority contains PEM-encoded certificate authority certificates
+	// used to validate the connection when fetching discovery information.
+	// If unset, the system verifier is used.
+	// +optional
+	CertificateAuthority string
+}
+
+// ClaimValidationRule provides the configuration for a single claim validation rule.
+type ClaimValidationRule struct {
+	// Claim is the name of a required claim.
+	Claim string
+
+	// RequiredValue is the value of a required claim.
+	// +optional
+	RequiredValue string
+}
+
+// ClaimMappings provides the configuration for claim mapping.
+type ClaimMappings struct {
+	// Username represents an option for the username attribute.
+	Username PrefixedClaimOrExpression
+
+	// Groups represents an option for the groups attribute.
+	// +optional
+	Groups PrefixedClaimOrExpression
+}
+
+// PrefixedClaimOrExpression provides the configuration for a single prefixed claim.
+type PrefixedClaimOrExpression struct {
+	// Claim is the JWT claim to use.
+	Claim string
+
+	// Prefix is prepended to the claim value to prevent clashes with existing names.
+	// If unset, usernames are prefixed with the issuer URL followed by "#" unless the claim is "email".
+	// +optional
+	Prefix *string
+}
@@ -167,3 +167,76 @@ type TracingConfiguration struct {
 	// Defaults to 0.
 	SamplingRatePerMillion *int32 `json:"samplingRatePerMillion,omitempty" protobuf:"varint,2,opt,name=samplingRatePerMillion"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuthenticationConfiguration provides versioned configuration for authentication.
+type AuthenticationConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// jwt is a list of authenticators for JWT bearer tokens issued by OIDC providers.
+	// Adding or removing an entry takes effect without restarting the server
+	// when the configuration file is reloaded.
+	JWT []JWTAuthenticator `json:"jwt"`
+}
+
+// JWTAuthenticator provides the configuration for a single JWT authenticator.
+type JWTAuthenticator struct {
+	// issuer contains the basic OIDC provider connection options.
+	Issuer Issuer `json:"issuer"`
+
+	// claimValidationRules are rules that are applied to validate token claims to authenticate users.
+	// +optional
+	ClaimValidationRules []ClaimValidationRule `json:"claimValidationRules,omitempty"`
+
+	// claimMappings points claims of a token to be treated as user attributes.
+	ClaimMappings ClaimMappings `json:"claimMappings"`
+}
+
+// Issuer provides the configuration for an external provider specific settings.
+type Issuer struct {
+	// url points to the issuer URL in a format https://url/path.
+	// This must match the "iss" claim in the presented JWT, and the issuer returned from discovery.
+	URL string `json:"url"`
+
+	// audiences is the set of acceptable audiences the JWT must be issued to.
+	// At least one of the entries must match the "aud" claim in presented JWTs.
+	Audiences []string `json:"audiences"`
+
+	// certificateAuthority contains PEM-encoded certificate authority certificates
+	// used to validate the connection when fetching discovery information.
+	// If unset, the system verifier is used.
+	// +optional
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+}
+
+// ClaimValidationRule provides the configuration for a single claim validation rule.
+type ClaimValidationRule struct {
+	// claim is the name of a required claim.
+	Claim string `json:"claim"`
+
+	// requiredValue is the value of a required claim.
+	// +optional
+	RequiredValue string `json:"requiredValue,omitempty"`
+}
+
+// ClaimMappings provides the configuration for claim mapping.
+type ClaimMappings struct {
+	// username represents an option for the username attribute.
+	Username PrefixedClaimOrExpression `json:"username"`
+
+	// groups represents an option for the groups attribute.
+	// +optional
+	Groups PrefixedClaimOrExpression `json:"groups,omitempty"`
+}
+
+// PrefixedClaimOrExpression provides the configuration for a single prefixed claim.
+type PrefixedClaimOrExpression struct {
+	// claim is the JWT claim to use.
+	Claim string `json:"claim"`
+
+	// prefix is prepended to the claim value to prevent clashes with existing names.
+	// If unset, usernames are prefixed with the issuer URL followed by "#" unless the claim is "email".
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+}
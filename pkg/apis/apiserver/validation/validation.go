@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	api "k8s.io/apiserver/pkg/apis/apiserver"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// ValidateAuthenticationConfiguration validates a given AuthenticationConfiguration.
+func ValidateAuthenticationConfiguration(c *api.AuthenticationConfiguration) field.ErrorList {
+	var allErrs field.ErrorList
+	root := field.NewPath("jwt")
+	issuers := sets.NewString()
+	for i, a := range c.JWT {
+		fldPath := root.Index(i)
+		allErrs = append(allErrs, validateJWTAuthenticator(a, fldPath)...)
+		if issuers.Has(a.Issuer.URL) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("issuer", "url"), a.Issuer.URL))
+		}
+		issuers.Insert(a.Issuer.URL)
+	}
+	return allErrs
+}
+
+func validateJWTAuthenticator(a api.JWTAuthenticator, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateIssuer(a.Issuer, fldPath.Child("issuer"))...)
+	allErrs = append(allErrs, validateClaimValidationRules(a.ClaimValidationRules, fldPath.Child("claimValidationRules"))...)
+	allErrs = append(allErrs, validateClaimMappings(a.ClaimMappings, fldPath.Child("claimMappings"))...)
+	return allErrs
+}
+
+func validateIssuer(i api.Issuer, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	urlPath := fldPath.Child("url")
+	if len(i.URL) == 0 {
+		allErrs = append(allErrs, field.Required(urlPath, "issuer url is required"))
+	} else if u, err := url.Parse(i.URL); err != nil {
+		allErrs = append(allErrs, field.Invalid(urlPath, i.URL, err.Error()))
+	} else {
+		if u.Scheme != "https" {
+			allErrs = append(allErrs, field.Invalid(urlPath, i.URL, "url scheme must be https"))
+		}
+		if u.User != nil {
+			allErrs = append(allErrs, field.Invalid(urlPath, i.URL, "url must not contain a username or password"))
+		}
+		if len(u.RawQuery) > 0 {
+			allErrs = append(allErrs, field.Invalid(urlPath, i.URL, "url must not contain a query"))
+		}
+		if len(u.Fragment) > 0 {
+			allErrs = append(allErrs, field.Invalid(urlPath, i.URL, "url must not contain a fragment"))
+		}
+	}
+
+	audPath := fldPath.Child("audiences")
+	if len(i.Audiences) == 0 {
+		allErrs = append(allErrs, field.Required(audPath, "at least one audience is required"))
+	}
+	seen := sets.NewString()
+	for j, aud := range i.Audiences {
+		switch {
+		case len(aud) == 0:
+			allErrs = append(allErrs, field.Required(audPath.Index(j), "audience must not be empty"))
+		case seen.Has(aud):
+			allErrs = append(allErrs, field.Duplicate(audPath.Index(j), aud))
+		}
+		seen.Insert(aud)
+	}
+
+	if len(i.CertificateAuthority) > 0 {
+		if _, err := certutil.ParseCertsPEM([]byte(i.CertificateAuthority)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("certificateAuthority"), "<omitted>", err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateClaimValidationRules(rules []api.ClaimValidationRule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.NewString()
+	for i, rule := range rules {
+		claimPath := fldPath.Index(i).Child("claim")
+		switch {
+		case len(rule.Claim) == 0:
+			allErrs = append(allErrs, field.Required(claimPath, "claim name is required"))
+		case seen.Has(rule.Claim):
+			allErrs = append(allErrs, field.Duplicate(claimPath, rule.Claim))
+		}
+		seen.Insert(rule.Claim)
+	}
+	return allErrs
+}
+
+func validateClaimMappings(m api.ClaimMappings, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(m.Username.Claim) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("username", "claim"), "username claim is required"))
+	}
+	if len(m.Groups.Claim) == 0 && m.Groups.Prefix != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("groups", "prefix"), *m.Groups.Prefix, "prefix requires a groups claim"))
+	}
+	return allErrs
+}
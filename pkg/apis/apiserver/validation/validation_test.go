@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	api "k8s.io/apiserver/pkg/apis/apiserver"
+)
+
+func TestValidateAuthenticationConfiguration(t *testing.T) {
+	prefix := "oidc:"
+	valid := func() api.JWTAuthenticator {
+		return api.JWTAuthenticator{
+			Issuer: api.Issuer{
+				URL:       "https://issuer.example.com",
+				Audiences: []string{"my-apiserver"},
+			},
+			ClaimMappings: api.ClaimMappings{
+				Username: api.PrefixedClaimOrExpression{Claim: "sub"},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		jwt       func() []api.JWTAuthenticator
+		expectErr string
+	}{
+		{
+			name: "empty",
+			jwt:  func() []api.JWTAuthenticator { return nil },
+		},
+		{
+			name: "valid",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.ClaimValidationRules = []api.ClaimValidationRule{{Claim: "hd", RequiredValue: "example.com"}}
+				a.ClaimMappings.Groups = api.PrefixedClaimOrExpression{Claim: "groups", Prefix: &prefix}
+				b := valid()
+				b.Issuer.URL = "https://other.example.com/path"
+				b.Issuer.Audiences = []string{"a", "b"}
+				return []api.JWTAuthenticator{a, b}
+			},
+		},
+		{
+			name: "duplicate issuer",
+			jwt: func() []api.JWTAuthenticator {
+				return []api.JWTAuthenticator{valid(), valid()}
+			},
+			expectErr: "jwt[1].issuer.url: Duplicate value",
+		},
+		{
+			name: "missing issuer url",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.Issuer.URL = ""
+				return []api.JWTAuthenticator{a}
+			},
+			expectErr: "jwt[0].issuer.url: Required value",
+		},
+		{
+			name: "http issuer url",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.Issuer.URL = "http://issuer.example.com"
+				return []api.JWTAuthenticator{a}
+			},
+			expectErr: "url scheme must be https",
+		},
+		{
+			name: "issuer url with query",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.Issuer.URL = "https://issuer.example.com?foo=bar"
+				return []api.JWTAuthenticator{a}
+			},
+			expectErr: "url must not contain a query",
+		},
+		{
+			name: "missing audiences",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.Issuer.Audiences = nil
+				return []api.JWTAuthenticator{a}
+			},
+			expectErr: "jwt[0].issuer.audiences: Required value",
+		},
+		{
+			name: "duplicate audience",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.Issuer.Audiences = []string{"a", "a"}
+				return []api.JWTAuthenticator{a}
+			},
+			expectErr: "jwt[0].issuer.audiences[1]: Duplicate value",
+		},
+		{
+			name: "invalid certificate authority",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.Issuer.CertificateAuthority = "not a certificate"
+				return []api.JWTAuthenticator{a}
+			},
+			expectErr: "jwt[0].issuer.certificateAuthority: Invalid value",
+		},
+		{
+			name: "missing claim validation rule claim",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.ClaimValidationRules = []api.ClaimValidationRule{{RequiredValue: "foo"}}
+				return []api.JWTAuthenticator{a}
+			},
+			expectErr: "jwt[0].claimValidationRules[0].claim: Required value",
+		},
+		{
+			name: "missing username claim",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.ClaimMappings.Username.Claim = ""
+				return []api.JWTAuthenticator{a}
+			},
+			expectErr: "jwt[0].claimMappings.username.claim: Required value",
+		},
+		{
+			name: "groups prefix without claim",
+			jwt: func() []api.JWTAuthenticator {
+				a := valid()
+				a.ClaimMappings.Groups.Prefix = &prefix
+				return []api.JWTAuthenticator{a}
+			},
+			expectErr: "prefix requires a groups claim",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateAuthenticationConfiguration(&api.AuthenticationConfiguration{JWT: tc.jwt()})
+			if len(tc.expectErr) == 0 {
+				if len(errs) != 0 {
+					t.Errorf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 {
+				t.Fatalf("expected error %q, got none", tc.expectErr)
+			}
+			if err := errs.ToAggregate().Error(); !strings.Contains(err, tc.expectErr) {
+				t.Errorf("expected error %q, got %q", tc.expectErr, err)
+			}
+		})
+	}
+}
@@ -51,6 +51,16 @@ func Funcs(codecs runtimeserializer.CodecFactory) []interface{} {
 					ContentType: runtime.ContentTypeJSON,
 				}
 			}
+			switch c.RandBool() {
+			case true:
+				e.ObjectDiff = nil
+			case false:
+				e.ObjectDiff = &runtime.Unknown{
+					TypeMeta:    runtime.TypeMeta{APIVersion: "", Kind: ""},
+					Raw:         []byte(`{"metadata":{"labels":{"someKey":"someValue"}}}`),
+					ContentType: runtime.ContentTypeJSON,
+				}
+			}
 		},
 		func(o *audit.ObjectReference, c fuzz.Continue) {
 			c.FuzzNoCustom(o)
@@ -29,6 +29,11 @@ func ordLevel(l Level) int {
 	}
 }
 
+// IsKnown returns whether the level is one of the levels defined by the audit API.
+func (a Level) IsKnown() bool {
+	return a == LevelNone || ordLevel(a) > 0
+}
+
 func (a Level) Less(b Level) bool {
 	return ordLevel(a) < ordLevel(b)
 }
@@ -36,3 +41,33 @@ func (a Level) Less(b Level) bool {
 func (a Level) GreaterOrEqual(b Level) bool {
 	return ordLevel(a) >= ordLevel(b)
 }
+
+func ordStage(s Stage) int {
+	switch s {
+	case StageRequestReceived:
+		return 0
+	case StageResponseStarted:
+		return 1
+	case StageResponseComplete:
+		return 2
+	case StagePanic:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// IsKnown returns whether the stage is one of the stages defined by the audit API.
+func (a Stage) IsKnown() bool {
+	return ordStage(a) >= 0
+}
+
+// Less returns whether stage a comes before stage b in request handling.
+// Unknown stages come after all known stages.
+func (a Stage) Less(b Stage) bool {
+	oa, ob := ordStage(a), ordStage(b)
+	if oa < 0 || ob < 0 {
+		return ob < 0 && (oa >= 0 || a < b)
+	}
+	return oa < ob
+}
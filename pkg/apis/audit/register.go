@@ -48,6 +48,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&EventList{},
 		&Policy{},
 		&PolicyList{},
+		&AuditSink{},
+		&AuditSinkList{},
 	)
 	return nil
 }
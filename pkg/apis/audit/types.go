@@ -36,6 +36,10 @@ const (
 	// to kubelet node, users will only get http headers sent from kubelet node, so no Audit-ID is
 	// sent when users run command like "kubectl exec" or "kubectl attach".
 	HeaderAuditID = "Audit-ID"
+	// Header to hold the audit ID of the request on whose behalf a server sends a request to
+	// another server, e.g. an aggregated apiserver calling the kube-apiserver while serving a
+	// request. The receiving server records it as the parent audit ID of its events.
+	HeaderParentAuditID = "Audit-Parent-ID"
 )
 
 // Level defines the amount of information logged during auditing
@@ -73,6 +77,31 @@ const (
 	StagePanic Stage = "Panic"
 )
 
+// SamplingMode defines how requests are sampled by a PolicyRule with a SamplingRate.
+type SamplingMode string
+
+// Valid sampling modes.
+const (
+	// SamplingModeRandom audits each matching request with a probability of 1/SamplingRate.
+	SamplingModeRandom SamplingMode = "Random"
+	// SamplingModeEveryNth audits every SamplingRate-th matching request.
+	SamplingModeEveryNth SamplingMode = "EveryNth"
+)
+
+// Scope is the scope of the resources a PolicyRule applies to.
+type Scope string
+
+// Valid scopes.
+const (
+	// ScopeCluster matches requests without a namespace, i.e. requests for cluster-scoped
+	// resources and for namespaced resources across all namespaces.
+	ScopeCluster Scope = "Cluster"
+	// ScopeNamespaced matches requests for namespaced resources within a namespace.
+	ScopeNamespaced Scope = "Namespaced"
+	// ScopeAll matches requests of any scope.
+	ScopeAll Scope = "*"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // Event captures all the information that can be included in an API audit log.
@@ -84,6 +113,10 @@ type Event struct {
 
 	// Unique audit ID, generated for each request.
 	AuditID types.UID
+	// ParentAuditID is the audit ID of the request on whose behalf this request was sent,
+	// when the request was sent by a server while serving another request.
+	// +optional
+	ParentAuditID types.UID
 	// Stage of the request handling when this event instance was generated.
 	Stage Stage
 
@@ -131,12 +164,30 @@ type Event struct {
 	// at Response Level.
 	// +optional
 	ResponseObject *runtime.Unknown
+	// ObjectDiff is a JSON merge patch from the object before an update or patch request to
+	// the object after it, recorded instead of the request and response objects if the
+	// policy asks for it. Only logged at RequestResponse Level.
+	// +optional
+	ObjectDiff *runtime.Unknown
 
 	// Time the request reached the apiserver.
 	RequestReceivedTimestamp metav1.MicroTime
 	// Time the request reached current audit stage.
 	StageTimestamp metav1.MicroTime
 
+	// Latency is the time the apiserver took to handle the request. Only set at the
+	// ResponseComplete stage.
+	// +optional
+	Latency *metav1.Duration
+	// ResponseSize is the number of bytes of the response body written. Only set at the
+	// ResponseComplete stage.
+	// +optional
+	ResponseSize *int64
+	// FlowControl is the API Priority and Fairness classification of the request.
+	// Not set for requests exempt from or not subject to API Priority and Fairness.
+	// +optional
+	FlowControl *FlowControl
+
 	// Annotations is an unstructured key value map stored with an audit event that may be set by
 	// plugins invoked in the request serving chain, including authentication, authorization and
 	// admission plugins. Note that these annotations are for the audit event, and do not correspond
@@ -187,6 +238,24 @@ type Policy struct {
 	// in a rule will override the global default.
 	// +optional
 	OmitManagedFields bool
+
+	// MinimumLevels are the lowest levels requests are audited at, whatever level
+	// the rules yield. The minimum levels also apply to requests that are not
+	// selected by a rule's sampling.
+	// +optional
+	MinimumLevels *MinimumLevels
+}
+
+// MinimumLevels are the lowest levels requests are audited at, by verb class.
+type MinimumLevels struct {
+	// Read is the minimum level of read requests, i.e. requests with the verbs
+	// "get", "list", "watch" and "head".
+	// +optional
+	Read Level
+
+	// Write is the minimum level of all other requests.
+	// +optional
+	Write Level
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -208,19 +277,40 @@ type PolicyRule struct {
 
 	// The users (by authenticated user name) this rule applies to.
 	// An empty list implies every user.
+	// *s are allowed, but only as the full, final step in the name, in which case
+	// every user name with the preceding prefix matches.
+	// Examples:
+	//  "system:serviceaccount:monitoring:*" - all service accounts of the monitoring namespace
+	//  "*" - every authenticated user
 	// +optional
 	Users []string
 	// The user groups this rule applies to. A user is considered matching
 	// if it is a member of any of the UserGroups.
 	// An empty list implies every user group.
+	// *s are allowed, but only as the full, final step in the group name.
+	// Example: "system:serviceaccounts:*" - the groups of all namespaced service accounts
 	// +optional
 	UserGroups []string
 
+	// ExceptUsers is a list of users (by authenticated user name) this rule does not apply to,
+	// even if they are matched by Users. Supports the same wildcards as Users.
+	// +optional
+	ExceptUsers []string
+	// ExceptUserGroups is a list of user groups this rule does not apply to. A user is
+	// excluded if it is a member of any of the ExceptUserGroups.
+	// Supports the same wildcards as UserGroups.
+	// +optional
+	ExceptUserGroups []string
+
 	// The verbs that match this rule.
 	// An empty list implies every verb.
 	// +optional
 	Verbs []string
 
+	// ExceptVerbs is a list of verbs this rule does not apply to.
+	// +optional
+	ExceptVerbs []string
+
 	// Rules can apply to API resources (such as "pods" or "secrets"),
 	// non-resource URL paths (such as "/api"), or neither, but not both.
 	// If neither is specified, the rule is treated as a default for all URLs.
@@ -233,12 +323,19 @@ type PolicyRule struct {
 	// An empty list implies every namespace.
 	// +optional
 	Namespaces []string
+	// ExceptNamespaces is a list of namespaces this rule does not apply to.
+	// The empty string "" excludes non-namespaced resources.
+	// Non-resource requests are never excluded by ExceptNamespaces.
+	// +optional
+	ExceptNamespaces []string
 
 	// NonResourceURLs is a set of URL paths that should be audited.
 	// *s are allowed, but only as the full, final step in the path.
+	// Paths prefixed with ~ are regular expressions that must match the whole path.
 	// Examples:
 	//  "/metrics" - Log requests for apiserver metrics
 	//  "/healthz*" - Log all health checks
+	//  "~/apis/[^/]+/v1beta1" - Log discovery of all v1beta1 API groups
 	// +optional
 	NonResourceURLs []string
 
@@ -258,12 +355,147 @@ type PolicyRule struct {
 	// Policy.OmitManagedFields will stand.
 	// +optional
 	OmitManagedFields *bool
+
+	// MatchConditions is a list of CEL expressions that must all evaluate to true
+	// for a request to match this rule. The expressions are evaluated against the
+	// request attributes after all other fields of the rule have matched.
+	// An empty list implies no additional conditions.
+	// +optional
+	MatchConditions []MatchCondition
+
+	// Backends is a list of audit backends (by name, e.g. "log" or "webhook") the events
+	// of requests matching this rule are sent to.
+	// An empty list implies every configured backend.
+	// +optional
+	Backends []string
+
+	// SamplingRate limits auditing of the requests matching this rule to one in every
+	// SamplingRate requests. Requests that are not sampled are not audited.
+	// If unset or 1, every matching request is audited.
+	// +optional
+	SamplingRate *int32
+
+	// SamplingMode determines how requests are sampled if SamplingRate is set.
+	// Valid values are "Random" (the default) and "EveryNth".
+	// +optional
+	SamplingMode SamplingMode
+
+	// ObjectSelector restricts the rule to create and update requests whose request
+	// object has labels matching the selector. The selector is evaluated once the
+	// request object has been decoded; until then the request is audited at the highest
+	// level it may end up with. Requests of other verbs never match a rule with an
+	// object selector. Implies the rule only applies to resource requests.
+	// +optional
+	ObjectSelector *metav1.LabelSelector
+
+	// NamespaceSelector restricts the rule to requests for namespaced resources in
+	// namespaces with labels matching the selector. It is evaluated in addition to
+	// Namespaces. Implies the rule only applies to resource requests.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector
+
+	// ResponseCodes restricts the rule to requests answered with one of the given
+	// HTTP status codes, e.g. "403", or status code classes, e.g. "5xx".
+	// The rule is evaluated once the response code is known. Until then, the request
+	// is audited at the highest level it may end up with.
+	// +optional
+	ResponseCodes []string
+
+	// RedactFields is a list of JSONPath-like field paths, e.g. ".data" or ".data.*",
+	// whose values are replaced with "[REDACTED]" in the request and response objects
+	// of the audit events. A path is a sequence of ".<field>" and "[*]" segments,
+	// where a field of "*" matches every field of an object and "[*]" matches every
+	// element of a list. For lists, the paths are applied to each item of the list.
+	// +optional
+	RedactFields []string
+
+	// ObjectDiff, for update and patch requests audited at the RequestResponse level,
+	// replaces the request and response objects of the audit events with a JSON merge
+	// patch from the object before the request to the object after it.
+	// +optional
+	ObjectDiff bool
+
+	// SourceIPs restricts the rule to requests from clients with an IP address in
+	// one of the given CIDRs. The address of the client connection is used, addresses
+	// claimed by the X-Forwarded-For and X-Real-Ip headers are not taken into account.
+	// +optional
+	SourceIPs []string
+
+	// UserAgents restricts the rule to requests with a User-Agent header matching one
+	// of the given patterns, e.g. "kube-scheduler/*". A "*" in a pattern matches any
+	// sequence of characters.
+	// +optional
+	UserAgents []string
+
+	// ActiveWindow restricts the rule to requests received within the given time window.
+	// +optional
+	ActiveWindow *ActiveWindow
+
+	// Scope restricts the rule to requests of the given scope.
+	// Valid values are "Cluster", "Namespaced" and "*" (the default, any scope).
+	// "Cluster" matches requests without a namespace, i.e. requests for cluster-scoped
+	// resources and for namespaced resources across all namespaces. "Cluster" and
+	// "Namespaced" imply the rule only applies to resource requests.
+	// +optional
+	Scope Scope
+
+	// DryRun, if set, restricts the rule to dry-run requests (true) or to requests
+	// that are not dry-run (false).
+	// +optional
+	DryRun *bool
+
+	// ServerSideApply, if set, restricts the rule to server-side apply requests (true)
+	// or to requests that are not server-side apply requests (false).
+	// +optional
+	ServerSideApply *bool
+
+	// Name identifies the rule. It must be unique within the policy. The name of the
+	// rule a request matched is recorded in the "audit.k8s.io/policy-rule" annotation
+	// of the audit events of the request.
+	// +optional
+	Name string
+
+	// AuditAnnotations restricts the rule to requests with the given audit annotations,
+	// which are set during the request lifecycle, e.g. by authorization or admission.
+	// Every given annotation must be set to the given value.
+	// The rule is evaluated once the response code is known. Until then, the request
+	// is audited at the highest level it may end up with.
+	// +optional
+	AuditAnnotations map[string]string
+}
+
+// ActiveWindow is a time window in which a PolicyRule applies.
+// At least one of Start and End must be set.
+type ActiveWindow struct {
+	// Start is the time from which on the rule applies.
+	// If unset, the rule applies until End.
+	// +optional
+	Start *metav1.Time
+
+	// End is the time from which on the rule does not apply anymore.
+	// If unset, the rule applies from Start on.
+	// +optional
+	End *metav1.Time
+}
+
+// MatchCondition represents a condition which must be fulfilled for a request to match a PolicyRule.
+type MatchCondition struct {
+	// Name is an identifier for this match condition, used to report evaluation errors.
+	Name string
+
+	// Expression represents the expression which will be evaluated by CEL. Must evaluate to bool.
+	// The expression has access to a `request` variable with the following fields:
+	//  'verb', 'apiGroup', 'apiVersion', 'resource', 'subresource', 'namespace', 'name',
+	//  'path' (string), 'resourceRequest' (bool) and
+	//  'user' (map with 'username', 'uid' (string), 'groups' (list) and 'extra' (map)).
+	Expression string
 }
 
 // GroupResources represents resource kinds in an API group.
 type GroupResources struct {
 	// Group is the name of the API group that contains the resources.
 	// The empty string represents the core API group.
+	// '*' matches all API groups.
 	// +optional
 	Group string
 	// Resources is a list of resources this rule applies to.
@@ -288,6 +520,14 @@ type GroupResources struct {
 	ResourceNames []string
 }
 
+// FlowControl is the API Priority and Fairness classification of a request.
+type FlowControl struct {
+	// FlowSchema is the name of the flow schema the request matched.
+	FlowSchema string
+	// PriorityLevel is the name of the priority level the request was assigned to.
+	PriorityLevel string
+}
+
 // ObjectReference contains enough information to let you inspect or modify the referred object.
 type ObjectReference struct {
 	// +optional
@@ -310,3 +550,98 @@ type ObjectReference struct {
 	// +optional
 	Subresource string
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuditSink configures a dynamic destination of audit events: a webhook the
+// events selected by the sink's policy are sent to. Sinks are added, changed
+// and removed while the apiserver is running.
+type AuditSink struct {
+	metav1.TypeMeta
+	// +optional
+	metav1.ObjectMeta
+
+	// Spec defines the audit configuration of the sink.
+	Spec AuditSinkSpec
+}
+
+// AuditSinkSpec holds the spec for the audit sink.
+type AuditSinkSpec struct {
+	// Policy selects the events sent to the sink among the events of the
+	// audit policy of the apiserver.
+	Policy SinkPolicy
+
+	// Webhook to send events to.
+	Webhook SinkWebhook
+}
+
+// SinkPolicy selects the audit events sent to a sink. Events of the audit
+// policy of the apiserver routed to other backends are never sent to sinks.
+type SinkPolicy struct {
+	// Level is the highest level of the events sent to the sink. Events of
+	// higher levels are sent without the request and response objects not
+	// included at Level.
+	Level Level
+
+	// OmitStages is a list of stages of the events that are not sent to the sink.
+	// +optional
+	OmitStages []Stage
+
+	// Verbs, if set, is the verbs of the events sent to the sink.
+	// +optional
+	Verbs []string
+
+	// Namespaces, if set, is the namespaces of the events sent to the sink.
+	// The empty namespace matches the events of cluster-scoped resources and
+	// of non-resource requests.
+	// +optional
+	Namespaces []string
+}
+
+// SinkWebhook holds the configuration of the webhook of a sink.
+type SinkWebhook struct {
+	// Throttle holds the options for throttling the webhook.
+	// +optional
+	Throttle *WebhookThrottleConfig
+
+	// ClientConfig holds the connection parameters for the webhook.
+	ClientConfig WebhookClientConfig
+}
+
+// WebhookThrottleConfig holds the configuration for throttling events.
+type WebhookThrottleConfig struct {
+	// QPS is the maximum number of batches sent per second.
+	// Defaults to 10 QPS.
+	// +optional
+	QPS *int64
+
+	// Burst is the maximum number of batches sent at once.
+	// Defaults to 15.
+	// +optional
+	Burst *int64
+}
+
+// WebhookClientConfig contains the information to make a TLS connection with
+// the webhook.
+type WebhookClientConfig struct {
+	// URL gives the location of the webhook, in standard URL form
+	// ("scheme://host:port/path"). The scheme must be "https", the URL must
+	// not contain user information, a query or a fragment.
+	URL string
+
+	// CABundle is a PEM encoded CA bundle used to validate the webhook's
+	// server certificate. If unspecified, the system trust roots are used.
+	// +optional
+	CABundle []byte
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuditSinkList is a list of AuditSinks.
+type AuditSinkList struct {
+	metav1.TypeMeta
+	// +optional
+	metav1.ListMeta
+
+	Items []AuditSink
+}
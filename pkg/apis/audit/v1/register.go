@@ -52,6 +52,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&EventList{},
 		&Policy{},
 		&PolicyList{},
+		&AuditSink{},
+		&AuditSinkList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
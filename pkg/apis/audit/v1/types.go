@@ -29,6 +29,10 @@ const (
 	// Audit-ID header should be set by the first server to receive the request (e.g. the federation
 	// server or kube-aggregator).
 	HeaderAuditID = "Audit-ID"
+	// Header to hold the audit ID of the request on whose behalf a server sends a request to
+	// another server, e.g. an aggregated apiserver calling the kube-apiserver while serving a
+	// request. The receiving server records it as the parent audit ID of its events.
+	HeaderParentAuditID = "Audit-Parent-ID"
 )
 
 // Level defines the amount of information logged during auditing
@@ -66,6 +70,31 @@ const (
 	StagePanic Stage = "Panic"
 )
 
+// SamplingMode defines how requests are sampled by a PolicyRule with a SamplingRate.
+type SamplingMode string
+
+// Valid sampling modes.
+const (
+	// SamplingModeRandom audits each matching request with a probability of 1/SamplingRate.
+	SamplingModeRandom SamplingMode = "Random"
+	// SamplingModeEveryNth audits every SamplingRate-th matching request.
+	SamplingModeEveryNth SamplingMode = "EveryNth"
+)
+
+// Scope is the scope of the resources a PolicyRule applies to.
+type Scope string
+
+// Valid scopes.
+const (
+	// ScopeCluster matches requests without a namespace, i.e. requests for cluster-scoped
+	// resources and for namespaced resources across all namespaces.
+	ScopeCluster Scope = "Cluster"
+	// ScopeNamespaced matches requests for namespaced resources within a namespace.
+	ScopeNamespaced Scope = "Namespaced"
+	// ScopeAll matches requests of any scope.
+	ScopeAll Scope = "*"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // Event captures all the information that can be included in an API audit log.
@@ -77,6 +106,10 @@ type Event struct {
 
 	// Unique audit ID, generated for each request.
 	AuditID types.UID `json:"auditID" protobuf:"bytes,2,opt,name=auditID,casttype=k8s.io/apimachinery/pkg/types.UID"`
+	// ParentAuditID is the audit ID of the request on whose behalf this request was sent,
+	// when the request was sent by a server while serving another request.
+	// +optional
+	ParentAuditID types.UID `json:"parentAuditID,omitempty" protobuf:"bytes,21,opt,name=parentAuditID,casttype=k8s.io/apimachinery/pkg/types.UID"`
 	// Stage of the request handling when this event instance was generated.
 	Stage Stage `json:"stage" protobuf:"bytes,3,opt,name=stage,casttype=Stage"`
 
@@ -124,6 +157,11 @@ type Event struct {
 	// at Response Level.
 	// +optional
 	ResponseObject *runtime.Unknown `json:"responseObject,omitempty" protobuf:"bytes,12,opt,name=responseObject"`
+	// ObjectDiff is a JSON merge patch (RFC 7386) from the object before an update or patch
+	// request to the object after it, recorded instead of the request and response objects
+	// if the policy asks for it. Only logged at RequestResponse Level.
+	// +optional
+	ObjectDiff *runtime.Unknown `json:"objectDiff,omitempty" protobuf:"bytes,20,opt,name=objectDiff"`
 	// Time the request reached the apiserver.
 	// +optional
 	RequestReceivedTimestamp metav1.MicroTime `json:"requestReceivedTimestamp" protobuf:"bytes,13,opt,name=requestReceivedTimestamp"`
@@ -131,6 +169,19 @@ type Event struct {
 	// +optional
 	StageTimestamp metav1.MicroTime `json:"stageTimestamp" protobuf:"bytes,14,opt,name=stageTimestamp"`
 
+	// Latency is the time the apiserver took to handle the request. Only set at the
+	// ResponseComplete stage.
+	// +optional
+	Latency *metav1.Duration `json:"latency,omitempty" protobuf:"bytes,17,opt,name=latency"`
+	// ResponseSize is the number of bytes of the response body written. Only set at the
+	// ResponseComplete stage.
+	// +optional
+	ResponseSize *int64 `json:"responseSize,omitempty" protobuf:"varint,18,opt,name=responseSize"`
+	// FlowControl is the API Priority and Fairness classification of the request.
+	// Not set for requests exempt from or not subject to API Priority and Fairness.
+	// +optional
+	FlowControl *FlowControl `json:"flowControl,omitempty" protobuf:"bytes,19,opt,name=flowControl"`
+
 	// Annotations is an unstructured key value map stored with an audit event that may be set by
 	// plugins invoked in the request serving chain, including authentication, authorization and
 	// admission plugins. Note that these annotations are for the audit event, and do not correspond
@@ -181,6 +232,25 @@ type Policy struct {
 	// in a rule will override the global default.
 	// +optional
 	OmitManagedFields bool `json:"omitManagedFields,omitempty" protobuf:"varint,4,opt,name=omitManagedFields"`
+
+	// MinimumLevels are the lowest levels requests are audited at, whatever level
+	// the rules yield, so that a misordered catch-all rule can never silently drop
+	// writes from the audit log. The minimum levels also apply to requests that are
+	// not selected by a rule's sampling.
+	// +optional
+	MinimumLevels *MinimumLevels `json:"minimumLevels,omitempty" protobuf:"bytes,5,opt,name=minimumLevels"`
+}
+
+// MinimumLevels are the lowest levels requests are audited at, by verb class.
+type MinimumLevels struct {
+	// Read is the minimum level of read requests, i.e. requests with the verbs
+	// "get", "list", "watch" and "head".
+	// +optional
+	Read Level `json:"read,omitempty" protobuf:"bytes,1,opt,name=read,casttype=Level"`
+
+	// Write is the minimum level of all other requests.
+	// +optional
+	Write Level `json:"write,omitempty" protobuf:"bytes,2,opt,name=write,casttype=Level"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -202,19 +272,40 @@ type PolicyRule struct {
 
 	// The users (by authenticated user name) this rule applies to.
 	// An empty list implies every user.
+	// *s are allowed, but only as the full, final step in the name, in which case
+	// every user name with the preceding prefix matches.
+	// Examples:
+	//  "system:serviceaccount:monitoring:*" - all service accounts of the monitoring namespace
+	//  "*" - every authenticated user
 	// +optional
 	Users []string `json:"users,omitempty" protobuf:"bytes,2,rep,name=users"`
 	// The user groups this rule applies to. A user is considered matching
 	// if it is a member of any of the UserGroups.
 	// An empty list implies every user group.
+	// *s are allowed, but only as the full, final step in the group name.
+	// Example: "system:serviceaccounts:*" - the groups of all namespaced service accounts
 	// +optional
 	UserGroups []string `json:"userGroups,omitempty" protobuf:"bytes,3,rep,name=userGroups"`
 
+	// ExceptUsers is a list of users (by authenticated user name) this rule does not apply to,
+	// even if they are matched by Users. Supports the same wildcards as Users.
+	// +optional
+	ExceptUsers []string `json:"exceptUsers,omitempty" protobuf:"bytes,11,rep,name=exceptUsers"`
+	// ExceptUserGroups is a list of user groups this rule does not apply to. A user is
+	// excluded if it is a member of any of the ExceptUserGroups.
+	// Supports the same wildcards as UserGroups.
+	// +optional
+	ExceptUserGroups []string `json:"exceptUserGroups,omitempty" protobuf:"bytes,12,rep,name=exceptUserGroups"`
+
 	// The verbs that match this rule.
 	// An empty list implies every verb.
 	// +optional
 	Verbs []string `json:"verbs,omitempty" protobuf:"bytes,4,rep,name=verbs"`
 
+	// ExceptVerbs is a list of verbs this rule does not apply to.
+	// +optional
+	ExceptVerbs []string `json:"exceptVerbs,omitempty" protobuf:"bytes,13,rep,name=exceptVerbs"`
+
 	// Rules can apply to API resources (such as "pods" or "secrets"),
 	// non-resource URL paths (such as "/api"), or neither, but not both.
 	// If neither is specified, the rule is treated as a default for all URLs.
@@ -227,12 +318,19 @@ type PolicyRule struct {
 	// An empty list implies every namespace.
 	// +optional
 	Namespaces []string `json:"namespaces,omitempty" protobuf:"bytes,6,rep,name=namespaces"`
+	// ExceptNamespaces is a list of namespaces this rule does not apply to.
+	// The empty string "" excludes non-namespaced resources.
+	// Non-resource requests are never excluded by ExceptNamespaces.
+	// +optional
+	ExceptNamespaces []string `json:"exceptNamespaces,omitempty" protobuf:"bytes,14,rep,name=exceptNamespaces"`
 
 	// NonResourceURLs is a set of URL paths that should be audited.
 	// *s are allowed, but only as the full, final step in the path.
+	// Paths prefixed with ~ are regular expressions that must match the whole path.
 	// Examples:
 	//  "/metrics" - Log requests for apiserver metrics
 	//  "/healthz*" - Log all health checks
+	//  "~/apis/[^/]+/v1beta1" - Log discovery of all v1beta1 API groups
 	// +optional
 	NonResourceURLs []string `json:"nonResourceURLs,omitempty" protobuf:"bytes,7,rep,name=nonResourceURLs"`
 
@@ -252,12 +350,181 @@ type PolicyRule struct {
 	// Policy.OmitManagedFields will stand.
 	// +optional
 	OmitManagedFields *bool `json:"omitManagedFields,omitempty" protobuf:"varint,9,opt,name=omitManagedFields"`
+
+	// MatchConditions is a list of CEL expressions that must all evaluate to true
+	// for a request to match this rule. The expressions are evaluated against the
+	// request attributes after all other fields of the rule have matched.
+	// An empty list implies no additional conditions.
+	// +optional
+	MatchConditions []MatchCondition `json:"matchConditions,omitempty" protobuf:"bytes,10,rep,name=matchConditions"`
+
+	// Backends is a list of audit backends (by name, e.g. "log" or "webhook") the events
+	// of requests matching this rule are sent to.
+	// An empty list implies every configured backend.
+	// +optional
+	Backends []string `json:"backends,omitempty" protobuf:"bytes,15,rep,name=backends"`
+
+	// SamplingRate limits auditing of the requests matching this rule to one in every
+	// SamplingRate requests. Requests that are not sampled are not audited.
+	// For example, a SamplingRate of 100 audits 1% of the matching requests.
+	// If unset or 1, every matching request is audited.
+	// +optional
+	SamplingRate *int32 `json:"samplingRate,omitempty" protobuf:"varint,16,opt,name=samplingRate"`
+
+	// SamplingMode determines how requests are sampled if SamplingRate is set.
+	// Valid values are "Random" (the default), which audits each matching request with a
+	// probability of 1/SamplingRate, and "EveryNth", which audits every SamplingRate-th
+	// matching request.
+	// +optional
+	SamplingMode SamplingMode `json:"samplingMode,omitempty" protobuf:"bytes,17,opt,name=samplingMode,casttype=SamplingMode"`
+
+	// ObjectSelector restricts the rule to create and update requests whose request
+	// object has labels matching the selector, e.g. "RequestResponse only for Pods
+	// labeled tier=control-plane".
+	// The selector is evaluated once the request object has been decoded. Until then,
+	// the request is audited at the highest level it may end up with, so the
+	// RequestReceived stage may be recorded at a higher level than the later stages.
+	// Requests of other verbs never match a rule with an object selector.
+	// Implies the rule only applies to resource requests.
+	// +optional
+	ObjectSelector *metav1.LabelSelector `json:"objectSelector,omitempty" protobuf:"bytes,18,opt,name=objectSelector"`
+
+	// NamespaceSelector restricts the rule to requests for namespaced resources in
+	// namespaces with labels matching the selector, e.g. "all namespaces labeled env=prod".
+	// Namespace labels are looked up in an informer cache, so the rule follows
+	// namespaces as they are created, relabeled and deleted.
+	// It is evaluated in addition to Namespaces. Requests for cluster-scoped resources
+	// never match a rule with a namespace selector.
+	// Implies the rule only applies to resource requests.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty" protobuf:"bytes,19,opt,name=namespaceSelector"`
+
+	// ResponseCodes restricts the rule to requests answered with one of the given
+	// HTTP status codes, e.g. "403", or status code classes, e.g. "5xx". This allows
+	// rules such as "RequestResponse for requests failing with 401, 403 or 5xx".
+	// The rule is evaluated once the response code is known. Until then, the request is
+	// audited at the highest level it may end up with, so the RequestReceived stage may
+	// be recorded at a higher level than the later stages.
+	// +optional
+	ResponseCodes []string `json:"responseCodes,omitempty" protobuf:"bytes,20,rep,name=responseCodes"`
+
+	// RedactFields is a list of JSONPath-like field paths, e.g. ".data" or ".data.*",
+	// whose values are replaced with "[REDACTED]" in the request and response objects
+	// of the audit events, e.g. to scrub the values of Secrets and ConfigMaps while
+	// still auditing them at the RequestResponse level.
+	// A path is a sequence of ".<field>" and "[*]" segments, where a field of "*"
+	// matches every field of an object and "[*]" matches every element of a list.
+	// For lists, the paths are applied to each item of the list.
+	// Objects that cannot be redacted, e.g. JSON patches, are omitted from the events.
+	// +optional
+	RedactFields []string `json:"redactFields,omitempty" protobuf:"bytes,21,rep,name=redactFields"`
+
+	// ObjectDiff, for update and patch requests audited at the RequestResponse level,
+	// replaces the request and response objects of the audit events with a JSON merge
+	// patch (RFC 7386) from the object before the request to the object after it.
+	// This keeps what changed while cutting the size of the events.
+	// Requests that do not modify an existing object are audited with their full objects.
+	// +optional
+	ObjectDiff bool `json:"objectDiff,omitempty" protobuf:"varint,30,opt,name=objectDiff"`
+
+	// SourceIPs restricts the rule to requests from clients with an IP address in
+	// one of the given CIDRs, e.g. "10.0.0.0/8", so that traffic from trusted
+	// control-plane networks can be audited at a different level than external clients.
+	// The address of the client connection is used. Addresses claimed by the
+	// X-Forwarded-For and X-Real-Ip headers are not taken into account, as clients
+	// can set them freely.
+	// +optional
+	SourceIPs []string `json:"sourceIPs,omitempty" protobuf:"bytes,22,rep,name=sourceIPs"`
+
+	// UserAgents restricts the rule to requests with a User-Agent header matching one
+	// of the given patterns, e.g. "kube-scheduler/*". A "*" in a pattern matches any
+	// sequence of characters. This allows targeting clients like kube-scheduler or
+	// kube-controller-manager even when they connect with shared identities.
+	// As clients can set the User-Agent header freely, it must not be relied upon
+	// to lower the level of requests that need to be audited.
+	// +optional
+	UserAgents []string `json:"userAgents,omitempty" protobuf:"bytes,23,rep,name=userAgents"`
+
+	// ActiveWindow restricts the rule to requests received within the given time window,
+	// so that elevated auditing can apply during change freezes or incident response
+	// windows without redeploying the policy.
+	// +optional
+	ActiveWindow *ActiveWindow `json:"activeWindow,omitempty" protobuf:"bytes,24,opt,name=activeWindow"`
+
+	// Scope restricts the rule to requests of the given scope, like the scope of RBAC
+	// and admission webhook rules, so that access to cluster-scoped resources can be
+	// singled out without enumerating the resources.
+	// Valid values are "Cluster", "Namespaced" and "*" (the default, any scope).
+	// "Cluster" matches requests without a namespace, i.e. requests for cluster-scoped
+	// resources and for namespaced resources across all namespaces. "Cluster" and
+	// "Namespaced" imply the rule only applies to resource requests.
+	// +optional
+	Scope Scope `json:"scope,omitempty" protobuf:"bytes,25,opt,name=scope,casttype=Scope"`
+
+	// DryRun, if set, restricts the rule to dry-run requests (true) or to requests
+	// that are not dry-run (false), e.g. to audit dry-run requests at the Metadata
+	// level only while real mutations are audited at the RequestResponse level.
+	// +optional
+	DryRun *bool `json:"dryRun,omitempty" protobuf:"varint,26,opt,name=dryRun"`
+
+	// ServerSideApply, if set, restricts the rule to server-side apply requests (true)
+	// or to requests that are not server-side apply requests (false).
+	// +optional
+	ServerSideApply *bool `json:"serverSideApply,omitempty" protobuf:"varint,27,opt,name=serverSideApply"`
+
+	// Name identifies the rule. It must be unique within the policy. The name of the
+	// rule a request matched is recorded in the "audit.k8s.io/policy-rule" annotation
+	// of the audit events of the request.
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,28,opt,name=name"`
+
+	// AuditAnnotations restricts the rule to requests with the given audit annotations,
+	// which are set during the request lifecycle, e.g. by authorization or admission.
+	// Every given annotation must be set to the given value, e.g.
+	// "authorization.k8s.io/decision": "forbid" to escalate forbidden requests.
+	// The rule is evaluated once the response code is known. Until then, the request
+	// is audited at the highest level it may end up with.
+	// +optional
+	AuditAnnotations map[string]string `json:"auditAnnotations,omitempty" protobuf:"bytes,29,rep,name=auditAnnotations"`
+}
+
+// ActiveWindow is a time window in which a PolicyRule applies.
+// At least one of Start and End must be set.
+type ActiveWindow struct {
+	// Start is the time from which on the rule applies.
+	// If unset, the rule applies until End.
+	// +optional
+	Start *metav1.Time `json:"start,omitempty" protobuf:"bytes,1,opt,name=start"`
+
+	// End is the time from which on the rule does not apply anymore.
+	// If unset, the rule applies from Start on.
+	// +optional
+	End *metav1.Time `json:"end,omitempty" protobuf:"bytes,2,opt,name=end"`
+}
+
+// MatchCondition represents a condition which must be fulfilled for a request to match a PolicyRule.
+type MatchCondition struct {
+	// Name is an identifier for this match condition, used to report evaluation errors.
+	// Required.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+
+	// Expression represents the expression which will be evaluated by CEL. Must evaluate to bool.
+	// The expression has access to a `request` variable with the following fields:
+	//  'verb', 'apiGroup', 'apiVersion', 'resource', 'subresource', 'namespace', 'name',
+	//  'path' (string), 'resourceRequest' (bool) and
+	//  'user' (map with 'username', 'uid' (string), 'groups' (list) and 'extra' (map)).
+	//
+	// Example: request.verb == 'update' && request.name.startsWith('kube-')
+	//
+	// Required.
+	Expression string `json:"expression" protobuf:"bytes,2,opt,name=expression"`
 }
 
 // GroupResources represents resource kinds in an API group.
 type GroupResources struct {
 	// Group is the name of the API group that contains the resources.
 	// The empty string represents the core API group.
+	// '*' matches all API groups.
 	// +optional
 	Group string `json:"group,omitempty" protobuf:"bytes,1,opt,name=group"`
 	// Resources is a list of resources this rule applies to.
@@ -282,6 +549,14 @@ type GroupResources struct {
 	ResourceNames []string `json:"resourceNames,omitempty" protobuf:"bytes,3,rep,name=resourceNames"`
 }
 
+// FlowControl is the API Priority and Fairness classification of a request.
+type FlowControl struct {
+	// FlowSchema is the name of the flow schema the request matched.
+	FlowSchema string `json:"flowSchema" protobuf:"bytes,1,opt,name=flowSchema"`
+	// PriorityLevel is the name of the priority level the request was assigned to.
+	PriorityLevel string `json:"priorityLevel" protobuf:"bytes,2,opt,name=priorityLevel"`
+}
+
 // ObjectReference contains enough information to let you inspect or modify the referred object.
 type ObjectReference struct {
 	// +optional
@@ -304,3 +579,98 @@ type ObjectReference struct {
 	// +optional
 	Subresource string `json:"subresource,omitempty" protobuf:"bytes,8,opt,name=subresource"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuditSink configures a dynamic destination of audit events: a webhook the
+// events selected by the sink's policy are sent to. Sinks are added, changed
+// and removed while the apiserver is running.
+type AuditSink struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec defines the audit configuration of the sink.
+	Spec AuditSinkSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// AuditSinkSpec holds the spec for the audit sink.
+type AuditSinkSpec struct {
+	// Policy selects the events sent to the sink among the events of the
+	// audit policy of the apiserver.
+	Policy SinkPolicy `json:"policy" protobuf:"bytes,1,opt,name=policy"`
+
+	// Webhook to send events to.
+	Webhook SinkWebhook `json:"webhook" protobuf:"bytes,2,opt,name=webhook"`
+}
+
+// SinkPolicy selects the audit events sent to a sink. Events of the audit
+// policy of the apiserver routed to other backends are never sent to sinks.
+type SinkPolicy struct {
+	// Level is the highest level of the events sent to the sink. Events of
+	// higher levels are sent without the request and response objects not
+	// included at Level.
+	Level Level `json:"level" protobuf:"bytes,1,opt,name=level,casttype=Level"`
+
+	// OmitStages is a list of stages of the events that are not sent to the sink.
+	// +optional
+	OmitStages []Stage `json:"omitStages,omitempty" protobuf:"bytes,2,rep,name=omitStages"`
+
+	// Verbs, if set, is the verbs of the events sent to the sink.
+	// +optional
+	Verbs []string `json:"verbs,omitempty" protobuf:"bytes,3,rep,name=verbs"`
+
+	// Namespaces, if set, is the namespaces of the events sent to the sink.
+	// The empty namespace matches the events of cluster-scoped resources and
+	// of non-resource requests.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty" protobuf:"bytes,4,rep,name=namespaces"`
+}
+
+// SinkWebhook holds the configuration of the webhook of a sink.
+type SinkWebhook struct {
+	// Throttle holds the options for throttling the webhook.
+	// +optional
+	Throttle *WebhookThrottleConfig `json:"throttle,omitempty" protobuf:"bytes,1,opt,name=throttle"`
+
+	// ClientConfig holds the connection parameters for the webhook.
+	ClientConfig WebhookClientConfig `json:"clientConfig" protobuf:"bytes,2,opt,name=clientConfig"`
+}
+
+// WebhookThrottleConfig holds the configuration for throttling events.
+type WebhookThrottleConfig struct {
+	// QPS is the maximum number of batches sent per second.
+	// Defaults to 10 QPS.
+	// +optional
+	QPS *int64 `json:"qps,omitempty" protobuf:"varint,1,opt,name=qps"`
+
+	// Burst is the maximum number of batches sent at once.
+	// Defaults to 15.
+	// +optional
+	Burst *int64 `json:"burst,omitempty" protobuf:"varint,2,opt,name=burst"`
+}
+
+// WebhookClientConfig contains the information to make a TLS connection with
+// the webhook.
+type WebhookClientConfig struct {
+	// URL gives the location of the webhook, in standard URL form
+	// ("scheme://host:port/path"). The scheme must be "https", the URL must
+	// not contain user information, a query or a fragment.
+	URL string `json:"url" protobuf:"bytes,1,opt,name=url"`
+
+	// CABundle is a PEM encoded CA bundle used to validate the webhook's
+	// server certificate. If unspecified, the system trust roots are used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty" protobuf:"bytes,2,opt,name=caBundle"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuditSinkList is a list of AuditSinks.
+type AuditSinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []AuditSink `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
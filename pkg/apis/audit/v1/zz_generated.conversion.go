@@ -39,6 +39,46 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*ActiveWindow)(nil), (*audit.ActiveWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ActiveWindow_To_audit_ActiveWindow(a.(*ActiveWindow), b.(*audit.ActiveWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.ActiveWindow)(nil), (*ActiveWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_ActiveWindow_To_v1_ActiveWindow(a.(*audit.ActiveWindow), b.(*ActiveWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuditSink)(nil), (*audit.AuditSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_AuditSink_To_audit_AuditSink(a.(*AuditSink), b.(*audit.AuditSink), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.AuditSink)(nil), (*AuditSink)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_AuditSink_To_v1_AuditSink(a.(*audit.AuditSink), b.(*AuditSink), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuditSinkList)(nil), (*audit.AuditSinkList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_AuditSinkList_To_audit_AuditSinkList(a.(*AuditSinkList), b.(*audit.AuditSinkList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.AuditSinkList)(nil), (*AuditSinkList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_AuditSinkList_To_v1_AuditSinkList(a.(*audit.AuditSinkList), b.(*AuditSinkList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuditSinkSpec)(nil), (*audit.AuditSinkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_AuditSinkSpec_To_audit_AuditSinkSpec(a.(*AuditSinkSpec), b.(*audit.AuditSinkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.AuditSinkSpec)(nil), (*AuditSinkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_AuditSinkSpec_To_v1_AuditSinkSpec(a.(*audit.AuditSinkSpec), b.(*AuditSinkSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Event)(nil), (*audit.Event)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_Event_To_audit_Event(a.(*Event), b.(*audit.Event), scope)
 	}); err != nil {
@@ -59,6 +99,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*FlowControl)(nil), (*audit.FlowControl)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_FlowControl_To_audit_FlowControl(a.(*FlowControl), b.(*audit.FlowControl), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.FlowControl)(nil), (*FlowControl)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_FlowControl_To_v1_FlowControl(a.(*audit.FlowControl), b.(*FlowControl), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*GroupResources)(nil), (*audit.GroupResources)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_GroupResources_To_audit_GroupResources(a.(*GroupResources), b.(*audit.GroupResources), scope)
 	}); err != nil {
@@ -69,6 +119,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MatchCondition)(nil), (*audit.MatchCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_MatchCondition_To_audit_MatchCondition(a.(*MatchCondition), b.(*audit.MatchCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.MatchCondition)(nil), (*MatchCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_MatchCondition_To_v1_MatchCondition(a.(*audit.MatchCondition), b.(*MatchCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MinimumLevels)(nil), (*audit.MinimumLevels)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_MinimumLevels_To_audit_MinimumLevels(a.(*MinimumLevels), b.(*audit.MinimumLevels), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.MinimumLevels)(nil), (*MinimumLevels)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_MinimumLevels_To_v1_MinimumLevels(a.(*audit.MinimumLevels), b.(*MinimumLevels), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ObjectReference)(nil), (*audit.ObjectReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_ObjectReference_To_audit_ObjectReference(a.(*ObjectReference), b.(*audit.ObjectReference), scope)
 	}); err != nil {
@@ -109,12 +179,153 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SinkPolicy)(nil), (*audit.SinkPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_SinkPolicy_To_audit_SinkPolicy(a.(*SinkPolicy), b.(*audit.SinkPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.SinkPolicy)(nil), (*SinkPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_SinkPolicy_To_v1_SinkPolicy(a.(*audit.SinkPolicy), b.(*SinkPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SinkWebhook)(nil), (*audit.SinkWebhook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_SinkWebhook_To_audit_SinkWebhook(a.(*SinkWebhook), b.(*audit.SinkWebhook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.SinkWebhook)(nil), (*SinkWebhook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_SinkWebhook_To_v1_SinkWebhook(a.(*audit.SinkWebhook), b.(*SinkWebhook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*WebhookClientConfig)(nil), (*audit.WebhookClientConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_WebhookClientConfig_To_audit_WebhookClientConfig(a.(*WebhookClientConfig), b.(*audit.WebhookClientConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.WebhookClientConfig)(nil), (*WebhookClientConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_WebhookClientConfig_To_v1_WebhookClientConfig(a.(*audit.WebhookClientConfig), b.(*WebhookClientConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*WebhookThrottleConfig)(nil), (*audit.WebhookThrottleConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_WebhookThrottleConfig_To_audit_WebhookThrottleConfig(a.(*WebhookThrottleConfig), b.(*audit.WebhookThrottleConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*audit.WebhookThrottleConfig)(nil), (*WebhookThrottleConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_audit_WebhookThrottleConfig_To_v1_WebhookThrottleConfig(a.(*audit.WebhookThrottleConfig), b.(*WebhookThrottleConfig), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1_ActiveWindow_To_audit_ActiveWindow(in *ActiveWindow, out *audit.ActiveWindow, s conversion.Scope) error {
+	out.Start = (*metav1.Time)(unsafe.Pointer(in.Start))
+	out.End = (*metav1.Time)(unsafe.Pointer(in.End))
+	return nil
+}
+
+// Convert_v1_ActiveWindow_To_audit_ActiveWindow is an autogenerated conversion function.
+func Convert_v1_ActiveWindow_To_audit_ActiveWindow(in *ActiveWindow, out *audit.ActiveWindow, s conversion.Scope) error {
+	return autoConvert_v1_ActiveWindow_To_audit_ActiveWindow(in, out, s)
+}
+
+func autoConvert_audit_ActiveWindow_To_v1_ActiveWindow(in *audit.ActiveWindow, out *ActiveWindow, s conversion.Scope) error {
+	out.Start = (*metav1.Time)(unsafe.Pointer(in.Start))
+	out.End = (*metav1.Time)(unsafe.Pointer(in.End))
+	return nil
+}
+
+// Convert_audit_ActiveWindow_To_v1_ActiveWindow is an autogenerated conversion function.
+func Convert_audit_ActiveWindow_To_v1_ActiveWindow(in *audit.ActiveWindow, out *ActiveWindow, s conversion.Scope) error {
+	return autoConvert_audit_ActiveWindow_To_v1_ActiveWindow(in, out, s)
+}
+
+func autoConvert_v1_AuditSink_To_audit_AuditSink(in *AuditSink, out *audit.AuditSink, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1_AuditSinkSpec_To_audit_AuditSinkSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_AuditSink_To_audit_AuditSink is an autogenerated conversion function.
+func Convert_v1_AuditSink_To_audit_AuditSink(in *AuditSink, out *audit.AuditSink, s conversion.Scope) error {
+	return autoConvert_v1_AuditSink_To_audit_AuditSink(in, out, s)
+}
+
+func autoConvert_audit_AuditSink_To_v1_AuditSink(in *audit.AuditSink, out *AuditSink, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_audit_AuditSinkSpec_To_v1_AuditSinkSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_audit_AuditSink_To_v1_AuditSink is an autogenerated conversion function.
+func Convert_audit_AuditSink_To_v1_AuditSink(in *audit.AuditSink, out *AuditSink, s conversion.Scope) error {
+	return autoConvert_audit_AuditSink_To_v1_AuditSink(in, out, s)
+}
+
+func autoConvert_v1_AuditSinkList_To_audit_AuditSinkList(in *AuditSinkList, out *audit.AuditSinkList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]audit.AuditSink)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1_AuditSinkList_To_audit_AuditSinkList is an autogenerated conversion function.
+func Convert_v1_AuditSinkList_To_audit_AuditSinkList(in *AuditSinkList, out *audit.AuditSinkList, s conversion.Scope) error {
+	return autoConvert_v1_AuditSinkList_To_audit_AuditSinkList(in, out, s)
+}
+
+func autoConvert_audit_AuditSinkList_To_v1_AuditSinkList(in *audit.AuditSinkList, out *AuditSinkList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]AuditSink)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_audit_AuditSinkList_To_v1_AuditSinkList is an autogenerated conversion function.
+func Convert_audit_AuditSinkList_To_v1_AuditSinkList(in *audit.AuditSinkList, out *AuditSinkList, s conversion.Scope) error {
+	return autoConvert_audit_AuditSinkList_To_v1_AuditSinkList(in, out, s)
+}
+
+func autoConvert_v1_AuditSinkSpec_To_audit_AuditSinkSpec(in *AuditSinkSpec, out *audit.AuditSinkSpec, s conversion.Scope) error {
+	if err := Convert_v1_SinkPolicy_To_audit_SinkPolicy(&in.Policy, &out.Policy, s); err != nil {
+		return err
+	}
+	if err := Convert_v1_SinkWebhook_To_audit_SinkWebhook(&in.Webhook, &out.Webhook, s); err != nil {
+		return err
+	}
 	return nil
 }
 
+// Convert_v1_AuditSinkSpec_To_audit_AuditSinkSpec is an autogenerated conversion function.
+func Convert_v1_AuditSinkSpec_To_audit_AuditSinkSpec(in *AuditSinkSpec, out *audit.AuditSinkSpec, s conversion.Scope) error {
+	return autoConvert_v1_AuditSinkSpec_To_audit_AuditSinkSpec(in, out, s)
+}
+
+func autoConvert_audit_AuditSinkSpec_To_v1_AuditSinkSpec(in *audit.AuditSinkSpec, out *AuditSinkSpec, s conversion.Scope) error {
+	if err := Convert_audit_SinkPolicy_To_v1_SinkPolicy(&in.Policy, &out.Policy, s); err != nil {
+		return err
+	}
+	if err := Convert_audit_SinkWebhook_To_v1_SinkWebhook(&in.Webhook, &out.Webhook, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_audit_AuditSinkSpec_To_v1_AuditSinkSpec is an autogenerated conversion function.
+func Convert_audit_AuditSinkSpec_To_v1_AuditSinkSpec(in *audit.AuditSinkSpec, out *AuditSinkSpec, s conversion.Scope) error {
+	return autoConvert_audit_AuditSinkSpec_To_v1_AuditSinkSpec(in, out, s)
+}
+
 func autoConvert_v1_Event_To_audit_Event(in *Event, out *audit.Event, s conversion.Scope) error {
 	out.Level = audit.Level(in.Level)
 	out.AuditID = types.UID(in.AuditID)
+	out.ParentAuditID = types.UID(in.ParentAuditID)
 	out.Stage = audit.Stage(in.Stage)
 	out.RequestURI = in.RequestURI
 	out.Verb = in.Verb
@@ -126,8 +337,12 @@ func autoConvert_v1_Event_To_audit_Event(in *Event, out *audit.Event, s conversi
 	out.ResponseStatus = (*metav1.Status)(unsafe.Pointer(in.ResponseStatus))
 	out.RequestObject = (*runtime.Unknown)(unsafe.Pointer(in.RequestObject))
 	out.ResponseObject = (*runtime.Unknown)(unsafe.Pointer(in.ResponseObject))
+	out.ObjectDiff = (*runtime.Unknown)(unsafe.Pointer(in.ObjectDiff))
 	out.RequestReceivedTimestamp = in.RequestReceivedTimestamp
 	out.StageTimestamp = in.StageTimestamp
+	out.Latency = (*metav1.Duration)(unsafe.Pointer(in.Latency))
+	out.ResponseSize = (*int64)(unsafe.Pointer(in.ResponseSize))
+	out.FlowControl = (*audit.FlowControl)(unsafe.Pointer(in.FlowControl))
 	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
 	return nil
 }
@@ -140,6 +355,7 @@ func Convert_v1_Event_To_audit_Event(in *Event, out *audit.Event, s conversion.S
 func autoConvert_audit_Event_To_v1_Event(in *audit.Event, out *Event, s conversion.Scope) error {
 	out.Level = Level(in.Level)
 	out.AuditID = types.UID(in.AuditID)
+	out.ParentAuditID = types.UID(in.ParentAuditID)
 	out.Stage = Stage(in.Stage)
 	out.RequestURI = in.RequestURI
 	out.Verb = in.Verb
@@ -151,8 +367,12 @@ func autoConvert_audit_Event_To_v1_Event(in *audit.Event, out *Event, s conversi
 	out.ResponseStatus = (*metav1.Status)(unsafe.Pointer(in.ResponseStatus))
 	out.RequestObject = (*runtime.Unknown)(unsafe.Pointer(in.RequestObject))
 	out.ResponseObject = (*runtime.Unknown)(unsafe.Pointer(in.ResponseObject))
+	out.ObjectDiff = (*runtime.Unknown)(unsafe.Pointer(in.ObjectDiff))
 	out.RequestReceivedTimestamp = in.RequestReceivedTimestamp
 	out.StageTimestamp = in.StageTimestamp
+	out.Latency = (*metav1.Duration)(unsafe.Pointer(in.Latency))
+	out.ResponseSize = (*int64)(unsafe.Pointer(in.ResponseSize))
+	out.FlowControl = (*FlowControl)(unsafe.Pointer(in.FlowControl))
 	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
 	return nil
 }
@@ -184,6 +404,28 @@ func Convert_audit_EventList_To_v1_EventList(in *audit.EventList, out *EventList
 	return autoConvert_audit_EventList_To_v1_EventList(in, out, s)
 }
 
+func autoConvert_v1_FlowControl_To_audit_FlowControl(in *FlowControl, out *audit.FlowControl, s conversion.Scope) error {
+	out.FlowSchema = in.FlowSchema
+	out.PriorityLevel = in.PriorityLevel
+	return nil
+}
+
+// Convert_v1_FlowControl_To_audit_FlowControl is an autogenerated conversion function.
+func Convert_v1_FlowControl_To_audit_FlowControl(in *FlowControl, out *audit.FlowControl, s conversion.Scope) error {
+	return autoConvert_v1_FlowControl_To_audit_FlowControl(in, out, s)
+}
+
+func autoConvert_audit_FlowControl_To_v1_FlowControl(in *audit.FlowControl, out *FlowControl, s conversion.Scope) error {
+	out.FlowSchema = in.FlowSchema
+	out.PriorityLevel = in.PriorityLevel
+	return nil
+}
+
+// Convert_audit_FlowControl_To_v1_FlowControl is an autogenerated conversion function.
+func Convert_audit_FlowControl_To_v1_FlowControl(in *audit.FlowControl, out *FlowControl, s conversion.Scope) error {
+	return autoConvert_audit_FlowControl_To_v1_FlowControl(in, out, s)
+}
+
 func autoConvert_v1_GroupResources_To_audit_GroupResources(in *GroupResources, out *audit.GroupResources, s conversion.Scope) error {
 	out.Group = in.Group
 	out.Resources = *(*[]string)(unsafe.Pointer(&in.Resources))
@@ -208,6 +450,50 @@ func Convert_audit_GroupResources_To_v1_GroupResources(in *audit.GroupResources,
 	return autoConvert_audit_GroupResources_To_v1_GroupResources(in, out, s)
 }
 
+func autoConvert_v1_MatchCondition_To_audit_MatchCondition(in *MatchCondition, out *audit.MatchCondition, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Expression = in.Expression
+	return nil
+}
+
+// Convert_v1_MatchCondition_To_audit_MatchCondition is an autogenerated conversion function.
+func Convert_v1_MatchCondition_To_audit_MatchCondition(in *MatchCondition, out *audit.MatchCondition, s conversion.Scope) error {
+	return autoConvert_v1_MatchCondition_To_audit_MatchCondition(in, out, s)
+}
+
+func autoConvert_audit_MatchCondition_To_v1_MatchCondition(in *audit.MatchCondition, out *MatchCondition, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Expression = in.Expression
+	return nil
+}
+
+// Convert_audit_MatchCondition_To_v1_MatchCondition is an autogenerated conversion function.
+func Convert_audit_MatchCondition_To_v1_MatchCondition(in *audit.MatchCondition, out *MatchCondition, s conversion.Scope) error {
+	return autoConvert_audit_MatchCondition_To_v1_MatchCondition(in, out, s)
+}
+
+func autoConvert_v1_MinimumLevels_To_audit_MinimumLevels(in *MinimumLevels, out *audit.MinimumLevels, s conversion.Scope) error {
+	out.Read = audit.Level(in.Read)
+	out.Write = audit.Level(in.Write)
+	return nil
+}
+
+// Convert_v1_MinimumLevels_To_audit_MinimumLevels is an autogenerated conversion function.
+func Convert_v1_MinimumLevels_To_audit_MinimumLevels(in *MinimumLevels, out *audit.MinimumLevels, s conversion.Scope) error {
+	return autoConvert_v1_MinimumLevels_To_audit_MinimumLevels(in, out, s)
+}
+
+func autoConvert_audit_MinimumLevels_To_v1_MinimumLevels(in *audit.MinimumLevels, out *MinimumLevels, s conversion.Scope) error {
+	out.Read = Level(in.Read)
+	out.Write = Level(in.Write)
+	return nil
+}
+
+// Convert_audit_MinimumLevels_To_v1_MinimumLevels is an autogenerated conversion function.
+func Convert_audit_MinimumLevels_To_v1_MinimumLevels(in *audit.MinimumLevels, out *MinimumLevels, s conversion.Scope) error {
+	return autoConvert_audit_MinimumLevels_To_v1_MinimumLevels(in, out, s)
+}
+
 func autoConvert_v1_ObjectReference_To_audit_ObjectReference(in *ObjectReference, out *audit.ObjectReference, s conversion.Scope) error {
 	out.Resource = in.Resource
 	out.Namespace = in.Namespace
@@ -247,6 +533,7 @@ func autoConvert_v1_Policy_To_audit_Policy(in *Policy, out *audit.Policy, s conv
 	out.Rules = *(*[]audit.PolicyRule)(unsafe.Pointer(&in.Rules))
 	out.OmitStages = *(*[]audit.Stage)(unsafe.Pointer(&in.OmitStages))
 	out.OmitManagedFields = in.OmitManagedFields
+	out.MinimumLevels = (*audit.MinimumLevels)(unsafe.Pointer(in.MinimumLevels))
 	return nil
 }
 
@@ -260,6 +547,7 @@ func autoConvert_audit_Policy_To_v1_Policy(in *audit.Policy, out *Policy, s conv
 	out.Rules = *(*[]PolicyRule)(unsafe.Pointer(&in.Rules))
 	out.OmitStages = *(*[]Stage)(unsafe.Pointer(&in.OmitStages))
 	out.OmitManagedFields = in.OmitManagedFields
+	out.MinimumLevels = (*MinimumLevels)(unsafe.Pointer(in.MinimumLevels))
 	return nil
 }
 
@@ -294,12 +582,33 @@ func autoConvert_v1_PolicyRule_To_audit_PolicyRule(in *PolicyRule, out *audit.Po
 	out.Level = audit.Level(in.Level)
 	out.Users = *(*[]string)(unsafe.Pointer(&in.Users))
 	out.UserGroups = *(*[]string)(unsafe.Pointer(&in.UserGroups))
+	out.ExceptUsers = *(*[]string)(unsafe.Pointer(&in.ExceptUsers))
+	out.ExceptUserGroups = *(*[]string)(unsafe.Pointer(&in.ExceptUserGroups))
 	out.Verbs = *(*[]string)(unsafe.Pointer(&in.Verbs))
+	out.ExceptVerbs = *(*[]string)(unsafe.Pointer(&in.ExceptVerbs))
 	out.Resources = *(*[]audit.GroupResources)(unsafe.Pointer(&in.Resources))
 	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.ExceptNamespaces = *(*[]string)(unsafe.Pointer(&in.ExceptNamespaces))
 	out.NonResourceURLs = *(*[]string)(unsafe.Pointer(&in.NonResourceURLs))
 	out.OmitStages = *(*[]audit.Stage)(unsafe.Pointer(&in.OmitStages))
 	out.OmitManagedFields = (*bool)(unsafe.Pointer(in.OmitManagedFields))
+	out.MatchConditions = *(*[]audit.MatchCondition)(unsafe.Pointer(&in.MatchConditions))
+	out.Backends = *(*[]string)(unsafe.Pointer(&in.Backends))
+	out.SamplingRate = (*int32)(unsafe.Pointer(in.SamplingRate))
+	out.SamplingMode = audit.SamplingMode(in.SamplingMode)
+	out.ObjectSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.ObjectSelector))
+	out.NamespaceSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.NamespaceSelector))
+	out.ResponseCodes = *(*[]string)(unsafe.Pointer(&in.ResponseCodes))
+	out.RedactFields = *(*[]string)(unsafe.Pointer(&in.RedactFields))
+	out.ObjectDiff = in.ObjectDiff
+	out.SourceIPs = *(*[]string)(unsafe.Pointer(&in.SourceIPs))
+	out.UserAgents = *(*[]string)(unsafe.Pointer(&in.UserAgents))
+	out.ActiveWindow = (*audit.ActiveWindow)(unsafe.Pointer(in.ActiveWindow))
+	out.Scope = audit.Scope(in.Scope)
+	out.DryRun = (*bool)(unsafe.Pointer(in.DryRun))
+	out.ServerSideApply = (*bool)(unsafe.Pointer(in.ServerSideApply))
+	out.Name = in.Name
+	out.AuditAnnotations = *(*map[string]string)(unsafe.Pointer(&in.AuditAnnotations))
 	return nil
 }
 
@@ -312,12 +621,33 @@ func autoConvert_audit_PolicyRule_To_v1_PolicyRule(in *audit.PolicyRule, out *Po
 	out.Level = Level(in.Level)
 	out.Users = *(*[]string)(unsafe.Pointer(&in.Users))
 	out.UserGroups = *(*[]string)(unsafe.Pointer(&in.UserGroups))
+	out.ExceptUsers = *(*[]string)(unsafe.Pointer(&in.ExceptUsers))
+	out.ExceptUserGroups = *(*[]string)(unsafe.Pointer(&in.ExceptUserGroups))
 	out.Verbs = *(*[]string)(unsafe.Pointer(&in.Verbs))
+	out.ExceptVerbs = *(*[]string)(unsafe.Pointer(&in.ExceptVerbs))
 	out.Resources = *(*[]GroupResources)(unsafe.Pointer(&in.Resources))
 	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.ExceptNamespaces = *(*[]string)(unsafe.Pointer(&in.ExceptNamespaces))
 	out.NonResourceURLs = *(*[]string)(unsafe.Pointer(&in.NonResourceURLs))
 	out.OmitStages = *(*[]Stage)(unsafe.Pointer(&in.OmitStages))
 	out.OmitManagedFields = (*bool)(unsafe.Pointer(in.OmitManagedFields))
+	out.MatchConditions = *(*[]MatchCondition)(unsafe.Pointer(&in.MatchConditions))
+	out.Backends = *(*[]string)(unsafe.Pointer(&in.Backends))
+	out.SamplingRate = (*int32)(unsafe.Pointer(in.SamplingRate))
+	out.SamplingMode = SamplingMode(in.SamplingMode)
+	out.ObjectSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.ObjectSelector))
+	out.NamespaceSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.NamespaceSelector))
+	out.ResponseCodes = *(*[]string)(unsafe.Pointer(&in.ResponseCodes))
+	out.RedactFields = *(*[]string)(unsafe.Pointer(&in.RedactFields))
+	out.ObjectDiff = in.ObjectDiff
+	out.SourceIPs = *(*[]string)(unsafe.Pointer(&in.SourceIPs))
+	out.UserAgents = *(*[]string)(unsafe.Pointer(&in.UserAgents))
+	out.ActiveWindow = (*ActiveWindow)(unsafe.Pointer(in.ActiveWindow))
+	out.Scope = Scope(in.Scope)
+	out.DryRun = (*bool)(unsafe.Pointer(in.DryRun))
+	out.ServerSideApply = (*bool)(unsafe.Pointer(in.ServerSideApply))
+	out.Name = in.Name
+	out.AuditAnnotations = *(*map[string]string)(unsafe.Pointer(&in.AuditAnnotations))
 	return nil
 }
 
@@ -325,3 +655,99 @@ func autoConvert_audit_PolicyRule_To_v1_PolicyRule(in *audit.PolicyRule, out *Po
 func Convert_audit_PolicyRule_To_v1_PolicyRule(in *audit.PolicyRule, out *PolicyRule, s conversion.Scope) error {
 	return autoConvert_audit_PolicyRule_To_v1_PolicyRule(in, out, s)
 }
+
+func autoConvert_v1_SinkPolicy_To_audit_SinkPolicy(in *SinkPolicy, out *audit.SinkPolicy, s conversion.Scope) error {
+	out.Level = audit.Level(in.Level)
+	out.OmitStages = *(*[]audit.Stage)(unsafe.Pointer(&in.OmitStages))
+	out.Verbs = *(*[]string)(unsafe.Pointer(&in.Verbs))
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	return nil
+}
+
+// Convert_v1_SinkPolicy_To_audit_SinkPolicy is an autogenerated conversion function.
+func Convert_v1_SinkPolicy_To_audit_SinkPolicy(in *SinkPolicy, out *audit.SinkPolicy, s conversion.Scope) error {
+	return autoConvert_v1_SinkPolicy_To_audit_SinkPolicy(in, out, s)
+}
+
+func autoConvert_audit_SinkPolicy_To_v1_SinkPolicy(in *audit.SinkPolicy, out *SinkPolicy, s conversion.Scope) error {
+	out.Level = Level(in.Level)
+	out.OmitStages = *(*[]Stage)(unsafe.Pointer(&in.OmitStages))
+	out.Verbs = *(*[]string)(unsafe.Pointer(&in.Verbs))
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	return nil
+}
+
+// Convert_audit_SinkPolicy_To_v1_SinkPolicy is an autogenerated conversion function.
+func Convert_audit_SinkPolicy_To_v1_SinkPolicy(in *audit.SinkPolicy, out *SinkPolicy, s conversion.Scope) error {
+	return autoConvert_audit_SinkPolicy_To_v1_SinkPolicy(in, out, s)
+}
+
+func autoConvert_v1_SinkWebhook_To_audit_SinkWebhook(in *SinkWebhook, out *audit.SinkWebhook, s conversion.Scope) error {
+	out.Throttle = (*audit.WebhookThrottleConfig)(unsafe.Pointer(in.Throttle))
+	if err := Convert_v1_WebhookClientConfig_To_audit_WebhookClientConfig(&in.ClientConfig, &out.ClientConfig, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_SinkWebhook_To_audit_SinkWebhook is an autogenerated conversion function.
+func Convert_v1_SinkWebhook_To_audit_SinkWebhook(in *SinkWebhook, out *audit.SinkWebhook, s conversion.Scope) error {
+	return autoConvert_v1_SinkWebhook_To_audit_SinkWebhook(in, out, s)
+}
+
+func autoConvert_audit_SinkWebhook_To_v1_SinkWebhook(in *audit.SinkWebhook, out *SinkWebhook, s conversion.Scope) error {
+	out.Throttle = (*WebhookThrottleConfig)(unsafe.Pointer(in.Throttle))
+	if err := Convert_audit_WebhookClientConfig_To_v1_WebhookClientConfig(&in.ClientConfig, &out.ClientConfig, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_audit_SinkWebhook_To_v1_SinkWebhook is an autogenerated conversion function.
+func Convert_audit_SinkWebhook_To_v1_SinkWebhook(in *audit.SinkWebhook, out *SinkWebhook, s conversion.Scope) error {
+	return autoConvert_audit_SinkWebhook_To_v1_SinkWebhook(in, out, s)
+}
+
+func autoConvert_v1_WebhookClientConfig_To_audit_WebhookClientConfig(in *WebhookClientConfig, out *audit.WebhookClientConfig, s conversion.Scope) error {
+	out.URL = in.URL
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_v1_WebhookClientConfig_To_audit_WebhookClientConfig is an autogenerated conversion function.
+func Convert_v1_WebhookClientConfig_To_audit_WebhookClientConfig(in *WebhookClientConfig, out *audit.WebhookClientConfig, s conversion.Scope) error {
+	return autoConvert_v1_WebhookClientConfig_To_audit_WebhookClientConfig(in, out, s)
+}
+
+func autoConvert_audit_WebhookClientConfig_To_v1_WebhookClientConfig(in *audit.WebhookClientConfig, out *WebhookClientConfig, s conversion.Scope) error {
+	out.URL = in.URL
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_audit_WebhookClientConfig_To_v1_WebhookClientConfig is an autogenerated conversion function.
+func Convert_audit_WebhookClientConfig_To_v1_WebhookClientConfig(in *audit.WebhookClientConfig, out *WebhookClientConfig, s conversion.Scope) error {
+	return autoConvert_audit_WebhookClientConfig_To_v1_WebhookClientConfig(in, out, s)
+}
+
+func autoConvert_v1_WebhookThrottleConfig_To_audit_WebhookThrottleConfig(in *WebhookThrottleConfig, out *audit.WebhookThrottleConfig, s conversion.Scope) error {
+	out.QPS = (*int64)(unsafe.Pointer(in.QPS))
+	out.Burst = (*int64)(unsafe.Pointer(in.Burst))
+	return nil
+}
+
+// Convert_v1_WebhookThrottleConfig_To_audit_WebhookThrottleConfig is an autogenerated conversion function.
+func Convert_v1_WebhookThrottleConfig_To_audit_WebhookThrottleConfig(in *WebhookThrottleConfig, out *audit.WebhookThrottleConfig, s conversion.Scope) error {
+	return autoConvert_v1_WebhookThrottleConfig_To_audit_WebhookThrottleConfig(in, out, s)
+}
+
+func autoConvert_audit_WebhookThrottleConfig_To_v1_WebhookThrottleConfig(in *audit.WebhookThrottleConfig, out *WebhookThrottleConfig, s conversion.Scope) error {
+	out.QPS = (*int64)(unsafe.Pointer(in.QPS))
+	out.Burst = (*int64)(unsafe.Pointer(in.Burst))
+	return nil
+}
+
+// Convert_audit_WebhookThrottleConfig_To_v1_WebhookThrottleConfig is an autogenerated conversion function.
+func Convert_audit_WebhookThrottleConfig_To_v1_WebhookThrottleConfig(in *audit.WebhookThrottleConfig, out *WebhookThrottleConfig, s conversion.Scope) error {
+	return autoConvert_audit_WebhookThrottleConfig_To_v1_WebhookThrottleConfig(in, out, s)
+}
@@ -17,20 +17,38 @@ limitations under the License.
 package validation
 
 import (
+	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/apis/audit"
+	auditutil "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/audit/policy/matchconditions"
+	netutils "k8s.io/utils/net"
 )
 
 // ValidatePolicy validates the audit policy
 func ValidatePolicy(policy *audit.Policy) field.ErrorList {
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, validateOmitStages(policy.OmitStages, field.NewPath("omitStages"))...)
+	if policy.MinimumLevels != nil {
+		allErrs = append(allErrs, validateMinimumLevels(policy.MinimumLevels, field.NewPath("minimumLevels"))...)
+	}
 	rulePath := field.NewPath("rules")
+	names := sets.NewString()
 	for i, rule := range policy.Rules {
 		allErrs = append(allErrs, validatePolicyRule(rule, rulePath.Index(i))...)
+		if rule.Name != "" {
+			if names.Has(rule.Name) {
+				allErrs = append(allErrs, field.Duplicate(rulePath.Index(i).Child("name"), rule.Name))
+			}
+			names.Insert(rule.Name)
+		}
 	}
 	return allErrs
 }
@@ -38,12 +56,43 @@ func ValidatePolicy(policy *audit.Policy) field.ErrorList {
 func validatePolicyRule(rule audit.PolicyRule, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, validateLevel(rule.Level, fldPath.Child("level"))...)
+	allErrs = append(allErrs, validateWildcardNames(rule.Users, fldPath.Child("users"))...)
+	allErrs = append(allErrs, validateWildcardNames(rule.UserGroups, fldPath.Child("userGroups"))...)
+	allErrs = append(allErrs, validateWildcardNames(rule.ExceptUsers, fldPath.Child("exceptUsers"))...)
+	allErrs = append(allErrs, validateWildcardNames(rule.ExceptUserGroups, fldPath.Child("exceptUserGroups"))...)
 	allErrs = append(allErrs, validateNonResourceURLs(rule.NonResourceURLs, fldPath.Child("nonResourceURLs"))...)
 	allErrs = append(allErrs, validateResources(rule.Resources, fldPath.Child("resources"))...)
 	allErrs = append(allErrs, validateOmitStages(rule.OmitStages, fldPath.Child("omitStages"))...)
+	allErrs = append(allErrs, validateMatchConditions(rule.MatchConditions, fldPath.Child("matchConditions"))...)
+	allErrs = append(allErrs, validateBackends(rule.Backends, fldPath.Child("backends"))...)
+	allErrs = append(allErrs, validateSampling(rule.SamplingRate, rule.SamplingMode, fldPath)...)
+	switch rule.Scope {
+	case "", audit.ScopeCluster, audit.ScopeNamespaced, audit.ScopeAll:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("scope"), rule.Scope, validScopes))
+	}
+	if rule.ObjectSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(rule.ObjectSelector, fldPath.Child("objectSelector"))...)
+	}
+	allErrs = append(allErrs, validateResponseCodes(rule.ResponseCodes, fldPath.Child("responseCodes"))...)
+	if rule.NamespaceSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(rule.NamespaceSelector, fldPath.Child("namespaceSelector"))...)
+	}
+	allErrs = append(allErrs, validateRedactFields(rule.RedactFields, fldPath.Child("redactFields"))...)
+	allErrs = append(allErrs, validateSourceIPs(rule.SourceIPs, fldPath.Child("sourceIPs"))...)
+	if rule.ActiveWindow != nil {
+		allErrs = append(allErrs, validateActiveWindow(rule.ActiveWindow, fldPath.Child("activeWindow"))...)
+	}
+	allErrs = append(allErrs, validation.ValidateAnnotations(rule.AuditAnnotations, fldPath.Child("auditAnnotations"))...)
+	for i, userAgent := range rule.UserAgents {
+		if userAgent == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("userAgents").Index(i), "user agent pattern must not be empty"))
+		}
+	}
 
 	if len(rule.NonResourceURLs) > 0 {
-		if len(rule.Resources) > 0 || len(rule.Namespaces) > 0 {
+		if len(rule.Resources) > 0 || len(rule.Namespaces) > 0 || rule.ObjectSelector != nil || rule.NamespaceSelector != nil ||
+			rule.Scope == audit.ScopeCluster || rule.Scope == audit.ScopeNamespaced {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("nonResourceURLs"), rule.NonResourceURLs, "rules cannot apply to both regular resources and non-resource URLs"))
 		}
 	}
@@ -65,6 +114,17 @@ var validOmitStages = []string{
 	string(audit.StagePanic),
 }
 
+func validateMinimumLevels(levels *audit.MinimumLevels, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if levels.Read != "" {
+		allErrs = append(allErrs, validateLevel(levels.Read, fldPath.Child("read"))...)
+	}
+	if levels.Write != "" {
+		allErrs = append(allErrs, validateLevel(levels.Write, fldPath.Child("write"))...)
+	}
+	return allErrs
+}
+
 func validateLevel(level audit.Level, fldPath *field.Path) field.ErrorList {
 	switch level {
 	case audit.LevelNone, audit.LevelMetadata, audit.LevelRequest, audit.LevelRequestResponse:
@@ -83,6 +143,13 @@ func validateNonResourceURLs(urls []string, fldPath *field.Path) field.ErrorList
 			continue
 		}
 
+		if strings.HasPrefix(url, "~") {
+			if _, err := regexp.Compile(url[1:]); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i), url, fmt.Sprintf("invalid regular expression: %v", err)))
+			}
+			continue
+		}
+
 		if !strings.HasPrefix(url, "/") {
 			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), url, "non-resource URL rules must begin with a '/' character"))
 		}
@@ -94,11 +161,72 @@ func validateNonResourceURLs(urls []string, fldPath *field.Path) field.ErrorList
 	return allErrs
 }
 
+func validateWildcardNames(names []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, name := range names {
+		if name != "" && strings.ContainsRune(name[:len(name)-1], '*') {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), name, "wildcards '*' must be the final character of the name"))
+		}
+	}
+	return allErrs
+}
+
+func validateResponseCodes(codes []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, code := range codes {
+		if !isResponseCode(code) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), code, "must be an HTTP status code like 403 or a status code class like 5xx"))
+		}
+	}
+	return allErrs
+}
+
+func isResponseCode(code string) bool {
+	if len(code) != 3 || code[0] < '1' || code[0] > '5' {
+		return false
+	}
+	if code[1:] == "xx" {
+		return true
+	}
+	return code[1] >= '0' && code[1] <= '9' && code[2] >= '0' && code[2] <= '9'
+}
+
+func validateRedactFields(paths []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, path := range paths {
+		if _, err := auditutil.ParseFieldPath(path); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), path, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateSourceIPs(cidrs []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, cidr := range cidrs {
+		if _, _, err := netutils.ParseCIDRSloppy(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), cidr, "must be a CIDR like 10.0.0.0/8"))
+		}
+	}
+	return allErrs
+}
+
+func validateActiveWindow(window *audit.ActiveWindow, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if window.Start == nil && window.End == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of start and end must be set"))
+	}
+	if window.Start != nil && window.End != nil && !window.Start.Before(window.End) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("end"), window.End, "must be after start"))
+	}
+	return allErrs
+}
+
 func validateResources(groupResources []audit.GroupResources, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	for _, groupResource := range groupResources {
-		// The empty string represents the core API group.
-		if len(groupResource.Group) != 0 {
+		// The empty string represents the core API group, "*" all API groups.
+		if len(groupResource.Group) != 0 && groupResource.Group != "*" {
 			// Group names must be lower case and be valid DNS subdomains.
 			// reference: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md
 			// an error is returned for group name like rbac.authorization.k8s.io/v1beta1
@@ -118,16 +246,147 @@ func validateResources(groupResources []audit.GroupResources, fldPath *field.Pat
 func validateOmitStages(omitStages []audit.Stage, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	for i, stage := range omitStages {
-		valid := false
-		for _, validOmitStage := range validOmitStages {
-			if string(stage) == validOmitStage {
-				valid = true
-				break
-			}
-		}
-		if !valid {
+		if !stage.IsKnown() {
 			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), string(stage), "allowed stages are "+strings.Join(validOmitStages, ",")))
 		}
 	}
 	return allErrs
 }
+
+var validSamplingModes = []string{
+	string(audit.SamplingModeRandom),
+	string(audit.SamplingModeEveryNth),
+}
+
+var validScopes = []string{
+	string(audit.ScopeCluster),
+	string(audit.ScopeNamespaced),
+	string(audit.ScopeAll),
+}
+
+func validateSampling(rate *int32, mode audit.SamplingMode, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if rate != nil && *rate < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("samplingRate"), *rate, "must be greater than or equal to 1"))
+	}
+	switch mode {
+	case "", audit.SamplingModeRandom, audit.SamplingModeEveryNth:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("samplingMode"), mode, validSamplingModes))
+	}
+	return allErrs
+}
+
+func validateBackends(backends []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := sets.NewString()
+	for i, backend := range backends {
+		if len(backend) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), ""))
+		} else if names.Has(backend) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), backend))
+		}
+		names.Insert(backend)
+	}
+	return allErrs
+}
+
+func validateMatchConditions(conditions []audit.MatchCondition, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := sets.NewString()
+	for i, condition := range conditions {
+		idxPath := fldPath.Index(i)
+		if len(condition.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else if names.Has(condition.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), condition.Name))
+		} else {
+			names.Insert(condition.Name)
+		}
+		if len(condition.Expression) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("expression"), ""))
+		} else if err := matchconditions.CompileExpression(condition.Expression); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("expression"), condition.Expression, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// ValidateAuditSink validates the audit sink.
+func ValidateAuditSink(sink *audit.AuditSink) field.ErrorList {
+	return validateAuditSink(sink, nil)
+}
+
+// ValidateAuditSinkList validates the audit sinks of the list, whose names
+// must be unique.
+func ValidateAuditSinkList(list *audit.AuditSinkList) field.ErrorList {
+	var allErrs field.ErrorList
+	itemsPath := field.NewPath("items")
+	names := sets.NewString()
+	for i := range list.Items {
+		sink := &list.Items[i]
+		allErrs = append(allErrs, validateAuditSink(sink, itemsPath.Index(i))...)
+		if names.Has(sink.Name) {
+			allErrs = append(allErrs, field.Duplicate(itemsPath.Index(i).Child("metadata", "name"), sink.Name))
+		}
+		names.Insert(sink.Name)
+	}
+	return allErrs
+}
+
+func validateAuditSink(sink *audit.AuditSink, fldPath *field.Path) field.ErrorList {
+	allErrs := validation.ValidateObjectMeta(&sink.ObjectMeta, false, validation.NameIsDNSSubdomain, fldPath.Child("metadata"))
+	specPath := fldPath.Child("spec")
+	allErrs = append(allErrs, validateSinkPolicy(sink.Spec.Policy, specPath.Child("policy"))...)
+	allErrs = append(allErrs, validateSinkWebhook(sink.Spec.Webhook, specPath.Child("webhook"))...)
+	return allErrs
+}
+
+func validateSinkPolicy(policy audit.SinkPolicy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateLevel(policy.Level, fldPath.Child("level"))...)
+	allErrs = append(allErrs, validateOmitStages(policy.OmitStages, fldPath.Child("omitStages"))...)
+	return allErrs
+}
+
+func validateSinkWebhook(webhook audit.SinkWebhook, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if throttle := webhook.Throttle; throttle != nil {
+		throttlePath := fldPath.Child("throttle")
+		if throttle.QPS != nil && *throttle.QPS <= 0 {
+			allErrs = append(allErrs, field.Invalid(throttlePath.Child("qps"), *throttle.QPS, "must be a positive number"))
+		}
+		if throttle.Burst != nil && *throttle.Burst <= 0 {
+			allErrs = append(allErrs, field.Invalid(throttlePath.Child("burst"), *throttle.Burst, "must be a positive number"))
+		}
+	}
+	allErrs = append(allErrs, validateSinkURL(webhook.ClientConfig.URL, fldPath.Child("clientConfig", "url"))...)
+	return allErrs
+}
+
+func validateSinkURL(rawURL string, fldPath *field.Path) field.ErrorList {
+	if rawURL == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, rawURL, err.Error())}
+	}
+	var allErrs field.ErrorList
+	if u.Scheme != "https" {
+		allErrs = append(allErrs, field.Invalid(fldPath, rawURL, "'https' is the only allowed URL scheme"))
+	}
+	if u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath, rawURL, "host must be provided"))
+	}
+	if u.User != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, rawURL, "user information is not permitted in the URL"))
+	}
+	if u.RawQuery != "" || u.ForceQuery {
+		allErrs = append(allErrs, field.Invalid(fldPath, rawURL, "query parameters are not permitted in the URL"))
+	}
+	if u.Fragment != "" {
+		allErrs = append(allErrs, field.Invalid(fldPath, rawURL, "fragments are not permitted in the URL"))
+	}
+	return allErrs
+}
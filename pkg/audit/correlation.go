@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"net/http"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// PropagateAuditID sets the audit headers of a request that is proxied to
+// another server while serving the request of ctx, e.g. by an aggregator to an
+// aggregated apiserver. The proxied request is the same request, so the other
+// server records its events under the same audit ID, also when the audit ID
+// was generated by this server rather than sent by the client.
+func PropagateAuditID(ctx context.Context, header http.Header) {
+	if auditID, ok := request.AuditIDFrom(ctx); ok {
+		header.Set(auditinternal.HeaderAuditID, string(auditID))
+	}
+	if parentAuditID, ok := request.ParentAuditIDFrom(ctx); ok {
+		header.Set(auditinternal.HeaderParentAuditID, string(parentAuditID))
+	}
+}
+
+// WithParentAuditID returns a round tripper that sets the Audit-Parent-ID header
+// of the requests sent with a context carrying an audit ID, i.e. the requests
+// an apiserver sends to other servers while serving a request. The other server
+// generates a new audit ID for such a request and records the audit ID of the
+// request it was sent on behalf of as the parent audit ID of its events.
+//
+// It is meant to wrap the transport of the clients of aggregated apiservers,
+// e.g. by rest.Config.Wrap.
+func WithParentAuditID(rt http.RoundTripper) http.RoundTripper {
+	return &parentAuditIDRoundTripper{delegate: rt}
+}
+
+type parentAuditIDRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+var _ utilnet.RoundTripperWrapper = &parentAuditIDRoundTripper{}
+
+func (rt *parentAuditIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	auditID, ok := request.AuditIDFrom(req.Context())
+	if !ok || len(req.Header.Get(auditinternal.HeaderParentAuditID)) > 0 {
+		return rt.delegate.RoundTrip(req)
+	}
+	// Round trippers must not modify the request.
+	req = utilnet.CloneRequest(req)
+	req.Header.Set(auditinternal.HeaderParentAuditID, string(auditID))
+	return rt.delegate.RoundTrip(req)
+}
+
+func (rt *parentAuditIDRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.delegate
+}
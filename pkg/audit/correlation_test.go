@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropagateAuditID(t *testing.T) {
+	header := http.Header{}
+	PropagateAuditID(context.Background(), header)
+	assert.Empty(t, header, "no audit headers without an audit ID")
+
+	ctx := request.WithAuditID(context.Background(), "child")
+	ctx = request.WithParentAuditID(ctx, "parent")
+	header.Set(auditinternal.HeaderAuditID, "spoofed")
+	PropagateAuditID(ctx, header)
+	assert.Equal(t, "child", header.Get(auditinternal.HeaderAuditID))
+	assert.Equal(t, "parent", header.Get(auditinternal.HeaderParentAuditID))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithParentAuditID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		auditID  types.UID
+		header   string
+		expected string
+	}{{
+		name: "no audit ID",
+	}, {
+		name:     "audit ID",
+		auditID:  "parent",
+		expected: "parent",
+	}, {
+		name:     "parent audit ID already set",
+		auditID:  "parent",
+		header:   "other",
+		expected: "other",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			rt := WithParentAuditID(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				got = req.Header.Get(auditinternal.HeaderParentAuditID)
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			}))
+
+			ctx := request.WithAuditID(context.Background(), tc.auditID)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://localhost/api", nil)
+			require.NoError(t, err)
+			if tc.header != "" {
+				req.Header.Set(auditinternal.HeaderParentAuditID, tc.header)
+			}
+			_, err = rt.RoundTrip(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+			assert.Equal(t, tc.header, req.Header.Get(auditinternal.HeaderParentAuditID), "the request must not be modified")
+			assert.Empty(t, req.Header.Get(auditinternal.HeaderAuditID), "the audit ID must not be propagated")
+		})
+	}
+}
+
+func TestNewEventFromRequestParentAuditID(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/namespaces", nil)
+	require.NoError(t, err)
+	ctx := request.WithAuditID(req.Context(), "child")
+	ctx = request.WithParentAuditID(ctx, "parent")
+
+	ev, err := NewEventFromRequest(req.WithContext(ctx), time.Now(), auditinternal.LevelMetadata, &authorizer.AttributesRecord{})
+	require.NoError(t, err)
+	assert.Equal(t, types.UID("child"), ev.AuditID)
+	assert.Equal(t, types.UID("parent"), ev.ParentAuditID)
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package audit
 
 import (
+	"net"
+
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 )
@@ -31,6 +34,33 @@ type AuditContext struct {
 	// Event is the audit Event object that is being captured to be written in
 	// the API audit log. It is set to nil when the request is not being audited.
 	Event *audit.Event
+
+	// EvaluateObject, if set, evaluates the audit configuration of the request
+	// once the request object is known. It is set if the configuration evaluated
+	// from the request attributes alone is provisional.
+	EvaluateObject func(runtime.Object) RequestAuditConfigWithLevel
+
+	// oldObject is the encoded object before an update or patch request, recorded
+	// to diff the response object against if RequestAuditConfig.ObjectDiff is set.
+	oldObject *runtime.Unknown
+}
+
+// RequestAttributes are the authorizer attributes of a request together with
+// attributes of the HTTP request that audit policy rules may match on.
+type RequestAttributes struct {
+	authorizer.Attributes
+
+	// SourceIP is the IP address of the client connection, or nil if unknown.
+	SourceIP net.IP
+
+	// UserAgent is the User-Agent header of the request.
+	UserAgent string
+
+	// DryRun is true if the request is a dry-run request.
+	DryRun bool
+
+	// ServerSideApply is true if the request is a server-side apply patch.
+	ServerSideApply bool
 }
 
 // RequestAuditConfig is the evaluated audit configuration that is applicable to
@@ -43,6 +73,23 @@ type RequestAuditConfig struct {
 	// OmitManagedFields indicates whether to omit the managed fields of the request
 	// and response bodies from being written to the API audit log.
 	OmitManagedFields bool
+
+	// Backends is the names of the backends the events of the request are sent to.
+	// An empty list means the events are sent to every backend.
+	Backends []string
+
+	// RedactFields is the field paths whose values are redacted in the request
+	// and response bodies written to the API audit log.
+	RedactFields []string
+
+	// ObjectDiff indicates whether to replace the request and response bodies of
+	// update and patch requests with a merge patch from the old to the new object.
+	ObjectDiff bool
+
+	// EvaluateResponse, if set, evaluates the final audit configuration of the
+	// request once the response code is known, given the audit annotations of the
+	// request. It is set if the configuration is provisional until then.
+	EvaluateResponse func(responseCode int32, annotations map[string]string) RequestAuditConfigWithLevel
 }
 
 // RequestAuditConfigWithLevel includes Level at which the request is being audited.
@@ -54,6 +101,23 @@ type RequestAuditConfigWithLevel struct {
 
 	// Level at which the request is being audited at
 	Level audit.Level
+
+	// DependsOnObject is true if the configuration is provisional because it
+	// depends on the request object, which is not known yet. In that case Level
+	// is the highest level the request may be audited at.
+	DependsOnObject bool
+
+	// MatchedRule identifies the policy rule the configuration was evaluated from.
+	// It is nil if no rule matched or the evaluator does not report rules.
+	MatchedRule *PolicyRuleRef
+}
+
+// PolicyRuleRef identifies a rule of an audit policy.
+type PolicyRuleRef struct {
+	// Index is the index of the rule in the policy.
+	Index int
+	// Name is the name of the rule, if any.
+	Name string
 }
 
 // PolicyRuleEvaluator exposes methods for evaluating the policy rules.
@@ -63,3 +127,15 @@ type PolicyRuleEvaluator interface {
 	// is applicable to the given equest.
 	EvaluatePolicyRule(authorizer.Attributes) RequestAuditConfigWithLevel
 }
+
+// ObjectPolicyRuleEvaluator is implemented by PolicyRuleEvaluators with policy
+// rules that match on the request object.
+type ObjectPolicyRuleEvaluator interface {
+	PolicyRuleEvaluator
+
+	// EvaluatePolicyRuleForObject evaluates the audit policy of the apiserver
+	// against the given authorizer attributes and request object. It is meant
+	// to be called if EvaluatePolicyRule returned a configuration that
+	// DependsOnObject.
+	EvaluatePolicyRuleForObject(authorizer.Attributes, runtime.Object) RequestAuditConfigWithLevel
+}
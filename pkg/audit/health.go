@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckHealth returns the health of the backend if it implements
+// HealthChecker, and nil otherwise. Backends wrapping other backends use it to
+// report the health of their delegates.
+func CheckHealth(backend Backend) error {
+	if checker, ok := backend.(HealthChecker); ok {
+		return checker.HealthCheck()
+	}
+	return nil
+}
+
+// ShutdownWithContext shuts down the backend, returning an error if the
+// context is done before it completes. Backends not implementing
+// GracefulShutdowner keep shutting down in the background in that case.
+func ShutdownWithContext(ctx context.Context, backend Backend) error {
+	if shutdowner, ok := backend.(GracefulShutdowner); ok {
+		return shutdowner.ShutdownWithContext(ctx)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		backend.Shutdown()
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("audit backend %s did not shut down: %w", backend, ctx.Err())
+	}
+}
@@ -30,6 +30,25 @@ const (
 	subsystem = "apiserver_audit"
 )
 
+// Reasons of audit events being dropped by audit plugins.
+const (
+	// DropReasonBufferFull is the reason of events dropped since the buffer
+	// of the backend was full.
+	DropReasonBufferFull = "buffer_full"
+	// DropReasonTooLarge is the reason of events dropped since they exceeded
+	// the maximum event size.
+	DropReasonTooLarge = "too_large"
+	// DropReasonSendFailed is the reason of events dropped since sending them
+	// failed.
+	DropReasonSendFailed = "send_failed"
+	// DropReasonShutdown is the reason of events dropped since they were not
+	// delivered before the shutdown deadline.
+	DropReasonShutdown = "shutdown"
+	// DropReasonRateLimited is the reason of events dropped since their user
+	// exceeded the rate of events allowed for each user.
+	DropReasonRateLimited = "rate_limited"
+)
+
 /*
  * By default, all the following metrics are defined as falling under
  * ALPHA stability level https://github.com/kubernetes/enhancements/blob/master/keps/sig-instrumentation/1209-metrics-stability/kubernetes-control-plane-metrics-stability.md#stability-classes)
@@ -56,6 +75,18 @@ var (
 		},
 		[]string{"plugin"},
 	)
+	droppedCounter = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "events_dropped_total",
+			Help: "Counter of audit events dropped. Plugin identifies the backend the events were " +
+				"dropped for. Reason is 'buffer_full' if its buffer was full, 'too_large' if the events " +
+				"exceeded the maximum event size, 'send_failed' if sending them failed, 'shutdown' if they " +
+				"were not delivered before the shutdown deadline, or 'rate_limited' if their user exceeded its rate.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin", "reason"},
+	)
 	levelCounter = metrics.NewCounterVec(
 		&metrics.CounterOpts{
 			Subsystem:      subsystem,
@@ -80,6 +111,7 @@ var (
 func init() {
 	legacyregistry.MustRegister(eventCounter)
 	legacyregistry.MustRegister(errorCounter)
+	legacyregistry.MustRegister(droppedCounter)
 	legacyregistry.MustRegister(levelCounter)
 	legacyregistry.MustRegister(ApiserverAuditDroppedCounter)
 }
@@ -94,6 +126,12 @@ func ObservePolicyLevel(ctx context.Context, level auditinternal.Level) {
 	levelCounter.WithContext(ctx).WithLabelValues(string(level)).Inc()
 }
 
+// ObserveDroppedEvents updates the relevant prometheus metrics for audit events
+// dropped for the plugin for the reason.
+func ObserveDroppedEvents(plugin, reason string, count int) {
+	droppedCounter.WithLabelValues(plugin, reason).Add(float64(count))
+}
+
 // HandlePluginError handles an error that occurred in an audit plugin. This method should only be
 // used if the error may have prevented the audit event from being properly recorded. The events are
 // logged to the debug log.
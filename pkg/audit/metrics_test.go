@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestObserveDroppedEvents(t *testing.T) {
+	droppedCounter.Reset()
+
+	ObserveDroppedEvents("webhook", DropReasonSendFailed, 3)
+	ObserveDroppedEvents("webhook", DropReasonTooLarge, 1)
+	ObserveDroppedEvents("log", DropReasonBufferFull, 2)
+
+	expected := strings.NewReader(`
+		# HELP apiserver_audit_events_dropped_total [ALPHA] Counter of audit events dropped. Plugin identifies the backend the events were dropped for. Reason is 'buffer_full' if its buffer was full, 'too_large' if the events exceeded the maximum event size, 'send_failed' if sending them failed, 'shutdown' if they were not delivered before the shutdown deadline, or 'rate_limited' if their user exceeded its rate.
+		# TYPE apiserver_audit_events_dropped_total counter
+		apiserver_audit_events_dropped_total{plugin="log",reason="buffer_full"} 2
+		apiserver_audit_events_dropped_total{plugin="webhook",reason="send_failed"} 3
+		apiserver_audit_events_dropped_total{plugin="webhook",reason="too_large"} 1
+`)
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, expected, "apiserver_audit_events_dropped_total"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+)
+
+// RequestLatencyAnnotationKey is the annotation RequestLatencyMutator sets to
+// the time elapsed between receiving the request and the stage of the event.
+const RequestLatencyAnnotationKey = "audit.k8s.io/request-latency"
+
+// EventMutator enriches audit events before they are written by backends.
+type EventMutator interface {
+	// MutateEvent modifies the event. The event and its annotations are a copy
+	// owned by the mutators, other fields referencing memory, like the request
+	// and response objects, are shared and must be replaced, not modified.
+	MutateEvent(ev *auditinternal.Event)
+}
+
+// EventMutatorFunc is an EventMutator calling the function.
+type EventMutatorFunc func(ev *auditinternal.Event)
+
+// MutateEvent calls f(ev).
+func (f EventMutatorFunc) MutateEvent(ev *auditinternal.Event) {
+	f(ev)
+}
+
+// StaticAnnotationsMutator returns a mutator adding the annotations to every
+// event, e.g. the name of the cluster, the region or the identity of the node.
+// Annotations already set on the event are kept.
+func StaticAnnotationsMutator(annotations map[string]string) EventMutator {
+	return EventMutatorFunc(func(ev *auditinternal.Event) {
+		for k, v := range annotations {
+			if _, ok := ev.Annotations[k]; !ok {
+				ev.Annotations[k] = v
+			}
+		}
+	})
+}
+
+// RequestLatencyMutator is a mutator annotating events past the
+// RequestReceived stage with the latency of the request, as of their stage.
+var RequestLatencyMutator EventMutator = EventMutatorFunc(func(ev *auditinternal.Event) {
+	if ev.Stage == auditinternal.StageRequestReceived || ev.RequestReceivedTimestamp.IsZero() || ev.StageTimestamp.IsZero() {
+		return
+	}
+	ev.Annotations[RequestLatencyAnnotationKey] = ev.StageTimestamp.Sub(ev.RequestReceivedTimestamp.Time).String()
+})
+
+type mutatingBackend struct {
+	delegate Backend
+	mutators []EventMutator
+}
+
+var _ Backend = &mutatingBackend{}
+var _ RoutingSink = &mutatingBackend{}
+
+// NewMutatingBackend returns a backend running the mutators in order on a copy
+// of each event, before passing it to the delegate. The delegate is returned
+// if there are no mutators. If the delegate is a RoutingSink, events routed to
+// some of its backends are mutated the same way.
+func NewMutatingBackend(delegate Backend, mutators ...EventMutator) Backend {
+	if len(mutators) == 0 {
+		return delegate
+	}
+	return &mutatingBackend{
+		delegate: delegate,
+		mutators: mutators,
+	}
+}
+
+func (b *mutatingBackend) ProcessEvents(events ...*auditinternal.Event) bool {
+	return b.delegate.ProcessEvents(b.mutate(events)...)
+}
+
+// ProcessEventsForBackends mutates the events and delivers them to the backends
+// with the given names if the delegate is a RoutingSink, to all of its backends
+// otherwise.
+func (b *mutatingBackend) ProcessEventsForBackends(backends []string, events ...*auditinternal.Event) bool {
+	if routingSink, ok := b.delegate.(RoutingSink); ok {
+		return routingSink.ProcessEventsForBackends(backends, b.mutate(events)...)
+	}
+	return b.ProcessEvents(events...)
+}
+
+// mutate returns mutated copies of the events.
+func (b *mutatingBackend) mutate(events []*auditinternal.Event) []*auditinternal.Event {
+	mutated := make([]*auditinternal.Event, 0, len(events))
+	for _, ev := range events {
+		e := *ev
+		e.Annotations = make(map[string]string, len(ev.Annotations))
+		for k, v := range ev.Annotations {
+			e.Annotations[k] = v
+		}
+		for _, m := range b.mutators {
+			m.MutateEvent(&e)
+		}
+		mutated = append(mutated, &e)
+	}
+	return mutated
+}
+
+func (b *mutatingBackend) Run(stopCh <-chan struct{}) error {
+	return b.delegate.Run(stopCh)
+}
+
+func (b *mutatingBackend) Shutdown() {
+	b.delegate.Shutdown()
+}
+
+func (b *mutatingBackend) ShutdownWithContext(ctx context.Context) error {
+	return ShutdownWithContext(ctx, b.delegate)
+}
+
+func (b *mutatingBackend) HealthCheck() error {
+	return CheckHealth(b.delegate)
+}
+
+func (b *mutatingBackend) String() string {
+	return fmt.Sprintf("mutating<%s>", b.delegate)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+)
+
+func TestMutatingBackend(t *testing.T) {
+	received := time.Now()
+	events := []*auditinternal.Event{{
+		AuditID:                  "0",
+		Stage:                    auditinternal.StageRequestReceived,
+		RequestReceivedTimestamp: metav1.NewMicroTime(received),
+		StageTimestamp:           metav1.NewMicroTime(received),
+	}, {
+		AuditID:                  "1",
+		Stage:                    auditinternal.StageResponseComplete,
+		RequestReceivedTimestamp: metav1.NewMicroTime(received),
+		StageTimestamp:           metav1.NewMicroTime(received.Add(1500 * time.Millisecond)),
+		Annotations:              map[string]string{"region": "kept"},
+	}}
+
+	delegate := new(fakeBackend)
+	b := NewMutatingBackend(delegate,
+		StaticAnnotationsMutator(map[string]string{"cluster": "test", "region": "eu"}),
+		RequestLatencyMutator,
+		EventMutatorFunc(func(ev *auditinternal.Event) {
+			ev.Annotations["custom"] = string(ev.AuditID)
+		}),
+	)
+	if !b.ProcessEvents(events...) {
+		t.Fatal("ProcessEvents failed")
+	}
+	if len(delegate.events) != len(events) {
+		t.Fatalf("expected %d events, got %d", len(events), len(delegate.events))
+	}
+
+	expected := []map[string]string{
+		{"cluster": "test", "region": "eu", "custom": "0"},
+		{"cluster": "test", "region": "kept", "custom": "1", RequestLatencyAnnotationKey: "1.5s"},
+	}
+	for i, ev := range delegate.events {
+		if len(ev.Annotations) != len(expected[i]) {
+			t.Errorf("event %d: expected annotations %v, got %v", i, expected[i], ev.Annotations)
+			continue
+		}
+		for k, v := range expected[i] {
+			if ev.Annotations[k] != v {
+				t.Errorf("event %d: expected annotation %s=%q, got %q", i, k, v, ev.Annotations[k])
+			}
+		}
+	}
+	if events[0].Annotations != nil || len(events[1].Annotations) != 1 {
+		t.Errorf("events processed must not be modified, got annotations %v and %v", events[0].Annotations, events[1].Annotations)
+	}
+}
+
+func TestMutatingBackendWithoutMutators(t *testing.T) {
+	delegate := new(fakeBackend)
+	if b := NewMutatingBackend(delegate); b != delegate {
+		t.Errorf("expected the delegate without mutators, got %v", b)
+	}
+}
+
+func TestMutatingBackendRouting(t *testing.T) {
+	log, webhook := new(fakeBackend), new(fakeBackend)
+	b := NewMutatingBackend(RoutedUnion(
+		NamedBackend{Name: "log", Backend: log},
+		NamedBackend{Name: "webhook", Backend: webhook},
+	), StaticAnnotationsMutator(map[string]string{"cluster": "test"}))
+
+	routingSink, ok := b.(RoutingSink)
+	if !ok {
+		t.Fatalf("expected a RoutingSink, got %T", b)
+	}
+	if !routingSink.ProcessEventsForBackends([]string{"webhook"}, &auditinternal.Event{AuditID: "0"}) {
+		t.Fatal("ProcessEventsForBackends failed")
+	}
+	if len(log.events) != 0 {
+		t.Errorf("expected no events for the log backend, got %d", len(log.events))
+	}
+	if len(webhook.events) != 1 || webhook.events[0].Annotations["cluster"] != "test" {
+		t.Errorf("expected a mutated event for the webhook backend, got %v", webhook.events)
+	}
+}
+
+func TestMutatingBackendRoutingWithoutRoutingSink(t *testing.T) {
+	delegate := new(fakeBackend)
+	b := NewMutatingBackend(delegate, StaticAnnotationsMutator(map[string]string{"cluster": "test"}))
+
+	if !b.(RoutingSink).ProcessEventsForBackends([]string{"webhook"}, &auditinternal.Event{AuditID: "0"}) {
+		t.Fatal("ProcessEventsForBackends failed")
+	}
+	if len(delegate.events) != 1 || delegate.events[0].Annotations["cluster"] != "test" {
+		t.Errorf("expected a mutated event for the delegate, got %v", delegate.events)
+	}
+}
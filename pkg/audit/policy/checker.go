@@ -17,11 +17,28 @@ limitations under the License.
 package policy
 
 import (
+	"math/rand"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/apis/audit"
 	auditinternal "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/audit/policy/matchconditions"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
 )
 
 const (
@@ -29,18 +46,124 @@ const (
 	DefaultAuditLevel = audit.LevelNone
 )
 
+// PolicyDecisionObserver is notified of every decision of a policy rule evaluator.
+type PolicyDecisionObserver interface {
+	// ObservePolicyDecision is called with the index of the matched rule, or -1
+	// if no rule matched, the evaluated request attributes and the resulting level.
+	ObservePolicyDecision(ruleIndex int, attrs authorizer.Attributes, level audit.Level)
+}
+
+// PolicyDecisionObserverFunc is a function that implements PolicyDecisionObserver.
+type PolicyDecisionObserverFunc func(ruleIndex int, attrs authorizer.Attributes, level audit.Level)
+
+// ObservePolicyDecision calls f(ruleIndex, attrs, level).
+func (f PolicyDecisionObserverFunc) ObservePolicyDecision(ruleIndex int, attrs authorizer.Attributes, level audit.Level) {
+	f(ruleIndex, attrs, level)
+}
+
+// WantsNamespaceLister is implemented by policy rule evaluators that evaluate
+// namespace selectors and therefore need to look up namespaces.
+type WantsNamespaceLister interface {
+	// NeedsNamespaceLister returns whether the policy has rules with namespace selectors.
+	NeedsNamespaceLister() bool
+	// SetNamespaceLister sets the lister used to look up the labels of namespaces.
+	SetNamespaceLister(corev1listers.NamespaceLister)
+}
+
 // NewPolicyRuleEvaluator creates a new policy rule evaluator.
 func NewPolicyRuleEvaluator(policy *audit.Policy) auditinternal.PolicyRuleEvaluator {
+	return NewPolicyRuleEvaluatorWithObserver(policy, nil)
+}
+
+// NewPolicyRuleEvaluatorWithObserver creates a new policy rule evaluator that
+// reports every decision to the given observer. A nil observer is ignored.
+func NewPolicyRuleEvaluatorWithObserver(policy *audit.Policy, observer PolicyDecisionObserver) auditinternal.PolicyRuleEvaluator {
+	e := newPolicyRuleEvaluator(policy, matchconditions.CostLimits{})
+	e.observer = observer
+	return e
+}
+
+// NewServerPolicyRuleEvaluator creates a policy rule evaluator for the policy
+// the server audits requests with, whose decisions are recorded in the rule
+// match metric. The metric reflects a single policy, so there must not be more
+// than one such evaluator per process.
+func NewServerPolicyRuleEvaluator(policy *audit.Policy) auditinternal.PolicyRuleEvaluator {
+	e := newPolicyRuleEvaluator(policy, matchconditions.CostLimits{})
+	e.metrics = newRuleMetrics(policy.Rules)
+	return e
+}
+
+// newPolicyRuleEvaluator creates a policy rule evaluator that compiles match
+// conditions with the given cost limits. It records no metrics.
+func newPolicyRuleEvaluator(policy *audit.Policy, costLimits matchconditions.CostLimits) *policyRuleEvaluator {
+	matchers := make([]*matchconditions.Matcher, len(policy.Rules))
+	selectors := make([]labels.Selector, len(policy.Rules))
+	namespaceSelectors := make([]labels.Selector, len(policy.Rules))
+	sourceIPNets := make([][]*net.IPNet, len(policy.Rules))
+	samplingCounters := make([]uint64, len(policy.Rules))
 	for i, rule := range policy.Rules {
 		policy.Rules[i].OmitStages = unionStages(policy.OmitStages, rule.OmitStages)
+		if len(rule.MatchConditions) > 0 {
+			m, err := matchconditions.CompileWithCostLimits(rule.MatchConditions, costLimits)
+			if err != nil {
+				// The policy is validated on load, so this only happens for
+				// policies constructed programmatically. Such a rule never matches.
+				klog.ErrorS(err, "Failed to compile audit policy rule match conditions", "rule", i)
+			}
+			matchers[i] = m
+		}
+		if rule.ObjectSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(rule.ObjectSelector)
+			if err != nil {
+				// Like above, a rule with an invalid selector never matches.
+				klog.ErrorS(err, "Failed to parse audit policy rule object selector", "rule", i)
+				selector = labels.Nothing()
+			}
+			selectors[i] = selector
+		}
+		if rule.NamespaceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(rule.NamespaceSelector)
+			if err != nil {
+				klog.ErrorS(err, "Failed to parse audit policy rule namespace selector", "rule", i)
+				selector = labels.Nothing()
+			}
+			namespaceSelectors[i] = selector
+		}
+		for _, cidr := range rule.SourceIPs {
+			_, ipNet, err := netutils.ParseCIDRSloppy(cidr)
+			if err != nil {
+				// Like above, an invalid CIDR never matches.
+				klog.ErrorS(err, "Failed to parse audit policy rule source IP CIDR", "rule", i, "cidr", cidr)
+				continue
+			}
+			sourceIPNets[i] = append(sourceIPNets[i], ipNet)
+		}
+	}
+	return &policyRuleEvaluator{
+		Policy:             *policy,
+		index:              newPolicyIndex(policy.Rules),
+		matchers:           matchers,
+		selectors:          selectors,
+		namespaceSelectors: namespaceSelectors,
+		sourceIPNets:       sourceIPNets,
+		samplingCounters:   samplingCounters,
+		randIntn:           rand.Intn,
+		now:                time.Now,
 	}
-	return &policyRuleEvaluator{*policy}
 }
 
+// unionStages returns the known stages of the given lists without duplicates,
+// in the order in which they occur during request handling.
 func unionStages(stageLists ...[]audit.Stage) []audit.Stage {
 	m := make(map[audit.Stage]bool)
 	for _, sl := range stageLists {
 		for _, s := range sl {
+			if !s.IsKnown() {
+				// The policy is validated on load, so this only happens for
+				// policies constructed programmatically.
+				klog.InfoS("Ignoring unknown audit stage in policy", "stage", s)
+				continue
+			}
 			m[s] = true
 		}
 	}
@@ -48,6 +171,7 @@ func unionStages(stageLists ...[]audit.Stage) []audit.Stage {
 	for key := range m {
 		result = append(result, key)
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Less(result[j]) })
 	return result
 }
 
@@ -59,28 +183,438 @@ func NewFakePolicyRuleEvaluator(level audit.Level, stage []audit.Stage) auditint
 
 type policyRuleEvaluator struct {
 	audit.Policy
+
+	// index narrows down the rules to evaluate for a request.
+	index *policyIndex
+
+	// matchers holds the compiled match conditions, indexed like Policy.Rules.
+	matchers []*matchconditions.Matcher
+
+	// selectors holds the parsed object selectors, indexed like Policy.Rules.
+	selectors []labels.Selector
+
+	// namespaceSelectors holds the parsed namespace selectors, indexed like Policy.Rules.
+	namespaceSelectors []labels.Selector
+
+	// sourceIPNets holds the parsed source IP CIDRs, indexed like Policy.Rules.
+	sourceIPNets [][]*net.IPNet
+
+	// namespaceLister looks up the labels of namespaces for namespace selectors.
+	namespaceLister corev1listers.NamespaceLister
+
+	// samplingCounters counts the matched requests of rules with EveryNth
+	// sampling, indexed like Policy.Rules. Accessed atomically.
+	samplingCounters []uint64
+
+	// randIntn is the source of randomness for Random sampling.
+	randIntn func(n int) int
+
+	// now is the source of the current time for active windows.
+	now func() time.Time
+
+	// observer, if set, is notified of every decision.
+	observer PolicyDecisionObserver
+
+	// metrics, if set, records every decision in the rule match metric.
+	metrics *ruleMetrics
 }
 
+var _ auditinternal.ObjectPolicyRuleEvaluator = &policyRuleEvaluator{}
+var _ WantsNamespaceLister = &policyRuleEvaluator{}
+
 func (p *policyRuleEvaluator) EvaluatePolicyRule(attrs authorizer.Attributes) auditinternal.RequestAuditConfigWithLevel {
-	for _, rule := range p.Rules {
-		if ruleMatches(&rule, attrs) {
-			return auditinternal.RequestAuditConfigWithLevel{
-				Level: rule.Level,
-				RequestAuditConfig: auditinternal.RequestAuditConfig{
-					OmitStages:        rule.OmitStages,
-					OmitManagedFields: isOmitManagedFields(&rule, p.OmitManagedFields),
+	return p.evaluate(attrs, nil, response{})
+}
+
+func (p *policyRuleEvaluator) EvaluatePolicyRuleForObject(attrs authorizer.Attributes, obj runtime.Object) auditinternal.RequestAuditConfigWithLevel {
+	return p.evaluate(attrs, obj, response{})
+}
+
+// response holds what is known about the response of a request. The zero value
+// means the response is not known yet.
+type response struct {
+	// code is the response code.
+	code int32
+	// annotations are the audit annotations of the request.
+	annotations map[string]string
+}
+
+func (r response) known() bool {
+	return r.code != 0
+}
+
+// evaluate evaluates the policy for the given attributes, request object and
+// response. A nil obj means the request object is not known (yet). Rules that
+// may match once they are known make the result provisional. Once the response
+// is known, the request object is not expected anymore.
+func (p *policyRuleEvaluator) evaluate(attrs authorizer.Attributes, obj runtime.Object, resp response) auditinternal.RequestAuditConfigWithLevel {
+	matched, pending, pendingObject := p.findRule(attrs, obj, resp)
+
+	if pending >= 0 {
+		// Until the object and response are known, audit the request as if it
+		// matched the candidate rule with the highest level. Sampling and
+		// observers are left to the final decision.
+		provisional := pending
+		if matched >= 0 && p.Rules[pending].Level.Less(p.Rules[matched].Level) {
+			provisional = matched
+		}
+		rule := &p.Rules[provisional]
+		return auditinternal.RequestAuditConfigWithLevel{
+			Level: p.enforceMinimumLevel(attrs, rule.Level),
+			RequestAuditConfig: auditinternal.RequestAuditConfig{
+				OmitStages:        rule.OmitStages,
+				OmitManagedFields: isOmitManagedFields(rule, p.OmitManagedFields),
+				Backends:          rule.Backends,
+				RedactFields:      rule.RedactFields,
+				ObjectDiff:        rule.ObjectDiff,
+				EvaluateResponse: func(responseCode int32, annotations map[string]string) auditinternal.RequestAuditConfigWithLevel {
+					return p.evaluate(attrs, obj, response{code: responseCode, annotations: annotations})
 				},
+			},
+			DependsOnObject: pendingObject,
+			MatchedRule:     &auditinternal.PolicyRuleRef{Index: provisional, Name: rule.Name},
+		}
+	}
+
+	result := p.auditConfig(matched)
+	result.Level = p.enforceMinimumLevel(attrs, result.Level)
+	if p.metrics != nil {
+		p.metrics.observe(matched, result.Level)
+	}
+	if p.observer != nil {
+		p.observer.ObservePolicyDecision(matched, attrs, result.Level)
+	}
+	return result
+}
+
+// readVerbs are the verbs of the requests Policy.MinimumLevels considers reads.
+var readVerbs = []string{"get", "list", "watch", "head"}
+
+// enforceMinimumLevel raises the level to the minimum level of the request, if
+// the policy has minimum levels.
+func (p *policyRuleEvaluator) enforceMinimumLevel(attrs authorizer.Attributes, level audit.Level) audit.Level {
+	if p.MinimumLevels == nil {
+		return level
+	}
+	minimum := p.MinimumLevels.Write
+	if hasString(readVerbs, attrs.GetVerb()) {
+		minimum = p.MinimumLevels.Read
+	}
+	if minimum != "" && level.Less(minimum) {
+		return minimum
+	}
+	return level
+}
+
+// findRule returns the index of the first rule matching the given attributes,
+// request object and response, or -1 if no rule matches. Rules that may match
+// once the request object or response are known are pending; pending is the
+// index of the pending rule with the highest level, or -1.
+func (p *policyRuleEvaluator) findRule(attrs authorizer.Attributes, obj runtime.Object, resp response) (matched, pending int, pendingObject bool) {
+	matched, pending = -1, -1
+	match := func(i int) bool {
+		if !ruleMatches(&p.Rules[i], attrs) || !p.sourceIPMatches(i, attrs) || !userAgentMatches(&p.Rules[i], attrs) || !p.activeWindowMatches(i) || !requestKindMatches(&p.Rules[i], attrs) || !p.namespaceSelectorMatches(i, attrs) || !p.matchConditionsMatch(i, attrs) {
+			return false
+		}
+		objectResult := p.objectSelectorMatches(i, attrs, obj, resp.known())
+		responseResult := responseMatches(&p.Rules[i], resp)
+		if objectResult == resultNoMatch || responseResult == resultNoMatch {
+			return false
+		}
+		if objectResult == resultPending || responseResult == resultPending {
+			pendingObject = pendingObject || objectResult == resultPending
+			if pending < 0 || p.Rules[pending].Level.Less(p.Rules[i].Level) {
+				pending = i
 			}
+			return false
 		}
+		matched = i
+		return true
 	}
+	if p.index != nil {
+		p.index.forEachCandidate(attrs, match)
+	} else {
+		// Evaluators constructed without NewPolicyRuleEvaluator have no index.
+		for i := range p.Rules {
+			if match(i) {
+				break
+			}
+		}
+	}
+	return matched, pending, pendingObject
+}
 
+// auditConfig returns the audit config of the i-th rule, or the policy
+// defaults if i is negative.
+func (p *policyRuleEvaluator) auditConfig(i int) auditinternal.RequestAuditConfigWithLevel {
+	if i < 0 {
+		return auditinternal.RequestAuditConfigWithLevel{
+			Level: DefaultAuditLevel,
+			RequestAuditConfig: auditinternal.RequestAuditConfig{
+				OmitStages:        p.OmitStages,
+				OmitManagedFields: p.OmitManagedFields,
+			},
+		}
+	}
+
+	rule := &p.Rules[i]
+	level := rule.Level
+	if !p.sampled(i) {
+		level = audit.LevelNone
+	}
 	return auditinternal.RequestAuditConfigWithLevel{
-		Level: DefaultAuditLevel,
+		Level: level,
 		RequestAuditConfig: auditinternal.RequestAuditConfig{
-			OmitStages:        p.OmitStages,
-			OmitManagedFields: p.OmitManagedFields,
+			OmitStages:        rule.OmitStages,
+			OmitManagedFields: isOmitManagedFields(rule, p.OmitManagedFields),
+			Backends:          rule.Backends,
+			RedactFields:      rule.RedactFields,
+			ObjectDiff:        rule.ObjectDiff,
 		},
+		MatchedRule: &auditinternal.PolicyRuleRef{Index: i, Name: rule.Name},
+	}
+}
+
+// matchConditionsMatch returns whether the match conditions of the i-th rule
+// are fulfilled. Rules with conditions that failed to compile or evaluate
+// do not match.
+func (p *policyRuleEvaluator) matchConditionsMatch(i int, attrs authorizer.Attributes) bool {
+	if len(p.Rules[i].MatchConditions) == 0 {
+		return true
+	}
+	if p.matchers[i] == nil {
+		return false
+	}
+	matched, err := p.matchers[i].Match(attrs)
+	if err != nil {
+		klog.ErrorS(err, "Failed to evaluate audit policy rule match conditions", "rule", i)
+		return false
+	}
+	return matched
+}
+
+// NeedsNamespaceLister returns whether the policy has rules with namespace selectors.
+func (p *policyRuleEvaluator) NeedsNamespaceLister() bool {
+	for i := range p.Rules {
+		if p.Rules[i].NamespaceSelector != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNamespaceLister sets the lister used to evaluate namespace selectors.
+// It must be called before the evaluator is used.
+func (p *policyRuleEvaluator) SetNamespaceLister(lister corev1listers.NamespaceLister) {
+	p.namespaceLister = lister
+}
+
+// namespaceSelectorMatches returns whether the namespace selector of the i-th
+// rule matches the labels of the namespace of the request. Rules with namespace
+// selectors never match if the namespace cannot be found.
+func (p *policyRuleEvaluator) namespaceSelectorMatches(i int, attrs authorizer.Attributes) bool {
+	if p.Rules[i].NamespaceSelector == nil {
+		return true
+	}
+	namespace := attrs.GetNamespace()
+	if namespace == "" || p.namespaceLister == nil || p.namespaceSelectors == nil || p.namespaceSelectors[i] == nil {
+		return false
+	}
+	ns, err := p.namespaceLister.Get(namespace)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get namespace for audit policy rule namespace selector", "rule", i, "namespace", namespace)
+		}
+		return false
+	}
+	return p.namespaceSelectors[i].Matches(labels.Set(ns.Labels))
+}
+
+// sourceIPMatches returns whether the source IP of the request is in one of the
+// source IP CIDRs of the i-th rule. Requests with an unknown source IP only
+// match rules without source IPs.
+func (p *policyRuleEvaluator) sourceIPMatches(i int, attrs authorizer.Attributes) bool {
+	if len(p.Rules[i].SourceIPs) == 0 {
+		return true
+	}
+	requestAttrs, ok := attrs.(*auditinternal.RequestAttributes)
+	if !ok || requestAttrs.SourceIP == nil || p.sourceIPNets == nil {
+		return false
+	}
+	for _, ipNet := range p.sourceIPNets[i] {
+		if ipNet.Contains(requestAttrs.SourceIP) {
+			return true
+		}
+	}
+	return false
+}
+
+// userAgentMatches returns whether the User-Agent of the request matches one of
+// the user agent patterns of the rule. Requests with an unknown User-Agent only
+// match rules without user agents.
+func userAgentMatches(r *audit.PolicyRule, attrs authorizer.Attributes) bool {
+	if len(r.UserAgents) == 0 {
+		return true
+	}
+	requestAttrs, ok := attrs.(*auditinternal.RequestAttributes)
+	if !ok {
+		return false
+	}
+	for _, pattern := range r.UserAgents {
+		if globMatches(pattern, requestAttrs.UserAgent) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestKindMatches returns whether the request matches the dry-run and
+// server-side apply restrictions of the rule. Requests with unknown request
+// attributes are treated as neither dry-run nor server-side apply requests.
+func requestKindMatches(r *audit.PolicyRule, attrs authorizer.Attributes) bool {
+	if r.DryRun == nil && r.ServerSideApply == nil {
+		return true
+	}
+	var dryRun, serverSideApply bool
+	if requestAttrs, ok := attrs.(*auditinternal.RequestAttributes); ok {
+		dryRun, serverSideApply = requestAttrs.DryRun, requestAttrs.ServerSideApply
+	}
+	if r.DryRun != nil && *r.DryRun != dryRun {
+		return false
+	}
+	if r.ServerSideApply != nil && *r.ServerSideApply != serverSideApply {
+		return false
+	}
+	return true
+}
+
+// activeWindowMatches returns whether the current time is within the active
+// window of the i-th rule.
+func (p *policyRuleEvaluator) activeWindowMatches(i int) bool {
+	window := p.Rules[i].ActiveWindow
+	if window == nil {
+		return true
+	}
+	now := time.Now()
+	if p.now != nil {
+		now = p.now()
 	}
+	if window.Start != nil && now.Before(window.Start.Time) {
+		return false
+	}
+	if window.End != nil && !now.Before(window.End.Time) {
+		return false
+	}
+	return true
+}
+
+// globMatches returns whether s matches the pattern, in which "*" matches any
+// sequence of characters.
+func globMatches(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, last)
+}
+
+// matchResult is the result of matching a rule against request data that
+// may not be known yet.
+type matchResult int
+
+const (
+	resultMatch matchResult = iota
+	resultNoMatch
+	resultPending
+)
+
+// objectSelectorMatches returns whether the object selector of the i-th rule
+// matches the request object. The result is pending if the object is not known
+// yet but may be provided later, i.e. before the response is complete.
+func (p *policyRuleEvaluator) objectSelectorMatches(i int, attrs authorizer.Attributes, obj runtime.Object, responseComplete bool) matchResult {
+	if p.Rules[i].ObjectSelector == nil {
+		return resultMatch
+	}
+	if obj == nil {
+		if !responseComplete && hasString(objectVerbs, attrs.GetVerb()) {
+			return resultPending
+		}
+		return resultNoMatch
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil || p.selectors == nil || p.selectors[i] == nil {
+		return resultNoMatch
+	}
+	if p.selectors[i].Matches(labels.Set(accessor.GetLabels())) {
+		return resultMatch
+	}
+	return resultNoMatch
+}
+
+// responseMatches returns whether the response matches the response codes and
+// audit annotations of the rule. The result is pending if the response is not
+// known yet.
+func responseMatches(r *audit.PolicyRule, resp response) matchResult {
+	if len(r.ResponseCodes) == 0 && len(r.AuditAnnotations) == 0 {
+		return resultMatch
+	}
+	if !resp.known() {
+		return resultPending
+	}
+	for key, value := range r.AuditAnnotations {
+		if v, ok := resp.annotations[key]; !ok || v != value {
+			return resultNoMatch
+		}
+	}
+	return responseCodeMatches(r, resp.code)
+}
+
+// responseCodeMatches returns whether the response code matches the response
+// codes of the rule.
+func responseCodeMatches(r *audit.PolicyRule, responseCode int32) matchResult {
+	if len(r.ResponseCodes) == 0 {
+		return resultMatch
+	}
+	code := strconv.Itoa(int(responseCode))
+	for _, c := range r.ResponseCodes {
+		if c == code {
+			return resultMatch
+		}
+		// match "5xx"
+		if strings.HasSuffix(c, "xx") && len(code) == len(c) && code[0] == c[0] {
+			return resultMatch
+		}
+	}
+	return resultNoMatch
+}
+
+// objectVerbs are the verbs of requests that carry a request object.
+var objectVerbs = []string{"create", "update"}
+
+// sampled returns whether a request matching the i-th rule is selected by the
+// rule's sampling configuration.
+func (p *policyRuleEvaluator) sampled(i int) bool {
+	rule := &p.Rules[i]
+	if rule.SamplingRate == nil || *rule.SamplingRate <= 1 {
+		return true
+	}
+	rate := int(*rule.SamplingRate)
+	if rule.SamplingMode == audit.SamplingModeEveryNth {
+		return atomic.AddUint64(&p.samplingCounters[i], 1)%uint64(rate) == 0
+	}
+	return p.randIntn(rate) == 0
 }
 
 // isOmitManagedFields returns whether to omit managed fields from the request
@@ -99,7 +633,7 @@ func isOmitManagedFields(policyRule *audit.PolicyRule, policyDefault bool) bool
 func ruleMatches(r *audit.PolicyRule, attrs authorizer.Attributes) bool {
 	user := attrs.GetUser()
 	if len(r.Users) > 0 {
-		if user == nil || !hasString(r.Users, user.GetName()) {
+		if user == nil || !hasMatchingString(r.Users, user.GetName()) {
 			return false
 		}
 	}
@@ -109,7 +643,7 @@ func ruleMatches(r *audit.PolicyRule, attrs authorizer.Attributes) bool {
 		}
 		matched := false
 		for _, group := range user.GetGroups() {
-			if hasString(r.UserGroups, group) {
+			if hasMatchingString(r.UserGroups, group) {
 				matched = true
 				break
 			}
@@ -123,8 +657,11 @@ func ruleMatches(r *audit.PolicyRule, attrs authorizer.Attributes) bool {
 			return false
 		}
 	}
+	if ruleExcludes(r, attrs) {
+		return false
+	}
 
-	if len(r.Namespaces) > 0 || len(r.Resources) > 0 {
+	if len(r.Namespaces) > 0 || len(r.Resources) > 0 || r.ObjectSelector != nil || r.NamespaceSelector != nil || hasResourceScope(r) {
 		return ruleMatchesResource(r, attrs)
 	}
 
@@ -135,6 +672,30 @@ func ruleMatches(r *audit.PolicyRule, attrs authorizer.Attributes) bool {
 	return true
 }
 
+// Check whether the rule's exception fields exclude the request attrs.
+func ruleExcludes(r *audit.PolicyRule, attrs authorizer.Attributes) bool {
+	if user := attrs.GetUser(); user != nil {
+		if len(r.ExceptUsers) > 0 && hasMatchingString(r.ExceptUsers, user.GetName()) {
+			return true
+		}
+		if len(r.ExceptUserGroups) > 0 {
+			for _, group := range user.GetGroups() {
+				if hasMatchingString(r.ExceptUserGroups, group) {
+					return true
+				}
+			}
+		}
+	}
+	if len(r.ExceptVerbs) > 0 && hasString(r.ExceptVerbs, attrs.GetVerb()) {
+		return true
+	}
+	// Non-resource requests are not in any namespace, so they are never excluded by namespace.
+	if len(r.ExceptNamespaces) > 0 && attrs.IsResourceRequest() && hasString(r.ExceptNamespaces, attrs.GetNamespace()) {
+		return true
+	}
+	return false
+}
+
 // Check whether the rule's non-resource URLs match the request attrs.
 func ruleMatchesNonResource(r *audit.PolicyRule, attrs authorizer.Attributes) bool {
 	if attrs.IsResourceRequest() {
@@ -161,6 +722,15 @@ func pathMatches(path, spec string) bool {
 	if spec == path {
 		return true
 	}
+	// Allow a regular expression match
+	if strings.HasPrefix(spec, "~") {
+		re, err := pathRegexp(spec[1:])
+		if err != nil {
+			klog.ErrorS(err, "Invalid regular expression in audit policy rule non-resource URLs", "url", spec)
+			return false
+		}
+		return re.MatchString(path)
+	}
 	// Allow a trailing * subpath match
 	if strings.HasSuffix(spec, "*") && strings.HasPrefix(path, strings.TrimRight(spec, "*")) {
 		return true
@@ -168,12 +738,43 @@ func pathMatches(path, spec string) bool {
 	return false
 }
 
+// pathRegexps caches the compiled regular expressions of non-resource URLs.
+var pathRegexps sync.Map
+
+// pathRegexp returns the compiled regular expression, anchored to match whole paths.
+func pathRegexp(expr string) (*regexp.Regexp, error) {
+	if re, ok := pathRegexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, err
+	}
+	pathRegexps.Store(expr, re)
+	return re, nil
+}
+
+// hasResourceScope returns whether the rule is restricted to a scope of resources.
+func hasResourceScope(r *audit.PolicyRule) bool {
+	return r.Scope == audit.ScopeCluster || r.Scope == audit.ScopeNamespaced
+}
+
 // Check whether the rule's resource fields match the request attrs.
 func ruleMatchesResource(r *audit.PolicyRule, attrs authorizer.Attributes) bool {
 	if !attrs.IsResourceRequest() {
 		return false
 	}
 
+	switch r.Scope {
+	case audit.ScopeCluster:
+		if attrs.GetNamespace() != "" {
+			return false
+		}
+	case audit.ScopeNamespaced:
+		if attrs.GetNamespace() == "" {
+			return false
+		}
+	}
 	if len(r.Namespaces) > 0 {
 		if !hasString(r.Namespaces, attrs.GetNamespace()) { // Non-namespaced resources use the empty string.
 			return false
@@ -195,7 +796,7 @@ func ruleMatchesResource(r *audit.PolicyRule, attrs authorizer.Attributes) bool
 	name := attrs.GetName()
 
 	for _, gr := range r.Resources {
-		if gr.Group == apiGroup {
+		if gr.Group == apiGroup || gr.Group == "*" {
 			if len(gr.Resources) == 0 {
 				return true
 			}
@@ -230,6 +831,20 @@ func hasString(slice []string, value string) bool {
 	return false
 }
 
+// Utility function to check whether a string slice contains a pattern matching a string.
+// A pattern with a trailing * matches every string with the preceding prefix.
+func hasMatchingString(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if p == value {
+			return true
+		}
+		if strings.HasSuffix(p, "*") && strings.HasPrefix(value, strings.TrimSuffix(p, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
 type fakePolicyRuleEvaluator struct {
 	level audit.Level
 	stage []audit.Stage
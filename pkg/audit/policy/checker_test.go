@@ -19,13 +19,22 @@ package policy
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apiserver/pkg/apis/audit"
+	auditinternal "k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	netutils "k8s.io/utils/net"
 )
 
 var (
@@ -73,6 +82,18 @@ var (
 			ResourceRequest: true,
 			Path:            "/api/v1/namespaces/default/pods/busybox",
 		},
+		"status": &authorizer.AttributesRecord{
+			User:            tim,
+			Verb:            "update",
+			Namespace:       "default",
+			APIGroup:        "apps",
+			APIVersion:      "v1",
+			Resource:        "deployments",
+			Subresource:     "status",
+			Name:            "web",
+			ResourceRequest: true,
+			Path:            "/apis/apps/v1/namespaces/default/deployments/web/status",
+		},
 		"Unauthorized": &authorizer.AttributesRecord{
 			Verb:            "get",
 			Namespace:       "default",
@@ -101,6 +122,47 @@ var (
 			Level:      audit.LevelMetadata,
 			UserGroups: []string{"humans"},
 		},
+		"timsPrefix": {
+			Level: audit.LevelMetadata,
+			Users: []string{"tim@*"},
+		},
+		"allUsers": {
+			Level: audit.LevelMetadata,
+			Users: []string{"*"},
+		},
+		"humansPrefix": {
+			Level:      audit.LevelMetadata,
+			UserGroups: []string{"hum*"},
+		},
+		"serviceAccountsPrefix": {
+			Level:      audit.LevelRequest,
+			UserGroups: []string{"system:serviceaccounts:*"},
+		},
+		"exceptTims": {
+			Level:       audit.LevelRequestResponse,
+			ExceptUsers: []string{"tim@*"},
+		},
+		"exceptHumans": {
+			Level:            audit.LevelRequestResponse,
+			ExceptUserGroups: []string{"humans"},
+		},
+		"exceptGet": {
+			Level:       audit.LevelRequestResponse,
+			ExceptVerbs: []string{"get", "list", "watch"},
+		},
+		"exceptDefaultNamespace": {
+			Level:            audit.LevelRequestResponse,
+			ExceptNamespaces: []string{"default"},
+		},
+		"exceptClusterScoped": {
+			Level:            audit.LevelRequestResponse,
+			ExceptNamespaces: []string{""},
+		},
+		"logsExceptDefaultNamespace": {
+			Level:            audit.LevelRequestResponse,
+			ExceptNamespaces: []string{"default"},
+			NonResourceURLs:  []string{"/logs*"},
+		},
 		"serviceAccounts": {
 			Level:      audit.LevelRequest,
 			UserGroups: []string{"system:serviceaccounts"},
@@ -161,6 +223,34 @@ var (
 				ResourceNames: []string{"edit"},
 			}},
 		},
+		"statusWrites": {
+			Level: audit.LevelRequest,
+			Verbs: []string{"update", "patch"},
+			Resources: []audit.GroupResources{{
+				Group:     "*",
+				Resources: []string{"*/status"},
+			}},
+		},
+		"webStatus": {
+			Level: audit.LevelRequestResponse,
+			Resources: []audit.GroupResources{{
+				Group:         "*",
+				Resources:     []string{"*/status"},
+				ResourceNames: []string{"web"},
+			}},
+		},
+		"clusterScope": {
+			Level: audit.LevelRequestResponse,
+			Scope: audit.ScopeCluster,
+		},
+		"namespacedScope": {
+			Level: audit.LevelRequest,
+			Scope: audit.ScopeNamespaced,
+		},
+		"anyScope": {
+			Level: audit.LevelRequest,
+			Scope: audit.ScopeAll,
+		},
 		"omit RequestReceived": {
 			Level: audit.LevelRequest,
 			OmitStages: []audit.Stage{
@@ -237,10 +327,48 @@ func testAuditLevel(t *testing.T, stages []audit.Stage) {
 	test(t, "subresource", audit.LevelRequest, stages, stages, "getPodResourceWildcardMatching")
 	test(t, "subresource", audit.LevelRequest, stages, stages, "getPodSubResourceWildcardMatching")
 
+	test(t, "status", audit.LevelRequest, stages, stages, "statusWrites", "default")
+	test(t, "status", audit.LevelRequestResponse, stages, stages, "webStatus", "statusWrites")
+	test(t, "status", audit.LevelNone, stages, stages, "getPodResourceWildcardMatching", "getClusterRoles")
+	test(t, "subresource", audit.LevelNone, stages, stages, "statusWrites", "webStatus")
+	test(t, "namespaced", audit.LevelNone, stages, stages, "statusWrites", "webStatus")
+	test(t, "cluster", audit.LevelNone, stages, stages, "statusWrites", "webStatus")
+
 	test(t, "Unauthorized", audit.LevelNone, stages, stages, "tims")
 	test(t, "Unauthorized", audit.LevelMetadata, stages, stages, "tims", "default")
 	test(t, "Unauthorized", audit.LevelNone, stages, stages, "humans")
 	test(t, "Unauthorized", audit.LevelMetadata, stages, stages, "humans", "default")
+
+	test(t, "namespaced", audit.LevelMetadata, stages, stages, "timsPrefix")
+	test(t, "namespaced", audit.LevelMetadata, stages, stages, "allUsers")
+	test(t, "namespaced", audit.LevelMetadata, stages, stages, "humansPrefix")
+	test(t, "namespaced", audit.LevelNone, stages, stages, "serviceAccountsPrefix")
+	test(t, "Unauthorized", audit.LevelNone, stages, stages, "timsPrefix")
+	test(t, "Unauthorized", audit.LevelNone, stages, stages, "allUsers")
+	test(t, "Unauthorized", audit.LevelNone, stages, stages, "humansPrefix")
+
+	test(t, "namespaced", audit.LevelMetadata, stages, stages, "exceptTims", "default")
+	test(t, "namespaced", audit.LevelMetadata, stages, stages, "exceptHumans", "default")
+	test(t, "namespaced", audit.LevelMetadata, stages, stages, "exceptGet", "default")
+	test(t, "namespaced", audit.LevelMetadata, stages, stages, "exceptDefaultNamespace", "default")
+	test(t, "namespaced", audit.LevelRequestResponse, stages, stages, "exceptClusterScoped", "default")
+
+	test(t, "namespaced", audit.LevelMetadata, stages, stages, "clusterScope", "default")
+	test(t, "namespaced", audit.LevelRequest, stages, stages, "namespacedScope", "default")
+	test(t, "namespaced", audit.LevelRequest, stages, stages, "anyScope", "default")
+	test(t, "cluster", audit.LevelRequestResponse, stages, stages, "clusterScope", "default")
+	test(t, "cluster", audit.LevelMetadata, stages, stages, "namespacedScope", "default")
+	test(t, "cluster", audit.LevelRequest, stages, stages, "anyScope", "default")
+	test(t, "nonResource", audit.LevelMetadata, stages, stages, "clusterScope", "namespacedScope", "default")
+	test(t, "nonResource", audit.LevelRequest, stages, stages, "anyScope", "default")
+	test(t, "cluster", audit.LevelRequestResponse, stages, stages, "exceptDefaultNamespace", "default")
+	test(t, "cluster", audit.LevelMetadata, stages, stages, "exceptClusterScoped", "default")
+	test(t, "nonResource", audit.LevelRequestResponse, stages, stages, "exceptDefaultNamespace", "default")
+	test(t, "nonResource", audit.LevelRequestResponse, stages, stages, "exceptClusterScoped", "default")
+	test(t, "nonResource", audit.LevelRequestResponse, stages, stages, "logsExceptDefaultNamespace", "default")
+	test(t, "namespaced", audit.LevelMetadata, stages, stages, "logsExceptDefaultNamespace", "default")
+	test(t, "Unauthorized", audit.LevelRequestResponse, stages, stages, "exceptTims", "default")
+	test(t, "Unauthorized", audit.LevelRequestResponse, stages, stages, "exceptHumans", "default")
 }
 
 func TestChecker(t *testing.T) {
@@ -328,21 +456,21 @@ func TestUnionStages(t *testing.T) {
 			[]audit.Stage{audit.StageRequestReceived},
 			[]audit.Stage{audit.StageRequestReceived},
 		},
+		{
+			[]audit.Stage{audit.StagePanic, audit.StageResponseComplete},
+			[]audit.Stage{audit.StageResponseStarted, audit.StageRequestReceived},
+			[]audit.Stage{audit.StageRequestReceived, audit.StageResponseStarted, audit.StageResponseComplete, audit.StagePanic},
+		},
+		{
+			[]audit.Stage{"RequestRecieved", audit.StageResponseStarted},
+			nil,
+			[]audit.Stage{audit.StageResponseStarted},
+		},
 	}
 
 	for _, tc := range testCases {
 		result := unionStages(tc.s1, tc.s2)
-		assert.Len(t, result, len(tc.exp))
-		for _, expStage := range tc.exp {
-			ok := false
-			for _, resultStage := range result {
-				if resultStage == expStage {
-					ok = true
-					break
-				}
-			}
-			assert.True(t, ok)
-		}
+		assert.Equal(t, tc.exp, result)
 	}
 }
 
@@ -430,3 +558,585 @@ func TestOmitManagedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchConditions(t *testing.T) {
+	updateKubeRule := audit.PolicyRule{
+		Level: audit.LevelRequestResponse,
+		MatchConditions: []audit.MatchCondition{
+			{Name: "update", Expression: "request.verb == 'update'"},
+			{Name: "kube-prefixed", Expression: "request.name.startsWith('kube-')"},
+		},
+	}
+	humansRule := audit.PolicyRule{
+		Level: audit.LevelRequest,
+		MatchConditions: []audit.MatchCondition{
+			{Name: "humans", Expression: "'humans' in request.user.groups"},
+		},
+	}
+	brokenRule := audit.PolicyRule{
+		Level: audit.LevelRequest,
+		MatchConditions: []audit.MatchCondition{
+			{Name: "broken", Expression: "request.verb =="},
+		},
+	}
+	missingKeyRule := audit.PolicyRule{
+		Level: audit.LevelRequest,
+		MatchConditions: []audit.MatchCondition{
+			{Name: "missing", Expression: "request.doesNotExist == 'foo'"},
+		},
+	}
+	ownPodsRule := audit.PolicyRule{
+		Level: audit.LevelNone,
+		MatchConditions: []audit.MatchCondition{
+			{Name: "own-pods", Expression: "request.fieldSelector.exists(r, r.key == 'spec.nodeName' && 'system:node:' + r.values[0] == request.user.username)"},
+			{Name: "no-label-selector", Expression: "size(request.labelSelector) == 0"},
+		},
+	}
+	defaultRule := audit.PolicyRule{Level: audit.LevelMetadata}
+
+	nodeListAttrs := func(node, labelSelector string) authorizer.Attributes {
+		attr := &authorizer.AttributesRecord{
+			User:                      &user.DefaultInfo{Name: "system:node:node1"},
+			Verb:                      "list",
+			Resource:                  "pods",
+			ResourceRequest:           true,
+			FieldSelectorRequirements: fields.OneTermEqualSelector("spec.nodeName", node).Requirements(),
+		}
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attr.LabelSelectorRequirements, _ = selector.Requirements()
+		return attr
+	}
+
+	updateAttrs := func(name string, u user.Info) authorizer.Attributes {
+		return &authorizer.AttributesRecord{
+			User:            u,
+			Verb:            "update",
+			Namespace:       "kube-system",
+			Resource:        "configmaps",
+			Name:            name,
+			ResourceRequest: true,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		rules []audit.PolicyRule
+		attrs authorizer.Attributes
+		want  audit.Level
+	}{
+		{
+			name:  "all conditions match",
+			rules: []audit.PolicyRule{updateKubeRule, defaultRule},
+			attrs: updateAttrs("kube-proxy", tim),
+			want:  audit.LevelRequestResponse,
+		},
+		{
+			name:  "one condition does not match",
+			rules: []audit.PolicyRule{updateKubeRule, defaultRule},
+			attrs: updateAttrs("coredns", tim),
+			want:  audit.LevelMetadata,
+		},
+		{
+			name:  "non-matching verb",
+			rules: []audit.PolicyRule{updateKubeRule, defaultRule},
+			attrs: attrs["namespaced"],
+			want:  audit.LevelMetadata,
+		},
+		{
+			name:  "user groups",
+			rules: []audit.PolicyRule{humansRule, defaultRule},
+			attrs: attrs["nonResource"],
+			want:  audit.LevelRequest,
+		},
+		{
+			name:  "unauthenticated user",
+			rules: []audit.PolicyRule{humansRule, defaultRule},
+			attrs: attrs["Unauthorized"],
+			want:  audit.LevelMetadata,
+		},
+		{
+			name:  "compilation error never matches",
+			rules: []audit.PolicyRule{brokenRule, defaultRule},
+			attrs: attrs["namespaced"],
+			want:  audit.LevelMetadata,
+		},
+		{
+			name:  "evaluation error never matches",
+			rules: []audit.PolicyRule{missingKeyRule, defaultRule},
+			attrs: attrs["namespaced"],
+			want:  audit.LevelMetadata,
+		},
+		{
+			name:  "field selector",
+			rules: []audit.PolicyRule{ownPodsRule, defaultRule},
+			attrs: nodeListAttrs("node1", ""),
+			want:  audit.LevelNone,
+		},
+		{
+			name:  "field selector of another node",
+			rules: []audit.PolicyRule{ownPodsRule, defaultRule},
+			attrs: nodeListAttrs("node2", ""),
+			want:  audit.LevelMetadata,
+		},
+		{
+			name:  "label selector",
+			rules: []audit.PolicyRule{ownPodsRule, defaultRule},
+			attrs: nodeListAttrs("node1", "app in (web)"),
+			want:  audit.LevelMetadata,
+		},
+		{
+			name:  "no selectors",
+			rules: []audit.PolicyRule{ownPodsRule, defaultRule},
+			attrs: attrs["namespaced"],
+			want:  audit.LevelMetadata,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := &audit.Policy{Rules: test.rules}
+			got := NewPolicyRuleEvaluator(policy).EvaluatePolicyRule(test.attrs)
+			assert.Equal(t, test.want, got.Level)
+		})
+	}
+}
+
+func TestHasMatchingString(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		value    string
+		want     bool
+	}{
+		{nil, "tim", false},
+		{[]string{"tim"}, "tim", true},
+		{[]string{"tim"}, "timothy", false},
+		{[]string{"*"}, "tim", true},
+		{[]string{"*"}, "", true},
+		{[]string{"tim*"}, "timothy", true},
+		{[]string{"tim*"}, "tim", true},
+		{[]string{"tim*"}, "jim", false},
+		{[]string{"system:serviceaccount:monitoring:*"}, "system:serviceaccount:monitoring:prometheus", true},
+		{[]string{"system:serviceaccount:monitoring:*"}, "system:serviceaccount:kube-system:default", false},
+		{[]string{"jim", "tim*"}, "timothy", true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, hasMatchingString(test.patterns, test.value), "patterns: %v, value: %q", test.patterns, test.value)
+	}
+}
+
+func TestBackends(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{Level: audit.LevelRequestResponse, Verbs: []string{"create"}, Backends: []string{"webhook", "log"}},
+		{Level: audit.LevelMetadata, Backends: []string{"log"}},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy)
+
+	got := evaluator.EvaluatePolicyRule(&authorizer.AttributesRecord{Verb: "create"})
+	assert.Equal(t, []string{"webhook", "log"}, got.Backends)
+	got = evaluator.EvaluatePolicyRule(attrs["namespaced"])
+	assert.Equal(t, []string{"log"}, got.Backends)
+}
+
+func TestObjectDiff(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{Level: audit.LevelRequestResponse, Verbs: []string{"update", "patch"}, ObjectDiff: true},
+		{Level: audit.LevelRequestResponse},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy)
+
+	got := evaluator.EvaluatePolicyRule(&authorizer.AttributesRecord{Verb: "patch"})
+	assert.True(t, got.ObjectDiff)
+	got = evaluator.EvaluatePolicyRule(&authorizer.AttributesRecord{Verb: "create"})
+	assert.False(t, got.ObjectDiff)
+}
+
+func TestSampling(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	t.Run("every nth", func(t *testing.T) {
+		policy := &audit.Policy{Rules: []audit.PolicyRule{{
+			Level:        audit.LevelMetadata,
+			SamplingRate: int32Ptr(3),
+			SamplingMode: audit.SamplingModeEveryNth,
+		}}}
+		evaluator := NewPolicyRuleEvaluator(policy)
+		var levels []audit.Level
+		for i := 0; i < 6; i++ {
+			levels = append(levels, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level)
+		}
+		assert.Equal(t, []audit.Level{
+			audit.LevelNone, audit.LevelNone, audit.LevelMetadata,
+			audit.LevelNone, audit.LevelNone, audit.LevelMetadata,
+		}, levels)
+	})
+
+	t.Run("random", func(t *testing.T) {
+		policy := &audit.Policy{Rules: []audit.PolicyRule{{
+			Level:        audit.LevelMetadata,
+			SamplingRate: int32Ptr(100),
+		}}}
+		evaluator := NewPolicyRuleEvaluator(policy).(*policyRuleEvaluator)
+		next := 0
+		evaluator.randIntn = func(n int) int {
+			assert.Equal(t, 100, n)
+			defer func() { next++ }()
+			return next
+		}
+		assert.Equal(t, audit.LevelMetadata, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level)
+		assert.Equal(t, audit.LevelNone, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level)
+	})
+
+	t.Run("rate of one audits everything", func(t *testing.T) {
+		policy := &audit.Policy{Rules: []audit.PolicyRule{{
+			Level:        audit.LevelMetadata,
+			SamplingRate: int32Ptr(1),
+		}}}
+		evaluator := NewPolicyRuleEvaluator(policy)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, audit.LevelMetadata, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level)
+		}
+	})
+}
+
+func TestPolicyDecisionObserver(t *testing.T) {
+	type decision struct {
+		ruleIndex int
+		level     audit.Level
+	}
+	var decisions []decision
+	observer := PolicyDecisionObserverFunc(func(ruleIndex int, _ authorizer.Attributes, level audit.Level) {
+		decisions = append(decisions, decision{ruleIndex, level})
+	})
+	policy := &audit.Policy{Rules: []audit.PolicyRule{rules["getClusterRoles"], rules["getPods"]}}
+	evaluator := NewPolicyRuleEvaluatorWithObserver(policy, observer)
+
+	evaluator.EvaluatePolicyRule(attrs["namespaced"])
+	evaluator.EvaluatePolicyRule(attrs["cluster"])
+	evaluator.EvaluatePolicyRule(attrs["nonResource"])
+	assert.Equal(t, []decision{
+		{1, audit.LevelRequestResponse},
+		{0, audit.LevelRequestResponse},
+		{-1, DefaultAuditLevel},
+	}, decisions)
+}
+
+func TestObjectSelector(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{
+			Level:          audit.LevelRequestResponse,
+			Resources:      []audit.GroupResources{{Resources: []string{"pods"}}},
+			ObjectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "control-plane"}},
+		},
+		{Level: audit.LevelMetadata},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy).(auditinternal.ObjectPolicyRuleEvaluator)
+
+	create := &authorizer.AttributesRecord{
+		User:            tim,
+		Verb:            "create",
+		Namespace:       "kube-system",
+		Resource:        "pods",
+		ResourceRequest: true,
+	}
+	controlPlanePod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"tier": "control-plane"}}}
+	otherPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"tier": "frontend"}}}
+
+	provisional := evaluator.EvaluatePolicyRule(create)
+	assert.True(t, provisional.DependsOnObject)
+	assert.Equal(t, audit.LevelRequestResponse, provisional.Level, "provisional level must be the highest possible level")
+
+	final := evaluator.EvaluatePolicyRuleForObject(create, controlPlanePod)
+	assert.False(t, final.DependsOnObject)
+	assert.Equal(t, audit.LevelRequestResponse, final.Level)
+
+	final = evaluator.EvaluatePolicyRuleForObject(create, otherPod)
+	assert.False(t, final.DependsOnObject)
+	assert.Equal(t, audit.LevelMetadata, final.Level)
+
+	get := attrs["namespaced"]
+	decision := evaluator.EvaluatePolicyRule(get)
+	assert.False(t, decision.DependsOnObject, "requests without request object never match object selectors")
+	assert.Equal(t, audit.LevelMetadata, decision.Level)
+}
+
+func TestNamespaceSelector(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{
+			Level:             audit.LevelRequestResponse,
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		},
+		{Level: audit.LevelMetadata},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy)
+	w, ok := evaluator.(WantsNamespaceLister)
+	require.True(t, ok)
+	assert.True(t, w.NeedsNamespaceLister())
+
+	assert.Equal(t, audit.LevelMetadata, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level, "namespace selectors never match without a lister")
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	w.SetNamespaceLister(corev1listers.NewNamespaceLister(indexer))
+	assert.Equal(t, audit.LevelMetadata, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level, "unknown namespaces never match")
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"env": "prod"}}}
+	require.NoError(t, indexer.Add(ns))
+	assert.Equal(t, audit.LevelRequestResponse, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level)
+	assert.Equal(t, audit.LevelMetadata, evaluator.EvaluatePolicyRule(attrs["cluster"]).Level, "cluster-scoped requests never match")
+	assert.Equal(t, audit.LevelMetadata, evaluator.EvaluatePolicyRule(attrs["nonResource"]).Level, "non-resource requests never match")
+
+	ns = ns.DeepCopy()
+	ns.Labels["env"] = "staging"
+	require.NoError(t, indexer.Update(ns))
+	assert.Equal(t, audit.LevelMetadata, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level, "relabeled namespaces must not match")
+
+	assert.False(t, NewPolicyRuleEvaluator(&audit.Policy{Rules: []audit.PolicyRule{{Level: audit.LevelMetadata}}}).(WantsNamespaceLister).NeedsNamespaceLister())
+}
+
+func TestResponseCodes(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{Level: audit.LevelRequestResponse, ResponseCodes: []string{"401", "403", "5xx"}},
+		{Level: audit.LevelMetadata},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy)
+
+	provisional := evaluator.EvaluatePolicyRule(attrs["namespaced"])
+	assert.Equal(t, audit.LevelRequestResponse, provisional.Level, "provisional level must be the highest possible level")
+	require.NotNil(t, provisional.EvaluateResponse)
+
+	for code, level := range map[int32]audit.Level{
+		200: audit.LevelMetadata,
+		401: audit.LevelRequestResponse,
+		403: audit.LevelRequestResponse,
+		404: audit.LevelMetadata,
+		500: audit.LevelRequestResponse,
+		503: audit.LevelRequestResponse,
+	} {
+		final := provisional.EvaluateResponse(code, nil)
+		assert.Equal(t, level, final.Level, "response code %d", code)
+		assert.Nil(t, final.EvaluateResponse, "response code %d", code)
+	}
+
+	noResponseCodes := NewPolicyRuleEvaluator(&audit.Policy{Rules: []audit.PolicyRule{{Level: audit.LevelMetadata}}})
+	assert.Nil(t, noResponseCodes.EvaluatePolicyRule(attrs["namespaced"]).EvaluateResponse)
+}
+
+func TestAuditAnnotations(t *testing.T) {
+	forbidden := map[string]string{"authorization.k8s.io/decision": "forbid"}
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{Level: audit.LevelRequestResponse, AuditAnnotations: forbidden},
+		{Level: audit.LevelMetadata},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy)
+
+	provisional := evaluator.EvaluatePolicyRule(attrs["namespaced"])
+	assert.Equal(t, audit.LevelRequestResponse, provisional.Level, "provisional level must be the highest possible level")
+	require.NotNil(t, provisional.EvaluateResponse)
+
+	for name, tc := range map[string]struct {
+		annotations map[string]string
+		level       audit.Level
+	}{
+		"none":      {nil, audit.LevelMetadata},
+		"forbidden": {map[string]string{"authorization.k8s.io/decision": "forbid", "authorization.k8s.io/reason": ""}, audit.LevelRequestResponse},
+		"allowed":   {map[string]string{"authorization.k8s.io/decision": "allow"}, audit.LevelMetadata},
+	} {
+		assert.Equal(t, tc.level, provisional.EvaluateResponse(403, tc.annotations).Level, name)
+	}
+
+	decisions := EvaluateRequests(policy, []SyntheticRequest{
+		{Verb: "get", Resource: "pods", AuditAnnotations: forbidden},
+		{Verb: "get", Resource: "pods"},
+	})
+	assert.Equal(t, audit.LevelRequestResponse, decisions[0].Level)
+	assert.Equal(t, audit.LevelMetadata, decisions[1].Level)
+}
+
+func TestSourceIPs(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{Level: audit.LevelNone, SourceIPs: []string{"10.0.0.0/8", "fd00::/8"}},
+		{Level: audit.LevelRequestResponse},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy)
+
+	for sourceIP, level := range map[string]audit.Level{
+		"10.1.2.3":    audit.LevelNone,
+		"fd00::1":     audit.LevelNone,
+		"192.168.0.1": audit.LevelRequestResponse,
+		"2001:db8::1": audit.LevelRequestResponse,
+	} {
+		requestAttrs := &auditinternal.RequestAttributes{
+			Attributes: attrs["namespaced"],
+			SourceIP:   netutils.ParseIPSloppy(sourceIP),
+		}
+		assert.Equal(t, level, evaluator.EvaluatePolicyRule(requestAttrs).Level, "source IP %s", sourceIP)
+	}
+
+	assert.Equal(t, audit.LevelRequestResponse, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level,
+		"requests with an unknown source IP must not match rules with source IPs")
+}
+
+func TestUserAgents(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{Level: audit.LevelMetadata, UserAgents: []string{"kube-scheduler/*", "kube-controller-manager/*/leader-election"}},
+		{Level: audit.LevelRequestResponse},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy)
+
+	for userAgent, level := range map[string]audit.Level{
+		"kube-scheduler/v1.25.0 (linux/amd64) kubernetes/a866cbe":                                                                  audit.LevelMetadata,
+		"kube-controller-manager/v1.25.0 (linux/amd64) kubernetes/a866cbe/leader-election":                                         audit.LevelMetadata,
+		"kube-controller-manager/v1.25.0 (linux/amd64) kubernetes/a866cbe/system:serviceaccount:kube-system:deployment-controller": audit.LevelRequestResponse,
+		"kubectl/v1.25.0 (linux/amd64) kubernetes/a866cbe":                                                                         audit.LevelRequestResponse,
+		"": audit.LevelRequestResponse,
+	} {
+		requestAttrs := &auditinternal.RequestAttributes{
+			Attributes: attrs["namespaced"],
+			UserAgent:  userAgent,
+		}
+		assert.Equal(t, level, evaluator.EvaluatePolicyRule(requestAttrs).Level, "user agent %q", userAgent)
+	}
+
+	assert.Equal(t, audit.LevelRequestResponse, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level,
+		"requests with an unknown user agent must not match rules with user agents")
+}
+
+func TestActiveWindow(t *testing.T) {
+	start := time.Date(2022, time.December, 20, 0, 0, 0, 0, time.UTC)
+	end := start.Add(14 * 24 * time.Hour)
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{
+			Level:        audit.LevelRequestResponse,
+			Verbs:        []string{"create", "update", "patch", "delete"},
+			ActiveWindow: &audit.ActiveWindow{Start: &metav1.Time{Time: start}, End: &metav1.Time{Time: end}},
+		},
+		{
+			Level:        audit.LevelRequest,
+			ActiveWindow: &audit.ActiveWindow{End: &metav1.Time{Time: start}},
+		},
+		{Level: audit.LevelMetadata},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy).(*policyRuleEvaluator)
+
+	write := &authorizer.AttributesRecord{User: tim, Verb: "update", Resource: "pods", ResourceRequest: true}
+	for now, level := range map[time.Time]audit.Level{
+		start.Add(-time.Second): audit.LevelRequest,
+		start:                   audit.LevelRequestResponse,
+		end.Add(-time.Second):   audit.LevelRequestResponse,
+		end:                     audit.LevelMetadata,
+	} {
+		evaluator.now = func() time.Time { return now }
+		assert.Equal(t, level, evaluator.EvaluatePolicyRule(write).Level, "now: %v", now)
+	}
+}
+
+func TestDryRunAndServerSideApply(t *testing.T) {
+	yes, no := true, false
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{Level: audit.LevelMetadata, DryRun: &yes},
+		{Level: audit.LevelRequest, ServerSideApply: &yes},
+		{Level: audit.LevelRequestResponse, DryRun: &no},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy)
+
+	patch := &authorizer.AttributesRecord{User: tim, Verb: "patch", Namespace: "default", Resource: "pods", ResourceRequest: true}
+	tests := []struct {
+		dryRun, serverSideApply bool
+		level                   audit.Level
+	}{
+		{dryRun: true, serverSideApply: true, level: audit.LevelMetadata},
+		{dryRun: false, serverSideApply: true, level: audit.LevelRequest},
+		{dryRun: false, serverSideApply: false, level: audit.LevelRequestResponse},
+	}
+	for _, test := range tests {
+		requestAttrs := &auditinternal.RequestAttributes{
+			Attributes:      patch,
+			DryRun:          test.dryRun,
+			ServerSideApply: test.serverSideApply,
+		}
+		assert.Equal(t, test.level, evaluator.EvaluatePolicyRule(requestAttrs).Level, "dryRun: %v, serverSideApply: %v", test.dryRun, test.serverSideApply)
+	}
+
+	assert.Equal(t, audit.LevelRequestResponse, evaluator.EvaluatePolicyRule(patch).Level,
+		"requests with unknown request attributes must be treated as real mutations")
+}
+
+func TestMinimumLevels(t *testing.T) {
+	policy := &audit.Policy{
+		Rules: []audit.PolicyRule{
+			{Level: audit.LevelRequestResponse, Resources: []audit.GroupResources{{Resources: []string{"configmaps"}}}},
+			{Level: audit.LevelNone},
+		},
+		MinimumLevels: &audit.MinimumLevels{Write: audit.LevelMetadata},
+	}
+	evaluator := NewPolicyRuleEvaluator(policy)
+
+	write := &authorizer.AttributesRecord{User: tim, Verb: "delete", Namespace: "default", Resource: "pods", ResourceRequest: true}
+	assert.Equal(t, audit.LevelMetadata, evaluator.EvaluatePolicyRule(write).Level, "writes must not be audited below the minimum level")
+	assert.Equal(t, audit.LevelNone, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level, "reads have no minimum level")
+
+	configMapWrite := &authorizer.AttributesRecord{User: tim, Verb: "update", Namespace: "default", Resource: "configmaps", ResourceRequest: true}
+	assert.Equal(t, audit.LevelRequestResponse, evaluator.EvaluatePolicyRule(configMapWrite).Level, "the minimum level must not lower levels")
+
+	policy.MinimumLevels.Read = audit.LevelMetadata
+	evaluator = NewPolicyRuleEvaluator(policy)
+	assert.Equal(t, audit.LevelMetadata, evaluator.EvaluatePolicyRule(attrs["namespaced"]).Level)
+	assert.Equal(t, audit.LevelMetadata, Evaluate(policy, write).Level, "dry-run evaluation must enforce minimum levels")
+}
+
+func TestMatchedRule(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{Name: "secrets", Level: audit.LevelMetadata, Resources: []audit.GroupResources{{Resources: []string{"secrets"}}}},
+		{Level: audit.LevelRequest, Verbs: []string{"get"}},
+	}}
+	evaluator := NewPolicyRuleEvaluator(policy)
+
+	secret := &authorizer.AttributesRecord{User: tim, Verb: "get", Namespace: "default", Resource: "secrets", ResourceRequest: true}
+	assert.Equal(t, &auditinternal.PolicyRuleRef{Index: 0, Name: "secrets"}, evaluator.EvaluatePolicyRule(secret).MatchedRule)
+	assert.Equal(t, &auditinternal.PolicyRuleRef{Index: 1}, evaluator.EvaluatePolicyRule(attrs["namespaced"]).MatchedRule)
+	deletePod := &authorizer.AttributesRecord{User: tim, Verb: "delete", Namespace: "default", Resource: "pods", ResourceRequest: true}
+	assert.Nil(t, evaluator.EvaluatePolicyRule(deletePod).MatchedRule, "no rule matched")
+}
+
+func TestGlobMatches(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"kubectl", "kubectl", true},
+		{"kubectl", "kubectl/v1.25.0", false},
+		{"kubectl*", "kubectl/v1.25.0", true},
+		{"*", "", true},
+		{"*/leader-election", "kube-scheduler/v1.25.0/leader-election", true},
+		{"*/leader-election", "kube-scheduler/v1.25.0", false},
+		{"kube-*/*/leader-election", "kube-scheduler/v1.25.0/leader-election", true},
+		{"a*b*c", "abc", true},
+		{"a*b*c", "acb", false},
+		{"ab*ba", "aba", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, globMatches(test.pattern, test.s), "pattern: %q, s: %q", test.pattern, test.s)
+	}
+}
+
+func TestPathMatches(t *testing.T) {
+	tests := []struct {
+		spec, path string
+		want       bool
+	}{
+		{"*", "/healthz", true},
+		{"/healthz", "/healthz", true},
+		{"/healthz", "/healthz/etcd", false},
+		{"/healthz*", "/healthz/etcd", true},
+		{"~/apis/[^/]+/v1beta1", "/apis/batch/v1beta1", true},
+		{"~/apis/[^/]+/v1beta1", "/apis/batch/v1beta1/cronjobs", false},
+		{"~/apis/[^/]+/v1beta1", "/prefix/apis/batch/v1beta1", false},
+		{"~/openapi/v[23].*", "/openapi/v3/apis/apps/v1", true},
+		{"~/openapi/v[23].*", "/openapi/v1", false},
+		{"~/invalid/[", "/invalid/[", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, pathMatches(test.path, test.spec), "spec: %q, path: %q", test.spec, test.path)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/apis/audit"
+	auditinternal "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	netutils "k8s.io/utils/net"
+	"sigs.k8s.io/yaml"
+)
+
+// Decision is the result of a dry-run evaluation of an audit policy for a request.
+type Decision struct {
+	// RuleIndex is the index of the matched rule, or -1 if no rule matched.
+	RuleIndex int `json:"ruleIndex"`
+	// RuleName is the name of the matched rule, if any.
+	RuleName string `json:"ruleName,omitempty"`
+	// Level is the level the request is audited at.
+	Level audit.Level `json:"level"`
+}
+
+// SyntheticRequest describes a request to evaluate an audit policy against.
+type SyntheticRequest struct {
+	User   string   `json:"user,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+
+	Verb        string `json:"verb"`
+	APIGroup    string `json:"apiGroup,omitempty"`
+	APIVersion  string `json:"apiVersion,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+	// Path is the request path. Requests without a resource are non-resource requests.
+	Path string `json:"path,omitempty"`
+
+	// NamespaceLabels are the labels of the namespace of the request.
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+	// ObjectLabels are the labels of the request object. If unset, the request has no object.
+	ObjectLabels map[string]string `json:"objectLabels,omitempty"`
+	// ResponseCode is the response code of the request. Defaults to 200.
+	ResponseCode int32 `json:"responseCode,omitempty"`
+	// AuditAnnotations are the audit annotations set during the request, e.g. by authorization.
+	AuditAnnotations map[string]string `json:"auditAnnotations,omitempty"`
+	// SourceIP is the IP address of the client.
+	SourceIP string `json:"sourceIP,omitempty"`
+	// UserAgent is the User-Agent header of the request.
+	UserAgent string `json:"userAgent,omitempty"`
+	// DryRun is true for dry-run requests.
+	DryRun bool `json:"dryRun,omitempty"`
+	// ServerSideApply is true for server-side apply patches.
+	ServerSideApply bool `json:"serverSideApply,omitempty"`
+}
+
+// Attributes returns the authorizer attributes of the request.
+func (r *SyntheticRequest) Attributes() authorizer.Attributes {
+	var u user.Info
+	if r.User != "" || len(r.Groups) > 0 {
+		u = &user.DefaultInfo{Name: r.User, Groups: r.Groups}
+	}
+	attrs := &authorizer.AttributesRecord{
+		User:            u,
+		Verb:            r.Verb,
+		APIGroup:        r.APIGroup,
+		APIVersion:      r.APIVersion,
+		Resource:        r.Resource,
+		Subresource:     r.Subresource,
+		Namespace:       r.Namespace,
+		Name:            r.Name,
+		Path:            r.Path,
+		ResourceRequest: r.Resource != "",
+	}
+	return &auditinternal.RequestAttributes{
+		Attributes:      attrs,
+		SourceIP:        netutils.ParseIPSloppy(r.SourceIP),
+		UserAgent:       r.UserAgent,
+		DryRun:          r.DryRun,
+		ServerSideApply: r.ServerSideApply,
+	}
+}
+
+func (r *SyntheticRequest) object() runtime.Object {
+	if r.ObjectLabels == nil {
+		return nil
+	}
+	return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		Namespace: r.Namespace,
+		Name:      r.Name,
+		Labels:    r.ObjectLabels,
+	}}
+}
+
+func (r *SyntheticRequest) response() response {
+	code := r.ResponseCode
+	if code == 0 {
+		code = http.StatusOK
+	}
+	return response{code: code, annotations: r.AuditAnnotations}
+}
+
+// Evaluate evaluates the audit policy against the given request attributes
+// without auditing anything, e.g. to validate policy changes before rollout.
+// The request is assumed to have no request object, no audit annotations and
+// to succeed, and its namespace is unknown to namespace selectors. Sampling is
+// not applied, a sampled rule reports its configured level.
+func Evaluate(policy *audit.Policy, attrs authorizer.Attributes) Decision {
+	e := newDryRunEvaluator(policy)
+	return e.decide(attrs, nil, response{code: http.StatusOK})
+}
+
+// EvaluateRequests evaluates the audit policy against each of the given
+// requests, like Evaluate, and returns the decisions in the same order.
+func EvaluateRequests(policy *audit.Policy, requests []SyntheticRequest) []Decision {
+	e := newDryRunEvaluator(policy)
+	decisions := make([]Decision, 0, len(requests))
+	for i := range requests {
+		r := &requests[i]
+		e.SetNamespaceLister(namespaceListerFor(r))
+		decisions = append(decisions, e.decide(r.Attributes(), r.object(), r.response()))
+	}
+	return decisions
+}
+
+// LoadSyntheticRequestsFromFile reads a YAML or JSON list of synthetic requests.
+func LoadSyntheticRequestsFromFile(filePath string) ([]SyntheticRequest, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file path %q: %+v", filePath, err)
+	}
+	var requests []SyntheticRequest
+	if err := yaml.UnmarshalStrict(data, &requests); err != nil {
+		return nil, fmt.Errorf("failed decoding: %v: from file %v", err, filePath)
+	}
+	return requests, nil
+}
+
+// WriteDecisions writes a table of the requests and their decisions to w.
+func WriteDecisions(w io.Writer, requests []SyntheticRequest, decisions []Decision) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "USER\tVERB\tRESOURCE\tNAMESPACE\tNAME\tRULE\tLEVEL")
+	for i := range requests {
+		r := &requests[i]
+		resource := r.Path
+		if r.Resource != "" {
+			resource = r.Resource
+			if r.Subresource != "" {
+				resource += "/" + r.Subresource
+			}
+			if r.APIGroup != "" {
+				resource += "." + r.APIGroup
+			}
+		}
+		rule := "<none>"
+		if decisions[i].RuleName != "" {
+			rule = decisions[i].RuleName
+		} else if decisions[i].RuleIndex >= 0 {
+			rule = fmt.Sprint(decisions[i].RuleIndex)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.User, r.Verb, resource, r.Namespace, r.Name, rule, decisions[i].Level)
+	}
+	return tw.Flush()
+}
+
+func newDryRunEvaluator(policy *audit.Policy) *policyRuleEvaluator {
+	// Evaluate a copy, as the evaluator modifies the rules.
+	return NewPolicyRuleEvaluator(policy.DeepCopy()).(*policyRuleEvaluator)
+}
+
+func (p *policyRuleEvaluator) decide(attrs authorizer.Attributes, obj runtime.Object, resp response) Decision {
+	matched, _, _ := p.findRule(attrs, obj, resp)
+	if matched < 0 {
+		return Decision{RuleIndex: -1, Level: p.enforceMinimumLevel(attrs, DefaultAuditLevel)}
+	}
+	return Decision{RuleIndex: matched, RuleName: p.Rules[matched].Name, Level: p.enforceMinimumLevel(attrs, p.Rules[matched].Level)}
+}
+
+func namespaceListerFor(r *SyntheticRequest) corev1listers.NamespaceLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if r.Namespace != "" {
+		indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: r.Namespace, Labels: r.NamespaceLabels}})
+	}
+	return corev1listers.NewNamespaceLister(indexer)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/apis/audit"
+)
+
+const syntheticRequests = `
+- user: tim@k8s.io
+  groups: ["humans"]
+  verb: get
+  resource: pods
+  namespace: default
+  name: busybox
+- user: system:serviceaccount:kube-system:deployer
+  verb: create
+  apiGroup: apps
+  resource: deployments
+  namespace: prod
+  namespaceLabels:
+    env: prod
+- user: tim@k8s.io
+  verb: create
+  resource: pods
+  namespace: kube-system
+  objectLabels:
+    tier: control-plane
+- user: tim@k8s.io
+  verb: delete
+  resource: pods
+  namespace: default
+  responseCode: 403
+- verb: get
+  path: /healthz
+`
+
+func TestEvaluateRequests(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{
+		{Level: audit.LevelNone, NonResourceURLs: []string{"/healthz"}},
+		{Level: audit.LevelRequestResponse, ResponseCodes: []string{"403"}},
+		{
+			Level:          audit.LevelRequestResponse,
+			ObjectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "control-plane"}},
+		},
+		{
+			Level:             audit.LevelRequest,
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		},
+		{Level: audit.LevelMetadata, UserGroups: []string{"humans"}},
+	}}
+	path := filepath.Join(t.TempDir(), "requests.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(syntheticRequests), 0644))
+
+	requests, err := LoadSyntheticRequestsFromFile(path)
+	require.NoError(t, err)
+	decisions := EvaluateRequests(policy, requests)
+	assert.Equal(t, []Decision{
+		{RuleIndex: 4, Level: audit.LevelMetadata},
+		{RuleIndex: 3, Level: audit.LevelRequest},
+		{RuleIndex: 2, Level: audit.LevelRequestResponse},
+		{RuleIndex: 1, Level: audit.LevelRequestResponse},
+		{RuleIndex: 0, Level: audit.LevelNone},
+	}, decisions)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteDecisions(&out, requests, decisions))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, len(requests)+1)
+	assert.Equal(t, []string{"USER", "VERB", "RESOURCE", "NAMESPACE", "NAME", "RULE", "LEVEL"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"system:serviceaccount:kube-system:deployer", "create", "deployments.apps", "prod", "3", "Request"}, strings.Fields(lines[2]))
+}
+
+func TestEvaluate(t *testing.T) {
+	policy := &audit.Policy{Rules: []audit.PolicyRule{rules["getPods"], rules["getClusterRoles"]}}
+	assert.Equal(t, Decision{RuleIndex: 0, Level: audit.LevelRequestResponse}, Evaluate(policy, attrs["namespaced"]))
+	assert.Equal(t, Decision{RuleIndex: 1, Level: audit.LevelRequestResponse}, Evaluate(policy, attrs["cluster"]))
+	assert.Equal(t, Decision{RuleIndex: -1, Level: DefaultAuditLevel}, Evaluate(policy, attrs["nonResource"]))
+	assert.Nil(t, policy.Rules[0].OmitStages, "the policy must not be modified")
+
+	named := &audit.Policy{Rules: []audit.PolicyRule{{Name: "everything", Level: audit.LevelMetadata}}}
+	assert.Equal(t, Decision{RuleIndex: 0, RuleName: "everything", Level: audit.LevelMetadata}, Evaluate(named, attrs["namespaced"]))
+
+	_, err := LoadSyntheticRequestsFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
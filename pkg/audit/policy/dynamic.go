@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	auditinternal "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/audit/policy/matchconditions"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/util/filewatcher"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// FileRefreshDuration is the interval at which the policy file is re-read even if
+// no file events were observed. It is exposed so that tests can crank up the reload speed.
+var FileRefreshDuration = 1 * time.Minute
+
+// DynamicPolicyRuleEvaluator is a PolicyRuleEvaluator that reloads the audit
+// policy whenever the policy file changes. A policy that fails to load or validate
+// is rejected and the previously loaded policy stays in effect.
+type DynamicPolicyRuleEvaluator struct {
+	// filename is the name of the policy file to read.
+	filename string
+
+	// evaluator holds a *loadedPolicy with the last successfully loaded policy.
+	evaluator atomic.Value
+
+	// watcher reloads the policy file whenever it changes.
+	watcher *filewatcher.FileWatcher
+
+	// namespaceLister is passed on to the evaluators of loaded policies.
+	namespaceLister corev1listers.NamespaceLister
+}
+
+var _ auditinternal.ObjectPolicyRuleEvaluator = &DynamicPolicyRuleEvaluator{}
+var _ WantsNamespaceLister = &DynamicPolicyRuleEvaluator{}
+
+type loadedPolicy struct {
+	content   []byte
+	evaluator auditinternal.PolicyRuleEvaluator
+}
+
+// NewDynamicPolicyRuleEvaluator creates a policy rule evaluator that watches the
+// policy file at the given path and swaps in the new policy after it has been
+// validated. The initial policy must load successfully.
+// Run must be called for the file to be watched.
+func NewDynamicPolicyRuleEvaluator(path string) (*DynamicPolicyRuleEvaluator, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("file path not specified")
+	}
+	e := &DynamicPolicyRuleEvaluator{
+		filename: path,
+	}
+	e.watcher = filewatcher.New("DynamicAuditPolicy", path, e.loadPolicy)
+	if err := e.loadPolicy(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// EvaluatePolicyRule evaluates the currently loaded audit policy.
+func (e *DynamicPolicyRuleEvaluator) EvaluatePolicyRule(attrs authorizer.Attributes) auditinternal.RequestAuditConfigWithLevel {
+	return e.evaluator.Load().(*loadedPolicy).evaluator.EvaluatePolicyRule(attrs)
+}
+
+// EvaluatePolicyRuleForObject evaluates the currently loaded audit policy against
+// the request object.
+func (e *DynamicPolicyRuleEvaluator) EvaluatePolicyRuleForObject(attrs authorizer.Attributes, obj runtime.Object) auditinternal.RequestAuditConfigWithLevel {
+	evaluator := e.evaluator.Load().(*loadedPolicy).evaluator
+	if o, ok := evaluator.(auditinternal.ObjectPolicyRuleEvaluator); ok {
+		return o.EvaluatePolicyRuleForObject(attrs, obj)
+	}
+	return evaluator.EvaluatePolicyRule(attrs)
+}
+
+// NeedsNamespaceLister returns whether the currently loaded policy has rules with
+// namespace selectors. Namespace selectors of policies loaded later never match
+// unless a lister has been set.
+func (e *DynamicPolicyRuleEvaluator) NeedsNamespaceLister() bool {
+	w, ok := e.evaluator.Load().(*loadedPolicy).evaluator.(WantsNamespaceLister)
+	return ok && w.NeedsNamespaceLister()
+}
+
+// SetNamespaceLister sets the lister used to evaluate namespace selectors of the
+// loaded policies. It must be called before Run.
+func (e *DynamicPolicyRuleEvaluator) SetNamespaceLister(lister corev1listers.NamespaceLister) {
+	e.namespaceLister = lister
+	if w, ok := e.evaluator.Load().(*loadedPolicy).evaluator.(WantsNamespaceLister); ok {
+		w.SetNamespaceLister(lister)
+	}
+}
+
+// loadPolicy reads the policy file and swaps the evaluator if the content changed.
+func (e *DynamicPolicyRuleEvaluator) loadPolicy() error {
+	content, err := ioutil.ReadFile(e.filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file path %q: %v", e.filename, err)
+	}
+	if existing, ok := e.evaluator.Load().(*loadedPolicy); ok && bytes.Equal(existing.content, content) {
+		return nil
+	}
+
+	p, err := LoadPolicyFromBytes(content)
+	if err != nil {
+		return fmt.Errorf("%v: from file %v", err, e.filename)
+	}
+	evaluator := newPolicyRuleEvaluator(p, matchconditions.CostLimits{})
+	if e.namespaceLister != nil {
+		evaluator.SetNamespaceLister(e.namespaceLister)
+	}
+	if existing, ok := e.evaluator.Load().(*loadedPolicy); ok {
+		existing.evaluator.(*policyRuleEvaluator).metrics.stop()
+	}
+	evaluator.metrics = newRuleMetrics(p.Rules)
+	e.evaluator.Store(&loadedPolicy{
+		content:   content,
+		evaluator: evaluator,
+	})
+	klog.V(2).InfoS("Loaded audit policy", "file", e.filename, "rules", len(p.Rules))
+	return nil
+}
+
+// RunOnce runs a single sync loop
+func (e *DynamicPolicyRuleEvaluator) RunOnce(ctx context.Context) error {
+	return e.loadPolicy()
+}
+
+// Run starts the controller and blocks until ctx is done.
+func (e *DynamicPolicyRuleEvaluator) Run(ctx context.Context, workers int) {
+	e.watcher.Run(ctx, FileRefreshDuration)
+}
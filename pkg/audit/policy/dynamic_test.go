@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/apis/audit"
+)
+
+const dynamicPolicyPattern = `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+  - level: %s
+`
+
+func replacePolicyFile(t *testing.T, path, content string) {
+	t.Helper()
+	// Write to a temporary file and rename it, the way configmap volumes are updated.
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0644))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func TestDynamicPolicyRuleEvaluatorRunOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+
+	_, err := NewDynamicPolicyRuleEvaluator(path)
+	assert.Error(t, err, "missing file should fail")
+
+	replacePolicyFile(t, path, "not a policy")
+	_, err = NewDynamicPolicyRuleEvaluator(path)
+	assert.Error(t, err, "invalid policy should fail")
+
+	replacePolicyFile(t, path, policyWithLevel("Metadata"))
+	e, err := NewDynamicPolicyRuleEvaluator(path)
+	require.NoError(t, err)
+	assert.Equal(t, audit.LevelMetadata, e.EvaluatePolicyRule(attrs["namespaced"]).Level)
+
+	replacePolicyFile(t, path, policyWithLevel("RequestResponse"))
+	require.NoError(t, e.RunOnce(context.Background()))
+	assert.Equal(t, audit.LevelRequestResponse, e.EvaluatePolicyRule(attrs["namespaced"]).Level)
+
+	replacePolicyFile(t, path, policyWithLevel("Bogus"))
+	assert.Error(t, e.RunOnce(context.Background()))
+	assert.Equal(t, audit.LevelRequestResponse, e.EvaluatePolicyRule(attrs["namespaced"]).Level, "invalid policy must not replace the loaded one")
+}
+
+func TestDynamicPolicyRuleEvaluatorRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	replacePolicyFile(t, path, policyWithLevel("Metadata"))
+
+	e, err := NewDynamicPolicyRuleEvaluator(path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.Run(ctx, 1)
+
+	replacePolicyFile(t, path, policyWithLevel("Request"))
+	err = wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return e.EvaluatePolicyRule(attrs["namespaced"]).Level == audit.LevelRequest, nil
+	})
+	assert.NoError(t, err, "policy was not reloaded")
+}
+
+func policyWithLevel(level string) string {
+	return fmt.Sprintf(dynamicPolicyPattern, level)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"math/bits"
+	"strings"
+
+	"k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// ruleSet is a bitset of rule indexes.
+type ruleSet []uint64
+
+func newRuleSet(n int) ruleSet {
+	return make(ruleSet, (n+63)/64)
+}
+
+func (s ruleSet) add(i int) {
+	s[i/64] |= 1 << (uint(i) % 64)
+}
+
+func (s ruleSet) union(other ruleSet) ruleSet {
+	result := make(ruleSet, len(s))
+	for i := range s {
+		result[i] = s[i] | other[i]
+	}
+	return result
+}
+
+// dimensionIndex indexes the rules by the values of one request attribute.
+type dimensionIndex struct {
+	// unconstrained holds the rules that may match any value of the attribute.
+	unconstrained ruleSet
+	// byValue holds, per attribute value, the rules that may match the value.
+	// Every set includes the unconstrained rules.
+	byValue map[string]ruleSet
+}
+
+func newDimensionIndex(n int) dimensionIndex {
+	return dimensionIndex{
+		unconstrained: newRuleSet(n),
+		byValue:       map[string]ruleSet{},
+	}
+}
+
+func (d *dimensionIndex) addValue(rule int, value string, n int) {
+	s, ok := d.byValue[value]
+	if !ok {
+		s = newRuleSet(n)
+		d.byValue[value] = s
+	}
+	s.add(rule)
+}
+
+// finalize merges the unconstrained rules into every value set.
+func (d *dimensionIndex) finalize() {
+	for value, s := range d.byValue {
+		d.byValue[value] = s.union(d.unconstrained)
+	}
+}
+
+func (d *dimensionIndex) candidates(value string) ruleSet {
+	if s, ok := d.byValue[value]; ok {
+		return s
+	}
+	return d.unconstrained
+}
+
+// policyIndex narrows down the rules that may match a request before the
+// rules are evaluated in order. It indexes the rules by verb, user and API
+// group. Rules that cannot be indexed in a dimension (e.g. because of
+// wildcards) are candidates for every value of the dimension, so the index
+// only ever yields a superset of the matching rules.
+type policyIndex struct {
+	verbs  dimensionIndex
+	users  dimensionIndex
+	groups dimensionIndex
+}
+
+func newPolicyIndex(rules []audit.PolicyRule) *policyIndex {
+	n := len(rules)
+	idx := &policyIndex{
+		verbs:  newDimensionIndex(n),
+		users:  newDimensionIndex(n),
+		groups: newDimensionIndex(n),
+	}
+	for i := range rules {
+		r := &rules[i]
+
+		if len(r.Verbs) == 0 {
+			idx.verbs.unconstrained.add(i)
+		}
+		for _, verb := range r.Verbs {
+			idx.verbs.addValue(i, verb, n)
+		}
+
+		if len(r.Users) == 0 || hasWildcard(r.Users) {
+			idx.users.unconstrained.add(i)
+		} else {
+			for _, user := range r.Users {
+				idx.users.addValue(i, user, n)
+			}
+		}
+
+		if len(r.Resources) == 0 || hasWildcardGroup(r.Resources) {
+			idx.groups.unconstrained.add(i)
+		} else {
+			for _, gr := range r.Resources {
+				idx.groups.addValue(i, gr.Group, n)
+			}
+		}
+	}
+	idx.verbs.finalize()
+	idx.users.finalize()
+	idx.groups.finalize()
+	return idx
+}
+
+// forEachCandidate calls fn in rule order with every rule that may match the
+// request, until fn returns true.
+func (idx *policyIndex) forEachCandidate(attrs authorizer.Attributes, fn func(i int) bool) {
+	verbs := idx.verbs.candidates(attrs.GetVerb())
+	users := idx.users.unconstrained
+	if user := attrs.GetUser(); user != nil {
+		users = idx.users.candidates(user.GetName())
+	}
+	groups := idx.groups.unconstrained
+	if attrs.IsResourceRequest() {
+		groups = idx.groups.candidates(attrs.GetAPIGroup())
+	}
+
+	for w := range verbs {
+		word := verbs[w] & users[w] & groups[w]
+		for word != 0 {
+			if fn(w*64 + bits.TrailingZeros64(word)) {
+				return
+			}
+			word &= word - 1
+		}
+	}
+}
+
+func hasWildcard(patterns []string) bool {
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasWildcardGroup(groupResources []audit.GroupResources) bool {
+	for _, gr := range groupResources {
+		if gr.Group == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+func TestPolicyIndexCandidates(t *testing.T) {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	policyRules := make([]audit.PolicyRule, 0, len(names))
+	for _, name := range names {
+		policyRules = append(policyRules, rules[name])
+	}
+	idx := newPolicyIndex(policyRules)
+
+	for req, a := range attrs {
+		var candidates []int
+		idx.forEachCandidate(a, func(i int) bool {
+			candidates = append(candidates, i)
+			return false
+		})
+		assert.True(t, sort.IntsAreSorted(candidates), "request:%s candidates must be in rule order: %v", req, candidates)
+		for i := range policyRules {
+			if ruleMatches(&policyRules[i], a) {
+				assert.Contains(t, candidates, i, "request:%s rule:%s matches but is not a candidate", req, names[i])
+			}
+		}
+	}
+}
+
+func TestPolicyIndexStopsAtFirstMatch(t *testing.T) {
+	idx := newPolicyIndex([]audit.PolicyRule{
+		{Level: audit.LevelNone, Verbs: []string{"list"}},
+		{Level: audit.LevelMetadata, Verbs: []string{"get"}},
+		{Level: audit.LevelRequest},
+	})
+	var visited []int
+	idx.forEachCandidate(attrs["namespaced"], func(i int) bool {
+		visited = append(visited, i)
+		return true
+	})
+	assert.Equal(t, []int{1}, visited)
+}
+
+// largePolicy returns a policy with n rules that each target a distinct
+// group, followed by a catch-all rule.
+func largePolicy(n int) *audit.Policy {
+	policy := &audit.Policy{}
+	for i := 0; i < n; i++ {
+		policy.Rules = append(policy.Rules, audit.PolicyRule{
+			Level: audit.LevelRequestResponse,
+			Users: []string{fmt.Sprintf("user-%d", i%10)},
+			Verbs: []string{"create", "update", "patch"},
+			Resources: []audit.GroupResources{{
+				Group:     fmt.Sprintf("group-%d.example.com", i),
+				Resources: []string{"widgets", "gadgets"},
+			}},
+		})
+	}
+	policy.Rules = append(policy.Rules, audit.PolicyRule{Level: audit.LevelMetadata})
+	return policy
+}
+
+func BenchmarkEvaluatePolicyRule(b *testing.B) {
+	for _, n := range []int{10, 100, 500} {
+		evaluator := NewPolicyRuleEvaluator(largePolicy(n))
+		a := &authorizer.AttributesRecord{
+			User:            &user.DefaultInfo{Name: "user-3", Groups: []string{"system:authenticated"}},
+			Verb:            "get",
+			Namespace:       "default",
+			APIGroup:        "apps",
+			APIVersion:      "v1",
+			Resource:        "deployments",
+			Name:            "nginx",
+			ResourceRequest: true,
+		}
+		b.Run(fmt.Sprintf("rules=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				evaluator.EvaluatePolicyRule(a)
+			}
+		})
+	}
+}
+
+func BenchmarkEvaluatePolicyRuleLinear(b *testing.B) {
+	for _, n := range []int{10, 100, 500} {
+		policy := largePolicy(n)
+		a := &authorizer.AttributesRecord{
+			User:            &user.DefaultInfo{Name: "user-3", Groups: []string{"system:authenticated"}},
+			Verb:            "get",
+			Namespace:       "default",
+			APIGroup:        "apps",
+			APIVersion:      "v1",
+			Resource:        "deployments",
+			Name:            "nginx",
+			ResourceRequest: true,
+		}
+		b.Run(fmt.Sprintf("rules=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for j := range policy.Rules {
+					if ruleMatches(&policy.Rules[j], a) {
+						break
+					}
+				}
+			}
+		})
+	}
+}
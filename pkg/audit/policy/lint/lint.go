@@ -0,0 +1,308 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lint analyzes audit policies for rules that are likely mistakes,
+// e.g. rules that never match. Unlike validation, linting never rejects a
+// policy; its findings are meant to be reported as warnings.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/apis/audit"
+)
+
+// FindingType identifies the kind of a finding.
+type FindingType string
+
+const (
+	// FindingTypeShadowedRule is a rule that never matches, as an earlier rule
+	// matches every request it matches.
+	FindingTypeShadowedRule FindingType = "ShadowedRule"
+	// FindingTypeBroadRule is a rule that logs request or response bodies of
+	// requests to all resources.
+	FindingTypeBroadRule FindingType = "BroadRule"
+	// FindingTypeUnknownVerb is a verb that no request has.
+	FindingTypeUnknownVerb FindingType = "UnknownVerb"
+)
+
+// Finding is a likely mistake in an audit policy.
+type Finding struct {
+	// Type is the kind of the finding.
+	Type FindingType
+	// Field is the path of the policy field the finding refers to, e.g. "rules[2].verbs[0]".
+	Field string
+	// Detail describes the finding.
+	Detail string
+}
+
+// String returns the finding in the form "<field>: <detail>".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Field, f.Detail)
+}
+
+// knownVerbs are the verbs of resource requests, and the lowercase HTTP methods
+// that are the verbs of non-resource requests.
+var knownVerbs = sets.NewString(
+	"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection", "proxy",
+	"post", "put", "head", "options",
+)
+
+// Lint analyzes the policy and returns its findings, ordered by rule.
+func Lint(policy *audit.Policy) []Finding {
+	var findings []Finding
+	rulesPath := field.NewPath("rules")
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		rulePath := rulesPath.Index(i)
+
+		for j := 0; j < i; j++ {
+			if covers(&policy.Rules[j], rule) {
+				findings = append(findings, Finding{
+					Type:   FindingTypeShadowedRule,
+					Field:  rulePath.String(),
+					Detail: fmt.Sprintf("rule never matches, as %s matches all of its requests", rulesPath.Index(j)),
+				})
+				break
+			}
+		}
+
+		if isBroad(rule) {
+			findings = append(findings, Finding{
+				Type:   FindingTypeBroadRule,
+				Field:  rulePath.Child("level").String(),
+				Detail: fmt.Sprintf("rule logs %s of requests to all resources, consider restricting it by users, verbs, resources or namespaces", bodies(rule.Level)),
+			})
+		}
+
+		findings = append(findings, unknownVerbs(rule.Verbs, rulePath.Child("verbs"))...)
+		findings = append(findings, unknownVerbs(rule.ExceptVerbs, rulePath.Child("exceptVerbs"))...)
+	}
+	return findings
+}
+
+func unknownVerbs(verbs []string, fldPath *field.Path) []Finding {
+	var findings []Finding
+	for i, verb := range verbs {
+		if knownVerbs.Has(verb) {
+			continue
+		}
+		detail := fmt.Sprintf("no request has the verb %q", verb)
+		if lower := strings.ToLower(verb); lower != verb && knownVerbs.Has(lower) {
+			detail += fmt.Sprintf(", verbs are lowercase, e.g. %q", lower)
+		}
+		findings = append(findings, Finding{
+			Type:   FindingTypeUnknownVerb,
+			Field:  fldPath.Index(i).String(),
+			Detail: detail,
+		})
+	}
+	return findings
+}
+
+func bodies(level audit.Level) string {
+	if level == audit.LevelRequest {
+		return "the request bodies"
+	}
+	return "the request and response bodies"
+}
+
+// isBroad returns whether the rule logs bodies of requests to all resources.
+func isBroad(r *audit.PolicyRule) bool {
+	if r.Level.Less(audit.LevelRequest) || hasCriteria(r) {
+		return false
+	}
+	if len(r.Users) > 0 || len(r.UserGroups) > 0 || len(r.Verbs) > 0 || len(r.Namespaces) > 0 || len(r.NonResourceURLs) > 0 {
+		return false
+	}
+	if len(r.Resources) == 0 {
+		return true
+	}
+	for _, gr := range r.Resources {
+		if gr.Group == "*" && len(gr.ResourceNames) == 0 && (len(gr.Resources) == 0 || hasString(gr.Resources, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCriteria returns whether the rule matches requests by anything but their
+// users, groups, verbs, namespaces, resources and non-resource URLs. Lint only
+// reasons about these, so a rule with other criteria is assumed to match fewer
+// requests than it can tell.
+func hasCriteria(r *audit.PolicyRule) bool {
+	return len(r.ExceptUsers) > 0 || len(r.ExceptUserGroups) > 0 || len(r.ExceptVerbs) > 0 || len(r.ExceptNamespaces) > 0 ||
+		len(r.MatchConditions) > 0 || r.ObjectSelector != nil || r.NamespaceSelector != nil || len(r.ResponseCodes) > 0 ||
+		len(r.SourceIPs) > 0 || len(r.UserAgents) > 0 || r.ActiveWindow != nil || r.DryRun != nil || r.ServerSideApply != nil ||
+		len(r.AuditAnnotations) > 0 || r.Scope == audit.ScopeCluster || r.Scope == audit.ScopeNamespaced
+}
+
+// isResourceRule returns whether the rule only matches resource requests.
+func isResourceRule(r *audit.PolicyRule) bool {
+	return len(r.Namespaces) > 0 || len(r.Resources) > 0 || r.ObjectSelector != nil ||
+		r.NamespaceSelector != nil || r.Scope == audit.ScopeCluster || r.Scope == audit.ScopeNamespaced
+}
+
+// covers returns whether rule a matches every request rule b matches. It may
+// return false for rules it cannot reason about, but never returns true wrongly.
+func covers(a, b *audit.PolicyRule) bool {
+	if hasCriteria(a) {
+		return false
+	}
+	if !patternsCover(a.Users, b.Users) || !patternsCover(a.UserGroups, b.UserGroups) {
+		return false
+	}
+	if len(a.Verbs) > 0 && (len(b.Verbs) == 0 || !sets.NewString(a.Verbs...).HasAll(b.Verbs...)) {
+		return false
+	}
+
+	switch {
+	case isResourceRule(a):
+		if !isResourceRule(b) {
+			return false
+		}
+		if len(a.Namespaces) > 0 && (len(b.Namespaces) == 0 || !sets.NewString(a.Namespaces...).HasAll(b.Namespaces...)) {
+			return false
+		}
+		return groupResourcesCover(a.Resources, b.Resources)
+	case len(a.NonResourceURLs) > 0:
+		if isResourceRule(b) || len(b.NonResourceURLs) == 0 {
+			return false
+		}
+		for _, url := range b.NonResourceURLs {
+			if !anyCovers(a.NonResourceURLs, url, urlCovers) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// patternsCover returns whether the user or group patterns a match every name
+// the patterns b match. No patterns match every name.
+func patternsCover(a, b []string) bool {
+	if len(a) == 0 {
+		return true
+	}
+	if len(b) == 0 {
+		return false
+	}
+	for _, pattern := range b {
+		if !anyCovers(a, pattern, patternCovers) {
+			return false
+		}
+	}
+	return true
+}
+
+// patternCovers returns whether the name pattern a, which may have a trailing
+// "*", matches every name b matches.
+func patternCovers(a, b string) bool {
+	return a == b || strings.HasSuffix(a, "*") && strings.HasPrefix(b, strings.TrimSuffix(a, "*"))
+}
+
+// urlCovers returns whether the non-resource URL a matches every path b matches.
+// Regular expressions are only covered by "*" or themselves.
+func urlCovers(a, b string) bool {
+	if a == "*" || a == b {
+		return true
+	}
+	if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+		return false
+	}
+	return strings.HasSuffix(a, "*") && strings.HasPrefix(strings.TrimRight(b, "*"), strings.TrimRight(a, "*"))
+}
+
+// resourceCovers returns whether the resource a, e.g. "*", "pods", "pods/*" or
+// "*/status", matches every resource b matches.
+func resourceCovers(a, b string) bool {
+	switch {
+	case a == "*" || a == b:
+		return true
+	case strings.HasPrefix(a, "*/"):
+		subresource := strings.TrimPrefix(a, "*")
+		return len(b) > len(subresource) && strings.HasSuffix(b, subresource)
+	case strings.HasSuffix(a, "/*"):
+		resource := strings.TrimSuffix(a, "/*")
+		return b == resource || strings.HasPrefix(b, resource+"/")
+	}
+	return false
+}
+
+// groupResourcesCover returns whether the group resources a match every request
+// the group resources b match. No group resources match every request.
+func groupResourcesCover(a, b []audit.GroupResources) bool {
+	if len(a) == 0 {
+		return true
+	}
+	if len(b) == 0 {
+		return false
+	}
+	for _, gr := range b {
+		covered := false
+		for _, candidate := range a {
+			if groupResourceCovers(candidate, gr) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+func groupResourceCovers(a, b audit.GroupResources) bool {
+	if a.Group != "*" && a.Group != b.Group {
+		return false
+	}
+	if len(a.Resources) == 0 {
+		return true
+	}
+	if len(b.Resources) == 0 {
+		return false
+	}
+	for _, resource := range b.Resources {
+		if !anyCovers(a.Resources, resource, resourceCovers) {
+			return false
+		}
+	}
+	return len(a.ResourceNames) == 0 || len(b.ResourceNames) > 0 && sets.NewString(a.ResourceNames...).HasAll(b.ResourceNames...)
+}
+
+func anyCovers(patterns []string, value string, covers func(a, b string) bool) bool {
+	for _, p := range patterns {
+		if covers(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasString(slice []string, value string) bool {
+	for _, s := range slice {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
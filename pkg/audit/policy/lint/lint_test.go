@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apiserver/pkg/apis/audit"
+)
+
+func TestLint(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rules    []audit.PolicyRule
+		findings []Finding
+	}{
+		"recommended policy": {
+			rules: []audit.PolicyRule{
+				{Level: audit.LevelNone, Users: []string{"system:kube-proxy"}, Verbs: []string{"watch"}},
+				{Level: audit.LevelNone, NonResourceURLs: []string{"/healthz*", "/version"}},
+				{Level: audit.LevelMetadata, Resources: []audit.GroupResources{{Resources: []string{"secrets", "configmaps"}}}},
+				{Level: audit.LevelRequest, Verbs: []string{"get", "list", "watch"}, Resources: []audit.GroupResources{{Group: "*"}}},
+				{Level: audit.LevelRequestResponse, Resources: []audit.GroupResources{{Group: "*", Resources: []string{"*/status"}}}},
+				{Level: audit.LevelMetadata},
+			},
+		},
+		"catch-all shadows everything": {
+			rules: []audit.PolicyRule{
+				{Level: audit.LevelMetadata},
+				{Level: audit.LevelNone, NonResourceURLs: []string{"/healthz*"}},
+			},
+			findings: []Finding{{
+				Type:   FindingTypeShadowedRule,
+				Field:  "rules[1]",
+				Detail: "rule never matches, as rules[0] matches all of its requests",
+			}},
+		},
+		"wildcards": {
+			rules: []audit.PolicyRule{
+				{Level: audit.LevelNone, Users: []string{"system:serviceaccount:*"}, NonResourceURLs: []string{"/apis*"}},
+				{Level: audit.LevelNone, Users: []string{"system:serviceaccount:kube-system:*"}, NonResourceURLs: []string{"/apis/v1*", "/apis"}},
+				{Level: audit.LevelMetadata, Resources: []audit.GroupResources{{Group: "*", Resources: []string{"*/status", "pods/*"}}}},
+				{Level: audit.LevelRequest, Resources: []audit.GroupResources{{Group: "apps", Resources: []string{"deployments/status"}, ResourceNames: []string{"web"}}}},
+				{Level: audit.LevelRequest, Resources: []audit.GroupResources{{Resources: []string{"pods", "pods/log"}}}},
+			},
+			findings: []Finding{{
+				Type:   FindingTypeShadowedRule,
+				Field:  "rules[1]",
+				Detail: "rule never matches, as rules[0] matches all of its requests",
+			}, {
+				Type:   FindingTypeShadowedRule,
+				Field:  "rules[3]",
+				Detail: "rule never matches, as rules[2] matches all of its requests",
+			}, {
+				Type:   FindingTypeShadowedRule,
+				Field:  "rules[4]",
+				Detail: "rule never matches, as rules[2] matches all of its requests",
+			}},
+		},
+		"narrower earlier rules": {
+			rules: []audit.PolicyRule{
+				{Level: audit.LevelNone, Verbs: []string{"get"}, ExceptUsers: []string{"admin"}},
+				{Level: audit.LevelNone, Verbs: []string{"get"}, ResponseCodes: []string{"2xx"}},
+				{Level: audit.LevelNone, Namespaces: []string{"kube-system"}},
+				{Level: audit.LevelNone, NonResourceURLs: []string{"~/apis/.*"}},
+				{Level: audit.LevelMetadata, Verbs: []string{"get", "list"}},
+				{Level: audit.LevelMetadata, NonResourceURLs: []string{"/apis/v1"}},
+				{Level: audit.LevelMetadata, Namespaces: []string{"kube-system", "default"}},
+				{Level: audit.LevelMetadata, Resources: []audit.GroupResources{{Resources: []string{"pods"}}}},
+			},
+		},
+		"broad rules": {
+			rules: []audit.PolicyRule{
+				{Level: audit.LevelRequest, ExceptVerbs: []string{"get"}},
+				{Level: audit.LevelRequestResponse, Resources: []audit.GroupResources{{Group: "*", Resources: []string{"*"}}}},
+				{Level: audit.LevelRequest}, // Not shadowed, as rules[1] only matches resource requests.
+			},
+			findings: []Finding{{
+				Type:   FindingTypeBroadRule,
+				Field:  "rules[1].level",
+				Detail: "rule logs the request and response bodies of requests to all resources, consider restricting it by users, verbs, resources or namespaces",
+			}, {
+				Type:   FindingTypeBroadRule,
+				Field:  "rules[2].level",
+				Detail: "rule logs the request bodies of requests to all resources, consider restricting it by users, verbs, resources or namespaces",
+			}},
+		},
+		"unknown verbs": {
+			rules: []audit.PolicyRule{
+				{Level: audit.LevelNone, Verbs: []string{"get", "GET", "read"}, ExceptVerbs: []string{"deletecollection", "*"}},
+			},
+			findings: []Finding{{
+				Type:   FindingTypeUnknownVerb,
+				Field:  "rules[0].verbs[1]",
+				Detail: `no request has the verb "GET", verbs are lowercase, e.g. "get"`,
+			}, {
+				Type:   FindingTypeUnknownVerb,
+				Field:  "rules[0].verbs[2]",
+				Detail: `no request has the verb "read"`,
+			}, {
+				Type:   FindingTypeUnknownVerb,
+				Field:  "rules[0].exceptVerbs[1]",
+				Detail: `no request has the verb "*"`,
+			}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.findings, Lint(&audit.Policy{Rules: tc.rules}))
+		})
+	}
+}
+
+func TestResourceCovers(t *testing.T) {
+	for _, tc := range []struct {
+		a, b   string
+		covers bool
+	}{
+		{"*", "pods/log", true},
+		{"pods", "pods", true},
+		{"pods", "pods/log", false},
+		{"pods/*", "pods", true},
+		{"pods/*", "pods/log", true},
+		{"pods/*", "podtemplates", false},
+		{"*/status", "deployments/status", true},
+		{"*/status", "*/status", true},
+		{"*/status", "status", false},
+		{"*/status", "*", false},
+	} {
+		assert.Equal(t, tc.covers, resourceCovers(tc.a, tc.b), "%q covers %q", tc.a, tc.b)
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	f := Finding{Type: FindingTypeUnknownVerb, Field: "rules[0].verbs[0]", Detail: `no request has the verb "read"`}
+	assert.Equal(t, `rules[0].verbs[0]: no request has the verb "read"`, f.String())
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package matchconditions compiles and evaluates the CEL match conditions
+// of audit policy rules.
+package matchconditions
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker"
+
+	"k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// RequestVarName is the name of the CEL variable holding the request attributes.
+const RequestVarName = "request"
+
+// estimatedMaxSize is the size assumed for the strings, lists and maps of the request
+// variable when estimating the cost of an expression. Their actual sizes are not bounded,
+// so the estimate is not an upper bound of the cost of evaluating the expression.
+const estimatedMaxSize = 1024
+
+// CostLimits bounds the cost of match conditions. A zero limit is no limit.
+type CostLimits struct {
+	// PerExpression is the maximum estimated cost of an expression. Expressions estimated
+	// to be more expensive fail to compile.
+	PerExpression uint64
+	// PerCall is the maximum cost of evaluating an expression once. Evaluations exceeding
+	// it are aborted with an error.
+	PerCall uint64
+}
+
+var (
+	initEnvOnce sync.Once
+	initEnv     *cel.Env
+	initEnvErr  error
+)
+
+func getEnv() (*cel.Env, error) {
+	initEnvOnce.Do(func() {
+		initEnv, initEnvErr = cel.NewEnv(
+			cel.Variable(RequestVarName, cel.MapType(cel.StringType, cel.DynType)),
+		)
+	})
+	return initEnv, initEnvErr
+}
+
+// Matcher evaluates a list of compiled match conditions against request attributes.
+type Matcher struct {
+	conditions []compiledCondition
+}
+
+type compiledCondition struct {
+	name    string
+	program cel.Program
+}
+
+// Compile compiles the given match conditions. An error is returned if any
+// expression fails to compile or does not evaluate to a bool.
+func Compile(conditions []audit.MatchCondition) (*Matcher, error) {
+	return CompileWithCostLimits(conditions, CostLimits{})
+}
+
+// CompileWithCostLimits compiles the given match conditions like Compile, but
+// additionally fails for expressions exceeding the estimated cost limit and
+// aborts evaluations exceeding the per call cost limit.
+func CompileWithCostLimits(conditions []audit.MatchCondition, limits CostLimits) (*Matcher, error) {
+	env, err := getEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize CEL environment: %v", err)
+	}
+	m := &Matcher{conditions: make([]compiledCondition, 0, len(conditions))}
+	for _, c := range conditions {
+		program, err := compile(env, c.Expression, limits)
+		if err != nil {
+			return nil, fmt.Errorf("match condition %q: %v", c.Name, err)
+		}
+		m.conditions = append(m.conditions, compiledCondition{name: c.Name, program: program})
+	}
+	return m, nil
+}
+
+// CompileExpression compiles a single expression, returning any compilation error.
+func CompileExpression(expression string) error {
+	env, err := getEnv()
+	if err != nil {
+		return fmt.Errorf("failed to initialize CEL environment: %v", err)
+	}
+	_, err = compile(env, expression, CostLimits{})
+	return err
+}
+
+func compile(env *cel.Env, expression string, limits CostLimits) (cel.Program, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("compilation failed: %v", issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression must evaluate to bool, got %v", ast.OutputType())
+	}
+	var opts []cel.ProgramOption
+	if limits.PerExpression > 0 {
+		cost, err := env.EstimateCost(ast, sizeEstimator{})
+		if err != nil {
+			return nil, fmt.Errorf("cost estimation failed: %v", err)
+		}
+		if cost.Max > limits.PerExpression {
+			return nil, fmt.Errorf("estimated cost %d exceeds the limit of %d", cost.Max, limits.PerExpression)
+		}
+	}
+	if limits.PerCall > 0 {
+		opts = append(opts, cel.CostLimit(limits.PerCall))
+	}
+	program, err := env.Program(ast, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("program construction failed: %v", err)
+	}
+	return program, nil
+}
+
+// sizeEstimator estimates the sizes of the fields of the request variable as at most
+// estimatedMaxSize and leaves the cost of function calls to CEL.
+type sizeEstimator struct{}
+
+func (sizeEstimator) EstimateSize(element checker.AstNode) *checker.SizeEstimate {
+	return &checker.SizeEstimate{Min: 0, Max: estimatedMaxSize}
+}
+
+func (sizeEstimator) EstimateCallCost(function, overloadID string, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	return nil
+}
+
+// Match returns true if all match conditions evaluate to true for the given
+// attributes. Evaluation stops at the first condition that evaluates to false
+// or fails to evaluate.
+func (m *Matcher) Match(attrs authorizer.Attributes) (bool, error) {
+	if m == nil || len(m.conditions) == 0 {
+		return true, nil
+	}
+	activation := map[string]interface{}{RequestVarName: requestVar(attrs)}
+	for _, c := range m.conditions {
+		val, _, err := c.program.Eval(activation)
+		if err != nil {
+			return false, fmt.Errorf("match condition %q: %v", c.name, err)
+		}
+		matched, ok := val.Value().(bool)
+		if !ok {
+			return false, fmt.Errorf("match condition %q: expression evaluated to %v, not bool", c.name, val.Type())
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func requestVar(attrs authorizer.Attributes) map[string]interface{} {
+	u := map[string]interface{}{
+		"username": "",
+		"uid":      "",
+		"groups":   []string{},
+		"extra":    map[string][]string{},
+	}
+	if user := attrs.GetUser(); user != nil {
+		u["username"] = user.GetName()
+		u["uid"] = user.GetUID()
+		if groups := user.GetGroups(); groups != nil {
+			u["groups"] = groups
+		}
+		if extra := user.GetExtra(); extra != nil {
+			u["extra"] = extra
+		}
+	}
+	return map[string]interface{}{
+		"verb":            attrs.GetVerb(),
+		"apiGroup":        attrs.GetAPIGroup(),
+		"apiVersion":      attrs.GetAPIVersion(),
+		"resource":        attrs.GetResource(),
+		"subresource":     attrs.GetSubresource(),
+		"namespace":       attrs.GetNamespace(),
+		"name":            attrs.GetName(),
+		"path":            attrs.GetPath(),
+		"resourceRequest": attrs.IsResourceRequest(),
+		"fieldSelector":   fieldSelectorVar(attrs),
+		"labelSelector":   labelSelectorVar(attrs),
+		"user":            u,
+	}
+}
+
+// fieldSelectorVar returns the requirements of the field selector of a request as a list of maps
+// with the keys "key", "operator" and "values", like labelSelectorVar. It is empty if the request
+// has no field selector or it failed to parse.
+func fieldSelectorVar(attrs authorizer.Attributes) []interface{} {
+	requirements, err := attrs.GetFieldSelector()
+	if err != nil {
+		return []interface{}{}
+	}
+	ret := make([]interface{}, 0, len(requirements))
+	for _, r := range requirements {
+		ret = append(ret, map[string]interface{}{
+			"key":      r.Field,
+			"operator": string(r.Operator),
+			"values":   []string{r.Value},
+		})
+	}
+	return ret
+}
+
+// labelSelectorVar returns the requirements of the label selector of a request as a list of maps
+// with the keys "key", "operator" and "values". It is empty if the request has no label selector or
+// it failed to parse.
+func labelSelectorVar(attrs authorizer.Attributes) []interface{} {
+	requirements, err := attrs.GetLabelSelector()
+	if err != nil {
+		return []interface{}{}
+	}
+	ret := make([]interface{}, 0, len(requirements))
+	for _, r := range requirements {
+		values := r.Values().List()
+		if values == nil {
+			values = []string{}
+		}
+		ret = append(ret, map[string]interface{}{
+			"key":      r.Key(),
+			"operator": string(r.Operator()),
+			"values":   values,
+		})
+	}
+	return ret
+}
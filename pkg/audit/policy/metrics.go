@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"strconv"
+	"sync"
+
+	"k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	subsystem = "apiserver_audit"
+
+	// defaultRuleLabel is the rule label value of requests that matched no rule.
+	defaultRuleLabel = "default"
+)
+
+/*
+ * By default, all the following metrics are defined as falling under
+ * ALPHA stability level https://github.com/kubernetes/enhancements/blob/master/keps/sig-instrumentation/1209-metrics-stability/kubernetes-control-plane-metrics-stability.md#stability-classes)
+ *
+ * Promoting the stability level of the metric is a responsibility of the component owner, since it
+ * involves explicitly acknowledging support for the metric across multiple releases, in accordance with
+ * the metric stability policy.
+ */
+var (
+	ruleMatchCounter = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "policy_rule_match_total",
+			Help: "Counter of requests matched by audit policy rules. " +
+				"Rule is the name of the matched rule of the server's policy, or its index if it has no name, " +
+				"or 'default' if no rule matched. " +
+				"Level is the resulting audit level.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"rule", "level"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(ruleMatchCounter)
+}
+
+// levels are the audit levels, which the rule match metric is partitioned by.
+var levels = []audit.Level{audit.LevelNone, audit.LevelMetadata, audit.LevelRequest, audit.LevelRequestResponse}
+
+// ruleMetrics records the decisions of the server's policy in the rule match
+// metric. Once the policy is replaced, its series are deleted, so that the
+// counts of its rules are not attributed to the rules of the new policy.
+type ruleMetrics struct {
+	// rules holds the rule label values, indexed like the rules of the policy.
+	rules []string
+	// lock serializes counting decisions with deleting the series, so that
+	// a decision counted concurrently with stop does not recreate a series.
+	lock sync.RWMutex
+	// stopped is set once the policy has been replaced.
+	stopped bool
+}
+
+// newRuleMetrics creates the counters of the given rules, so that rules that
+// never match are reported with a count of zero.
+func newRuleMetrics(rules []audit.PolicyRule) *ruleMetrics {
+	m := &ruleMetrics{rules: make([]string, len(rules))}
+	for i := range rules {
+		m.rules[i] = rules[i].Name
+		if m.rules[i] == "" {
+			m.rules[i] = strconv.Itoa(i)
+		}
+		ruleMatchCounter.WithLabelValues(m.rules[i], string(rules[i].Level))
+	}
+	return m
+}
+
+// observe counts a policy decision. A negative rule index means no rule matched.
+func (m *ruleMetrics) observe(ruleIndex int, level audit.Level) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.stopped {
+		return
+	}
+	rule := defaultRuleLabel
+	if ruleIndex >= 0 {
+		rule = m.rules[ruleIndex]
+	}
+	ruleMatchCounter.WithLabelValues(rule, string(level)).Inc()
+}
+
+// stop stops counting decisions and deletes the series of the policy. It must
+// be called before the counters of the policy replacing it are created.
+func (m *ruleMetrics) stop() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.stopped = true
+	for _, rule := range append(m.rules, defaultRuleLabel) {
+		for _, level := range levels {
+			ruleMatchCounter.Delete(map[string]string{"rule": rule, "level": string(level)})
+		}
+	}
+}
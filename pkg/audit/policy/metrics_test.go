@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestRuleMatchMetrics(t *testing.T) {
+	ruleMatchCounter.Reset()
+
+	policy := &audit.Policy{Rules: []audit.PolicyRule{rules["getClusterRoles"], rules["getPods"], rules["getLogs"]}}
+	evaluator := NewServerPolicyRuleEvaluator(policy)
+	// evaluators of other policies, e.g. for dry runs, are not counted.
+	NewPolicyRuleEvaluator(policy).EvaluatePolicyRule(attrs["namespaced"])
+	evaluator.EvaluatePolicyRule(attrs["namespaced"])
+	evaluator.EvaluatePolicyRule(attrs["namespaced"])
+	evaluator.EvaluatePolicyRule(attrs["cluster"])
+	evaluator.EvaluatePolicyRule(attrs["subresource"])
+
+	expected := strings.NewReader(`
+		# HELP apiserver_audit_policy_rule_match_total [ALPHA] Counter of requests matched by audit policy rules. Rule is the name of the matched rule of the server's policy, or its index if it has no name, or 'default' if no rule matched. Level is the resulting audit level.
+		# TYPE apiserver_audit_policy_rule_match_total counter
+		apiserver_audit_policy_rule_match_total{level="None",rule="default"} 1
+		apiserver_audit_policy_rule_match_total{level="RequestResponse",rule="0"} 1
+		apiserver_audit_policy_rule_match_total{level="RequestResponse",rule="1"} 2
+		apiserver_audit_policy_rule_match_total{level="RequestResponse",rule="2"} 0
+`)
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, expected, "apiserver_audit_policy_rule_match_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+const namedRulesPolicy = `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+  - name: %s
+    level: Metadata
+    resources:
+      - resources: ["pods"]
+  - level: None
+`
+
+func TestRuleMatchMetricsReload(t *testing.T) {
+	ruleMatchCounter.Reset()
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	replacePolicyFile(t, path, fmt.Sprintf(namedRulesPolicy, "pods"))
+	e, err := NewDynamicPolicyRuleEvaluator(path)
+	require.NoError(t, err)
+	e.EvaluatePolicyRule(attrs["namespaced"])
+	e.EvaluatePolicyRule(attrs["cluster"])
+
+	expected := `
+		# HELP apiserver_audit_policy_rule_match_total [ALPHA] Counter of requests matched by audit policy rules. Rule is the name of the matched rule of the server's policy, or its index if it has no name, or 'default' if no rule matched. Level is the resulting audit level.
+		# TYPE apiserver_audit_policy_rule_match_total counter
+		apiserver_audit_policy_rule_match_total{level="Metadata",rule="pods"} 1
+		apiserver_audit_policy_rule_match_total{level="None",rule="1"} 1
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "apiserver_audit_policy_rule_match_total"); err != nil {
+		t.Error(err)
+	}
+
+	// the series of the replaced policy are dropped, not attributed to the rules of the new one.
+	replacePolicyFile(t, path, fmt.Sprintf(namedRulesPolicy, "all-pods"))
+	require.NoError(t, e.RunOnce(context.Background()))
+	e.EvaluatePolicyRule(attrs["namespaced"])
+
+	expected = `
+		# HELP apiserver_audit_policy_rule_match_total [ALPHA] Counter of requests matched by audit policy rules. Rule is the name of the matched rule of the server's policy, or its index if it has no name, or 'default' if no rule matched. Level is the resulting audit level.
+		# TYPE apiserver_audit_policy_rule_match_total counter
+		apiserver_audit_policy_rule_match_total{level="Metadata",rule="all-pods"} 1
+		apiserver_audit_policy_rule_match_total{level="None",rule="1"} 0
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "apiserver_audit_policy_rule_match_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRuleMatchMetricsConcurrentReload(t *testing.T) {
+	ruleMatchCounter.Reset()
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	replacePolicyFile(t, path, fmt.Sprintf(namedRulesPolicy, "rule-0"))
+	e, err := NewDynamicPolicyRuleEvaluator(path)
+	require.NoError(t, err)
+
+	stopCh := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stopCh:
+					return
+				default:
+					e.EvaluatePolicyRule(attrs["namespaced"])
+				}
+			}
+		}()
+	}
+	for i := 1; i <= 20; i++ {
+		replacePolicyFile(t, path, fmt.Sprintf(namedRulesPolicy, fmt.Sprintf("rule-%d", i)))
+		require.NoError(t, e.RunOnce(context.Background()))
+	}
+	close(stopCh)
+	wg.Wait()
+
+	// decisions of replaced policies counted during the reload must not
+	// recreate their series.
+	replacePolicyFile(t, path, fmt.Sprintf(namedRulesPolicy, "final"))
+	require.NoError(t, e.RunOnce(context.Background()))
+	expected := `
+		# HELP apiserver_audit_policy_rule_match_total [ALPHA] Counter of requests matched by audit policy rules. Rule is the name of the matched rule of the server's policy, or its index if it has no name, or 'default' if no rule matched. Level is the resulting audit level.
+		# TYPE apiserver_audit_policy_rule_match_total counter
+		apiserver_audit_policy_rule_match_total{level="Metadata",rule="final"} 0
+		apiserver_audit_policy_rule_match_total{level="None",rule="1"} 0
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "apiserver_audit_policy_rule_match_total"); err != nil {
+		t.Error(err)
+	}
+}
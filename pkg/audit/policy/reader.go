@@ -19,7 +19,9 @@ package policy
 import (
 	"fmt"
 	"io/ioutil"
+	"reflect"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
@@ -43,6 +45,16 @@ func init() {
 }
 
 func LoadPolicyFromFile(filePath string) (*auditinternal.Policy, error) {
+	return loadPolicyFromFile(filePath, LoadPolicyFromBytes)
+}
+
+// LoadPolicyFromFileStrict is like LoadPolicyFromFile, but rejects policies
+// with unknown or duplicate fields, e.g. a misspelled field name.
+func LoadPolicyFromFileStrict(filePath string) (*auditinternal.Policy, error) {
+	return loadPolicyFromFile(filePath, LoadPolicyFromBytesStrict)
+}
+
+func loadPolicyFromFile(filePath string, load func([]byte) (*auditinternal.Policy, error)) (*auditinternal.Policy, error) {
 	if filePath == "" {
 		return nil, fmt.Errorf("file path not specified")
 	}
@@ -51,7 +63,7 @@ func LoadPolicyFromFile(filePath string) (*auditinternal.Policy, error) {
 		return nil, fmt.Errorf("failed to read file path %q: %+v", filePath, err)
 	}
 
-	ret, err := LoadPolicyFromBytes(policyDef)
+	ret, err := load(policyDef)
 	if err != nil {
 		return nil, fmt.Errorf("%v: from file %v", err.Error(), filePath)
 	}
@@ -59,9 +71,125 @@ func LoadPolicyFromFile(filePath string) (*auditinternal.Policy, error) {
 	return ret, nil
 }
 
+// LoadPolicyFromFiles loads and validates the policies of the given files and
+// concatenates their rules in the order of the files, so that a base policy can be
+// extended by appending policy fragments. The policy-wide settings of the first file
+// apply to the merged policy, those of the other files are folded into their rules.
+// The minimum levels of the merged policy are the highest of all files.
+// A file other than the last one must not contain a rule that matches every
+// request, as it would shadow the rules of the following files.
+func LoadPolicyFromFiles(filePaths []string) (*auditinternal.Policy, error) {
+	if len(filePaths) == 0 {
+		return nil, fmt.Errorf("file path not specified")
+	}
+
+	var merged *auditinternal.Policy
+	var sources []string // the file of each merged rule
+	for i, filePath := range filePaths {
+		p, err := LoadPolicyFromFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if i < len(filePaths)-1 {
+			for j := range p.Rules {
+				if matchesEverything(&p.Rules[j]) {
+					return nil, fmt.Errorf("rule %d of %v matches every request and shadows the rules of %v", j, filePath, filePaths[i+1:])
+				}
+			}
+		}
+		if merged == nil {
+			merged = p
+			for range p.Rules {
+				sources = append(sources, filePath)
+			}
+			continue
+		}
+
+		merged.MinimumLevels = raiseMinimumLevels(merged.MinimumLevels, p.MinimumLevels)
+		for _, rule := range p.Rules {
+			for _, stage := range p.OmitStages {
+				if !hasStage(rule.OmitStages, stage) {
+					rule.OmitStages = append(rule.OmitStages, stage)
+				}
+			}
+			if rule.OmitManagedFields == nil {
+				omitManagedFields := p.OmitManagedFields
+				rule.OmitManagedFields = &omitManagedFields
+			}
+			for k := range merged.Rules {
+				if reflect.DeepEqual(merged.Rules[k], rule) {
+					klog.InfoS("Duplicate audit policy rule", "file", filePath, "rule", len(merged.Rules), "duplicateOfFile", sources[k], "duplicateOfRule", k)
+					break
+				}
+			}
+			merged.Rules = append(merged.Rules, rule)
+			sources = append(sources, filePath)
+		}
+	}
+	return merged, nil
+}
+
+// raiseMinimumLevels returns the higher of the minimum levels of a and b per verb class.
+func raiseMinimumLevels(a, b *auditinternal.MinimumLevels) *auditinternal.MinimumLevels {
+	if b == nil {
+		return a
+	}
+	if a == nil {
+		return b
+	}
+	return &auditinternal.MinimumLevels{
+		Read:  maxLevel(a.Read, b.Read),
+		Write: maxLevel(a.Write, b.Write),
+	}
+}
+
+func maxLevel(a, b auditinternal.Level) auditinternal.Level {
+	if a == "" || a.Less(b) {
+		return b
+	}
+	return a
+}
+
+func hasStage(stages []auditinternal.Stage, stage auditinternal.Stage) bool {
+	for _, s := range stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesEverything returns whether the rule matches every request.
+func matchesEverything(r *auditinternal.PolicyRule) bool {
+	if r.Scope != "" && r.Scope != auditinternal.ScopeAll {
+		return false
+	}
+	return reflect.DeepEqual(*r, auditinternal.PolicyRule{
+		Level:             r.Level,
+		OmitStages:        r.OmitStages,
+		OmitManagedFields: r.OmitManagedFields,
+		Backends:          r.Backends,
+		SamplingRate:      r.SamplingRate,
+		SamplingMode:      r.SamplingMode,
+		RedactFields:      r.RedactFields,
+		ObjectDiff:        r.ObjectDiff,
+		Scope:             r.Scope,
+		Name:              r.Name,
+	})
+}
+
 func LoadPolicyFromBytes(policyDef []byte) (*auditinternal.Policy, error) {
+	return loadPolicyFromBytes(policyDef, audit.Codecs.UniversalDecoder(apiGroupVersions...))
+}
+
+// LoadPolicyFromBytesStrict is like LoadPolicyFromBytes, but rejects policies
+// with unknown or duplicate fields, e.g. a misspelled field name.
+func LoadPolicyFromBytesStrict(policyDef []byte) (*auditinternal.Policy, error) {
+	return loadPolicyFromBytes(policyDef, audit.StrictCodecs.UniversalDecoder(apiGroupVersions...))
+}
+
+func loadPolicyFromBytes(policyDef []byte, decoder runtime.Decoder) (*auditinternal.Policy, error) {
 	policy := &auditinternal.Policy{}
-	decoder := audit.Codecs.UniversalDecoder(apiGroupVersions...)
 
 	_, gvk, err := decoder.Decode(policyDef, nil, policy)
 	if err != nil {
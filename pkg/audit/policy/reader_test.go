@@ -113,6 +113,35 @@ func TestParsePolicyWithNoVersionOrKind(t *testing.T) {
 	assert.Contains(t, err.Error(), "unknown group version field")
 }
 
+func TestLoadPolicyFromBytesStrict(t *testing.T) {
+	policyDef := strings.Replace(policyDefPattern, "{version}", "v1", 1)
+	policy, err := LoadPolicyFromBytesStrict([]byte(policyDef))
+	require.NoError(t, err)
+	assert.Equal(t, expectedPolicy, policy)
+
+	misspelledField := `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+  - level: Metadata
+    omitStage: ["RequestReceived"]
+`
+	_, err = LoadPolicyFromBytes([]byte(misspelledField))
+	assert.NoError(t, err)
+	_, err = LoadPolicyFromBytesStrict([]byte(misspelledField))
+	assert.Error(t, err)
+
+	misspelledStage := `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+  - level: Metadata
+    omitStages: ["RequestRecieved"]
+`
+	_, err = LoadPolicyFromBytesStrict([]byte(misspelledStage))
+	assert.Error(t, err)
+}
+
 func TestPolicyCntCheck(t *testing.T) {
 	var testCases = []struct {
 		caseName, policy string
@@ -135,6 +164,64 @@ kind: Policy`,
 	}
 }
 
+const basePolicy = `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages: ["RequestReceived"]
+minimumLevels:
+  write: Metadata
+rules:
+  - level: None
+    nonResourceURLs: ["/healthz*"]
+  - level: Metadata
+    resources:
+    - resources: ["secrets"]
+`
+
+const fragmentPolicy = `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages: ["ResponseStarted"]
+omitManagedFields: true
+minimumLevels:
+  read: Metadata
+  write: None
+rules:
+  - level: RequestResponse
+    namespaces: ["tenant-a"]
+  - level: Metadata
+`
+
+func TestLoadPolicyFromFiles(t *testing.T) {
+	base, err := writePolicy(t, basePolicy)
+	require.NoError(t, err)
+	defer os.Remove(base)
+	fragment, err := writePolicy(t, fragmentPolicy)
+	require.NoError(t, err)
+	defer os.Remove(fragment)
+
+	policy, err := LoadPolicyFromFiles([]string{base, fragment})
+	require.NoError(t, err)
+
+	omitManagedFields := true
+	assert.Equal(t, []audit.Stage{audit.StageRequestReceived}, policy.OmitStages)
+	assert.False(t, policy.OmitManagedFields)
+	assert.Equal(t, &audit.MinimumLevels{Read: audit.LevelMetadata, Write: audit.LevelMetadata}, policy.MinimumLevels,
+		"the minimum levels must be raised by the fragments, never lowered")
+	assert.Equal(t, []audit.PolicyRule{
+		{Level: audit.LevelNone, NonResourceURLs: []string{"/healthz*"}},
+		{Level: audit.LevelMetadata, Resources: []audit.GroupResources{{Resources: []string{"secrets"}}}},
+		{Level: audit.LevelRequestResponse, Namespaces: []string{"tenant-a"}, OmitStages: []audit.Stage{audit.StageResponseStarted}, OmitManagedFields: &omitManagedFields},
+		{Level: audit.LevelMetadata, OmitStages: []audit.Stage{audit.StageResponseStarted}, OmitManagedFields: &omitManagedFields},
+	}, policy.Rules)
+
+	_, err = LoadPolicyFromFiles([]string{fragment, base})
+	assert.Error(t, err, "a catch-all rule must not shadow the following files")
+
+	_, err = LoadPolicyFromFiles(nil)
+	assert.Error(t, err)
+}
+
 func writePolicy(t *testing.T, policy string) (string, error) {
 	f, err := ioutil.TempFile("", "policy.yaml")
 	require.NoError(t, err)
@@ -0,0 +1,269 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/apis/audit"
+	auditinternal "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/audit/policy/matchconditions"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultTenantPolicyConfigMapName is the default name of the config maps
+	// holding the audit policies of tenants.
+	DefaultTenantPolicyConfigMapName = "audit-policy"
+	// TenantPolicyConfigMapKey is the key of the audit policy in the config maps
+	// holding the audit policies of tenants.
+	TenantPolicyConfigMapKey = "policy.yaml"
+	// DefaultTenantMatchConditionCostLimit is the default estimated and per call
+	// cost limit of the match conditions of the audit policies of tenants.
+	DefaultTenantMatchConditionCostLimit = 1000000
+)
+
+// TenantPolicyConfig configures the audit policies of tenants.
+type TenantPolicyConfig struct {
+	// ConfigMapName is the name of the config maps holding the audit policies of
+	// tenants. Defaults to DefaultTenantPolicyConfigMapName.
+	ConfigMapName string
+	// MaxLevel is the highest level the policy of a tenant may audit requests at.
+	// Defaults to Metadata.
+	MaxLevel audit.Level
+	// NamespaceMaxLevels overrides MaxLevel for the tenants of individual namespaces.
+	NamespaceMaxLevels map[string]audit.Level
+	// MatchConditionCostLimits bounds the cost of the match conditions of the
+	// policies of tenants. Policies with conditions estimated to exceed them are
+	// rejected. Zero limits default to DefaultTenantMatchConditionCostLimit.
+	MatchConditionCostLimits matchconditions.CostLimits
+}
+
+// TenantPolicyRuleEvaluator composes a cluster audit policy with the audit
+// policies of tenants, which are read from config maps in their namespaces. The
+// policy of a tenant only applies to requests in its namespace, and can only
+// raise the level the cluster policy audits a request at, up to the maximum
+// level of the tenant. All other audit configuration of a request, e.g. the
+// stages to omit, is the cluster policy's.
+//
+// A tenant policy that fails to load or validate, or whose match conditions
+// exceed the cost limits, is rejected and the previously loaded policy of the
+// tenant stays in effect.
+type TenantPolicyRuleEvaluator struct {
+	cluster auditinternal.PolicyRuleEvaluator
+	config  TenantPolicyConfig
+
+	// tenants maps namespaces to the *loadedPolicy of their tenant.
+	tenants sync.Map
+
+	// namespaceLister is passed on to the evaluators of loaded policies.
+	namespaceLister corev1listers.NamespaceLister
+}
+
+var _ auditinternal.ObjectPolicyRuleEvaluator = &TenantPolicyRuleEvaluator{}
+var _ WantsNamespaceLister = &TenantPolicyRuleEvaluator{}
+
+// NewTenantPolicyRuleEvaluator creates a policy rule evaluator that composes the
+// cluster policy evaluator with the policies of tenants read from the config maps
+// of the given informer. The informer must be started for tenant policies to load.
+func NewTenantPolicyRuleEvaluator(cluster auditinternal.PolicyRuleEvaluator, config TenantPolicyConfig, configMaps corev1informers.ConfigMapInformer) (*TenantPolicyRuleEvaluator, error) {
+	if config.ConfigMapName == "" {
+		config.ConfigMapName = DefaultTenantPolicyConfigMapName
+	}
+	if config.MaxLevel == "" {
+		config.MaxLevel = audit.LevelMetadata
+	}
+	if config.MatchConditionCostLimits.PerExpression == 0 {
+		config.MatchConditionCostLimits.PerExpression = DefaultTenantMatchConditionCostLimit
+	}
+	if config.MatchConditionCostLimits.PerCall == 0 {
+		config.MatchConditionCostLimits.PerCall = DefaultTenantMatchConditionCostLimit
+	}
+	if !config.MaxLevel.IsKnown() {
+		return nil, fmt.Errorf("invalid maximum tenant audit level %q", config.MaxLevel)
+	}
+	for namespace, level := range config.NamespaceMaxLevels {
+		if !level.IsKnown() {
+			return nil, fmt.Errorf("invalid maximum audit level %q of tenant %q", level, namespace)
+		}
+	}
+
+	e := &TenantPolicyRuleEvaluator{
+		cluster: cluster,
+		config:  config,
+	}
+	_, err := configMaps.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    e.updateTenant,
+		UpdateFunc: func(_, obj interface{}) { e.updateTenant(obj) },
+		DeleteFunc: e.deleteTenant,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch tenant audit policies: %v", err)
+	}
+	return e, nil
+}
+
+// EvaluatePolicyRule evaluates the cluster policy and the policy of the tenant
+// of the request's namespace.
+func (e *TenantPolicyRuleEvaluator) EvaluatePolicyRule(attrs authorizer.Attributes) auditinternal.RequestAuditConfigWithLevel {
+	return e.evaluate(attrs, func(evaluator auditinternal.PolicyRuleEvaluator) auditinternal.RequestAuditConfigWithLevel {
+		return evaluator.EvaluatePolicyRule(attrs)
+	})
+}
+
+// EvaluatePolicyRuleForObject evaluates the cluster policy and the policy of the
+// tenant of the request's namespace against the request object.
+func (e *TenantPolicyRuleEvaluator) EvaluatePolicyRuleForObject(attrs authorizer.Attributes, obj runtime.Object) auditinternal.RequestAuditConfigWithLevel {
+	return e.evaluate(attrs, func(evaluator auditinternal.PolicyRuleEvaluator) auditinternal.RequestAuditConfigWithLevel {
+		if o, ok := evaluator.(auditinternal.ObjectPolicyRuleEvaluator); ok {
+			return o.EvaluatePolicyRuleForObject(attrs, obj)
+		}
+		return evaluator.EvaluatePolicyRule(attrs)
+	})
+}
+
+func (e *TenantPolicyRuleEvaluator) evaluate(attrs authorizer.Attributes, evaluate func(auditinternal.PolicyRuleEvaluator) auditinternal.RequestAuditConfigWithLevel) auditinternal.RequestAuditConfigWithLevel {
+	ls := evaluate(e.cluster)
+	namespace := attrs.GetNamespace()
+	if namespace == "" || !attrs.IsResourceRequest() {
+		return ls
+	}
+	tenant, ok := e.tenants.Load(namespace)
+	if !ok {
+		return ls
+	}
+	return raiseLevel(ls, evaluate(tenant.(*loadedPolicy).evaluator), e.maxLevel(namespace))
+}
+
+// maxLevel returns the highest level the policy of the tenant of the namespace
+// may audit requests at.
+func (e *TenantPolicyRuleEvaluator) maxLevel(namespace string) audit.Level {
+	if level, ok := e.config.NamespaceMaxLevels[namespace]; ok {
+		return level
+	}
+	return e.config.MaxLevel
+}
+
+// raiseLevel raises the level of the cluster configuration to the level of the
+// tenant configuration, capped at maxLevel. Provisional configurations are
+// composed once they are final.
+func raiseLevel(cluster, tenant auditinternal.RequestAuditConfigWithLevel, maxLevel audit.Level) auditinternal.RequestAuditConfigWithLevel {
+	ls := cluster
+	level := tenant.Level
+	if maxLevel.Less(level) {
+		level = maxLevel
+	}
+	if ls.Level.Less(level) {
+		ls.Level = level
+	}
+	ls.DependsOnObject = cluster.DependsOnObject || tenant.DependsOnObject
+	if cluster.EvaluateResponse != nil || tenant.EvaluateResponse != nil {
+		ls.EvaluateResponse = func(responseCode int32, annotations map[string]string) auditinternal.RequestAuditConfigWithLevel {
+			clusterFinal, tenantFinal := cluster, tenant
+			if cluster.EvaluateResponse != nil {
+				clusterFinal = cluster.EvaluateResponse(responseCode, annotations)
+			}
+			if tenant.EvaluateResponse != nil {
+				tenantFinal = tenant.EvaluateResponse(responseCode, annotations)
+			}
+			return raiseLevel(clusterFinal, tenantFinal, maxLevel)
+		}
+	}
+	return ls
+}
+
+// NeedsNamespaceLister returns whether the cluster policy has rules with
+// namespace selectors. Namespace selectors of tenant policies never match
+// unless a lister has been set.
+func (e *TenantPolicyRuleEvaluator) NeedsNamespaceLister() bool {
+	w, ok := e.cluster.(WantsNamespaceLister)
+	return ok && w.NeedsNamespaceLister()
+}
+
+// SetNamespaceLister sets the lister used to evaluate namespace selectors of the
+// cluster policy and the tenant policies. It must be called before the config map
+// informer is started.
+func (e *TenantPolicyRuleEvaluator) SetNamespaceLister(lister corev1listers.NamespaceLister) {
+	e.namespaceLister = lister
+	if w, ok := e.cluster.(WantsNamespaceLister); ok {
+		w.SetNamespaceLister(lister)
+	}
+}
+
+func (e *TenantPolicyRuleEvaluator) updateTenant(obj interface{}) {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok || cm.Name != e.config.ConfigMapName {
+		return
+	}
+	content := []byte(cm.Data[TenantPolicyConfigMapKey])
+	if existing, ok := e.tenants.Load(cm.Namespace); ok && string(existing.(*loadedPolicy).content) == string(content) {
+		return
+	}
+
+	p, err := LoadPolicyFromBytesStrict(content)
+	if err != nil {
+		klog.ErrorS(err, "Rejected tenant audit policy", "configMap", klog.KObj(cm))
+		return
+	}
+	if err := checkMatchConditionCosts(p, e.config.MatchConditionCostLimits); err != nil {
+		klog.ErrorS(err, "Rejected tenant audit policy", "configMap", klog.KObj(cm))
+		return
+	}
+	// tenant policies are not reflected in the rule match metric, whose rules are those of the cluster policy.
+	evaluator := newPolicyRuleEvaluator(p, e.config.MatchConditionCostLimits)
+	if e.namespaceLister != nil {
+		evaluator.SetNamespaceLister(e.namespaceLister)
+	}
+	e.tenants.Store(cm.Namespace, &loadedPolicy{
+		content:   content,
+		evaluator: evaluator,
+	})
+	klog.V(2).InfoS("Loaded tenant audit policy", "configMap", klog.KObj(cm), "rules", len(p.Rules))
+}
+
+// checkMatchConditionCosts returns an error if the match conditions of a rule of
+// the policy exceed the cost limits.
+func checkMatchConditionCosts(p *audit.Policy, limits matchconditions.CostLimits) error {
+	for i, rule := range p.Rules {
+		if len(rule.MatchConditions) == 0 {
+			continue
+		}
+		if _, err := matchconditions.CompileWithCostLimits(rule.MatchConditions, limits); err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (e *TenantPolicyRuleEvaluator) deleteTenant(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok || cm.Name != e.config.ConfigMapName {
+		return
+	}
+	e.tenants.Delete(cm.Namespace)
+	klog.V(2).InfoS("Removed tenant audit policy", "configMap", klog.KObj(cm))
+}
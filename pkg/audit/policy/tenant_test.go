@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/apis/audit"
+	auditinternal "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/audit/policy/matchconditions"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func tenantPolicyConfigMap(namespace, name, level string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data:       map[string]string{TenantPolicyConfigMapKey: policyWithLevel(level)},
+	}
+}
+
+func TestTenantPolicyRuleEvaluator(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		tenantPolicyConfigMap("default", DefaultTenantPolicyConfigMapName, "RequestResponse"),
+		tenantPolicyConfigMap("tenant-a", DefaultTenantPolicyConfigMapName, "RequestResponse"),
+		tenantPolicyConfigMap("tenant-b", "other", "RequestResponse"),
+		tenantPolicyConfigMap("tenant-c", DefaultTenantPolicyConfigMapName, "Bogus"),
+	)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	cluster := NewPolicyRuleEvaluator(&audit.Policy{Rules: []audit.PolicyRule{
+		{Level: audit.LevelRequest, Resources: []audit.GroupResources{{Resources: []string{"secrets"}}}},
+		{Level: audit.LevelNone},
+	}})
+	e, err := NewTenantPolicyRuleEvaluator(cluster, TenantPolicyConfig{
+		NamespaceMaxLevels: map[string]audit.Level{"tenant-a": audit.LevelRequest},
+	}, factory.Core().V1().ConfigMaps())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	request := func(namespace, resource string) authorizer.Attributes {
+		return &authorizer.AttributesRecord{Verb: "get", Namespace: namespace, Resource: resource, ResourceRequest: true}
+	}
+	for name, tc := range map[string]struct {
+		attrs authorizer.Attributes
+		level audit.Level
+	}{
+		"default tenant capped at Metadata":            {request("default", "pods"), audit.LevelMetadata},
+		"tenant with namespace ceiling":                {request("tenant-a", "pods"), audit.LevelRequest},
+		"cluster level is never lowered":               {request("default", "secrets"), audit.LevelRequest},
+		"config map with another name":                 {request("tenant-b", "pods"), audit.LevelNone},
+		"rejected tenant policy":                       {request("tenant-c", "pods"), audit.LevelNone},
+		"cluster-scoped request":                       {request("", "nodes"), audit.LevelNone},
+		"non-resource request":                         {&authorizer.AttributesRecord{Verb: "get", Path: "/healthz"}, audit.LevelNone},
+		"request in namespace without a tenant policy": {request("kube-system", "pods"), audit.LevelNone},
+	} {
+		assert.Equal(t, tc.level, e.EvaluatePolicyRule(tc.attrs).Level, name)
+	}
+
+	_, err = client.CoreV1().ConfigMaps("default").Update(ctx, tenantPolicyConfigMap("default", DefaultTenantPolicyConfigMapName, "Bogus"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, client.CoreV1().ConfigMaps("tenant-a").Delete(ctx, DefaultTenantPolicyConfigMapName, metav1.DeleteOptions{}))
+	err = wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return e.EvaluatePolicyRule(request("tenant-a", "pods")).Level == audit.LevelNone, nil
+	})
+	require.NoError(t, err, "deleted tenant policy must not apply")
+	assert.Equal(t, audit.LevelMetadata, e.EvaluatePolicyRule(request("default", "pods")).Level, "invalid tenant policy must not replace the loaded one")
+}
+
+const tenantPolicyWithConditionPattern = `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+  - level: Metadata
+    matchConditions:
+      - name: condition
+        expression: %q
+`
+
+func TestTenantPolicyMatchConditionCostLimits(t *testing.T) {
+	tenantPolicy := func(namespace, expression string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: DefaultTenantPolicyConfigMapName},
+			Data:       map[string]string{TenantPolicyConfigMapKey: fmt.Sprintf(tenantPolicyWithConditionPattern, expression)},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		tenantPolicy("cheap", `"admins" in request.user.groups`),
+		tenantPolicy("expensive", `request.user.groups.all(a, request.user.groups.all(b, a == b || a != b))`),
+		tenantPolicy("cheap-estimate", `request.user.groups.exists(g, g == "admins")`),
+	)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	cluster := NewPolicyRuleEvaluator(&audit.Policy{Rules: []audit.PolicyRule{{Level: audit.LevelNone}}})
+	e, err := NewTenantPolicyRuleEvaluator(cluster, TenantPolicyConfig{
+		MatchConditionCostLimits: matchconditions.CostLimits{PerCall: 100},
+	}, factory.Core().V1().ConfigMaps())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(DefaultTenantMatchConditionCostLimit), e.config.MatchConditionCostLimits.PerExpression)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	_, loaded := e.tenants.Load("cheap")
+	require.True(t, loaded, "policy within the cost limits must be loaded")
+	_, loaded = e.tenants.Load("expensive")
+	assert.False(t, loaded, "policy exceeding the estimated cost limit must be rejected")
+	tenant, loaded := e.tenants.Load("cheap-estimate")
+	require.True(t, loaded, "policy within the estimated cost limit must be loaded")
+	assert.Nil(t, tenant.(*loadedPolicy).evaluator.(*policyRuleEvaluator).metrics, "tenant policies must not record rule match metrics")
+
+	request := func(namespace string, groups ...string) authorizer.Attributes {
+		return &authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "user", Groups: groups}, Verb: "get", Namespace: namespace, Resource: "pods", ResourceRequest: true}
+	}
+	var manyGroups []string
+	for i := 0; i < 100; i++ {
+		manyGroups = append(manyGroups, fmt.Sprintf("group-%d", i))
+	}
+	assert.Equal(t, audit.LevelMetadata, e.EvaluatePolicyRule(request("cheap-estimate", "admins")).Level)
+	assert.Equal(t, audit.LevelNone, e.EvaluatePolicyRule(request("cheap-estimate", append(manyGroups, "admins")...)).Level, "evaluations exceeding the per call cost limit must not match")
+}
+
+func TestNewTenantPolicyRuleEvaluatorInvalidLevels(t *testing.T) {
+	configMaps := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().ConfigMaps()
+	cluster := NewPolicyRuleEvaluator(&audit.Policy{Rules: []audit.PolicyRule{{Level: audit.LevelNone}}})
+
+	_, err := NewTenantPolicyRuleEvaluator(cluster, TenantPolicyConfig{MaxLevel: "Bogus"}, configMaps)
+	assert.Error(t, err)
+	_, err = NewTenantPolicyRuleEvaluator(cluster, TenantPolicyConfig{NamespaceMaxLevels: map[string]audit.Level{"default": "Bogus"}}, configMaps)
+	assert.Error(t, err)
+}
+
+func TestRaiseLevel(t *testing.T) {
+	cluster := auditinternal.RequestAuditConfigWithLevel{
+		Level: audit.LevelRequestResponse,
+		RequestAuditConfig: auditinternal.RequestAuditConfig{
+			OmitStages: []audit.Stage{audit.StageRequestReceived},
+			EvaluateResponse: func(responseCode int32, _ map[string]string) auditinternal.RequestAuditConfigWithLevel {
+				if responseCode == 403 {
+					return auditinternal.RequestAuditConfigWithLevel{Level: audit.LevelRequestResponse}
+				}
+				return auditinternal.RequestAuditConfigWithLevel{Level: audit.LevelNone}
+			},
+		},
+	}
+	tenant := auditinternal.RequestAuditConfigWithLevel{Level: audit.LevelRequest, DependsOnObject: true}
+
+	ls := raiseLevel(cluster, tenant, audit.LevelMetadata)
+	assert.Equal(t, audit.LevelRequestResponse, ls.Level)
+	assert.Equal(t, []audit.Stage{audit.StageRequestReceived}, ls.OmitStages)
+	assert.True(t, ls.DependsOnObject)
+	require.NotNil(t, ls.EvaluateResponse)
+	assert.Equal(t, audit.LevelRequestResponse, ls.EvaluateResponse(403, nil).Level)
+	final := ls.EvaluateResponse(200, nil)
+	assert.Equal(t, audit.LevelMetadata, final.Level, "the tenant level must be capped")
+	assert.Nil(t, final.EvaluateResponse)
+}
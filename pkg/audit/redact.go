@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RedactedValue replaces the values of redacted fields.
+const RedactedValue = "[REDACTED]"
+
+// wildcardSegment matches every field of an object or every element of a list.
+const wildcardSegment = "*"
+
+// ParseFieldPath parses a field path of a policy rule's RedactFields, e.g.
+// ".data", ".data.*" or ".spec.containers[*].env", into its segments.
+// A leading "$" is allowed.
+func ParseFieldPath(path string) ([]string, error) {
+	rest := strings.TrimPrefix(path, "$")
+	if rest == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	var segments []string
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "[*]"):
+			segments = append(segments, wildcardSegment)
+			rest = rest[len("[*]"):]
+		case rest[0] == '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty field name at %q", "."+rest)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		default:
+			return nil, fmt.Errorf("expected \".<field>\" or \"[*]\" at %q", rest)
+		}
+	}
+	return segments, nil
+}
+
+// redactObject returns a copy of the given JSON object with the values at the given
+// field paths replaced with RedactedValue. If the object is a list, the paths are
+// applied to each of its items. An error is returned if the object is not a JSON object.
+func redactObject(obj *runtime.Unknown, paths []string) (*runtime.Unknown, error) {
+	if obj == nil || len(paths) == 0 {
+		return obj, nil
+	}
+	if obj.ContentType != runtime.ContentTypeJSON {
+		return nil, fmt.Errorf("unsupported content type %q", obj.ContentType)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(obj.Raw))
+	decoder.UseNumber()
+	var value map[string]interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode object: %v", err)
+	}
+
+	var items []interface{}
+	if kind, _ := value["kind"].(string); strings.HasSuffix(kind, "List") {
+		items, _ = value["items"].([]interface{})
+	}
+	for _, path := range paths {
+		segments, err := ParseFieldPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %v", path, err)
+		}
+		redactValue(value, segments)
+		for _, item := range items {
+			redactValue(item, segments)
+		}
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode object: %v", err)
+	}
+	return &runtime.Unknown{
+		Raw:         raw,
+		ContentType: runtime.ContentTypeJSON,
+	}, nil
+}
+
+// redactValue replaces the values at the given path below value in place.
+// Paths that do not exist are ignored.
+func redactValue(value interface{}, segments []string) {
+	if len(segments) == 0 {
+		return
+	}
+	segment, last := segments[0], len(segments) == 1
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key := range v {
+			if segment != wildcardSegment && key != segment {
+				continue
+			}
+			if last {
+				v[key] = RedactedValue
+			} else {
+				redactValue(v[key], segments[1:])
+			}
+		}
+	case []interface{}:
+		if segment != wildcardSegment {
+			return
+		}
+		for i := range v {
+			if last {
+				v[i] = RedactedValue
+			} else {
+				redactValue(v[i], segments[1:])
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		segments []string
+		wantErr  bool
+	}{
+		{path: ".data", segments: []string{"data"}},
+		{path: "$.data.*", segments: []string{"data", "*"}},
+		{path: ".spec.containers[*].env[*].value", segments: []string{"spec", "containers", "*", "env", "*", "value"}},
+		{path: "", wantErr: true},
+		{path: "$", wantErr: true},
+		{path: "data", wantErr: true},
+		{path: ".data.", wantErr: true},
+		{path: ".items[0]", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			segments, err := ParseFieldPath(test.path)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.segments, segments)
+		})
+	}
+}
+
+func TestRedactObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		object   string
+		expected string
+	}{
+		{
+			name:     "field",
+			paths:    []string{".data"},
+			object:   `{"kind":"Secret","data":{"password":"aHVudGVyMg=="},"type":"Opaque"}`,
+			expected: `{"kind":"Secret","data":"[REDACTED]","type":"Opaque"}`,
+		},
+		{
+			name:     "wildcard field keeps the keys",
+			paths:    []string{".data.*", ".stringData.*"},
+			object:   `{"kind":"Secret","data":{"password":"aHVudGVyMg==","user":"YWRtaW4="}}`,
+			expected: `{"kind":"Secret","data":{"password":"[REDACTED]","user":"[REDACTED]"}}`,
+		},
+		{
+			name:     "list elements",
+			paths:    []string{".spec.containers[*].env[*].value"},
+			object:   `{"spec":{"containers":[{"name":"c","env":[{"name":"TOKEN","value":"secret"}]}]}}`,
+			expected: `{"spec":{"containers":[{"name":"c","env":[{"name":"TOKEN","value":"[REDACTED]"}]}]}}`,
+		},
+		{
+			name:     "list items",
+			paths:    []string{".data"},
+			object:   `{"kind":"SecretList","items":[{"data":{"a":"b"}},{"data":{"c":"d"}}]}`,
+			expected: `{"kind":"SecretList","items":[{"data":"[REDACTED]"},{"data":"[REDACTED]"}]}`,
+		},
+		{
+			name:     "missing field",
+			paths:    []string{".data"},
+			object:   `{"kind":"ConfigMap","metadata":{"resourceVersion":"12345678901234567890"}}`,
+			expected: `{"kind":"ConfigMap","metadata":{"resourceVersion":"12345678901234567890"}}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := &runtime.Unknown{Raw: []byte(test.object), ContentType: runtime.ContentTypeJSON}
+			redacted, err := redactObject(obj, test.paths)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(redacted.Raw))
+			assert.Equal(t, test.object, string(obj.Raw), "the original object must not be mutated")
+		})
+	}
+
+	_, err := redactObject(&runtime.Unknown{Raw: []byte(`[{"op":"remove"}]`), ContentType: runtime.ContentTypeJSON}, []string{".data"})
+	assert.Error(t, err)
+	_, err = redactObject(&runtime.Unknown{Raw: []byte{0x0a}, ContentType: runtime.ContentTypeProtobuf}, []string{".data"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+)
+
+// Reader reads the events of an audit log written in the json format, one
+// encoded event per line. Events of any known audit API version are decoded.
+type Reader struct {
+	r       *bufio.Reader
+	decoder runtime.Decoder
+	line    int
+}
+
+// NewReader returns a reader of the events of the audit log read from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		r:       bufio.NewReader(r),
+		decoder: audit.Codecs.UniversalDecoder(),
+	}
+}
+
+// Read returns the next event of the audit log. Empty lines are skipped. It
+// returns io.EOF once all events are read.
+func (r *Reader) Read() (*auditinternal.Event, error) {
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return nil, err
+		}
+		r.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		ev := &auditinternal.Event{}
+		if err := runtime.DecodeInto(r.decoder, line, ev); err != nil {
+			return nil, fmt.Errorf("failed to decode event of line %d: %v", r.line, err)
+		}
+		return ev, nil
+	}
+}
+
+// ReadAll returns the remaining events of the audit log.
+func (r *Reader) ReadAll() ([]*auditinternal.Event, error) {
+	var events []*auditinternal.Event
+	for {
+		ev, err := r.Read()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, ev)
+	}
+}
+
+// ReadFile returns the events of the audit log file at the given path.
+func ReadFile(path string) ([]*auditinternal.Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	events, err := NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %v", path, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+)
+
+func encodeLog(t *testing.T, events ...*auditinternal.Event) []byte {
+	var buf bytes.Buffer
+	encoder := audit.Codecs.LegacyCodec(auditv1.SchemeGroupVersion)
+	for _, ev := range events {
+		require.NoError(t, encoder.Encode(ev, &buf))
+		// Empty lines are skipped.
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+func TestReader(t *testing.T) {
+	log := encodeLog(t,
+		&auditinternal.Event{AuditID: types.UID("1"), Stage: auditinternal.StageRequestReceived},
+		&auditinternal.Event{AuditID: types.UID("1"), Stage: auditinternal.StageResponseComplete},
+	)
+	r := NewReader(bytes.NewReader(log))
+
+	ev, err := r.Read()
+	require.NoError(t, err)
+	assert.Equal(t, auditinternal.StageRequestReceived, ev.Stage)
+	ev, err = r.Read()
+	require.NoError(t, err)
+	assert.Equal(t, auditinternal.StageResponseComplete, ev.Stage)
+	_, err = r.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReaderInvalidLine(t *testing.T) {
+	log := append(encodeLog(t, &auditinternal.Event{AuditID: types.UID("1")}), []byte("not an event\n")...)
+
+	events, err := NewReader(bytes.NewReader(log)).ReadAll()
+	assert.Len(t, events, 1)
+	assert.ErrorContains(t, err, "failed to decode event of line 3")
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	require.NoError(t, os.WriteFile(path, encodeLog(t, &auditinternal.Event{
+		AuditID:       types.UID("1"),
+		RequestObject: &runtime.Unknown{Raw: []byte(`{"kind":"Pod"}`), ContentType: runtime.ContentTypeJSON},
+	}), 0600))
+
+	events, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, types.UID("1"), events[0].AuditID)
+	assert.JSONEq(t, `{"kind":"Pod"}`, string(events[0].RequestObject.Raw))
+
+	_, err = ReadFile(filepath.Join(t.TempDir(), "missing.log"))
+	assert.Error(t, err)
+}
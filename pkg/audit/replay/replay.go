@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replay re-feeds the events of an audit log through audit backends and
+// audit policies, so that backend configurations and policy changes can be
+// regression-tested against captures of real traffic.
+package replay
+
+import (
+	"encoding/json"
+	"net/url"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	netutils "k8s.io/utils/net"
+)
+
+// Config configures a replay.
+type Config struct {
+	// Evaluator, if set, re-evaluates the audit policy for each event. Events
+	// the policy does not audit, or whose stage it omits, are skipped. The
+	// others are replayed at the level of the policy, if lower than the level
+	// they were recorded at, and routed to the backends of the policy rule.
+	Evaluator audit.PolicyRuleEvaluator
+	// BatchSize is the maximum number of events passed to the backend at once.
+	// Defaults to 1.
+	BatchSize int
+}
+
+// Result counts the events of a replay.
+type Result struct {
+	// Replayed is the number of events passed to the backend.
+	Replayed int
+	// Skipped is the number of events skipped by the audit policy.
+	Skipped int
+	// Failed is the number of replayed events of batches the backend failed to process.
+	Failed int
+}
+
+// Replay passes copies of the events to the backend, in order. The backend is
+// not run nor shut down, so that the caller can inspect it in between replays.
+func Replay(backend audit.Backend, events []*auditinternal.Event, config Config) Result {
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	var result Result
+	var batch []*auditinternal.Event
+	var backends []string
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if !processEvents(backend, backends, batch) {
+			result.Failed += len(batch)
+		}
+		result.Replayed += len(batch)
+		batch = nil
+	}
+	for _, ev := range events {
+		ev = ev.DeepCopy()
+		var routedTo []string
+		if config.Evaluator != nil {
+			ac := Evaluate(config.Evaluator, ev)
+			if !Audited(ac, ev.Stage) {
+				result.Skipped++
+				continue
+			}
+			audit.ApplyRequestAuditConfig(ev, ac)
+			routedTo = ac.Backends
+		}
+		// Events routed to different backends are passed in different batches.
+		if len(batch) == batchSize || !equalBackends(backends, routedTo) {
+			flush()
+		}
+		backends = routedTo
+		batch = append(batch, ev)
+	}
+	flush()
+	return result
+}
+
+func processEvents(backend audit.Backend, backends []string, events []*auditinternal.Event) bool {
+	if len(backends) > 0 {
+		if routing, ok := backend.(audit.RoutingSink); ok {
+			return routing.ProcessEventsForBackends(backends, events...)
+		}
+	}
+	return backend.ProcessEvents(events...)
+}
+
+func equalBackends(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate evaluates the audit policy of the evaluator for the request of the
+// event, including the rules matching on the response and, if the event holds
+// the request object, the rules matching on the request object.
+func Evaluate(evaluator audit.PolicyRuleEvaluator, ev *auditinternal.Event) audit.RequestAuditConfigWithLevel {
+	attrs := Attributes(ev)
+	ac := evaluator.EvaluatePolicyRule(attrs)
+	if ac.DependsOnObject {
+		if objectEvaluator, ok := evaluator.(audit.ObjectPolicyRuleEvaluator); ok {
+			if obj := requestObjectMetadata(ev); obj != nil {
+				ac = objectEvaluator.EvaluatePolicyRuleForObject(attrs, obj)
+			}
+		}
+	}
+	if ac.EvaluateResponse != nil && ev.ResponseStatus != nil {
+		ac = ac.EvaluateResponse(ev.ResponseStatus.Code, ev.Annotations)
+	}
+	return ac
+}
+
+// Audited tells whether an event of the given stage is recorded under the
+// audit configuration.
+func Audited(ac audit.RequestAuditConfigWithLevel, stage auditinternal.Stage) bool {
+	if ac.Level == auditinternal.LevelNone {
+		return false
+	}
+	for _, omitted := range ac.OmitStages {
+		if omitted == stage {
+			return false
+		}
+	}
+	return true
+}
+
+// Attributes returns the request attributes the audit policy is evaluated
+// against for the request of the event. The attributes of the request that are
+// not recorded in events are unset, i.e. server-side apply patches are not
+// told apart from other patches.
+func Attributes(ev *auditinternal.Event) *audit.RequestAttributes {
+	attrs := &authorizer.AttributesRecord{
+		User: &user.DefaultInfo{
+			Name:   ev.User.Username,
+			UID:    ev.User.UID,
+			Groups: ev.User.Groups,
+			Extra:  extra(ev.User.Extra),
+		},
+		Verb: ev.Verb,
+	}
+	var dryRun bool
+	if u, err := url.ParseRequestURI(ev.RequestURI); err == nil {
+		attrs.Path = u.Path
+		dryRun = len(u.Query()["dryRun"]) > 0
+	}
+	if ref := ev.ObjectRef; ref != nil {
+		attrs.ResourceRequest = true
+		attrs.APIGroup = ref.APIGroup
+		attrs.APIVersion = ref.APIVersion
+		attrs.Resource = ref.Resource
+		attrs.Subresource = ref.Subresource
+		attrs.Namespace = ref.Namespace
+		attrs.Name = ref.Name
+	}
+	ra := &audit.RequestAttributes{
+		Attributes: attrs,
+		UserAgent:  ev.UserAgent,
+		DryRun:     dryRun,
+	}
+	// The last source IP is the address of the client connection.
+	if n := len(ev.SourceIPs); n > 0 {
+		ra.SourceIP = netutils.ParseIPSloppy(ev.SourceIPs[n-1])
+	}
+	return ra
+}
+
+func extra(values map[string]authnv1.ExtraValue) map[string][]string {
+	if len(values) == 0 {
+		return nil
+	}
+	extra := make(map[string][]string, len(values))
+	for k, v := range values {
+		extra[k] = v
+	}
+	return extra
+}
+
+// requestObjectMetadata returns the metadata of the request object of the
+// event, or nil if the event does not hold a decodable request object.
+func requestObjectMetadata(ev *auditinternal.Event) *metav1.PartialObjectMetadata {
+	if ev.RequestObject == nil || len(ev.RequestObject.Raw) == 0 {
+		return nil
+	}
+	obj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(ev.RequestObject.Raw, obj); err != nil {
+		return nil
+	}
+	return obj
+}
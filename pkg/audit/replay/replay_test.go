@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/audit/policy"
+	"k8s.io/apiserver/plugin/pkg/audit/fake"
+)
+
+func podEvent(id string, stage auditinternal.Stage, verb, username string, code int32) *auditinternal.Event {
+	return &auditinternal.Event{
+		AuditID:    types.UID(id),
+		Level:      auditinternal.LevelRequestResponse,
+		Stage:      stage,
+		Verb:       verb,
+		RequestURI: "/api/v1/namespaces/default/pods/busybox?dryRun=All",
+		User:       authnv1.UserInfo{Username: username, Groups: []string{"humans"}},
+		SourceIPs:  []string{"10.0.0.1", "192.168.0.1"},
+		ObjectRef: &auditinternal.ObjectReference{
+			Resource:   "pods",
+			Namespace:  "default",
+			Name:       "busybox",
+			APIVersion: "v1",
+		},
+		ResponseStatus: &metav1.Status{Code: code},
+		RequestObject: &runtime.Unknown{
+			Raw:         []byte(`{"kind":"Pod","apiVersion":"v1","metadata":{"name":"busybox","labels":{"tier":"control-plane"}}}`),
+			ContentType: runtime.ContentTypeJSON,
+		},
+		ResponseObject: &runtime.Unknown{Raw: []byte(`{"kind":"Pod"}`), ContentType: runtime.ContentTypeJSON},
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	attrs := Attributes(podEvent("1", auditinternal.StageResponseComplete, "update", "tim", 200))
+
+	assert.Equal(t, "tim", attrs.GetUser().GetName())
+	assert.Equal(t, []string{"humans"}, attrs.GetUser().GetGroups())
+	assert.Equal(t, "update", attrs.GetVerb())
+	assert.True(t, attrs.IsResourceRequest())
+	assert.Equal(t, "pods", attrs.GetResource())
+	assert.Equal(t, "default", attrs.GetNamespace())
+	assert.Equal(t, "busybox", attrs.GetName())
+	assert.Equal(t, "/api/v1/namespaces/default/pods/busybox", attrs.GetPath())
+	assert.Equal(t, "192.168.0.1", attrs.SourceIP.String(), "the source IP must be the connection address")
+	assert.True(t, attrs.DryRun)
+}
+
+func TestEvaluate(t *testing.T) {
+	evaluator := policy.NewPolicyRuleEvaluator(&auditinternal.Policy{Rules: []auditinternal.PolicyRule{
+		{Level: auditinternal.LevelRequestResponse, ResponseCodes: []string{"403"}},
+		{
+			Level:          auditinternal.LevelRequest,
+			ObjectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "control-plane"}},
+		},
+		{Level: auditinternal.LevelMetadata},
+	}})
+
+	assert.Equal(t, auditinternal.LevelRequestResponse,
+		Evaluate(evaluator, podEvent("1", auditinternal.StageResponseComplete, "update", "tim", 403)).Level)
+	assert.Equal(t, auditinternal.LevelRequest,
+		Evaluate(evaluator, podEvent("2", auditinternal.StageResponseComplete, "update", "tim", 200)).Level)
+
+	ev := podEvent("3", auditinternal.StageResponseComplete, "update", "tim", 200)
+	ev.RequestObject = nil
+	assert.Equal(t, auditinternal.LevelMetadata, Evaluate(evaluator, ev).Level)
+}
+
+func TestReplay(t *testing.T) {
+	events := []*auditinternal.Event{
+		podEvent("1", auditinternal.StageRequestReceived, "get", "tim", 200),
+		podEvent("1", auditinternal.StageResponseComplete, "get", "tim", 200),
+		podEvent("2", auditinternal.StageRequestReceived, "get", "system:kube-scheduler", 200),
+		podEvent("2", auditinternal.StageResponseComplete, "get", "system:kube-scheduler", 200),
+		podEvent("3", auditinternal.StageResponseComplete, "delete", "tim", 200),
+	}
+
+	var batches [][]*auditinternal.Event
+	backend := &fake.Backend{OnRequest: func(events []*auditinternal.Event) {
+		batches = append(batches, events)
+	}}
+
+	result := Replay(backend, events, Config{BatchSize: 2})
+	assert.Equal(t, Result{Replayed: 5}, result)
+	require.Len(t, batches, 3)
+	assert.NotSame(t, events[0], batches[0][0], "events must be copied")
+
+	batches = nil
+	evaluator := policy.NewPolicyRuleEvaluator(&auditinternal.Policy{
+		OmitStages: []auditinternal.Stage{auditinternal.StageRequestReceived},
+		Rules: []auditinternal.PolicyRule{
+			{Level: auditinternal.LevelNone, Users: []string{"system:kube-scheduler"}},
+			{Level: auditinternal.LevelRequest, Verbs: []string{"delete"}},
+			{Level: auditinternal.LevelMetadata},
+		},
+	})
+	result = Replay(backend, events, Config{Evaluator: evaluator, BatchSize: 10})
+	assert.Equal(t, Result{Replayed: 2, Skipped: 3}, result)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 2)
+
+	get, del := batches[0][0], batches[0][1]
+	assert.Equal(t, types.UID("1"), get.AuditID)
+	assert.Equal(t, auditinternal.LevelMetadata, get.Level)
+	assert.Nil(t, get.RequestObject)
+	assert.Nil(t, get.ResponseObject)
+	assert.Equal(t, types.UID("3"), del.AuditID)
+	assert.Equal(t, auditinternal.LevelRequest, del.Level)
+	assert.NotNil(t, del.RequestObject)
+	assert.Nil(t, del.ResponseObject)
+	assert.Equal(t, auditinternal.LevelRequestResponse, events[4].Level, "replayed events must not be modified")
+}
+
+func TestReplayRoutesEvents(t *testing.T) {
+	routed := map[string]int{}
+	named := func(name string) audit.NamedBackend {
+		return audit.NamedBackend{Name: name, Backend: &fake.Backend{OnRequest: func(events []*auditinternal.Event) {
+			routed[name] += len(events)
+		}}}
+	}
+	backend := audit.RoutedUnion(named("log"), named("webhook"))
+	evaluator := policy.NewPolicyRuleEvaluator(&auditinternal.Policy{Rules: []auditinternal.PolicyRule{
+		{Level: auditinternal.LevelMetadata, Verbs: []string{"delete"}, Backends: []string{"webhook"}},
+		{Level: auditinternal.LevelMetadata},
+	}})
+
+	result := Replay(backend, []*auditinternal.Event{
+		podEvent("1", auditinternal.StageResponseComplete, "get", "tim", 200),
+		podEvent("2", auditinternal.StageResponseComplete, "delete", "tim", 200),
+	}, Config{Evaluator: evaluator, BatchSize: 10})
+	assert.Equal(t, Result{Replayed: 2}, result)
+	assert.Equal(t, map[string]int{"log": 1, "webhook": 2}, routed)
+}
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"mime"
+	"net"
 	"net/http"
 	"reflect"
 	"time"
@@ -36,13 +38,19 @@ import (
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/google/uuid"
 )
 
 const (
 	maxUserAgentLength      = 1024
 	userAgentTruncateSuffix = "...TRUNCATED"
+
+	// PolicyRuleAnnotationKey is the audit annotation holding the name of the
+	// policy rule that determined the audit configuration of the request.
+	PolicyRuleAnnotationKey = "audit.k8s.io/policy-rule"
 )
 
 func NewEventFromRequest(req *http.Request, requestReceivedTimestamp time.Time, level auditinternal.Level, attribs authorizer.Attributes) (*auditinternal.Event, error) {
@@ -59,6 +67,9 @@ func NewEventFromRequest(req *http.Request, requestReceivedTimestamp time.Time,
 		auditID = types.UID(uuid.New().String())
 	}
 	ev.AuditID = auditID
+	if parentAuditID, ok := request.ParentAuditIDFrom(req.Context()); ok {
+		ev.ParentAuditID = parentAuditID
+	}
 
 	ips := utilnet.SourceIPs(req)
 	ev.SourceIPs = make([]string, len(ips))
@@ -92,6 +103,42 @@ func NewEventFromRequest(req *http.Request, requestReceivedTimestamp time.Time,
 	return ev, nil
 }
 
+// NewRequestAttributes returns the attributes of the given request that audit
+// policies are evaluated against. The source IP is the address of the client
+// connection; addresses claimed by the X-Forwarded-For and X-Real-Ip headers
+// are ignored, as clients can set them freely.
+func NewRequestAttributes(req *http.Request, attribs authorizer.Attributes) *RequestAttributes {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return &RequestAttributes{
+		Attributes:      attribs,
+		SourceIP:        netutils.ParseIPSloppy(host),
+		UserAgent:       req.UserAgent(),
+		DryRun:          len(req.URL.Query()["dryRun"]) > 0,
+		ServerSideApply: attribs.GetVerb() == "patch" && contentType == string(types.ApplyPatchType),
+	}
+}
+
+// LogMatchedPolicyRule records the name of the matched policy rule in the
+// annotations of the audit event. Unnamed rules are not recorded. A rule recorded
+// before, e.g. for a provisional configuration, is replaced.
+func LogMatchedPolicyRule(ae *auditinternal.Event, rule *PolicyRuleRef) {
+	if ae == nil {
+		return
+	}
+	if rule == nil || rule.Name == "" {
+		delete(ae.Annotations, PolicyRuleAnnotationKey)
+		return
+	}
+	if ae.Annotations == nil {
+		ae.Annotations = make(map[string]string)
+	}
+	ae.Annotations[PolicyRuleAnnotationKey] = rule.Name
+}
+
 // LogImpersonatedUser fills in the impersonated user attributes into an audit event.
 func LogImpersonatedUser(ae *auditinternal.Event, user user.Info) {
 	if ae == nil || ae.Level.Less(auditinternal.LevelMetadata) {
@@ -108,9 +155,24 @@ func LogImpersonatedUser(ae *auditinternal.Event, user user.Info) {
 	}
 }
 
+// LogFlowControl fills in the API Priority and Fairness classification of the
+// request into an audit event.
+func LogFlowControl(ctx context.Context, flowSchema, priorityLevel string) {
+	ae := AuditEventFrom(ctx)
+	if ae == nil || ae.Level.Less(auditinternal.LevelMetadata) {
+		return
+	}
+	ae.FlowControl = &auditinternal.FlowControl{
+		FlowSchema:    flowSchema,
+		PriorityLevel: priorityLevel,
+	}
+}
+
 // LogRequestObject fills in the request object into an audit event. The passed runtime.Object
 // will be converted to the given gv.
 func LogRequestObject(ctx context.Context, obj runtime.Object, objGV schema.GroupVersion, gvr schema.GroupVersionResource, subresource string, s runtime.NegotiatedSerializer) {
+	evaluateObjectPolicy(ctx, obj)
+
 	ae := AuditEventFrom(ctx)
 	if ae == nil || ae.Level.Less(auditinternal.LevelMetadata) {
 		return
@@ -169,6 +231,94 @@ func LogRequestObject(ctx context.Context, obj runtime.Object, objGV schema.Grou
 		klog.Warningf("Auditing failed of %v request: %v", reflect.TypeOf(obj).Name(), err)
 		return
 	}
+	ae.RequestObject = redactFields(ctx, ae.RequestObject)
+}
+
+// evaluateObjectPolicy finalizes a provisional audit configuration that depends
+// on the request object. The level of the audit event can only be lowered, as the
+// provisional level is the highest level the request may be audited at.
+func evaluateObjectPolicy(ctx context.Context, obj runtime.Object) {
+	ac := AuditContextFrom(ctx)
+	if ac == nil || ac.Event == nil || ac.EvaluateObject == nil {
+		return
+	}
+	ls := ac.EvaluateObject(obj)
+	ac.EvaluateObject = nil
+	applyEvaluatedConfig(ac, ls)
+}
+
+// EvaluateResponsePolicy finalizes a provisional audit configuration that depends
+// on the response code of the request. It must be called once the response code is
+// known, before the ResponseStarted or ResponseComplete stage of the audit event is processed.
+func EvaluateResponsePolicy(ctx context.Context, responseCode int32) {
+	ac := AuditContextFrom(ctx)
+	if ac == nil || ac.Event == nil || ac.RequestAuditConfig.EvaluateResponse == nil {
+		return
+	}
+	ls := ac.RequestAuditConfig.EvaluateResponse(responseCode, auditAnnotationsOf(ctx, ac.Event))
+	ac.EvaluateObject = nil
+	applyEvaluatedConfig(ac, ls)
+}
+
+// auditAnnotationsOf returns a copy of the annotations of the audit event. Audit
+// annotations may be added concurrently, so they are copied under their lock.
+func auditAnnotationsOf(ctx context.Context, ae *auditinternal.Event) map[string]string {
+	if mutex, ok := auditAnnotationsMutex(ctx); ok {
+		mutex.Lock()
+		defer mutex.Unlock()
+	}
+	annotations := make(map[string]string, len(ae.Annotations))
+	for k, v := range ae.Annotations {
+		annotations[k] = v
+	}
+	return annotations
+}
+
+// applyEvaluatedConfig applies a re-evaluated audit configuration to the audit
+// context. The level of the audit event can only be lowered, as provisional levels
+// are the highest level the request may be audited at. Request and response
+// objects logged at the provisional level are dropped if the level is lowered.
+// The stages to omit apply to the stages processed from now on, stages already
+// processed under the provisional configuration are not affected.
+func applyEvaluatedConfig(ac *AuditContext, ls RequestAuditConfigWithLevel) {
+	ac.RequestAuditConfig.OmitStages = ls.OmitStages
+	ac.RequestAuditConfig.OmitManagedFields = ls.OmitManagedFields
+	ac.RequestAuditConfig.Backends = ls.Backends
+	ac.RequestAuditConfig.EvaluateResponse = ls.EvaluateResponse
+	ac.RequestAuditConfig.RedactFields = ls.RedactFields
+	ac.RequestAuditConfig.ObjectDiff = ls.ObjectDiff
+
+	ae := ac.Event
+	LogMatchedPolicyRule(ae, ls.MatchedRule)
+	// Objects logged under the provisional configuration must not escape the
+	// redaction of the final one.
+	ae.RequestObject = redactFieldsOf(ae.RequestObject, ls.RedactFields)
+	ae.ResponseObject = redactFieldsOf(ae.ResponseObject, ls.RedactFields)
+	ae.ObjectDiff = redactFieldsOf(ae.ObjectDiff, ls.RedactFields)
+	ac.oldObject = redactFieldsOf(ac.oldObject, ls.RedactFields)
+	if !ls.ObjectDiff {
+		ac.oldObject = nil
+	}
+	if !ls.Level.Less(ae.Level) {
+		return
+	}
+	ae.Level = ls.Level
+	if ae.Level.Less(auditinternal.LevelRequestResponse) {
+		ae.ResponseObject = nil
+		ae.ObjectDiff = nil
+		ac.oldObject = nil
+	}
+	if ae.Level.Less(auditinternal.LevelRequest) {
+		ae.RequestObject = nil
+	}
+}
+
+// ApplyRequestAuditConfig applies the audit configuration evaluated for a
+// request to an event recorded under another configuration, e.g. when replaying
+// recorded events under a changed audit policy. The level of the event is only
+// lowered, details not recorded under the original configuration are missing.
+func ApplyRequestAuditConfig(ev *auditinternal.Event, config RequestAuditConfigWithLevel) {
+	applyEvaluatedConfig(&AuditContext{Event: ev}, config)
 }
 
 // LogRequestPatch fills in the given patch as the request object into an audit event.
@@ -178,10 +328,10 @@ func LogRequestPatch(ctx context.Context, patch []byte) {
 		return
 	}
 
-	ae.RequestObject = &runtime.Unknown{
+	ae.RequestObject = redactFields(ctx, &runtime.Unknown{
 		Raw:         patch,
 		ContentType: runtime.ContentTypeJSON,
-	}
+	})
 }
 
 // LogResponseObject fills in the response object into an audit event. The passed runtime.Object
@@ -221,7 +371,81 @@ func LogResponseObject(ctx context.Context, obj runtime.Object, gv schema.GroupV
 	ae.ResponseObject, err = encodeObject(obj, gv, s)
 	if err != nil {
 		klog.Warningf("Audit failed for %q response: %v", reflect.TypeOf(obj).Name(), err)
+		return
+	}
+	ae.ResponseObject = redactFields(ctx, ae.ResponseObject)
+	if _, ok := obj.(*metav1.Status); !ok {
+		logObjectDiff(ctx, ae)
+	}
+}
+
+// LogOldObject records the object before an update or patch request, so that the
+// audit event can carry a diff to the response object instead of both full objects
+// if the audit policy asks for it. The passed runtime.Object will be converted to
+// the given gv.
+func LogOldObject(ctx context.Context, obj runtime.Object, gv schema.GroupVersion, s runtime.NegotiatedSerializer) {
+	ac := AuditContextFrom(ctx)
+	if ac == nil || ac.Event == nil || !ac.RequestAuditConfig.ObjectDiff || ac.Event.Level.Less(auditinternal.LevelRequestResponse) {
+		return
+	}
+
+	if ac.RequestAuditConfig.OmitManagedFields {
+		copy, ok, err := copyWithoutManagedFields(obj)
+		if err != nil {
+			klog.Warningf("error while dropping managed fields from the old object for %q error: %v", reflect.TypeOf(obj).Name(), err)
+		}
+		if ok {
+			obj = copy
+		}
+	}
+
+	old, err := encodeObject(obj, gv, s)
+	if err != nil {
+		klog.Warningf("Audit failed for %q old object: %v", reflect.TypeOf(obj).Name(), err)
+		return
+	}
+	ac.oldObject = redactFieldsOf(old, ac.RequestAuditConfig.RedactFields)
+}
+
+// logObjectDiff replaces the request and response objects of the audit event
+// with a JSON merge patch from the recorded old object to the response object.
+// The full objects are kept if there is nothing to diff against.
+func logObjectDiff(ctx context.Context, ae *auditinternal.Event) {
+	ac := AuditContextFrom(ctx)
+	if ac == nil || !ac.RequestAuditConfig.ObjectDiff || ac.oldObject == nil || ae.ResponseObject == nil {
+		return
+	}
+	diff, err := jsonpatch.CreateMergePatch(ac.oldObject.Raw, ae.ResponseObject.Raw)
+	if err != nil {
+		klog.Warningf("Audit failed to diff the %s object: %v", ae.Verb, err)
+		return
+	}
+	ae.ObjectDiff = &runtime.Unknown{
+		Raw:         diff,
+		ContentType: runtime.ContentTypeJSON,
+	}
+	ae.RequestObject = nil
+	ae.ResponseObject = nil
+}
+
+// redactFields redacts the fields of the given encoded object that the audit
+// configuration of the request asks to redact. Objects that cannot be redacted
+// are dropped, so that they are never audited unredacted.
+func redactFields(ctx context.Context, obj *runtime.Unknown) *runtime.Unknown {
+	auditContext := AuditContextFrom(ctx)
+	if auditContext == nil {
+		return obj
+	}
+	return redactFieldsOf(obj, auditContext.RequestAuditConfig.RedactFields)
+}
+
+func redactFieldsOf(obj *runtime.Unknown, paths []string) *runtime.Unknown {
+	redacted, err := redactObject(obj, paths)
+	if err != nil {
+		klog.Warningf("Omitting object from audit event, failed to redact fields %v: %v", paths, err)
+		return nil
 	}
+	return redacted
 }
 
 func encodeObject(obj runtime.Object, gv schema.GroupVersion, serializer runtime.NegotiatedSerializer) (*runtime.Unknown, error) {
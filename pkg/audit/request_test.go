@@ -17,6 +17,7 @@ limitations under the License.
 package audit
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -25,6 +26,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
@@ -226,3 +230,240 @@ func TestCopyWithoutManagedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestLogRequestObjectEvaluatesObjectPolicy(t *testing.T) {
+	ac := &AuditContext{
+		Event: &auditinternal.Event{Level: auditinternal.LevelRequestResponse},
+		EvaluateObject: func(obj runtime.Object) RequestAuditConfigWithLevel {
+			return RequestAuditConfigWithLevel{
+				Level:              auditinternal.LevelMetadata,
+				RequestAuditConfig: RequestAuditConfig{Backends: []string{"log"}},
+			}
+		},
+	}
+	ctx := WithAuditContext(context.Background(), ac)
+
+	LogRequestObject(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}, corev1.SchemeGroupVersion, corev1.SchemeGroupVersion.WithResource("pods"), "", nil)
+
+	assert.Equal(t, auditinternal.LevelMetadata, ac.Event.Level)
+	assert.Equal(t, []string{"log"}, ac.RequestAuditConfig.Backends)
+	assert.Nil(t, ac.EvaluateObject, "the object policy must only be evaluated once")
+	assert.Nil(t, ac.Event.RequestObject, "the request object must not be logged at Metadata level")
+}
+
+func TestEvaluateResponsePolicy(t *testing.T) {
+	ac := &AuditContext{
+		Event: &auditinternal.Event{
+			Level:          auditinternal.LevelRequestResponse,
+			Annotations:    map[string]string{"authorization.k8s.io/decision": "allow"},
+			RequestObject:  &runtime.Unknown{Raw: []byte("{}")},
+			ResponseObject: &runtime.Unknown{Raw: []byte("{}")},
+		},
+		RequestAuditConfig: RequestAuditConfig{
+			EvaluateResponse: func(responseCode int32, annotations map[string]string) RequestAuditConfigWithLevel {
+				assert.Equal(t, int32(200), responseCode)
+				assert.Equal(t, map[string]string{"authorization.k8s.io/decision": "allow"}, annotations)
+				return RequestAuditConfigWithLevel{
+					Level:              auditinternal.LevelRequest,
+					RequestAuditConfig: RequestAuditConfig{OmitStages: []auditinternal.Stage{auditinternal.StageResponseComplete}},
+				}
+			},
+		},
+	}
+	ctx := WithAuditContext(context.Background(), ac)
+
+	EvaluateResponsePolicy(ctx, 200)
+
+	assert.Equal(t, auditinternal.LevelRequest, ac.Event.Level)
+	assert.Equal(t, []auditinternal.Stage{auditinternal.StageResponseComplete}, ac.RequestAuditConfig.OmitStages, "the stages to omit must be those of the final configuration")
+	assert.NotNil(t, ac.Event.RequestObject, "the request object must be kept at Request level")
+	assert.Nil(t, ac.Event.ResponseObject, "the response object must be dropped below RequestResponse level")
+	assert.Nil(t, ac.RequestAuditConfig.EvaluateResponse, "the response policy must only be evaluated once")
+}
+
+func TestLogRequestPatchRedactsFields(t *testing.T) {
+	ac := &AuditContext{
+		Event:              &auditinternal.Event{Level: auditinternal.LevelRequest},
+		RequestAuditConfig: RequestAuditConfig{RedactFields: []string{".data.*"}},
+	}
+	ctx := WithAuditContext(context.Background(), ac)
+
+	LogRequestPatch(ctx, []byte(`{"data":{"password":"aHVudGVyMg=="}}`))
+	assert.JSONEq(t, `{"data":{"password":"[REDACTED]"}}`, string(ac.Event.RequestObject.Raw))
+
+	LogRequestPatch(ctx, []byte(`[{"op":"replace","path":"/data/password","value":"aHVudGVyMg=="}]`))
+	assert.Nil(t, ac.Event.RequestObject, "a patch that cannot be redacted must not be logged")
+}
+
+func TestEvaluateResponsePolicyRedactsFields(t *testing.T) {
+	ac := &AuditContext{
+		Event: &auditinternal.Event{
+			Level:         auditinternal.LevelRequestResponse,
+			RequestObject: &runtime.Unknown{Raw: []byte(`{"data":{"password":"aHVudGVyMg=="}}`), ContentType: runtime.ContentTypeJSON},
+		},
+		RequestAuditConfig: RequestAuditConfig{
+			EvaluateResponse: func(responseCode int32, annotations map[string]string) RequestAuditConfigWithLevel {
+				return RequestAuditConfigWithLevel{
+					Level:              auditinternal.LevelRequestResponse,
+					RequestAuditConfig: RequestAuditConfig{RedactFields: []string{".data"}},
+				}
+			},
+		},
+	}
+	ctx := WithAuditContext(context.Background(), ac)
+
+	EvaluateResponsePolicy(ctx, 200)
+
+	assert.JSONEq(t, `{"data":"[REDACTED]"}`, string(ac.Event.RequestObject.Raw))
+	assert.Equal(t, []string{".data"}, ac.RequestAuditConfig.RedactFields)
+}
+
+func TestLogObjectDiff(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	codecs := serializer.NewCodecFactory(scheme)
+	gv := corev1.SchemeGroupVersion
+
+	oldObj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", UID: "uid"},
+		Data:       map[string]string{"keep": "same", "change": "old", "remove": "gone"},
+	}
+	newObj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", UID: "uid"},
+		Data:       map[string]string{"keep": "same", "change": "new"},
+	}
+
+	for _, tc := range []struct {
+		name         string
+		level        auditinternal.Level
+		objectDiff   bool
+		response     runtime.Object
+		expectedDiff string
+	}{{
+		name:         "diff",
+		level:        auditinternal.LevelRequestResponse,
+		objectDiff:   true,
+		response:     newObj,
+		expectedDiff: `{"data":{"change":"new","remove":null}}`,
+	}, {
+		name:     "disabled",
+		level:    auditinternal.LevelRequestResponse,
+		response: newObj,
+	}, {
+		name:       "request level",
+		level:      auditinternal.LevelRequest,
+		objectDiff: true,
+		response:   newObj,
+	}, {
+		name:       "status response",
+		level:      auditinternal.LevelRequestResponse,
+		objectDiff: true,
+		response:   &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusConflict},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := &AuditContext{
+				Event:              &auditinternal.Event{Level: tc.level},
+				RequestAuditConfig: RequestAuditConfig{ObjectDiff: tc.objectDiff},
+			}
+			ctx := WithAuditContext(context.Background(), ac)
+
+			LogRequestObject(ctx, newObj, gv, gv.WithResource("configmaps"), "", codecs)
+			LogOldObject(ctx, oldObj, gv, codecs)
+			LogResponseObject(ctx, tc.response, gv, codecs)
+
+			if tc.expectedDiff == "" {
+				assert.Nil(t, ac.Event.ObjectDiff)
+				assert.NotNil(t, ac.Event.RequestObject, "the request object must be kept without a diff")
+				return
+			}
+			assert.JSONEq(t, tc.expectedDiff, string(ac.Event.ObjectDiff.Raw))
+			assert.Nil(t, ac.Event.RequestObject, "the request object must be replaced by the diff")
+			assert.Nil(t, ac.Event.ResponseObject, "the response object must be replaced by the diff")
+		})
+	}
+}
+
+func TestLogObjectDiffRedactsFields(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	codecs := serializer.NewCodecFactory(scheme)
+	gv := corev1.SchemeGroupVersion
+
+	ac := &AuditContext{
+		Event:              &auditinternal.Event{Level: auditinternal.LevelRequestResponse},
+		RequestAuditConfig: RequestAuditConfig{ObjectDiff: true, RedactFields: []string{".data.*"}},
+	}
+	ctx := WithAuditContext(context.Background(), ac)
+
+	LogOldObject(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: "1"}, Data: map[string][]byte{"password": []byte("hunter2")}}, gv, codecs)
+	LogResponseObject(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: "2"}, Data: map[string][]byte{"password": []byte("hunter3")}}, gv, codecs)
+
+	assert.JSONEq(t, `{"metadata":{"resourceVersion":"2"}}`, string(ac.Event.ObjectDiff.Raw), "changes of redacted values must not be recorded")
+}
+
+func TestLogMatchedPolicyRule(t *testing.T) {
+	ev := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+	LogMatchedPolicyRule(ev, &PolicyRuleRef{Index: 3})
+	assert.Empty(t, ev.Annotations, "unnamed rules must not be recorded")
+
+	LogMatchedPolicyRule(ev, &PolicyRuleRef{Index: 3, Name: "provisional"})
+	assert.Equal(t, "provisional", ev.Annotations[PolicyRuleAnnotationKey])
+
+	ac := &AuditContext{
+		Event: ev,
+		RequestAuditConfig: RequestAuditConfig{
+			EvaluateResponse: func(responseCode int32, annotations map[string]string) RequestAuditConfigWithLevel {
+				return RequestAuditConfigWithLevel{
+					Level:       auditinternal.LevelMetadata,
+					MatchedRule: &PolicyRuleRef{Index: 4, Name: "final"},
+				}
+			},
+		},
+	}
+	EvaluateResponsePolicy(WithAuditContext(context.Background(), ac), 200)
+	assert.Equal(t, "final", ev.Annotations[PolicyRuleAnnotationKey], "the final rule must replace the provisional one")
+}
+
+func TestNewRequestAttributesRequestKind(t *testing.T) {
+	patch := &authorizer.AttributesRecord{Verb: "patch"}
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/v1/namespaces/default/pods/foo?dryRun=All&fieldManager=kubectl", nil)
+	req.Header.Set("Content-Type", "application/apply-patch+yaml; charset=utf-8")
+	requestAttribs := NewRequestAttributes(req, patch)
+	assert.True(t, requestAttribs.DryRun)
+	assert.True(t, requestAttribs.ServerSideApply)
+
+	req, _ = http.NewRequest(http.MethodPatch, "/api/v1/namespaces/default/pods/foo", nil)
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	requestAttribs = NewRequestAttributes(req, patch)
+	assert.False(t, requestAttribs.DryRun)
+	assert.False(t, requestAttribs.ServerSideApply)
+}
+
+func TestNewRequestAttributes(t *testing.T) {
+	attribs := &authorizer.AttributesRecord{Verb: "get"}
+	for remoteAddr, sourceIP := range map[string]string{
+		"10.0.0.1:6443":  "10.0.0.1",
+		"[fd00::1]:6443": "fd00::1",
+		"10.0.0.2":       "10.0.0.2",
+		"":               "",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "192.168.0.1")
+		req.Header.Set("User-Agent", "kube-scheduler/v1.25.0")
+
+		requestAttribs := NewRequestAttributes(req, attribs)
+		assert.Equal(t, attribs, requestAttribs.Attributes)
+		assert.Equal(t, "kube-scheduler/v1.25.0", requestAttribs.UserAgent)
+		if sourceIP == "" {
+			assert.Nil(t, requestAttribs.SourceIP, "remote address %q", remoteAddr)
+		} else {
+			assert.Equal(t, sourceIP, requestAttribs.SourceIP.String(), "remote address %q", remoteAddr)
+		}
+	}
+}
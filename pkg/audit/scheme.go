@@ -30,6 +30,9 @@ import (
 var Scheme = runtime.NewScheme()
 var Codecs = serializer.NewCodecFactory(Scheme)
 
+// StrictCodecs is like Codecs, but its decoders fail on unknown and duplicate fields.
+var StrictCodecs = serializer.NewCodecFactory(Scheme, serializer.EnableStrict)
+
 func init() {
 	metav1.AddToGroupVersion(Scheme, schema.GroupVersion{Version: "v1"})
 	utilruntime.Must(v1.AddToScheme(Scheme))
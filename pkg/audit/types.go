@@ -17,6 +17,8 @@ limitations under the License.
 package audit
 
 import (
+	"context"
+
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 )
 
@@ -29,6 +31,16 @@ type Sink interface {
 	ProcessEvents(events ...*auditinternal.Event) bool
 }
 
+// RoutingSink is a Sink that is composed of named backends and can deliver
+// events to a subset of them.
+type RoutingSink interface {
+	Sink
+
+	// ProcessEventsForBackends handles events like ProcessEvents, but only delivers
+	// them to the backends with the given names. Unknown names are ignored.
+	ProcessEventsForBackends(backends []string, events ...*auditinternal.Event) bool
+}
+
 type Backend interface {
 	Sink
 
@@ -44,3 +56,20 @@ type Backend interface {
 	// Returns the backend PluginName.
 	String() string
 }
+
+// HealthChecker is implemented by backends that report their health, such as
+// backends failing to deliver events or close to dropping them.
+type HealthChecker interface {
+	// HealthCheck returns an error describing why the backend is unhealthy,
+	// or nil if it is healthy.
+	HealthCheck() error
+}
+
+// GracefulShutdowner is implemented by backends whose shutdown can be bounded
+// in time, such as backends buffering events.
+type GracefulShutdowner interface {
+	// ShutdownWithContext shuts down the backend like Shutdown, but returns
+	// an error once the context is done, even if not all pending events were
+	// delivered.
+	ShutdownWithContext(ctx context.Context) error
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package audit
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -62,6 +63,29 @@ func (u union) Shutdown() {
 	}
 }
 
+// ShutdownWithContext shuts down the backends in turn, as long as the context
+// is not done.
+func (u union) ShutdownWithContext(ctx context.Context) error {
+	var errs []error
+	for _, backend := range u.backends {
+		if err := ShutdownWithContext(ctx, backend); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.NewAggregate(errs)
+}
+
+// HealthCheck reports the backends that are unhealthy.
+func (u union) HealthCheck() error {
+	var errs []error
+	for _, backend := range u.backends {
+		if err := CheckHealth(backend); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", backend, err))
+		}
+	}
+	return errors.NewAggregate(errs)
+}
+
 func (u union) String() string {
 	var backendStrings []string
 	for _, backend := range u.backends {
@@ -69,3 +93,146 @@ func (u union) String() string {
 	}
 	return fmt.Sprintf("union[%s]", strings.Join(backendStrings, ","))
 }
+
+// NamedBackend is a backend together with the name audit policy rules use to
+// route events to it, and the filters selecting the events sent to it in
+// addition to the audit policy.
+type NamedBackend struct {
+	Name    string
+	Backend Backend
+	// OmitStages is the stages of the events that are not sent to the backend,
+	// in addition to the stages omitted by the audit policy.
+	OmitStages []auditinternal.Stage
+	// MaxLevel, if set, is the highest level of the events sent to the backend.
+	// Events of higher levels are sent without the request and response objects
+	// not included at MaxLevel.
+	MaxLevel auditinternal.Level
+	// Verbs, if set, is the verbs of the events sent to the backend.
+	Verbs []string
+	// Namespaces, if set, is the namespaces of the events sent to the backend.
+	// The empty namespace matches the events of cluster-scoped resources and of
+	// non-resource requests.
+	Namespaces []string
+}
+
+// filtered returns whether the backend filters events.
+func (b *NamedBackend) filtered() bool {
+	return len(b.OmitStages) > 0 || b.MaxLevel != "" || len(b.Verbs) > 0 || len(b.Namespaces) > 0
+}
+
+// filter returns the event to send to the backend, or nil if the event is not
+// sent to it. The event is copied if it has to be changed.
+func (b *NamedBackend) filter(event *auditinternal.Event) *auditinternal.Event {
+	if hasStage(b.OmitStages, event.Stage) {
+		return nil
+	}
+	if len(b.Verbs) > 0 && !hasString(b.Verbs, event.Verb) {
+		return nil
+	}
+	if len(b.Namespaces) > 0 {
+		namespace := ""
+		if event.ObjectRef != nil {
+			namespace = event.ObjectRef.Namespace
+		}
+		if !hasString(b.Namespaces, namespace) {
+			return nil
+		}
+	}
+	if b.MaxLevel == "" || !b.MaxLevel.Less(event.Level) {
+		return event
+	}
+	if b.MaxLevel.Less(auditinternal.LevelMetadata) {
+		return nil
+	}
+	e := *event
+	e.Level = b.MaxLevel
+	if b.MaxLevel.Less(auditinternal.LevelRequest) {
+		e.RequestObject = nil
+	}
+	if b.MaxLevel.Less(auditinternal.LevelRequestResponse) {
+		e.ResponseObject = nil
+		e.ObjectDiff = nil
+	}
+	return &e
+}
+
+// RoutedUnion returns an audit Backend which, like Union, logs events to a set of
+// backends, leaving out or reducing the events each backend filters. Additionally
+// it implements RoutingSink, so that events can be delivered to a subset of the
+// backends selected by name.
+func RoutedUnion(backends ...NamedBackend) Backend {
+	u := routedUnion{named: backends}
+	for _, b := range backends {
+		u.backends = append(u.backends, b.Backend)
+	}
+	return u
+}
+
+type routedUnion struct {
+	union
+	named []NamedBackend
+}
+
+var _ RoutingSink = routedUnion{}
+
+func (u routedUnion) ProcessEvents(events ...*auditinternal.Event) bool {
+	success := true
+	for i := range u.backends {
+		success = u.processEvents(i, events) && success
+	}
+	return success
+}
+
+func (u routedUnion) ProcessEventsForBackends(backends []string, events ...*auditinternal.Event) bool {
+	success := true
+	for i := range u.backends {
+		if !hasString(backends, u.named[i].Name) {
+			continue
+		}
+		success = u.processEvents(i, events) && success
+	}
+	return success
+}
+
+// processEvents sends the events the i-th backend does not filter out to it.
+func (u routedUnion) processEvents(i int, events []*auditinternal.Event) bool {
+	b := &u.named[i]
+	if !b.filtered() {
+		return b.Backend.ProcessEvents(events...)
+	}
+	filtered := make([]*auditinternal.Event, 0, len(events))
+	for _, event := range events {
+		if e := b.filter(event); e != nil {
+			filtered = append(filtered, e)
+		}
+	}
+	if len(filtered) == 0 {
+		return true
+	}
+	return b.Backend.ProcessEvents(filtered...)
+}
+
+func (u routedUnion) String() string {
+	if len(u.backends) == 1 {
+		return u.backends[0].String()
+	}
+	return u.union.String()
+}
+
+func hasString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+func hasStage(stages []auditinternal.Stage, stage auditinternal.Stage) bool {
+	for _, s := range stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
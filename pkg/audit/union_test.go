@@ -17,9 +17,14 @@ limitations under the License.
 package audit
 
 import (
+	"context"
+	"fmt"
+	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 )
@@ -113,3 +118,143 @@ func TestUnionRun(t *testing.T) {
 		t.Errorf("union backend run: %v", err)
 	}
 }
+
+func TestRoutedUnion(t *testing.T) {
+	log, webhook := new(fakeBackend), new(fakeBackend)
+	b := RoutedUnion(
+		NamedBackend{Name: "log", Backend: log},
+		NamedBackend{Name: "webhook", Backend: webhook},
+	)
+
+	b.ProcessEvents(&auditinternal.Event{AuditID: "all"})
+	routingSink, ok := b.(RoutingSink)
+	if !ok {
+		t.Fatalf("routed union does not implement RoutingSink")
+	}
+	routingSink.ProcessEventsForBackends([]string{"log"}, &auditinternal.Event{AuditID: "log-only"})
+	routingSink.ProcessEventsForBackends([]string{"unknown"}, &auditinternal.Event{AuditID: "nowhere"})
+
+	if got := len(log.events); got != 2 {
+		t.Errorf("log backend wanted 2 events, got %d", got)
+	}
+	if got := len(webhook.events); got != 1 {
+		t.Errorf("webhook backend wanted 1 event, got %d", got)
+	} else if webhook.events[0].AuditID != "all" {
+		t.Errorf("webhook backend wanted event %q, got %q", "all", webhook.events[0].AuditID)
+	}
+}
+
+func TestRoutedUnionOmitStages(t *testing.T) {
+	log, webhook := new(fakeBackend), new(fakeBackend)
+	b := RoutedUnion(
+		NamedBackend{Name: "log", Backend: log},
+		NamedBackend{Name: "webhook", Backend: webhook, OmitStages: []auditinternal.Stage{auditinternal.StageRequestReceived, auditinternal.StageResponseStarted}},
+	)
+
+	b.ProcessEvents(
+		&auditinternal.Event{Stage: auditinternal.StageRequestReceived},
+		&auditinternal.Event{Stage: auditinternal.StageResponseComplete},
+	)
+	b.(RoutingSink).ProcessEventsForBackends([]string{"log", "webhook"}, &auditinternal.Event{Stage: auditinternal.StageResponseStarted})
+
+	if got := len(log.events); got != 3 {
+		t.Errorf("log backend wanted 3 events, got %d", got)
+	}
+	if got := len(webhook.events); got != 1 {
+		t.Errorf("webhook backend wanted 1 event, got %d", got)
+	} else if webhook.events[0].Stage != auditinternal.StageResponseComplete {
+		t.Errorf("webhook backend wanted a %s event, got %s", auditinternal.StageResponseComplete, webhook.events[0].Stage)
+	}
+}
+
+func TestRoutedUnionFilters(t *testing.T) {
+	log, webhook := new(fakeBackend), new(fakeBackend)
+	b := RoutedUnion(
+		NamedBackend{Name: "log", Backend: log},
+		NamedBackend{
+			Name:       "webhook",
+			Backend:    webhook,
+			MaxLevel:   auditinternal.LevelRequest,
+			Verbs:      []string{"create", "update"},
+			Namespaces: []string{"default", ""},
+		},
+	)
+
+	request := &runtime.Unknown{Raw: []byte("request")}
+	response := &runtime.Unknown{Raw: []byte("response")}
+	diff := &runtime.Unknown{Raw: []byte("diff")}
+	b.ProcessEvents(
+		&auditinternal.Event{AuditID: "get", Verb: "get", Level: auditinternal.LevelMetadata},
+		&auditinternal.Event{AuditID: "create-default", Verb: "create", Level: auditinternal.LevelRequestResponse,
+			ObjectRef: &auditinternal.ObjectReference{Namespace: "default"}, RequestObject: request, ResponseObject: response, ObjectDiff: diff},
+		&auditinternal.Event{AuditID: "create-system", Verb: "create", Level: auditinternal.LevelMetadata,
+			ObjectRef: &auditinternal.ObjectReference{Namespace: "kube-system"}},
+		&auditinternal.Event{AuditID: "update-cluster", Verb: "update", Level: auditinternal.LevelMetadata,
+			ObjectRef: &auditinternal.ObjectReference{Resource: "nodes"}},
+	)
+
+	if got := len(log.events); got != 4 {
+		t.Errorf("log backend wanted 4 events, got %d", got)
+	}
+	var ids []types.UID
+	for _, event := range webhook.events {
+		ids = append(ids, event.AuditID)
+	}
+	if want := []types.UID{"create-default", "update-cluster"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("webhook backend wanted events %v, got %v", want, ids)
+	}
+	if e := webhook.events[0]; e.Level != auditinternal.LevelRequest || e.RequestObject != request || e.ResponseObject != nil || e.ObjectDiff != nil {
+		t.Errorf("webhook backend wanted a %s event with the request object only, got %#v", auditinternal.LevelRequest, e)
+	}
+	if e := log.events[1]; e.Level != auditinternal.LevelRequestResponse || e.ResponseObject != response || e.ObjectDiff != diff {
+		t.Errorf("log backend wanted the %s event unchanged, got %#v", auditinternal.LevelRequestResponse, e)
+	}
+}
+
+type unhealthyBackend struct {
+	fakeBackend
+}
+
+func (b *unhealthyBackend) HealthCheck() error {
+	return fmt.Errorf("unavailable")
+}
+
+func (b *unhealthyBackend) String() string {
+	return "unhealthy"
+}
+
+func TestRoutedUnionHealthCheck(t *testing.T) {
+	healthy := RoutedUnion(NamedBackend{Name: "log", Backend: new(fakeBackend)}, NamedBackend{Name: "webhook", Backend: new(fakeBackend)})
+	if err := CheckHealth(healthy); err != nil {
+		t.Errorf("healthy backends reported unhealthy: %v", err)
+	}
+	unhealthy := RoutedUnion(NamedBackend{Name: "log", Backend: new(fakeBackend)}, NamedBackend{Name: "webhook", Backend: new(unhealthyBackend)})
+	if err := CheckHealth(unhealthy); err == nil || err.Error() != "unhealthy: unavailable" {
+		t.Errorf("unhealthy backend wanted reported, got %v", err)
+	}
+}
+
+type blockingShutdownBackend struct {
+	fakeBackend
+	release chan struct{}
+}
+
+func (b *blockingShutdownBackend) Shutdown() {
+	<-b.release
+}
+
+func TestUnionShutdownWithContext(t *testing.T) {
+	blocking := &blockingShutdownBackend{release: make(chan struct{})}
+	defer close(blocking.release)
+	b := Union(new(fakeBackend), blocking)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := ShutdownWithContext(ctx, b); err == nil {
+		t.Error("expected an error shutting down a blocking backend")
+	}
+
+	if err := ShutdownWithContext(context.Background(), Union(new(fakeBackend), new(fakeBackend))); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// ErrTokenRejected is matched by errors.Is for errors of token authenticators that
+// definitively rejected a token, as opposed to failing to verify it.
+var ErrTokenRejected = errors.New("token rejected")
+
+type tokenRejectedError struct {
+	err error
+}
+
+func (e tokenRejectedError) Error() string        { return e.err.Error() }
+func (e tokenRejectedError) Unwrap() error        { return e.err }
+func (e tokenRejectedError) Is(target error) bool { return target == ErrTokenRejected }
+
+// NewTokenRejectedError marks err as a definitive rejection of the token. The
+// message of err is kept unchanged.
+func NewTokenRejectedError(err error) error {
+	return tokenRejectedError{err: err}
+}
+
+// IsTokenRejected returns true if err marks a definitive rejection of the token.
+// Aggregated errors, as returned by a union of token authenticators, are only
+// considered rejections if all of their errors are.
+func IsTokenRejected(err error) bool {
+	if agg, ok := err.(utilerrors.Aggregate); ok {
+		for _, err := range agg.Errors() {
+			if !IsTokenRejected(err) {
+				return false
+			}
+		}
+		return len(agg.Errors()) > 0
+	}
+	return errors.Is(err, ErrTokenRejected)
+}
+
+// FailureReason is a machine-readable reason for which an authenticator rejected
+// the credentials of a request.
+type FailureReason string
+
+const (
+	// FailureReasonExpired means the credential was valid but has expired.
+	FailureReasonExpired FailureReason = "Expired"
+	// FailureReasonInvalidAudience means the credential was not issued for any of
+	// the audiences accepted by the server.
+	FailureReasonInvalidAudience FailureReason = "InvalidAudience"
+	// FailureReasonUnknownIssuer means the credential was issued by an authority
+	// the server does not trust.
+	FailureReasonUnknownIssuer FailureReason = "UnknownIssuer"
+	// FailureReasonRevoked means the credential has been revoked by its issuer.
+	FailureReasonRevoked FailureReason = "Revoked"
+)
+
+type failureReasonError struct {
+	reason FailureReason
+	err    error
+}
+
+func (e failureReasonError) Error() string        { return e.err.Error() }
+func (e failureReasonError) Unwrap() error        { return e.err }
+func (e failureReasonError) Is(target error) bool { return target == ErrTokenRejected }
+
+// NewFailureReasonError attaches reason to err. The message of err is kept
+// unchanged. Errors with a failure reason are definitive rejections, see
+// IsTokenRejected.
+func NewFailureReasonError(reason FailureReason, err error) error {
+	return failureReasonError{reason: reason, err: err}
+}
+
+// FailureReasonFromError returns the failure reason attached to err, if any. For
+// aggregated errors the reason of the first error carrying one is returned.
+func FailureReasonFromError(err error) (FailureReason, bool) {
+	if agg, ok := err.(utilerrors.Aggregate); ok {
+		for _, err := range agg.Errors() {
+			if reason, ok := FailureReasonFromError(err); ok {
+				return reason, true
+			}
+		}
+		return "", false
+	}
+	var reasonErr failureReasonError
+	if errors.As(err, &reasonErr) {
+		return reasonErr.reason, true
+	}
+	return "", false
+}
+
+type throttledError struct {
+	retryAfter time.Duration
+}
+
+func (e throttledError) Error() string {
+	return fmt.Sprintf("too many failed authentication attempts, retry after %v", e.retryAfter)
+}
+
+// NewThrottledError returns an error refusing to authenticate a request because of too many
+// failed attempts with the same source or credentials, which may be retried after retryAfter.
+// Throttling errors are not rejections of the credentials, see IsTokenRejected.
+func NewThrottledError(retryAfter time.Duration) error {
+	return throttledError{retryAfter: retryAfter}
+}
+
+// ThrottledRetryAfter returns the duration after which a request refused by err may be retried,
+// if err is a throttling error. For aggregated errors the first throttling error is used.
+func ThrottledRetryAfter(err error) (time.Duration, bool) {
+	if agg, ok := err.(utilerrors.Aggregate); ok {
+		for _, err := range agg.Errors() {
+			if retryAfter, ok := ThrottledRetryAfter(err); ok {
+				return retryAfter, true
+			}
+		}
+		return 0, false
+	}
+	var throttledErr throttledError
+	if errors.As(err, &throttledErr) {
+		return throttledErr.retryAfter, true
+	}
+	return 0, false
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+func TestIsTokenRejected(t *testing.T) {
+	rejected := NewTokenRejectedError(errors.New("token expired"))
+	other := errors.New("connection refused")
+
+	if rejected.Error() != "token expired" {
+		t.Errorf("expected the message to be kept, got %q", rejected.Error())
+	}
+	testCases := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{name: "nil", err: nil, expect: false},
+		{name: "rejected", err: rejected, expect: true},
+		{name: "wrapped", err: fmt.Errorf("webhook: %w", rejected), expect: true},
+		{name: "other", err: other, expect: false},
+		{name: "aggregate of rejections", err: utilerrors.NewAggregate([]error{rejected, rejected}), expect: true},
+		{name: "mixed aggregate", err: utilerrors.NewAggregate([]error{rejected, other}), expect: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsTokenRejected(tc.err); got != tc.expect {
+				t.Errorf("expected %v, got %v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestFailureReasonFromError(t *testing.T) {
+	expired := NewFailureReasonError(FailureReasonExpired, errors.New("token is expired"))
+	revoked := NewFailureReasonError(FailureReasonRevoked, errors.New("certificate has been revoked"))
+	other := errors.New("connection refused")
+
+	if expired.Error() != "token is expired" {
+		t.Errorf("expected the message to be kept, got %q", expired.Error())
+	}
+	if !IsTokenRejected(expired) {
+		t.Errorf("expected an error with a failure reason to be a rejection")
+	}
+	testCases := []struct {
+		name         string
+		err          error
+		expectReason FailureReason
+		expectOK     bool
+	}{
+		{name: "nil", err: nil},
+		{name: "other", err: other},
+		{name: "reason", err: expired, expectReason: FailureReasonExpired, expectOK: true},
+		{name: "wrapped", err: fmt.Errorf("oidc: %w", revoked), expectReason: FailureReasonRevoked, expectOK: true},
+		{name: "aggregate", err: utilerrors.NewAggregate([]error{other, revoked, expired}), expectReason: FailureReasonRevoked, expectOK: true},
+		{name: "aggregate without reason", err: utilerrors.NewAggregate([]error{other}), expectOK: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, ok := FailureReasonFromError(tc.err)
+			if reason != tc.expectReason || ok != tc.expectOK {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tc.expectReason, tc.expectOK, reason, ok)
+			}
+		})
+	}
+}
+
+func TestThrottledRetryAfter(t *testing.T) {
+	throttled := NewThrottledError(30 * time.Second)
+	other := errors.New("connection refused")
+
+	if IsTokenRejected(throttled) {
+		t.Errorf("expected a throttling error not to be a rejection")
+	}
+	testCases := []struct {
+		name             string
+		err              error
+		expectRetryAfter time.Duration
+		expectOK         bool
+	}{
+		{name: "nil", err: nil},
+		{name: "other", err: other},
+		{name: "throttled", err: throttled, expectRetryAfter: 30 * time.Second, expectOK: true},
+		{name: "aggregate", err: utilerrors.NewAggregate([]error{other, throttled}), expectRetryAfter: 30 * time.Second, expectOK: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retryAfter, ok := ThrottledRetryAfter(tc.err)
+			if retryAfter != tc.expectRetryAfter || ok != tc.expectOK {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tc.expectRetryAfter, tc.expectOK, retryAfter, ok)
+			}
+		})
+	}
+}
@@ -18,32 +18,106 @@ package authenticatorfactory
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/group"
 	"k8s.io/apiserver/pkg/authentication/request/anonymous"
 	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
 	"k8s.io/apiserver/pkg/authentication/request/headerrequest"
+	"k8s.io/apiserver/pkg/authentication/request/normalize"
+	"k8s.io/apiserver/pkg/authentication/request/spiffe"
+	"k8s.io/apiserver/pkg/authentication/request/throttle"
 	unionauth "k8s.io/apiserver/pkg/authentication/request/union"
 	"k8s.io/apiserver/pkg/authentication/request/websocket"
 	"k8s.io/apiserver/pkg/authentication/request/x509"
+	"k8s.io/apiserver/pkg/authentication/token/binding"
 	"k8s.io/apiserver/pkg/authentication/token/cache"
+	"k8s.io/apiserver/pkg/authentication/token/shape"
+	tokenunion "k8s.io/apiserver/pkg/authentication/token/union"
 	"k8s.io/apiserver/pkg/server/dynamiccertificates"
 	webhooktoken "k8s.io/apiserver/plugin/pkg/authenticator/token/webhook"
 	authenticationclient "k8s.io/client-go/kubernetes/typed/authentication/v1"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
+// negativeCacheMaxEntries bounds the number of remembered token rejections, so that
+// clients sending many distinct invalid tokens cannot grow the cache unboundedly.
+const negativeCacheMaxEntries = 4096
+
+// throttleMaxEntries bounds the number of sources and credentials whose failed authentication attempts are remembered.
+const throttleMaxEntries = 16384
+
+// groupResolverCacheMaxEntries bounds the number of users whose resolved groups are remembered.
+const groupResolverCacheMaxEntries = 4096
+
+// The names of the authentication mechanisms, by which their order and short-circuiting are configured.
+const (
+	MechanismRequestHeader = "requestheader"
+	MechanismSPIFFE        = "spiffe"
+	MechanismX509          = "x509"
+	MechanismBearerToken   = "bearertoken"
+	MechanismWebSocket     = "websocket"
+)
+
+// DefaultMechanismOrder is the order in which the authentication mechanisms are tried, unless
+// configured otherwise: front-proxy first, then client certificates, then remote.
+var DefaultMechanismOrder = []string{MechanismRequestHeader, MechanismSPIFFE, MechanismX509, MechanismBearerToken, MechanismWebSocket}
+
+// ValidateMechanisms returns an error if names contains an unknown or a repeated authentication mechanism.
+func ValidateMechanisms(names []string) error {
+	known := sets.NewString(DefaultMechanismOrder...)
+	seen := sets.NewString()
+	for _, name := range names {
+		if !known.Has(name) {
+			return fmt.Errorf("unknown authentication mechanism %q, must be one of %v", name, DefaultMechanismOrder)
+		}
+		if seen.Has(name) {
+			return fmt.Errorf("authentication mechanism %q is listed more than once", name)
+		}
+		seen.Insert(name)
+	}
+	return nil
+}
+
 // DelegatingAuthenticatorConfig is the minimal configuration needed to create an authenticator
 // built to delegate authentication to a kube API server
 type DelegatingAuthenticatorConfig struct {
 	Anonymous bool
 
+	// AnonymousPaths restricts anonymous authentication to the listed request paths when
+	// Anonymous is true. A path ending in "*" is a prefix match. If empty, anonymous
+	// authentication applies to every path.
+	AnonymousPaths []string
+
+	// AnonymousIdentities are the identities of anonymous requests to specific paths, which are
+	// otherwise authenticated as system:anonymous. They do not extend AnonymousPaths.
+	AnonymousIdentities []anonymous.PathIdentity
+
 	// TokenAccessReviewClient is a client to do token review. It can be nil. Then every token is ignored.
 	TokenAccessReviewClient authenticationclient.AuthenticationV1Interface
 
+	// TokenAccessReviewFailoverClients are clients to further token review endpoints. They are
+	// tried in order when the request to TokenAccessReviewClient fails.
+	TokenAccessReviewFailoverClients []authenticationclient.AuthenticationV1Interface
+
+	// WebhookCircuitBreaker configures the circuit breaker that skips token review endpoints
+	// after repeated failures. If nil, every request is sent to the endpoints.
+	WebhookCircuitBreaker *webhooktoken.CircuitBreakerConfig
+
+	// TokenCertificateBinding, if true, only accepts bearer tokens bound to a client certificate
+	// from clients presenting that certificate over TLS.
+	TokenCertificateBinding bool
+
+	// WebhookTokenShape, if set, rejects tokens that are not of the given shape locally, before
+	// they are sent for token review. If nil, every token is sent.
+	WebhookTokenShape *shape.Config
+
 	// TokenAccessReviewTimeout specifies a time limit for requests made by the authorization webhook client.
 	TokenAccessReviewTimeout time.Duration
 
@@ -55,51 +129,230 @@ type DelegatingAuthenticatorConfig struct {
 	// CacheTTL is the length of time that a token authentication answer will be cached.
 	CacheTTL time.Duration
 
+	// CacheFailureTTL is the length of time that a failed token authentication answer will be cached.
+	// If zero, CacheTTL is used.
+	CacheFailureTTL time.Duration
+
+	// CacheMaxEntries bounds the number of cached token authentication answers. Zero means unbounded.
+	CacheMaxEntries int
+
+	// CacheJitter is the maximum fraction by which the TTL of each cached answer is randomly shortened.
+	CacheJitter float64
+
+	// NegativeCacheTTL is the length of time that tokens rejected by all token authenticators
+	// are rejected again without consulting them. Zero disables the negative cache.
+	NegativeCacheTTL time.Duration
+
 	// CAContentProvider are the options for verifying incoming connections using mTLS and directly assigning to users.
 	// Generally this is the CA bundle file used to authenticate client certificates
 	// If this is nil, then mTLS will not be used.
 	ClientCertificateCAContentProvider dynamiccertificates.CAContentProvider
 
+	// ClientCertificateRevocationChecker, if set, rejects client certificates that have been revoked.
+	ClientCertificateRevocationChecker x509.RevocationChecker
+
+	// ClientCertificateExpirationWarningThreshold, if positive, is the remaining lifetime of client
+	// certificates below which their clients are sent a warning.
+	ClientCertificateExpirationWarningThreshold time.Duration
+
+	// ClientCertificateUserConversion builds the user of a verified client certificate.
+	// If nil, x509.CommonNameUserConversion is used.
+	ClientCertificateUserConversion x509.UserConversion
+
+	// SPIFFE, if set, configures the authentication of SPIFFE X.509 SVIDs presented as client certificates.
+	SPIFFE *spiffe.Config
+
 	APIAudiences authenticator.Audiences
 
+	// TokenFileAuthenticator authenticates bearer tokens listed in a static token file before
+	// any other token authenticator. It is typically a tokenfile.DynamicTokenFile. It can be nil.
+	TokenFileAuthenticator authenticator.Token
+
+	// JWTAuthenticator authenticates bearer tokens locally before they are sent for token review.
+	// It is typically a DynamicJWTAuthenticator. It can be nil.
+	JWTAuthenticator authenticator.Token
+
+	// AuthenticationProvider authenticates bearer tokens with an external authentication provider
+	// before they are sent for token review. Its answers are cached like those of the token review.
+	// It is typically a grpcprovider.Authenticator. It can be nil.
+	AuthenticationProvider authenticator.Token
+
+	// ExecAuthenticator authenticates bearer tokens by running an external command before they are
+	// sent for token review. Its answers are cached like those of the token review.
+	// It is typically an exec.Authenticator. It can be nil.
+	ExecAuthenticator authenticator.Token
+
 	RequestHeaderConfig *RequestHeaderConfig
+
+	// GroupResolver, if set, resolves additional groups of users authenticated by any
+	// authenticator but the anonymous one. It can be nil.
+	GroupResolver group.GroupResolver
+
+	// GroupResolverCacheTTL is the length of time that the groups resolved for a user will be cached.
+	// Zero disables the cache.
+	GroupResolverCacheTTL time.Duration
+
+	// TracerProvider, if set, records a span for each authenticator tried for a request.
+	TracerProvider oteltrace.TracerProvider
+
+	// Throttle, if set, refuses requests of sources or credentials whose bearer tokens were rejected
+	// too often, without consulting the token authenticators.
+	Throttle *throttle.Config
+
+	// UserNormalization, if set, normalizes the users authenticated by each mechanism.
+	UserNormalization *normalize.Config
+
+	// UntrustedMechanisms lists authentication mechanisms not trusted to authenticate the users and
+	// groups reserved for the system. Their users with a name or group starting with "system:" are rejected.
+	UntrustedMechanisms []string
+
+	// MechanismOrder lists authentication mechanisms to try before the others, which follow in
+	// DefaultMechanismOrder. Anonymous authentication is always tried last.
+	MechanismOrder []string
+
+	// ShortCircuitMechanisms lists authentication mechanisms whose failures are definitive: if one of them
+	// finds credentials in a request but fails to authenticate them, the remaining mechanisms are not tried.
+	ShortCircuitMechanisms []string
 }
 
 func (c DelegatingAuthenticatorConfig) New() (authenticator.Request, *spec.SecurityDefinitions, error) {
-	authenticators := []authenticator.Request{}
+	mechanisms := map[string]authenticator.Request{}
 	securityDefinitions := spec.SecurityDefinitions{}
 
-	// front-proxy first, then remote
 	// Add the front proxy authenticator if requested
 	if c.RequestHeaderConfig != nil {
-		requestHeaderAuthenticator := headerrequest.NewDynamicVerifyOptionsSecure(
-			c.RequestHeaderConfig.CAContentProvider.VerifyOptions,
-			c.RequestHeaderConfig.AllowedClientNames,
-			c.RequestHeaderConfig.UsernameHeaders,
-			c.RequestHeaderConfig.GroupHeaders,
-			c.RequestHeaderConfig.ExtraHeaderPrefixes,
-		)
-		authenticators = append(authenticators, requestHeaderAuthenticator)
+		var requestHeaderAuthenticator authenticator.Request
+		if len(c.RequestHeaderConfig.ProxyIdentityScopes) > 0 {
+			requestHeaderAuthenticator = headerrequest.NewDynamicVerifyOptionsSecureScoped(
+				c.RequestHeaderConfig.CAContentProvider.VerifyOptions,
+				c.RequestHeaderConfig.AllowedClientNames,
+				c.RequestHeaderConfig.UsernameHeaders,
+				c.RequestHeaderConfig.GroupHeaders,
+				c.RequestHeaderConfig.ExtraHeaderPrefixes,
+				c.RequestHeaderConfig.ProxyIdentityScopes,
+			)
+		} else {
+			requestHeaderAuthenticator = headerrequest.NewDynamicVerifyOptionsSecure(
+				c.RequestHeaderConfig.CAContentProvider.VerifyOptions,
+				c.RequestHeaderConfig.AllowedClientNames,
+				c.RequestHeaderConfig.UsernameHeaders,
+				c.RequestHeaderConfig.GroupHeaders,
+				c.RequestHeaderConfig.ExtraHeaderPrefixes,
+			)
+		}
+		mechanisms[MechanismRequestHeader] = requestHeaderAuthenticator
+	}
+
+	// SPIFFE SVIDs are mapped before generic client certificates
+	if c.SPIFFE != nil {
+		spiffeAuthenticator, err := spiffe.New(*c.SPIFFE)
+		if err != nil {
+			return nil, nil, err
+		}
+		mechanisms[MechanismSPIFFE] = spiffeAuthenticator
 	}
 
 	// x509 client cert auth
 	if c.ClientCertificateCAContentProvider != nil {
-		authenticators = append(authenticators, x509.NewDynamic(c.ClientCertificateCAContentProvider.VerifyOptions, x509.CommonNameUserConversion))
+		userConversion := c.ClientCertificateUserConversion
+		if userConversion == nil {
+			userConversion = x509.CommonNameUserConversion
+		}
+		certAuth := x509.NewDynamic(c.ClientCertificateCAContentProvider.VerifyOptions, userConversion)
+		if c.ClientCertificateRevocationChecker != nil {
+			certAuth = x509.NewDynamicWithRevocation(c.ClientCertificateCAContentProvider.VerifyOptions, userConversion, c.ClientCertificateRevocationChecker)
+		}
+		certAuth.WarnOnExpiration(c.ClientCertificateExpirationWarningThreshold)
+		mechanisms[MechanismX509] = certAuth
+	}
+
+	tokenAuthenticators := []authenticator.Token{}
+	// static tokens are looked up in memory, so they are neither cached nor sent anywhere.
+	if c.TokenFileAuthenticator != nil {
+		tokenAuthenticators = append(tokenAuthenticators, c.TokenFileAuthenticator)
+	}
+	// JWTs are verified locally first and are not cached, so that issuers
+	// removed from the configuration stop being trusted immediately.
+	if c.JWTAuthenticator != nil {
+		tokenAuthenticators = append(tokenAuthenticators, c.JWTAuthenticator)
+	}
+
+	failureTTL := c.CacheFailureTTL
+	if failureTTL == 0 {
+		failureTTL = c.CacheTTL
+	}
+	cacheConfig := cache.Config{
+		SuccessTTL:   c.CacheTTL,
+		FailureTTL:   failureTTL,
+		MaxEntries:   c.CacheMaxEntries,
+		Jitter:       c.CacheJitter,
+		APIAudiences: c.APIAudiences,
+	}
+
+	if c.AuthenticationProvider != nil {
+		tokenAuthenticators = append(tokenAuthenticators, cache.NewWithConfig(c.AuthenticationProvider, cacheConfig))
+	}
+
+	if c.ExecAuthenticator != nil {
+		tokenAuthenticators = append(tokenAuthenticators, cache.NewWithConfig(c.ExecAuthenticator, cacheConfig))
 	}
 
 	if c.TokenAccessReviewClient != nil {
 		if c.WebhookRetryBackoff == nil {
 			return nil, nil, errors.New("retry backoff parameters for delegating authentication webhook has not been specified")
 		}
-		tokenAuth, err := webhooktoken.NewFromInterface(c.TokenAccessReviewClient, c.APIAudiences, *c.WebhookRetryBackoff, c.TokenAccessReviewTimeout, webhooktoken.AuthenticatorMetrics{
+		metrics := webhooktoken.AuthenticatorMetrics{
 			RecordRequestTotal:   RecordRequestTotal,
 			RecordRequestLatency: RecordRequestLatency,
-		})
+		}
+		var (
+			tokenAuth *webhooktoken.WebhookTokenAuthenticator
+			err       error
+		)
+		if len(c.TokenAccessReviewFailoverClients) > 0 || c.WebhookCircuitBreaker != nil {
+			var breaker webhooktoken.CircuitBreakerConfig
+			if c.WebhookCircuitBreaker != nil {
+				breaker = *c.WebhookCircuitBreaker
+			}
+			clients := append([]authenticationclient.AuthenticationV1Interface{c.TokenAccessReviewClient}, c.TokenAccessReviewFailoverClients...)
+			tokenAuth, err = webhooktoken.NewFromInterfaces(clients, c.APIAudiences, *c.WebhookRetryBackoff, c.TokenAccessReviewTimeout, metrics, breaker)
+		} else {
+			tokenAuth, err = webhooktoken.NewFromInterface(c.TokenAccessReviewClient, c.APIAudiences, *c.WebhookRetryBackoff, c.TokenAccessReviewTimeout, metrics)
+		}
 		if err != nil {
 			return nil, nil, err
 		}
-		cachingTokenAuth := cache.New(tokenAuth, false, c.CacheTTL, c.CacheTTL)
-		authenticators = append(authenticators, bearertoken.New(cachingTokenAuth), websocket.NewProtocolAuthenticator(cachingTokenAuth))
+		var cachedTokenAuth authenticator.Token = cache.NewWithConfig(tokenAuth, cacheConfig)
+		if c.WebhookTokenShape != nil {
+			cachedTokenAuth = shape.New(cachedTokenAuth, *c.WebhookTokenShape)
+		}
+		tokenAuthenticators = append(tokenAuthenticators, cachedTokenAuth)
+	}
+
+	if len(tokenAuthenticators) > 0 {
+		var tokenAuth authenticator.Token = cache.NewNegative(tokenunion.New(tokenAuthenticators...), c.NegativeCacheTTL, negativeCacheMaxEntries)
+		var bearerTokenAuth, websocketAuth authenticator.Request
+		if c.TokenCertificateBinding {
+			// the binding depends on the connection rather than the token, so it is checked outside of the caches.
+			tokenAuth = binding.New(tokenAuth)
+			bearerTokenAuth = binding.WithRequestPeerCertificate(bearertoken.New(tokenAuth))
+			websocketAuth = binding.WithRequestPeerCertificate(websocket.NewProtocolAuthenticator(tokenAuth))
+		} else {
+			bearerTokenAuth = bearertoken.New(tokenAuth)
+			websocketAuth = websocket.NewProtocolAuthenticator(tokenAuth)
+		}
+		if c.Throttle != nil {
+			throttleConfig := *c.Throttle
+			if throttleConfig.MaxEntries == 0 {
+				throttleConfig.MaxEntries = throttleMaxEntries
+			}
+			// both mechanisms share the failures, a token rejected in one is throttled in the other.
+			t := throttle.NewThrottle(throttleConfig)
+			bearerTokenAuth = t.Wrap(bearerTokenAuth)
+			websocketAuth = t.Wrap(websocketAuth)
+		}
+		mechanisms[MechanismBearerToken] = bearerTokenAuth
+		mechanisms[MechanismWebSocket] = websocketAuth
 
 		securityDefinitions["BearerToken"] = &spec.SecurityScheme{
 			SecuritySchemeProps: spec.SecuritySchemeProps{
@@ -111,16 +364,80 @@ func (c DelegatingAuthenticatorConfig) New() (authenticator.Request, *spec.Secur
 		}
 	}
 
+	authenticators, err := c.orderedMechanisms(mechanisms)
+	if err != nil {
+		return nil, nil, err
+	}
 	if len(authenticators) == 0 {
 		if c.Anonymous {
-			return anonymous.NewAuthenticator(), &securityDefinitions, nil
+			return c.anonymousAuthenticator(), &securityDefinitions, nil
 		}
 		return nil, nil, errors.New("No authentication method configured")
 	}
 
-	authenticator := group.NewAuthenticatedGroupAdder(unionauth.New(authenticators...))
+	authenticator := unionauth.New(authenticators...)
+	if c.GroupResolver != nil {
+		resolver := c.GroupResolver
+		if c.GroupResolverCacheTTL > 0 {
+			resolver = group.NewCachedGroupResolver(resolver, c.GroupResolverCacheTTL, groupResolverCacheMaxEntries)
+		}
+		authenticator = group.NewGroupResolvingAdder(authenticator, resolver)
+	}
+	authenticator = group.NewAuthenticatedGroupAdder(authenticator)
 	if c.Anonymous {
-		authenticator = unionauth.NewFailOnError(authenticator, anonymous.NewAuthenticator())
+		authenticator = unionauth.NewFailOnError(authenticator, c.anonymousAuthenticator())
 	}
 	return authenticator, &securityDefinitions, nil
 }
+
+// orderedMechanisms returns the configured mechanisms in MechanismOrder followed by the others in
+// DefaultMechanismOrder, wrapped as members of the authenticator union.
+func (c DelegatingAuthenticatorConfig) orderedMechanisms(mechanisms map[string]authenticator.Request) ([]authenticator.Request, error) {
+	if err := ValidateMechanisms(c.MechanismOrder); err != nil {
+		return nil, err
+	}
+	if err := ValidateMechanisms(c.ShortCircuitMechanisms); err != nil {
+		return nil, err
+	}
+	if err := ValidateMechanisms(c.UntrustedMechanisms); err != nil {
+		return nil, err
+	}
+	shortCircuit := sets.NewString(c.ShortCircuitMechanisms...)
+	untrusted := sets.NewString(c.UntrustedMechanisms...)
+	ordered := sets.NewString()
+	authenticators := []authenticator.Request{}
+	for _, name := range append(append([]string{}, c.MechanismOrder...), DefaultMechanismOrder...) {
+		auth, ok := mechanisms[name]
+		if !ok || ordered.Has(name) {
+			continue
+		}
+		ordered.Insert(name)
+		if c.UserNormalization != nil || untrusted.Has(name) {
+			var config normalize.Config
+			if c.UserNormalization != nil {
+				config = *c.UserNormalization
+			}
+			config.RejectReservedIdentities = config.RejectReservedIdentities || untrusted.Has(name)
+			auth = normalize.New(auth, config)
+		}
+		auth = c.named(name, auth)
+		if shortCircuit.Has(name) {
+			auth = unionauth.ShortCircuit(auth)
+		}
+		authenticators = append(authenticators, auth)
+	}
+	return authenticators, nil
+}
+
+// named wraps a member of the authenticator union to record its metrics, and its spans if tracing is configured.
+func (c DelegatingAuthenticatorConfig) named(name string, auth authenticator.Request) authenticator.Request {
+	return unionauth.NamedWithTracing(name, auth, c.TracerProvider)
+}
+
+func (c DelegatingAuthenticatorConfig) anonymousAuthenticator() authenticator.Request {
+	anonymousAuthenticator := anonymous.NewAuthenticator()
+	if len(c.AnonymousPaths) > 0 {
+		anonymousAuthenticator = anonymous.NewAuthenticatorForPaths(c.AnonymousPaths)
+	}
+	return c.named("anonymous", anonymous.WithPathIdentities(anonymousAuthenticator, c.AnonymousIdentities))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticatorfactory
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	unionauth "k8s.io/apiserver/pkg/authentication/request/union"
+)
+
+func TestOrderedMechanisms(t *testing.T) {
+	var tried []string
+	mechanism := func(name string, err error) authenticator.Request {
+		return authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+			tried = append(tried, name)
+			return nil, false, err
+		})
+	}
+	mechanisms := map[string]authenticator.Request{
+		MechanismRequestHeader: mechanism(MechanismRequestHeader, nil),
+		MechanismX509:          mechanism(MechanismX509, errors.New("invalid client certificate")),
+		MechanismBearerToken:   mechanism(MechanismBearerToken, nil),
+	}
+
+	testCases := []struct {
+		name        string
+		config      DelegatingAuthenticatorConfig
+		expectTried []string
+		expectError bool
+	}{
+		{
+			name:        "default order",
+			expectTried: []string{MechanismRequestHeader, MechanismX509, MechanismBearerToken},
+		},
+		{
+			name:        "configured order first",
+			config:      DelegatingAuthenticatorConfig{MechanismOrder: []string{MechanismBearerToken, MechanismWebSocket}},
+			expectTried: []string{MechanismBearerToken, MechanismRequestHeader, MechanismX509},
+		},
+		{
+			name:        "short-circuit on failure",
+			config:      DelegatingAuthenticatorConfig{ShortCircuitMechanisms: []string{MechanismX509}},
+			expectTried: []string{MechanismRequestHeader, MechanismX509},
+		},
+		{
+			name:        "unknown mechanism",
+			config:      DelegatingAuthenticatorConfig{MechanismOrder: []string{"kerberos"}},
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tried = nil
+			authenticators, err := tc.config.orderedMechanisms(mechanisms)
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, _ := http.NewRequest("GET", "/", nil)
+			if _, ok, _ := unionauth.New(authenticators...).AuthenticateRequest(req); ok {
+				t.Fatal("unexpectedly authenticated")
+			}
+			if !reflect.DeepEqual(tried, tc.expectTried) {
+				t.Errorf("expected %v to be tried, got %v", tc.expectTried, tried)
+			}
+		})
+	}
+}
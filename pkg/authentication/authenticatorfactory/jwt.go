@@ -0,0 +1,223 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticatorfactory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/apis/apiserver"
+	"k8s.io/apiserver/pkg/apis/apiserver/install"
+	"k8s.io/apiserver/pkg/apis/apiserver/validation"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	tokenunion "k8s.io/apiserver/pkg/authentication/token/union"
+	"k8s.io/apiserver/pkg/util/filewatcher"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/oidc"
+	"k8s.io/klog/v2"
+)
+
+// FileRefreshDuration is the interval at which the authentication configuration file
+// is re-read even if no file events were observed. It is exposed so that tests can
+// crank up the reload speed.
+var FileRefreshDuration = 1 * time.Minute
+
+var (
+	cfgScheme = runtime.NewScheme()
+	codecs    = serializer.NewCodecFactory(cfgScheme)
+)
+
+func init() {
+	install.Install(cfgScheme)
+}
+
+// closableTokenAuthenticator is a token authenticator that holds resources which
+// must be released once it is no longer used.
+type closableTokenAuthenticator interface {
+	authenticator.Token
+	Close()
+}
+
+// DynamicJWTAuthenticator is a token authenticator that authenticates JWTs against
+// the issuers listed in an AuthenticationConfiguration file and reloads the file
+// whenever it changes. A configuration that fails to load or validate is rejected
+// and the previously loaded configuration stays in effect.
+type DynamicJWTAuthenticator struct {
+	// filename is the name of the authentication configuration file to read.
+	filename string
+
+	// authenticator holds a *loadedJWTConfig with the last successfully loaded configuration.
+	authenticator atomic.Value
+
+	// watcher reloads the authentication configuration file whenever it changes.
+	watcher *filewatcher.FileWatcher
+
+	// newAuthenticator builds the authenticator for a single issuer and audience.
+	// It is overridden in tests.
+	newAuthenticator func(oidc.Options) (closableTokenAuthenticator, error)
+}
+
+var _ authenticator.Token = &DynamicJWTAuthenticator{}
+
+type loadedJWTConfig struct {
+	content        []byte
+	authenticator  authenticator.Token
+	authenticators []closableTokenAuthenticator
+}
+
+func (l *loadedJWTConfig) close() {
+	for _, a := range l.authenticators {
+		a.Close()
+	}
+}
+
+// NewDynamicJWTAuthenticator creates a token authenticator that watches the
+// authentication configuration file at the given path and swaps in the new JWT
+// authenticators after the configuration has been validated. The initial
+// configuration must load successfully.
+// Run must be called for the file to be watched.
+func NewDynamicJWTAuthenticator(path string) (*DynamicJWTAuthenticator, error) {
+	return newDynamicJWTAuthenticator(path, func(opts oidc.Options) (closableTokenAuthenticator, error) {
+		return oidc.New(opts)
+	})
+}
+
+func newDynamicJWTAuthenticator(path string, newAuthenticator func(oidc.Options) (closableTokenAuthenticator, error)) (*DynamicJWTAuthenticator, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("file path not specified")
+	}
+	a := &DynamicJWTAuthenticator{
+		filename:         path,
+		newAuthenticator: newAuthenticator,
+	}
+	a.watcher = filewatcher.New("DynamicJWTAuthenticator", path, a.loadConfig)
+	if err := a.loadConfig(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// AuthenticateToken authenticates the token against the currently loaded issuers.
+func (a *DynamicJWTAuthenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	return a.authenticator.Load().(*loadedJWTConfig).authenticator.AuthenticateToken(ctx, token)
+}
+
+// ReadAuthenticationConfiguration decodes and validates the authentication configuration in data.
+func ReadAuthenticationConfiguration(data []byte) (*apiserver.AuthenticationConfiguration, error) {
+	config := &apiserver.AuthenticationConfiguration{}
+	// this handles json/yaml/whatever, and decodes all registered version to the internal version
+	if err := runtime.DecodeInto(codecs.UniversalDecoder(), data, config); err != nil {
+		return nil, fmt.Errorf("unable to decode authentication configuration data: %v", err)
+	}
+	if errs := validation.ValidateAuthenticationConfiguration(config); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+	return config, nil
+}
+
+// loadConfig reads the configuration file and swaps the authenticators if the content changed.
+func (a *DynamicJWTAuthenticator) loadConfig() error {
+	content, err := ioutil.ReadFile(a.filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file path %q: %v", a.filename, err)
+	}
+	existing, _ := a.authenticator.Load().(*loadedJWTConfig)
+	if existing != nil && bytes.Equal(existing.content, content) {
+		return nil
+	}
+
+	config, err := ReadAuthenticationConfiguration(content)
+	if err != nil {
+		return fmt.Errorf("%v: from file %v", err, a.filename)
+	}
+	loaded := &loadedJWTConfig{content: content}
+	for _, jwt := range config.JWT {
+		for _, audience := range jwt.Issuer.Audiences {
+			opts := jwtOptions(jwt, audience)
+			auth, err := a.newAuthenticator(opts)
+			if err != nil {
+				loaded.close()
+				return fmt.Errorf("failed to create authenticator for issuer %q: %v: from file %v", jwt.Issuer.URL, err, a.filename)
+			}
+			loaded.authenticators = append(loaded.authenticators, auth)
+		}
+	}
+	tokenAuthenticators := make([]authenticator.Token, 0, len(loaded.authenticators))
+	for _, auth := range loaded.authenticators {
+		tokenAuthenticators = append(tokenAuthenticators, auth)
+	}
+	loaded.authenticator = tokenunion.New(tokenAuthenticators...)
+
+	a.authenticator.Store(loaded)
+	if existing != nil {
+		existing.close()
+	}
+	klog.V(2).InfoS("Loaded authentication configuration", "file", a.filename, "issuers", len(config.JWT))
+	return nil
+}
+
+// jwtOptions converts a JWT authenticator configuration to the options of the
+// OIDC authenticator for a single audience.
+func jwtOptions(jwt apiserver.JWTAuthenticator, audience string) oidc.Options {
+	opts := oidc.Options{
+		IssuerURL:     jwt.Issuer.URL,
+		ClientID:      audience,
+		UsernameClaim: jwt.ClaimMappings.Username.Claim,
+		GroupsClaim:   jwt.ClaimMappings.Groups.Claim,
+	}
+	if len(jwt.Issuer.CertificateAuthority) > 0 {
+		opts.CAContentProvider = staticCAContent(jwt.Issuer.CertificateAuthority)
+	}
+	switch {
+	case jwt.ClaimMappings.Username.Prefix != nil:
+		opts.UsernamePrefix = *jwt.ClaimMappings.Username.Prefix
+	case jwt.ClaimMappings.Username.Claim != "email":
+		opts.UsernamePrefix = jwt.Issuer.URL + "#"
+	}
+	if jwt.ClaimMappings.Groups.Prefix != nil {
+		opts.GroupsPrefix = *jwt.ClaimMappings.Groups.Prefix
+	}
+	if len(jwt.ClaimValidationRules) > 0 {
+		opts.RequiredClaims = make(map[string]string, len(jwt.ClaimValidationRules))
+		for _, rule := range jwt.ClaimValidationRules {
+			opts.RequiredClaims[rule.Claim] = rule.RequiredValue
+		}
+	}
+	return opts
+}
+
+// staticCAContent is a CA bundle taken from the authentication configuration.
+type staticCAContent string
+
+func (s staticCAContent) CurrentCABundleContent() []byte {
+	return []byte(s)
+}
+
+// RunOnce runs a single sync loop
+func (a *DynamicJWTAuthenticator) RunOnce(ctx context.Context) error {
+	return a.loadConfig()
+}
+
+// Run starts the controller and blocks until ctx is done.
+func (a *DynamicJWTAuthenticator) Run(ctx context.Context, workers int) {
+	a.watcher.Run(ctx, FileRefreshDuration)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticatorfactory
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/oidc"
+)
+
+// fakeJWTAuthenticator accepts the token "<issuer>/<audience>".
+type fakeJWTAuthenticator struct {
+	opts   oidc.Options
+	closed bool
+}
+
+func (f *fakeJWTAuthenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	if token != f.opts.IssuerURL+"/"+f.opts.ClientID {
+		return nil, false, nil
+	}
+	return &authenticator.Response{User: &user.DefaultInfo{Name: f.opts.UsernamePrefix + "user"}}, true, nil
+}
+
+func (f *fakeJWTAuthenticator) Close() {
+	f.closed = true
+}
+
+const (
+	issuerAConfig = `
+apiVersion: apiserver.config.k8s.io/v1alpha1
+kind: AuthenticationConfiguration
+jwt:
+- issuer:
+    url: https://a.example.com
+    audiences: ["aud"]
+  claimMappings:
+    username:
+      claim: sub
+`
+	issuerABConfig = issuerAConfig + `
+- issuer:
+    url: https://b.example.com
+    audiences: ["aud", "other"]
+  claimValidationRules:
+  - claim: hd
+    requiredValue: example.com
+  claimMappings:
+    username:
+      claim: email
+    groups:
+      claim: groups
+      prefix: "b:"
+`
+	invalidConfig = `
+apiVersion: apiserver.config.k8s.io/v1alpha1
+kind: AuthenticationConfiguration
+jwt:
+- issuer:
+    url: http://c.example.com
+`
+)
+
+func TestDynamicJWTAuthenticator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authn.yaml")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var created []*fakeJWTAuthenticator
+	newFake := func(opts oidc.Options) (closableTokenAuthenticator, error) {
+		f := &fakeJWTAuthenticator{opts: opts}
+		created = append(created, f)
+		return f, nil
+	}
+	authenticate := func(a *DynamicJWTAuthenticator, token, expectUser string) {
+		t.Helper()
+		resp, ok, err := a.AuthenticateToken(context.Background(), token)
+		if err != nil {
+			t.Fatalf("unexpected error authenticating %q: %v", token, err)
+		}
+		if len(expectUser) == 0 {
+			if ok {
+				t.Errorf("expected %q to be rejected, got user %q", token, resp.User.GetName())
+			}
+			return
+		}
+		if !ok || resp.User.GetName() != expectUser {
+			t.Errorf("expected %q to authenticate as %q, got %v %v", token, expectUser, ok, resp)
+		}
+	}
+
+	writeConfig(issuerAConfig)
+	a, err := newDynamicJWTAuthenticator(path, newFake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticate(a, "https://a.example.com/aud", "https://a.example.com#user")
+	authenticate(a, "https://b.example.com/aud", "")
+
+	// Adding an issuer takes effect on reload and releases the previous authenticators.
+	writeConfig(issuerABConfig)
+	if err := a.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	authenticate(a, "https://a.example.com/aud", "https://a.example.com#user")
+	authenticate(a, "https://b.example.com/aud", "user")
+	authenticate(a, "https://b.example.com/other", "user")
+	if len(created) != 4 || !created[0].closed || created[1].closed {
+		t.Errorf("expected only the first authenticator to be closed")
+	}
+	expectOpts := oidc.Options{
+		IssuerURL:      "https://b.example.com",
+		ClientID:       "other",
+		UsernameClaim:  "email",
+		GroupsClaim:    "groups",
+		GroupsPrefix:   "b:",
+		RequiredClaims: map[string]string{"hd": "example.com"},
+	}
+	if !reflect.DeepEqual(created[3].opts, expectOpts) {
+		t.Errorf("unexpected options: %#v", created[3].opts)
+	}
+
+	// Unchanged content is not reloaded.
+	if err := a.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 4 {
+		t.Errorf("expected unchanged configuration not to be reloaded")
+	}
+
+	// An invalid configuration is rejected and the previous one stays in effect.
+	writeConfig(invalidConfig)
+	if err := a.RunOnce(context.Background()); err == nil {
+		t.Fatal("expected invalid configuration to be rejected")
+	}
+	authenticate(a, "https://b.example.com/aud", "user")
+	for _, f := range created[1:] {
+		if f.closed {
+			t.Errorf("expected authenticator for %s to stay open", f.opts.IssuerURL)
+		}
+	}
+}
+
+func TestNewDynamicJWTAuthenticatorInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authn.yaml")
+	if err := ioutil.WriteFile(path, []byte(invalidConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDynamicJWTAuthenticator(path); err == nil {
+		t.Error("expected invalid initial configuration to be rejected")
+	}
+	if _, err := NewDynamicJWTAuthenticator(""); err == nil {
+		t.Error("expected missing path to be rejected")
+	}
+}
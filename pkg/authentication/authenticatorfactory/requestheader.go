@@ -34,4 +34,6 @@ type RequestHeaderConfig struct {
 	CAContentProvider dynamiccertificates.CAContentProvider
 	// AllowedClientNames is a list of common names that may be presented by the authenticating front proxy.  Empty means: accept any.
 	AllowedClientNames headerrequest.StringSliceProvider
+	// ProxyIdentityScopes limits the identities individual front proxies may assert. Empty means: no limits.
+	ProxyIdentityScopes headerrequest.ProxyIdentityScopes
 }
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package group
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+)
+
+// GroupResolver resolves groups of an authenticated user from a source other than its credentials,
+// such as a directory service.
+type GroupResolver interface {
+	// ResolveGroups returns the groups to add to the given user.
+	ResolveGroups(ctx context.Context, user user.Info) ([]string, error)
+}
+
+// GroupResolverFunc is a function that implements the GroupResolver interface.
+type GroupResolverFunc func(ctx context.Context, user user.Info) ([]string, error)
+
+// ResolveGroups implements GroupResolver.
+func (f GroupResolverFunc) ResolveGroups(ctx context.Context, user user.Info) ([]string, error) {
+	return f(ctx, user)
+}
+
+// GroupResolvingAdder adds the groups resolved for an authenticated user.Info
+type GroupResolvingAdder struct {
+	// Authenticator is delegated to make the authentication decision
+	Authenticator authenticator.Request
+	// Resolver resolves the groups to add to the user.Info from a successful authentication
+	Resolver GroupResolver
+}
+
+// NewGroupResolvingAdder wraps a request authenticator, and adds the groups returned by resolver to the
+// returned user when authentication succeeds. If the groups cannot be resolved, the user is returned
+// with the groups of the wrapped authenticator only.
+func NewGroupResolvingAdder(auth authenticator.Request, resolver GroupResolver) authenticator.Request {
+	return &GroupResolvingAdder{auth, resolver}
+}
+
+func (g *GroupResolvingAdder) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	r, ok, err := g.Authenticator.AuthenticateRequest(req)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	resolved, err := g.Resolver.ResolveGroups(req.Context(), r.User)
+	if err != nil {
+		klog.ErrorS(err, "Unable to resolve the groups of the user", "user", r.User.GetName())
+		return r, true, nil
+	}
+
+	newGroups := make([]string, 0, len(r.User.GetGroups())+len(resolved))
+	newGroups = append(newGroups, r.User.GetGroups()...)
+	for _, group := range resolved {
+		if !containsGroup(newGroups, group) {
+			newGroups = append(newGroups, group)
+		}
+	}
+	if len(newGroups) == len(r.User.GetGroups()) {
+		return r, true, nil
+	}
+
+	ret := *r // shallow copy
+	ret.User = &user.DefaultInfo{
+		Name:   r.User.GetName(),
+		UID:    r.User.GetUID(),
+		Groups: newGroups,
+		Extra:  r.User.GetExtra(),
+	}
+	return &ret, true, nil
+}
+
+func containsGroup(groups []string, group string) bool {
+	for _, g := range groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// cachedGroupResolver remembers the groups resolved for each user.
+type cachedGroupResolver struct {
+	resolver GroupResolver
+	ttl      time.Duration
+	cache    *utilcache.LRUExpireCache
+}
+
+type cachedGroupsKey struct {
+	name string
+	uid  string
+}
+
+// NewCachedGroupResolver returns a GroupResolver that remembers the groups resolved by resolver for
+// ttl, keyed by the name and UID of the user, for at most maxEntries users. Failures are not
+// remembered. It must only wrap resolvers whose answer depends on nothing but the name and UID.
+func NewCachedGroupResolver(resolver GroupResolver, ttl time.Duration, maxEntries int) GroupResolver {
+	return &cachedGroupResolver{
+		resolver: resolver,
+		ttl:      ttl,
+		cache:    utilcache.NewLRUExpireCache(maxEntries),
+	}
+}
+
+// ResolveGroups implements GroupResolver.
+func (c *cachedGroupResolver) ResolveGroups(ctx context.Context, user user.Info) ([]string, error) {
+	key := cachedGroupsKey{name: user.GetName(), uid: user.GetUID()}
+	if groups, ok := c.cache.Get(key); ok {
+		return groups.([]string), nil
+	}
+	groups, err := c.resolver.ResolveGroups(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Add(key, groups, c.ttl)
+	return groups, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package group
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+func TestGroupResolvingAdder(t *testing.T) {
+	testCases := []struct {
+		name           string
+		resolved       []string
+		resolveErr     error
+		expectedGroups []string
+	}{
+		{
+			name:           "add",
+			resolved:       []string{"resolved"},
+			expectedGroups: []string{"original", "resolved"},
+		},
+		{
+			name:           "skip duplicates",
+			resolved:       []string{"original", "resolved", "resolved"},
+			expectedGroups: []string{"original", "resolved"},
+		},
+		{
+			name:           "resolver error",
+			resolveErr:     errors.New("directory unavailable"),
+			expectedGroups: []string{"original"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := &authenticator.Response{User: &user.DefaultInfo{Name: "user", Groups: []string{"original"}}}
+			orig := toJson(response)
+
+			adder := NewGroupResolvingAdder(
+				authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+					return response, true, nil
+				}),
+				GroupResolverFunc(func(ctx context.Context, u user.Info) ([]string, error) {
+					if u.GetName() != "user" {
+						t.Errorf("unexpected user %q", u.GetName())
+					}
+					return tc.resolved, tc.resolveErr
+				}),
+			)
+
+			req, _ := http.NewRequest("GET", "/", nil)
+			r, ok, err := adder.AuthenticateRequest(req)
+			if !ok || err != nil {
+				t.Fatalf("expected the request to be authenticated, got %v, %v", ok, err)
+			}
+			if !reflect.DeepEqual(r.User.GetGroups(), tc.expectedGroups) {
+				t.Errorf("Unexpected groups\ngot:\t%#v\nwant:\t%#v", r.User.GetGroups(), tc.expectedGroups)
+			}
+			if got := toJson(response); got != orig {
+				t.Errorf("Expected response from delegate to be unmodified: orig=%v got=%v", orig, got)
+			}
+		})
+	}
+}
+
+func TestGroupResolvingAdderUnauthenticated(t *testing.T) {
+	adder := NewGroupResolvingAdder(
+		authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+			return nil, false, nil
+		}),
+		GroupResolverFunc(func(ctx context.Context, u user.Info) ([]string, error) {
+			t.Errorf("unexpected call to the resolver")
+			return nil, nil
+		}),
+	)
+	req, _ := http.NewRequest("GET", "/", nil)
+	if _, ok, err := adder.AuthenticateRequest(req); ok || err != nil {
+		t.Errorf("expected the request not to be authenticated, got %v, %v", ok, err)
+	}
+}
+
+func TestCachedGroupResolver(t *testing.T) {
+	calls := 0
+	var resolveErr error
+	resolver := NewCachedGroupResolver(GroupResolverFunc(func(ctx context.Context, u user.Info) ([]string, error) {
+		calls++
+		if resolveErr != nil {
+			return nil, resolveErr
+		}
+		return []string{u.GetName() + "-group"}, nil
+	}), time.Minute, 10)
+
+	resolve := func(u user.Info, expected []string) {
+		t.Helper()
+		groups, err := resolver.ResolveGroups(context.Background(), u)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(groups, expected) {
+			t.Errorf("expected groups %v, got %v", expected, groups)
+		}
+	}
+	alice := &user.DefaultInfo{Name: "alice", UID: "1"}
+	resolve(alice, []string{"alice-group"})
+	resolve(alice, []string{"alice-group"})
+	if calls != 1 {
+		t.Errorf("expected the groups of the user to be cached, got %d calls", calls)
+	}
+
+	// a user with the same name but another UID is resolved on its own
+	resolve(&user.DefaultInfo{Name: "alice", UID: "2"}, []string{"alice-group"})
+	if calls != 2 {
+		t.Errorf("expected users to be keyed by UID, got %d calls", calls)
+	}
+
+	// failures are not cached
+	resolveErr = errors.New("directory unavailable")
+	bob := &user.DefaultInfo{Name: "bob"}
+	if _, err := resolver.ResolveGroups(context.Background(), bob); err == nil {
+		t.Fatal("expected error")
+	}
+	resolveErr = nil
+	resolve(bob, []string{"bob-group"})
+	if calls != 4 {
+		t.Errorf("expected failures not to be cached, got %d calls", calls)
+	}
+}
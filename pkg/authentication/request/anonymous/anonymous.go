@@ -18,6 +18,7 @@ package anonymous
 
 import (
 	"net/http"
+	"strings"
 
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/user"
@@ -30,14 +31,107 @@ const (
 )
 
 func NewAuthenticator() authenticator.Request {
+	return authenticator.RequestFunc(authenticate)
+}
+
+// NewAuthenticatorForPaths returns an authenticator that authenticates requests as
+// anonymous only if their path is in the given allowlist. A path ending in "*" matches
+// every path with that prefix, e.g. "/readyz*" matches "/readyz" and "/readyz/etcd".
+// Requests to any other path are left unauthenticated.
+func NewAuthenticatorForPaths(paths []string) authenticator.Request {
+	matches := newPathMatcher(paths)
+	return authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		if !matches(req) {
+			return nil, false, nil
+		}
+		return authenticate(req)
+	})
+}
+
+// PathIdentity is the synthetic identity of unauthenticated requests to a set of paths,
+// so that e.g. probes of health endpoints can be told apart from other anonymous requests.
+type PathIdentity struct {
+	// Paths are the request paths the identity applies to. A path ending in "*" matches
+	// every path with that prefix.
+	Paths []string
+	// Username is the name of the user.
+	Username string
+	// Groups are the groups of the user. The system:unauthenticated group is always added.
+	Groups []string
+}
+
+// WithPathIdentities wraps an anonymous authenticator, and replaces the user it returns with
+// the first of the identities whose paths match the request path. Requests it does not
+// authenticate are left unauthenticated, so identities never extend its allowed paths.
+func WithPathIdentities(auth authenticator.Request, identities []PathIdentity) authenticator.Request {
+	if len(identities) == 0 {
+		return auth
+	}
+	matchers := make([]func(*http.Request) bool, 0, len(identities))
+	for _, identity := range identities {
+		matchers = append(matchers, newPathMatcher(identity.Paths))
+	}
+
 	return authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
-		auds, _ := authenticator.AudiencesFrom(req.Context())
-		return &authenticator.Response{
-			User: &user.DefaultInfo{
-				Name:   anonymousUser,
-				Groups: []string{unauthenticatedGroup},
-			},
-			Audiences: auds,
-		}, true, nil
+		resp, ok, err := auth.AuthenticateRequest(req)
+		if err != nil || !ok {
+			return resp, ok, err
+		}
+		for i, matches := range matchers {
+			if !matches(req) {
+				continue
+			}
+			groups := make([]string, 0, len(identities[i].Groups)+1)
+			groups = append(groups, identities[i].Groups...)
+			groups = append(groups, unauthenticatedGroup)
+			ret := *resp // shallow copy
+			ret.User = &user.DefaultInfo{
+				Name:   identities[i].Username,
+				Groups: groups,
+			}
+			return &ret, true, nil
+		}
+		return resp, true, nil
 	})
 }
+
+// newPathMatcher returns a function reporting whether the path of a request is one of
+// paths, or has the prefix of one of paths ending in "*".
+func newPathMatcher(paths []string) func(*http.Request) bool {
+	exact := map[string]bool{}
+	prefixes := []string{}
+	for _, p := range paths {
+		if strings.HasSuffix(p, "*") {
+			prefixes = append(prefixes, strings.TrimSuffix(p, "*"))
+		} else {
+			exact[p] = true
+		}
+	}
+
+	return func(req *http.Request) bool {
+		if req.URL == nil {
+			return false
+		}
+		path := req.URL.Path
+		if exact[path] {
+			return true
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func authenticate(req *http.Request) (*authenticator.Response, bool, error) {
+	auds, _ := authenticator.AudiencesFrom(req.Context())
+	return &authenticator.Response{
+		User: &user.DefaultInfo{
+			Name:   anonymousUser,
+			Groups: []string{unauthenticatedGroup},
+		},
+		Audiences: auds,
+	}, true, nil
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package anonymous
 
 import (
+	"context"
 	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -41,3 +44,72 @@ func TestAnonymous(t *testing.T) {
 		t.Fatalf("Expected group %s, got %v", user.AllUnauthenticated, r.User.GetGroups())
 	}
 }
+
+func TestAnonymousForPaths(t *testing.T) {
+	a := NewAuthenticatorForPaths([]string{"/healthz", "/readyz*", "/apis"})
+	testCases := []struct {
+		path     string
+		expectOK bool
+	}{
+		{path: "/healthz", expectOK: true},
+		{path: "/healthz/etcd", expectOK: false},
+		{path: "/readyz", expectOK: true},
+		{path: "/readyz/etcd", expectOK: true},
+		{path: "/apis", expectOK: true},
+		{path: "/apis/apps/v1", expectOK: false},
+		{path: "/api/v1/secrets", expectOK: false},
+		{path: "/", expectOK: false},
+	}
+	for _, tc := range testCases {
+		r, ok, err := a.AuthenticateRequest(&http.Request{URL: &url.URL{Path: tc.path}})
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.path, err)
+		}
+		if ok != tc.expectOK {
+			t.Fatalf("%s: expected ok=%v, got %v", tc.path, tc.expectOK, ok)
+		}
+		if ok && r.User.GetName() != user.Anonymous {
+			t.Fatalf("%s: expected username %s, got %s", tc.path, user.Anonymous, r.User.GetName())
+		}
+	}
+}
+
+func TestAnonymousWithPathIdentities(t *testing.T) {
+	a := WithPathIdentities(NewAuthenticatorForPaths([]string{"/healthz", "/readyz*", "/metrics", "/version"}), []PathIdentity{
+		{Paths: []string{"/healthz", "/readyz*"}, Username: "system:probe", Groups: []string{"system:probes"}},
+		{Paths: []string{"/metrics", "/readyz/etcd"}, Username: "system:scraper"},
+		{Paths: []string{"/api*"}, Username: "system:never"},
+	})
+	testCases := []struct {
+		path           string
+		expectOK       bool
+		expectUsername string
+		expectGroups   []string
+	}{
+		{path: "/healthz", expectOK: true, expectUsername: "system:probe", expectGroups: []string{"system:probes", user.AllUnauthenticated}},
+		{path: "/readyz/etcd", expectOK: true, expectUsername: "system:probe", expectGroups: []string{"system:probes", user.AllUnauthenticated}},
+		{path: "/metrics", expectOK: true, expectUsername: "system:scraper", expectGroups: []string{user.AllUnauthenticated}},
+		{path: "/version", expectOK: true, expectUsername: user.Anonymous, expectGroups: []string{user.AllUnauthenticated}},
+		{path: "/api/v1/secrets", expectOK: false},
+	}
+	for _, tc := range testCases {
+		ctx := authenticator.WithAudiences(context.Background(), authenticator.Audiences{"api"})
+		req := (&http.Request{URL: &url.URL{Path: tc.path}}).WithContext(ctx)
+		r, ok, err := a.AuthenticateRequest(req)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.path, err)
+		}
+		if ok != tc.expectOK {
+			t.Fatalf("%s: expected ok=%v, got %v", tc.path, tc.expectOK, ok)
+		}
+		if !ok {
+			continue
+		}
+		if r.User.GetName() != tc.expectUsername || !reflect.DeepEqual(r.User.GetGroups(), tc.expectGroups) {
+			t.Errorf("%s: expected user %s in groups %v, got %s in %v", tc.path, tc.expectUsername, tc.expectGroups, r.User.GetName(), r.User.GetGroups())
+		}
+		if !reflect.DeepEqual(r.Audiences, authenticator.Audiences{"api"}) {
+			t.Errorf("%s: expected the audiences of the request, got %v", tc.path, r.Audiences)
+		}
+	}
+}
@@ -37,7 +37,8 @@ func New(auth authenticator.Token) *Authenticator {
 	return &Authenticator{auth}
 }
 
-var invalidToken = errors.New("invalid bearer token")
+// invalidToken is a rejection of the token, as the token authenticator did not accept it.
+var invalidToken = authenticator.NewTokenRejectedError(errors.New("invalid bearer token"))
 
 func (a *Authenticator) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
 	auth := strings.TrimSpace(req.Header.Get("Authorization"))
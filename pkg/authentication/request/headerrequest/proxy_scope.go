@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package headerrequest
+
+import (
+	"fmt"
+	"net/http"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// ProxyIdentityScope limits the identities a single front proxy may assert. Patterns
+// either match exactly or, when ending in "*", match every value with that prefix.
+type ProxyIdentityScope struct {
+	// ProxyClientName is the common name of the client certificate presented by the front proxy.
+	ProxyClientName string
+	// AllowedUsernames are the user names the proxy may assert. If empty, the proxy may not assert any user.
+	AllowedUsernames []string
+	// AllowedGroups are the groups the proxy may assert. If empty, the proxy may not assert any group.
+	AllowedGroups []string
+	// AllowedExtraKeys are the keys of the extra attributes the proxy may assert. If empty, the proxy
+	// may not assert any extra attribute.
+	AllowedExtraKeys []string
+}
+
+// ProxyIdentityScopes is a list of scopes, at most one per proxy client name.
+type ProxyIdentityScopes []ProxyIdentityScope
+
+// scopedRequestHeaderAuthenticator rejects identities asserted by a front proxy outside
+// of the scope configured for the proxy's client certificate.
+type scopedRequestHeaderAuthenticator struct {
+	delegate authenticator.Request
+	scopes   map[string]ProxyIdentityScope
+}
+
+// NewScoped wraps a request header authenticator so that every front proxy with a scope may
+// only assert the identities allowed by it. Proxies without a scope are not restricted. The
+// proxy is identified by the common name of the verified client certificate, so the returned
+// authenticator must be wrapped by an x509 verifier.
+func NewScoped(delegate authenticator.Request, scopes ProxyIdentityScopes) authenticator.Request {
+	byName := make(map[string]ProxyIdentityScope, len(scopes))
+	for _, scope := range scopes {
+		byName[scope.ProxyClientName] = scope
+	}
+	return &scopedRequestHeaderAuthenticator{delegate: delegate, scopes: byName}
+}
+
+func (a *scopedRequestHeaderAuthenticator) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	resp, ok, err := a.delegate.AuthenticateRequest(req)
+	if !ok || err != nil {
+		return resp, ok, err
+	}
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil, false, fmt.Errorf("unable to scope request header identity without a client certificate")
+	}
+
+	proxyName := req.TLS.PeerCertificates[0].Subject.CommonName
+	scope, scoped := a.scopes[proxyName]
+	if !scoped {
+		return resp, ok, err
+	}
+	if name := resp.User.GetName(); !user.MatchesAnyPattern(scope.AllowedUsernames, name) {
+		return nil, false, fmt.Errorf("front proxy %q is not allowed to assert user %q", proxyName, name)
+	}
+	for _, group := range resp.User.GetGroups() {
+		if !user.MatchesAnyPattern(scope.AllowedGroups, group) {
+			return nil, false, fmt.Errorf("front proxy %q is not allowed to assert group %q", proxyName, group)
+		}
+	}
+	for key := range resp.User.GetExtra() {
+		if !user.MatchesAnyPattern(scope.AllowedExtraKeys, key) {
+			return nil, false, fmt.Errorf("front proxy %q is not allowed to assert extra %q", proxyName, key)
+		}
+	}
+	return resp, ok, err
+}
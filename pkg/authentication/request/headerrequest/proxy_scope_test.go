@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package headerrequest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"testing"
+)
+
+func TestScopedRequestHeader(t *testing.T) {
+	scopes := ProxyIdentityScopes{
+		{
+			ProxyClientName:  "proxy-a",
+			AllowedUsernames: []string{"oidc:*"},
+			AllowedGroups:    []string{"oidc:*", "system:authenticated"},
+			AllowedExtraKeys: []string{"oidc.example.com/*"},
+		},
+		{
+			ProxyClientName:  "proxy-b",
+			AllowedUsernames: []string{"bob"},
+		},
+	}
+	auth := NewScoped(NewDynamic(
+		StaticStringSlice{"X-Remote-User"},
+		StaticStringSlice{"X-Remote-Group"},
+		StaticStringSlice{"X-Remote-Extra-"},
+	), scopes)
+
+	testcases := map[string]struct {
+		proxyName      string
+		requestHeaders http.Header
+		noTLS          bool
+
+		expectedOk    bool
+		expectedError bool
+	}{
+		"scoped user and groups": {
+			proxyName:      "proxy-a",
+			requestHeaders: http.Header{"X-Remote-User": {"oidc:alice"}, "X-Remote-Group": {"oidc:devs", "system:authenticated"}},
+			expectedOk:     true,
+		},
+		"user outside of scope": {
+			proxyName:      "proxy-a",
+			requestHeaders: http.Header{"X-Remote-User": {"system:admin"}},
+			expectedError:  true,
+		},
+		"group outside of scope": {
+			proxyName:      "proxy-a",
+			requestHeaders: http.Header{"X-Remote-User": {"oidc:alice"}, "X-Remote-Group": {"system:masters"}},
+			expectedError:  true,
+		},
+		"scoped extra": {
+			proxyName:      "proxy-a",
+			requestHeaders: http.Header{"X-Remote-User": {"oidc:alice"}, "X-Remote-Extra-Oidc.example.com%2fclaims": {"email"}},
+			expectedOk:     true,
+		},
+		"extra outside of scope": {
+			proxyName:      "proxy-a",
+			requestHeaders: http.Header{"X-Remote-User": {"oidc:alice"}, "X-Remote-Extra-Scopes": {"admin"}},
+			expectedError:  true,
+		},
+		"no extras allowed": {
+			proxyName:      "proxy-b",
+			requestHeaders: http.Header{"X-Remote-User": {"bob"}, "X-Remote-Extra-Scopes": {"admin"}},
+			expectedError:  true,
+		},
+		"no groups allowed": {
+			proxyName:      "proxy-b",
+			requestHeaders: http.Header{"X-Remote-User": {"bob"}, "X-Remote-Group": {"devs"}},
+			expectedError:  true,
+		},
+		"unscoped proxy": {
+			proxyName:      "proxy-c",
+			requestHeaders: http.Header{"X-Remote-User": {"system:admin"}, "X-Remote-Group": {"system:masters"}},
+			expectedOk:     true,
+		},
+		"no user": {
+			proxyName: "proxy-a",
+		},
+		"no client certificate": {
+			noTLS:          true,
+			requestHeaders: http.Header{"X-Remote-User": {"oidc:alice"}},
+			expectedError:  true,
+		},
+	}
+
+	for k, testcase := range testcases {
+		req := &http.Request{Header: testcase.requestHeaders}
+		if req.Header == nil {
+			req.Header = http.Header{}
+		}
+		if !testcase.noTLS {
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: testcase.proxyName}}}}
+		}
+
+		// the authenticator removes the headers it consumed
+		expectedName := req.Header.Get("X-Remote-User")
+
+		resp, ok, err := auth.AuthenticateRequest(req)
+		if testcase.expectedError != (err != nil) {
+			t.Errorf("%v: expected error %v, got %v", k, testcase.expectedError, err)
+			continue
+		}
+		if ok != testcase.expectedOk {
+			t.Errorf("%v: expected %v, got %v", k, testcase.expectedOk, ok)
+			continue
+		}
+		if ok && resp.User.GetName() != expectedName {
+			t.Errorf("%v: unexpected user %q", k, resp.User.GetName())
+		}
+	}
+}
@@ -154,6 +154,14 @@ func NewDynamicVerifyOptionsSecure(verifyOptionFn x509request.VerifyOptionFunc,
 	return x509request.NewDynamicCAVerifier(verifyOptionFn, headerAuthenticator, proxyClientNames)
 }
 
+// NewDynamicVerifyOptionsSecureScoped is like NewDynamicVerifyOptionsSecure, but limits the
+// identities each front proxy may assert to its scope in proxyScopes.
+func NewDynamicVerifyOptionsSecureScoped(verifyOptionFn x509request.VerifyOptionFunc, proxyClientNames, nameHeaders, groupHeaders, extraHeaderPrefixes StringSliceProvider, proxyScopes ProxyIdentityScopes) authenticator.Request {
+	headerAuthenticator := NewScoped(NewDynamic(nameHeaders, groupHeaders, extraHeaderPrefixes), proxyScopes)
+
+	return x509request.NewDynamicCAVerifier(verifyOptionFn, headerAuthenticator, proxyClientNames)
+}
+
 func (a *requestHeaderAuthRequestHandler) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
 	name := headerValue(req.Header, a.nameHeaders.Value())
 	if len(name) == 0 {
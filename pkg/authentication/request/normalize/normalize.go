@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package normalize normalizes and validates the users authenticated by request authenticators,
+// so that authorization sees users of a consistent form regardless of the mechanism used.
+package normalize
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// ReservedPrefix is the prefix of the users and groups reserved for the system.
+const ReservedPrefix = "system:"
+
+// PrefixMapping replaces the prefix From of a group by To. An empty To strips the prefix.
+type PrefixMapping struct {
+	From string
+	To   string
+}
+
+// Config describes the normalization and validation of authenticated users.
+type Config struct {
+	// LowercaseUsernames lowercases the names of users.
+	LowercaseUsernames bool
+
+	// GroupPrefixMappings rewrite the prefixes of groups. The first mapping whose From prefix
+	// matches a group is applied.
+	GroupPrefixMappings []PrefixMapping
+
+	// MaxExtraSize is the maximum total size in bytes of the keys and values of the extra fields
+	// of a user. Users exceeding it are rejected. Zero means unlimited.
+	MaxExtraSize int
+
+	// RejectReservedIdentities rejects users whose name or groups, after normalization, start
+	// with ReservedPrefix, for authenticators not trusted to authenticate system identities.
+	RejectReservedIdentities bool
+}
+
+type normalizer struct {
+	authenticator authenticator.Request
+	config        Config
+}
+
+// New returns a request authenticator that normalizes the users authenticated by auth as
+// described by config, and fails the authentication of users config rejects.
+func New(auth authenticator.Request, config Config) authenticator.Request {
+	return &normalizer{authenticator: auth, config: config}
+}
+
+// AuthenticateRequest implements authenticator.Request
+func (n *normalizer) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	resp, ok, err := n.authenticator.AuthenticateRequest(req)
+	if err != nil || !ok {
+		return resp, ok, err
+	}
+	u, err := n.config.normalize(resp.User)
+	if err != nil {
+		return nil, false, err
+	}
+	ret := *resp // shallow copy
+	ret.User = u
+	return &ret, true, nil
+}
+
+// normalize returns the normalized copy of u, or an error if u is rejected.
+func (c Config) normalize(u user.Info) (user.Info, error) {
+	name := u.GetName()
+	if c.LowercaseUsernames {
+		name = strings.ToLower(name)
+	}
+
+	var groups []string
+	if u.GetGroups() != nil {
+		groups = make([]string, 0, len(u.GetGroups()))
+		for _, group := range u.GetGroups() {
+			groups = append(groups, c.mapGroup(group))
+		}
+	}
+
+	if c.RejectReservedIdentities {
+		if strings.HasPrefix(name, ReservedPrefix) {
+			return nil, fmt.Errorf("user %q is reserved for the system", name)
+		}
+		for _, group := range groups {
+			if strings.HasPrefix(group, ReservedPrefix) {
+				return nil, fmt.Errorf("group %q of user %q is reserved for the system", group, name)
+			}
+		}
+	}
+
+	if c.MaxExtraSize > 0 {
+		size := 0
+		for key, values := range u.GetExtra() {
+			size += len(key)
+			for _, value := range values {
+				size += len(value)
+			}
+		}
+		if size > c.MaxExtraSize {
+			return nil, fmt.Errorf("extra fields of user %q of %d bytes exceed the maximum of %d", name, size, c.MaxExtraSize)
+		}
+	}
+
+	return &user.DefaultInfo{
+		Name:   name,
+		UID:    u.GetUID(),
+		Groups: groups,
+		Extra:  u.GetExtra(),
+	}, nil
+}
+
+// mapGroup applies the first group prefix mapping matching group.
+func (c Config) mapGroup(group string) string {
+	for _, mapping := range c.GroupPrefixMappings {
+		if strings.HasPrefix(group, mapping.From) {
+			return mapping.To + strings.TrimPrefix(group, mapping.From)
+		}
+	}
+	return group
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package normalize
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       Config
+		user         *user.DefaultInfo
+		expectUser   *user.DefaultInfo
+		expectReject bool
+	}{
+		{
+			name:       "no normalization",
+			user:       &user.DefaultInfo{Name: "Alice", UID: "1", Groups: []string{"oidc:admins"}, Extra: map[string][]string{"scopes": {"a"}}},
+			expectUser: &user.DefaultInfo{Name: "Alice", UID: "1", Groups: []string{"oidc:admins"}, Extra: map[string][]string{"scopes": {"a"}}},
+		},
+		{
+			name:       "lowercase usernames",
+			config:     Config{LowercaseUsernames: true},
+			user:       &user.DefaultInfo{Name: "Alice@Example.com", Groups: []string{"Admins"}},
+			expectUser: &user.DefaultInfo{Name: "alice@example.com", Groups: []string{"Admins"}},
+		},
+		{
+			name:       "map and strip group prefixes",
+			config:     Config{GroupPrefixMappings: []PrefixMapping{{From: "oidc:", To: "corp:"}, {From: "ldap:"}}},
+			user:       &user.DefaultInfo{Name: "alice", Groups: []string{"oidc:admins", "ldap:devs", "other"}},
+			expectUser: &user.DefaultInfo{Name: "alice", Groups: []string{"corp:admins", "devs", "other"}},
+		},
+		{
+			name:         "reserved username",
+			config:       Config{RejectReservedIdentities: true},
+			user:         &user.DefaultInfo{Name: "system:admin"},
+			expectReject: true,
+		},
+		{
+			name:         "reserved group",
+			config:       Config{RejectReservedIdentities: true},
+			user:         &user.DefaultInfo{Name: "alice", Groups: []string{"system:masters"}},
+			expectReject: true,
+		},
+		{
+			name:         "reserved group after mapping",
+			config:       Config{RejectReservedIdentities: true, GroupPrefixMappings: []PrefixMapping{{From: "oidc:"}}},
+			user:         &user.DefaultInfo{Name: "alice", Groups: []string{"oidc:system:masters"}},
+			expectReject: true,
+		},
+		{
+			name:       "reserved identities allowed",
+			user:       &user.DefaultInfo{Name: "system:admin", Groups: []string{"system:masters"}},
+			expectUser: &user.DefaultInfo{Name: "system:admin", Groups: []string{"system:masters"}},
+		},
+		{
+			name:       "extra within size",
+			config:     Config{MaxExtraSize: 8},
+			user:       &user.DefaultInfo{Name: "alice", Extra: map[string][]string{"key": {"ab", "cde"}}},
+			expectUser: &user.DefaultInfo{Name: "alice", Extra: map[string][]string{"key": {"ab", "cde"}}},
+		},
+		{
+			name:         "extra exceeding size",
+			config:       Config{MaxExtraSize: 8},
+			user:         &user.DefaultInfo{Name: "alice", Extra: map[string][]string{"key": {strings.Repeat("x", 6)}}},
+			expectReject: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := New(authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+				return &authenticator.Response{User: tc.user, Audiences: authenticator.Audiences{"api"}}, true, nil
+			}), tc.config)
+			req, _ := http.NewRequest("GET", "/", nil)
+			resp, ok, err := auth.AuthenticateRequest(req)
+			if tc.expectReject {
+				if ok || err == nil {
+					t.Fatalf("expected the user to be rejected, got %v, %v", ok, err)
+				}
+				return
+			}
+			if !ok || err != nil {
+				t.Fatalf("expected the user to be authenticated, got %v, %v", ok, err)
+			}
+			if !reflect.DeepEqual(resp.User, tc.expectUser) {
+				t.Errorf("expected user %#v, got %#v", tc.expectUser, resp.User)
+			}
+			if !reflect.DeepEqual(resp.Audiences, authenticator.Audiences{"api"}) {
+				t.Errorf("expected the audiences to be kept, got %v", resp.Audiences)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spiffe provides a request authenticator that validates SPIFFE X.509
+// SVIDs presented as client certificates and maps their SPIFFE IDs to users.
+package spiffe // import "k8s.io/apiserver/pkg/authentication/request/spiffe"
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	x509request "k8s.io/apiserver/pkg/authentication/request/x509"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// DefaultUsernameTemplate maps an SVID to its full SPIFFE ID.
+const DefaultUsernameTemplate = "{{.ID}}"
+
+var trustDomainRegexp = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+// Config configures a SPIFFE SVID authenticator.
+type Config struct {
+	// TrustDomain is the SPIFFE trust domain of the accepted SVIDs, e.g. "example.org".
+	TrustDomain string
+
+	// VerifyOptionsFn provides the options, in particular the trust bundle of the
+	// trust domain as roots, used to verify SVIDs.
+	VerifyOptionsFn x509request.VerifyOptionFunc
+
+	// UsernameTemplate is a text/template producing the username of an SVID.
+	// The template is executed with an ID value. Defaults to DefaultUsernameTemplate.
+	UsernameTemplate string
+
+	// GroupTemplates are text/templates producing the groups of an SVID. Templates
+	// producing an empty string are ignored.
+	GroupTemplates []string
+}
+
+// ID is the SPIFFE ID of an SVID, as passed to username and group templates.
+type ID struct {
+	// ID is the full SPIFFE ID, e.g. "spiffe://example.org/ns/default/sa/builder".
+	ID string
+	// TrustDomain is the trust domain of the ID, e.g. "example.org".
+	TrustDomain string
+	// Path is the path of the ID, e.g. "/ns/default/sa/builder".
+	Path string
+	// Segments are the segments of the path, e.g. ["ns", "default", "sa", "builder"].
+	Segments []string
+}
+
+// New returns a request authenticator that verifies client certificates with the
+// configured verify options and accepts them if they are SVIDs of the configured trust domain.
+func New(config Config) (authenticator.Request, error) {
+	conversion, err := NewUserConversion(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.VerifyOptionsFn == nil {
+		return nil, fmt.Errorf("spiffe: verify options are required")
+	}
+	return x509request.NewDynamic(config.VerifyOptionsFn, conversion), nil
+}
+
+// NewUserConversion returns a UserConversion that accepts verified chains whose leaf
+// is an SVID of the configured trust domain, and maps its SPIFFE ID to a user.
+func NewUserConversion(config Config) (x509request.UserConversion, error) {
+	if !trustDomainRegexp.MatchString(config.TrustDomain) {
+		return nil, fmt.Errorf("spiffe: invalid trust domain %q", config.TrustDomain)
+	}
+	usernameTemplate := config.UsernameTemplate
+	if len(usernameTemplate) == 0 {
+		usernameTemplate = DefaultUsernameTemplate
+	}
+	username, err := template.New("username").Option("missingkey=error").Parse(usernameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("spiffe: invalid username template: %v", err)
+	}
+	var groups []*template.Template
+	for i, text := range config.GroupTemplates {
+		group, err := template.New(fmt.Sprintf("group%d", i)).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("spiffe: invalid group template %q: %v", text, err)
+		}
+		groups = append(groups, group)
+	}
+
+	return x509request.UserConversionFunc(func(chain []*x509.Certificate) (*authenticator.Response, bool, error) {
+		id, err := svidID(chain[0])
+		if err != nil {
+			return nil, false, err
+		}
+		if id.TrustDomain != config.TrustDomain {
+			return nil, false, nil
+		}
+		name, err := execute(username, id)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(name) == 0 {
+			return nil, false, nil
+		}
+		info := &user.DefaultInfo{Name: name}
+		for _, group := range groups {
+			g, err := execute(group, id)
+			if err != nil {
+				return nil, false, err
+			}
+			if len(g) > 0 {
+				info.Groups = append(info.Groups, g)
+			}
+		}
+		return &authenticator.Response{User: info}, true, nil
+	}), nil
+}
+
+// svidID validates that cert is an X.509 SVID and returns its SPIFFE ID.
+func svidID(cert *x509.Certificate) (*ID, error) {
+	if len(cert.URIs) != 1 {
+		return nil, fmt.Errorf("spiffe: an SVID must have exactly one URI SAN, got %d", len(cert.URIs))
+	}
+	if cert.IsCA {
+		return nil, fmt.Errorf("spiffe: a leaf SVID must not be a CA certificate")
+	}
+	if cert.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return nil, fmt.Errorf("spiffe: a leaf SVID must have the digital signature key usage")
+	}
+	return parseID(cert.URIs[0])
+}
+
+func parseID(u *url.URL) (*ID, error) {
+	if u.Scheme != "spiffe" {
+		return nil, fmt.Errorf("spiffe: invalid SPIFFE ID %q: scheme must be spiffe", u)
+	}
+	if u.User != nil || len(u.Port()) > 0 || len(u.RawQuery) > 0 || len(u.Fragment) > 0 {
+		return nil, fmt.Errorf("spiffe: invalid SPIFFE ID %q: must not contain a userinfo, port, query or fragment", u)
+	}
+	if !trustDomainRegexp.MatchString(u.Host) {
+		return nil, fmt.Errorf("spiffe: invalid SPIFFE ID %q: invalid trust domain", u)
+	}
+	id := &ID{ID: u.String(), TrustDomain: u.Host, Path: u.Path}
+	if len(u.Path) > 0 {
+		for _, segment := range strings.Split(strings.TrimPrefix(u.Path, "/"), "/") {
+			if len(segment) == 0 || segment == "." || segment == ".." {
+				return nil, fmt.Errorf("spiffe: invalid SPIFFE ID %q: invalid path", u)
+			}
+			id.Segments = append(id.Segments, segment)
+		}
+	}
+	return id, nil
+}
+
+func execute(t *template.Template, id *ID) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, id); err != nil {
+		return "", fmt.Errorf("spiffe: executing template %s: %v", t.Name(), err)
+	}
+	return buf.String(), nil
+}
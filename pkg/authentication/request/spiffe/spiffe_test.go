@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spiffe
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	x509request "k8s.io/apiserver/pkg/authentication/request/x509"
+)
+
+func newCertificate(t *testing.T, template, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func newSVID(t *testing.T, ca *x509.Certificate, caKey crypto.Signer, ids ...string) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, id := range ids {
+		u, err := url.Parse(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template.URIs = append(template.URIs, u)
+	}
+	cert, _ := newCertificate(t, template, ca, caKey)
+	return cert
+}
+
+func TestSPIFFEAuthenticator(t *testing.T) {
+	ca, caKey := newCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "spire"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, nil)
+	opts := x509request.DefaultVerifyOptions()
+	opts.Roots = x509.NewCertPool()
+	opts.Roots.AddCert(ca)
+
+	a, err := New(Config{
+		TrustDomain:      "example.org",
+		VerifyOptionsFn:  x509request.StaticVerifierFn(opts),
+		UsernameTemplate: "spiffe:{{.TrustDomain}}:{{.Path}}",
+		GroupTemplates: []string{
+			"spiffe:{{.TrustDomain}}",
+			`{{if eq (index .Segments 0) "ns"}}spiffe:ns:{{index .Segments 1}}{{end}}`,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name         string
+		cert         *x509.Certificate
+		expectOK     bool
+		expectErr    bool
+		expectName   string
+		expectGroups []string
+	}{
+		{
+			name:         "workload",
+			cert:         newSVID(t, ca, caKey, "spiffe://example.org/ns/default/sa/builder"),
+			expectOK:     true,
+			expectName:   "spiffe:example.org:/ns/default/sa/builder",
+			expectGroups: []string{"spiffe:example.org", "spiffe:ns:default"},
+		},
+		{
+			name:         "empty group template result",
+			cert:         newSVID(t, ca, caKey, "spiffe://example.org/node/worker-1"),
+			expectOK:     true,
+			expectName:   "spiffe:example.org:/node/worker-1",
+			expectGroups: []string{"spiffe:example.org"},
+		},
+		{
+			name: "other trust domain",
+			cert: newSVID(t, ca, caKey, "spiffe://other.org/ns/default/sa/builder"),
+		},
+		{
+			name:      "not an SVID",
+			cert:      newSVID(t, ca, caKey),
+			expectErr: true,
+		},
+		{
+			name:      "multiple URI SANs",
+			cert:      newSVID(t, ca, caKey, "spiffe://example.org/a", "spiffe://example.org/b"),
+			expectErr: true,
+		},
+		{
+			name:      "invalid path",
+			cert:      newSVID(t, ca, caKey, "spiffe://example.org/a//b"),
+			expectErr: true,
+		},
+		{
+			name:      "wrong scheme",
+			cert:      newSVID(t, ca, caKey, "https://example.org/a"),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &http.Request{TLS: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{tc.cert}}}
+			resp, ok, err := a.AuthenticateRequest(req)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error=%v, got %v", tc.expectErr, err)
+			}
+			if ok != tc.expectOK {
+				t.Fatalf("expected ok=%v, got %v", tc.expectOK, ok)
+			}
+			if !ok {
+				return
+			}
+			if resp.User.GetName() != tc.expectName {
+				t.Errorf("expected name %q, got %q", tc.expectName, resp.User.GetName())
+			}
+			if !reflect.DeepEqual(resp.User.GetGroups(), tc.expectGroups) {
+				t.Errorf("expected groups %v, got %v", tc.expectGroups, resp.User.GetGroups())
+			}
+		})
+	}
+}
+
+func TestNewUserConversionInvalid(t *testing.T) {
+	for name, config := range map[string]Config{
+		"missing trust domain":      {},
+		"invalid trust domain":      {TrustDomain: "Example.org"},
+		"invalid username":          {TrustDomain: "example.org", UsernameTemplate: "{{.ID"},
+		"invalid group":             {TrustDomain: "example.org", GroupTemplates: []string{"{{end}}"}},
+		"missing verify options fn": {TrustDomain: "example.org"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(config); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package throttle limits failed authentication attempts per client, so that credential stuffing
+// does not reach expensive authenticators such as the token review webhook.
+package throttle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/utils/clock"
+)
+
+// Key selects by what failed authentication attempts are counted.
+type Key string
+
+const (
+	// KeySourceIP counts failed attempts per remote address of the connection. Behind a proxy, this
+	// is the address of the proxy.
+	KeySourceIP Key = "source-ip"
+	// KeyCredential counts failed attempts per credential presented in the Authorization header.
+	// Only a hash of the credential is kept.
+	KeyCredential Key = "credential"
+)
+
+// Config describes the throttling of failed authentication attempts.
+type Config struct {
+	// MaxFailures is the number of failed attempts per key allowed within Window. Further requests
+	// of the key are refused until the oldest failure leaves the window.
+	MaxFailures int
+
+	// Window is the duration of the sliding window in which failed attempts are counted.
+	Window time.Duration
+
+	// Keys are the keys by which failed attempts are counted. A request is refused if any of
+	// its keys exceeded MaxFailures.
+	Keys []Key
+
+	// MaxEntries bounds the number of keys whose failures are remembered. The least recently
+	// used keys are forgotten first.
+	MaxEntries int
+}
+
+var throttledRequests = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Namespace:      "apiserver",
+		Subsystem:      "authentication",
+		Name:           "throttled_requests_total",
+		Help:           "Number of requests refused without authentication because of too many failed authentication attempts, partitioned by the key exceeding the limit.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"key"},
+)
+
+func init() {
+	legacyregistry.MustRegister(throttledRequests)
+}
+
+// failures is the sliding log of the failed attempts of a key.
+type failures struct {
+	lock  sync.Mutex
+	times []time.Time
+}
+
+// Throttle counts the failed authentication attempts of the request authenticators it wraps
+// together, e.g. those of the bearer token and the websocket protocol authenticators.
+type Throttle struct {
+	config Config
+	clock  utilcache.Clock
+
+	// lock serializes the creation of the failure logs of new identifiers.
+	lock     sync.Mutex
+	failures *utilcache.LRUExpireCache
+}
+
+type throttler struct {
+	*Throttle
+	authenticator authenticator.Request
+}
+
+// NewThrottle returns a throttle refusing requests whose source or credentials failed to
+// authenticate config.MaxFailures times within config.Window, see Wrap.
+func NewThrottle(config Config) *Throttle {
+	return newThrottleWithClock(config, clock.RealClock{})
+}
+
+func newThrottleWithClock(config Config, clock utilcache.Clock) *Throttle {
+	return &Throttle{
+		config:   config,
+		clock:    clock,
+		failures: utilcache.NewLRUExpireCacheWithClock(config.MaxEntries, clock),
+	}
+}
+
+// Wrap returns a request authenticator that refuses requests whose source or credentials failed
+// to authenticate too often with a throttling error, see authenticator.NewThrottledError, without
+// consulting auth. Only rejections of tokens count as failures, see authenticator.IsTokenRejected,
+// so auth should be a token authenticator: other failures, e.g. an unavailable token review
+// webhook, say nothing about the credentials, and must not lock out the clients sharing a source.
+func (t *Throttle) Wrap(auth authenticator.Request) authenticator.Request {
+	return &throttler{Throttle: t, authenticator: auth}
+}
+
+// New returns a request authenticator throttling auth as configured by config, see Throttle.Wrap.
+func New(auth authenticator.Request, config Config) authenticator.Request {
+	return NewThrottle(config).Wrap(auth)
+}
+
+func newWithClock(auth authenticator.Request, config Config, clock utilcache.Clock) *throttler {
+	return &throttler{Throttle: newThrottleWithClock(config, clock), authenticator: auth}
+}
+
+// AuthenticateRequest implements authenticator.Request
+func (t *throttler) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	keys := t.keys(req)
+	now := t.clock.Now()
+	for _, key := range t.config.Keys {
+		id, ok := keys[key]
+		if !ok {
+			continue
+		}
+		f, found := t.failuresOf(key, id)
+		if !found {
+			continue
+		}
+		if retryAfter, throttled := f.retryAfter(now, t.config); throttled {
+			throttledRequests.WithContext(req.Context()).WithLabelValues(string(key)).Inc()
+			return nil, false, authenticator.NewThrottledError(retryAfter)
+		}
+	}
+
+	resp, ok, err := t.authenticator.AuthenticateRequest(req)
+	// only requests presenting tokens that were rejected count as failures, not requests without
+	// any credentials or those failing for a reason unrelated to the credentials, e.g. timeouts.
+	if !ok && authenticator.IsTokenRejected(err) {
+		for key, id := range keys {
+			t.recordFailure(key, id, t.clock.Now())
+		}
+	}
+	return resp, ok, err
+}
+
+// keys returns the identifiers of req for the configured keys.
+func (t *Throttle) keys(req *http.Request) map[Key]string {
+	keys := map[Key]string{}
+	for _, key := range t.config.Keys {
+		switch key {
+		case KeySourceIP:
+			host, _, err := net.SplitHostPort(req.RemoteAddr)
+			if err != nil {
+				host = req.RemoteAddr
+			}
+			if len(host) > 0 {
+				keys[key] = host
+			}
+		case KeyCredential:
+			if credential := req.Header.Get("Authorization"); len(credential) > 0 {
+				sum := sha256.Sum256([]byte(credential))
+				keys[key] = hex.EncodeToString(sum[:])
+			}
+		}
+	}
+	return keys
+}
+
+// failuresOf returns the failure log of the identifier id of key, if it has one.
+func (t *Throttle) failuresOf(key Key, id string) (*failures, bool) {
+	f, ok := t.failures.Get(string(key) + "/" + id)
+	if !ok {
+		return nil, false
+	}
+	return f.(*failures), true
+}
+
+// recordFailure adds a failure at now to the failure log of the identifier id of key. The log is
+// kept for a window after its latest failure.
+func (t *Throttle) recordFailure(key Key, id string, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	f, ok := t.failuresOf(key, id)
+	if !ok {
+		f = &failures{}
+	}
+	f.add(now, t.config)
+	t.failures.Add(string(key)+"/"+id, f, t.config.Window)
+}
+
+// retryAfter returns the duration until a request may be attempted again, if the failures within
+// the window reached the limit.
+func (f *failures) retryAfter(now time.Time, config Config) (time.Duration, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.expire(now, config.Window)
+	if len(f.times) < config.MaxFailures {
+		return 0, false
+	}
+	return f.times[0].Add(config.Window).Sub(now), true
+}
+
+// add records a failure at now, keeping at most the failures relevant to the limit.
+func (f *failures) add(now time.Time, config Config) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.expire(now, config.Window)
+	f.times = append(f.times, now)
+	if len(f.times) > config.MaxFailures {
+		f.times = f.times[len(f.times)-config.MaxFailures:]
+	}
+}
+
+// expire drops the failures that left the window.
+func (f *failures) expire(now time.Time, window time.Duration) {
+	i := 0
+	for i < len(f.times) && !now.Before(f.times[i].Add(window)) {
+		i++
+	}
+	f.times = f.times[i:]
+}
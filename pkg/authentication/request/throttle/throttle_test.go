@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// fakeAuthenticator accepts the bearer token "good", fails to verify the token "outage", rejects
+// other tokens and ignores requests without one.
+type fakeAuthenticator struct {
+	calls int
+}
+
+func (f *fakeAuthenticator) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	f.calls++
+	switch req.Header.Get("Authorization") {
+	case "":
+		return nil, false, nil
+	case "Bearer good":
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "user"}}, true, nil
+	case "Bearer outage":
+		return nil, false, errors.New("token review webhook unavailable")
+	default:
+		return nil, false, authenticator.NewTokenRejectedError(errors.New("invalid bearer token"))
+	}
+}
+
+func isThrottled(err error) bool {
+	_, throttled := authenticator.ThrottledRetryAfter(err)
+	return throttled
+}
+
+func newRequest(remoteAddr, token string) *http.Request {
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.RemoteAddr = remoteAddr
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func TestThrottleBySourceIP(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	delegate := &fakeAuthenticator{}
+	auth := newWithClock(delegate, Config{MaxFailures: 2, Window: time.Minute, Keys: []Key{KeySourceIP}, MaxEntries: 10}, clock)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := auth.AuthenticateRequest(newRequest("10.0.0.1:1234", "bad")); err == nil || isThrottled(err) {
+			t.Fatalf("expected attempt %d to be rejected by the authenticator, got %v", i, err)
+		}
+		clock.Step(10 * time.Second)
+	}
+	// requests without credentials and successful requests do not count as failures
+	if _, ok, err := auth.AuthenticateRequest(newRequest("10.0.0.2:1234", "")); ok || err != nil {
+		t.Fatalf("expected a request without credentials to pass through, got %v, %v", ok, err)
+	}
+
+	calls := delegate.calls
+	_, _, err := auth.AuthenticateRequest(newRequest("10.0.0.1:4321", "good"))
+	retryAfter, throttled := authenticator.ThrottledRetryAfter(err)
+	if !throttled {
+		t.Fatalf("expected the source to be throttled, got %v", err)
+	}
+	if retryAfter != 40*time.Second {
+		t.Errorf("expected to retry after 40s, got %v", retryAfter)
+	}
+	if delegate.calls != calls {
+		t.Errorf("expected the authenticator not to be consulted for a throttled request")
+	}
+
+	if _, ok, err := auth.AuthenticateRequest(newRequest("10.0.0.2:1234", "good")); !ok || err != nil {
+		t.Fatalf("expected another source not to be throttled, got %v, %v", ok, err)
+	}
+
+	clock.Step(retryAfter)
+	if _, ok, err := auth.AuthenticateRequest(newRequest("10.0.0.1:1234", "good")); !ok || err != nil {
+		t.Fatalf("expected the source to be allowed once the oldest failure left the window, got %v, %v", ok, err)
+	}
+}
+
+func TestThrottleByCredential(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	auth := newWithClock(&fakeAuthenticator{}, Config{MaxFailures: 1, Window: time.Minute, Keys: []Key{KeyCredential}, MaxEntries: 10}, clock)
+
+	if _, _, err := auth.AuthenticateRequest(newRequest("10.0.0.1:1234", "stolen")); err == nil || isThrottled(err) {
+		t.Fatalf("expected the first attempt to be rejected by the authenticator, got %v", err)
+	}
+	if _, _, err := auth.AuthenticateRequest(newRequest("10.0.0.2:1234", "stolen")); !isThrottled(err) {
+		t.Fatalf("expected the credential to be throttled from any source, got %v", err)
+	}
+	if _, ok, err := auth.AuthenticateRequest(newRequest("10.0.0.1:1234", "good")); !ok || err != nil {
+		t.Fatalf("expected other credentials not to be throttled, got %v, %v", ok, err)
+	}
+}
+
+func TestThrottleOnlyRejectedTokens(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	auth := newWithClock(&fakeAuthenticator{}, Config{MaxFailures: 1, Window: time.Minute, Keys: []Key{KeySourceIP}, MaxEntries: 10}, clock)
+
+	// failures to verify the token, e.g. during an outage of the webhook, say nothing about the credentials
+	for i := 0; i < 3; i++ {
+		if _, _, err := auth.AuthenticateRequest(newRequest("10.0.0.1:1234", "outage")); err == nil || isThrottled(err) {
+			t.Fatalf("expected attempt %d to fail in the authenticator, got %v", i, err)
+		}
+	}
+	if _, ok, err := auth.AuthenticateRequest(newRequest("10.0.0.1:1234", "good")); !ok || err != nil {
+		t.Fatalf("expected the source not to be throttled, got %v, %v", ok, err)
+	}
+}
+
+func TestThrottleShared(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	throttle := newThrottleWithClock(Config{MaxFailures: 1, Window: time.Minute, Keys: []Key{KeySourceIP}, MaxEntries: 10}, clock)
+	first, second := throttle.Wrap(&fakeAuthenticator{}), throttle.Wrap(&fakeAuthenticator{})
+
+	if _, _, err := first.AuthenticateRequest(newRequest("10.0.0.1:1234", "bad")); err == nil || isThrottled(err) {
+		t.Fatalf("expected the first attempt to be rejected by the authenticator, got %v", err)
+	}
+	if _, _, err := second.AuthenticateRequest(newRequest("10.0.0.1:1234", "good")); !isThrottled(err) {
+		t.Fatalf("expected the failures to be shared by the authenticators, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package union
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	successLabel = "success"
+	failureLabel = "failure"
+	errorLabel   = "error"
+)
+
+var (
+	authenticatorAttempts = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      "apiserver",
+			Subsystem:      "authentication",
+			Name:           "authenticator_attempts_total",
+			Help:           "Number of authentication attempts of each member of the authenticator union, partitioned by result.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"authenticator", "result"},
+	)
+
+	authenticatorLatency = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      "apiserver",
+			Subsystem:      "authentication",
+			Name:           "authenticator_duration_seconds",
+			Help:           "Authentication latency in seconds of each member of the authenticator union, partitioned by result.",
+			Buckets:        []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"authenticator", "result"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(authenticatorAttempts, authenticatorLatency)
+}
+
+// resultLabel returns the result label of an authentication attempt.
+func resultLabel(ok bool, err error) string {
+	switch {
+	case err != nil:
+		return errorLabel
+	case ok:
+		return successLabel
+	default:
+		return failureLabel
+	}
+}
+
+// recordAttempt records the outcome and latency of a single authentication attempt.
+func recordAttempt(ctx context.Context, name string, ok bool, err error, latency time.Duration) {
+	result := resultLabel(ok, err)
+	authenticatorAttempts.WithContext(ctx).WithLabelValues(name, result).Inc()
+	authenticatorLatency.WithContext(ctx).WithLabelValues(name, result).Observe(latency.Seconds())
+}
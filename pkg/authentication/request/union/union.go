@@ -17,12 +17,25 @@ limitations under the License.
 package union
 
 import (
+	"context"
 	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 )
 
+const (
+	// instrumentationName identifies the tracer of the members of an authenticator union.
+	instrumentationName = "k8s.io/apiserver/pkg/authentication/request/union"
+
+	mechanismAttributeKey = attribute.Key("authentication.mechanism")
+	resultAttributeKey    = attribute.Key("authentication.result")
+)
+
 // unionAuthRequestHandler authenticates requests using a chain of authenticator.Requests
 type unionAuthRequestHandler struct {
 	// Handlers is a chain of request authenticators to delegate to
@@ -49,13 +62,25 @@ func NewFailOnError(authRequestHandlers ...authenticator.Request) authenticator.
 	return &unionAuthRequestHandler{Handlers: authRequestHandlers, FailOnError: true}
 }
 
+// shortCircuitAuthRequestHandler marks an authenticator whose errors end the chain of a union.
+type shortCircuitAuthRequestHandler struct {
+	authenticator.Request
+}
+
+// ShortCircuit returns a request authenticator whose errors short-circuit the chain of a union
+// created by New, for authenticators whose failures are definitive, e.g. a client certificate that
+// is presented but not valid. Its errors are returned without trying the remaining authenticators.
+func ShortCircuit(authRequestHandler authenticator.Request) authenticator.Request {
+	return &shortCircuitAuthRequestHandler{authRequestHandler}
+}
+
 // AuthenticateRequest authenticates the request using a chain of authenticator.Request objects.
 func (authHandler *unionAuthRequestHandler) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
 	var errlist []error
 	for _, currAuthRequestHandler := range authHandler.Handlers {
 		resp, ok, err := currAuthRequestHandler.AuthenticateRequest(req)
 		if err != nil {
-			if authHandler.FailOnError {
+			if _, shortCircuit := currAuthRequestHandler.(*shortCircuitAuthRequestHandler); authHandler.FailOnError || shortCircuit {
 				return resp, ok, err
 			}
 			errlist = append(errlist, err)
@@ -69,3 +94,51 @@ func (authHandler *unionAuthRequestHandler) AuthenticateRequest(req *http.Reques
 
 	return nil, false, utilerrors.NewAggregate(errlist)
 }
+
+// namedAuthRequestHandler reports metrics, and optionally spans, for a member of an authenticator union.
+type namedAuthRequestHandler struct {
+	name    string
+	handler authenticator.Request
+	// tracer records a span for each attempt. It is nil if spans are not recorded.
+	tracer trace.Tracer
+}
+
+// Named returns a request authenticator that records the attempts, outcomes and latency of
+// the given authenticator under name, so that the members of a union can be told apart.
+func Named(name string, authRequestHandler authenticator.Request) authenticator.Request {
+	return &namedAuthRequestHandler{name: name, handler: authRequestHandler}
+}
+
+// NamedWithTracing is like Named, but additionally records each attempt as a span of a tracer
+// provided by tp. Spans of the authenticators used underneath, such as the token cache or the
+// token review webhook, are recorded as its children. If tp is nil, it is equivalent to Named.
+func NamedWithTracing(name string, authRequestHandler authenticator.Request, tp trace.TracerProvider) authenticator.Request {
+	n := &namedAuthRequestHandler{name: name, handler: authRequestHandler}
+	if tp != nil {
+		n.tracer = tp.Tracer(instrumentationName)
+	}
+	return n
+}
+
+// AuthenticateRequest authenticates the request using the wrapped authenticator and records the result.
+func (n *namedAuthRequestHandler) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	var span trace.Span
+	if n.tracer != nil {
+		var ctx context.Context
+		ctx, span = n.tracer.Start(req.Context(), "Authenticate", trace.WithAttributes(mechanismAttributeKey.String(n.name)))
+		defer span.End()
+		req = req.WithContext(ctx)
+	}
+
+	start := time.Now()
+	resp, ok, err := n.handler.AuthenticateRequest(req)
+	recordAttempt(req.Context(), n.name, ok, err, time.Since(start))
+
+	if span != nil {
+		span.SetAttributes(resultAttributeKey.String(resultLabel(ok, err)))
+		if err != nil {
+			span.RecordError(err)
+		}
+	}
+	return resp, ok, err
+}
@@ -23,8 +23,14 @@ import (
 	"strings"
 	"testing"
 
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
 )
 
 type mockAuthRequestHandler struct {
@@ -165,3 +171,104 @@ func TestAuthenticateRequestFailEarly(t *testing.T) {
 		t.Errorf("Unexpectedly authenticated: %v", isAuthenticated)
 	}
 }
+
+func TestAuthenticateRequestShortCircuit(t *testing.T) {
+	handler1 := &mockAuthRequestHandler{err: errors.New("first")}
+	handler2 := ShortCircuit(&mockAuthRequestHandler{err: errors.New("second")})
+	handler3 := &mockAuthRequestHandler{returnUser: user1, isAuthenticated: true}
+	authRequestHandler := New(handler1, handler2, handler3)
+	req, _ := http.NewRequest("GET", "http://example.org", nil)
+
+	_, isAuthenticated, err := authRequestHandler.AuthenticateRequest(req)
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if err.Error() != "second" {
+		t.Errorf("Expected only the error of the short-circuiting handler, got %v", err)
+	}
+	if isAuthenticated {
+		t.Errorf("Unexpectedly authenticated: %v", isAuthenticated)
+	}
+
+	// without an error, the short-circuiting handler passes on to the rest of the chain
+	authRequestHandler = New(ShortCircuit(&mockAuthRequestHandler{}), handler3)
+	resp, isAuthenticated, err := authRequestHandler.AuthenticateRequest(req)
+	if err != nil || !isAuthenticated {
+		t.Fatalf("Expected to be authenticated, got %v, %v", isAuthenticated, err)
+	}
+	if !reflect.DeepEqual(user1, resp.User) {
+		t.Errorf("Expected %v, got %v", user1, resp.User)
+	}
+}
+
+func TestNamedAuthenticatorMetrics(t *testing.T) {
+	authenticatorAttempts.Reset()
+	authenticatorLatency.Reset()
+
+	authRequestHandler := NewFailOnError(
+		New(
+			Named("x509", &mockAuthRequestHandler{}),
+			Named("bearertoken", &mockAuthRequestHandler{returnUser: user1, isAuthenticated: true}),
+		),
+		Named("anonymous", &mockAuthRequestHandler{returnUser: user2, isAuthenticated: true}),
+	)
+	req, _ := http.NewRequest("GET", "http://example.org", nil)
+	if _, ok, err := authRequestHandler.AuthenticateRequest(req); !ok || err != nil {
+		t.Fatalf("expected request to be authenticated, got %v, %v", ok, err)
+	}
+
+	failing := Named("bearertoken", &mockAuthRequestHandler{err: errors.New("boom")})
+	if _, _, err := failing.AuthenticateRequest(req); err == nil {
+		t.Fatal("expected error")
+	}
+
+	expected := `
+		# HELP apiserver_authentication_authenticator_attempts_total [ALPHA] Number of authentication attempts of each member of the authenticator union, partitioned by result.
+		# TYPE apiserver_authentication_authenticator_attempts_total counter
+		apiserver_authentication_authenticator_attempts_total{authenticator="bearertoken",result="error"} 1
+		apiserver_authentication_authenticator_attempts_total{authenticator="bearertoken",result="success"} 1
+		apiserver_authentication_authenticator_attempts_total{authenticator="x509",result="failure"} 1
+	`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "apiserver_authentication_authenticator_attempts_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNamedAuthenticatorTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	authRequestHandler := New(
+		NamedWithTracing("x509", &mockAuthRequestHandler{err: errors.New("boom")}, tp),
+		NamedWithTracing("bearertoken", &mockAuthRequestHandler{returnUser: user1, isAuthenticated: true}, tp),
+	)
+	req, _ := http.NewRequest("GET", "http://example.org", nil)
+	if _, ok, err := authRequestHandler.AuthenticateRequest(req); !ok || err != nil {
+		t.Fatalf("expected request to be authenticated, got %v, %v", ok, err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	expected := []struct {
+		mechanism string
+		result    string
+		errored   bool
+	}{
+		{mechanism: "x509", result: errorLabel, errored: true},
+		{mechanism: "bearertoken", result: successLabel},
+	}
+	for i, span := range spans {
+		attrs := map[attribute.Key]string{}
+		for _, kv := range span.Attributes() {
+			attrs[kv.Key] = kv.Value.Emit()
+		}
+		if attrs[mechanismAttributeKey] != expected[i].mechanism || attrs[resultAttributeKey] != expected[i].result {
+			t.Errorf("span %d: expected mechanism %q and result %q, got %v", i, expected[i].mechanism, expected[i].result, attrs)
+		}
+		if errored := len(span.Events()) > 0; errored != expected[i].errored {
+			t.Errorf("span %d: expected an error to be recorded: %v, got events %v", i, expected[i].errored, span.Events())
+		}
+	}
+}
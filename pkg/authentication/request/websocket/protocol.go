@@ -32,7 +32,8 @@ const bearerProtocolPrefix = "base64url.bearer.authorization.k8s.io."
 
 var protocolHeader = textproto.CanonicalMIMEHeaderKey("Sec-WebSocket-Protocol")
 
-var errInvalidToken = errors.New("invalid bearer token")
+// errInvalidToken is a rejection of the token, as the token authenticator did not accept it.
+var errInvalidToken = authenticator.NewTokenRejectedError(errors.New("invalid bearer token"))
 
 // ProtocolAuthenticator allows a websocket connection to provide a bearer token as a subprotocol
 // in the format "base64url.bearer.authorization.<base64url-without-padding(bearer-token)>"
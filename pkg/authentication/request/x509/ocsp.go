@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package x509
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maxOCSPResponseSize bounds the size of a response read from an OCSP responder.
+	maxOCSPResponseSize = 1 << 20
+
+	// defaultOCSPCacheTTL is used for responses that do not set a next update time.
+	defaultOCSPCacheTTL = 5 * time.Minute
+
+	// maxOCSPResponseAge bounds how long ago a response may have been produced, the longest
+	// validity interval the CA/Browser Forum baseline requirements allow.
+	maxOCSPResponseAge = 10 * 24 * time.Hour
+
+	// ocspClockSkew is the clock skew tolerated between the responder and the server when
+	// checking the validity interval of a response.
+	ocspClockSkew = 5 * time.Minute
+
+	ocspCacheSize = 4096
+)
+
+// OCSPChecker is a RevocationChecker that queries an OCSP responder for the status of
+// the leaf of a chain. Responses are cached until their next update time. Responses that
+// expired or were produced too long ago or in the future are treated as unknown status.
+type OCSPChecker struct {
+	// responder overrides the OCSP servers listed in the certificates.
+	responder string
+	client    *http.Client
+	// softFail accepts certificates whose status could not be determined.
+	softFail bool
+
+	// cache holds the *ocsp.Response of each checked certificate.
+	cache *utilcache.LRUExpireCache
+	now   func() time.Time
+}
+
+var _ RevocationChecker = &OCSPChecker{}
+
+// NewOCSPChecker returns a RevocationChecker that queries the OCSP responder at
+// responder, or the first OCSP server listed in the checked certificate if responder
+// is empty. Revoked certificates are always rejected. If softFail is true,
+// certificates are accepted when no responder is known, the responder cannot be
+// reached or reports an unknown status; otherwise they are rejected.
+func NewOCSPChecker(responder string, client *http.Client, softFail bool) *OCSPChecker {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &OCSPChecker{
+		responder: responder,
+		client:    client,
+		softFail:  softFail,
+		cache:     utilcache.NewLRUExpireCache(ocspCacheSize),
+		now:       time.Now,
+	}
+}
+
+// CheckRevocation implements RevocationChecker
+func (o *OCSPChecker) CheckRevocation(ctx context.Context, chain []*x509.Certificate) error {
+	if len(chain) < 2 {
+		return nil
+	}
+	leaf, issuer := chain[0], chain[1]
+	resp, err := o.status(ctx, leaf, issuer)
+	if err != nil {
+		if o.softFail {
+			klog.V(4).InfoS("Accepting certificate with unknown revocation status", "certificate", certificateIdentifier(leaf), "err", err)
+			return nil
+		}
+		return fmt.Errorf("checking revocation of certificate %s failed: %v", certificateIdentifier(leaf), err)
+	}
+	switch resp.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return authenticator.NewFailureReasonError(authenticator.FailureReasonRevoked, fmt.Errorf("certificate %s has been revoked", certificateIdentifier(leaf)))
+	default:
+		if o.softFail {
+			return nil
+		}
+		return fmt.Errorf("revocation status of certificate %s is unknown", certificateIdentifier(leaf))
+	}
+}
+
+func (o *OCSPChecker) status(ctx context.Context, leaf, issuer *x509.Certificate) (*ocsp.Response, error) {
+	key := string(issuer.RawSubject) + "/" + leaf.SerialNumber.String()
+	if cached, ok := o.cache.Get(key); ok {
+		return cached.(*ocsp.Response), nil
+	}
+
+	responder := o.responder
+	if len(responder) == 0 {
+		if len(leaf.OCSPServer) == 0 {
+			return nil, fmt.Errorf("no OCSP responder configured or listed in the certificate")
+		}
+		responder = leaf.OCSPServer[0]
+	}
+	reqBody, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responder, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("Accept", "application/ocsp-response")
+	httpResp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP responder %s returned %s", responder, httpResp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ocsp.ParseResponseForCert(body, leaf, issuer)
+	if err != nil {
+		return nil, err
+	}
+	now := o.now()
+	if err := checkOCSPValidity(resp, now); err != nil {
+		return nil, fmt.Errorf("OCSP responder %s returned an invalid response: %v", responder, err)
+	}
+
+	ttl := defaultOCSPCacheTTL
+	if !resp.NextUpdate.IsZero() {
+		ttl = resp.NextUpdate.Sub(now)
+	}
+	if maxTTL := resp.ThisUpdate.Add(maxOCSPResponseAge).Sub(now); ttl > maxTTL {
+		ttl = maxTTL
+	}
+	if ttl > 0 {
+		o.cache.Add(key, resp, ttl)
+	}
+	return resp, nil
+}
+
+// checkOCSPValidity returns an error if resp is not valid at now: it must have been produced
+// within maxOCSPResponseAge and, if it has a next update time, that time must not have passed.
+// Both allow for a clock skew of ocspClockSkew.
+func checkOCSPValidity(resp *ocsp.Response, now time.Time) error {
+	if resp.ThisUpdate.After(now.Add(ocspClockSkew)) {
+		return fmt.Errorf("response is not valid before %v", resp.ThisUpdate)
+	}
+	if resp.ThisUpdate.Before(now.Add(-maxOCSPResponseAge - ocspClockSkew)) {
+		return fmt.Errorf("response was produced at %v, more than %v ago", resp.ThisUpdate, maxOCSPResponseAge)
+	}
+	if !resp.NextUpdate.IsZero() && resp.NextUpdate.Before(now.Add(-ocspClockSkew)) {
+		return fmt.Errorf("response expired at %v", resp.NextUpdate)
+	}
+	return nil
+}
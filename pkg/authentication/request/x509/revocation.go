@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package x509
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/util/filewatcher"
+	"k8s.io/klog/v2"
+)
+
+// RevocationChecker checks whether a verified certificate chain has been revoked.
+type RevocationChecker interface {
+	// CheckRevocation returns an error if the leaf of the chain is revoked, or if its
+	// revocation status could not be determined and the chain must not be trusted.
+	CheckRevocation(ctx context.Context, chain []*x509.Certificate) error
+}
+
+// RevocationCheckers checks a chain against each of the checkers in turn.
+type RevocationCheckers []RevocationChecker
+
+// CheckRevocation implements RevocationChecker
+func (r RevocationCheckers) CheckRevocation(ctx context.Context, chain []*x509.Certificate) error {
+	for _, checker := range r {
+		if err := checker.CheckRevocation(ctx, chain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CRLFileRefreshDuration is the interval at which the CRL file is re-read even if
+// no file events were observed. It is exposed so that tests can crank up the reload speed.
+var CRLFileRefreshDuration = 1 * time.Minute
+
+// DynamicCRLFile is a RevocationChecker that rejects certificates listed in a file
+// of PEM or DER encoded certificate revocation lists, and reloads the file whenever
+// it changes. A file that fails to load is rejected and the previously loaded
+// revocation lists stay in effect.
+type DynamicCRLFile struct {
+	// filename is the name of the CRL file to read.
+	filename string
+
+	// crls holds a *loadedCRLs with the last successfully loaded revocation lists.
+	crls atomic.Value
+
+	// watcher reloads the CRL file whenever it changes.
+	watcher *filewatcher.FileWatcher
+}
+
+var _ RevocationChecker = &DynamicCRLFile{}
+
+type loadedCRLs struct {
+	content []byte
+	lists   []*x509.RevocationList
+	// revoked holds the revoked serial numbers of each list, by list index.
+	revoked []map[string]bool
+}
+
+// NewDynamicCRLFile creates a RevocationChecker that watches the CRL file at the
+// given path. The initial file must load successfully.
+// Run must be called for the file to be watched.
+func NewDynamicCRLFile(path string) (*DynamicCRLFile, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("file path not specified")
+	}
+	c := &DynamicCRLFile{
+		filename: path,
+	}
+	c.watcher = filewatcher.New("DynamicCRLFile", path, c.loadCRLs)
+	if err := c.loadCRLs(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CheckRevocation rejects the leaf of the chain if a revocation list issued and signed
+// by the certificate that issued the leaf lists its serial number.
+func (c *DynamicCRLFile) CheckRevocation(ctx context.Context, chain []*x509.Certificate) error {
+	if len(chain) < 2 {
+		return nil
+	}
+	leaf, issuer := chain[0], chain[1]
+	loaded := c.crls.Load().(*loadedCRLs)
+	for i, list := range loaded.lists {
+		if !bytes.Equal(list.RawIssuer, issuer.RawSubject) {
+			continue
+		}
+		if err := list.CheckSignatureFrom(issuer); err != nil {
+			continue
+		}
+		if loaded.revoked[i][leaf.SerialNumber.String()] {
+			return authenticator.NewFailureReasonError(authenticator.FailureReasonRevoked, fmt.Errorf("certificate %s has been revoked", certificateIdentifier(leaf)))
+		}
+	}
+	return nil
+}
+
+// parseCRLs parses PEM encoded "X509 CRL" blocks, or a single DER encoded revocation list.
+func parseCRLs(content []byte) ([]*x509.RevocationList, error) {
+	var lists []*x509.RevocationList
+	rest := content
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "X509 CRL" {
+			continue
+		}
+		list, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	if len(lists) > 0 {
+		return lists, nil
+	}
+	list, err := x509.ParseRevocationList(content)
+	if err != nil {
+		return nil, fmt.Errorf("no PEM encoded CRL found and not a DER encoded CRL: %v", err)
+	}
+	return []*x509.RevocationList{list}, nil
+}
+
+// loadCRLs reads the CRL file and swaps the revocation lists if the content changed.
+func (c *DynamicCRLFile) loadCRLs() error {
+	content, err := ioutil.ReadFile(c.filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file path %q: %v", c.filename, err)
+	}
+	if existing, ok := c.crls.Load().(*loadedCRLs); ok && bytes.Equal(existing.content, content) {
+		return nil
+	}
+
+	lists, err := parseCRLs(content)
+	if err != nil {
+		return fmt.Errorf("%v: from file %v", err, c.filename)
+	}
+	loaded := &loadedCRLs{content: content, lists: lists}
+	var entries int
+	for _, list := range lists {
+		revoked := make(map[string]bool, len(list.RevokedCertificates))
+		for _, entry := range list.RevokedCertificates {
+			revoked[entry.SerialNumber.String()] = true
+		}
+		loaded.revoked = append(loaded.revoked, revoked)
+		entries += len(revoked)
+	}
+	c.crls.Store(loaded)
+	klog.V(2).InfoS("Loaded certificate revocation lists", "file", c.filename, "lists", len(lists), "revoked", entries)
+	return nil
+}
+
+// RunOnce runs a single sync loop
+func (c *DynamicCRLFile) RunOnce(ctx context.Context) error {
+	return c.loadCRLs()
+}
+
+// Run starts the controller and blocks until ctx is done.
+func (c *DynamicCRLFile) Run(ctx context.Context, workers int) {
+	c.watcher.Run(ctx, CRLFileRefreshDuration)
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package x509
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, serial int64, commonName string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func (ca *testCA) crl(t *testing.T, revoked ...int64) []byte {
+	t.Helper()
+	template := &x509.RevocationList{
+		Number:     big.NewInt(time.Now().UnixNano()),
+		ThisUpdate: time.Now().Add(-time.Minute),
+		NextUpdate: time.Now().Add(time.Hour),
+	}
+	for _, serial := range revoked {
+		template.RevokedCertificates = append(template.RevokedCertificates, pkix.RevokedCertificate{
+			SerialNumber:   big.NewInt(serial),
+			RevocationTime: time.Now().Add(-time.Minute),
+		})
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, template, ca.cert, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func pemCRL(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+func TestDynamicCRLFile(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	other := newTestCA(t, "other-ca")
+	leaf1 := ca.issue(t, 10, "one")
+	leaf2 := ca.issue(t, 20, "two")
+
+	path := filepath.Join(t.TempDir(), "crl.pem")
+	write := func(content []byte) {
+		t.Helper()
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectRevoked := func(c *DynamicCRLFile, leaf *x509.Certificate, revoked bool) {
+		t.Helper()
+		err := c.CheckRevocation(context.Background(), []*x509.Certificate{leaf, ca.cert})
+		if (err != nil) != revoked {
+			t.Errorf("%s: expected revoked=%v, got %v", leaf.Subject.CommonName, revoked, err)
+		}
+		if reason, _ := authenticator.FailureReasonFromError(err); revoked && reason != authenticator.FailureReasonRevoked {
+			t.Errorf("%s: expected failure reason %q, got %q", leaf.Subject.CommonName, authenticator.FailureReasonRevoked, reason)
+		}
+	}
+
+	// the CRL of another issuer does not apply
+	write(append(pemCRL(ca.crl(t, 10)), pemCRL(other.crl(t, 20))...))
+	c, err := NewDynamicCRLFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRevoked(c, leaf1, true)
+	expectRevoked(c, leaf2, false)
+
+	// DER encoded lists are reloaded
+	write(ca.crl(t, 20))
+	if err := c.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expectRevoked(c, leaf1, false)
+	expectRevoked(c, leaf2, true)
+
+	// an invalid file keeps the previous lists
+	write([]byte("garbage"))
+	if err := c.RunOnce(context.Background()); err == nil {
+		t.Fatal("expected an invalid CRL file to be rejected")
+	}
+	expectRevoked(c, leaf2, true)
+
+	if _, err := NewDynamicCRLFile(path); err == nil {
+		t.Error("expected an invalid initial CRL file to be rejected")
+	}
+}
+
+func TestOCSPChecker(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	good := ca.issue(t, 10, "good")
+	revoked := ca.issue(t, 20, "revoked")
+
+	var requests int
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		status := ocsp.Good
+		if req.SerialNumber.Cmp(revoked.SerialNumber) == 0 {
+			status = ocsp.Revoked
+		}
+		resp, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:       status,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, ca.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(resp)
+	}))
+	defer server.Close()
+
+	checker := NewOCSPChecker(server.URL, server.Client(), false)
+	if err := checker.CheckRevocation(context.Background(), []*x509.Certificate{good, ca.cert}); err != nil {
+		t.Errorf("expected good certificate to be accepted: %v", err)
+	}
+	if err := checker.CheckRevocation(context.Background(), []*x509.Certificate{revoked, ca.cert}); err == nil || !strings.Contains(err.Error(), "revoked") {
+		t.Errorf("expected revoked certificate to be rejected, got %v", err)
+	}
+	// responses are cached until their next update
+	if err := checker.CheckRevocation(context.Background(), []*x509.Certificate{good, ca.cert}); err != nil {
+		t.Errorf("expected good certificate to be accepted: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests to the responder, got %d", requests)
+	}
+
+	failing = true
+	unknown := ca.issue(t, 30, "unknown")
+	if err := checker.CheckRevocation(context.Background(), []*x509.Certificate{unknown, ca.cert}); err == nil {
+		t.Error("expected a certificate with unknown status to be rejected")
+	}
+	softChecker := NewOCSPChecker(server.URL, server.Client(), true)
+	if err := softChecker.CheckRevocation(context.Background(), []*x509.Certificate{unknown, ca.cert}); err != nil {
+		t.Errorf("expected soft fail to accept a certificate with unknown status: %v", err)
+	}
+}
+
+func TestOCSPCheckerValidity(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	now := time.Now()
+
+	testCases := []struct {
+		name       string
+		thisUpdate time.Time
+		nextUpdate time.Time
+		expectErr  bool
+	}{
+		{
+			name:       "valid",
+			thisUpdate: now.Add(-time.Hour),
+			nextUpdate: now.Add(time.Hour),
+		},
+		{
+			name:       "no next update",
+			thisUpdate: now.Add(-time.Hour),
+		},
+		{
+			name:       "expired",
+			thisUpdate: now.Add(-2 * time.Hour),
+			nextUpdate: now.Add(-time.Hour),
+			expectErr:  true,
+		},
+		{
+			name:       "expired within clock skew",
+			thisUpdate: now.Add(-2 * time.Hour),
+			nextUpdate: now.Add(-time.Minute),
+		},
+		{
+			name:       "produced in the future",
+			thisUpdate: now.Add(time.Hour),
+			nextUpdate: now.Add(2 * time.Hour),
+			expectErr:  true,
+		},
+		{
+			name:       "produced in the future within clock skew",
+			thisUpdate: now.Add(time.Minute),
+			nextUpdate: now.Add(time.Hour),
+		},
+		{
+			name:       "produced too long ago",
+			thisUpdate: now.Add(-maxOCSPResponseAge - time.Hour),
+			nextUpdate: now.Add(time.Hour),
+			expectErr:  true,
+		},
+		{
+			name:       "produced too long ago without next update",
+			thisUpdate: now.Add(-maxOCSPResponseAge - time.Hour),
+			expectErr:  true,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cert := ca.issue(t, int64(100+i), tc.name)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				resp, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+					Status:       ocsp.Good,
+					SerialNumber: cert.SerialNumber,
+					ThisUpdate:   tc.thisUpdate,
+					NextUpdate:   tc.nextUpdate,
+				}, ca.key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				w.Write(resp)
+			}))
+			defer server.Close()
+
+			checker := NewOCSPChecker(server.URL, server.Client(), false)
+			checker.now = func() time.Time { return now }
+			err := checker.CheckRevocation(context.Background(), []*x509.Certificate{cert, ca.cert})
+			if tc.expectErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorRevocation(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	leaf1 := ca.issue(t, 10, "one")
+	leaf2 := ca.issue(t, 20, "two")
+
+	path := filepath.Join(t.TempDir(), "crl.pem")
+	if err := os.WriteFile(path, pemCRL(ca.crl(t, 10)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	crl, err := NewDynamicCRLFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultVerifyOptions()
+	opts.Roots = x509.NewCertPool()
+	opts.Roots.AddCert(ca.cert)
+	a := NewDynamicWithRevocation(StaticVerifierFn(opts), CommonNameUserConversion, RevocationCheckers{crl})
+
+	for _, tc := range []struct {
+		leaf       *x509.Certificate
+		expectUser bool
+	}{
+		{leaf: leaf1, expectUser: false},
+		{leaf: leaf2, expectUser: true},
+	} {
+		req := &http.Request{TLS: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{tc.leaf}}}
+		resp, ok, err := a.AuthenticateRequest(req)
+		if tc.expectUser {
+			if !ok || err != nil || resp.User.GetName() != tc.leaf.Subject.CommonName {
+				t.Errorf("%s: expected user, got %v, %v, %v", tc.leaf.Subject.CommonName, resp, ok, err)
+			}
+		} else if ok || err == nil {
+			t.Errorf("%s: expected revoked certificate to be rejected, got %v, %v", tc.leaf.Subject.CommonName, ok, err)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package x509
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+const (
+	// UsernameFromCommonName takes the username from the subject's CommonName.
+	UsernameFromCommonName = "CommonName"
+	// UsernameFromURISAN takes the username from the first URI subject alternative name.
+	UsernameFromURISAN = "URISAN"
+	// UsernameFromEmailSAN takes the username from the first email subject alternative name.
+	UsernameFromEmailSAN = "EmailSAN"
+
+	// GroupsFromOrganization takes the groups from the subject's Organizations.
+	GroupsFromOrganization = "Organization"
+	// GroupsFromOrganizationalUnit takes the groups from the subject's OrganizationalUnits.
+	GroupsFromOrganizationalUnit = "OrganizationalUnit"
+)
+
+// UserMapping configures how the user of a client certificate is derived from its attributes.
+type UserMapping struct {
+	// UsernameSource is the certificate attribute the username is taken from. One of
+	// UsernameFromCommonName, UsernameFromURISAN, UsernameFromEmailSAN, or the dotted
+	// object identifier of a subject attribute, e.g. "0.9.2342.19200300.100.1.1" for UID.
+	// Defaults to UsernameFromCommonName.
+	UsernameSource string
+
+	// UsernamePattern, if set, is a regular expression the username must match for the
+	// certificate to be mapped to a user.
+	UsernamePattern string
+	// UsernameReplacement, if set, rewrites the username with the matches of UsernamePattern,
+	// using the syntax of regexp.Regexp.ReplaceAllString.
+	UsernameReplacement string
+
+	// GroupsSource is the subject attribute groups are taken from. One of GroupsFromOrganization
+	// and GroupsFromOrganizationalUnit. Defaults to GroupsFromOrganization.
+	GroupsSource string
+
+	// GroupsPattern, if set, is a regular expression values must match to become groups.
+	// Other values are ignored.
+	GroupsPattern string
+	// GroupsReplacement, if set, rewrites each group with the matches of GroupsPattern.
+	GroupsReplacement string
+}
+
+// NewUserConversion returns a UserConversion that builds user info from a certificate
+// chain as configured by the mapping.
+func NewUserConversion(m UserMapping) (UserConversion, error) {
+	usernameFn, err := usernameSource(m.UsernameSource)
+	if err != nil {
+		return nil, err
+	}
+	var groupsFn func(*x509.Certificate) []string
+	switch m.GroupsSource {
+	case "", GroupsFromOrganization:
+		groupsFn = func(c *x509.Certificate) []string { return c.Subject.Organization }
+	case GroupsFromOrganizationalUnit:
+		groupsFn = func(c *x509.Certificate) []string { return c.Subject.OrganizationalUnit }
+	default:
+		return nil, fmt.Errorf("unknown groups source %q, must be one of %s or %s", m.GroupsSource, GroupsFromOrganization, GroupsFromOrganizationalUnit)
+	}
+	usernameRewrite, err := newRewrite(m.UsernamePattern, m.UsernameReplacement)
+	if err != nil {
+		return nil, fmt.Errorf("invalid username pattern: %v", err)
+	}
+	groupsRewrite, err := newRewrite(m.GroupsPattern, m.GroupsReplacement)
+	if err != nil {
+		return nil, fmt.Errorf("invalid groups pattern: %v", err)
+	}
+
+	return UserConversionFunc(func(chain []*x509.Certificate) (*authenticator.Response, bool, error) {
+		username, ok := usernameRewrite.apply(usernameFn(chain[0]))
+		if !ok || len(username) == 0 {
+			return nil, false, nil
+		}
+		var groups []string
+		for _, group := range groupsFn(chain[0]) {
+			if group, ok := groupsRewrite.apply(group); ok && len(group) > 0 {
+				groups = append(groups, group)
+			}
+		}
+		return &authenticator.Response{
+			User: &user.DefaultInfo{
+				Name:   username,
+				Groups: groups,
+			},
+		}, true, nil
+	}), nil
+}
+
+func usernameSource(source string) (func(*x509.Certificate) string, error) {
+	switch source {
+	case "", UsernameFromCommonName:
+		return func(c *x509.Certificate) string { return c.Subject.CommonName }, nil
+	case UsernameFromURISAN:
+		return func(c *x509.Certificate) string {
+			if len(c.URIs) == 0 {
+				return ""
+			}
+			return c.URIs[0].String()
+		}, nil
+	case UsernameFromEmailSAN:
+		return func(c *x509.Certificate) string {
+			if len(c.EmailAddresses) == 0 {
+				return ""
+			}
+			return c.EmailAddresses[0]
+		}, nil
+	}
+	oid, err := parseOID(source)
+	if err != nil {
+		return nil, fmt.Errorf("unknown username source %q, must be one of %s, %s, %s or an object identifier",
+			source, UsernameFromCommonName, UsernameFromURISAN, UsernameFromEmailSAN)
+	}
+	return func(c *x509.Certificate) string {
+		for _, name := range c.Subject.Names {
+			if name.Type.Equal(oid) {
+				if value, ok := name.Value.(string); ok {
+					return value
+				}
+			}
+		}
+		return ""
+	}, nil
+}
+
+func parseOID(s string) (asn1.ObjectIdentifier, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("object identifier %q must have at least two components", s)
+	}
+	oid := make(asn1.ObjectIdentifier, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid object identifier %q", s)
+		}
+		oid = append(oid, n)
+	}
+	return oid, nil
+}
+
+// rewrite filters and rewrites attribute values with a regular expression.
+type rewrite struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func newRewrite(pattern, replacement string) (*rewrite, error) {
+	if len(pattern) == 0 {
+		if len(replacement) > 0 {
+			return nil, fmt.Errorf("a replacement requires a pattern")
+		}
+		return &rewrite{}, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &rewrite{pattern: re, replacement: replacement}, nil
+}
+
+// apply returns the rewritten value and whether the value matched the pattern.
+func (r *rewrite) apply(value string) (string, bool) {
+	if r.pattern == nil {
+		return value, true
+	}
+	if !r.pattern.MatchString(value) {
+		return "", false
+	}
+	if len(r.replacement) == 0 {
+		return value, true
+	}
+	return r.pattern.ReplaceAllString(value, r.replacement), true
+}
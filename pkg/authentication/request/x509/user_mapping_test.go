@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package x509
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestNewUserConversion(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spiffeID, _ := url.Parse("spiffe://example.com/ns/default/sa/builder")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject: pkix.Name{
+			CommonName:         "CN=jane",
+			Organization:       []string{"acme"},
+			OrganizationalUnit: []string{"team-platform", "team-storage", "building-7"},
+			ExtraNames: []pkix.AttributeTypeAndValue{
+				{Type: asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 1}, Value: "jdoe"},
+			},
+		},
+		URIs:           []*url.URL{spiffeID},
+		EmailAddresses: []string{"jane@example.com"},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name         string
+		mapping      UserMapping
+		expectErr    bool
+		expectOK     bool
+		expectName   string
+		expectGroups []string
+	}{
+		{
+			name:         "default",
+			expectOK:     true,
+			expectName:   "CN=jane",
+			expectGroups: []string{"acme"},
+		},
+		{
+			name:         "uri san",
+			mapping:      UserMapping{UsernameSource: UsernameFromURISAN},
+			expectOK:     true,
+			expectName:   "spiffe://example.com/ns/default/sa/builder",
+			expectGroups: []string{"acme"},
+		},
+		{
+			name:         "email san",
+			mapping:      UserMapping{UsernameSource: UsernameFromEmailSAN},
+			expectOK:     true,
+			expectName:   "jane@example.com",
+			expectGroups: []string{"acme"},
+		},
+		{
+			name:         "uid oid",
+			mapping:      UserMapping{UsernameSource: "0.9.2342.19200300.100.1.1"},
+			expectOK:     true,
+			expectName:   "jdoe",
+			expectGroups: []string{"acme"},
+		},
+		{
+			name:     "missing oid",
+			mapping:  UserMapping{UsernameSource: "1.2.3.4"},
+			expectOK: false,
+		},
+		{
+			name: "username rewrite",
+			mapping: UserMapping{
+				UsernamePattern:     "^CN=(.*)$",
+				UsernameReplacement: "pki:$1",
+			},
+			expectOK:     true,
+			expectName:   "pki:jane",
+			expectGroups: []string{"acme"},
+		},
+		{
+			name:     "username not matching",
+			mapping:  UserMapping{UsernamePattern: "^admin-"},
+			expectOK: false,
+		},
+		{
+			name: "groups from matching organizational units",
+			mapping: UserMapping{
+				GroupsSource:      GroupsFromOrganizationalUnit,
+				GroupsPattern:     "^team-(.*)$",
+				GroupsReplacement: "$1",
+			},
+			expectOK:     true,
+			expectName:   "CN=jane",
+			expectGroups: []string{"platform", "storage"},
+		},
+		{
+			name:      "unknown username source",
+			mapping:   UserMapping{UsernameSource: "Serial"},
+			expectErr: true,
+		},
+		{
+			name:      "unknown groups source",
+			mapping:   UserMapping{GroupsSource: "Locality"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid pattern",
+			mapping:   UserMapping{UsernamePattern: "("},
+			expectErr: true,
+		},
+		{
+			name:      "replacement without pattern",
+			mapping:   UserMapping{GroupsReplacement: "$1"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conversion, err := NewUserConversion(tc.mapping)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error=%v, got %v", tc.expectErr, err)
+			}
+			if err != nil {
+				return
+			}
+			resp, ok, err := conversion.User([]*x509.Certificate{cert, ca.cert})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tc.expectOK {
+				t.Fatalf("expected ok=%v, got %v", tc.expectOK, ok)
+			}
+			if !ok {
+				return
+			}
+			if resp.User.GetName() != tc.expectName {
+				t.Errorf("expected name %q, got %q", tc.expectName, resp.User.GetName())
+			}
+			// attributes of the same type may be reordered by the DER encoding
+			groups := resp.User.GetGroups()
+			sort.Strings(groups)
+			if !reflect.DeepEqual(groups, tc.expectGroups) {
+				t.Errorf("expected groups %v, got %v", tc.expectGroups, resp.User.GetGroups())
+			}
+		})
+	}
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package x509
 
 import (
+	"context"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -29,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/warning"
 	"k8s.io/component-base/metrics"
 	"k8s.io/component-base/metrics/legacyregistry"
 )
@@ -41,34 +44,50 @@ import (
  * involves explicitly acknowledging support for the metric across multiple releases, in accordance with
  * the metric stability policy.
  */
+var clientCertificateExpirationBuckets = []float64{
+	0,
+	(30 * time.Minute).Seconds(),
+	(1 * time.Hour).Seconds(),
+	(2 * time.Hour).Seconds(),
+	(6 * time.Hour).Seconds(),
+	(12 * time.Hour).Seconds(),
+	(24 * time.Hour).Seconds(),
+	(2 * 24 * time.Hour).Seconds(),
+	(4 * 24 * time.Hour).Seconds(),
+	(7 * 24 * time.Hour).Seconds(),
+	(30 * 24 * time.Hour).Seconds(),
+	(3 * 30 * 24 * time.Hour).Seconds(),
+	(6 * 30 * 24 * time.Hour).Seconds(),
+	(12 * 30 * 24 * time.Hour).Seconds(),
+}
+
 var clientCertificateExpirationHistogram = metrics.NewHistogram(
 	&metrics.HistogramOpts{
-		Namespace: "apiserver",
-		Subsystem: "client",
-		Name:      "certificate_expiration_seconds",
-		Help:      "Distribution of the remaining lifetime on the certificate used to authenticate a request.",
-		Buckets: []float64{
-			0,
-			(30 * time.Minute).Seconds(),
-			(1 * time.Hour).Seconds(),
-			(2 * time.Hour).Seconds(),
-			(6 * time.Hour).Seconds(),
-			(12 * time.Hour).Seconds(),
-			(24 * time.Hour).Seconds(),
-			(2 * 24 * time.Hour).Seconds(),
-			(4 * 24 * time.Hour).Seconds(),
-			(7 * 24 * time.Hour).Seconds(),
-			(30 * 24 * time.Hour).Seconds(),
-			(3 * 30 * 24 * time.Hour).Seconds(),
-			(6 * 30 * 24 * time.Hour).Seconds(),
-			(12 * 30 * 24 * time.Hour).Seconds(),
-		},
+		Namespace:      "apiserver",
+		Subsystem:      "client",
+		Name:           "certificate_expiration_seconds",
+		Help:           "Distribution of the remaining lifetime on the certificate used to authenticate a request.",
+		Buckets:        clientCertificateExpirationBuckets,
+		StabilityLevel: metrics.ALPHA,
+	},
+)
+
+// authenticatedClientCertificateExpirationHistogram only observes certificates that authenticated a
+// request, unlike clientCertificateExpirationHistogram, which observes every presented certificate.
+var authenticatedClientCertificateExpirationHistogram = metrics.NewHistogram(
+	&metrics.HistogramOpts{
+		Namespace:      "apiserver",
+		Subsystem:      "client",
+		Name:           "authenticated_certificate_expiration_seconds",
+		Help:           "Distribution of the remaining lifetime on the verified certificate that authenticated a request.",
+		Buckets:        clientCertificateExpirationBuckets,
 		StabilityLevel: metrics.ALPHA,
 	},
 )
 
 func init() {
 	legacyregistry.MustRegister(clientCertificateExpirationHistogram)
+	legacyregistry.MustRegister(authenticatedClientCertificateExpirationHistogram)
 }
 
 // UserConversion defines an interface for extracting user info from a client certificate chain
@@ -115,6 +134,13 @@ type VerifyOptionFunc func() (x509.VerifyOptions, bool)
 type Authenticator struct {
 	verifyOptionsFn VerifyOptionFunc
 	user            UserConversion
+
+	// revocationChecker, if set, rejects verified chains whose client certificate has been revoked.
+	revocationChecker RevocationChecker
+
+	// expirationWarningThreshold, if positive, is the remaining lifetime below which clients are
+	// warned that their certificate is about to expire.
+	expirationWarningThreshold time.Duration
 }
 
 // New returns a request.Authenticator that verifies client certificates using the provided
@@ -126,7 +152,20 @@ func New(opts x509.VerifyOptions, user UserConversion) *Authenticator {
 // NewDynamic returns a request.Authenticator that verifies client certificates using the provided
 // VerifyOptionFunc (which may be dynamic), and converts valid certificate chains into user.Info using the provided UserConversion
 func NewDynamic(verifyOptionsFn VerifyOptionFunc, user UserConversion) *Authenticator {
-	return &Authenticator{verifyOptionsFn, user}
+	return &Authenticator{verifyOptionsFn: verifyOptionsFn, user: user}
+}
+
+// NewDynamicWithRevocation returns a request.Authenticator like NewDynamic that additionally
+// rejects client certificates which the provided RevocationChecker reports as revoked.
+func NewDynamicWithRevocation(verifyOptionsFn VerifyOptionFunc, user UserConversion, revocationChecker RevocationChecker) *Authenticator {
+	return &Authenticator{verifyOptionsFn: verifyOptionsFn, user: user, revocationChecker: revocationChecker}
+}
+
+// WarnOnExpiration makes the authenticator add a warning to the responses to requests authenticated
+// with a client certificate that expires within threshold, so that clients failing to rotate their
+// certificate are noticed before it expires. It must be called before the authenticator is used.
+func (a *Authenticator) WarnOnExpiration(threshold time.Duration) {
+	a.expirationWarningThreshold = threshold
 }
 
 // AuthenticateRequest authenticates the request using presented client certificates
@@ -152,15 +191,26 @@ func (a *Authenticator) AuthenticateRequest(req *http.Request) (*authenticator.R
 	clientCertificateExpirationHistogram.WithContext(req.Context()).Observe(remaining.Seconds())
 	chains, err := req.TLS.PeerCertificates[0].Verify(optsCopy)
 	if err != nil {
-		return nil, false, fmt.Errorf(
+		verifyErr := fmt.Errorf(
 			"verifying certificate %s failed: %w",
 			certificateIdentifier(req.TLS.PeerCertificates[0]),
 			err,
 		)
+		if reason, ok := verifyFailureReason(err); ok {
+			verifyErr = authenticator.NewFailureReasonError(reason, verifyErr)
+		}
+		return nil, false, verifyErr
 	}
 
 	var errlist []error
 	for _, chain := range chains {
+		if a.revocationChecker != nil {
+			if err := a.revocationChecker.CheckRevocation(req.Context(), chain); err != nil {
+				errlist = append(errlist, err)
+				continue
+			}
+		}
+
 		user, ok, err := a.user.User(chain)
 		if err != nil {
 			errlist = append(errlist, err)
@@ -168,12 +218,38 @@ func (a *Authenticator) AuthenticateRequest(req *http.Request) (*authenticator.R
 		}
 
 		if ok {
+			a.recordExpiration(req.Context(), chain[0])
 			return user, ok, err
 		}
 	}
 	return nil, false, utilerrors.NewAggregate(errlist)
 }
 
+// recordExpiration observes the remaining lifetime of a certificate that authenticated a request, and
+// warns the client if it is about to expire.
+func (a *Authenticator) recordExpiration(ctx context.Context, cert *x509.Certificate) {
+	remaining := time.Until(cert.NotAfter)
+	authenticatedClientCertificateExpirationHistogram.WithContext(ctx).Observe(remaining.Seconds())
+	if a.expirationWarningThreshold > 0 && remaining < a.expirationWarningThreshold {
+		warning.AddWarning(ctx, "", fmt.Sprintf("the client certificate used to authenticate expires in %v, at %s; renew it to avoid losing access",
+			remaining.Round(time.Second), cert.NotAfter.UTC().Format(time.RFC3339)))
+	}
+}
+
+// verifyFailureReason returns the reason for which a client certificate failed to verify, if it is
+// one that can be told to the client.
+func verifyFailureReason(err error) (authenticator.FailureReason, bool) {
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired {
+		return authenticator.FailureReasonExpired, true
+	}
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthorityErr) {
+		return authenticator.FailureReasonUnknownIssuer, true
+	}
+	return "", false
+}
+
 // Verifier implements request.Authenticator by verifying a client cert on the request, then delegating to the wrapped auth
 type Verifier struct {
 	verifyOptionsFn VerifyOptionFunc
@@ -17,6 +17,7 @@ limitations under the License.
 package x509
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/warning"
 )
 
 const (
@@ -806,3 +808,46 @@ func TestCertificateIdentifier(t *testing.T) {
 		})
 	}
 }
+
+type fakeWarningRecorder struct {
+	warnings []string
+}
+
+func (r *fakeWarningRecorder) AddWarning(agent, text string) {
+	r.warnings = append(r.warnings, text)
+}
+
+func TestX509ExpirationWarning(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	leaf := ca.issue(t, 10, "client") // expires in an hour
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	opts := x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+
+	testCases := []struct {
+		name          string
+		threshold     time.Duration
+		expectWarning bool
+	}{
+		{name: "disabled", threshold: 0},
+		{name: "below threshold", threshold: 2 * time.Hour, expectWarning: true},
+		{name: "above threshold", threshold: 30 * time.Minute},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := New(opts, CommonNameUserConversion)
+			a.WarnOnExpiration(tc.threshold)
+
+			recorder := &fakeWarningRecorder{}
+			req := (&http.Request{TLS: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}}}).
+				WithContext(warning.WithWarningRecorder(context.Background(), recorder))
+			resp, ok, err := a.AuthenticateRequest(req)
+			if err != nil || !ok || resp.User.GetName() != "client" {
+				t.Fatalf("expected the request to be authenticated, got %v, %v, %v", resp, ok, err)
+			}
+			if warned := len(recorder.warnings) > 0; warned != tc.expectWarning {
+				t.Errorf("expected warning=%v, got %v", tc.expectWarning, recorder.warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package binding enforces certificate-bound access tokens as described by RFC 8705: a token
+// carrying a confirmation claim with the thumbprint of a client certificate is only accepted
+// from a client presenting that certificate over TLS, so that a stolen token is of no use alone.
+package binding
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+)
+
+type key int
+
+const peerCertificateKey key = iota
+
+// WithPeerCertificate returns a copy of parent in which the client certificate of the TLS connection is set.
+func WithPeerCertificate(parent context.Context, cert *x509.Certificate) context.Context {
+	return context.WithValue(parent, peerCertificateKey, cert)
+}
+
+// PeerCertificateFrom returns the client certificate of the TLS connection, if set.
+func PeerCertificateFrom(ctx context.Context) (*x509.Certificate, bool) {
+	cert, ok := ctx.Value(peerCertificateKey).(*x509.Certificate)
+	return cert, ok && cert != nil
+}
+
+// WithRequestPeerCertificate returns a request authenticator that makes the client certificate
+// presented on the TLS connection of a request available to the token authenticators used by auth.
+func WithRequestPeerCertificate(auth authenticator.Request) authenticator.Request {
+	return authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+			// the copy shares the headers of req, so that authenticators can still remove credentials from them
+			req = req.WithContext(WithPeerCertificate(req.Context(), req.TLS.PeerCertificates[0]))
+		}
+		return auth.AuthenticateRequest(req)
+	})
+}
+
+var (
+	errMissingCertificate    = errors.New("token is bound to a client certificate, but none was presented")
+	errCertificateMismatch   = errors.New("token is bound to a client certificate other than the one presented")
+	errMalformedConfirmation = errors.New("token has a malformed confirmation claim")
+	errUnsupportedBinding    = errors.New("token is bound by a confirmation method other than a client certificate")
+)
+
+type certificateBinding struct {
+	authenticator authenticator.Token
+}
+
+// New returns a token authenticator that rejects JWTs bound to a client certificate by an
+// x5t#S256 confirmation claim unless the context carries that certificate, see WithPeerCertificate.
+// JWTs bound by other confirmation methods are rejected, since their binding cannot be checked.
+// Other tokens are delegated to auth unchanged. The binding is checked before auth is consulted,
+// so auth must verify the signature of the tokens it accepts.
+func New(auth authenticator.Token) authenticator.Token {
+	return &certificateBinding{authenticator: auth}
+}
+
+// AuthenticateToken implements authenticator.Token
+func (b *certificateBinding) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	thumbprint, err := certificateThumbprint(token)
+	if err != nil {
+		return nil, false, authenticator.NewTokenRejectedError(err)
+	}
+	if len(thumbprint) > 0 {
+		cert, ok := PeerCertificateFrom(ctx)
+		if !ok {
+			return nil, false, authenticator.NewTokenRejectedError(errMissingCertificate)
+		}
+		sum := sha256.Sum256(cert.Raw)
+		if subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(sum[:])), []byte(thumbprint)) != 1 {
+			return nil, false, authenticator.NewTokenRejectedError(errCertificateMismatch)
+		}
+	}
+	return b.authenticator.AuthenticateToken(ctx, token)
+}
+
+// confirmation is the confirmation claim of RFC 8705 binding a token to a client certificate.
+type confirmation struct {
+	X5tS256 string `json:"x5t#S256"`
+}
+
+// certificateThumbprint returns the base64url encoded SHA-256 thumbprint of the client
+// certificate token is bound to, or an empty string if token is not a JWT with a confirmation
+// claim. Tokens bound by other confirmation methods, e.g. to a key, are rejected, since their
+// binding cannot be checked. The signature of the token is not verified.
+func certificateThumbprint(token string) (string, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return "", nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(segments[1])
+	if err != nil {
+		return "", nil
+	}
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", nil
+	}
+	rawConfirmation, ok := claims["cnf"]
+	if !ok {
+		return "", nil
+	}
+	// a confirmation claim that cannot be understood must not be mistaken for the absence of a binding
+	var cnf confirmation
+	if err := json.Unmarshal(rawConfirmation, &cnf); err != nil {
+		return "", errMalformedConfirmation
+	}
+	if len(cnf.X5tS256) == 0 {
+		return "", errUnsupportedBinding
+	}
+	return cnf.X5tS256, nil
+}
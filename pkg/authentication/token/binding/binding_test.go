@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+func newCertificate(t *testing.T, commonName string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func thumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func jwt(payload string) string {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	return encode(`{"alg":"RS256"}`) + "." + encode(payload) + "." + encode("signature")
+}
+
+func TestCertificateBinding(t *testing.T) {
+	cert := newCertificate(t, "client")
+	otherCert := newCertificate(t, "other")
+
+	testCases := []struct {
+		name         string
+		token        string
+		cert         *x509.Certificate
+		expectReject bool
+	}{
+		{name: "opaque token without certificate", token: "opaque"},
+		{name: "unbound JWT without certificate", token: jwt(`{"sub":"user"}`)},
+		{name: "unbound JWT with certificate", token: jwt(`{"sub":"user"}`), cert: cert},
+		{name: "JWT bound to a key rather than a certificate", token: jwt(`{"sub":"user","cnf":{"jkt":"abc"}}`), cert: cert, expectReject: true},
+		{name: "JWT with empty confirmation", token: jwt(`{"sub":"user","cnf":{}}`), cert: cert, expectReject: true},
+		{name: "JWT with payload not JSON", token: jwt(`user`)},
+		{name: "bound JWT with matching certificate", token: jwt(`{"sub":"user","cnf":{"x5t#S256":"` + thumbprint(cert) + `"}}`), cert: cert},
+		{name: "bound JWT without certificate", token: jwt(`{"sub":"user","cnf":{"x5t#S256":"` + thumbprint(cert) + `"}}`), expectReject: true},
+		{name: "bound JWT with other certificate", token: jwt(`{"sub":"user","cnf":{"x5t#S256":"` + thumbprint(cert) + `"}}`), cert: otherCert, expectReject: true},
+		{name: "JWT with malformed confirmation", token: jwt(`{"sub":"user","cnf":"` + thumbprint(cert) + `"}`), cert: cert, expectReject: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			auth := New(authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+				called = true
+				return &authenticator.Response{User: &user.DefaultInfo{Name: "user"}}, true, nil
+			}))
+
+			ctx := context.Background()
+			if tc.cert != nil {
+				ctx = WithPeerCertificate(ctx, tc.cert)
+			}
+			_, ok, err := auth.AuthenticateToken(ctx, tc.token)
+			if tc.expectReject {
+				if ok || !authenticator.IsTokenRejected(err) {
+					t.Errorf("expected the token to be rejected, got ok=%v err=%v", ok, err)
+				}
+				if called {
+					t.Error("expected the wrapped authenticator not to be called")
+				}
+				return
+			}
+			if !ok || err != nil {
+				t.Errorf("expected the token to be authenticated, got ok=%v err=%v", ok, err)
+			}
+		})
+	}
+}
+
+func TestWithRequestPeerCertificate(t *testing.T) {
+	cert := newCertificate(t, "client")
+	token := jwt(`{"sub":"user","cnf":{"x5t#S256":"` + thumbprint(cert) + `"}}`)
+	auth := WithRequestPeerCertificate(bearertoken.New(New(authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "user"}}, true, nil
+	}))))
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if _, ok, _ := auth.AuthenticateRequest(req); ok {
+		t.Error("expected a request without a client certificate to be rejected")
+	}
+
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	if _, ok, err := auth.AuthenticateRequest(req); !ok || err != nil {
+		t.Fatalf("expected a request with the bound client certificate to be authenticated, got ok=%v err=%v", ok, err)
+	}
+	if len(req.Header.Get("Authorization")) > 0 {
+		t.Error("expected the bearer token to be removed from the original request")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// lruCache is a cache bounded to maxSize entries. When full, the least recently
+// used entry is evicted to make room for a new one.
+type lruCache struct {
+	clock   clock.Clock
+	maxSize int
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	// lru orders the entries from the most to the least recently used.
+	lru list.List
+}
+
+type lruEntry struct {
+	key       string
+	value     *cacheRecord
+	expiresAt time.Time
+}
+
+func newLRUCache(maxSize int, clock clock.Clock) cache {
+	return &lruCache{
+		clock:   clock,
+		maxSize: maxSize,
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (c *lruCache) get(key string) (*cacheRecord, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*lruEntry)
+	if !c.clock.Now().Before(entry.expiresAt) {
+		c.removeElement(e)
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return entry.value, true
+}
+
+func (c *lruCache) set(key string, value *cacheRecord, ttl time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	expiresAt := c.clock.Now().Add(ttl)
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.lru.MoveToFront(e)
+		return
+	}
+	if c.lru.Len() >= c.maxSize {
+		c.removeElement(c.lru.Back())
+		stats.evicted()
+	}
+	c.entries[key] = c.lru.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+}
+
+func (c *lruCache) remove(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.removeElement(e)
+	}
+}
+
+func (c *lruCache) removeElement(e *list.Element) {
+	c.lru.Remove(e)
+	delete(c.entries, e.Value.(*lruEntry).key)
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
 )
 
 func TestSimpleCache(t *testing.T) {
@@ -51,6 +52,37 @@ func TestStripedCache(t *testing.T) {
 	testCache(newStripedCache(32, fnvHashFunc, func() cache { return newSimpleCache(clock.RealClock{}) }), t)
 }
 
+func TestLRUCache(t *testing.T) {
+	testCache(newLRUCache(10, clock.RealClock{}), t)
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	c := newLRUCache(2, fakeClock)
+	record := &cacheRecord{}
+
+	c.set("a", record, time.Minute)
+	c.set("b", record, time.Minute)
+	// touch a so that b is the least recently used entry
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	c.set("c", record, time.Minute)
+	if _, ok := c.get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.get(key); !ok {
+			t.Errorf("expected %s to be cached", key)
+		}
+	}
+
+	fakeClock.Step(time.Minute)
+	if _, ok := c.get("a"); ok {
+		t.Error("expected a to expire")
+	}
+}
+
 func benchmarkCache(cache cache, b *testing.B, numKeys int) {
 	keys := []string{}
 	for i := 0; i < numKeys; i++ {
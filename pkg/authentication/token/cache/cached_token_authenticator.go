@@ -25,14 +25,18 @@ import (
 	"errors"
 	"hash"
 	"io"
+	mathrand "math/rand"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 	"unsafe"
 
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
@@ -67,6 +71,11 @@ type cachedTokenAuthenticator struct {
 	cacheErrs  bool
 	successTTL time.Duration
 	failureTTL time.Duration
+	jitter     float64
+	randFloat  func() float64
+
+	// apiAudiences are the audiences reported in the metrics, others are reported as one.
+	apiAudiences sets.String
 
 	cache cache
 	group singleflight.Group
@@ -86,35 +95,84 @@ type cache interface {
 	remove(key string)
 }
 
+// Config holds the tuning parameters of a cached token authenticator.
+type Config struct {
+	// CacheErrs controls whether results with an error are cached.
+	CacheErrs bool
+
+	// SuccessTTL is the length of time a successful authentication is cached. Zero disables it.
+	SuccessTTL time.Duration
+
+	// FailureTTL is the length of time a failed authentication is cached. Zero disables it.
+	FailureTTL time.Duration
+
+	// MaxEntries bounds the number of cached results. When the cache is full,
+	// the least recently used results are evicted. Zero means unbounded.
+	MaxEntries int
+
+	// Jitter is the maximum fraction, in [0, 1), by which the TTL of each entry is
+	// randomly shortened so that entries cached together do not all expire and
+	// get revalidated at the same time.
+	Jitter float64
+
+	// APIAudiences are the audiences of the server. The requests for them are counted per
+	// audience, the requests for other audiences, which clients may choose, are counted together.
+	APIAudiences authenticator.Audiences
+}
+
 // New returns a token authenticator that caches the results of the specified authenticator. A ttl of 0 bypasses the cache.
 func New(authenticator authenticator.Token, cacheErrs bool, successTTL, failureTTL time.Duration) authenticator.Token {
 	return newWithClock(authenticator, cacheErrs, successTTL, failureTTL, clock.RealClock{})
 }
 
+// NewWithConfig returns a token authenticator that caches the results of the specified
+// authenticator as configured by config.
+func NewWithConfig(authenticator authenticator.Token, config Config) authenticator.Token {
+	return newWithConfig(authenticator, config, clock.RealClock{})
+}
+
 func newWithClock(authenticator authenticator.Token, cacheErrs bool, successTTL, failureTTL time.Duration, clock clock.Clock) authenticator.Token {
-	randomCacheKey := make([]byte, 32)
-	if _, err := rand.Read(randomCacheKey); err != nil {
-		panic(err) // rand should never fail
+	return newWithConfig(authenticator, Config{CacheErrs: cacheErrs, SuccessTTL: successTTL, FailureTTL: failureTTL}, clock)
+}
+
+func newWithConfig(authenticator authenticator.Token, config Config, clock clock.Clock) *cachedTokenAuthenticator {
+	// Cache performance degrades noticeably when the number of
+	// tokens in operation exceeds the size of the cache. It is
+	// cheap to make the cache big in the second dimension below,
+	// the memory is only consumed when that many tokens are being
+	// used. Currently we advertise support 5k nodes and 10k
+	// namespaces; a 32k entry cache is therefore a 2x safety
+	// margin.
+	const stripeCount = 32
+	newCache := func() cache { return newSimpleCache(clock) }
+	if config.MaxEntries > 0 {
+		// split the bound evenly across the stripes, rounding up.
+		stripeSize := (config.MaxEntries + stripeCount - 1) / stripeCount
+		newCache = func() cache { return newLRUCache(stripeSize, clock) }
 	}
 
 	return &cachedTokenAuthenticator{
 		authenticator: authenticator,
-		cacheErrs:     cacheErrs,
-		successTTL:    successTTL,
-		failureTTL:    failureTTL,
-		// Cache performance degrades noticeably when the number of
-		// tokens in operation exceeds the size of the cache. It is
-		// cheap to make the cache big in the second dimension below,
-		// the memory is only consumed when that many tokens are being
-		// used. Currently we advertise support 5k nodes and 10k
-		// namespaces; a 32k entry cache is therefore a 2x safety
-		// margin.
-		cache: newStripedCache(32, fnvHashFunc, func() cache { return newSimpleCache(clock) }),
-
-		hashPool: &sync.Pool{
-			New: func() interface{} {
-				return hmac.New(sha256.New, randomCacheKey)
-			},
+		cacheErrs:     config.CacheErrs,
+		successTTL:    config.SuccessTTL,
+		failureTTL:    config.FailureTTL,
+		jitter:        config.Jitter,
+		randFloat:     mathrand.Float64,
+		apiAudiences:  sets.NewString(config.APIAudiences...),
+		cache:         newStripedCache(stripeCount, fnvHashFunc, newCache),
+		hashPool:      newHashPool(),
+	}
+}
+
+// newHashPool returns a pool of HMACs keyed with a random per pool key.
+func newHashPool() *sync.Pool {
+	randomCacheKey := make([]byte, 32)
+	if _, err := rand.Read(randomCacheKey); err != nil {
+		panic(err) // rand should never fail
+	}
+	return &sync.Pool{
+		New: func() interface{} {
+			return hmac.New(sha256.New, randomCacheKey)
 		},
 	}
 }
@@ -132,18 +190,21 @@ func (a *cachedTokenAuthenticator) AuthenticateToken(ctx context.Context, token
 }
 
 func (a *cachedTokenAuthenticator) doAuthenticateToken(ctx context.Context, token string) *cacheRecord {
-	doneAuthenticating := stats.authenticating(ctx)
-
 	auds, audsOk := authenticator.AudiencesFrom(ctx)
 
-	key := keyFunc(a.hashPool, auds, token)
+	doneAuthenticating := stats.authenticating(ctx, normalizeAudiences(auds), a.apiAudiences)
+
+	key := keyFunc(a.hashPool, auds, audsOk, token)
+	span := trace.SpanFromContext(ctx)
 	if record, ok := a.cache.get(key); ok {
 		// Record cache hit
 		doneAuthenticating(true)
+		span.AddEvent("token cache hit")
 		return record
 	}
 
 	// Record cache miss
+	span.AddEvent("token cache miss")
 	doneBlocking := stats.blocking(ctx)
 	defer doneBlocking()
 	defer doneAuthenticating(false)
@@ -177,8 +238,8 @@ func (a *cachedTokenAuthenticator) doAuthenticateToken(ctx context.Context, toke
 		}
 
 		// Detach the context because the lookup may be shared by multiple callers,
-		// however propagate the audience.
-		ctx, cancel := context.WithTimeout(context.Background(), sharedLookupTimeout)
+		// however propagate the audience, and the span of the caller that started the lookup.
+		ctx, cancel := context.WithTimeout(trace.ContextWithSpan(context.Background(), span), sharedLookupTimeout)
 		defer cancel()
 
 		if audsOk {
@@ -199,9 +260,9 @@ func (a *cachedTokenAuthenticator) doAuthenticateToken(ctx context.Context, toke
 
 		switch {
 		case record.ok && a.successTTL > 0:
-			a.cache.set(key, record, a.successTTL)
+			a.cache.set(key, record, a.jitterTTL(a.successTTL))
 		case !record.ok && a.failureTTL > 0:
-			a.cache.set(key, record, a.failureTTL)
+			a.cache.set(key, record, a.jitterTTL(a.failureTTL))
 		}
 
 		return record, nil
@@ -217,9 +278,24 @@ func (a *cachedTokenAuthenticator) doAuthenticateToken(ctx context.Context, toke
 	}
 }
 
+// jitterTTL randomly shortens ttl by up to the configured jitter fraction.
+func (a *cachedTokenAuthenticator) jitterTTL(ttl time.Duration) time.Duration {
+	if a.jitter <= 0 {
+		return ttl
+	}
+	return ttl - time.Duration(a.randFloat()*a.jitter*float64(ttl))
+}
+
+// unrestrictedAudiences is written instead of the number of audiences when the request
+// is not audience limited. It can never be the length of an actual audience list, so a
+// request that is not audience limited never shares a key with one limited to no audience.
+const unrestrictedAudiences = 1<<32 - 1
+
 // keyFunc generates a string key by hashing the inputs.
 // This lowers the memory requirement of the cache and keeps tokens out of memory.
-func keyFunc(hashPool *sync.Pool, auds []string, token string) string {
+// The audiences are treated as a set, so the same audiences in a different order
+// or with duplicates share a key.
+func keyFunc(hashPool *sync.Pool, auds []string, audsOk bool, token string) string {
 	h := hashPool.Get().(hash.Hash)
 
 	h.Reset()
@@ -229,10 +305,15 @@ func keyFunc(hashPool *sync.Pool, auds []string, token string) string {
 	b := a[:]
 
 	writeLengthPrefixedString(h, b, token)
-	// encode the length of audiences to avoid ambiguities
-	writeLength(h, b, len(auds))
-	for _, aud := range auds {
-		writeLengthPrefixedString(h, b, aud)
+	if audsOk {
+		auds = normalizeAudiences(auds)
+		// encode the length of audiences to avoid ambiguities
+		writeLength(h, b, len(auds))
+		for _, aud := range auds {
+			writeLengthPrefixedString(h, b, aud)
+		}
+	} else {
+		writeLength(h, b, unrestrictedAudiences)
 	}
 
 	key := toString(h.Sum(nil)) // skip base64 encoding to save an allocation
@@ -242,6 +323,32 @@ func keyFunc(hashPool *sync.Pool, auds []string, token string) string {
 	return key
 }
 
+// normalizeAudiences returns the audiences sorted and without duplicates. It only
+// allocates if auds is not already normalized.
+func normalizeAudiences(auds []string) []string {
+	normalized := true
+	for i := 1; i < len(auds); i++ {
+		if auds[i-1] >= auds[i] {
+			normalized = false
+			break
+		}
+	}
+	if normalized {
+		return auds
+	}
+
+	sorted := make([]string, len(auds))
+	copy(sorted, auds)
+	sort.Strings(sorted)
+	unique := sorted[:1]
+	for _, aud := range sorted[1:] {
+		if aud != unique[len(unique)-1] {
+			unique = append(unique, aud)
+		}
+	}
+	return unique
+}
+
 // writeLengthPrefixedString writes s with a length prefix to prevent ambiguities, i.e. "xy" + "z" == "x" + "yz"
 // the length of b is assumed to be 4 (b is mutated by this function to store the length of s)
 func writeLengthPrefixedString(w io.Writer, b []byte, s string) {
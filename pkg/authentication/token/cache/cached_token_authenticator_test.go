@@ -31,6 +31,9 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/apiserver/pkg/audit"
@@ -120,6 +123,59 @@ func TestCachedTokenAuthenticator(t *testing.T) {
 	}
 }
 
+func TestCachedTokenAuthenticatorConfig(t *testing.T) {
+	var calls int
+	fakeAuth := authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+		calls++
+		return &authenticator.Response{User: &user.DefaultInfo{Name: token}}, token != "bad", nil
+	})
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	a := newWithConfig(fakeAuth, Config{
+		SuccessTTL: 10 * time.Minute,
+		FailureTTL: time.Minute,
+		MaxEntries: 64,
+		Jitter:     0.5,
+	}, fakeClock)
+	a.randFloat = func() float64 { return 1 }
+
+	authenticate := func(token string, expectCalls int) {
+		t.Helper()
+		calls = 0
+		a.AuthenticateToken(context.Background(), token)
+		if calls != expectCalls {
+			t.Errorf("%s: expected %d calls, got %d", token, expectCalls, calls)
+		}
+	}
+
+	authenticate("good", 1)
+	authenticate("bad", 1)
+	authenticate("good", 0)
+	authenticate("bad", 0)
+
+	// the failure ttl is shortened to 30s by the jitter
+	fakeClock.Step(30 * time.Second)
+	authenticate("bad", 1)
+	authenticate("good", 0)
+
+	// the success ttl is shortened to 5m by the jitter
+	fakeClock.Step(5 * time.Minute)
+	authenticate("good", 1)
+
+	// each of the 32 stripes holds two entries, so that the good and bad tokens stay cached
+	// even if they share a stripe, but 65 tokens cannot all stay cached
+	for i := 0; i < 65; i++ {
+		authenticate(fmt.Sprintf("token-%d", i), 1)
+	}
+	calls = 0
+	for i := 0; i < 65; i++ {
+		a.AuthenticateToken(context.Background(), fmt.Sprintf("token-%d", i))
+	}
+	if calls == 0 {
+		t.Error("expected entries to be evicted from the bounded cache")
+	}
+}
+
 func TestCachedTokenAuthenticatorWithAudiences(t *testing.T) {
 	resultUsers := make(map[string]user.Info)
 	fakeAuth := authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
@@ -141,6 +197,90 @@ func TestCachedTokenAuthenticatorWithAudiences(t *testing.T) {
 	}
 }
 
+func TestCachedTokenAuthenticatorTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "Authenticate")
+
+	var lookupSpanID trace.SpanID
+	fakeAuth := authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+		lookupSpanID = trace.SpanContextFromContext(ctx).SpanID()
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "user1"}}, true, nil
+	})
+	a := newWithClock(fakeAuth, true, time.Minute, 0, testingclock.NewFakeClock(time.Now()))
+
+	for i := 0; i < 2; i++ {
+		if _, ok, _ := a.AuthenticateToken(ctx, "token1"); !ok {
+			t.Fatalf("expected the token to be authenticated")
+		}
+	}
+	parent.End()
+
+	if lookupSpanID != parent.SpanContext().SpanID() {
+		t.Errorf("expected the span of the caller to be propagated to the lookup")
+	}
+	var events []string
+	for _, event := range recorder.Ended()[0].Events() {
+		events = append(events, event.Name)
+	}
+	if expected := []string{"token cache miss", "token cache hit"}; !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestCachedTokenAuthenticatorAudienceSet(t *testing.T) {
+	calls := 0
+	fakeAuth := authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+		calls++
+		auds, ok := authenticator.AudiencesFrom(ctx)
+		if ok && len(auds) == 0 {
+			// limited to no audience at all
+			return nil, false, nil
+		}
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "user1"}, Audiences: auds}, true, nil
+	})
+	fakeClock := testingclock.NewFakeClock(time.Now())
+
+	a := newWithClock(fakeAuth, true, time.Minute, time.Minute, fakeClock)
+
+	if _, ok, _ := a.AuthenticateToken(authenticator.WithAudiences(context.Background(), []string{"audA", "audB"}), "token1"); !ok {
+		t.Fatalf("expected token to be authenticated")
+	}
+	if _, ok, _ := a.AuthenticateToken(authenticator.WithAudiences(context.Background(), []string{"audB", "audA", "audB"}), "token1"); !ok {
+		t.Fatalf("expected token to be authenticated")
+	}
+	if calls != 1 {
+		t.Errorf("expected the same audience set to share a cache entry, got %d calls", calls)
+	}
+
+	if _, ok, _ := a.AuthenticateToken(context.Background(), "token2"); !ok {
+		t.Fatalf("expected token to be authenticated without audiences")
+	}
+	if _, ok, _ := a.AuthenticateToken(authenticator.WithAudiences(context.Background(), nil), "token2"); ok {
+		t.Errorf("expected a request limited to no audience not to hit the unrestricted cache entry")
+	}
+	if calls != 3 {
+		t.Errorf("expected unrestricted and empty audiences to use different cache entries, got %d calls", calls)
+	}
+}
+
+func TestNormalizeAudiences(t *testing.T) {
+	for _, tc := range []struct {
+		auds     []string
+		expected []string
+	}{
+		{auds: nil, expected: nil},
+		{auds: []string{"a"}, expected: []string{"a"}},
+		{auds: []string{"a", "b"}, expected: []string{"a", "b"}},
+		{auds: []string{"b", "a"}, expected: []string{"a", "b"}},
+		{auds: []string{"b", "a", "b", "a"}, expected: []string{"a", "b"}},
+	} {
+		if got := normalizeAudiences(tc.auds); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("normalizeAudiences(%v) = %v, expected %v", tc.auds, got, tc.expected)
+		}
+	}
+}
+
 var bKey string
 
 // use a realistic token for benchmarking
@@ -163,7 +303,7 @@ func BenchmarkKeyFunc(b *testing.B) {
 	b.Run("has audiences", func(b *testing.B) {
 		var key string
 		for n := 0; n < b.N; n++ {
-			key = keyFunc(hashPool, auds, jwtToken)
+			key = keyFunc(hashPool, auds, true, jwtToken)
 		}
 		bKey = key
 	})
@@ -171,7 +311,7 @@ func BenchmarkKeyFunc(b *testing.B) {
 	b.Run("nil audiences", func(b *testing.B) {
 		var key string
 		for n := 0; n < b.N; n++ {
-			key = keyFunc(hashPool, nil, jwtToken)
+			key = keyFunc(hashPool, nil, false, jwtToken)
 		}
 		bKey = key
 	})
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/utils/clock"
+)
+
+// negativeCachedTokenAuthenticator remembers tokens that were recently rejected
+// and rejects them again without consulting the wrapped authenticator.
+type negativeCachedTokenAuthenticator struct {
+	authenticator authenticator.Token
+	ttl           time.Duration
+	cache         cache
+
+	// hashPool is a per authenticator pool of hash.Hash; tokens are only ever stored hashed.
+	hashPool *sync.Pool
+}
+
+// NewNegative returns a token authenticator that caches rejections of the specified
+// authenticator for ttl, so that clients retrying with an invalid or expired token
+// do not reach the authenticator until the entry expires. A token is considered
+// rejected if it was not authenticated and either no error or only errors marked
+// with NewTokenRejectedError were returned; other errors are never cached.
+// At most maxEntries rejections are remembered. A ttl of 0 bypasses the cache.
+func NewNegative(authenticator authenticator.Token, ttl time.Duration, maxEntries int) authenticator.Token {
+	return newNegativeWithClock(authenticator, ttl, maxEntries, clock.RealClock{})
+}
+
+func newNegativeWithClock(authenticator authenticator.Token, ttl time.Duration, maxEntries int, clock clock.Clock) authenticator.Token {
+	if ttl <= 0 || maxEntries <= 0 {
+		return authenticator
+	}
+	const stripeCount = 32
+	stripeSize := (maxEntries + stripeCount - 1) / stripeCount
+	return &negativeCachedTokenAuthenticator{
+		authenticator: authenticator,
+		ttl:           ttl,
+		cache:         newStripedCache(stripeCount, fnvHashFunc, func() cache { return newLRUCache(stripeSize, clock) }),
+		hashPool:      newHashPool(),
+	}
+}
+
+// AuthenticateToken implements authenticator.Token
+func (a *negativeCachedTokenAuthenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	auds, audsOk := authenticator.AudiencesFrom(ctx)
+	key := keyFunc(a.hashPool, auds, audsOk, token)
+	if record, ok := a.cache.get(key); ok {
+		trace.SpanFromContext(ctx).AddEvent("negative token cache hit")
+		return nil, false, record.err
+	}
+
+	resp, ok, err := a.authenticator.AuthenticateToken(ctx, token)
+	if !ok && (err == nil || authenticator.IsTokenRejected(err)) {
+		a.cache.set(key, &cacheRecord{err: err}, a.ttl)
+	}
+	return resp, ok, err
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestNegativeCachedTokenAuthenticator(t *testing.T) {
+	rejected := authenticator.NewTokenRejectedError(errors.New("token expired"))
+	results := map[string]struct {
+		ok  bool
+		err error
+	}{
+		"good":      {ok: true},
+		"unknown":   {},
+		"expired":   {err: rejected},
+		"transient": {err: errors.New("connection refused")},
+	}
+	calls := map[string]int{}
+	fakeAuth := authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+		calls[token]++
+		r := results[token]
+		if !r.ok {
+			return nil, false, r.err
+		}
+		return &authenticator.Response{User: &user.DefaultInfo{Name: token}}, true, nil
+	})
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	a := newNegativeWithClock(fakeAuth, time.Second, 100, fakeClock)
+
+	for i := 0; i < 3; i++ {
+		for token := range results {
+			_, ok, err := a.AuthenticateToken(context.Background(), token)
+			if ok != results[token].ok || err != results[token].err {
+				t.Errorf("%s: expected %v, %v, got %v, %v", token, results[token].ok, results[token].err, ok, err)
+			}
+		}
+	}
+	expectCalls := map[string]int{"good": 3, "unknown": 1, "expired": 1, "transient": 3}
+	for token, expected := range expectCalls {
+		if calls[token] != expected {
+			t.Errorf("%s: expected %d calls, got %d", token, expected, calls[token])
+		}
+	}
+
+	// rejections expire
+	fakeClock.Step(time.Second)
+	a.AuthenticateToken(context.Background(), "expired")
+	if calls["expired"] != 2 {
+		t.Errorf("expected the rejection to expire, got %d calls", calls["expired"])
+	}
+
+	// rejections are scoped to the audiences
+	ctx := authenticator.WithAudiences(context.Background(), authenticator.Audiences{"other"})
+	a.AuthenticateToken(ctx, "expired")
+	if calls["expired"] != 3 {
+		t.Errorf("expected a different audience to miss the cache, got %d calls", calls["expired"])
+	}
+}
+
+func TestNegativeCachedTokenAuthenticatorDisabled(t *testing.T) {
+	fakeAuth := authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+		return nil, false, nil
+	})
+	if a := NewNegative(fakeAuth, 0, 100); a == nil {
+		t.Fatal("expected an authenticator")
+	} else if _, ok := a.(*negativeCachedTokenAuthenticator); ok {
+		t.Error("expected a zero ttl to bypass the cache")
+	}
+}
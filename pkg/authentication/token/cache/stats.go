@@ -20,6 +20,7 @@ import (
 	"context"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/component-base/metrics"
 	"k8s.io/component-base/metrics/legacyregistry"
 )
@@ -61,6 +62,27 @@ var (
 		},
 		[]string{"status"},
 	)
+	// audienceRequestCount is partitioned by the audiences the requests were limited to. The
+	// audiences may be chosen by clients, e.g. in the spec of a TokenReview, so audiences other
+	// than those of the server configuration are reported as otherAudienceTag to bound the
+	// cardinality.
+	audienceRequestCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      "authentication",
+			Subsystem:      "token_cache",
+			Name:           "audience_request_total",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"audience", "status"},
+	)
+	evictionCount = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Namespace:      "authentication",
+			Subsystem:      "token_cache",
+			Name:           "evictions_total",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
 )
 
 func init() {
@@ -69,6 +91,8 @@ func init() {
 		requestCount,
 		fetchCount,
 		activeFetchCount,
+		audienceRequestCount,
+		evictionCount,
 	)
 }
 
@@ -81,13 +105,17 @@ const (
 
 	fetchInFlightTag = "in_flight"
 	fetchBlockedTag  = "blocked"
+
+	otherAudienceTag = "other"
 )
 
 type statsCollector struct{}
 
 var stats = statsCollector{}
 
-func (statsCollector) authenticating(ctx context.Context) func(hit bool) {
+// authenticating records the authentication of a token for the audiences, reporting the ones
+// not in known as otherAudienceTag.
+func (statsCollector) authenticating(ctx context.Context, auds []string, known sets.String) func(hit bool) {
 	start := time.Now()
 	return func(hit bool) {
 		var tag string
@@ -101,6 +129,17 @@ func (statsCollector) authenticating(ctx context.Context) func(hit bool) {
 
 		requestCount.WithContext(ctx).WithLabelValues(tag).Inc()
 		requestLatency.WithContext(ctx).WithLabelValues(tag).Observe(float64(latency.Milliseconds()) / 1000)
+		other := false
+		for _, aud := range auds {
+			if !known.Has(aud) {
+				other = true
+				continue
+			}
+			audienceRequestCount.WithContext(ctx).WithLabelValues(aud, tag).Inc()
+		}
+		if other {
+			audienceRequestCount.WithContext(ctx).WithLabelValues(otherAudienceTag, tag).Inc()
+		}
 	}
 }
 
@@ -124,3 +163,7 @@ func (statsCollector) fetching(ctx context.Context) func(ok bool) {
 		activeFetchCount.WithContext(ctx).WithLabelValues(fetchInFlightTag).Dec()
 	}
 }
+
+func (statsCollector) evicted() {
+	evictionCount.Inc()
+}
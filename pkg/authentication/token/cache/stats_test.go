@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestAudienceRequestCount(t *testing.T) {
+	audienceRequestCount.Reset()
+
+	known := sets.NewString("api")
+	stats.authenticating(context.Background(), []string{"api"}, known)(true)
+	stats.authenticating(context.Background(), []string{"api", "client-a", "client-b"}, known)(false)
+	stats.authenticating(context.Background(), []string{"client-c"}, known)(false)
+
+	expected := `
+# HELP authentication_token_cache_audience_request_total [ALPHA] 
+# TYPE authentication_token_cache_audience_request_total counter
+authentication_token_cache_audience_request_total{audience="api",status="hit"} 1
+authentication_token_cache_audience_request_total{audience="api",status="miss"} 1
+authentication_token_cache_audience_request_total{audience="other",status="miss"} 2
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "authentication_token_cache_audience_request_total"); err != nil {
+		t.Error(err)
+	}
+}
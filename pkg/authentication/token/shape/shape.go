@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package shape rejects malformed bearer tokens before they reach token authenticators
+// that are expensive to consult, such as the token review webhook.
+package shape
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+)
+
+// Token68Characters are the characters of the b64token syntax of bearer tokens defined by RFC 6750.
+const Token68Characters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-._~+/="
+
+// Config describes the tokens that are passed on to the wrapped authenticator.
+type Config struct {
+	// MaxLength is the maximum length of a token in bytes. Zero means unlimited.
+	MaxLength int
+
+	// AllowedCharacters are the characters a token may consist of. If empty, any character is allowed.
+	AllowedCharacters string
+
+	// ValidateJWTShape requires tokens of three segments separated by dots, which are taken to be
+	// JWTs, to consist of base64url encoded segments, the first of which is a JSON object.
+	ValidateJWTShape bool
+}
+
+type shapeValidator struct {
+	authenticator authenticator.Token
+	config        Config
+}
+
+// New returns a token authenticator that rejects tokens not matching config, and delegates the
+// others to auth. Rejected tokens are reported as definitive rejections.
+func New(auth authenticator.Token, config Config) authenticator.Token {
+	return &shapeValidator{authenticator: auth, config: config}
+}
+
+// AuthenticateToken implements authenticator.Token
+func (v *shapeValidator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	if err := v.config.validate(token); err != nil {
+		return nil, false, authenticator.NewTokenRejectedError(err)
+	}
+	return v.authenticator.AuthenticateToken(ctx, token)
+}
+
+// validate returns an error describing why token does not match c. The token itself is never part
+// of the error, so that it is not logged.
+func (c Config) validate(token string) error {
+	if c.MaxLength > 0 && len(token) > c.MaxLength {
+		return fmt.Errorf("token length %d exceeds the maximum of %d", len(token), c.MaxLength)
+	}
+	if len(c.AllowedCharacters) > 0 {
+		if i := strings.IndexFunc(token, func(r rune) bool { return !strings.ContainsRune(c.AllowedCharacters, r) }); i >= 0 {
+			return fmt.Errorf("token contains a disallowed character at position %d", i)
+		}
+	}
+	if c.ValidateJWTShape {
+		if segments := strings.Split(token, "."); len(segments) == 3 {
+			return validateJWTSegments(segments)
+		}
+	}
+	return nil
+}
+
+// validateJWTSegments checks that the segments of a JWS compact serialization are well formed,
+// without verifying the signature or the claims.
+func validateJWTSegments(segments []string) error {
+	for i, segment := range segments {
+		if len(segment) == 0 {
+			return fmt.Errorf("token segment %d of a JWT is empty", i)
+		}
+	}
+	header, err := base64.RawURLEncoding.DecodeString(segments[0])
+	if err != nil {
+		return fmt.Errorf("token header of a JWT is not base64url encoded: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(header, &fields); err != nil {
+		return fmt.Errorf("token header of a JWT is not a JSON object: %v", err)
+	}
+	for i, segment := range segments[1:] {
+		if _, err := base64.RawURLEncoding.DecodeString(segment); err != nil {
+			return fmt.Errorf("token segment %d of a JWT is not base64url encoded: %v", i+1, err)
+		}
+	}
+	return nil
+}
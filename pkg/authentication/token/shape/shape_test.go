@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shape
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+func TestShapeValidator(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	jwt := encode(`{"alg":"RS256"}`) + "." + encode(`{"sub":"user"}`) + "." + encode("signature")
+
+	testCases := []struct {
+		name         string
+		config       Config
+		token        string
+		expectReject bool
+	}{
+		{name: "no constraints", token: strings.Repeat("x", 8192) + " \x00"},
+		{name: "within max length", config: Config{MaxLength: 5}, token: "12345"},
+		{name: "exceeds max length", config: Config{MaxLength: 5}, token: "123456", expectReject: true},
+		{name: "allowed characters", config: Config{AllowedCharacters: Token68Characters}, token: "abc-DEF_123.~+/=="},
+		{name: "disallowed character", config: Config{AllowedCharacters: Token68Characters}, token: "abc def", expectReject: true},
+		{name: "disallowed non-ASCII character", config: Config{AllowedCharacters: Token68Characters}, token: "abcä", expectReject: true},
+		{name: "well formed JWT", config: Config{ValidateJWTShape: true}, token: jwt},
+		{name: "bootstrap token is not a JWT", config: Config{ValidateJWTShape: true}, token: "abcdef.0123456789abcdef"},
+		{name: "opaque token is not a JWT", config: Config{ValidateJWTShape: true}, token: "opaque"},
+		{name: "JWT with empty segment", config: Config{ValidateJWTShape: true}, token: encode(`{"alg":"RS256"}`) + ".." + encode("signature"), expectReject: true},
+		{name: "JWT with header not base64url", config: Config{ValidateJWTShape: true}, token: "a+b." + encode("{}") + "." + encode("signature"), expectReject: true},
+		{name: "JWT with header not JSON", config: Config{ValidateJWTShape: true}, token: encode("header") + "." + encode("{}") + "." + encode("signature"), expectReject: true},
+		{name: "JWT with signature not base64url", config: Config{ValidateJWTShape: true}, token: encode(`{"alg":"RS256"}`) + "." + encode("{}") + ".a=b", expectReject: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			auth := New(authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+				called = true
+				return &authenticator.Response{User: &user.DefaultInfo{Name: "user"}}, true, nil
+			}), tc.config)
+
+			_, ok, err := auth.AuthenticateToken(context.Background(), tc.token)
+			if tc.expectReject {
+				if ok || !authenticator.IsTokenRejected(err) {
+					t.Errorf("expected the token to be rejected, got %v, %v", ok, err)
+				}
+				if called {
+					t.Errorf("expected the wrapped authenticator not to be called")
+				}
+				if err != nil && strings.Contains(err.Error(), tc.token) {
+					t.Errorf("expected the error not to contain the token: %v", err)
+				}
+				return
+			}
+			if !ok || err != nil || !called {
+				t.Errorf("expected the token to be passed on, got %v, %v", ok, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenfile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/util/filewatcher"
+	"k8s.io/klog/v2"
+)
+
+// FileRefreshDuration is the interval at which the token file is re-read even if no
+// file events were observed. It is exposed so that tests can crank up the reload speed.
+var FileRefreshDuration = 1 * time.Minute
+
+// DynamicTokenFile is a token authenticator that authenticates the tokens listed in a
+// CSV file and reloads the file whenever it changes. A file that fails to parse or
+// validate is rejected and the previously loaded tokens stay in effect.
+type DynamicTokenFile struct {
+	// filename is the name of the token file to read.
+	filename string
+
+	// tokens holds a *loadedTokens with the last successfully loaded file.
+	tokens atomic.Value
+
+	// watcher reloads the token file whenever it changes.
+	watcher *filewatcher.FileWatcher
+}
+
+var _ authenticator.Token = &DynamicTokenFile{}
+
+type loadedTokens struct {
+	content       []byte
+	authenticator *TokenAuthenticator
+}
+
+// NewDynamicCSV creates a token authenticator that watches the CSV token file at the
+// given path and swaps in its tokens after the file has been validated. The format
+// is the one of NewCSV, but empty or duplicate tokens and records without a user
+// name are rejected. The initial file must load successfully.
+// Run must be called for the file to be watched.
+func NewDynamicCSV(path string) (*DynamicTokenFile, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("file path not specified")
+	}
+	a := &DynamicTokenFile{
+		filename: path,
+	}
+	a.watcher = filewatcher.New("DynamicTokenFile", path, a.loadTokens)
+	if err := a.loadTokens(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// AuthenticateToken authenticates the token against the currently loaded tokens.
+func (a *DynamicTokenFile) AuthenticateToken(ctx context.Context, value string) (*authenticator.Response, bool, error) {
+	return a.tokens.Load().(*loadedTokens).authenticator.AuthenticateToken(ctx, value)
+}
+
+// loadTokens reads the token file and swaps the tokens if the content changed.
+func (a *DynamicTokenFile) loadTokens() error {
+	content, err := ioutil.ReadFile(a.filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file path %q: %v", a.filename, err)
+	}
+	existing, _ := a.tokens.Load().(*loadedTokens)
+	if existing != nil && bytes.Equal(existing.content, content) {
+		return nil
+	}
+
+	tokens, err := readCSV(a.filename, bytes.NewReader(content), true)
+	if err != nil {
+		return err
+	}
+	a.tokens.Store(&loadedTokens{content: content, authenticator: New(tokens)})
+	klog.V(2).InfoS("Loaded token file", "file", a.filename, "tokens", len(tokens))
+	return nil
+}
+
+// RunOnce runs a single sync loop
+func (a *DynamicTokenFile) RunOnce(ctx context.Context) error {
+	return a.loadTokens()
+}
+
+// Run starts the controller and blocks until ctx is done.
+func (a *DynamicTokenFile) Run(ctx context.Context, workers int) {
+	a.watcher.Run(ctx, FileRefreshDuration)
+}
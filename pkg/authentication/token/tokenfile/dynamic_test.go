@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenfile
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestDynamicTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.csv")
+	writeTokens := func(content string) {
+		t.Helper()
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	authenticate := func(a *DynamicTokenFile, token, expectUser string) {
+		t.Helper()
+		resp, ok, err := a.AuthenticateToken(context.Background(), token)
+		if err != nil {
+			t.Fatalf("unexpected error authenticating %q: %v", token, err)
+		}
+		if len(expectUser) == 0 {
+			if ok {
+				t.Errorf("expected %q to be rejected, got user %q", token, resp.User.GetName())
+			}
+			return
+		}
+		if !ok || resp.User.GetName() != expectUser {
+			t.Errorf("expected %q to authenticate as %q, got %v %v", token, expectUser, ok, resp)
+		}
+	}
+
+	writeTokens("token1,user1,uid1\n")
+	a, err := NewDynamicCSV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticate(a, "token1", "user1")
+	authenticate(a, "token2", "")
+
+	// Changes take effect on reload.
+	writeTokens("token2,user2,uid2,\"group1,group2\"\n")
+	if err := a.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	authenticate(a, "token1", "")
+	authenticate(a, "token2", "user2")
+
+	// Invalid files are rejected and the previous tokens stay in effect.
+	for name, content := range map[string]string{
+		"insufficient columns": "token3,user3\n",
+		"empty token":          ",user3,uid3\n",
+		"empty user name":      "token3,,uid3\n",
+		"duplicate token":      "token3,user3,uid3\ntoken3,user4,uid4\n",
+	} {
+		writeTokens(content)
+		if err := a.RunOnce(context.Background()); err == nil {
+			t.Errorf("%s: expected the token file to be rejected", name)
+		}
+		authenticate(a, "token2", "user2")
+		authenticate(a, "token3", "")
+	}
+}
+
+func TestNewDynamicCSVInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.csv")
+	if err := ioutil.WriteFile(path, []byte("token1,user1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDynamicCSV(path); err == nil {
+		t.Error("expected invalid initial token file to be rejected")
+	}
+	if _, err := NewDynamicCSV(""); err == nil {
+		t.Error("expected missing path to be rejected")
+	}
+}
@@ -49,9 +49,21 @@ func NewCSV(path string) (*TokenAuthenticator, error) {
 	}
 	defer file.Close()
 
+	tokens, err := readCSV(path, file, false)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenAuthenticator{
+		tokens: tokens,
+	}, nil
+}
+
+// readCSV reads the token records of the file at path from r. If strict is true, empty and
+// duplicate tokens and records without a user name are errors rather than being warned about.
+func readCSV(path string, r io.Reader, strict bool) (map[string]*user.DefaultInfo, error) {
 	recordNum := 0
 	tokens := make(map[string]*user.DefaultInfo)
-	reader := csv.NewReader(file)
+	reader := csv.NewReader(r)
 	reader.FieldsPerRecord = -1
 	for {
 		record, err := reader.Read()
@@ -67,15 +79,24 @@ func NewCSV(path string) (*TokenAuthenticator, error) {
 
 		recordNum++
 		if record[0] == "" {
+			if strict {
+				return nil, fmt.Errorf("empty token has been found in token file '%s', record number '%d'", path, recordNum)
+			}
 			klog.Warningf("empty token has been found in token file '%s', record number '%d'", path, recordNum)
 			continue
 		}
+		if strict && record[1] == "" {
+			return nil, fmt.Errorf("empty user name has been found in token file '%s', record number '%d'", path, recordNum)
+		}
 
 		obj := &user.DefaultInfo{
 			Name: record[1],
 			UID:  record[2],
 		}
 		if _, exist := tokens[record[0]]; exist {
+			if strict {
+				return nil, fmt.Errorf("duplicate token has been found in token file '%s', record number '%d'", path, recordNum)
+			}
 			klog.Warningf("duplicate token has been found in token file '%s', record number '%d'", path, recordNum)
 		}
 		tokens[record[0]] = obj
@@ -84,10 +105,7 @@ func NewCSV(path string) (*TokenAuthenticator, error) {
 			obj.Groups = strings.Split(record[3], ",")
 		}
 	}
-
-	return &TokenAuthenticator{
-		tokens: tokens,
-	}, nil
+	return tokens, nil
 }
 
 func (a *TokenAuthenticator) AuthenticateToken(ctx context.Context, value string) (*authenticator.Response, bool, error) {
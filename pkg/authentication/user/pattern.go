@@ -0,0 +1,35 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user
+
+import "strings"
+
+// MatchesAnyPattern returns true if value, e.g. a user name, a group or an extra key, matches any of
+// the patterns. A pattern either matches exactly or, when ending in "*", matches every value with
+// that prefix.
+func MatchesAnyPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "*") {
+			if strings.HasPrefix(value, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		} else if pattern == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizer
+
+import "context"
+
+type dryRunKeyType int
+
+const dryRunKey dryRunKeyType = iota
+
+// WithDryRun returns a copy of parent in which authorizers are asked to decide without side
+// effects on the server, such as recording metrics or caching decisions. It is used to evaluate
+// recorded or hypothetical requests against an authorizer chain.
+func WithDryRun(parent context.Context) context.Context {
+	return context.WithValue(parent, dryRunKey, true)
+}
+
+// IsDryRun returns true if ctx was set up with WithDryRun.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey).(bool)
+	return dryRun
+}
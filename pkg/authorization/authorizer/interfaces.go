@@ -20,6 +20,8 @@ import (
 	"context"
 	"net/http"
 
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apiserver/pkg/authentication/user"
 )
 
@@ -62,6 +64,16 @@ type Attributes interface {
 
 	// GetPath returns the path of the request
 	GetPath() string
+
+	// GetFieldSelector returns the requirements of the field selector of a list, watch or
+	// deletecollection request, or nil if it has none. An error is returned if the field selector
+	// of the request could not be parsed, in which case authorizers must not rely on it.
+	GetFieldSelector() (fields.Requirements, error)
+
+	// GetLabelSelector returns the requirements of the label selector of a list, watch or
+	// deletecollection request, or nil if it has none. An error is returned if the label selector
+	// of the request could not be parsed, in which case authorizers must not rely on it.
+	GetLabelSelector() (labels.Requirements, error)
 }
 
 // Authorizer makes an authorization decision based on information gained by making
@@ -83,6 +95,35 @@ type RuleResolver interface {
 	RulesFor(user user.Info, namespace string) ([]ResourceRuleInfo, []NonResourceRuleInfo, bool, error)
 }
 
+// The keys of the computed attributes set by the built-in AttributesMutators.
+const (
+	// ComputedAttributeFieldSelector is the field selector of the request, as given in its query.
+	ComputedAttributeFieldSelector = "fieldSelector"
+	// ComputedAttributeLabelSelector is the label selector of the request, as given in its query.
+	ComputedAttributeLabelSelector = "labelSelector"
+	// ComputedAttributeOrigin is the IP address of the peer the request was received from.
+	ComputedAttributeOrigin = "origin"
+)
+
+// ComputedAttributes is implemented by Attributes that hold attributes computed by AttributesMutators.
+// Authorizers relying on computed attributes type-assert to it.
+type ComputedAttributes interface {
+	// GetComputedAttribute returns the computed attribute key, and whether it is set.
+	GetComputedAttribute(key string) (string, bool)
+}
+
+// AttributesMutator adds computed attributes to the attributes of a request before it is authorized,
+// so that custom authorizers can rely on them without a filter of their own.
+type AttributesMutator interface {
+	MutateAttributes(req *http.Request, attrs *AttributesRecord) error
+}
+
+type AttributesMutatorFunc func(req *http.Request, attrs *AttributesRecord) error
+
+func (f AttributesMutatorFunc) MutateAttributes(req *http.Request, attrs *AttributesRecord) error {
+	return f(req, attrs)
+}
+
 // RequestAttributesGetter provides a function that extracts Attributes from an http.Request
 type RequestAttributesGetter interface {
 	GetRequestAttributes(user.Info, *http.Request) Attributes
@@ -100,6 +141,14 @@ type AttributesRecord struct {
 	Name            string
 	ResourceRequest bool
 	Path            string
+
+	FieldSelectorRequirements fields.Requirements
+	FieldSelectorParsingErr   error
+	LabelSelectorRequirements labels.Requirements
+	LabelSelectorParsingErr   error
+
+	// Computed holds the attributes computed by AttributesMutators, by key.
+	Computed map[string]string
 }
 
 func (a AttributesRecord) GetUser() user.Info {
@@ -146,6 +195,29 @@ func (a AttributesRecord) GetPath() string {
 	return a.Path
 }
 
+func (a AttributesRecord) GetFieldSelector() (fields.Requirements, error) {
+	return a.FieldSelectorRequirements, a.FieldSelectorParsingErr
+}
+
+func (a AttributesRecord) GetLabelSelector() (labels.Requirements, error) {
+	return a.LabelSelectorRequirements, a.LabelSelectorParsingErr
+}
+
+var _ ComputedAttributes = AttributesRecord{}
+
+func (a AttributesRecord) GetComputedAttribute(key string) (string, bool) {
+	value, ok := a.Computed[key]
+	return value, ok
+}
+
+// SetComputedAttribute sets the computed attribute key to value.
+func (a *AttributesRecord) SetComputedAttribute(key, value string) {
+	if a.Computed == nil {
+		a.Computed = map[string]string{}
+	}
+	a.Computed[key] = value
+}
+
 type Decision int
 
 const (
@@ -38,10 +38,41 @@ type DelegatingAuthorizerConfig struct {
 	// You generally want more responsive, "deny, try again" flows.
 	DenyCacheTTL time.Duration
 
+	// AllowCacheMaxEntries is the maximum number of successful authorization responses that will be cached.
+	// If zero, webhook.DefaultCacheMaxEntries is used.
+	AllowCacheMaxEntries int
+
+	// DenyCacheMaxEntries is the maximum number of unsuccessful authorization responses that will be cached.
+	// If zero, webhook.DefaultCacheMaxEntries is used.
+	DenyCacheMaxEntries int
+
 	// WebhookRetryBackoff specifies the backoff parameters for the authorization webhook retry logic.
 	// This allows us to configure the sleep time at each iteration and the maximum number of retries allowed
 	// before we fail the webhook call in order to limit the fan out that ensues when the system is degraded.
 	WebhookRetryBackoff *wait.Backoff
+
+	// MatchConditions restrict the requests sent for subject access review to those matching all of them.
+	// The authorizer has no opinion on other requests.
+	MatchConditions []webhook.MatchCondition
+
+	// SecondarySubjectAccessReviewClient reviews the requests while SubjectAccessReviewClient is unhealthy.
+	// It is optional.
+	SecondarySubjectAccessReviewClient authorizationclient.AuthorizationV1Interface
+
+	// Failover tunes the failover to SecondarySubjectAccessReviewClient and the failback from it.
+	Failover webhook.FailoverConfig
+
+	// Timeout bounds the time taken to decide on a request, separately from the deadline of the request.
+	// Zero means no bound.
+	Timeout time.Duration
+
+	// DecisionOnTimeout is the decision on a request once Timeout expires, DecisionDeny or DecisionNoOpinion.
+	// The zero value is DecisionDeny, failing closed.
+	DecisionOnTimeout authorizer.Decision
+
+	// PriorityQueue rate limits the reviews, queueing them by the priority level of their requests. The
+	// clients should then not rate limit the reviews themselves. The zero value does not rate limit.
+	PriorityQueue webhook.PriorityQueueConfig
 }
 
 func (c DelegatingAuthorizerConfig) New() (authorizer.Authorizer, error) {
@@ -49,14 +80,41 @@ func (c DelegatingAuthorizerConfig) New() (authorizer.Authorizer, error) {
 		return nil, errors.New("retry backoff parameters for delegating authorization webhook has not been specified")
 	}
 
-	return webhook.NewFromInterface(
+	webhookAuthorizer, err := webhook.NewFromInterface(
 		c.SubjectAccessReviewClient,
 		c.AllowCacheTTL,
 		c.DenyCacheTTL,
 		*c.WebhookRetryBackoff,
 		webhook.AuthorizerMetrics{
-			RecordRequestTotal:   RecordRequestTotal,
-			RecordRequestLatency: RecordRequestLatency,
+			RecordRequestTotal:    RecordRequestTotal,
+			RecordRequestLatency:  RecordRequestLatency,
+			RecordCacheLookup:     RecordCacheLookup,
+			RecordEndpointRequest: RecordEndpointRequest,
+			RecordEndpointHealth:  RecordEndpointHealth,
+			RecordQueueWait:       RecordQueueWait,
+			RecordDecision:        RecordDecision,
 		},
 	)
+	if err != nil {
+		return nil, err
+	}
+	allowCacheMaxEntries, denyCacheMaxEntries := c.AllowCacheMaxEntries, c.DenyCacheMaxEntries
+	if allowCacheMaxEntries == 0 {
+		allowCacheMaxEntries = webhook.DefaultCacheMaxEntries
+	}
+	if denyCacheMaxEntries == 0 {
+		denyCacheMaxEntries = webhook.DefaultCacheMaxEntries
+	}
+	webhookAuthorizer.SetCacheSizes(allowCacheMaxEntries, denyCacheMaxEntries)
+	if err := webhookAuthorizer.SetMatchConditions(c.MatchConditions); err != nil {
+		return nil, err
+	}
+	webhookAuthorizer.SetTimeout(c.Timeout, c.DecisionOnTimeout)
+	webhookAuthorizer.SetPriorityQueue(c.PriorityQueue)
+	if c.SecondarySubjectAccessReviewClient != nil {
+		webhookAuthorizer.SetFailoverEndpoints([]webhook.FailoverEndpoint{
+			{Name: "secondary", Client: c.SecondarySubjectAccessReviewClient},
+		}, c.Failover)
+	}
+	return webhookAuthorizer, nil
 }
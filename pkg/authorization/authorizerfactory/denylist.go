@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizerfactory
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"sigs.k8s.io/yaml"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+const (
+	// DenyRuleAnnotationKey is the audit annotation naming the deny rule that denied a request.
+	DenyRuleAnnotationKey = "authorization.k8s.io/deny-rule"
+	// DenyReasonAnnotationKey is the audit annotation holding the reason of the deny rule that denied a request.
+	DenyReasonAnnotationKey = "authorization.k8s.io/deny-reason"
+)
+
+// DenyList is the content of a deny list file, a list of rules of which any denies a request.
+type DenyList struct {
+	Rules []DenyRule `json:"rules"`
+}
+
+// DenyRule denies the requests it matches, such as exec and attach for some groups, or node proxy
+// access. It matches requests like a PolicyRule does, except that a rule without users and groups
+// applies to all users, and a rule without apiGroups matches resources of any API group.
+type DenyRule struct {
+	// Name identifies the rule in denial reasons and audit annotations.
+	Name string `json:"name"`
+	// Reason explains the denial to the client and in the audit annotations.
+	Reason string `json:"reason,omitempty"`
+
+	PolicyRule
+}
+
+// denyListAuthorizer denies the requests matching a rule of a deny list, and has no opinion on others.
+type denyListAuthorizer struct {
+	rules []DenyRule
+}
+
+var _ authorizer.Authorizer = &denyListAuthorizer{}
+
+// NewDenyListAuthorizer returns an authorizer that denies the requests matching any of rules. It is meant
+// to run before the other authorizers, so that the matching requests are denied whatever they decide.
+func NewDenyListAuthorizer(rules []DenyRule) (authorizer.Authorizer, error) {
+	if err := validateDenyList(&DenyList{Rules: rules}); err != nil {
+		return nil, err
+	}
+	return &denyListAuthorizer{rules: rules}, nil
+}
+
+// NewDenyListAuthorizerFromFile returns a deny list authorizer using the rules in filename, which
+// is written in YAML or JSON.
+func NewDenyListAuthorizerFromFile(filename string) (authorizer.Authorizer, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	denyList := &DenyList{}
+	if err := yaml.UnmarshalStrict(content, denyList); err != nil {
+		return nil, fmt.Errorf("failed to parse deny list %s: %v", filename, err)
+	}
+	a, err := NewDenyListAuthorizer(denyList.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deny list %s: %v", filename, err)
+	}
+	return a, nil
+}
+
+func validateDenyList(denyList *DenyList) error {
+	var errs []error
+	names := sets.NewString()
+	for i, rule := range denyList.Rules {
+		switch {
+		case len(rule.Name) == 0:
+			errs = append(errs, fmt.Errorf("rules[%d]: name is required", i))
+		case names.Has(rule.Name):
+			errs = append(errs, fmt.Errorf("rules[%d]: duplicate name %q", i, rule.Name))
+		}
+		names.Insert(rule.Name)
+
+		errs = append(errs, validateRuleTargets(i, &rule.PolicyRule, false)...)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// Authorize denies a request matching a rule, recording the rule in the audit annotations, and
+// has no opinion otherwise.
+func (a *denyListAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	for i := range a.rules {
+		rule := &a.rules[i]
+		if !rule.denies(attr) {
+			continue
+		}
+		reason := rule.Reason
+		if len(reason) == 0 {
+			reason = "the request is on the deny list"
+		}
+		audit.AddAuditAnnotations(ctx,
+			DenyRuleAnnotationKey, rule.Name,
+			DenyReasonAnnotationKey, reason)
+		return authorizer.DecisionDeny, fmt.Sprintf("denied by rule %q: %s", rule.Name, reason), nil
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
+
+// denies returns whether the rule matches the request of attr.
+func (r *DenyRule) denies(attr authorizer.Attributes) bool {
+	if len(r.Users) > 0 || len(r.Groups) > 0 {
+		if u := attr.GetUser(); u == nil || !r.appliesTo(u) {
+			return false
+		}
+	}
+	if !attr.IsResourceRequest() {
+		return r.allowsNonResourceRequest(attr)
+	}
+	return matches(r.Verbs, attr.GetVerb(), false) &&
+		matches(r.APIGroups, attr.GetAPIGroup(), true) &&
+		matches(r.Namespaces, attr.GetNamespace(), true) &&
+		r.matchesResource(attr.GetResource(), attr.GetSubresource())
+}
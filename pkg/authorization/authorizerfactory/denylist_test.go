@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizerfactory
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+const testDenyList = `
+rules:
+- name: no-exec
+  reason: exec and attach are reserved to the operators
+  groups: ["developers"]
+  verbs: ["create", "get"]
+  resources: ["pods/exec", "pods/attach"]
+- name: no-node-proxy
+  verbs: ["*"]
+  resources: ["nodes/proxy"]
+- name: no-debug
+  users: ["mallory"]
+  verbs: ["get"]
+  nonResourceURLs: ["/debug/*"]
+`
+
+func TestDenyListAuthorizer(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "deny-list.yaml")
+	if err := ioutil.WriteFile(filename, []byte(testDenyList), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewDenyListAuthorizerFromFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	developer := &user.DefaultInfo{Name: "dave", Groups: []string{"developers"}}
+	operator := &user.DefaultInfo{Name: "olga", Groups: []string{"operators"}}
+
+	tests := []struct {
+		name           string
+		attrs          authorizer.AttributesRecord
+		expected       authorizer.Decision
+		expectedRule   string
+		expectedReason string
+	}{
+		{
+			name:           "exec by group",
+			attrs:          authorizer.AttributesRecord{User: developer, Verb: "create", Resource: "pods", Subresource: "exec", Namespace: "default", Name: "web", ResourceRequest: true},
+			expected:       authorizer.DecisionDeny,
+			expectedRule:   "no-exec",
+			expectedReason: "exec and attach are reserved to the operators",
+		},
+		{
+			name:     "exec by other group",
+			attrs:    authorizer.AttributesRecord{User: operator, Verb: "create", Resource: "pods", Subresource: "exec", Namespace: "default", Name: "web", ResourceRequest: true},
+			expected: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:     "other subresource",
+			attrs:    authorizer.AttributesRecord{User: developer, Verb: "get", Resource: "pods", Subresource: "log", Namespace: "default", Name: "web", ResourceRequest: true},
+			expected: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:           "node proxy by anyone",
+			attrs:          authorizer.AttributesRecord{User: operator, Verb: "get", Resource: "nodes", Subresource: "proxy", Name: "node-1", ResourceRequest: true},
+			expected:       authorizer.DecisionDeny,
+			expectedRule:   "no-node-proxy",
+			expectedReason: "the request is on the deny list",
+		},
+		{
+			name:           "node proxy without user",
+			attrs:          authorizer.AttributesRecord{Verb: "get", Resource: "nodes", Subresource: "proxy", Name: "node-1", ResourceRequest: true},
+			expected:       authorizer.DecisionDeny,
+			expectedRule:   "no-node-proxy",
+			expectedReason: "the request is on the deny list",
+		},
+		{
+			name:           "non-resource URL by user",
+			attrs:          authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "mallory"}, Verb: "get", Path: "/debug/pprof/heap"},
+			expected:       authorizer.DecisionDeny,
+			expectedRule:   "no-debug",
+			expectedReason: "the request is on the deny list",
+		},
+		{
+			name:     "non-resource URL by other user",
+			attrs:    authorizer.AttributesRecord{User: developer, Verb: "get", Path: "/debug/pprof/heap"},
+			expected: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:     "subject rule without user",
+			attrs:    authorizer.AttributesRecord{Verb: "get", Path: "/debug/pprof/heap"},
+			expected: authorizer.DecisionNoOpinion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+			ctx := audit.WithAuditContext(context.Background(), &audit.AuditContext{Event: ev})
+
+			decision, reason, err := a.Authorize(ctx, &tt.attrs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision != tt.expected {
+				t.Fatalf("expected decision %v, got %v", tt.expected, decision)
+			}
+			if ev.Annotations[DenyRuleAnnotationKey] != tt.expectedRule {
+				t.Errorf("expected deny rule annotation %q, got %q", tt.expectedRule, ev.Annotations[DenyRuleAnnotationKey])
+			}
+			if ev.Annotations[DenyReasonAnnotationKey] != tt.expectedReason {
+				t.Errorf("expected deny reason annotation %q, got %q", tt.expectedReason, ev.Annotations[DenyReasonAnnotationKey])
+			}
+			if len(tt.expectedRule) > 0 && !strings.Contains(reason, tt.expectedRule) {
+				t.Errorf("expected the reason %q to name the rule %q", reason, tt.expectedRule)
+			}
+		})
+	}
+}
+
+func TestNewDenyListAuthorizerValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       []DenyRule
+		expectedErr string
+	}{
+		{
+			name:        "no name",
+			rules:       []DenyRule{{PolicyRule: PolicyRule{Verbs: []string{"*"}, Resources: []string{"nodes/proxy"}}}},
+			expectedErr: "rules[0]: name is required",
+		},
+		{
+			name: "duplicate name",
+			rules: []DenyRule{
+				{Name: "proxy", PolicyRule: PolicyRule{Verbs: []string{"*"}, Resources: []string{"nodes/proxy"}}},
+				{Name: "proxy", PolicyRule: PolicyRule{Verbs: []string{"*"}, Resources: []string{"services/proxy"}}},
+			},
+			expectedErr: `rules[1]: duplicate name "proxy"`,
+		},
+		{
+			name:        "no verbs",
+			rules:       []DenyRule{{Name: "proxy", PolicyRule: PolicyRule{Resources: []string{"nodes/proxy"}}}},
+			expectedErr: "rules[0]: verbs are required",
+		},
+		{
+			name:        "no resources nor non-resource URLs",
+			rules:       []DenyRule{{Name: "proxy", PolicyRule: PolicyRule{Verbs: []string{"*"}}}},
+			expectedErr: "rules[0]: exactly one of resources or nonResourceURLs is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDenyListAuthorizer(tt.rules)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
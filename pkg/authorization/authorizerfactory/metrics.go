@@ -18,6 +18,7 @@ package authorizerfactory
 
 import (
 	"context"
+	"strconv"
 
 	compbasemetrics "k8s.io/component-base/metrics"
 	"k8s.io/component-base/metrics/legacyregistry"
@@ -52,9 +53,70 @@ var (
 		[]string{"code"},
 	)
 
+	cacheLookupTotal = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "apiserver_delegated_authz_cache_lookups_total",
+			Help:           "Number of lookups of the response caches. Broken down by result: authorized or unauthorized for hits of the respective cache, or miss.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"result"},
+	)
+
+	endpointRequestTotal = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "apiserver_delegated_authz_endpoint_request_total",
+			Help:           "Number of HTTP requests to each endpoint of the delegated authorization, partitioned by endpoint and status code.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"endpoint", "code"},
+	)
+
+	endpointHealthy = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Name:           "apiserver_delegated_authz_endpoint_healthy",
+			Help:           "Whether each endpoint of the delegated authorization is healthy (1) or failed over from (0).",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"endpoint"},
+	)
+
+	queueWaitDuration = compbasemetrics.NewHistogramVec(
+		&compbasemetrics.HistogramOpts{
+			Name:           "apiserver_delegated_authz_queue_wait_duration_seconds",
+			Help:           "Time in seconds reviews of the delegated authorization waited for its rate limit. Broken down by the priority level of the request.",
+			Buckets:        []float64{0.005, 0.025, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"priority_level"},
+	)
+
+	decisionTotal = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "apiserver_delegated_authz_decisions_total",
+			Help:           "Number of requests decided by the delegated authorization. Broken down by decision: allow, deny or no-opinion, and whether an error was returned.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"decision", "error"},
+	)
+
+	policyFileReloadTotal = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "apiserver_authorization_policy_file_reloads_total",
+			Help:           "Number of attempts to load a changed authorization policy file. Broken down by status: success, or failure if the new content is invalid.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"status"},
+	)
+
 	metrics = registerables{
 		requestTotal,
 		requestLatency,
+		cacheLookupTotal,
+		endpointRequestTotal,
+		endpointHealthy,
+		queueWaitDuration,
+		decisionTotal,
+		policyFileReloadTotal,
 	}
 )
 
@@ -67,3 +129,47 @@ func RecordRequestTotal(ctx context.Context, code string) {
 func RecordRequestLatency(ctx context.Context, code string, latency float64) {
 	requestLatency.WithContext(ctx).WithLabelValues(code).Observe(latency)
 }
+
+// RecordCacheLookup increments the number of response cache lookups for the delegated authorization. Broken down by result.
+func RecordCacheLookup(ctx context.Context, result string) {
+	cacheLookupTotal.WithContext(ctx).WithLabelValues(result).Inc()
+}
+
+// RecordEndpointRequest increments the number of requests to an endpoint of the delegated authorization.
+// Broken down by endpoint and status code.
+func RecordEndpointRequest(ctx context.Context, endpoint, code string) {
+	endpointRequestTotal.WithContext(ctx).WithLabelValues(endpoint, code).Inc()
+}
+
+// RecordEndpointHealth records whether an endpoint of the delegated authorization is healthy.
+func RecordEndpointHealth(endpoint string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	endpointHealthy.WithLabelValues(endpoint).Set(value)
+}
+
+// RecordQueueWait measures the time in seconds a review of the delegated authorization waited for its rate limit.
+// Broken down by the priority level of the request, "unclassified" if it has none.
+func RecordQueueWait(ctx context.Context, priorityLevel string, wait float64) {
+	if len(priorityLevel) == 0 {
+		priorityLevel = "unclassified"
+	}
+	queueWaitDuration.WithContext(ctx).WithLabelValues(priorityLevel).Observe(wait)
+}
+
+// RecordDecision increments the number of requests decided by the delegated authorization.
+// Broken down by decision and whether an error was returned.
+func RecordDecision(ctx context.Context, decision string, failed bool) {
+	decisionTotal.WithContext(ctx).WithLabelValues(decision, strconv.FormatBool(failed)).Inc()
+}
+
+// recordPolicyFileReload increments the number of loads of a changed authorization policy file.
+func recordPolicyFileReload(success bool) {
+	status := "success"
+	if !success {
+		status = "failure"
+	}
+	policyFileReloadTotal.WithLabelValues(status).Inc()
+}
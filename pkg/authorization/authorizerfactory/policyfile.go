@@ -0,0 +1,349 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizerfactory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"sigs.k8s.io/yaml"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/klog/v2"
+)
+
+// PolicyFileRefreshDuration is the interval at which the policy file is read again, in case
+// changes to it are missed by the file watch. It is exposed so that tests can speed it up.
+var PolicyFileRefreshDuration = 1 * time.Minute
+
+// Policy is the content of a policy file, a list of rules of which any may allow a request.
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule allows the given users and groups to perform the given verbs, either on the
+// given resources, or on the given non-resource URLs. All lists accept "*" to match anything.
+type PolicyRule struct {
+	// Users and Groups are the subjects of the rule. A request matches the rule if its user
+	// is one of Users, or belongs to one of Groups.
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+
+	// Verbs are the request verbs the rule allows, such as "get" or "list".
+	Verbs []string `json:"verbs"`
+
+	// APIGroups, Resources and Namespaces restrict the resource requests the rule allows.
+	// A resource may name a subresource as "pods/log", and "*/scale" matches a subresource of
+	// any resource. An empty list of Namespaces matches any namespace and cluster scoped resources.
+	APIGroups  []string `json:"apiGroups,omitempty"`
+	Resources  []string `json:"resources,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NonResourceURLs are the paths of the non-resource requests the rule allows. A path
+	// ending in "*" matches any path with the preceding prefix.
+	NonResourceURLs []string `json:"nonResourceURLs,omitempty"`
+}
+
+// PolicyFileAuthorizer allows the requests matching a rule of a policy file, and has no opinion
+// on other requests. The policy file is read again when it changes while Run is running.
+type PolicyFileAuthorizer struct {
+	filename string
+
+	// policy is the last successfully loaded *Policy.
+	policy atomic.Value
+
+	// lock serializes the loading of the policy file.
+	lock sync.Mutex
+	// content is the content of the file the current policy was loaded from.
+	content []byte
+}
+
+var _ authorizer.Authorizer = &PolicyFileAuthorizer{}
+var _ authorizer.RuleResolver = &PolicyFileAuthorizer{}
+
+// NewPolicyFileAuthorizer returns an authorizer using the policy in filename, which is written
+// in YAML or JSON. It fails if the file cannot be read or holds an invalid policy.
+func NewPolicyFileAuthorizer(filename string) (*PolicyFileAuthorizer, error) {
+	if len(filename) == 0 {
+		return nil, fmt.Errorf("missing filename for authorization policy")
+	}
+
+	a := &PolicyFileAuthorizer{filename: filename}
+	if err := a.loadPolicy(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ParsePolicy parses and validates a policy written in YAML or JSON.
+func ParsePolicy(data []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse authorization policy: %v", err)
+	}
+	if err := validatePolicy(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func validatePolicy(policy *Policy) error {
+	var errs []error
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+			errs = append(errs, fmt.Errorf("rules[%d]: at least one of users or groups is required", i))
+		}
+		errs = append(errs, validateRuleTargets(i, rule, true)...)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// validateRuleTargets validates the verbs, resources and non-resource URLs of the rule at index i.
+func validateRuleTargets(i int, rule *PolicyRule, requireAPIGroups bool) []error {
+	var errs []error
+	if len(rule.Verbs) == 0 {
+		errs = append(errs, fmt.Errorf("rules[%d]: verbs are required", i))
+	}
+	isResourceRule := len(rule.Resources) > 0
+	isNonResourceRule := len(rule.NonResourceURLs) > 0
+	switch {
+	case isResourceRule == isNonResourceRule:
+		errs = append(errs, fmt.Errorf("rules[%d]: exactly one of resources or nonResourceURLs is required", i))
+	case isResourceRule && requireAPIGroups && len(rule.APIGroups) == 0:
+		errs = append(errs, fmt.Errorf("rules[%d]: apiGroups are required with resources", i))
+	case isNonResourceRule && (len(rule.APIGroups) > 0 || len(rule.Namespaces) > 0):
+		errs = append(errs, fmt.Errorf("rules[%d]: apiGroups and namespaces are not allowed with nonResourceURLs", i))
+	}
+	for _, url := range rule.NonResourceURLs {
+		if strings.Contains(strings.TrimSuffix(url, "*"), "*") {
+			errs = append(errs, fmt.Errorf("rules[%d]: nonResourceURL %q may only contain a trailing \"*\"", i, url))
+		}
+	}
+	return errs
+}
+
+// loadPolicy reads the policy file, and replaces the current policy if the content changed and is valid.
+func (a *PolicyFileAuthorizer) loadPolicy() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	content, err := ioutil.ReadFile(a.filename)
+	if err != nil {
+		return err
+	}
+	// An empty file is more likely being written than meant to allow nothing.
+	if len(bytes.TrimSpace(content)) == 0 {
+		return fmt.Errorf("missing content for authorization policy file %s", a.filename)
+	}
+	if a.content != nil && bytes.Equal(a.content, content) {
+		return nil
+	}
+
+	policy, err := ParsePolicy(content)
+	if err != nil {
+		recordPolicyFileReload(false)
+		return fmt.Errorf("failed to load authorization policy file %s: %v", a.filename, err)
+	}
+	a.policy.Store(policy)
+	a.content = content
+	recordPolicyFileReload(true)
+	klog.V(2).InfoS("Loaded authorization policy", "file", a.filename, "rules", len(policy.Rules))
+	return nil
+}
+
+// Run reloads the policy file whenever it changes, and at least every PolicyFileRefreshDuration,
+// until ctx is done. A policy file that cannot be read or is invalid leaves the current policy in place.
+func (a *PolicyFileAuthorizer) Run(ctx context.Context) {
+	klog.InfoS("Starting authorization policy file watch", "file", a.filename)
+	defer klog.InfoS("Shutting down authorization policy file watch", "file", a.filename)
+
+	go wait.Until(func() {
+		if err := a.loadPolicy(); err != nil {
+			klog.ErrorS(err, "Failed to reload authorization policy")
+		}
+	}, PolicyFileRefreshDuration, ctx.Done())
+
+	go wait.Until(func() {
+		if err := a.watchPolicyFile(ctx.Done()); err != nil {
+			klog.ErrorS(err, "Failed to watch authorization policy file, will retry later")
+		}
+	}, time.Minute, ctx.Done())
+
+	<-ctx.Done()
+}
+
+func (a *PolicyFileAuthorizer) watchPolicyFile(stopCh <-chan struct{}) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating fsnotify watcher: %v", err)
+	}
+	defer w.Close()
+
+	if err := w.Add(a.filename); err != nil {
+		return fmt.Errorf("error adding watch for file %s: %v", a.filename, err)
+	}
+	// Reload in case the file was updated before the watch started.
+	a.reloadAfterEvent()
+
+	for {
+		select {
+		case e := <-w.Events:
+			// Editors and volume mounts replace the file, which ends the watch, so watch the new file.
+			if e.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				if err := w.Remove(a.filename); err != nil {
+					klog.InfoS("Failed to remove file watch, it may have been deleted", "file", a.filename, "err", err)
+				}
+				if err := w.Add(a.filename); err != nil {
+					return fmt.Errorf("error adding watch for file %s: %v", a.filename, err)
+				}
+			}
+			a.reloadAfterEvent()
+		case err := <-w.Errors:
+			return fmt.Errorf("received fsnotify error: %v", err)
+		case <-stopCh:
+			return nil
+		}
+	}
+}
+
+func (a *PolicyFileAuthorizer) reloadAfterEvent() {
+	if err := a.loadPolicy(); err != nil {
+		klog.ErrorS(err, "Failed to reload authorization policy")
+	}
+}
+
+// currentPolicy returns the last successfully loaded policy.
+func (a *PolicyFileAuthorizer) currentPolicy() *Policy {
+	return a.policy.Load().(*Policy)
+}
+
+// Authorize allows a request if a rule of the policy matches it, and has no opinion otherwise.
+func (a *PolicyFileAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	u := attr.GetUser()
+	if u == nil {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	for i, rule := range a.currentPolicy().Rules {
+		if !rule.appliesTo(u) {
+			continue
+		}
+		if attr.IsResourceRequest() && rule.allowsResourceRequest(attr) || !attr.IsResourceRequest() && rule.allowsNonResourceRequest(attr) {
+			return authorizer.DecisionAllow, fmt.Sprintf("allowed by rules[%d] of the authorization policy file", i), nil
+		}
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
+
+// RulesFor returns the rules of the policy that apply to user in namespace.
+func (a *PolicyFileAuthorizer) RulesFor(u user.Info, namespace string) ([]authorizer.ResourceRuleInfo, []authorizer.NonResourceRuleInfo, bool, error) {
+	var (
+		resourceRules    []authorizer.ResourceRuleInfo
+		nonResourceRules []authorizer.NonResourceRuleInfo
+	)
+	for _, rule := range a.currentPolicy().Rules {
+		if !rule.appliesTo(u) {
+			continue
+		}
+		switch {
+		case len(rule.NonResourceURLs) > 0:
+			nonResourceRules = append(nonResourceRules, &authorizer.DefaultNonResourceRuleInfo{
+				Verbs:           rule.Verbs,
+				NonResourceURLs: rule.NonResourceURLs,
+			})
+		case matches(rule.Namespaces, namespace, true):
+			resourceRules = append(resourceRules, &authorizer.DefaultResourceRuleInfo{
+				Verbs:     rule.Verbs,
+				APIGroups: rule.APIGroups,
+				Resources: rule.Resources,
+			})
+		}
+	}
+	return resourceRules, nonResourceRules, false, nil
+}
+
+// appliesTo returns whether u is a subject of the rule.
+func (r *PolicyRule) appliesTo(u user.Info) bool {
+	if matches(r.Users, u.GetName(), false) || matches(r.Groups, "*", false) {
+		return true
+	}
+	for _, group := range u.GetGroups() {
+		if matches(r.Groups, group, false) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PolicyRule) allowsResourceRequest(attr authorizer.Attributes) bool {
+	return matches(r.Verbs, attr.GetVerb(), false) &&
+		matches(r.APIGroups, attr.GetAPIGroup(), false) &&
+		matches(r.Namespaces, attr.GetNamespace(), true) &&
+		r.matchesResource(attr.GetResource(), attr.GetSubresource())
+}
+
+func (r *PolicyRule) matchesResource(resource, subresource string) bool {
+	combined := resource
+	if len(subresource) > 0 {
+		combined = resource + "/" + subresource
+	}
+	for _, allowed := range r.Resources {
+		switch {
+		case allowed == "*", allowed == combined:
+			return true
+		case len(subresource) > 0 && allowed == "*/"+subresource:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PolicyRule) allowsNonResourceRequest(attr authorizer.Attributes) bool {
+	if !matches(r.Verbs, attr.GetVerb(), false) {
+		return false
+	}
+	for _, url := range r.NonResourceURLs {
+		if url == attr.GetPath() || strings.HasSuffix(url, "*") && strings.HasPrefix(attr.GetPath(), strings.TrimSuffix(url, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns whether value is in allowed or allowed contains "*". If emptyMatchesAll is
+// true, an empty allowed list matches any value.
+func matches(allowed []string, value string, emptyMatchesAll bool) bool {
+	if len(allowed) == 0 {
+		return emptyMatchesAll
+	}
+	for _, a := range allowed {
+		if a == "*" || a == value {
+			return true
+		}
+	}
+	return false
+}
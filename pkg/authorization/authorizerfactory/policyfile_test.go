@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizerfactory
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+const testPolicy = `
+rules:
+- users: ["alice"]
+  verbs: ["get", "list"]
+  apiGroups: [""]
+  resources: ["pods", "pods/log"]
+  namespaces: ["team-a"]
+- groups: ["admins"]
+  verbs: ["*"]
+  apiGroups: ["*"]
+  resources: ["*"]
+- groups: ["*"]
+  verbs: ["update"]
+  apiGroups: ["apps"]
+  resources: ["*/scale"]
+- users: ["*"]
+  verbs: ["get"]
+  nonResourceURLs: ["/healthz", "/metrics/*"]
+`
+
+func writePolicyFile(t *testing.T, filename, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestPolicyFileAuthorizer(t *testing.T, content string) (*PolicyFileAuthorizer, string) {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicyFile(t, filename, content)
+	a, err := NewPolicyFileAuthorizer(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a, filename
+}
+
+func TestPolicyFileAuthorizer(t *testing.T) {
+	a, _ := newTestPolicyFileAuthorizer(t, testPolicy)
+
+	alice := &user.DefaultInfo{Name: "alice"}
+	bob := &user.DefaultInfo{Name: "bob", Groups: []string{"admins"}}
+	carol := &user.DefaultInfo{Name: "carol", Groups: []string{"developers"}}
+
+	tests := []struct {
+		name     string
+		attrs    authorizer.AttributesRecord
+		expected authorizer.Decision
+	}{
+		{
+			name:     "user allowed in namespace",
+			attrs:    authorizer.AttributesRecord{User: alice, Verb: "list", Resource: "pods", Namespace: "team-a", ResourceRequest: true},
+			expected: authorizer.DecisionAllow,
+		},
+		{
+			name:     "user allowed on subresource",
+			attrs:    authorizer.AttributesRecord{User: alice, Verb: "get", Resource: "pods", Subresource: "log", Name: "web", Namespace: "team-a", ResourceRequest: true},
+			expected: authorizer.DecisionAllow,
+		},
+		{
+			name:     "user not allowed on other subresource",
+			attrs:    authorizer.AttributesRecord{User: alice, Verb: "get", Resource: "pods", Subresource: "exec", Name: "web", Namespace: "team-a", ResourceRequest: true},
+			expected: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:     "user not allowed in other namespace",
+			attrs:    authorizer.AttributesRecord{User: alice, Verb: "list", Resource: "pods", Namespace: "team-b", ResourceRequest: true},
+			expected: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:     "user not allowed verb",
+			attrs:    authorizer.AttributesRecord{User: alice, Verb: "delete", Resource: "pods", Namespace: "team-a", ResourceRequest: true},
+			expected: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:     "group allowed everything",
+			attrs:    authorizer.AttributesRecord{User: bob, Verb: "delete", APIGroup: "apps", Resource: "deployments", ResourceRequest: true},
+			expected: authorizer.DecisionAllow,
+		},
+		{
+			name:     "group wildcard on subresource of any resource",
+			attrs:    authorizer.AttributesRecord{User: carol, Verb: "update", APIGroup: "apps", Resource: "deployments", Subresource: "scale", Namespace: "team-b", ResourceRequest: true},
+			expected: authorizer.DecisionAllow,
+		},
+		{
+			name:     "group wildcard not on resource",
+			attrs:    authorizer.AttributesRecord{User: carol, Verb: "update", APIGroup: "apps", Resource: "deployments", Namespace: "team-b", ResourceRequest: true},
+			expected: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:     "non-resource exact path",
+			attrs:    authorizer.AttributesRecord{User: carol, Verb: "get", Path: "/healthz"},
+			expected: authorizer.DecisionAllow,
+		},
+		{
+			name:     "non-resource path prefix",
+			attrs:    authorizer.AttributesRecord{User: carol, Verb: "get", Path: "/metrics/slis"},
+			expected: authorizer.DecisionAllow,
+		},
+		{
+			name:     "non-resource path not allowed",
+			attrs:    authorizer.AttributesRecord{User: carol, Verb: "get", Path: "/debug/pprof"},
+			expected: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:     "non-resource rule does not allow resources",
+			attrs:    authorizer.AttributesRecord{User: carol, Verb: "get", Resource: "pods", Namespace: "team-a", ResourceRequest: true},
+			expected: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:     "no user",
+			attrs:    authorizer.AttributesRecord{Verb: "get", Path: "/healthz"},
+			expected: authorizer.DecisionNoOpinion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, _, err := a.Authorize(context.Background(), &tt.attrs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision != tt.expected {
+				t.Errorf("expected decision %v, got %v", tt.expected, decision)
+			}
+		})
+	}
+}
+
+func TestPolicyFileAuthorizerRulesFor(t *testing.T) {
+	a, _ := newTestPolicyFileAuthorizer(t, testPolicy)
+
+	resourceRules, nonResourceRules, incomplete, err := a.RulesFor(&user.DefaultInfo{Name: "alice"}, "team-b")
+	if err != nil || incomplete {
+		t.Fatalf("unexpected result: incomplete=%v, err=%v", incomplete, err)
+	}
+	expectedResourceRules := []authorizer.ResourceRuleInfo{
+		&authorizer.DefaultResourceRuleInfo{Verbs: []string{"update"}, APIGroups: []string{"apps"}, Resources: []string{"*/scale"}},
+	}
+	if !reflect.DeepEqual(resourceRules, expectedResourceRules) {
+		t.Errorf("expected resource rules %#v, got %#v", expectedResourceRules, resourceRules)
+	}
+	expectedNonResourceRules := []authorizer.NonResourceRuleInfo{
+		&authorizer.DefaultNonResourceRuleInfo{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz", "/metrics/*"}},
+	}
+	if !reflect.DeepEqual(nonResourceRules, expectedNonResourceRules) {
+		t.Errorf("expected non-resource rules %#v, got %#v", expectedNonResourceRules, nonResourceRules)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		expectedErr string
+	}{
+		{
+			name:   "valid",
+			policy: testPolicy,
+		},
+		{
+			name:   "json",
+			policy: `{"rules": [{"users": ["alice"], "verbs": ["get"], "nonResourceURLs": ["/version"]}]}`,
+		},
+		{
+			name:        "unknown field",
+			policy:      `{"rules": [{"user": ["alice"], "verbs": ["get"], "nonResourceURLs": ["/version"]}]}`,
+			expectedErr: "failed to parse authorization policy",
+		},
+		{
+			name:        "no subjects",
+			policy:      `{"rules": [{"verbs": ["get"], "nonResourceURLs": ["/version"]}]}`,
+			expectedErr: "rules[0]: at least one of users or groups is required",
+		},
+		{
+			name:        "no verbs",
+			policy:      `{"rules": [{"users": ["alice"], "nonResourceURLs": ["/version"]}]}`,
+			expectedErr: "rules[0]: verbs are required",
+		},
+		{
+			name:        "resources and non-resource URLs",
+			policy:      `{"rules": [{"users": ["alice"], "verbs": ["get"], "apiGroups": [""], "resources": ["pods"], "nonResourceURLs": ["/version"]}]}`,
+			expectedErr: "rules[0]: exactly one of resources or nonResourceURLs is required",
+		},
+		{
+			name:        "resources without API groups",
+			policy:      `{"rules": [{"users": ["alice"], "verbs": ["get"], "resources": ["pods"]}]}`,
+			expectedErr: "rules[0]: apiGroups are required with resources",
+		},
+		{
+			name:        "namespaces with non-resource URLs",
+			policy:      `{"rules": [{"users": ["alice"], "verbs": ["get"], "namespaces": ["default"], "nonResourceURLs": ["/version"]}]}`,
+			expectedErr: "rules[0]: apiGroups and namespaces are not allowed with nonResourceURLs",
+		},
+		{
+			name:        "inner wildcard in non-resource URL",
+			policy:      `{"rules": [{"users": ["alice"], "verbs": ["get"], "nonResourceURLs": ["/apis/*/v1"]}]}`,
+			expectedErr: `rules[0]: nonResourceURL "/apis/*/v1" may only contain a trailing "*"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.policy))
+			if len(tt.expectedErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestPolicyFileAuthorizerReload(t *testing.T) {
+	a, filename := newTestPolicyFileAuthorizer(t, `{"rules": [{"users": ["alice"], "verbs": ["get"], "nonResourceURLs": ["/version"]}]}`)
+	attrs := &authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "bob"}, Verb: "get", Path: "/version"}
+
+	if decision, _, _ := a.Authorize(context.Background(), attrs); decision != authorizer.DecisionNoOpinion {
+		t.Fatalf("expected no opinion before the reload, got %v", decision)
+	}
+
+	originalRefresh := PolicyFileRefreshDuration
+	PolicyFileRefreshDuration = 10 * time.Millisecond
+	defer func() { PolicyFileRefreshDuration = originalRefresh }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.Run(ctx)
+
+	writePolicyFile(t, filename, `{"rules": [{"users": ["alice", "bob"], "verbs": ["get"], "nonResourceURLs": ["/version"]}]}`)
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		decision, _, _ := a.Authorize(context.Background(), attrs)
+		return decision == authorizer.DecisionAllow, nil
+	}); err != nil {
+		t.Fatalf("the changed policy file was not reloaded: %v", err)
+	}
+
+	// An invalid policy leaves the current one in place.
+	writePolicyFile(t, filename, `{"rules": [{"users": ["alice"]}]}`)
+	if err := a.loadPolicy(); err == nil {
+		t.Fatal("expected an error loading an invalid policy")
+	}
+	if decision, _, _ := a.Authorize(context.Background(), attrs); decision != authorizer.DecisionAllow {
+		t.Errorf("expected the previous policy to stay in place, got %v", decision)
+	}
+}
+
+func TestNewPolicyFileAuthorizerEmptyFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicyFile(t, filename, "\n")
+	if _, err := NewPolicyFileAuthorizer(filename); err == nil || !strings.Contains(err.Error(), "missing content") {
+		t.Errorf("expected an error for an empty policy file, got %v", err)
+	}
+}
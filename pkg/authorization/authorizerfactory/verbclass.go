@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizerfactory
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// VerbClass groups the verbs of requests that may be authorized by different authorizer chains.
+type VerbClass string
+
+const (
+	// VerbClassRead are the read-only requests: get, list and watch.
+	VerbClassRead VerbClass = "read"
+	// VerbClassWrite are the requests of all other verbs, except connect requests.
+	VerbClassWrite VerbClass = "write"
+	// VerbClassConnect are the requests connecting to a pod or proxying to a resource, whatever
+	// their verb: the proxy verb, and the exec, attach, portforward and proxy subresources.
+	VerbClassConnect VerbClass = "connect"
+)
+
+// VerbClasses are all verb classes.
+var VerbClasses = []VerbClass{VerbClassRead, VerbClassWrite, VerbClassConnect}
+
+var connectSubresources = sets.NewString("exec", "attach", "portforward", "proxy")
+
+// VerbClassOf returns the verb class of a request.
+func VerbClassOf(attr authorizer.Attributes) VerbClass {
+	if attr.GetVerb() == "proxy" || (attr.IsResourceRequest() && connectSubresources.Has(attr.GetSubresource())) {
+		return VerbClassConnect
+	}
+	if attr.IsReadOnly() {
+		return VerbClassRead
+	}
+	return VerbClassWrite
+}
+
+// verbClassAuthorizer delegates each request to the authorizer of its verb class.
+type verbClassAuthorizer map[VerbClass]authorizer.Authorizer
+
+// NewVerbClassAuthorizer returns an authorizer delegating each request to the authorizer of its
+// verb class, so that e.g. reads are authorized by fast local authorizers only, while writes always
+// consult a webhook. It has no opinion on the requests of verb classes without an authorizer.
+func NewVerbClassAuthorizer(authorizers map[VerbClass]authorizer.Authorizer) authorizer.Authorizer {
+	a := verbClassAuthorizer{}
+	for class, classAuthorizer := range authorizers {
+		a[class] = classAuthorizer
+	}
+	return a
+}
+
+func (a verbClassAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	classAuthorizer, ok := a[VerbClassOf(attr)]
+	if !ok {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	return classAuthorizer.Authorize(ctx, attr)
+}
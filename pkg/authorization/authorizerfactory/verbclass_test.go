@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizerfactory
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+func TestVerbClassOf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		attr     authorizer.AttributesRecord
+		expected VerbClass
+	}{
+		{"get", authorizer.AttributesRecord{Verb: "get", Resource: "pods", ResourceRequest: true}, VerbClassRead},
+		{"list", authorizer.AttributesRecord{Verb: "list", Resource: "pods", ResourceRequest: true}, VerbClassRead},
+		{"watch", authorizer.AttributesRecord{Verb: "watch", Resource: "pods", ResourceRequest: true}, VerbClassRead},
+		{"non-resource get", authorizer.AttributesRecord{Verb: "get", Path: "/metrics"}, VerbClassRead},
+		{"create", authorizer.AttributesRecord{Verb: "create", Resource: "pods", ResourceRequest: true}, VerbClassWrite},
+		{"deletecollection", authorizer.AttributesRecord{Verb: "deletecollection", Resource: "pods", ResourceRequest: true}, VerbClassWrite},
+		{"non-resource post", authorizer.AttributesRecord{Verb: "post", Path: "/exec"}, VerbClassWrite},
+		{"exec", authorizer.AttributesRecord{Verb: "create", Resource: "pods", Subresource: "exec", ResourceRequest: true}, VerbClassConnect},
+		{"exec with get", authorizer.AttributesRecord{Verb: "get", Resource: "pods", Subresource: "exec", ResourceRequest: true}, VerbClassConnect},
+		{"node proxy", authorizer.AttributesRecord{Verb: "get", Resource: "nodes", Subresource: "proxy", ResourceRequest: true}, VerbClassConnect},
+		{"proxy verb", authorizer.AttributesRecord{Verb: "proxy", Resource: "services", ResourceRequest: true}, VerbClassConnect},
+		{"status", authorizer.AttributesRecord{Verb: "get", Resource: "pods", Subresource: "status", ResourceRequest: true}, VerbClassRead},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if class := VerbClassOf(tc.attr); class != tc.expected {
+				t.Errorf("expected verb class %q, got %q", tc.expected, class)
+			}
+		})
+	}
+}
+
+func TestVerbClassAuthorizer(t *testing.T) {
+	decide := func(decision authorizer.Decision, reason string) authorizer.Authorizer {
+		return authorizer.AuthorizerFunc(func(context.Context, authorizer.Attributes) (authorizer.Decision, string, error) {
+			return decision, reason, nil
+		})
+	}
+	a := NewVerbClassAuthorizer(map[VerbClass]authorizer.Authorizer{
+		VerbClassRead:  decide(authorizer.DecisionAllow, "local"),
+		VerbClassWrite: decide(authorizer.DecisionDeny, "webhook"),
+	})
+	alice := &user.DefaultInfo{Name: "alice"}
+
+	testCases := []struct {
+		name             string
+		attr             authorizer.AttributesRecord
+		expectedDecision authorizer.Decision
+		expectedReason   string
+	}{
+		{"read", authorizer.AttributesRecord{User: alice, Verb: "list", Resource: "pods", ResourceRequest: true}, authorizer.DecisionAllow, "local"},
+		{"write", authorizer.AttributesRecord{User: alice, Verb: "delete", Resource: "pods", ResourceRequest: true}, authorizer.DecisionDeny, "webhook"},
+		{"connect without authorizer", authorizer.AttributesRecord{User: alice, Verb: "create", Resource: "pods", Subresource: "exec", ResourceRequest: true}, authorizer.DecisionNoOpinion, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision, reason, err := a.Authorize(context.Background(), tc.attr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decision != tc.expectedDecision || reason != tc.expectedReason {
+				t.Errorf("expected %v %q, got %v %q", tc.expectedDecision, tc.expectedReason, decision, reason)
+			}
+		})
+	}
+}
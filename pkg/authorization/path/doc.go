@@ -14,5 +14,6 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package path contains an authorizer that allows certain paths and path prefixes.
+// Package path contains an authorizer that allows certain paths and path prefixes, optionally
+// only with certain verbs and from certain networks.
 package path // import "k8s.io/apiserver/pkg/authorization/path"
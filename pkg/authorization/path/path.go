@@ -19,50 +19,155 @@ package path
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
 )
 
+// Rule allows the requests for some paths, optionally only with some verbs and from some networks,
+// e.g. unauthenticated GET requests on /healthz and /metrics from localhost only.
+type Rule struct {
+	// Paths are fully matching paths, or end in * in case a prefix match is done. A leading / is optional.
+	Paths []string
+	// Verbs restrict the rule to requests with these verbs, e.g. "get". Empty means any verb.
+	Verbs []string
+	// SourceCIDRs restrict the rule to requests received from these networks. Empty means from anywhere.
+	// The source of a request is the address of its connection, see request.RemoteIPFrom.
+	SourceCIDRs []string
+}
+
+// scopedRule is a parsed Rule.
+type scopedRule struct {
+	paths    sets.String
+	prefixes []string
+	verbs    sets.String
+	sources  []*net.IPNet
+}
+
 // NewAuthorizer returns an authorizer which accepts a given set of paths.
 // Each path is either a fully matching path or it ends in * in case a prefix match is done. A leading / is optional.
 func NewAuthorizer(alwaysAllowPaths []string) (authorizer.Authorizer, error) {
-	var prefixes []string
-	paths := sets.NewString()
-	for _, p := range alwaysAllowPaths {
+	return NewScopedAuthorizer([]Rule{{Paths: alwaysAllowPaths}})
+}
+
+// NewScopedAuthorizer returns an authorizer which accepts the non-resource requests matching any of rules.
+func NewScopedAuthorizer(rules []Rule) (authorizer.Authorizer, error) {
+	var scopedRules []*scopedRule
+	for _, rule := range rules {
+		r, err := newScopedRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		scopedRules = append(scopedRules, r)
+	}
+
+	return authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		if a.IsResourceRequest() {
+			return authorizer.DecisionNoOpinion, "", nil
+		}
+
+		for _, r := range scopedRules {
+			if r.matches(ctx, a) {
+				return authorizer.DecisionAllow, "", nil
+			}
+		}
+
+		return authorizer.DecisionNoOpinion, "", nil
+	}), nil
+}
+
+func newScopedRule(rule Rule) (*scopedRule, error) {
+	r := &scopedRule{paths: sets.NewString(), verbs: sets.NewString(rule.Verbs...)}
+	for _, p := range rule.Paths {
 		p = strings.TrimPrefix(p, "/")
 		if len(p) == 0 {
 			// matches "/"
-			paths.Insert(p)
+			r.paths.Insert(p)
 			continue
 		}
 		if strings.ContainsRune(p[:len(p)-1], '*') {
 			return nil, fmt.Errorf("only trailing * allowed in %q", p)
 		}
 		if strings.HasSuffix(p, "*") {
-			prefixes = append(prefixes, p[:len(p)-1])
+			r.prefixes = append(r.prefixes, p[:len(p)-1])
 		} else {
-			paths.Insert(p)
+			r.paths.Insert(p)
 		}
 	}
-
-	return authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
-		if a.IsResourceRequest() {
-			return authorizer.DecisionNoOpinion, "", nil
+	for _, cidr := range rule.SourceCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source CIDR %q: %v", cidr, err)
 		}
+		r.sources = append(r.sources, network)
+	}
+	return r, nil
+}
+
+func (r *scopedRule) matches(ctx context.Context, a authorizer.Attributes) bool {
+	if r.verbs.Len() > 0 && !r.verbs.Has(a.GetVerb()) {
+		return false
+	}
+	if len(r.sources) > 0 && !r.fromSource(ctx) {
+		return false
+	}
 
-		pth := strings.TrimPrefix(a.GetPath(), "/")
-		if paths.Has(pth) {
-			return authorizer.DecisionAllow, "", nil
+	pth := strings.TrimPrefix(a.GetPath(), "/")
+	if r.paths.Has(pth) {
+		return true
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(pth, prefix) {
+			return true
 		}
+	}
+	return false
+}
 
-		for _, prefix := range prefixes {
-			if strings.HasPrefix(pth, prefix) {
-				return authorizer.DecisionAllow, "", nil
-			}
+// fromSource returns whether the request of ctx was received from one of the source networks.
+// A request of unknown source is from none.
+func (r *scopedRule) fromSource(ctx context.Context) bool {
+	ip, ok := request.RemoteIPFrom(ctx)
+	if !ok {
+		return false
+	}
+	for _, network := range r.sources {
+		if network.Contains(ip) {
+			return true
 		}
+	}
+	return false
+}
 
-		return authorizer.DecisionNoOpinion, "", nil
-	}), nil
+// ParseRule parses a rule written as semicolon separated fields, each of a name and comma separated values,
+// e.g. "paths=/healthz,/metrics;verbs=get;sources=127.0.0.0/8,::1/128". The paths field is required.
+func ParseRule(s string) (Rule, error) {
+	var rule Rule
+	for _, field := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || len(value) == 0 {
+			return Rule{}, fmt.Errorf("invalid field %q of rule %q, must be of the form name=value[,value...]", field, s)
+		}
+		values := strings.Split(value, ",")
+		switch strings.TrimSpace(name) {
+		case "paths":
+			rule.Paths = values
+		case "verbs":
+			rule.Verbs = values
+		case "sources":
+			rule.SourceCIDRs = values
+		default:
+			return Rule{}, fmt.Errorf("unknown field %q of rule %q, must be one of paths, verbs, sources", name, s)
+		}
+	}
+	if len(rule.Paths) == 0 {
+		return Rule{}, fmt.Errorf("rule %q has no paths", s)
+	}
+	if _, err := newScopedRule(rule); err != nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: %v", s, err)
+	}
+	return rule, nil
 }
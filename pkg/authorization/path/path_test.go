@@ -18,9 +18,12 @@ package path
 
 import (
 	"context"
+	"net"
+	"reflect"
 	"testing"
 
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
 )
 
 func TestNewAuthorizer(t *testing.T) {
@@ -76,3 +79,73 @@ func TestNewAuthorizer(t *testing.T) {
 		})
 	}
 }
+
+func TestNewScopedAuthorizer(t *testing.T) {
+	a, err := NewScopedAuthorizer([]Rule{
+		{Paths: []string{"/healthz", "/metrics"}, Verbs: []string{"get"}, SourceCIDRs: []string{"127.0.0.0/8", "::1/128"}},
+		{Paths: []string{"/readyz*"}, Verbs: []string{"get", "head"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		attrs    authorizer.AttributesRecord
+		remoteIP string
+		want     authorizer.Decision
+	}{
+		{"from localhost", authorizer.AttributesRecord{Verb: "get", Path: "/metrics"}, "127.0.0.1", authorizer.DecisionAllow},
+		{"from IPv6 localhost", authorizer.AttributesRecord{Verb: "get", Path: "/healthz"}, "::1", authorizer.DecisionAllow},
+		{"from elsewhere", authorizer.AttributesRecord{Verb: "get", Path: "/metrics"}, "10.0.0.1", authorizer.DecisionNoOpinion},
+		{"from unknown source", authorizer.AttributesRecord{Verb: "get", Path: "/metrics"}, "", authorizer.DecisionNoOpinion},
+		{"other verb", authorizer.AttributesRecord{Verb: "post", Path: "/metrics"}, "127.0.0.1", authorizer.DecisionNoOpinion},
+		{"other path", authorizer.AttributesRecord{Verb: "get", Path: "/debug/pprof"}, "127.0.0.1", authorizer.DecisionNoOpinion},
+		{"resource request", authorizer.AttributesRecord{Verb: "get", Resource: "pods", ResourceRequest: true}, "127.0.0.1", authorizer.DecisionNoOpinion},
+		{"prefix from anywhere", authorizer.AttributesRecord{Verb: "head", Path: "/readyz/etcd"}, "10.0.0.1", authorizer.DecisionAllow},
+		{"prefix other verb", authorizer.AttributesRecord{Verb: "post", Path: "/readyz"}, "10.0.0.1", authorizer.DecisionNoOpinion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := request.WithRemoteIP(context.Background(), net.ParseIP(tt.remoteIP))
+			if got, _, err := a.Authorize(ctx, tt.attrs); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    Rule
+		wantErr bool
+	}{
+		{
+			rule: "paths=/healthz,/metrics;verbs=get;sources=127.0.0.0/8,::1/128",
+			want: Rule{Paths: []string{"/healthz", "/metrics"}, Verbs: []string{"get"}, SourceCIDRs: []string{"127.0.0.0/8", "::1/128"}},
+		},
+		{
+			rule: "paths=/readyz*",
+			want: Rule{Paths: []string{"/readyz*"}},
+		},
+		{rule: "verbs=get", wantErr: true},
+		{rule: "paths=/healthz;verbs", wantErr: true},
+		{rule: "paths=/healthz;users=bob", wantErr: true},
+		{rule: "paths=/foo*bar", wantErr: true},
+		{rule: "paths=/healthz;sources=localhost", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := ParseRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replay evaluates recorded requests, for example read from an audit log, against an
+// authorizer chain in dry-run mode, to validate changes of the authorization configuration
+// before they are rolled out.
+package replay
+
+import (
+	"context"
+
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/union"
+)
+
+// Result is the decision of an authorizer on a recorded request.
+type Result struct {
+	// Attributes are the attributes of the request.
+	Attributes authorizer.Attributes
+	// Decision, Reason and Err are as returned by the authorizer.
+	Decision authorizer.Decision
+	Reason   string
+	Err      error
+	// DecidingAuthorizer is the member of the authorizer union that made the decision. It is only
+	// set if Decided is true, i.e. the authorizer is a union and one of its members allowed or
+	// denied the request.
+	DecidingAuthorizer union.DecidingAuthorizer
+	Decided            bool
+}
+
+// Replay evaluates each of requests against a in dry-run mode, and returns the results in the order
+// of requests. Authorizers are expected not to record metrics or cache decisions in dry-run mode, see
+// authorizer.WithDryRun. If ctx is done, the remaining requests are not evaluated and the results
+// returned so far are returned.
+func Replay(ctx context.Context, a authorizer.Authorizer, requests []authorizer.Attributes) []Result {
+	results := make([]Result, 0, len(requests))
+	for _, attr := range requests {
+		if ctx.Err() != nil {
+			break
+		}
+		results = append(results, evaluate(ctx, a, attr))
+	}
+	return results
+}
+
+// Difference is a request on which two authorizers decide differently.
+type Difference struct {
+	// Current is the result of the authorizer in use.
+	Current Result
+	// Proposed is the result of the authorizer that would replace it.
+	Proposed Result
+}
+
+// Compare evaluates each of requests against current and proposed in dry-run mode, and returns the
+// requests on which they make different decisions, or on which only one of them fails, in the
+// order of requests. It answers what would change for recorded traffic if proposed replaced current.
+func Compare(ctx context.Context, current, proposed authorizer.Authorizer, requests []authorizer.Attributes) []Difference {
+	var differences []Difference
+	for _, attr := range requests {
+		if ctx.Err() != nil {
+			break
+		}
+		c, p := evaluate(ctx, current, attr), evaluate(ctx, proposed, attr)
+		if c.Decision != p.Decision || (c.Err == nil) != (p.Err == nil) {
+			differences = append(differences, Difference{Current: c, Proposed: p})
+		}
+	}
+	return differences
+}
+
+func evaluate(ctx context.Context, a authorizer.Authorizer, attr authorizer.Attributes) Result {
+	ctx = union.WithDecidingAuthorizer(authorizer.WithDryRun(ctx))
+	decision, reason, err := a.Authorize(ctx, attr)
+	result := Result{Attributes: attr, Decision: decision, Reason: reason, Err: err}
+	result.DecidingAuthorizer, result.Decided = union.DecidingAuthorizerFrom(ctx)
+	return result
+}
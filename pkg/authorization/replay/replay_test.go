@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/union"
+)
+
+// verbAuthorizer allows the verbs it is given, and has no opinion on others. It fails on the verb "fail".
+func verbAuthorizer(verbs ...string) authorizer.AuthorizerFunc {
+	return func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		if !authorizer.IsDryRun(ctx) {
+			return authorizer.DecisionDeny, "not a dry run", nil
+		}
+		if a.GetVerb() == "fail" {
+			return authorizer.DecisionNoOpinion, "", errors.New("failed")
+		}
+		for _, verb := range verbs {
+			if a.GetVerb() == verb {
+				return authorizer.DecisionAllow, "allowed " + verb, nil
+			}
+		}
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+}
+
+func requests(verbs ...string) []authorizer.Attributes {
+	var attrs []authorizer.Attributes
+	for _, verb := range verbs {
+		attrs = append(attrs, authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "alice"}, Verb: verb, ResourceRequest: true, Resource: "pods"})
+	}
+	return attrs
+}
+
+func TestReplay(t *testing.T) {
+	a := union.New(union.Named("readers", verbAuthorizer("get", "list")), union.Named("writers", verbAuthorizer("create")))
+
+	results := Replay(context.Background(), a, requests("get", "create", "delete"))
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	if r := results[0]; r.Decision != authorizer.DecisionAllow || r.Reason != "allowed get" || !r.Decided || r.DecidingAuthorizer.Name != "readers" || r.DecidingAuthorizer.Index != 0 {
+		t.Errorf("unexpected result for get: %+v", r)
+	}
+	if r := results[1]; r.Decision != authorizer.DecisionAllow || !r.Decided || r.DecidingAuthorizer.Name != "writers" || r.DecidingAuthorizer.Index != 1 {
+		t.Errorf("unexpected result for create: %+v", r)
+	}
+	if r := results[2]; r.Decision != authorizer.DecisionNoOpinion || r.Decided || r.Err != nil {
+		t.Errorf("unexpected result for delete: %+v", r)
+	}
+	for i, r := range results {
+		if r.Attributes.GetVerb() != requests("get", "create", "delete")[i].GetVerb() {
+			t.Errorf("result %d is for verb %q", i, r.Attributes.GetVerb())
+		}
+	}
+}
+
+func TestReplayCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if results := Replay(ctx, verbAuthorizer("get"), requests("get")); len(results) != 0 {
+		t.Errorf("expected no results after cancellation, got %+v", results)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	current := union.New(union.Named("readers", verbAuthorizer("get", "list")))
+	proposed := union.New(union.Named("readers", verbAuthorizer("get")), union.Named("writers", verbAuthorizer("create")))
+
+	differences := Compare(context.Background(), current, proposed, requests("get", "list", "create", "delete", "fail"))
+
+	expected := []struct {
+		verb              string
+		current, proposed authorizer.Decision
+	}{
+		{"list", authorizer.DecisionAllow, authorizer.DecisionNoOpinion},
+		{"create", authorizer.DecisionNoOpinion, authorizer.DecisionAllow},
+	}
+	if len(differences) != len(expected) {
+		t.Fatalf("expected %d differences, got %+v", len(expected), differences)
+	}
+	for i, e := range expected {
+		d := differences[i]
+		if d.Current.Attributes.GetVerb() != e.verb || d.Current.Decision != e.current || d.Proposed.Decision != e.proposed {
+			t.Errorf("difference %d: expected %s %v -> %v, got %+v", i, e.verb, e.current, e.proposed, d)
+		}
+	}
+	if name := differences[1].Proposed.DecidingAuthorizer.Name; name != "writers" {
+		t.Errorf("expected create to be decided by writers, got %q", name)
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+	failing := authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		return authorizer.DecisionNoOpinion, "", errors.New("unavailable")
+	})
+
+	differences := Compare(context.Background(), verbAuthorizer(), failing, requests("delete"))
+	if len(differences) != 1 || differences[0].Current.Err != nil || differences[0].Proposed.Err == nil {
+		t.Errorf("expected a difference in errors, got %+v", differences)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package union
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// DecidingAuthorizer describes the member of an authorizer union that made its decision.
+type DecidingAuthorizer struct {
+	// Name is the name the member was given with Named, or "" if it is not named.
+	Name string
+	// Index is the position of the member in the union, starting at 0.
+	Index int
+	// Latency is the time the member took to make the decision.
+	Latency time.Duration
+}
+
+type decidingAuthorizerKeyType int
+
+const decidingAuthorizerKey decidingAuthorizerKeyType = iota
+
+// decidingAuthorizerRecord holds the deciding authorizer of a request once it is known.
+type decidingAuthorizerRecord struct {
+	decider  DecidingAuthorizer
+	recorded bool
+}
+
+// WithDecidingAuthorizer returns a copy of parent in which authorizer unions record the member
+// that made their decision, to be retrieved with DecidingAuthorizerFrom once authorization is done.
+func WithDecidingAuthorizer(parent context.Context) context.Context {
+	return context.WithValue(parent, decidingAuthorizerKey, &decidingAuthorizerRecord{})
+}
+
+// DecidingAuthorizerFrom returns the member of an authorizer union that made the decision on the
+// request of ctx. If unions are nested, the member of the innermost union that decided is returned.
+// It returns false if no union made a decision, or ctx was not set up with WithDecidingAuthorizer.
+func DecidingAuthorizerFrom(ctx context.Context) (DecidingAuthorizer, bool) {
+	record, ok := ctx.Value(decidingAuthorizerKey).(*decidingAuthorizerRecord)
+	if !ok || !record.recorded {
+		return DecidingAuthorizer{}, false
+	}
+	return record.decider, true
+}
+
+// recordDecision records the member of a union that made an allow or deny decision in ctx, and as an
+// event of the span of ctx. An inner union records before the union containing it, so the first
+// record of a request is kept.
+func recordDecision(ctx context.Context, decider DecidingAuthorizer, decision authorizer.Decision) {
+	label := decisionLabel(decision)
+	trace.SpanFromContext(ctx).AddEvent("Authorization decision", trace.WithAttributes(
+		authorizerAttributeKey.String(decider.Name),
+		authorizerIndexAttributeKey.Int(decider.Index),
+		decisionAttributeKey.String(label),
+	))
+
+	if record, ok := ctx.Value(decidingAuthorizerKey).(*decidingAuthorizerRecord); ok && !record.recorded {
+		record.decider = decider
+		record.recorded = true
+	}
+}
+
+// recordEvaluation records the evaluation of a request by the member of a union named name in the
+// metrics, unless ctx is a dry run.
+func recordEvaluation(ctx context.Context, name string, decision authorizer.Decision, latency time.Duration, err error) {
+	if authorizer.IsDryRun(ctx) {
+		return
+	}
+	labels := []string{authorizerLabel(name), decisionLabel(decision), strconv.FormatBool(err != nil)}
+	decisions.WithContext(ctx).WithLabelValues(labels...).Inc()
+	decisionLatency.WithContext(ctx).WithLabelValues(labels...).Observe(latency.Seconds())
+}
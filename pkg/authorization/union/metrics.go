@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package union
+
+import (
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	allowLabel     = "allow"
+	denyLabel      = "deny"
+	noOpinionLabel = "no-opinion"
+
+	// unnamedLabel is the authorizer label of members of a union that are not named.
+	unnamedLabel = "unnamed"
+)
+
+var (
+	decisions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      "apiserver",
+			Subsystem:      "authorization",
+			Name:           "union_decisions_total",
+			Help:           "Number of requests evaluated by each member of the authorizer union, partitioned by decision, including no-opinion, and whether an error was returned.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"authorizer", "decision", "error"},
+	)
+
+	decisionLatency = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      "apiserver",
+			Subsystem:      "authorization",
+			Name:           "union_decision_duration_seconds",
+			Help:           "Latency in seconds of the evaluation of requests by each member of the authorizer union, partitioned by decision, including no-opinion, and whether an error was returned.",
+			Buckets:        []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"authorizer", "decision", "error"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(decisions, decisionLatency)
+}
+
+// decisionLabel returns the decision label of a decision.
+func decisionLabel(decision authorizer.Decision) string {
+	switch decision {
+	case authorizer.DecisionAllow:
+		return allowLabel
+	case authorizer.DecisionNoOpinion:
+		return noOpinionLabel
+	default:
+		return denyLabel
+	}
+}
+
+// authorizerLabel returns the authorizer label of a member of a union named name.
+func authorizerLabel(name string) string {
+	if len(name) == 0 {
+		return unnamedLabel
+	}
+	return name
+}
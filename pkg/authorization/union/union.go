@@ -27,15 +27,44 @@ package union
 import (
 	"context"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 )
 
+const (
+	authorizerAttributeKey      = attribute.Key("authorization.authorizer")
+	authorizerIndexAttributeKey = attribute.Key("authorization.authorizer_index")
+	decisionAttributeKey        = attribute.Key("authorization.decision")
+)
+
 // unionAuthzHandler authorizer against a chain of authorizer.Authorizer
 type unionAuthzHandler []authorizer.Authorizer
 
+// namedAuthorizer is a member of an authorizer union that is reported under a name.
+type namedAuthorizer struct {
+	name string
+	authorizer.Authorizer
+}
+
+// Named returns an authorizer that is reported under name when it makes the decision of a
+// union, in the audit annotations, metrics and trace events of the request.
+func Named(name string, a authorizer.Authorizer) authorizer.Authorizer {
+	return &namedAuthorizer{name: name, Authorizer: a}
+}
+
+// nameOf returns the name of a member of an authorizer union, or "" if it is not named.
+func nameOf(a authorizer.Authorizer) string {
+	if n, ok := a.(*namedAuthorizer); ok {
+		return n.name
+	}
+	return ""
+}
+
 // New returns an authorizer that authorizes against a chain of authorizer.Authorizer objects
 func New(authorizationHandlers ...authorizer.Authorizer) authorizer.Authorizer {
 	return unionAuthzHandler(authorizationHandlers)
@@ -48,8 +77,11 @@ func (authzHandler unionAuthzHandler) Authorize(ctx context.Context, a authorize
 		reasonlist []string
 	)
 
-	for _, currAuthzHandler := range authzHandler {
+	for i, currAuthzHandler := range authzHandler {
+		start := time.Now()
 		decision, reason, err := currAuthzHandler.Authorize(ctx, a)
+		latency := time.Since(start)
+		recordEvaluation(ctx, nameOf(currAuthzHandler), decision, latency, err)
 
 		if err != nil {
 			errlist = append(errlist, err)
@@ -59,6 +91,7 @@ func (authzHandler unionAuthzHandler) Authorize(ctx context.Context, a authorize
 		}
 		switch decision {
 		case authorizer.DecisionAllow, authorizer.DecisionDeny:
+			recordDecision(ctx, DecidingAuthorizer{Name: nameOf(currAuthzHandler), Index: i, Latency: latency}, decision)
 			return decision, reason, err
 		case authorizer.DecisionNoOpinion:
 			// continue to the next authorizer
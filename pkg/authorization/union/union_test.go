@@ -21,10 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
 )
 
 type mockAuthzHandler struct {
@@ -265,3 +268,102 @@ func TestAuthorizationUnequivocalDeny(t *testing.T) {
 		})
 	}
 }
+
+func TestDecidingAuthorizer(t *testing.T) {
+	noOpinion := &mockAuthzHandler{decision: authorizer.DecisionNoOpinion}
+	allow := &mockAuthzHandler{decision: authorizer.DecisionAllow}
+	deny := &mockAuthzHandler{decision: authorizer.DecisionDeny}
+
+	tests := []struct {
+		name       string
+		authorizer authorizer.Authorizer
+		expected   *DecidingAuthorizer
+	}{
+		{
+			name:       "named allow",
+			authorizer: New(Named("first", noOpinion), Named("second", allow), Named("third", deny)),
+			expected:   &DecidingAuthorizer{Name: "second", Index: 1},
+		},
+		{
+			name:       "unnamed deny",
+			authorizer: New(noOpinion, noOpinion, deny),
+			expected:   &DecidingAuthorizer{Index: 2},
+		},
+		{
+			name:       "no opinion",
+			authorizer: New(Named("first", noOpinion), noOpinion),
+		},
+		{
+			name:       "innermost union",
+			authorizer: New(Named("outer", noOpinion), New(Named("inner-first", noOpinion), Named("inner-second", deny))),
+			expected:   &DecidingAuthorizer{Name: "inner-second", Index: 1},
+		},
+		{
+			name:       "outer union",
+			authorizer: New(New(Named("inner", noOpinion)), Named("outer", allow)),
+			expected:   &DecidingAuthorizer{Name: "outer", Index: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithDecidingAuthorizer(context.Background())
+			if _, _, err := tt.authorizer.Authorize(ctx, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			decider, ok := DecidingAuthorizerFrom(ctx)
+			if tt.expected == nil {
+				if ok {
+					t.Fatalf("expected no deciding authorizer, got %#v", decider)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("expected deciding authorizer %#v, got none", *tt.expected)
+			}
+			decider.Latency = 0
+			if decider != *tt.expected {
+				t.Errorf("expected deciding authorizer %#v, got %#v", *tt.expected, decider)
+			}
+		})
+	}
+}
+
+func TestDecidingAuthorizerWithoutRecorder(t *testing.T) {
+	authzHandler := New(Named("allow", &mockAuthzHandler{decision: authorizer.DecisionAllow}))
+
+	ctx := context.Background()
+	if decision, _, _ := authzHandler.Authorize(ctx, nil); decision != authorizer.DecisionAllow {
+		t.Fatalf("expected allow, got %v", decision)
+	}
+	if decider, ok := DecidingAuthorizerFrom(ctx); ok {
+		t.Errorf("expected no deciding authorizer without a recorder, got %#v", decider)
+	}
+}
+
+func TestEvaluationMetrics(t *testing.T) {
+	decisions.Reset()
+	authzHandler := New(
+		Named("first", &mockAuthzHandler{decision: authorizer.DecisionNoOpinion, err: errors.New("foo")}),
+		&mockAuthzHandler{decision: authorizer.DecisionNoOpinion},
+		Named("third", &mockAuthzHandler{decision: authorizer.DecisionAllow}),
+	)
+
+	if decision, _, _ := authzHandler.Authorize(context.Background(), nil); decision != authorizer.DecisionAllow {
+		t.Fatalf("expected allow, got %v", decision)
+	}
+	// dry runs are not recorded
+	authzHandler.Authorize(authorizer.WithDryRun(context.Background()), nil)
+
+	expected := `
+# HELP apiserver_authorization_union_decisions_total [ALPHA] Number of requests evaluated by each member of the authorizer union, partitioned by decision, including no-opinion, and whether an error was returned.
+# TYPE apiserver_authorization_union_decisions_total counter
+apiserver_authorization_union_decisions_total{authorizer="first",decision="no-opinion",error="true"} 1
+apiserver_authorization_union_decisions_total{authorizer="third",decision="allow",error="false"} 1
+apiserver_authorization_union_decisions_total{authorizer="unnamed",decision="no-opinion",error="false"} 1
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "apiserver_authorization_union_decisions_total"); err != nil {
+		t.Error(err)
+	}
+}
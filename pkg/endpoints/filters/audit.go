@@ -24,9 +24,11 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/apiserver/pkg/audit"
@@ -60,10 +62,15 @@ func WithAudit(handler http.Handler, sink audit.Sink, policy audit.PolicyRuleEva
 		req = req.WithContext(audit.WithAuditContext(req.Context(), auditContext))
 
 		ctx := req.Context()
-		omitStages := auditContext.RequestAuditConfig.OmitStages
 
 		ev.Stage = auditinternal.StageRequestReceived
-		if processed := processAuditEvent(ctx, sink, ev, omitStages); !processed {
+		// The level of a provisional audit configuration is the highest level the
+		// request may be audited at, so the RequestReceived stage is held back until
+		// the configuration is final.
+		var requestReceived *auditinternal.Event
+		if auditContext.RequestAuditConfig.EvaluateResponse != nil || auditContext.EvaluateObject != nil {
+			requestReceived = ev.DeepCopy()
+		} else if processed := processAuditEvent(ctx, sink, ev); !processed {
 			audit.ApiserverAuditDroppedCounter.WithContext(ctx).Inc()
 			responsewriters.InternalError(w, req, errors.New("failed to store audit event"))
 			return
@@ -77,7 +84,10 @@ func WithAudit(handler http.Handler, sink audit.Sink, policy audit.PolicyRuleEva
 				longRunningSink = sink
 			}
 		}
-		respWriter := decorateResponseWriter(ctx, w, ev, longRunningSink, omitStages)
+		auditWriter := newAuditResponseWriter(ctx, w, ev, longRunningSink)
+		auditWriter.requestReceived = requestReceived
+		auditWriter.requestReceivedSink = sink
+		respWriter := responsewriter.WrapForHTTP1Or2(auditWriter)
 
 		// send audit event when we leave this func, either via a panic or cleanly. In the case of long
 		// running requests, this will be the second audit event.
@@ -91,7 +101,9 @@ func WithAudit(handler http.Handler, sink audit.Sink, policy audit.PolicyRuleEva
 					Reason:  metav1.StatusReasonInternalError,
 					Message: fmt.Sprintf("APIServer panic'd: %v", r),
 				}
-				processAuditEvent(ctx, sink, ev, omitStages)
+				audit.EvaluateResponsePolicy(ctx, ev.ResponseStatus.Code)
+				auditWriter.processRequestReceived()
+				processAuditEvent(ctx, sink, ev)
 				return
 			}
 
@@ -105,14 +117,20 @@ func WithAudit(handler http.Handler, sink audit.Sink, policy audit.PolicyRuleEva
 			if ev.ResponseStatus == nil && longRunningSink != nil {
 				ev.ResponseStatus = fakedSuccessStatus
 				ev.Stage = auditinternal.StageResponseStarted
-				processAuditEvent(ctx, longRunningSink, ev, omitStages)
+				audit.EvaluateResponsePolicy(ctx, ev.ResponseStatus.Code)
+				auditWriter.processRequestReceived()
+				processAuditEvent(ctx, longRunningSink, ev)
 			}
 
 			ev.Stage = auditinternal.StageResponseComplete
 			if ev.ResponseStatus == nil {
 				ev.ResponseStatus = fakedSuccessStatus
 			}
-			processAuditEvent(ctx, sink, ev, omitStages)
+			responseSize := auditWriter.bytesWritten()
+			ev.ResponseSize = &responseSize
+			audit.EvaluateResponsePolicy(ctx, ev.ResponseStatus.Code)
+			auditWriter.processRequestReceived()
+			processAuditEvent(ctx, sink, ev)
 		}()
 		handler.ServeHTTP(respWriter, req)
 	})
@@ -130,7 +148,8 @@ func evaluatePolicyAndCreateAuditEvent(req *http.Request, policy audit.PolicyRul
 		return nil, fmt.Errorf("failed to GetAuthorizerAttributes: %v", err)
 	}
 
-	ls := policy.EvaluatePolicyRule(attribs)
+	requestAttribs := audit.NewRequestAttributes(req, attribs)
+	ls := policy.EvaluatePolicyRule(requestAttribs)
 	audit.ObservePolicyLevel(ctx, ls.Level)
 	if ls.Level == auditinternal.LevelNone {
 		// Don't audit.
@@ -147,11 +166,18 @@ func evaluatePolicyAndCreateAuditEvent(req *http.Request, policy audit.PolicyRul
 	if err != nil {
 		return nil, fmt.Errorf("failed to complete audit event from request: %v", err)
 	}
+	audit.LogMatchedPolicyRule(ev, ls.MatchedRule)
 
-	return &audit.AuditContext{
+	auditContext := &audit.AuditContext{
 		RequestAuditConfig: ls.RequestAuditConfig,
 		Event:              ev,
-	}, nil
+	}
+	if objectPolicy, ok := policy.(audit.ObjectPolicyRuleEvaluator); ok && ls.DependsOnObject {
+		auditContext.EvaluateObject = func(obj runtime.Object) audit.RequestAuditConfigWithLevel {
+			return objectPolicy.EvaluatePolicyRuleForObject(requestAttribs, obj)
+		}
+	}
+	return auditContext, nil
 }
 
 // writeLatencyToAnnotation writes the latency incurred in different
@@ -181,10 +207,20 @@ func writeLatencyToAnnotation(ctx context.Context, ev *auditinternal.Event) {
 	audit.AddAuditAnnotationsMap(ctx, layerLatencies)
 }
 
-func processAuditEvent(ctx context.Context, sink audit.Sink, ev *auditinternal.Event, omitStages []auditinternal.Stage) bool {
-	for _, stage := range omitStages {
-		if ev.Stage == stage {
-			return true
+// processAuditEvent sends the event to the sink, unless the audit configuration of the
+// request omits its stage. The configuration is the one in effect when the stage is
+// processed, which may have been re-evaluated since the request was received.
+func processAuditEvent(ctx context.Context, sink audit.Sink, ev *auditinternal.Event) bool {
+	if ev.Level == auditinternal.LevelNone {
+		// The level was lowered after evaluating the request object.
+		return true
+	}
+	ac := audit.AuditContextFrom(ctx)
+	if ac != nil {
+		for _, stage := range ac.RequestAuditConfig.OmitStages {
+			if ev.Stage == stage {
+				return true
+			}
 		}
 	}
 
@@ -193,25 +229,32 @@ func processAuditEvent(ctx context.Context, sink audit.Sink, ev *auditinternal.E
 		ev.StageTimestamp = metav1.NewMicroTime(ev.RequestReceivedTimestamp.Time)
 	case ev.Stage == auditinternal.StageResponseComplete:
 		ev.StageTimestamp = metav1.NewMicroTime(time.Now())
+		ev.Latency = &metav1.Duration{Duration: ev.StageTimestamp.Sub(ev.RequestReceivedTimestamp.Time)}
 		writeLatencyToAnnotation(ctx, ev)
 	default:
 		ev.StageTimestamp = metav1.NewMicroTime(time.Now())
 	}
 
 	audit.ObserveEvent(ctx)
+	if ac != nil && len(ac.RequestAuditConfig.Backends) > 0 {
+		if routingSink, ok := sink.(audit.RoutingSink); ok {
+			return routingSink.ProcessEventsForBackends(ac.RequestAuditConfig.Backends, ev)
+		}
+	}
 	return sink.ProcessEvents(ev)
 }
 
-func decorateResponseWriter(ctx context.Context, responseWriter http.ResponseWriter, ev *auditinternal.Event, sink audit.Sink, omitStages []auditinternal.Stage) http.ResponseWriter {
-	delegate := &auditResponseWriter{
+func decorateResponseWriter(ctx context.Context, responseWriter http.ResponseWriter, ev *auditinternal.Event, sink audit.Sink) http.ResponseWriter {
+	return responsewriter.WrapForHTTP1Or2(newAuditResponseWriter(ctx, responseWriter, ev, sink))
+}
+
+func newAuditResponseWriter(ctx context.Context, responseWriter http.ResponseWriter, ev *auditinternal.Event, sink audit.Sink) *auditResponseWriter {
+	return &auditResponseWriter{
 		ctx:            ctx,
 		ResponseWriter: responseWriter,
 		event:          ev,
 		sink:           sink,
-		omitStages:     omitStages,
 	}
-
-	return responsewriter.WrapForHTTP1Or2(delegate)
 }
 
 var _ http.ResponseWriter = &auditResponseWriter{}
@@ -221,11 +264,19 @@ var _ responsewriter.UserProvidedDecorator = &auditResponseWriter{}
 // create immediately an event (for long running requests).
 type auditResponseWriter struct {
 	http.ResponseWriter
-	ctx        context.Context
-	event      *auditinternal.Event
-	once       sync.Once
-	sink       audit.Sink
-	omitStages []auditinternal.Stage
+	ctx   context.Context
+	event *auditinternal.Event
+	once  sync.Once
+	sink  audit.Sink
+	// written is the number of bytes of the response body written, accessed atomically.
+	written int64
+
+	// requestReceived is the RequestReceived stage of the event, if it is held back
+	// until the audit configuration of the request is final. It is processed by
+	// requestReceivedSink.
+	requestReceived     *auditinternal.Event
+	requestReceivedSink audit.Sink
+	requestReceivedOnce sync.Once
 }
 
 func (a *auditResponseWriter) Unwrap() http.ResponseWriter {
@@ -239,9 +290,32 @@ func (a *auditResponseWriter) processCode(code int) {
 		}
 		a.event.ResponseStatus.Code = int32(code)
 		a.event.Stage = auditinternal.StageResponseStarted
+		audit.EvaluateResponsePolicy(a.ctx, a.event.ResponseStatus.Code)
+		a.processRequestReceived()
 
 		if a.sink != nil {
-			processAuditEvent(a.ctx, a.sink, a.event, a.omitStages)
+			processAuditEvent(a.ctx, a.sink, a.event)
+		}
+	})
+}
+
+// processRequestReceived processes the held back RequestReceived stage of the event
+// at the final level and matched rule of the audit configuration. It must be called
+// once the configuration is final, before any later stage of the event is processed.
+func (a *auditResponseWriter) processRequestReceived() {
+	a.requestReceivedOnce.Do(func() {
+		if a.requestReceived == nil {
+			return
+		}
+		ev := a.requestReceived
+		ev.Level = a.event.Level
+		if rule, ok := a.event.Annotations[audit.PolicyRuleAnnotationKey]; ok {
+			audit.LogMatchedPolicyRule(ev, &audit.PolicyRuleRef{Name: rule})
+		} else {
+			audit.LogMatchedPolicyRule(ev, nil)
+		}
+		if processed := processAuditEvent(a.ctx, a.requestReceivedSink, ev); !processed {
+			audit.ApiserverAuditDroppedCounter.WithContext(a.ctx).Inc()
 		}
 	})
 }
@@ -249,7 +323,13 @@ func (a *auditResponseWriter) processCode(code int) {
 func (a *auditResponseWriter) Write(bs []byte) (int, error) {
 	// the Go library calls WriteHeader internally if no code was written yet. But this will go unnoticed for us
 	a.processCode(http.StatusOK)
-	return a.ResponseWriter.Write(bs)
+	n, err := a.ResponseWriter.Write(bs)
+	atomic.AddInt64(&a.written, int64(n))
+	return n, err
+}
+
+func (a *auditResponseWriter) bytesWritten() int64 {
+	return atomic.LoadInt64(&a.written)
 }
 
 func (a *auditResponseWriter) WriteHeader(code int) {
@@ -76,7 +76,7 @@ func (s *fakeAuditSink) Pop(timeout time.Duration) (*auditinternal.Event, error)
 
 func TestConstructResponseWriter(t *testing.T) {
 	inner := &responsewriter.FakeResponseWriter{}
-	actual := decorateResponseWriter(context.Background(), inner, nil, nil, nil)
+	actual := decorateResponseWriter(context.Background(), inner, nil, nil)
 	switch v := actual.(type) {
 	case *auditResponseWriter:
 	default:
@@ -86,7 +86,7 @@ func TestConstructResponseWriter(t *testing.T) {
 		t.Errorf("Expected the decorator to return the inner http.ResponseWriter object")
 	}
 
-	actual = decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriterFlusherCloseNotifier{}, nil, nil, nil)
+	actual = decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriterFlusherCloseNotifier{}, nil, nil)
 	//lint:file-ignore SA1019 Keep supporting deprecated http.CloseNotifier
 	if _, ok := actual.(http.CloseNotifier); !ok {
 		t.Errorf("Expected http.ResponseWriter to implement http.CloseNotifier")
@@ -98,7 +98,7 @@ func TestConstructResponseWriter(t *testing.T) {
 		t.Errorf("Expected http.ResponseWriter not to implement http.Hijacker")
 	}
 
-	actual = decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriterFlusherCloseNotifierHijacker{}, nil, nil, nil)
+	actual = decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriterFlusherCloseNotifierHijacker{}, nil, nil)
 	//lint:file-ignore SA1019 Keep supporting deprecated http.CloseNotifier
 	if _, ok := actual.(http.CloseNotifier); !ok {
 		t.Errorf("Expected http.ResponseWriter to implement http.CloseNotifier")
@@ -113,7 +113,7 @@ func TestConstructResponseWriter(t *testing.T) {
 
 func TestDecorateResponseWriterWithoutChannel(t *testing.T) {
 	ev := &auditinternal.Event{}
-	actual := decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriter{}, ev, nil, nil)
+	actual := decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriter{}, ev, nil)
 
 	// write status. This will not block because firstEventSentCh is nil
 	actual.WriteHeader(42)
@@ -127,7 +127,7 @@ func TestDecorateResponseWriterWithoutChannel(t *testing.T) {
 
 func TestDecorateResponseWriterWithImplicitWrite(t *testing.T) {
 	ev := &auditinternal.Event{}
-	actual := decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriter{}, ev, nil, nil)
+	actual := decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriter{}, ev, nil)
 
 	// write status. This will not block because firstEventSentCh is nil
 	actual.Write([]byte("foo"))
@@ -142,7 +142,7 @@ func TestDecorateResponseWriterWithImplicitWrite(t *testing.T) {
 func TestDecorateResponseWriterChannel(t *testing.T) {
 	sink := &fakeAuditSink{}
 	ev := &auditinternal.Event{}
-	actual := decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriter{}, ev, sink, nil)
+	actual := decorateResponseWriter(context.Background(), &responsewriter.FakeResponseWriter{}, ev, sink)
 
 	done := make(chan struct{})
 	go func() {
@@ -857,3 +857,123 @@ func withTestContext(req *http.Request, user user.Info, ae *auditinternal.Event)
 	}
 	return req.WithContext(ctx)
 }
+
+func TestAuditResponseCodes(t *testing.T) {
+	ruleEvaluator := policy.NewPolicyRuleEvaluator(&auditinternal.Policy{Rules: []auditinternal.PolicyRule{
+		{Level: auditinternal.LevelRequestResponse, ResponseCodes: []string{"403", "5xx"}},
+		{Level: auditinternal.LevelNone, ResponseCodes: []string{"404"}},
+		{Level: auditinternal.LevelMetadata},
+	}})
+
+	for _, test := range []struct {
+		code          int
+		expectedLevel auditinternal.Level
+	}{
+		{http.StatusOK, auditinternal.LevelMetadata},
+		{http.StatusNotFound, auditinternal.LevelNone},
+		{http.StatusForbidden, auditinternal.LevelRequestResponse},
+		{http.StatusServiceUnavailable, auditinternal.LevelRequestResponse},
+	} {
+		t.Run(http.StatusText(test.code), func(t *testing.T) {
+			sink := &fakeAuditSink{}
+			handler := WithAudit(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(test.code)
+			}), sink, ruleEvaluator, nil)
+
+			req, _ := http.NewRequest("GET", "/api/v1/namespaces/default/pods", nil)
+			req.RemoteAddr = "127.0.0.1"
+			req = withTestContext(req, &user.DefaultInfo{Name: "admin"}, nil)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			// the RequestReceived stage is held back until the level is final, rather
+			// than sent at the highest level the request may be audited at.
+			events := sink.Events()
+			if test.expectedLevel == auditinternal.LevelNone {
+				if len(events) != 0 {
+					t.Fatalf("expected no events, got %d", len(events))
+				}
+				return
+			}
+			if len(events) != 2 {
+				t.Fatalf("expected 2 events, got %d", len(events))
+			}
+			for i, stage := range []auditinternal.Stage{auditinternal.StageRequestReceived, auditinternal.StageResponseComplete} {
+				if events[i].Stage != stage || events[i].Level != test.expectedLevel {
+					t.Errorf("expected level %s for stage %s, got %s for stage %s", test.expectedLevel, stage, events[i].Level, events[i].Stage)
+				}
+			}
+			if events[0].StageTimestamp != events[0].RequestReceivedTimestamp {
+				t.Errorf("expected the stage timestamp of stage %s to be the time the request was received", events[0].Stage)
+			}
+		})
+	}
+}
+
+func TestAuditResponseCodesOmitStages(t *testing.T) {
+	ruleEvaluator := policy.NewPolicyRuleEvaluator(&auditinternal.Policy{Rules: []auditinternal.PolicyRule{
+		{Level: auditinternal.LevelRequestResponse, ResponseCodes: []string{"403"}},
+		{Level: auditinternal.LevelMetadata, OmitStages: []auditinternal.Stage{auditinternal.StageResponseComplete}},
+	}})
+
+	for _, test := range []struct {
+		code           int
+		expectedStages []auditinternal.Stage
+	}{
+		// the stages to omit are those of the rule matching the response code.
+		{http.StatusOK, []auditinternal.Stage{auditinternal.StageRequestReceived}},
+		{http.StatusForbidden, []auditinternal.Stage{auditinternal.StageRequestReceived, auditinternal.StageResponseComplete}},
+	} {
+		t.Run(http.StatusText(test.code), func(t *testing.T) {
+			sink := &fakeAuditSink{}
+			handler := WithAudit(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(test.code)
+			}), sink, ruleEvaluator, nil)
+
+			req, _ := http.NewRequest("GET", "/api/v1/namespaces/default/pods", nil)
+			req.RemoteAddr = "127.0.0.1"
+			req = withTestContext(req, &user.DefaultInfo{Name: "admin"}, nil)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			var stages []auditinternal.Stage
+			for _, ev := range sink.Events() {
+				stages = append(stages, ev.Stage)
+			}
+			if !reflect.DeepEqual(stages, test.expectedStages) {
+				t.Errorf("expected stages %v, got %v", test.expectedStages, stages)
+			}
+		})
+	}
+}
+
+func TestAuditResponseComplete(t *testing.T) {
+	sink := &fakeAuditSink{}
+	handler := WithAudit(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		audit.LogFlowControl(req.Context(), "workload-low", "global-default")
+		w.Write([]byte("hello"))
+		w.Write([]byte(" world"))
+	}), sink, policy.NewFakePolicyRuleEvaluator(auditinternal.LevelMetadata, nil), nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/namespaces/default/pods", nil)
+	req.RemoteAddr = "127.0.0.1"
+	req = withTestContext(req, &user.DefaultInfo{Name: "admin"}, nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	events := sink.Events()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Latency != nil || events[0].ResponseSize != nil {
+		t.Errorf("expected no latency and response size at stage %s", events[0].Stage)
+	}
+	ev := events[1]
+	if ev.Latency == nil || ev.Latency.Duration != ev.StageTimestamp.Sub(ev.RequestReceivedTimestamp.Time) {
+		t.Errorf("expected the latency of the request, got %v", ev.Latency)
+	}
+	if ev.ResponseSize == nil || *ev.ResponseSize != int64(len("hello world")) {
+		t.Errorf("expected response size %d, got %v", len("hello world"), ev.ResponseSize)
+	}
+	expectedFlowControl := &auditinternal.FlowControl{FlowSchema: "workload-low", PriorityLevel: "global-default"}
+	if !reflect.DeepEqual(ev.FlowControl, expectedFlowControl) {
+		t.Errorf("expected flow control %v, got %v", expectedFlowControl, ev.FlowControl)
+	}
+}
@@ -24,14 +24,26 @@ import (
 	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/klog/v2"
 )
 
+// failureReasonAnnotationKey is the audit annotation key recording why the credentials of a request were rejected.
+const failureReasonAnnotationKey = "authentication.k8s.io/failure-reason"
+
+type failureReasonKeyType int
+
+const (
+	failureReasonKey failureReasonKeyType = iota
+	throttledRetryAfterKey
+)
+
 type recordMetrics func(context.Context, *authenticator.Response, bool, error, authenticator.Audiences, time.Time, time.Time)
 
 // WithAuthentication creates an http handler that tries to authenticate the given request as a user, and then
@@ -61,6 +73,12 @@ func withAuthentication(handler http.Handler, auth authenticator.Request, failed
 		if err != nil || !ok {
 			if err != nil {
 				klog.ErrorS(err, "Unable to authenticate the request")
+				if retryAfter, ok := authenticator.ThrottledRetryAfter(err); ok {
+					req = req.WithContext(withThrottledRetryAfter(req.Context(), retryAfter))
+				}
+				if reason, ok := authenticator.FailureReasonFromError(err); ok {
+					req = req.WithContext(withFailureReason(req.Context(), reason))
+				}
 			}
 			failed.ServeHTTP(w, req)
 			return
@@ -69,6 +87,7 @@ func withAuthentication(handler http.Handler, auth authenticator.Request, failed
 		if !audiencesAreAcceptable(apiAuds, resp.Audiences) {
 			err = fmt.Errorf("unable to match the audience: %v , accepted: %v", resp.Audiences, apiAuds)
 			klog.Error(err)
+			req = req.WithContext(withFailureReason(req.Context(), authenticator.FailureReasonInvalidAudience))
 			failed.ServeHTTP(w, req)
 			return
 		}
@@ -81,6 +100,10 @@ func withAuthentication(handler http.Handler, auth authenticator.Request, failed
 	})
 }
 
+// Unauthorized returns an http handler that responds with a 401 status. If the authenticator gave a
+// reason for rejecting the request, it is included as a cause in the status details and recorded as
+// an audit annotation. Requests refused because of too many failed authentication attempts are
+// responded to with a 429 status instead.
 func Unauthorized(s runtime.NegotiatedSerializer) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
@@ -91,7 +114,25 @@ func Unauthorized(s runtime.NegotiatedSerializer) http.Handler {
 		}
 
 		gv := schema.GroupVersion{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion}
-		responsewriters.ErrorNegotiated(apierrors.NewUnauthorized("Unauthorized"), s, gv, w, req)
+		if retryAfter, ok := throttledRetryAfterFrom(ctx); ok {
+			// round up, so that clients do not retry before the throttling ends
+			retryAfterSeconds := int((retryAfter + time.Second - 1) / time.Second)
+			responsewriters.ErrorNegotiated(apierrors.NewTooManyRequests("too many failed authentication attempts", retryAfterSeconds), s, gv, w, req)
+			return
+		}
+
+		statusErr := apierrors.NewUnauthorized("Unauthorized")
+		if reason, ok := failureReasonFrom(ctx); ok {
+			audit.AddAuditAnnotation(ctx, failureReasonAnnotationKey, string(reason))
+			statusErr.ErrStatus.Details = &metav1.StatusDetails{
+				Causes: []metav1.StatusCause{{
+					Type:    metav1.CauseType(reason),
+					Message: failureReasonMessage(reason),
+				}},
+			}
+		}
+
+		responsewriters.ErrorNegotiated(statusErr, s, gv, w, req)
 	})
 }
 
@@ -102,3 +143,36 @@ func audiencesAreAcceptable(apiAuds, responseAudiences authenticator.Audiences)
 
 	return len(apiAuds.Intersect(responseAudiences)) > 0
 }
+
+func withFailureReason(ctx context.Context, reason authenticator.FailureReason) context.Context {
+	return context.WithValue(ctx, failureReasonKey, reason)
+}
+
+func failureReasonFrom(ctx context.Context) (authenticator.FailureReason, bool) {
+	reason, ok := ctx.Value(failureReasonKey).(authenticator.FailureReason)
+	return reason, ok
+}
+
+func withThrottledRetryAfter(ctx context.Context, retryAfter time.Duration) context.Context {
+	return context.WithValue(ctx, throttledRetryAfterKey, retryAfter)
+}
+
+func throttledRetryAfterFrom(ctx context.Context) (time.Duration, bool) {
+	retryAfter, ok := ctx.Value(throttledRetryAfterKey).(time.Duration)
+	return retryAfter, ok
+}
+
+func failureReasonMessage(reason authenticator.FailureReason) string {
+	switch reason {
+	case authenticator.FailureReasonExpired:
+		return "the presented credentials have expired"
+	case authenticator.FailureReasonInvalidAudience:
+		return "the presented credentials are not valid for this server"
+	case authenticator.FailureReasonUnknownIssuer:
+		return "the presented credentials were issued by an untrusted issuer"
+	case authenticator.FailureReasonRevoked:
+		return "the presented credentials have been revoked"
+	default:
+		return "the presented credentials were rejected"
+	}
+}
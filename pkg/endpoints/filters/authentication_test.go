@@ -18,8 +18,11 @@ package filters
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/user"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
@@ -260,3 +263,95 @@ func TestAuthenticateRequestError(t *testing.T) {
 
 	<-failed
 }
+
+func TestAuthenticateRequestFailureReason(t *testing.T) {
+	testCases := []struct {
+		name         string
+		apiAuds      authenticator.Audiences
+		resp         *authenticator.Response
+		err          error
+		expectReason authenticator.FailureReason
+	}{
+		{
+			name: "no reason",
+			err:  errors.New("failure"),
+		},
+		{
+			name:         "reason",
+			err:          authenticator.NewFailureReasonError(authenticator.FailureReasonExpired, errors.New("token is expired")),
+			expectReason: authenticator.FailureReasonExpired,
+		},
+		{
+			name:         "audience mismatch",
+			apiAuds:      authenticator.Audiences{"api"},
+			resp:         &authenticator.Response{User: &user.DefaultInfo{Name: "user"}, Audiences: authenticator.Audiences{"other"}},
+			expectReason: authenticator.FailureReasonInvalidAudience,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := WithAuthentication(
+				http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+					t.Errorf("unexpected call to handler")
+				}),
+				authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+					return tc.resp, tc.resp != nil, tc.err
+				}),
+				Unauthorized(newSerializer()),
+				tc.apiAuds,
+			)
+
+			ev := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+			req := withTestContext(httptest.NewRequest("GET", "/api/v1/namespaces", nil), nil, ev)
+			w := httptest.NewRecorder()
+			auth.ServeHTTP(w, req)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+			}
+			status := &metav1.Status{}
+			if err := json.Unmarshal(w.Body.Bytes(), status); err != nil {
+				t.Fatalf("unexpected error decoding the status: %v", err)
+			}
+			if tc.expectReason == "" {
+				if status.Details != nil {
+					t.Errorf("expected no details, got %#v", status.Details)
+				}
+				if _, ok := ev.Annotations[failureReasonAnnotationKey]; ok {
+					t.Errorf("expected no failure reason annotation, got %v", ev.Annotations)
+				}
+				return
+			}
+			if status.Details == nil || len(status.Details.Causes) != 1 || status.Details.Causes[0].Type != metav1.CauseType(tc.expectReason) {
+				t.Errorf("expected a cause of type %q, got %#v", tc.expectReason, status.Details)
+			}
+			if got := ev.Annotations[failureReasonAnnotationKey]; got != string(tc.expectReason) {
+				t.Errorf("expected failure reason annotation %q, got %q", tc.expectReason, got)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRequestThrottled(t *testing.T) {
+	auth := WithAuthentication(
+		http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			t.Errorf("unexpected call to handler")
+		}),
+		authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+			return nil, false, authenticator.NewThrottledError(1500 * time.Millisecond)
+		}),
+		Unauthorized(newSerializer()),
+		nil,
+	)
+
+	req := withTestContext(httptest.NewRequest("GET", "/api/v1/namespaces", nil), nil, &auditinternal.Event{Level: auditinternal.LevelMetadata})
+	w := httptest.NewRecorder()
+	auth.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("expected Retry-After of 2 seconds, got %q", got)
+	}
+}
@@ -19,13 +19,18 @@ package filters
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"k8s.io/klog/v2"
 
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/union"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
 	"k8s.io/apiserver/pkg/endpoints/request"
 )
@@ -35,6 +40,11 @@ const (
 	decisionAnnotationKey = "authorization.k8s.io/decision"
 	reasonAnnotationKey   = "authorization.k8s.io/reason"
 
+	// Annotation key names describing the member of an authorizer union that made the decision
+	authorizerAnnotationKey        = "authorization.k8s.io/authorizer"
+	authorizerIndexAnnotationKey   = "authorization.k8s.io/authorizer-index"
+	authorizerLatencyAnnotationKey = "authorization.k8s.io/authorizer-latency"
+
 	// Annotation values set in advanced audit
 	decisionAllow  = "allow"
 	decisionForbid = "forbid"
@@ -42,7 +52,8 @@ const (
 )
 
 // WithAuthorizationCheck passes all authorized requests on to handler, and returns a forbidden error otherwise.
-func WithAuthorization(handler http.Handler, a authorizer.Authorizer, s runtime.NegotiatedSerializer) http.Handler {
+// The mutators add computed attributes to the attributes of each request before it is authorized.
+func WithAuthorization(handler http.Handler, a authorizer.Authorizer, s runtime.NegotiatedSerializer, mutators ...authorizer.AttributesMutator) http.Handler {
 	if a == nil {
 		klog.Warning("Authorization is disabled")
 		return handler
@@ -55,7 +66,15 @@ func WithAuthorization(handler http.Handler, a authorizer.Authorizer, s runtime.
 			responsewriters.InternalError(w, req, err)
 			return
 		}
-		authorized, reason, err := a.Authorize(ctx, attributes)
+		if err := mutateAttributes(req, attributes, mutators); err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+		authorizeCtx := union.WithDecidingAuthorizer(request.WithRemoteIP(ctx, request.RemoteIP(req.RemoteAddr)))
+		authorized, reason, err := a.Authorize(authorizeCtx, attributes)
+		if decider, ok := union.DecidingAuthorizerFrom(authorizeCtx); ok {
+			addDecidingAuthorizerAnnotations(ctx, decider)
+		}
 		// an authorizer like RBAC could encounter evaluation errors and still allow the request, so authorizer decision is checked before error here.
 		if authorized == authorizer.DecisionAllow {
 			audit.AddAuditAnnotations(ctx,
@@ -78,6 +97,52 @@ func WithAuthorization(handler http.Handler, a authorizer.Authorizer, s runtime.
 	})
 }
 
+// mutateAttributes runs the mutators on the attributes of req, as returned by GetAuthorizerAttributes.
+func mutateAttributes(req *http.Request, attributes authorizer.Attributes, mutators []authorizer.AttributesMutator) error {
+	if len(mutators) == 0 {
+		return nil
+	}
+	record, ok := attributes.(*authorizer.AttributesRecord)
+	if !ok {
+		return fmt.Errorf("unexpected type %T of authorization attributes", attributes)
+	}
+	for _, mutator := range mutators {
+		if err := mutator.MutateAttributes(req, record); err != nil {
+			return fmt.Errorf("failed to compute authorization attributes: %v", err)
+		}
+	}
+	return nil
+}
+
+// SelectorsAndOriginMutator computes the field and label selectors of a request, as given in its
+// query, and its origin, the IP address of the peer it was received from.
+var SelectorsAndOriginMutator authorizer.AttributesMutator = authorizer.AttributesMutatorFunc(func(req *http.Request, attrs *authorizer.AttributesRecord) error {
+	query := req.URL.Query()
+	for key, param := range map[string]string{
+		authorizer.ComputedAttributeFieldSelector: "fieldSelector",
+		authorizer.ComputedAttributeLabelSelector: "labelSelector",
+	} {
+		if selector := query.Get(param); len(selector) > 0 {
+			attrs.SetComputedAttribute(key, selector)
+		}
+	}
+	if ip := request.RemoteIP(req.RemoteAddr); ip != nil {
+		attrs.SetComputedAttribute(authorizer.ComputedAttributeOrigin, ip.String())
+	}
+	return nil
+})
+
+// addDecidingAuthorizerAnnotations records the member of an authorizer union that made the
+// decision on a request in its audit annotations.
+func addDecidingAuthorizerAnnotations(ctx context.Context, decider union.DecidingAuthorizer) {
+	if len(decider.Name) > 0 {
+		audit.AddAuditAnnotation(ctx, authorizerAnnotationKey, decider.Name)
+	}
+	audit.AddAuditAnnotations(ctx,
+		authorizerIndexAnnotationKey, strconv.Itoa(decider.Index),
+		authorizerLatencyAnnotationKey, decider.Latency.String())
+}
+
 func GetAuthorizerAttributes(ctx context.Context) (authorizer.Attributes, error) {
 	attribs := authorizer.AttributesRecord{}
 
@@ -103,5 +168,20 @@ func GetAuthorizerAttributes(ctx context.Context) (authorizer.Attributes, error)
 	attribs.Namespace = requestInfo.Namespace
 	attribs.Name = requestInfo.Name
 
+	if len(requestInfo.FieldSelector) > 0 {
+		if selector, err := fields.ParseSelector(requestInfo.FieldSelector); err != nil {
+			attribs.FieldSelectorParsingErr = err
+		} else {
+			attribs.FieldSelectorRequirements = selector.Requirements()
+		}
+	}
+	if len(requestInfo.LabelSelector) > 0 {
+		if selector, err := labels.Parse(requestInfo.LabelSelector); err != nil {
+			attribs.LabelSelectorParsingErr = err
+		} else {
+			attribs.LabelSelectorRequirements, _ = selector.Requirements()
+		}
+	}
+
 	return &attribs, nil
 }
@@ -19,6 +19,7 @@ package filters
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -26,10 +27,15 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	batch "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/selection"
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/union"
+	"k8s.io/apiserver/pkg/endpoints/request"
 )
 
 func TestGetAuthorizerAttributes(t *testing.T) {
@@ -101,6 +107,31 @@ func TestGetAuthorizerAttributes(t *testing.T) {
 				Resource:        "jobs",
 			},
 		},
+		"selectors": {
+			Verb: "GET",
+			Path: "/api/v1/pods?fieldSelector=spec.nodeName%3Dnode1&labelSelector=app%3Dweb",
+			ExpectedAttributes: &authorizer.AttributesRecord{
+				Verb:                      "list",
+				Path:                      "/api/v1/pods",
+				ResourceRequest:           true,
+				APIVersion:                "v1",
+				Resource:                  "pods",
+				FieldSelectorRequirements: fields.Requirements{{Operator: selection.Equals, Field: "spec.nodeName", Value: "node1"}},
+				LabelSelectorRequirements: labels.Requirements{*mustRequirement(t, "app", selection.Equals, "web")},
+			},
+		},
+		"invalid label selector": {
+			Verb: "GET",
+			Path: "/api/v1/pods?labelSelector=app+in+(web",
+			ExpectedAttributes: &authorizer.AttributesRecord{
+				Verb:                    "list",
+				Path:                    "/api/v1/pods",
+				ResourceRequest:         true,
+				APIVersion:              "v1",
+				Resource:                "pods",
+				LabelSelectorParsingErr: labelSelectorParsingErr("app in (web"),
+			},
+		},
 	}
 
 	for k, tc := range testcases {
@@ -124,6 +155,19 @@ func TestGetAuthorizerAttributes(t *testing.T) {
 	}
 }
 
+func mustRequirement(t *testing.T, key string, op selection.Operator, values ...string) *labels.Requirement {
+	r, err := labels.NewRequirement(key, op, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func labelSelectorParsingErr(selector string) error {
+	_, err := labels.Parse(selector)
+	return err
+}
+
 type fakeAuthorizer struct {
 	decision authorizer.Decision
 	reason   string
@@ -185,3 +229,144 @@ func TestAuditAnnotation(t *testing.T) {
 	}
 
 }
+
+func TestDecidingAuthorizerAuditAnnotation(t *testing.T) {
+	noOpinion := fakeAuthorizer{authorizer.DecisionNoOpinion, "", nil}
+	testcases := map[string]struct {
+		authorizer           authorizer.Authorizer
+		authorizerAnnotation string
+		indexAnnotation      string
+	}{
+		"named allow": {
+			authorizer:           union.New(union.Named("first", noOpinion), union.Named("second", fakeAuthorizer{authorizer.DecisionAllow, "", nil})),
+			authorizerAnnotation: "second",
+			indexAnnotation:      "1",
+		},
+		"unnamed deny": {
+			authorizer:      union.New(fakeAuthorizer{authorizer.DecisionDeny, "", nil}, noOpinion),
+			indexAnnotation: "0",
+		},
+		"no opinion": {
+			authorizer: union.New(noOpinion, noOpinion),
+		},
+		"not a union": {
+			authorizer: fakeAuthorizer{authorizer.DecisionAllow, "", nil},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	negotiatedSerializer := serializer.NewCodecFactory(scheme).WithoutConversion()
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			audit := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+			handler := WithAuthorization(&fakeHTTPHandler{}, tc.authorizer, negotiatedSerializer)
+
+			req, _ := http.NewRequest("GET", "/api/v1/namespaces/default/pods", nil)
+			req = withTestContext(req, nil, audit)
+			req.RemoteAddr = "127.0.0.1"
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tc.authorizerAnnotation, audit.Annotations[authorizerAnnotationKey], "unexpected authorizer annotation")
+			assert.Equal(t, tc.indexAnnotation, audit.Annotations[authorizerIndexAnnotationKey], "unexpected authorizer index annotation")
+			_, hasLatency := audit.Annotations[authorizerLatencyAnnotationKey]
+			assert.Equal(t, len(tc.indexAnnotation) > 0, hasLatency, "unexpected presence of the authorizer latency annotation")
+		})
+	}
+}
+
+func TestAuthorizationRemoteIP(t *testing.T) {
+	var remoteIP net.IP
+	a := authorizer.AuthorizerFunc(func(ctx context.Context, _ authorizer.Attributes) (authorizer.Decision, string, error) {
+		remoteIP, _ = request.RemoteIPFrom(ctx)
+		return authorizer.DecisionAllow, "", nil
+	})
+
+	scheme := runtime.NewScheme()
+	handler := WithAuthorization(&fakeHTTPHandler{}, a, serializer.NewCodecFactory(scheme).WithoutConversion())
+	req, _ := http.NewRequest("GET", "/api/v1/namespaces/default/pods", nil)
+	req = withTestContext(req, nil, &auditinternal.Event{Level: auditinternal.LevelMetadata})
+	req.RemoteAddr = "10.0.0.1:34567"
+	req.Header.Set("X-Forwarded-For", "127.0.0.1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !remoteIP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("expected the remote IP of the connection 10.0.0.1, got %v", remoteIP)
+	}
+}
+
+func TestAuthorizationAttributesMutators(t *testing.T) {
+	var computed map[string]string
+	a := authorizer.AuthorizerFunc(func(_ context.Context, attrs authorizer.Attributes) (authorizer.Decision, string, error) {
+		computed = map[string]string{}
+		c, ok := attrs.(authorizer.ComputedAttributes)
+		if !ok {
+			return authorizer.DecisionNoOpinion, "", errors.New("no computed attributes")
+		}
+		for _, key := range []string{authorizer.ComputedAttributeFieldSelector, authorizer.ComputedAttributeLabelSelector, authorizer.ComputedAttributeOrigin, "tenant"} {
+			if value, ok := c.GetComputedAttribute(key); ok {
+				computed[key] = value
+			}
+		}
+		return authorizer.DecisionAllow, "", nil
+	})
+	tenantMutator := authorizer.AttributesMutatorFunc(func(req *http.Request, attrs *authorizer.AttributesRecord) error {
+		tenant := req.Header.Get("X-Tenant")
+		if len(tenant) == 0 {
+			return errors.New("missing tenant")
+		}
+		attrs.SetComputedAttribute("tenant", tenant)
+		return nil
+	})
+
+	testcases := map[string]struct {
+		url              string
+		tenant           string
+		expectedCode     int
+		expectedComputed map[string]string
+	}{
+		"selectors and origin": {
+			url:          "/api/v1/namespaces/default/pods?fieldSelector=spec.nodeName%3Dnode-1&labelSelector=app%3Dweb",
+			tenant:       "blue",
+			expectedCode: http.StatusOK,
+			expectedComputed: map[string]string{
+				authorizer.ComputedAttributeFieldSelector: "spec.nodeName=node-1",
+				authorizer.ComputedAttributeLabelSelector: "app=web",
+				authorizer.ComputedAttributeOrigin:        "10.0.0.1",
+				"tenant":                                  "blue",
+			},
+		},
+		"no selectors": {
+			url:          "/api/v1/namespaces/default/pods",
+			tenant:       "blue",
+			expectedCode: http.StatusOK,
+			expectedComputed: map[string]string{
+				authorizer.ComputedAttributeOrigin: "10.0.0.1",
+				"tenant":                           "blue",
+			},
+		},
+		"mutator error": {
+			url:          "/api/v1/namespaces/default/pods",
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	negotiatedSerializer := serializer.NewCodecFactory(scheme).WithoutConversion()
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			computed = nil
+			handler := WithAuthorization(&fakeHTTPHandler{}, a, negotiatedSerializer, SelectorsAndOriginMutator, tenantMutator)
+
+			req, _ := http.NewRequest("GET", tc.url, nil)
+			req = withTestContext(req, nil, &auditinternal.Event{Level: auditinternal.LevelMetadata})
+			req.RemoteAddr = "10.0.0.1:34567"
+			if len(tc.tenant) > 0 {
+				req.Header.Set("X-Tenant", tc.tenant)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.expectedCode, recorder.Code, "unexpected status code")
+			assert.Equal(t, tc.expectedComputed, computed, "unexpected computed attributes")
+		})
+	}
+}
@@ -39,6 +39,13 @@ import (
 
 // WithImpersonation is a filter that will inspect and check requests that attempt to change the user.Info for their requests
 func WithImpersonation(handler http.Handler, a authorizer.Authorizer, s runtime.NegotiatedSerializer) http.Handler {
+	return WithImpersonationPolicy(handler, a, s, nil)
+}
+
+// WithImpersonationPolicy is like WithImpersonation, but additionally denies impersonation
+// requests violating the given policy and records the identity of the impersonator in
+// the audit annotations. A nil policy only applies the authorization checks.
+func WithImpersonationPolicy(handler http.Handler, a authorizer.Authorizer, s runtime.NegotiatedSerializer, policy *ImpersonationPolicy) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		impersonationRequests, err := buildImpersonationRequests(req.Header)
 		if err != nil {
@@ -80,6 +87,8 @@ func WithImpersonation(handler http.Handler, a authorizer.Authorizer, s runtime.
 				ResourceRequest: true,
 			}
 
+			// identity is the part of the impersonated user constrained by the policy, if any
+			var identity *impersonatedIdentity
 			switch gvk.GroupKind() {
 			case v1.SchemeGroupVersion.WithKind("ServiceAccount").GroupKind():
 				actingAsAttributes.Resource = "serviceaccounts"
@@ -88,14 +97,17 @@ func WithImpersonation(handler http.Handler, a authorizer.Authorizer, s runtime.
 					// if groups aren't specified for a service account, we know the groups because its a fixed mapping.  Add them
 					groups = serviceaccount.MakeGroupNames(impersonationRequest.Namespace)
 				}
+				identity = &impersonatedIdentity{kind: "user", value: username}
 
 			case v1.SchemeGroupVersion.WithKind("User").GroupKind():
 				actingAsAttributes.Resource = "users"
 				username = impersonationRequest.Name
+				identity = &impersonatedIdentity{kind: "user", value: username}
 
 			case v1.SchemeGroupVersion.WithKind("Group").GroupKind():
 				actingAsAttributes.Resource = "groups"
 				groups = append(groups, impersonationRequest.Name)
+				identity = &impersonatedIdentity{kind: "group", value: impersonationRequest.Name}
 
 			case authenticationv1.SchemeGroupVersion.WithKind("UserExtra").GroupKind():
 				extraKey := impersonationRequest.FieldPath
@@ -103,6 +115,7 @@ func WithImpersonation(handler http.Handler, a authorizer.Authorizer, s runtime.
 				actingAsAttributes.Resource = "userextras"
 				actingAsAttributes.Subresource = extraKey
 				userExtra[extraKey] = append(userExtra[extraKey], extraValue)
+				identity = &impersonatedIdentity{kind: "extra", value: extraKey}
 
 			case authenticationv1.SchemeGroupVersion.WithKind("UID").GroupKind():
 				uid = string(impersonationRequest.Name)
@@ -120,6 +133,14 @@ func WithImpersonation(handler http.Handler, a authorizer.Authorizer, s runtime.
 				responsewriters.Forbidden(ctx, actingAsAttributes, w, req, reason, s)
 				return
 			}
+
+			if identity != nil {
+				if reason := policy.check(requestor, *identity); len(reason) > 0 {
+					klog.V(4).InfoS("Forbidden by impersonation policy", "URI", req.RequestURI, "Reason", reason)
+					responsewriters.Forbidden(ctx, actingAsAttributes, w, req, reason, s)
+					return
+				}
+			}
 		}
 
 		if username != user.Anonymous {
@@ -168,6 +189,11 @@ func WithImpersonation(handler http.Handler, a authorizer.Authorizer, s runtime.
 
 		ae := audit.AuditEventFrom(ctx)
 		audit.LogImpersonatedUser(ae, newUser)
+		if policy != nil {
+			audit.AddAuditAnnotations(ctx,
+				impersonatorUsernameAnnotationKey, requestor.GetName(),
+				impersonatorGroupsAnnotationKey, strings.Join(requestor.GetGroups(), ","))
+		}
 
 		// clear all the impersonation headers from the request
 		req.Header.Del(authenticationv1.ImpersonateUserHeader)
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+const (
+	// impersonatorUsernameAnnotationKey records the name of the user that impersonated another identity.
+	impersonatorUsernameAnnotationKey = "authentication.k8s.io/impersonator-username"
+	// impersonatorGroupsAnnotationKey records the groups of the user that impersonated another identity.
+	impersonatorGroupsAnnotationKey = "authentication.k8s.io/impersonator-groups"
+
+	systemIdentityPrefix = "system:"
+)
+
+// ImpersonationPolicy bounds impersonation beyond the authorization check of the
+// impersonation filter. A request is only allowed to impersonate an identity if the
+// authorizer allows it and the policy does not deny it.
+type ImpersonationPolicy struct {
+	// DenySystemIdentities denies impersonating users and groups with the "system:" prefix,
+	// including service accounts. The system:authenticated and system:unauthenticated
+	// groups, which are added to impersonated users anyway, may always be requested.
+	DenySystemIdentities bool
+
+	// Constraints restrict the identities that selected impersonators may assume. An
+	// impersonator not selected by any constraint is only bound by the authorizer. An
+	// impersonator selected by one or more constraints may only assume an identity that
+	// is allowed by at least one of them.
+	Constraints []ImpersonationConstraint
+}
+
+// ImpersonationConstraint restricts the identities a set of impersonators may assume.
+// Patterns either match exactly or, when ending in "*", match every value with that prefix.
+type ImpersonationConstraint struct {
+	// Users and Groups select the impersonators the constraint applies to, by user name
+	// or by group membership.
+	Users  []string
+	Groups []string
+
+	// AllowedUsernames are the user names the impersonators may assume. Service accounts
+	// are matched by their full user name, e.g. system:serviceaccount:kube-system:default.
+	// If empty, no user may be impersonated.
+	AllowedUsernames []string
+	// AllowedGroups are the groups the impersonators may assume. If empty, no group may be impersonated.
+	AllowedGroups []string
+	// AllowedExtraKeys are the user extra keys the impersonators may set. If empty, no extra may be set.
+	AllowedExtraKeys []string
+}
+
+// impersonatedIdentity is a single identity attribute requested to be impersonated.
+type impersonatedIdentity struct {
+	// kind is one of "user", "group" or "extra".
+	kind  string
+	value string
+}
+
+// check returns a reason if the policy denies the impersonator to assume the identity,
+// or an empty string if it is allowed.
+func (p *ImpersonationPolicy) check(impersonator user.Info, identity impersonatedIdentity) string {
+	if p == nil {
+		return ""
+	}
+
+	if identity.kind == "group" && (identity.value == user.AllAuthenticated || identity.value == user.AllUnauthenticated) {
+		// one of them is added to every impersonated user anyway
+		return ""
+	}
+
+	if p.DenySystemIdentities && identity.kind != "extra" && strings.HasPrefix(identity.value, systemIdentityPrefix) {
+		return fmt.Sprintf("impersonating system %s %q is not allowed", identity.kind, identity.value)
+	}
+
+	selected := false
+	for i := range p.Constraints {
+		c := &p.Constraints[i]
+		if !c.selects(impersonator) {
+			continue
+		}
+		selected = true
+		if c.allows(identity) {
+			return ""
+		}
+	}
+	if selected {
+		return fmt.Sprintf("impersonation constraints do not allow %q to impersonate %s %q", impersonator.GetName(), identity.kind, identity.value)
+	}
+	return ""
+}
+
+func (c *ImpersonationConstraint) selects(impersonator user.Info) bool {
+	if user.MatchesAnyPattern(c.Users, impersonator.GetName()) {
+		return true
+	}
+	for _, group := range impersonator.GetGroups() {
+		if user.MatchesAnyPattern(c.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ImpersonationConstraint) allows(identity impersonatedIdentity) bool {
+	switch identity.kind {
+	case "user":
+		return user.MatchesAnyPattern(c.AllowedUsernames, identity.value)
+	case "group":
+		return user.MatchesAnyPattern(c.AllowedGroups, identity.value)
+	case "extra":
+		return user.MatchesAnyPattern(c.AllowedExtraKeys, identity.value)
+	}
+	return false
+}
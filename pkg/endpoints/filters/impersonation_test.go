@@ -29,6 +29,7 @@ import (
 	authenticationapi "k8s.io/api/authentication/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/endpoints/request"
@@ -569,3 +570,125 @@ func TestImpersonationFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestImpersonationPolicy(t *testing.T) {
+	policy := &ImpersonationPolicy{
+		DenySystemIdentities: true,
+		Constraints: []ImpersonationConstraint{
+			{
+				Groups:           []string{"breakglass"},
+				AllowedUsernames: []string{"alice", "team-*"},
+				AllowedGroups:    []string{"developers"},
+				AllowedExtraKeys: []string{"scopes"},
+			},
+		},
+	}
+	testCases := []struct {
+		name         string
+		requestor    *user.DefaultInfo
+		user         string
+		groups       []string
+		extras       map[string]string
+		expectedCode int
+	}{
+		{
+			name:         "allowed user",
+			requestor:    &user.DefaultInfo{Name: "system:admin", Groups: []string{"breakglass"}},
+			user:         "alice",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "allowed user prefix with groups and extras",
+			requestor:    &user.DefaultInfo{Name: "system:admin", Groups: []string{"breakglass"}},
+			user:         "team-a",
+			groups:       []string{"developers", user.AllAuthenticated},
+			extras:       map[string]string{"scopes": "view"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "user not allowed",
+			requestor:    &user.DefaultInfo{Name: "system:admin", Groups: []string{"breakglass"}},
+			user:         "bob",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "group not allowed",
+			requestor:    &user.DefaultInfo{Name: "system:admin", Groups: []string{"breakglass"}},
+			user:         "alice",
+			groups:       []string{"admins"},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "extra key not allowed",
+			requestor:    &user.DefaultInfo{Name: "system:admin", Groups: []string{"breakglass"}},
+			user:         "alice",
+			extras:       map[string]string{"tenant": "a"},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "unconstrained impersonator",
+			requestor:    &user.DefaultInfo{Name: "system:admin"},
+			user:         "bob",
+			groups:       []string{"admins"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "system user denied",
+			requestor:    &user.DefaultInfo{Name: "system:admin"},
+			user:         "system:kube-controller-manager",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "service account denied",
+			requestor:    &user.DefaultInfo{Name: "system:admin"},
+			user:         "system:serviceaccount:kube-system:default",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "system group denied",
+			requestor:    &user.DefaultInfo{Name: "system:admin"},
+			user:         "bob",
+			groups:       []string{user.SystemPrivilegedGroup},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actualUser user.Info
+			delegate := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				actualUser, _ = request.UserFrom(req.Context())
+			})
+			handler := WithImpersonationPolicy(delegate, impersonateAuthorizer{}, serializer.NewCodecFactory(runtime.NewScheme()), policy)
+
+			req, _ := http.NewRequest("GET", "/api/v1/namespaces", nil)
+			req.Header.Set(authenticationapi.ImpersonateUserHeader, tc.user)
+			for _, group := range tc.groups {
+				req.Header.Add(authenticationapi.ImpersonateGroupHeader, group)
+			}
+			for key, value := range tc.extras {
+				req.Header.Add(authenticationapi.ImpersonateUserExtraHeaderPrefix+key, value)
+			}
+			ae := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+			req = withTestContext(req, tc.requestor, ae)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			if recorder.Code != tc.expectedCode {
+				t.Fatalf("expected %v, got %v: %s", tc.expectedCode, recorder.Code, recorder.Body.String())
+			}
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+			if actualUser == nil || actualUser.GetName() != tc.user {
+				t.Fatalf("expected user %q, got %v", tc.user, actualUser)
+			}
+			if ae.Annotations[impersonatorUsernameAnnotationKey] != tc.requestor.Name {
+				t.Errorf("expected impersonator username annotation %q, got %q", tc.requestor.Name, ae.Annotations[impersonatorUsernameAnnotationKey])
+			}
+			if groups := strings.Join(tc.requestor.Groups, ","); ae.Annotations[impersonatorGroupsAnnotationKey] != groups {
+				t.Errorf("expected impersonator groups annotation %q, got %q", groups, ae.Annotations[impersonatorGroupsAnnotationKey])
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 //
 // a. If the caller does not specify a value for Audit-ID in the request header, we generate a new audit ID
 // b. We echo the Audit-ID value to the caller via the response Header 'Audit-ID'.
+// c. If the caller specifies a value for Audit-Parent-ID in the request header, we attach it as the parent audit ID.
 func WithAuditID(handler http.Handler) http.Handler {
 	return withAuditID(handler, func() string {
 		return uuid.New().String()
@@ -50,7 +51,9 @@ func withAuditID(handler http.Handler, newAuditIDFunc func() string) http.Handle
 		}
 
 		// Note: we save the user specified value of the Audit-ID header as is, no truncation is performed.
-		r = r.WithContext(request.WithAuditID(ctx, types.UID(auditID)))
+		ctx = request.WithAuditID(ctx, types.UID(auditID))
+		ctx = request.WithParentAuditID(ctx, types.UID(r.Header.Get(auditinternal.HeaderParentAuditID)))
+		r = r.WithContext(ctx)
 
 		// We echo the Audit-ID in to the response header.
 		// It's not guaranteed Audit-ID http header is sent for all requests.
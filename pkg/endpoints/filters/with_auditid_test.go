@@ -33,6 +33,7 @@ func TestWithAuditID(t *testing.T) {
 		newAuditIDFunc   func() string
 		auditIDSpecified string
 		auditIDExpected  string
+		parentSpecified  string
 	}{
 		{
 			name:             "user specifies a value for Audit-ID in the request header",
@@ -58,6 +59,12 @@ func TestWithAuditID(t *testing.T) {
 			},
 			auditIDExpected: largeAuditID,
 		},
+		{
+			name:             "user specifies a value for Audit-Parent-ID in the request header",
+			auditIDSpecified: "foo-bar-baz",
+			auditIDExpected:  "foo-bar-baz",
+			parentSpecified:  "parent",
+		},
 	}
 
 	for _, test := range tests {
@@ -66,6 +73,7 @@ func TestWithAuditID(t *testing.T) {
 			var (
 				innerHandlerCallCount int
 				auditIDGot            string
+				parentGot             string
 				found                 bool
 			)
 			handler := http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
@@ -76,6 +84,9 @@ func TestWithAuditID(t *testing.T) {
 
 				found = ok
 				auditIDGot = string(v)
+
+				p, _ := request.ParentAuditIDFrom(req.Context())
+				parentGot = string(p)
 			})
 
 			wrapped := WithAuditID(handler)
@@ -90,6 +101,9 @@ func TestWithAuditID(t *testing.T) {
 			if len(test.auditIDSpecified) > 0 {
 				testRequest.Header.Set(auditKey, test.auditIDSpecified)
 			}
+			if len(test.parentSpecified) > 0 {
+				testRequest.Header.Set("Audit-Parent-ID", test.parentSpecified)
+			}
 
 			w := httptest.NewRecorder()
 			wrapped.ServeHTTP(w, testRequest)
@@ -104,6 +118,10 @@ func TestWithAuditID(t *testing.T) {
 				t.Errorf("WithAuditID: expected the request context to have: %q, but got=%q", test.auditIDExpected, auditIDGot)
 			}
 
+			if test.parentSpecified != parentGot {
+				t.Errorf("WithAuditID: expected the request context to have parent audit ID: %q, but got=%q", test.parentSpecified, parentGot)
+			}
+
 			auditIDEchoed := w.Header().Get(auditKey)
 			if test.auditIDExpected != auditIDEchoed {
 				t.Errorf("WithAuditID: expected Audit-ID response header: %q, but got: %q", test.auditIDExpected, auditIDEchoed)
@@ -653,7 +653,7 @@ func (p *patcher) patchResource(ctx context.Context, scope *RequestScope) (runti
 		return obj, nil
 	}
 
-	transformers := []rest.TransformFunc{p.applyPatch, p.applyAdmission, dedupOwnerReferencesTransformer}
+	transformers := []rest.TransformFunc{auditOldObjectTransformer(scope), p.applyPatch, p.applyAdmission, dedupOwnerReferencesTransformer}
 	if scope.FieldManager != nil {
 		transformers = append(transformers, fieldmanager.IgnoreManagedFieldsTimestampsTransformer)
 	}
@@ -39,6 +39,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/endpoints/handlers/fieldmanager"
 	requestmetrics "k8s.io/apiserver/pkg/endpoints/handlers/metrics"
@@ -272,6 +273,19 @@ func hasUID(obj runtime.Object) (bool, error) {
 	return true, nil
 }
 
+// auditOldObjectTransformer returns a transform func recording the current object
+// of an update or patch request in the audit context, so that the audit event can
+// carry a diff between the old and the new object. Objects that do not exist yet,
+// i.e. create on update, are not recorded.
+func auditOldObjectTransformer(scope *RequestScope) rest.TransformFunc {
+	return func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+		if exists, err := hasUID(oldObj); err == nil && exists {
+			audit.LogOldObject(ctx, oldObj, scope.Kind.GroupVersion(), scope.Serializer)
+		}
+		return newObj, nil
+	}
+}
+
 // checkName checks the provided name against the request
 func checkName(obj runtime.Object, name, namespace string, namer ScopeNamer) error {
 	objNamespace, objName, err := namer.ObjectName(obj)
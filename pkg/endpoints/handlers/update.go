@@ -148,7 +148,7 @@ func UpdateResource(r rest.Updater, scope *RequestScope, admit admission.Interfa
 		}
 
 		userInfo, _ := request.UserFrom(ctx)
-		transformers := []rest.TransformFunc{}
+		transformers := []rest.TransformFunc{auditOldObjectTransformer(scope)}
 
 		// allows skipping managedFields update if the resulting object is too big
 		shouldUpdateManagedFields := true
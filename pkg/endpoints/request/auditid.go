@@ -24,8 +24,12 @@ import (
 
 type auditIDKeyType int
 
-// auditIDKey is the key to associate the Audit-ID value of a request.
-const auditIDKey auditIDKeyType = iota
+const (
+	// auditIDKey is the key to associate the Audit-ID value of a request.
+	auditIDKey auditIDKeyType = iota
+	// parentAuditIDKey is the key to associate the Audit-Parent-ID value of a request.
+	parentAuditIDKey
+)
 
 // WithAuditID returns a copy of the parent context into which the Audit-ID
 // associated with the request is set.
@@ -44,6 +48,23 @@ func AuditIDFrom(ctx context.Context) (types.UID, bool) {
 	return auditID, ok
 }
 
+// WithParentAuditID returns a copy of the parent context into which the
+// audit ID of the request on whose behalf the request was sent is set.
+//
+// If the specified parentAuditID is empty, no value is set and the parent context is returned as is.
+func WithParentAuditID(parent context.Context, parentAuditID types.UID) context.Context {
+	if parentAuditID == "" {
+		return parent
+	}
+	return WithValue(parent, parentAuditIDKey, parentAuditID)
+}
+
+// ParentAuditIDFrom returns the value of the parent audit ID from the request context.
+func ParentAuditIDFrom(ctx context.Context) (types.UID, bool) {
+	parentAuditID, ok := ctx.Value(parentAuditIDKey).(types.UID)
+	return parentAuditID, ok
+}
+
 // GetAuditIDTruncated returns the audit ID (truncated) from the request context.
 // If the length of the Audit-ID value exceeds the limit, we truncate it to keep
 // the first N (maxAuditIDLength) characters.
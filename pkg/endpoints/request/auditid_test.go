@@ -66,3 +66,21 @@ func TestAuditIDFrom(t *testing.T) {
 		})
 	}
 }
+
+func TestParentAuditIDFrom(t *testing.T) {
+	parent := context.TODO()
+	if ctx := WithParentAuditID(parent, ""); ctx != parent {
+		t.Error("expected no copy of the parent context with an empty parent audit ID")
+	}
+	if _, ok := ParentAuditIDFrom(parent); ok {
+		t.Error("expected ParentAuditIDFrom to return false without a parent audit ID")
+	}
+
+	ctx := WithParentAuditID(WithAuditID(parent, "child"), "parent")
+	if value, ok := ParentAuditIDFrom(ctx); !ok || value != "parent" {
+		t.Errorf("expected parent audit ID: %q, but got: %q (%t)", "parent", value, ok)
+	}
+	if value, _ := AuditIDFrom(ctx); value != "child" {
+		t.Errorf("expected audit ID: %q, but got: %q", "child", value)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"context"
+	"net"
+)
+
+type remoteIPKeyType int
+
+// remoteIPKey is the key for the context of the IP address of the peer the request was received from.
+const remoteIPKey remoteIPKeyType = iota
+
+// WithRemoteIP returns a copy of parent context in which the IP address of the peer the request was
+// received from is set. It is the address of the connection, as opposed to an address given in a
+// header such as X-Forwarded-For, which the peer can set to anything.
+//
+// If the specified IP is nil, no value is set and the parent context is returned as is.
+func WithRemoteIP(parent context.Context, ip net.IP) context.Context {
+	if ip == nil {
+		return parent
+	}
+	return WithValue(parent, remoteIPKey, ip)
+}
+
+// RemoteIPFrom returns the value of the remote IP key from the specified context.
+func RemoteIPFrom(ctx context.Context) (net.IP, bool) {
+	ip, ok := ctx.Value(remoteIPKey).(net.IP)
+	return ip, ok
+}
+
+// RemoteIP returns the IP address of remoteAddr, the address of the peer of a request as in
+// http.Request.RemoteAddr, or nil if it holds none.
+func RemoteIP(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return net.ParseIP(host)
+}
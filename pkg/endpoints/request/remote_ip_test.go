@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestWithRemoteIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expected   net.IP
+	}{
+		{
+			name:       "IPv4 with port",
+			remoteAddr: "127.0.0.1:34567",
+			expected:   net.ParseIP("127.0.0.1"),
+		},
+		{
+			name:       "IPv6 with port",
+			remoteAddr: "[::1]:34567",
+			expected:   net.ParseIP("::1"),
+		},
+		{
+			name:       "IP without port",
+			remoteAddr: "10.0.0.1",
+			expected:   net.ParseIP("10.0.0.1"),
+		},
+		{
+			name:       "no IP",
+			remoteAddr: "@",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithRemoteIP(context.TODO(), RemoteIP(test.remoteAddr))
+
+			ip, ok := RemoteIPFrom(ctx)
+			if ok != (test.expected != nil) {
+				t.Fatalf("RemoteIPFrom: expected ok=%v, got %v", test.expected != nil, ok)
+			}
+			if !ip.Equal(test.expected) {
+				t.Errorf("RemoteIPFrom: expected %v, got %v", test.expected, ip)
+			}
+		})
+	}
+}
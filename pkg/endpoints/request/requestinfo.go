@@ -62,6 +62,11 @@ type RequestInfo struct {
 	Name string
 	// Parts are the path parts for the request, always starting with /{resource}/{name}
 	Parts []string
+
+	// FieldSelector and LabelSelector are the selectors of list, watch and deletecollection requests, as given in
+	// their query. They are empty for other requests.
+	FieldSelector string
+	LabelSelector string
 }
 
 // specialVerbs contains just strings which are used in REST paths for special actions that don't fall under the normal
@@ -243,6 +248,13 @@ func (r *RequestInfoFactory) NewRequestInfo(req *http.Request) (*RequestInfo, er
 		requestInfo.Verb = "deletecollection"
 	}
 
+	switch requestInfo.Verb {
+	case "list", "watch", "deletecollection":
+		query := req.URL.Query()
+		requestInfo.FieldSelector = query.Get("fieldSelector")
+		requestInfo.LabelSelector = query.Get("labelSelector")
+	}
+
 	return &requestInfo, nil
 }
 
@@ -263,3 +263,39 @@ func TestFieldSelectorParsing(t *testing.T) {
 		}
 	}
 }
+
+func TestSelectors(t *testing.T) {
+	tests := []struct {
+		method                string
+		url                   string
+		expectedFieldSelector string
+		expectedLabelSelector string
+	}{
+		{"GET", "/api/v1/pods", "", ""},
+		{"GET", "/api/v1/pods?fieldSelector=spec.nodeName%3Dnode1&labelSelector=app%3Dweb", "spec.nodeName=node1", "app=web"},
+		{"GET", "/api/v1/pods?watch=true&fieldSelector=spec.nodeName%3Dnode1", "spec.nodeName=node1", ""},
+		{"DELETE", "/api/v1/namespaces/other/pods?labelSelector=app!%3Dweb", "", "app!=web"},
+		// selectors of requests for single objects are ignored
+		{"GET", "/api/v1/namespaces/other/pods/foo?fieldSelector=spec.nodeName%3Dnode1", "", ""},
+		{"DELETE", "/api/v1/namespaces/other/pods/foo?labelSelector=app%3Dweb", "", ""},
+		{"GET", "/healthz?labelSelector=app%3Dweb", "", ""},
+	}
+
+	resolver := newTestRequestInfoResolver()
+
+	for _, tc := range tests {
+		req, _ := http.NewRequest(tc.method, tc.url, nil)
+
+		requestInfo, err := resolver.NewRequestInfo(req)
+		if err != nil {
+			t.Errorf("%s %s: unexpected error %v", tc.method, tc.url, err)
+			continue
+		}
+		if e, a := tc.expectedFieldSelector, requestInfo.FieldSelector; e != a {
+			t.Errorf("%s %s: expected field selector %q, actual %q", tc.method, tc.url, e, a)
+		}
+		if e, a := tc.expectedLabelSelector, requestInfo.LabelSelector; e != a {
+			t.Errorf("%s %s: expected label selector %q, actual %q", tc.method, tc.url, e, a)
+		}
+	}
+}
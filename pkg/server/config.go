@@ -36,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	utilwaitgroup "k8s.io/apimachinery/pkg/util/waitgroup"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/apiserver/pkg/admission"
@@ -134,6 +135,9 @@ type Config struct {
 	AuditBackend audit.Backend
 	// AuditPolicyRuleEvaluator makes the decision of whether and how to audit log a request.
 	AuditPolicyRuleEvaluator audit.PolicyRuleEvaluator
+	// AuditShutdownTimeout bounds the time the audit backend is given to deliver pending events
+	// once requests are drained during graceful termination. No bound if zero.
+	AuditShutdownTimeout time.Duration
 	// ExternalAddress is the host name to use for external (public internet) facing URLs (e.g. Swagger)
 	// Will default to a value based on secure serving info and available ipv4 IPs.
 	ExternalAddress string
@@ -309,12 +313,27 @@ type AuthenticationInfo struct {
 	APIAudiences authenticator.Audiences
 	// Authenticator determines which subject is making the request
 	Authenticator authenticator.Request
+	// ConfigReloaders keep the Authenticator in sync with its configuration files,
+	// such as JWT issuers, static token files or certificate revocation lists. They are started by a post-start hook.
+	ConfigReloaders []dynamiccertificates.ControllerRunner
 }
 
 type AuthorizationInfo struct {
 	// Authorizer determines whether the subject is allowed to make the request based only
 	// on the RequestURI
 	Authorizer authorizer.Authorizer
+
+	// ImpersonationPolicy optionally bounds which identities users may impersonate beyond
+	// what the Authorizer allows. If nil, impersonation is only subject to the Authorizer.
+	ImpersonationPolicy *genericapifilters.ImpersonationPolicy
+
+	// FlushCache, if set, drops the decisions cached by the Authorizer. With profiling enabled, it is
+	// exposed as the debug flag /debug/flags/authorization-cache, which flushes on a PUT of "flush".
+	FlushCache func()
+
+	// AttributesMutators add computed attributes to the attributes of each request before the Authorizer
+	// decides on it, e.g. genericapifilters.SelectorsAndOriginMutator.
+	AttributesMutators []authorizer.AttributesMutator
 }
 
 // NewConfig returns a Config struct with the default values
@@ -619,6 +638,7 @@ func (c completedConfig) New(name string, delegationTarget DelegationTarget) (*G
 		admissionControl:           c.AdmissionControl,
 		Serializer:                 c.Serializer,
 		AuditBackend:               c.AuditBackend,
+		AuditShutdownTimeout:       c.AuditShutdownTimeout,
 		Authorizer:                 c.Authorization.Authorizer,
 		delegationTarget:           delegationTarget,
 		EquivalentResourceRegistry: c.EquivalentResourceRegistry,
@@ -756,6 +776,24 @@ func (c completedConfig) New(name string, delegationTarget DelegationTarget) (*G
 		}
 	}
 
+	if len(c.Authentication.ConfigReloaders) > 0 {
+		const authenticationConfigReloaderHookName = "authentication-config-reloader"
+		if !s.isPostStartHookRegistered(authenticationConfigReloaderHookName) {
+			if err := s.AddPostStartHook(authenticationConfigReloaderHookName, func(context PostStartHookContext) error {
+				for _, reloader := range c.Authentication.ConfigReloaders {
+					ctx, cancel := wait.ContextForChannel(context.StopCh)
+					go func(reloader dynamiccertificates.ControllerRunner) {
+						defer cancel()
+						reloader.Run(ctx, 1)
+					}(reloader)
+				}
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Add PostStartHook for maintenaing the object count tracker.
 	if c.StorageObjectCountTracker != nil {
 		const storageObjectCountTrackerHookName = "storage-object-count-tracker-hook"
@@ -807,7 +845,7 @@ func BuildHandlerChainWithStorageVersionPrecondition(apiHandler http.Handler, c
 
 func DefaultBuildHandlerChain(apiHandler http.Handler, c *Config) http.Handler {
 	handler := filterlatency.TrackCompleted(apiHandler)
-	handler = genericapifilters.WithAuthorization(handler, c.Authorization.Authorizer, c.Serializer)
+	handler = genericapifilters.WithAuthorization(handler, c.Authorization.Authorizer, c.Serializer, c.Authorization.AttributesMutators...)
 	handler = filterlatency.TrackStarted(handler, "authorization")
 
 	if c.FlowControl != nil {
@@ -822,7 +860,7 @@ func DefaultBuildHandlerChain(apiHandler http.Handler, c *Config) http.Handler {
 	}
 
 	handler = filterlatency.TrackCompleted(handler)
-	handler = genericapifilters.WithImpersonation(handler, c.Authorization.Authorizer, c.Serializer)
+	handler = genericapifilters.WithImpersonationPolicy(handler, c.Authorization.Authorizer, c.Serializer, c.Authorization.ImpersonationPolicy)
 	handler = filterlatency.TrackStarted(handler, "impersonation")
 
 	handler = filterlatency.TrackCompleted(handler)
@@ -869,6 +907,19 @@ func DefaultBuildHandlerChain(apiHandler http.Handler, c *Config) http.Handler {
 	return handler
 }
 
+// authorizationCacheSetter returns the setter of the authorization-cache debug flag, which flushes
+// the authorization cache when set to "flush".
+func authorizationCacheSetter(flush func()) routes.StringFlagSetterFunc {
+	return func(value string) (string, error) {
+		if strings.TrimSpace(value) != "flush" {
+			return "", fmt.Errorf("unsupported value %q, only \"flush\" is supported", value)
+		}
+		flush()
+		klog.Info("Flushed the authorization cache")
+		return "authorization cache flushed", nil
+	}
+}
+
 func installAPI(s *GenericAPIServer, c *Config) {
 	if c.EnableIndex {
 		routes.Index{}.Install(s.listedPathProvider, s.Handler.NonGoRestfulMux)
@@ -878,8 +929,11 @@ func installAPI(s *GenericAPIServer, c *Config) {
 		if c.EnableContentionProfiling {
 			goruntime.SetBlockProfileRate(1)
 		}
-		// so far, only logging related endpoints are considered valid to add for these debug flags.
+		// so far, only logging related endpoints and flushing the authorization cache are considered valid to add for these debug flags.
 		routes.DebugFlags{}.Install(s.Handler.NonGoRestfulMux, "v", routes.StringFlagPutHandler(logs.GlogSetter))
+		if c.Authorization.FlushCache != nil {
+			routes.DebugFlags{}.Install(s.Handler.NonGoRestfulMux, "authorization-cache", routes.StringFlagPutHandler(authorizationCacheSetter(c.Authorization.FlushCache)))
+		}
 	}
 	if c.EnableMetrics {
 		if c.EnableProfiling {
@@ -959,5 +1013,5 @@ func AuthorizeClientBearerToken(loopback *restclient.Config, authn *Authenticati
 	authn.Authenticator = authenticatorunion.New(tokenAuthenticator, authn.Authenticator)
 
 	tokenAuthorizer := authorizerfactory.NewPrivilegedGroups(user.SystemPrivilegedGroup)
-	authz.Authorizer = authorizerunion.New(tokenAuthorizer, authz.Authorizer)
+	authz.Authorizer = authorizerunion.New(authorizerunion.Named("loopback", tokenAuthorizer), authz.Authorizer)
 }
@@ -364,3 +364,17 @@ func (b *testBackend) ProcessEvents(events ...*auditinternal.Event) bool {
 	b.events = append(b.events, events...)
 	return true
 }
+
+func TestAuthorizationCacheSetter(t *testing.T) {
+	flushed := 0
+	setter := authorizationCacheSetter(func() { flushed++ })
+	if _, err := setter("clear"); err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+	if _, err := setter("flush\n"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if flushed != 1 {
+		t.Errorf("expected the cache to be flushed once, got %d", flushed)
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	flowcontrol "k8s.io/api/flowcontrol/v1beta2"
 	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/audit"
 	epmetrics "k8s.io/apiserver/pkg/endpoints/metrics"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/server/httplog"
@@ -118,6 +119,7 @@ func WithPriorityAndFairness(
 
 			httplog.AddKeyValue(ctx, "apf_pl", truncateLogField(pl.Name))
 			httplog.AddKeyValue(ctx, "apf_fs", truncateLogField(fs.Name))
+			audit.LogFlowControl(ctx, fs.Name, pl.Name)
 		}
 		// estimateWork is called, if at all, after noteFn
 		estimateWork := func() flowcontrolrequest.WorkEstimate {
@@ -266,7 +268,7 @@ func WithPriorityAndFairness(
 
 			select {
 			case <-shouldStartWatchCh:
-				watchCtx := utilflowcontrol.WithInitializationSignal(ctx, watchInitializationSignal)
+				watchCtx := utilflowcontrol.WithInitializationSignal(withPriorityLevel(ctx, classification), watchInitializationSignal)
 				watchReq = r.WithContext(watchCtx)
 				handler.ServeHTTP(w, watchReq)
 				// Protect from the situation when request will not reach storage layer
@@ -289,7 +291,7 @@ func WithPriorityAndFairness(
 				served = true
 				setResponseHeaders(classification, w)
 
-				handler.ServeHTTP(w, r)
+				handler.ServeHTTP(w, r.WithContext(withPriorityLevel(ctx, classification)))
 			}
 
 			fcIfc.Handle(ctx, digest, noteFn, estimateWork, queueNote, execute)
@@ -312,6 +314,15 @@ func StartPriorityAndFairnessWatermarkMaintenance(stopCh <-chan struct{}) {
 	startWatermarkMaintenance(waitingMark, stopCh)
 }
 
+// withPriorityLevel returns a copy of ctx with the priority level of classification, so that
+// later filters, like the authorization, can prioritize the request accordingly.
+func withPriorityLevel(ctx context.Context, classification *PriorityAndFairnessClassification) context.Context {
+	if classification == nil {
+		return ctx
+	}
+	return utilflowcontrol.WithPriorityLevel(ctx, classification.PriorityLevelName)
+}
+
 func setResponseHeaders(classification *PriorityAndFairnessClassification, w http.ResponseWriter) {
 	if classification == nil {
 		return
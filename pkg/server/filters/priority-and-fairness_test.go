@@ -664,6 +664,31 @@ func TestApfWithRequestDigest(t *testing.T) {
 	}
 }
 
+func TestApfPriorityLevelInContext(t *testing.T) {
+	epmetrics.Register()
+	fcmetrics.Register()
+	longRunningFunc := func(_ *http.Request, _ *apirequest.RequestInfo) bool { return false }
+	fakeFilter := fakeApfFilter{mockDecision: decisionNoQueuingExecute, WatchTracker: utilflowcontrol.NewWatchTracker()}
+
+	var priorityLevel string
+	var found bool
+	handler := WithPriorityAndFairness(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		priorityLevel, found = utilflowcontrol.PriorityLevelFrom(req.Context())
+	}), longRunningFunc, fakeFilter, defaultRequestWorkEstimator)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/namespaces/default", nil)
+	if err != nil {
+		t.Fatalf("Failed to create new http request - %v", err)
+	}
+	req = req.WithContext(apirequest.WithRequestInfo(req.Context(), &apirequest.RequestInfo{Verb: "get"}))
+	req = req.WithContext(apirequest.WithUser(req.Context(), &user.DefaultInfo{Name: "foo"}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if expected := bootstrap.SuggestedPriorityLevelConfigurationGlobalDefault.Name; !found || priorityLevel != expected {
+		t.Errorf("Expected the priority level %q in the context of the executed request, got %q (found: %v)", expected, priorityLevel, found)
+	}
+}
+
 func TestPriorityAndFairnessWithPanicRecoveryAndTimeoutFilter(t *testing.T) {
 	epmetrics.Register()
 	fcmetrics.Register()
@@ -17,6 +17,7 @@ limitations under the License.
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	gpath "path"
@@ -193,6 +194,8 @@ type GenericAPIServer struct {
 
 	// auditing. The backend is started before the server starts listening.
 	AuditBackend audit.Backend
+	// AuditShutdownTimeout bounds the shutdown of the audit backend, no bound if zero.
+	AuditShutdownTimeout time.Duration
 
 	// Authorizer determines whether a user is allowed to make a certain request. The Handler does a preliminary
 	// authorization check using the request URI but it may be necessary to make additional checks, such as in
@@ -602,8 +605,19 @@ func (s preparedGenericAPIServer) Run(stopCh <-chan struct{}) error {
 	<-drainedCh.Signaled()
 
 	if s.AuditBackend != nil {
-		s.AuditBackend.Shutdown()
-		klog.V(1).InfoS("[graceful-termination] audit backend shutdown completed")
+		if s.AuditShutdownTimeout > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), s.AuditShutdownTimeout)
+			err := audit.ShutdownWithContext(ctx, s.AuditBackend)
+			cancel()
+			if err != nil {
+				klog.ErrorS(err, "[graceful-termination] audit backend shutdown timed out", "timeout", s.AuditShutdownTimeout)
+			} else {
+				klog.V(1).InfoS("[graceful-termination] audit backend shutdown completed")
+			}
+		} else {
+			s.AuditBackend.Shutdown()
+			klog.V(1).InfoS("[graceful-termination] audit backend shutdown completed")
+		}
 	}
 
 	// wait for stoppedCh that is closed when the graceful termination (server.Shutdown) is finished.
@@ -17,31 +17,48 @@ limitations under the License.
 package options
 
 import (
+	"crypto"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
-	"gopkg.in/natefinch/lumberjack.v2"
 	"k8s.io/klog/v2"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
 	"k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/audit/policy"
 	"k8s.io/apiserver/pkg/server"
 	"k8s.io/apiserver/pkg/server/egressselector"
-	"k8s.io/apiserver/pkg/util/webhook"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
 	pluginbuffered "k8s.io/apiserver/plugin/pkg/audit/buffered"
+	plugindedup "k8s.io/apiserver/plugin/pkg/audit/dedup"
+	plugindynamic "k8s.io/apiserver/plugin/pkg/audit/dynamic"
+	pluginkafka "k8s.io/apiserver/plugin/pkg/audit/kafka"
 	pluginlog "k8s.io/apiserver/plugin/pkg/audit/log"
+	pluginotlp "k8s.io/apiserver/plugin/pkg/audit/otlp"
+	pluginratelimit "k8s.io/apiserver/plugin/pkg/audit/ratelimit"
+	plugins3 "k8s.io/apiserver/plugin/pkg/audit/s3"
+	pluginsyslog "k8s.io/apiserver/plugin/pkg/audit/syslog"
 	plugintruncate "k8s.io/apiserver/plugin/pkg/audit/truncate"
+	pluginunixsocket "k8s.io/apiserver/plugin/pkg/audit/unixsocket"
 	pluginwebhook "k8s.io/apiserver/plugin/pkg/audit/webhook"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/keyutil"
+	cliflag "k8s.io/component-base/cli/flag"
 )
 
 const (
@@ -52,26 +69,71 @@ const (
 	defaultBatchMaxWait       = 30 * time.Second // Send events at least twice a minute.
 	defaultBatchThrottleQPS   = 10               // Limit the send rate by 10 QPS.
 	defaultBatchThrottleBurst = 15               // Allow up to 15 QPS burst.
+	defaultBatchMaxBlock      = time.Second      // Block requests for up to 1 second when blocking on overflow.
+	defaultBatchWALMaxSize    = 1 << 30          // Keep up to 1GiB of events in the write-ahead log.
+
+	// Default configuration values for coalescing identical events.
+	defaultDedupWindow     = 10 * time.Second // Coalesce identical events for up to 10 seconds.
+	defaultDedupMaxPending = 10000            // Coalesce up to 10000 distinct events at a time.
+
+	// Default configuration values for rate limiting the events of each user.
+	defaultRateLimitQPS            = 10          // Limit the events of each user to 10 per second.
+	defaultRateLimitBurst          = 50          // Allow bursts of up to 50 events of each user.
+	defaultRateLimitReportInterval = time.Minute // Report suppressed events once a minute.
 )
 
-func appendBackend(existing, newBackend audit.Backend) audit.Backend {
-	if existing == nil {
-		return newBackend
+// auditBackendHealthCheckName is the name of the health and readiness check
+// reporting the audit backend being unhealthy, e.g. failing to deliver events.
+const auditBackendHealthCheckName = "audit-backend"
+
+// auditLogEncryptionResource is the resource of the encryption configuration
+// whose providers encrypt the audit log.
+var auditLogEncryptionResource = schema.GroupResource{Group: "audit.k8s.io", Resource: "events"}
+
+// unionBackends joins the non-nil backends by name, so that audit policy rules
+// can route events to a subset of them.
+func unionBackends(backends ...audit.NamedBackend) audit.Backend {
+	var named []audit.NamedBackend
+	for _, b := range backends {
+		if b.Backend != nil {
+			named = append(named, b)
+		}
 	}
-	if newBackend == nil {
-		return existing
+	if len(named) == 0 {
+		return nil
 	}
-	return audit.Union(existing, newBackend)
+	return audit.RoutedUnion(named...)
 }
 
 type AuditOptions struct {
 	// Policy configuration file for filtering audit events that are captured.
 	// If unspecified, a default is provided.
 	PolicyFile string
+	// PolicyFileReload enables reloading the policy file whenever it changes.
+	PolicyFileReload bool
+	// ShutdownTimeout bounds the time the audit backends are given to deliver
+	// pending events during graceful termination. No bound if zero.
+	ShutdownTimeout time.Duration
+
+	// EventAnnotations are added to every audit event, e.g. the name of the
+	// cluster, the region or the identity of the node.
+	EventAnnotations map[string]string
+	// AnnotateRequestLatency annotates audit events with the latency of the
+	// request as of their stage.
+	AnnotateRequestLatency bool
+	// EventMutators enrich audit events before they are written by the
+	// backends, after the annotations above. Not configurable by flags.
+	EventMutators []audit.EventMutator
 
 	// Plugin options
-	LogOptions     AuditLogOptions
-	WebhookOptions AuditWebhookOptions
+	LogOptions        AuditLogOptions
+	WebhookOptions    AuditWebhookOptions
+	DynamicOptions    AuditDynamicOptions
+	KafkaOptions      AuditKafkaOptions
+	S3Options         AuditS3Options
+	OTLPOptions       AuditOTLPOptions
+	SyslogOptions     AuditSyslogOptions
+	UnixSocketOptions AuditUnixSocketOptions
 }
 
 const (
@@ -112,6 +174,25 @@ type AuditTruncateOptions struct {
 
 	// Truncating configuration.
 	TruncateConfig plugintruncate.Config
+
+	// Strategies applied in order to events larger than the max event size.
+	Strategies []string
+}
+
+type AuditDedupOptions struct {
+	// Whether coalescing identical Metadata-level events is enabled or not.
+	Enabled bool
+
+	// Deduplicating configuration.
+	DedupConfig plugindedup.Config
+}
+
+type AuditRateLimitOptions struct {
+	// Whether rate limiting the events of each user is enabled or not.
+	Enabled bool
+
+	// Rate limiting configuration.
+	RateLimitConfig pluginratelimit.Config
 }
 
 // AuditLogOptions determines the output of the structured audit log by default.
@@ -123,8 +204,35 @@ type AuditLogOptions struct {
 	Format     string
 	Compress   bool
 
-	BatchOptions    AuditBatchOptions
-	TruncateOptions AuditTruncateOptions
+	// RotateCommand, if set, is the executable run with the path of the log
+	// file after each rotation of the log file.
+	RotateCommand string
+
+	// HashChain links the events written to the log in a hash chain. If
+	// ChainSigningKeyFile is set, checkpoints of the chain are signed with the
+	// key at most every ChainCheckpointInterval.
+	HashChain               bool
+	ChainSigningKeyFile     string
+	ChainCheckpointInterval time.Duration
+
+	// EncryptionProviderConfigFile is the encryption configuration of which
+	// the providers of the events.audit.k8s.io resource encrypt the log.
+	EncryptionProviderConfigFile string
+
+	// OmitStages is the stages of the events that are not written to the log,
+	// in addition to the stages omitted by the audit policy.
+	OmitStages []string
+	// MaxLevel, if set, is the highest level of the events written to the log.
+	// Verbs and Namespaces, if set, are the only verbs and namespaces of the
+	// events written to the log.
+	MaxLevel   string
+	Verbs      []string
+	Namespaces []string
+
+	BatchOptions     AuditBatchOptions
+	TruncateOptions  AuditTruncateOptions
+	DedupOptions     AuditDedupOptions
+	RateLimitOptions AuditRateLimitOptions
 
 	// API group version used for serializing audit events.
 	GroupVersionString string
@@ -135,8 +243,57 @@ type AuditWebhookOptions struct {
 	ConfigFile     string
 	InitialBackoff time.Duration
 
-	BatchOptions    AuditBatchOptions
-	TruncateOptions AuditTruncateOptions
+	// BackoffFactor, BackoffJitter and MaxAttempts configure the exponential
+	// backoff of retrying to send a batch of events.
+	BackoffFactor float64
+	BackoffJitter float64
+	MaxAttempts   int
+	// RetryBudget is the maximum time spent sending a batch of events,
+	// including retries. No limit if zero.
+	RetryBudget time.Duration
+	// RequestReceivedTimeout and ResponseCompleteTimeout, if set, are the
+	// maximum time spent sending the events of these stages in the blocking
+	// modes, overriding RetryBudget. RequestBudget, if set, is the maximum
+	// time spent sending all the events of a request in the blocking modes.
+	RequestReceivedTimeout  time.Duration
+	ResponseCompleteTimeout time.Duration
+	RequestBudget           time.Duration
+
+	// CircuitBreakerFailureThreshold is the number of consecutive batches
+	// failing to be sent that stops sending events for CircuitBreakerOpenDuration.
+	// The circuit breaker is disabled if zero.
+	CircuitBreakerFailureThreshold int
+	CircuitBreakerOpenDuration     time.Duration
+
+	// SecondaryConfigFile, if set, defines the webhook batches of events are
+	// sent to after FailoverThreshold consecutive batches failed to be sent
+	// to the webhook, until it recovers. The webhook is probed for recovery
+	// every FailoverProbeInterval.
+	SecondaryConfigFile   string
+	FailoverThreshold     int
+	FailoverProbeInterval time.Duration
+
+	// ContentType is the media type batches of events are sent as.
+	ContentType string
+	// Compression is the content encoding batches of events of at least
+	// CompressionThreshold bytes are compressed with, none if empty.
+	Compression          string
+	CompressionThreshold int
+
+	// OmitStages is the stages of the events that are not sent to the webhook,
+	// in addition to the stages omitted by the audit policy.
+	OmitStages []string
+	// MaxLevel, if set, is the highest level of the events sent to the webhook.
+	// Verbs and Namespaces, if set, are the only verbs and namespaces of the
+	// events sent to the webhook.
+	MaxLevel   string
+	Verbs      []string
+	Namespaces []string
+
+	BatchOptions     AuditBatchOptions
+	TruncateOptions  AuditTruncateOptions
+	DedupOptions     AuditDedupOptions
+	RateLimitOptions AuditRateLimitOptions
 
 	// API group version used for serializing audit events.
 	GroupVersionString string
@@ -147,6 +304,11 @@ type AuditDynamicOptions struct {
 	// Enabled tells whether the dynamic audit capability is enabled.
 	Enabled bool
 
+	// ConfigFile is the path to the file of the audit sinks, which is re-read
+	// while the apiserver is running. If empty, the AuditSink objects are
+	// watched through the API instead.
+	ConfigFile string
+
 	// Configuration for batching backend. This is currently only used as an override
 	// for integration tests
 	BatchConfig *pluginbuffered.BatchConfig
@@ -155,23 +317,41 @@ type AuditDynamicOptions struct {
 func NewAuditOptions() *AuditOptions {
 	return &AuditOptions{
 		WebhookOptions: AuditWebhookOptions{
-			InitialBackoff: pluginwebhook.DefaultInitialBackoffDelay,
+			InitialBackoff:             pluginwebhook.DefaultInitialBackoffDelay,
+			BackoffFactor:              pluginwebhook.DefaultBackoffFactor,
+			BackoffJitter:              pluginwebhook.DefaultBackoffJitter,
+			MaxAttempts:                pluginwebhook.DefaultMaxAttempts,
+			CircuitBreakerOpenDuration: pluginwebhook.DefaultCircuitBreakerOpenDuration,
+			FailoverThreshold:          pluginwebhook.DefaultFailoverThreshold,
+			FailoverProbeInterval:      pluginwebhook.DefaultFailoverProbeInterval,
+			ContentType:                pluginwebhook.ContentTypeJSON,
+			CompressionThreshold:       pluginwebhook.DefaultCompressionThreshold,
 			BatchOptions: AuditBatchOptions{
 				Mode:        ModeBatch,
 				BatchConfig: defaultWebhookBatchConfig(),
 			},
 			TruncateOptions:    NewAuditTruncateOptions(),
+			DedupOptions:       NewAuditDedupOptions(),
+			RateLimitOptions:   NewAuditRateLimitOptions(),
 			GroupVersionString: "audit.k8s.io/v1",
 		},
 		LogOptions: AuditLogOptions{
-			Format: pluginlog.FormatJson,
+			Format:                  pluginlog.FormatJson,
+			ChainCheckpointInterval: pluginlog.DefaultChainCheckpointInterval,
 			BatchOptions: AuditBatchOptions{
 				Mode:        ModeBlocking,
 				BatchConfig: defaultLogBatchConfig(),
 			},
 			TruncateOptions:    NewAuditTruncateOptions(),
+			DedupOptions:       NewAuditDedupOptions(),
+			RateLimitOptions:   NewAuditRateLimitOptions(),
 			GroupVersionString: "audit.k8s.io/v1",
 		},
+		KafkaOptions:      NewAuditKafkaOptions(),
+		S3Options:         NewAuditS3Options(),
+		OTLPOptions:       NewAuditOTLPOptions(),
+		SyslogOptions:     NewAuditSyslogOptions(),
+		UnixSocketOptions: NewAuditUnixSocketOptions(),
 	}
 }
 
@@ -182,6 +362,28 @@ func NewAuditTruncateOptions() AuditTruncateOptions {
 			MaxBatchSize: 10 * 1024 * 1024, // 10MB
 			MaxEventSize: 100 * 1024,       // 100KB
 		},
+		Strategies: []string{string(plugintruncate.StrategyStripObjects)},
+	}
+}
+
+func NewAuditDedupOptions() AuditDedupOptions {
+	return AuditDedupOptions{
+		Enabled: false,
+		DedupConfig: plugindedup.Config{
+			Window:     defaultDedupWindow,
+			MaxPending: defaultDedupMaxPending,
+		},
+	}
+}
+
+func NewAuditRateLimitOptions() AuditRateLimitOptions {
+	return AuditRateLimitOptions{
+		Enabled: false,
+		RateLimitConfig: pluginratelimit.Config{
+			QPS:            defaultRateLimitQPS,
+			Burst:          defaultRateLimitBurst,
+			ReportInterval: defaultRateLimitReportInterval,
+		},
 	}
 }
 
@@ -194,10 +396,57 @@ func (o *AuditOptions) Validate() []error {
 	var allErrors []error
 	allErrors = append(allErrors, o.LogOptions.Validate()...)
 	allErrors = append(allErrors, o.WebhookOptions.Validate()...)
+	allErrors = append(allErrors, o.DynamicOptions.Validate()...)
+	allErrors = append(allErrors, o.KafkaOptions.Validate()...)
+	allErrors = append(allErrors, o.S3Options.Validate()...)
+	allErrors = append(allErrors, o.OTLPOptions.Validate()...)
+	allErrors = append(allErrors, o.SyslogOptions.Validate()...)
+	allErrors = append(allErrors, o.UnixSocketOptions.Validate()...)
+
+	if o.ShutdownTimeout < 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit shutdown timeout %v, must not be negative", o.ShutdownTimeout))
+	}
+	for key := range o.EventAnnotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			allErrors = append(allErrors, fmt.Errorf("invalid audit event annotation key %q: %s", key, strings.Join(errs, "; ")))
+		}
+	}
+
+	allErrors = append(allErrors, o.validateWALDirs()...)
 
 	return allErrors
 }
 
+// validateWALDirs checks that the enabled backends do not share a write-ahead log directory.
+func (o *AuditOptions) validateWALDirs() []error {
+	var allErrors []error
+	backends := map[string]bool{}
+	for _, b := range []struct {
+		pluginName string
+		enabled    bool
+		batch      AuditBatchOptions
+	}{
+		{pluginlog.PluginName, o.LogOptions.enabled(), o.LogOptions.BatchOptions},
+		{pluginwebhook.PluginName, o.WebhookOptions.enabled(), o.WebhookOptions.BatchOptions},
+		{pluginkafka.PluginName, o.KafkaOptions.enabled(), o.KafkaOptions.BatchOptions},
+		{plugins3.PluginName, o.S3Options.enabled(), o.S3Options.BatchOptions},
+		{pluginotlp.PluginName, o.OTLPOptions.enabled(), o.OTLPOptions.BatchOptions},
+		{pluginsyslog.PluginName, o.SyslogOptions.enabled(), o.SyslogOptions.BatchOptions},
+		{pluginunixsocket.PluginName, o.UnixSocketOptions.enabled(), o.UnixSocketOptions.BatchOptions},
+	} {
+		dir := b.batch.walDir()
+		if !b.enabled || dir == "" {
+			continue
+		}
+		dir = filepath.Clean(dir)
+		if backends[dir] {
+			allErrors = append(allErrors, fmt.Errorf("the audit %s backend must not share the write-ahead log directory %s with another backend", b.pluginName, dir))
+		}
+		backends[dir] = true
+	}
+	return allErrors
+}
+
 func validateBackendMode(pluginName string, mode string) error {
 	for _, m := range AllowedModes {
 		if m == mode {
@@ -230,6 +479,22 @@ func validateBackendBatchOptions(pluginName string, options AuditBatchOptions) e
 			return fmt.Errorf("invalid audit batch %s throttle burst %v, must be a positive number", pluginName, config.ThrottleBurst)
 		}
 	}
+	switch config.OverflowPolicy {
+	case pluginbuffered.OverflowPolicyDrop:
+	case pluginbuffered.OverflowPolicyBlock:
+		if config.MaxBlockDuration < 0 {
+			return fmt.Errorf("invalid audit batch %s max block duration %v, must not be negative", pluginName, config.MaxBlockDuration)
+		}
+	case pluginbuffered.OverflowPolicySpillToDisk:
+		if config.WALDir == "" {
+			return fmt.Errorf("audit batch %s write-ahead log directory must be specified for overflow policy %s", pluginName, config.OverflowPolicy)
+		}
+		if config.WALMaxSize <= 0 {
+			return fmt.Errorf("invalid audit batch %s write-ahead log max size %v, must be a positive number", pluginName, config.WALMaxSize)
+		}
+	default:
+		return fmt.Errorf("invalid audit batch %s overflow policy %s, allowed policies are %q", pluginName, config.OverflowPolicy, strings.Join(pluginbuffered.AllowedOverflowPolicies, ","))
+	}
 	return nil
 }
 
@@ -251,6 +516,52 @@ func validateGroupVersionString(groupVersion string) error {
 	return nil
 }
 
+func validateOmitStages(pluginName string, stages []string) error {
+	for _, stage := range stages {
+		if !auditinternal.Stage(stage).IsKnown() {
+			return fmt.Errorf("invalid audit %s omit stage %q, known stages are %q", pluginName, stage, strings.Join(knownStages(), ","))
+		}
+	}
+	return nil
+}
+
+func knownStages() []string {
+	return []string{
+		string(auditinternal.StageRequestReceived),
+		string(auditinternal.StageResponseStarted),
+		string(auditinternal.StageResponseComplete),
+		string(auditinternal.StagePanic),
+	}
+}
+
+func validateMaxLevel(pluginName string, level string) error {
+	if level == "" {
+		return nil
+	}
+	for _, known := range knownMaxLevels() {
+		if level == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid audit %s max level %q, known levels are %q", pluginName, level, strings.Join(knownMaxLevels(), ","))
+}
+
+func knownMaxLevels() []string {
+	return []string{
+		string(auditinternal.LevelMetadata),
+		string(auditinternal.LevelRequest),
+		string(auditinternal.LevelRequestResponse),
+	}
+}
+
+func toStages(stages []string) []auditinternal.Stage {
+	var result []auditinternal.Stage
+	for _, stage := range stages {
+		result = append(result, auditinternal.Stage(stage))
+	}
+	return result
+}
+
 func knownGroupVersion(gv schema.GroupVersion) bool {
 	for _, knownGv := range knownGroupVersions {
 		if gv == knownGv {
@@ -267,13 +578,47 @@ func (o *AuditOptions) AddFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&o.PolicyFile, "audit-policy-file", o.PolicyFile,
 		"Path to the file that defines the audit policy configuration.")
+	fs.BoolVar(&o.PolicyFileReload, "audit-policy-file-reload", o.PolicyFileReload,
+		"If true, the audit policy file is watched and reloaded whenever it changes. "+
+			"A policy that fails to load keeps the previously loaded policy in effect.")
+	fs.DurationVar(&o.ShutdownTimeout, "audit-shutdown-timeout", o.ShutdownTimeout,
+		"The maximum time the audit backends are given to deliver pending events once in-flight requests "+
+			"are drained during graceful termination. Events not delivered by then are lost. "+
+			"If zero, the apiserver waits until all pending events are delivered.")
+	fs.Var(cliflag.NewMapStringString(&o.EventAnnotations), "audit-event-annotations",
+		"A set of key=value pairs added as annotations to every audit event, e.g. the name of the cluster, "+
+			"the region or the identity of the node. Annotations set by the apiserver take precedence.")
+	fs.BoolVar(&o.AnnotateRequestLatency, "audit-annotate-request-latency", o.AnnotateRequestLatency,
+		"If true, audit events past the RequestReceived stage are annotated with the latency of the request "+
+			"as of their stage, as "+audit.RequestLatencyAnnotationKey+".")
 
 	o.LogOptions.AddFlags(fs)
 	o.LogOptions.BatchOptions.AddFlags(pluginlog.PluginName, fs)
 	o.LogOptions.TruncateOptions.AddFlags(pluginlog.PluginName, fs)
+	o.LogOptions.DedupOptions.AddFlags(pluginlog.PluginName, fs)
+	o.LogOptions.RateLimitOptions.AddFlags(pluginlog.PluginName, fs)
 	o.WebhookOptions.AddFlags(fs)
 	o.WebhookOptions.BatchOptions.AddFlags(pluginwebhook.PluginName, fs)
 	o.WebhookOptions.TruncateOptions.AddFlags(pluginwebhook.PluginName, fs)
+	o.WebhookOptions.DedupOptions.AddFlags(pluginwebhook.PluginName, fs)
+	o.WebhookOptions.RateLimitOptions.AddFlags(pluginwebhook.PluginName, fs)
+	o.DynamicOptions.AddFlags(fs)
+	o.KafkaOptions.AddFlags(fs)
+	o.KafkaOptions.TLSOptions.AddFlags(pluginkafka.PluginName, fs)
+	o.KafkaOptions.BatchOptions.AddFlags(pluginkafka.PluginName, fs)
+	o.KafkaOptions.TruncateOptions.AddFlags(pluginkafka.PluginName, fs)
+	o.S3Options.AddFlags(fs)
+	o.S3Options.BatchOptions.AddFlags(plugins3.PluginName, fs)
+	o.OTLPOptions.AddFlags(fs)
+	o.OTLPOptions.TLSOptions.AddFlags(pluginotlp.PluginName, fs)
+	o.OTLPOptions.BatchOptions.AddFlags(pluginotlp.PluginName, fs)
+	o.OTLPOptions.TruncateOptions.AddFlags(pluginotlp.PluginName, fs)
+	o.SyslogOptions.AddFlags(fs)
+	o.SyslogOptions.TLSOptions.AddFlags(pluginsyslog.PluginName, fs)
+	o.SyslogOptions.BatchOptions.AddFlags(pluginsyslog.PluginName, fs)
+	o.SyslogOptions.TruncateOptions.AddFlags(pluginsyslog.PluginName, fs)
+	o.UnixSocketOptions.AddFlags(fs)
+	o.UnixSocketOptions.BatchOptions.AddFlags(pluginunixsocket.PluginName, fs)
 }
 
 func (o *AuditOptions) ApplyTo(
@@ -302,7 +647,24 @@ func (o *AuditOptions) ApplyTo(
 		if evaluator == nil {
 			klog.V(2).Info("No audit policy file provided, no events will be recorded for log backend")
 		} else {
-			logBackend = o.LogOptions.newBackend(w)
+			var stopCh <-chan struct{}
+			if o.LogOptions.EncryptionProviderConfigFile != "" {
+				// Stop the KMS plugins of the encryption providers once requests are drained.
+				stopCh = c.DrainedNotify()
+			}
+			logBackend, err = o.LogOptions.newBackend(w, stopCh)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// The backends sending events to remote servers dial through the control plane egress.
+	var egressDialer utilnet.DialFunc
+	if c.EgressSelector != nil {
+		egressDialer, err = c.EgressSelector.Lookup(egressselector.ControlPlane.AsNetworkContext())
+		if err != nil {
+			return err
 		}
 	}
 
@@ -312,16 +674,7 @@ func (o *AuditOptions) ApplyTo(
 		if evaluator == nil {
 			klog.V(2).Info("No audit policy file provided, no events will be recorded for webhook backend")
 		} else {
-			if c.EgressSelector != nil {
-				var egressDialer utilnet.DialFunc
-				egressDialer, err = c.EgressSelector.Lookup(egressselector.ControlPlane.AsNetworkContext())
-				if err != nil {
-					return err
-				}
-				webhookBackend, err = o.WebhookOptions.newUntruncatedBackend(egressDialer)
-			} else {
-				webhookBackend, err = o.WebhookOptions.newUntruncatedBackend(nil)
-			}
+			webhookBackend, err = o.WebhookOptions.newUntruncatedBackend(egressDialer)
 			if err != nil {
 				return err
 			}
@@ -338,30 +691,139 @@ func (o *AuditOptions) ApplyTo(
 	if webhookBackend != nil {
 		// if only webhook is enabled wrap it in the truncate options
 		dynamicBackend = o.WebhookOptions.TruncateOptions.wrapBackend(webhookBackend, groupVersion)
+		dynamicBackend = o.WebhookOptions.DedupOptions.wrapBackend(dynamicBackend)
+		dynamicBackend = o.WebhookOptions.RateLimitOptions.wrapBackend(dynamicBackend)
+	}
+
+	// 5. Build the backend of the dynamic audit sinks
+	var sinksBackend audit.Backend
+	if o.DynamicOptions.Enabled {
+		if evaluator == nil {
+			klog.V(2).Info("No audit policy file provided, no events will be recorded for dynamic audit sinks")
+		} else {
+			sinksBackend, err = o.DynamicOptions.newBackend(o.WebhookOptions.retryConfig(), c.LoopbackClientConfig)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// 6. Build the backends of the other sinks
+	kafkaBackend, err := newSinkBackend(pluginkafka.PluginName, o.KafkaOptions.enabled(), evaluator, func() (audit.Backend, error) {
+		return o.KafkaOptions.newBackend(egressDialer)
+	})
+	if err != nil {
+		return err
+	}
+	s3Backend, err := newSinkBackend(plugins3.PluginName, o.S3Options.enabled(), evaluator, func() (audit.Backend, error) {
+		return o.S3Options.newBackend(egressDialer, c.APIServerID)
+	})
+	if err != nil {
+		return err
+	}
+	otlpBackend, err := newSinkBackend(pluginotlp.PluginName, o.OTLPOptions.enabled(), evaluator, func() (audit.Backend, error) {
+		return o.OTLPOptions.newBackend(egressDialer, c.APIServerID)
+	})
+	if err != nil {
+		return err
+	}
+	syslogBackend, err := newSinkBackend(pluginsyslog.PluginName, o.SyslogOptions.enabled(), evaluator, func() (audit.Backend, error) {
+		return o.SyslogOptions.newBackend(egressDialer)
+	})
+	if err != nil {
+		return err
+	}
+	unixSocketBackend, err := newSinkBackend(pluginunixsocket.PluginName, o.UnixSocketOptions.enabled(), evaluator, o.UnixSocketOptions.newBackend)
+	if err != nil {
+		return err
 	}
 
-	// 5. Set the policy rule evaluator
+	// 7. Set the policy rule evaluator
 	c.AuditPolicyRuleEvaluator = evaluator
+	c.AuditShutdownTimeout = o.ShutdownTimeout
+	if dynamicEvaluator, ok := evaluator.(*policy.DynamicPolicyRuleEvaluator); ok {
+		c.AddPostStartHookOrDie("audit-policy-file-reloader", func(context server.PostStartHookContext) error {
+			ctx, cancel := wait.ContextForChannel(context.StopCh)
+			go func() {
+				defer cancel()
+				dynamicEvaluator.Run(ctx, 1)
+			}()
+			return nil
+		})
+	}
 
-	// 6. Join the log backend with the webhooks
-	c.AuditBackend = appendBackend(logBackend, dynamicBackend)
+	// 8. Join the log backend with the webhooks and the other sinks, enriching the events of all of them
+	c.AuditBackend = unionBackends(
+		o.LogOptions.namedBackend(logBackend),
+		o.WebhookOptions.namedBackend(dynamicBackend),
+		o.DynamicOptions.namedBackend(sinksBackend),
+		o.KafkaOptions.namedBackend(kafkaBackend),
+		o.S3Options.namedBackend(s3Backend),
+		o.OTLPOptions.namedBackend(otlpBackend),
+		o.SyslogOptions.namedBackend(syslogBackend),
+		o.UnixSocketOptions.namedBackend(unixSocketBackend),
+	)
+	if c.AuditBackend != nil {
+		c.AuditBackend = audit.NewMutatingBackend(c.AuditBackend, o.eventMutators()...)
+	}
 
 	if c.AuditBackend != nil {
 		klog.V(2).Infof("Using audit backend: %s", c.AuditBackend)
+
+		// Not a liveness check, restarting the apiserver does not make the backend healthy.
+		backend := c.AuditBackend
+		check := healthz.NamedCheck(auditBackendHealthCheckName, func(_ *http.Request) error {
+			return audit.CheckHealth(backend)
+		})
+		c.HealthzChecks = append(c.HealthzChecks, check)
+		c.AddReadyzChecks(check)
 	}
 	return nil
 }
 
+// newSinkBackend returns the backend of a sink built by newBackend, or nil if
+// the sink is not enabled or there is no audit policy to record events by.
+func newSinkBackend(pluginName string, enabled bool, evaluator audit.PolicyRuleEvaluator, newBackend func() (audit.Backend, error)) (audit.Backend, error) {
+	if !enabled {
+		return nil, nil
+	}
+	if evaluator == nil {
+		klog.V(2).Infof("No audit policy file provided, no events will be recorded for %s backend", pluginName)
+		return nil, nil
+	}
+	return newBackend()
+}
+
+// eventMutators returns the mutators enriching audit events, in order.
+func (o *AuditOptions) eventMutators() []audit.EventMutator {
+	var mutators []audit.EventMutator
+	if len(o.EventAnnotations) > 0 {
+		mutators = append(mutators, audit.StaticAnnotationsMutator(o.EventAnnotations))
+	}
+	if o.AnnotateRequestLatency {
+		mutators = append(mutators, audit.RequestLatencyMutator)
+	}
+	return append(mutators, o.EventMutators...)
+}
+
 func (o *AuditOptions) newPolicyRuleEvaluator() (audit.PolicyRuleEvaluator, error) {
 	if o.PolicyFile == "" {
 		return nil, nil
 	}
 
+	if o.PolicyFileReload {
+		evaluator, err := policy.NewDynamicPolicyRuleEvaluator(o.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading audit policy file: %v", err)
+		}
+		return evaluator, nil
+	}
+
 	p, err := policy.LoadPolicyFromFile(o.PolicyFile)
 	if err != nil {
 		return nil, fmt.Errorf("loading audit policy file: %v", err)
 	}
-	return policy.NewPolicyRuleEvaluator(p), nil
+	return policy.NewServerPolicyRuleEvaluator(p), nil
 }
 
 func (o *AuditBatchOptions) AddFlags(pluginName string, fs *pflag.FlagSet) {
@@ -385,6 +847,30 @@ func (o *AuditBatchOptions) AddFlags(pluginName string, fs *pflag.FlagSet) {
 	fs.IntVar(&o.BatchConfig.ThrottleBurst, fmt.Sprintf("audit-%s-batch-throttle-burst", pluginName),
 		o.BatchConfig.ThrottleBurst, "Maximum number of requests sent at the same "+
 			"moment if ThrottleQPS was not utilized before. Only used in batch mode.")
+	fs.StringVar((*string)(&o.BatchConfig.OverflowPolicy), fmt.Sprintf("audit-%s-batch-overflow-policy", pluginName),
+		string(o.BatchConfig.OverflowPolicy), "What happens to events when the buffer is full. "+
+			"drop drops them. block blocks requests until the events are buffered, for at most "+
+			"the max block duration. spill-to-disk stores events in a write-ahead log before "+
+			"batching, so they are not dropped and survive restarts of the apiserver; events "+
+			"may be sent twice after a restart. Known policies are "+
+			strings.Join(pluginbuffered.AllowedOverflowPolicies, ",")+". Only used in batch mode.")
+	fs.DurationVar(&o.BatchConfig.MaxBlockDuration, fmt.Sprintf("audit-%s-batch-max-block-duration", pluginName),
+		o.BatchConfig.MaxBlockDuration, "The maximum time a request is blocked for its events "+
+			"to be buffered, zero meaning no limit. Only used with the block overflow policy.")
+	fs.StringVar(&o.BatchConfig.WALDir, fmt.Sprintf("audit-%s-batch-wal-dir", pluginName),
+		o.BatchConfig.WALDir, "Directory of the write-ahead log. Only used with the "+
+			"spill-to-disk overflow policy.")
+	fs.Int64Var(&o.BatchConfig.WALMaxSize, fmt.Sprintf("audit-%s-batch-wal-max-size", pluginName),
+		o.BatchConfig.WALMaxSize, "The maximum size in bytes of the write-ahead log. The oldest "+
+			"events are dropped when it is exceeded. Only used with the spill-to-disk overflow policy.")
+}
+
+// walDir returns the directory of the write-ahead log used, if any.
+func (o *AuditBatchOptions) walDir() string {
+	if o.Mode != ModeBatch || o.BatchConfig.OverflowPolicy != pluginbuffered.OverflowPolicySpillToDisk {
+		return ""
+	}
+	return o.BatchConfig.WALDir
 }
 
 type ignoreErrorsBackend struct {
@@ -396,6 +882,10 @@ func (i *ignoreErrorsBackend) ProcessEvents(ev ...*auditinternal.Event) bool {
 	return true
 }
 
+func (i *ignoreErrorsBackend) HealthCheck() error {
+	return audit.CheckHealth(i.Backend)
+}
+
 func (i *ignoreErrorsBackend) String() string {
 	return fmt.Sprintf("ignoreErrors<%s>", i.Backend)
 }
@@ -419,6 +909,12 @@ func (o *AuditTruncateOptions) Validate(pluginName string) error {
 		return fmt.Errorf("invalid audit truncate %s max batch size %v, must be greater than "+
 			"max event size (%v)", pluginName, config.MaxBatchSize, config.MaxEventSize)
 	}
+	for _, strategy := range o.Strategies {
+		if !sets.NewString(plugintruncate.AllowedStrategies...).Has(strategy) {
+			return fmt.Errorf("invalid audit truncate %s strategy %q, allowed strategies are %q",
+				pluginName, strategy, strings.Join(plugintruncate.AllowedStrategies, ","))
+		}
+	}
 	return nil
 }
 
@@ -431,15 +927,86 @@ func (o *AuditTruncateOptions) AddFlags(pluginName string, fs *pflag.FlagSet) {
 			"it is split into several batches of smaller size.")
 	fs.Int64Var(&o.TruncateConfig.MaxEventSize, fmt.Sprintf("audit-%s-truncate-max-event-size", pluginName),
 		o.TruncateConfig.MaxEventSize, "Maximum size of the audit event sent to the underlying backend. "+
-			"If the size of an event is greater than this number, first request and response are removed, and "+
+			"If the size of an event is greater than this number, the truncate strategies are applied in order, and "+
 			"if this doesn't reduce the size enough, event is discarded.")
+	fs.StringSliceVar(&o.Strategies, fmt.Sprintf("audit-%s-truncate-strategies", pluginName),
+		o.Strategies, "Comma separated list of strategies applied in order to audit events larger than the "+
+			"max event size, until they fit. Known strategies are "+strings.Join(plugintruncate.AllowedStrategies, ",")+".")
 }
 
 func (o *AuditTruncateOptions) wrapBackend(delegate audit.Backend, gv schema.GroupVersion) audit.Backend {
 	if !o.Enabled {
 		return delegate
 	}
-	return plugintruncate.NewBackend(delegate, o.TruncateConfig, gv)
+	config := o.TruncateConfig
+	config.Strategies = nil
+	for _, strategy := range o.Strategies {
+		config.Strategies = append(config.Strategies, plugintruncate.Strategy(strategy))
+	}
+	return plugintruncate.NewBackend(delegate, config, gv)
+}
+
+func (o *AuditDedupOptions) Validate(pluginName string) error {
+	config := o.DedupConfig
+	if config.Window <= 0 {
+		return fmt.Errorf("invalid audit dedup %s window %v, must be a positive duration", pluginName, config.Window)
+	}
+	if config.MaxPending <= 0 {
+		return fmt.Errorf("invalid audit dedup %s max pending %v, must be a positive number", pluginName, config.MaxPending)
+	}
+	return nil
+}
+
+func (o *AuditDedupOptions) AddFlags(pluginName string, fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enabled, fmt.Sprintf("audit-%s-dedup-enabled", pluginName),
+		o.Enabled, "Whether identical Metadata-level events, of the same stage, user, verb, object and response code, "+
+			"are coalesced into the first of them, annotated with their count.")
+	fs.DurationVar(&o.DedupConfig.Window, fmt.Sprintf("audit-%s-dedup-window", pluginName),
+		o.DedupConfig.Window, "The time identical events are coalesced for. Coalesced events are delayed by up to this duration.")
+	fs.IntVar(&o.DedupConfig.MaxPending, fmt.Sprintf("audit-%s-dedup-max-pending", pluginName),
+		o.DedupConfig.MaxPending, "The maximum number of distinct events coalesced at a time. "+
+			"Further distinct events are not coalesced.")
+}
+
+func (o *AuditDedupOptions) wrapBackend(delegate audit.Backend) audit.Backend {
+	if !o.Enabled {
+		return delegate
+	}
+	return plugindedup.NewBackend(delegate, o.DedupConfig)
+}
+
+func (o *AuditRateLimitOptions) Validate(pluginName string) error {
+	config := o.RateLimitConfig
+	if config.QPS <= 0 {
+		return fmt.Errorf("invalid audit user rate limit %s qps %v, must be a positive number", pluginName, config.QPS)
+	}
+	if config.Burst <= 0 {
+		return fmt.Errorf("invalid audit user rate limit %s burst %v, must be a positive number", pluginName, config.Burst)
+	}
+	if config.ReportInterval <= 0 {
+		return fmt.Errorf("invalid audit user rate limit %s report interval %v, must be a positive duration", pluginName, config.ReportInterval)
+	}
+	return nil
+}
+
+func (o *AuditRateLimitOptions) AddFlags(pluginName string, fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enabled, fmt.Sprintf("audit-%s-user-ratelimit-enabled", pluginName),
+		o.Enabled, "Whether the requests of each user, identified by username, are rate limited. All events of "+
+			"requests exceeding the rate are dropped, and their number is reported in an event of the user annotated with "+
+			pluginratelimit.SuppressedCountAnnotationKey+".")
+	fs.Float32Var(&o.RateLimitConfig.QPS, fmt.Sprintf("audit-%s-user-ratelimit-qps", pluginName),
+		o.RateLimitConfig.QPS, "The maximum average number of requests per second of each user whose events are sent.")
+	fs.IntVar(&o.RateLimitConfig.Burst, fmt.Sprintf("audit-%s-user-ratelimit-burst", pluginName),
+		o.RateLimitConfig.Burst, "The maximum number of requests of each user whose events are sent at once.")
+	fs.DurationVar(&o.RateLimitConfig.ReportInterval, fmt.Sprintf("audit-%s-user-ratelimit-report-interval", pluginName),
+		o.RateLimitConfig.ReportInterval, "The interval the number of suppressed events of each user is reported at.")
+}
+
+func (o *AuditRateLimitOptions) wrapBackend(delegate audit.Backend) audit.Backend {
+	if !o.Enabled {
+		return delegate
+	}
+	return pluginratelimit.NewBackend(delegate, o.RateLimitConfig)
 }
 
 func (o *AuditLogOptions) AddFlags(fs *pflag.FlagSet) {
@@ -453,11 +1020,39 @@ func (o *AuditLogOptions) AddFlags(fs *pflag.FlagSet) {
 		"The maximum size in megabytes of the audit log file before it gets rotated.")
 	fs.StringVar(&o.Format, "audit-log-format", o.Format,
 		"Format of saved audits. \"legacy\" indicates 1-line text format for each event."+
-			" \"json\" indicates structured json format. Known formats are "+
+			" \"json\" indicates structured json format. \"logger\" writes each event through the logger of the"+
+			" apiserver, as JSON lines to standard out with --logging-format=json, and requires --audit-log-path=-."+
+			" Known formats are "+
 			strings.Join(pluginlog.AllowedFormats, ",")+".")
 	fs.StringVar(&o.GroupVersionString, "audit-log-version", o.GroupVersionString,
 		"API group and version used for serializing audit events written to log.")
 	fs.BoolVar(&o.Compress, "audit-log-compress", o.Compress, "If set, the rotated log files will be compressed using gzip.")
+	fs.StringVar(&o.RotateCommand, "audit-log-rotate-command", o.RotateCommand,
+		"Path to an executable run after each rotation of the audit log file, with the path of the "+
+			"audit log file as its argument. The rotated files are next to it, named after it with the time of the rotation.")
+	fs.BoolVar(&o.HashChain, "audit-log-hash-chain", o.HashChain,
+		"If set, each event written to the log is annotated with its sequence number and the SHA-256 "+
+			"hash of the previous event, so that modified or removed events can be detected. Requires the json format.")
+	fs.StringVar(&o.ChainSigningKeyFile, "audit-log-chain-signing-key-file", o.ChainSigningKeyFile,
+		"Path to a PEM-encoded private key signing checkpoints of the audit log hash chain. Ed25519, ECDSA and RSA keys are supported.")
+	fs.DurationVar(&o.ChainCheckpointInterval, "audit-log-chain-checkpoint-interval", o.ChainCheckpointInterval,
+		"The minimum amount of time between signed checkpoints of the audit log hash chain.")
+	fs.StringVar(&o.EncryptionProviderConfigFile, "audit-log-encryption-provider-config", o.EncryptionProviderConfigFile,
+		"The file containing configuration for encryption providers, of which the providers of the "+
+			auditLogEncryptionResource.String()+" resource encrypt the events written to the log. "+
+			"Each line of the log holds a base64-encoded encrypted event.")
+	fs.StringSliceVar(&o.OmitStages, "audit-log-omit-stages", o.OmitStages,
+		"Stages of the audit events that are not written to the log, in addition to the stages omitted by the audit policy. Known stages are "+
+			strings.Join(knownStages(), ",")+".")
+	fs.StringVar(&o.MaxLevel, "audit-log-max-level", o.MaxLevel,
+		"The highest level of the audit events written to the log. Events of higher levels are written "+
+			"without the request and response bodies the level does not include. Known levels are "+
+			strings.Join(knownMaxLevels(), ",")+".")
+	fs.StringSliceVar(&o.Verbs, "audit-log-verbs", o.Verbs,
+		"If set, only audit events of these verbs are written to the log.")
+	fs.StringSliceVar(&o.Namespaces, "audit-log-namespaces", o.Namespaces,
+		"If set, only audit events of these namespaces are written to the log. The empty namespace matches "+
+			"cluster-scoped resources and non-resource requests.")
 }
 
 func (o *AuditLogOptions) Validate() []error {
@@ -474,16 +1069,30 @@ func (o *AuditLogOptions) Validate() []error {
 	if err := o.TruncateOptions.Validate(pluginlog.PluginName); err != nil {
 		allErrors = append(allErrors, err)
 	}
+	if err := o.DedupOptions.Validate(pluginlog.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := o.RateLimitOptions.Validate(pluginlog.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
 
 	if err := validateGroupVersionString(o.GroupVersionString); err != nil {
 		allErrors = append(allErrors, err)
 	}
+	if err := validateOmitStages(pluginlog.PluginName, o.OmitStages); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := validateMaxLevel(pluginlog.PluginName, o.MaxLevel); err != nil {
+		allErrors = append(allErrors, err)
+	}
 
 	// Check log format
 	if !sets.NewString(pluginlog.AllowedFormats...).Has(o.Format) {
 		allErrors = append(allErrors, fmt.Errorf("invalid audit log format %s, allowed formats are %q", o.Format, strings.Join(pluginlog.AllowedFormats, ",")))
 	}
 
+	allErrors = append(allErrors, o.validateHashChain()...)
+
 	// Check validities of MaxAge, MaxBackups and MaxSize of log options, if file log backend is enabled.
 	if o.MaxAge < 0 {
 		allErrors = append(allErrors, fmt.Errorf("--audit-log-maxage %v can't be a negative number", o.MaxAge))
@@ -494,15 +1103,55 @@ func (o *AuditLogOptions) Validate() []error {
 	if o.MaxSize < 0 {
 		allErrors = append(allErrors, fmt.Errorf("--audit-log-maxsize %v can't be a negative number", o.MaxSize))
 	}
+	if o.Format == pluginlog.FormatLogger {
+		if o.Path != "-" {
+			allErrors = append(allErrors, fmt.Errorf("the %q audit log format requires --audit-log-path=-", pluginlog.FormatLogger))
+		}
+		if o.EncryptionProviderConfigFile != "" {
+			allErrors = append(allErrors, fmt.Errorf("--audit-log-encryption-provider-config does not support the %q audit log format", pluginlog.FormatLogger))
+		}
+	}
+	if o.RotateCommand != "" && o.Path == "-" {
+		allErrors = append(allErrors, fmt.Errorf("--audit-log-rotate-command requires an --audit-log-path other than standard out"))
+	}
 
 	return allErrors
 }
 
+func (o *AuditLogOptions) validateHashChain() []error {
+	var allErrors []error
+	if !o.HashChain {
+		if o.ChainSigningKeyFile != "" {
+			allErrors = append(allErrors, fmt.Errorf("--audit-log-chain-signing-key-file requires --audit-log-hash-chain"))
+		}
+		return allErrors
+	}
+	if o.Format != pluginlog.FormatJson {
+		allErrors = append(allErrors, fmt.Errorf("--audit-log-hash-chain requires the %q audit log format", pluginlog.FormatJson))
+	}
+	if o.ChainCheckpointInterval <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("--audit-log-chain-checkpoint-interval %v must be a positive duration", o.ChainCheckpointInterval))
+	}
+	return allErrors
+}
+
 // Check whether the log backend is enabled based on the options.
 func (o *AuditLogOptions) enabled() bool {
 	return o != nil && o.Path != ""
 }
 
+// namedBackend returns the log backend with the events it filters.
+func (o *AuditLogOptions) namedBackend(backend audit.Backend) audit.NamedBackend {
+	return audit.NamedBackend{
+		Name:       pluginlog.PluginName,
+		Backend:    backend,
+		OmitStages: toStages(o.OmitStages),
+		MaxLevel:   auditinternal.Level(o.MaxLevel),
+		Verbs:      o.Verbs,
+		Namespaces: o.Namespaces,
+	}
+}
+
 func (o *AuditLogOptions) getWriter() (io.Writer, error) {
 	if !o.enabled() {
 		return nil, nil
@@ -516,13 +1165,30 @@ func (o *AuditLogOptions) getWriter() (io.Writer, error) {
 		return nil, fmt.Errorf("ensureLogFile: %w", err)
 	}
 
-	return &lumberjack.Logger{
-		Filename:   o.Path,
+	return pluginlog.NewRotatingWriter(o.Path, pluginlog.RotateConfig{
 		MaxAge:     o.MaxAge,
 		MaxBackups: o.MaxBackups,
 		MaxSize:    o.MaxSize,
 		Compress:   o.Compress,
-	}, nil
+		OnRotate:   o.onRotate(),
+	})
+}
+
+// onRotate returns the hook running the rotate command after each rotation of
+// the log file, nil if there is none. The command runs in the background, so
+// that it does not hold up the events written to the log.
+func (o *AuditLogOptions) onRotate() func(path string) {
+	if o.RotateCommand == "" {
+		return nil
+	}
+	command := o.RotateCommand
+	return func(path string) {
+		go func() {
+			if out, err := exec.Command(command, path).CombinedOutput(); err != nil {
+				klog.Errorf("Audit log rotate command %s failed: %v, output: %q", command, err, out)
+			}
+		}()
+	}
 }
 
 func (o *AuditLogOptions) ensureLogFile() error {
@@ -537,12 +1203,57 @@ func (o *AuditLogOptions) ensureLogFile() error {
 	return f.Close()
 }
 
-func (o *AuditLogOptions) newBackend(w io.Writer) audit.Backend {
+func (o *AuditLogOptions) newBackend(w io.Writer, stopCh <-chan struct{}) (audit.Backend, error) {
 	groupVersion, _ := schema.ParseGroupVersion(o.GroupVersionString)
-	log := pluginlog.NewBackend(w, o.Format, groupVersion)
+	if o.EncryptionProviderConfigFile != "" {
+		transformers, err := encryptionconfig.GetTransformerOverrides(o.EncryptionProviderConfigFile, stopCh)
+		if err != nil {
+			return nil, err
+		}
+		transformer, ok := transformers[auditLogEncryptionResource]
+		if !ok {
+			return nil, fmt.Errorf("encryption provider configuration %s has no providers for the %s resource",
+				o.EncryptionProviderConfigFile, auditLogEncryptionResource)
+		}
+		w = pluginlog.NewEncryptingWriter(w, transformer)
+	}
+	var log audit.Backend
+	if o.Format == pluginlog.FormatLogger {
+		log = pluginlog.NewLoggerBackend(klog.Background(), groupVersion)
+	} else if o.HashChain {
+		config, err := o.chainConfig()
+		if err != nil {
+			return nil, err
+		}
+		log, err = pluginlog.NewChainedBackend(w, o.Format, groupVersion, config)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		log = pluginlog.NewBackend(w, o.Format, groupVersion)
+	}
 	log = o.BatchOptions.wrapBackend(log)
 	log = o.TruncateOptions.wrapBackend(log, groupVersion)
-	return log
+	log = o.DedupOptions.wrapBackend(log)
+	log = o.RateLimitOptions.wrapBackend(log)
+	return log, nil
+}
+
+func (o *AuditLogOptions) chainConfig() (pluginlog.ChainConfig, error) {
+	config := pluginlog.ChainConfig{CheckpointInterval: o.ChainCheckpointInterval}
+	if o.ChainSigningKeyFile == "" {
+		return config, nil
+	}
+	key, err := keyutil.PrivateKeyFromFile(o.ChainSigningKeyFile)
+	if err != nil {
+		return config, fmt.Errorf("failed to read audit log chain signing key: %v", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return config, fmt.Errorf("audit log chain signing key %s is not a signing key", o.ChainSigningKeyFile)
+	}
+	config.SigningKey = signer
+	return config, nil
 }
 
 func (o *AuditWebhookOptions) AddFlags(fs *pflag.FlagSet) {
@@ -554,8 +1265,62 @@ func (o *AuditWebhookOptions) AddFlags(fs *pflag.FlagSet) {
 		o.InitialBackoff, "The amount of time to wait before retrying the first failed request.")
 	fs.MarkDeprecated("audit-webhook-batch-initial-backoff",
 		"Deprecated, use --audit-webhook-initial-backoff instead.")
+	fs.Float64Var(&o.BackoffFactor, "audit-webhook-backoff-factor", o.BackoffFactor,
+		"The factor the amount of time to wait before retrying a failed request is multiplied by after each retry.")
+	fs.Float64Var(&o.BackoffJitter, "audit-webhook-backoff-jitter", o.BackoffJitter,
+		"The maximum random fraction added to the amount of time to wait before retrying a failed request.")
+	fs.IntVar(&o.MaxAttempts, "audit-webhook-max-attempts", o.MaxAttempts,
+		"The maximum number of attempts to send a batch of events.")
+	fs.DurationVar(&o.RetryBudget, "audit-webhook-retry-budget", o.RetryBudget,
+		"The maximum amount of time spent sending a batch of events, including retries, zero meaning no limit.")
+	fs.DurationVar(&o.RequestReceivedTimeout, "audit-webhook-request-received-timeout", o.RequestReceivedTimeout,
+		"The maximum amount of time spent sending an event of the RequestReceived stage, including retries, "+
+			"in the blocking modes. Overrides --audit-webhook-retry-budget if set.")
+	fs.DurationVar(&o.ResponseCompleteTimeout, "audit-webhook-response-complete-timeout", o.ResponseCompleteTimeout,
+		"The maximum amount of time spent sending an event of the ResponseComplete stage, including retries, "+
+			"in the blocking modes. Overrides --audit-webhook-retry-budget if set.")
+	fs.DurationVar(&o.RequestBudget, "audit-webhook-request-budget", o.RequestBudget,
+		"The maximum amount of time spent sending all the events of a request in the blocking modes, so that "+
+			"audit delivery cannot consume the entire request timeout. Events are dropped once it is spent. "+
+			"Zero means no limit.")
+	fs.IntVar(&o.CircuitBreakerFailureThreshold, "audit-webhook-circuit-breaker-failure-threshold",
+		o.CircuitBreakerFailureThreshold, "The number of consecutive batches failing to be sent after "+
+			"which no events are sent to the webhook for the circuit breaker open duration. Events are "+
+			"dropped meanwhile. The circuit breaker is disabled if zero.")
+	fs.DurationVar(&o.CircuitBreakerOpenDuration, "audit-webhook-circuit-breaker-open-duration",
+		o.CircuitBreakerOpenDuration, "The amount of time no events are sent to a webhook that is down, "+
+			"before a single batch is sent to probe whether it recovered.")
+	fs.StringVar(&o.SecondaryConfigFile, "audit-webhook-secondary-config-file", o.SecondaryConfigFile,
+		"Path to a kubeconfig formatted file that defines a secondary audit webhook, which batches of events "+
+			"are sent to while the audit webhook is down.")
+	fs.IntVar(&o.FailoverThreshold, "audit-webhook-failover-threshold", o.FailoverThreshold,
+		"The number of consecutive batches failing to be sent to the audit webhook after which batches "+
+			"are sent to the secondary audit webhook. Only used with a secondary audit webhook.")
+	fs.DurationVar(&o.FailoverProbeInterval, "audit-webhook-failover-probe-interval", o.FailoverProbeInterval,
+		"The amount of time between batches sent to the audit webhook to probe whether it recovered, "+
+			"while batches are sent to the secondary audit webhook.")
+	fs.StringVar(&o.ContentType, "audit-webhook-content-type", o.ContentType,
+		"Content type batches of events are sent to the webhook as. Protobuf is cheaper to serialize "+
+			"and smaller than JSON. Known content types are "+strings.Join(pluginwebhook.AllowedContentTypes, ",")+".")
+	fs.StringVar(&o.Compression, "audit-webhook-compression", o.Compression,
+		"Content encoding batches of events sent to the webhook are compressed with, none if empty. "+
+			"Known compressions are "+strings.Join(pluginwebhook.AllowedCompressions, ",")+".")
+	fs.IntVar(&o.CompressionThreshold, "audit-webhook-compression-threshold", o.CompressionThreshold,
+		"The size in bytes of the smallest batch of events that is compressed.")
 	fs.StringVar(&o.GroupVersionString, "audit-webhook-version", o.GroupVersionString,
 		"API group and version used for serializing audit events written to webhook.")
+	fs.StringSliceVar(&o.OmitStages, "audit-webhook-omit-stages", o.OmitStages,
+		"Stages of the audit events that are not sent to the webhook, in addition to the stages omitted by the audit policy. Known stages are "+
+			strings.Join(knownStages(), ",")+".")
+	fs.StringVar(&o.MaxLevel, "audit-webhook-max-level", o.MaxLevel,
+		"The highest level of the audit events sent to the webhook. Events of higher levels are sent "+
+			"without the request and response bodies the level does not include. Known levels are "+
+			strings.Join(knownMaxLevels(), ",")+".")
+	fs.StringSliceVar(&o.Verbs, "audit-webhook-verbs", o.Verbs,
+		"If set, only audit events of these verbs are sent to the webhook.")
+	fs.StringSliceVar(&o.Namespaces, "audit-webhook-namespaces", o.Namespaces,
+		"If set, only audit events of these namespaces are sent to the webhook. The empty namespace matches "+
+			"cluster-scoped resources and non-resource requests.")
 }
 
 func (o *AuditWebhookOptions) Validate() []error {
@@ -570,22 +1335,151 @@ func (o *AuditWebhookOptions) Validate() []error {
 	if err := o.TruncateOptions.Validate(pluginwebhook.PluginName); err != nil {
 		allErrors = append(allErrors, err)
 	}
+	if err := o.DedupOptions.Validate(pluginwebhook.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := o.RateLimitOptions.Validate(pluginwebhook.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
 
 	if err := validateGroupVersionString(o.GroupVersionString); err != nil {
 		allErrors = append(allErrors, err)
 	}
+	if err := validateOmitStages(pluginwebhook.PluginName, o.OmitStages); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := validateMaxLevel(pluginwebhook.PluginName, o.MaxLevel); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if !sets.NewString(pluginwebhook.AllowedContentTypes...).Has(o.ContentType) {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook content type %s, allowed content types are %q", o.ContentType, strings.Join(pluginwebhook.AllowedContentTypes, ",")))
+	}
+	if o.Compression != "" && !sets.NewString(pluginwebhook.AllowedCompressions...).Has(o.Compression) {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook compression %s, allowed compressions are %q", o.Compression, strings.Join(pluginwebhook.AllowedCompressions, ",")))
+	}
+	if o.CompressionThreshold < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--audit-webhook-compression-threshold %v can't be a negative number", o.CompressionThreshold))
+	}
+	allErrors = append(allErrors, o.validateRetry()...)
+	if o.SecondaryConfigFile != "" {
+		if o.FailoverThreshold <= 0 {
+			allErrors = append(allErrors, fmt.Errorf("invalid audit webhook failover threshold %v, must be a positive number", o.FailoverThreshold))
+		}
+		if o.FailoverProbeInterval <= 0 {
+			allErrors = append(allErrors, fmt.Errorf("invalid audit webhook failover probe interval %v, must be a positive duration", o.FailoverProbeInterval))
+		}
+	}
+	return allErrors
+}
+
+func (o *AuditWebhookOptions) encodingConfig() pluginwebhook.EncodingConfig {
+	return pluginwebhook.EncodingConfig{
+		ContentType:          o.ContentType,
+		Compression:          o.Compression,
+		CompressionThreshold: o.CompressionThreshold,
+	}
+}
+
+func (o *AuditWebhookOptions) validateRetry() []error {
+	var allErrors []error
+	if o.BackoffFactor < 1 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook backoff factor %v, must not be less than 1", o.BackoffFactor))
+	}
+	if o.BackoffJitter < 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook backoff jitter %v, must not be negative", o.BackoffJitter))
+	}
+	if o.MaxAttempts <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook max attempts %v, must be a positive number", o.MaxAttempts))
+	}
+	if o.RetryBudget < 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook retry budget %v, must not be negative", o.RetryBudget))
+	}
+	if o.RequestReceivedTimeout < 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook request received timeout %v, must not be negative", o.RequestReceivedTimeout))
+	}
+	if o.ResponseCompleteTimeout < 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook response complete timeout %v, must not be negative", o.ResponseCompleteTimeout))
+	}
+	if o.RequestBudget < 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook request budget %v, must not be negative", o.RequestBudget))
+	}
+	if o.BatchOptions.Mode == ModeBatch && (o.RequestReceivedTimeout != 0 || o.ResponseCompleteTimeout != 0 || o.RequestBudget != 0) {
+		allErrors = append(allErrors, fmt.Errorf("audit webhook stage timeouts and request budget require the %q or %q mode", ModeBlocking, ModeBlockingStrict))
+	}
+	if o.CircuitBreakerFailureThreshold < 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook circuit breaker failure threshold %v, must not be negative", o.CircuitBreakerFailureThreshold))
+	}
+	if o.CircuitBreakerFailureThreshold > 0 && o.CircuitBreakerOpenDuration <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit webhook circuit breaker open duration %v, must be a positive duration", o.CircuitBreakerOpenDuration))
+	}
 	return allErrors
 }
 
+// retryConfig returns the configuration of retrying to send batches of events.
+func (o *AuditWebhookOptions) retryConfig() pluginwebhook.RetryConfig {
+	config := pluginwebhook.RetryConfig{
+		Backoff: wait.Backoff{
+			Duration: o.InitialBackoff,
+			Factor:   o.BackoffFactor,
+			Jitter:   o.BackoffJitter,
+			Steps:    o.MaxAttempts,
+		},
+		Budget:        o.RetryBudget,
+		RequestBudget: o.RequestBudget,
+	}
+	if o.RequestReceivedTimeout > 0 || o.ResponseCompleteTimeout > 0 {
+		config.StageTimeouts = map[auditinternal.Stage]time.Duration{}
+		if o.RequestReceivedTimeout > 0 {
+			config.StageTimeouts[auditinternal.StageRequestReceived] = o.RequestReceivedTimeout
+		}
+		if o.ResponseCompleteTimeout > 0 {
+			config.StageTimeouts[auditinternal.StageResponseComplete] = o.ResponseCompleteTimeout
+		}
+	}
+	if o.CircuitBreakerFailureThreshold > 0 {
+		config.CircuitBreaker = &pluginwebhook.CircuitBreakerConfig{
+			FailureThreshold: o.CircuitBreakerFailureThreshold,
+			OpenDuration:     o.CircuitBreakerOpenDuration,
+		}
+	}
+	return config
+}
+
+func (o *AuditWebhookOptions) failoverConfig() pluginwebhook.FailoverConfig {
+	return pluginwebhook.FailoverConfig{
+		FailureThreshold: o.FailoverThreshold,
+		ProbeInterval:    o.FailoverProbeInterval,
+	}
+}
+
 func (o *AuditWebhookOptions) enabled() bool {
 	return o != nil && o.ConfigFile != ""
 }
 
+// namedBackend returns the webhook backend with the events it filters.
+func (o *AuditWebhookOptions) namedBackend(backend audit.Backend) audit.NamedBackend {
+	return audit.NamedBackend{
+		Name:       pluginwebhook.PluginName,
+		Backend:    backend,
+		OmitStages: toStages(o.OmitStages),
+		MaxLevel:   auditinternal.Level(o.MaxLevel),
+		Verbs:      o.Verbs,
+		Namespaces: o.Namespaces,
+	}
+}
+
 // newUntruncatedBackend returns a webhook backend without the truncate options applied
 // this is done so that the same trucate backend can wrap both the webhook and dynamic backends
 func (o *AuditWebhookOptions) newUntruncatedBackend(customDial utilnet.DialFunc) (audit.Backend, error) {
 	groupVersion, _ := schema.ParseGroupVersion(o.GroupVersionString)
-	webhook, err := pluginwebhook.NewBackend(o.ConfigFile, groupVersion, webhook.DefaultRetryBackoffWithInitialDelay(o.InitialBackoff), customDial)
+	var webhook audit.Backend
+	var err error
+	if o.SecondaryConfigFile != "" {
+		webhook, err = pluginwebhook.NewFailoverBackend(o.ConfigFile, o.SecondaryConfigFile, groupVersion,
+			o.encodingConfig(), o.retryConfig(), o.failoverConfig(), customDial)
+	} else {
+		webhook, err = pluginwebhook.NewBackend(o.ConfigFile, groupVersion, o.encodingConfig(), o.retryConfig(), customDial)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("initializing audit webhook: %v", err)
 	}
@@ -593,6 +1487,63 @@ func (o *AuditWebhookOptions) newUntruncatedBackend(customDial utilnet.DialFunc)
 	return webhook, nil
 }
 
+func (o *AuditDynamicOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enabled, "audit-dynamic-configuration", o.Enabled,
+		"Enables dynamic audit sinks, webhooks configured by AuditSink objects of the audit.k8s.io/v1 API, "+
+			"which are added, changed and removed without restarting the apiserver. The objects are watched through "+
+			"the auditsinks resource of the apiserver, which must be served, e.g. by a custom resource definition, "+
+			"or read from --audit-dynamic-sinks-file if set. The events of the audit policy "+
+			"routed to the "+plugindynamic.PluginName+" backend, or to all backends, are sent to the sinks. "+
+			"The retry options of the audit webhook apply to the webhooks of the sinks.")
+	fs.StringVar(&o.ConfigFile, "audit-dynamic-sinks-file", o.ConfigFile,
+		"Path to a file with an AuditSinkList or AuditSink of the audit.k8s.io/v1 API to read the dynamic audit sinks "+
+			"from instead of watching the auditsinks resource. The file is re-read periodically. A file that fails to load "+
+			"is rejected and the previously loaded sinks stay in effect.")
+}
+
+func (o *AuditDynamicOptions) Validate() []error {
+	var allErrors []error
+	if !o.Enabled && o.ConfigFile != "" {
+		allErrors = append(allErrors, fmt.Errorf("--audit-dynamic-sinks-file requires --audit-dynamic-configuration"))
+	}
+	return allErrors
+}
+
+// newBackend returns the backend sending events to the webhooks of the sinks,
+// read from the sinks file or, without one, watched with the loopback client.
+func (o *AuditDynamicOptions) newBackend(retryConfig pluginwebhook.RetryConfig, loopbackClientConfig *rest.Config) (audit.Backend, error) {
+	batchConfig := defaultWebhookBatchConfig()
+	if o.BatchConfig != nil {
+		batchConfig = *o.BatchConfig
+	}
+	config := plugindynamic.Config{
+		ReloadInterval: plugindynamic.DefaultReloadInterval,
+		BatchConfig:    batchConfig,
+		RetryConfig:    retryConfig,
+		GroupVersion:   auditv1.SchemeGroupVersion,
+	}
+	if o.ConfigFile != "" {
+		return plugindynamic.NewFileBackend(o.ConfigFile, config)
+	}
+	if loopbackClientConfig == nil {
+		return nil, fmt.Errorf("watching audit sinks requires a loopback client configuration, or --audit-dynamic-sinks-file")
+	}
+	client, err := dynamic.NewForConfig(loopbackClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the audit sinks client: %v", err)
+	}
+	return plugindynamic.NewBackend(client, config)
+}
+
+// namedBackend returns the backend of the sinks, which filter events by their
+// own policies.
+func (o *AuditDynamicOptions) namedBackend(backend audit.Backend) audit.NamedBackend {
+	return audit.NamedBackend{
+		Name:    plugindynamic.PluginName,
+		Backend: backend,
+	}
+}
+
 // defaultWebhookBatchConfig returns the default BatchConfig used by the Webhook backend.
 func defaultWebhookBatchConfig() pluginbuffered.BatchConfig {
 	return pluginbuffered.BatchConfig{
@@ -605,6 +1556,10 @@ func defaultWebhookBatchConfig() pluginbuffered.BatchConfig {
 		ThrottleBurst:  defaultBatchThrottleBurst,
 
 		AsyncDelegate: true,
+
+		OverflowPolicy:   pluginbuffered.OverflowPolicyDrop,
+		MaxBlockDuration: defaultBatchMaxBlock,
+		WALMaxSize:       defaultBatchWALMaxSize,
 	}
 }
 
@@ -618,5 +1573,9 @@ func defaultLogBatchConfig() pluginbuffered.BatchConfig {
 		ThrottleEnable: false,
 		// Asynchronous log threads just create lock contention.
 		AsyncDelegate: false,
+
+		OverflowPolicy:   pluginbuffered.OverflowPolicyDrop,
+		MaxBlockDuration: defaultBatchMaxBlock,
+		WALMaxSize:       defaultBatchWALMaxSize,
 	}
 }
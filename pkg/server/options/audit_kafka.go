@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/audit"
+	pluginkafka "k8s.io/apiserver/plugin/pkg/audit/kafka"
+)
+
+// AuditKafkaOptions control the publishing of audit events to a Kafka topic.
+type AuditKafkaOptions struct {
+	// Brokers are the host:port addresses of the Kafka brokers. The kafka
+	// backend is enabled if set.
+	Brokers []string
+	// Topic is the topic events are published to, partitioned by PartitionKey.
+	Topic        string
+	PartitionKey string
+	// Timeout is the timeout of connecting to a broker and of publishing a
+	// batch of events, including retries.
+	Timeout time.Duration
+
+	TLSOptions AuditTLSOptions
+	// SASLMechanism, if set, is the SASL mechanism authenticating the apiserver
+	// as SASLUsername, with the password read from SASLPasswordFile.
+	SASLMechanism    string
+	SASLUsername     string
+	SASLPasswordFile string
+
+	BatchOptions    AuditBatchOptions
+	TruncateOptions AuditTruncateOptions
+
+	// API group version used for serializing audit events.
+	GroupVersionString string
+}
+
+func NewAuditKafkaOptions() AuditKafkaOptions {
+	return AuditKafkaOptions{
+		PartitionKey: string(pluginkafka.PartitionKeyAuditID),
+		Timeout:      pluginkafka.DefaultTimeout,
+		BatchOptions: AuditBatchOptions{
+			Mode:        ModeBatch,
+			BatchConfig: defaultWebhookBatchConfig(),
+		},
+		TruncateOptions:    NewAuditTruncateOptions(),
+		GroupVersionString: "audit.k8s.io/v1",
+	}
+}
+
+func (o *AuditKafkaOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&o.Brokers, "audit-kafka-brokers", o.Brokers,
+		"The host:port addresses of the Kafka brokers audit events are published to, one JSON encoded event per message. "+
+			"If set, the kafka audit backend is enabled.")
+	fs.StringVar(&o.Topic, "audit-kafka-topic", o.Topic,
+		"The Kafka topic audit events are published to.")
+	fs.StringVar(&o.PartitionKey, "audit-kafka-partition-key", o.PartitionKey,
+		"The attribute of the audit events the messages are partitioned by. Known partition keys are "+
+			strings.Join(pluginkafka.AllowedPartitionKeys, ",")+".")
+	fs.DurationVar(&o.Timeout, "audit-kafka-timeout", o.Timeout,
+		"The timeout of connecting to a Kafka broker and of publishing a batch of audit events, including retries.")
+	fs.StringVar(&o.SASLMechanism, "audit-kafka-sasl-mechanism", o.SASLMechanism,
+		"The SASL mechanism authenticating the apiserver to the Kafka brokers, none if empty. Known mechanisms are "+
+			strings.Join(pluginkafka.AllowedSASLMechanisms, ",")+".")
+	fs.StringVar(&o.SASLUsername, "audit-kafka-sasl-username", o.SASLUsername,
+		"The user name the apiserver authenticates to the Kafka brokers as. Requires a SASL mechanism.")
+	fs.StringVar(&o.SASLPasswordFile, "audit-kafka-sasl-password-file", o.SASLPasswordFile,
+		"Path to a file with the password the apiserver authenticates to the Kafka brokers with. Requires a SASL mechanism.")
+	fs.StringVar(&o.GroupVersionString, "audit-kafka-version", o.GroupVersionString,
+		"API group and version used for serializing audit events published to Kafka.")
+}
+
+func (o *AuditKafkaOptions) Validate() []error {
+	if !o.enabled() {
+		return nil
+	}
+
+	var allErrors []error
+	if err := validateBackendBatchOptions(pluginkafka.PluginName, o.BatchOptions); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := o.TruncateOptions.Validate(pluginkafka.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := o.TLSOptions.Validate(pluginkafka.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := validateGroupVersionString(o.GroupVersionString); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
+	if o.Topic == "" {
+		allErrors = append(allErrors, fmt.Errorf("--audit-kafka-topic must be specified with --audit-kafka-brokers"))
+	}
+	if !sets.NewString(pluginkafka.AllowedPartitionKeys...).Has(o.PartitionKey) {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit kafka partition key %s, allowed partition keys are %q", o.PartitionKey, strings.Join(pluginkafka.AllowedPartitionKeys, ",")))
+	}
+	if o.Timeout <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit kafka timeout %v, must be a positive duration", o.Timeout))
+	}
+	if o.SASLMechanism == "" {
+		if o.SASLUsername != "" || o.SASLPasswordFile != "" {
+			allErrors = append(allErrors, fmt.Errorf("--audit-kafka-sasl-username and --audit-kafka-sasl-password-file require --audit-kafka-sasl-mechanism"))
+		}
+		return allErrors
+	}
+	if !sets.NewString(pluginkafka.AllowedSASLMechanisms...).Has(o.SASLMechanism) {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit kafka SASL mechanism %s, allowed mechanisms are %q", o.SASLMechanism, strings.Join(pluginkafka.AllowedSASLMechanisms, ",")))
+	}
+	if o.SASLUsername == "" || o.SASLPasswordFile == "" {
+		allErrors = append(allErrors, fmt.Errorf("--audit-kafka-sasl-mechanism requires --audit-kafka-sasl-username and --audit-kafka-sasl-password-file"))
+	}
+	if o.SASLMechanism == pluginkafka.SASLMechanismPlain && !o.TLSOptions.Enabled {
+		allErrors = append(allErrors, fmt.Errorf("the %s audit kafka SASL mechanism sends the password in clear text and requires --audit-kafka-tls", pluginkafka.SASLMechanismPlain))
+	}
+	return allErrors
+}
+
+func (o *AuditKafkaOptions) enabled() bool {
+	return o != nil && len(o.Brokers) > 0
+}
+
+// namedBackend returns the kafka backend, which events are routed to by name.
+func (o *AuditKafkaOptions) namedBackend(backend audit.Backend) audit.NamedBackend {
+	return audit.NamedBackend{
+		Name:    pluginkafka.PluginName,
+		Backend: backend,
+	}
+}
+
+// newBackend returns the kafka backend connecting to the brokers with the given
+// dialer, if set, wrapped in the batch and truncate options.
+func (o *AuditKafkaOptions) newBackend(customDial utilnet.DialFunc) (audit.Backend, error) {
+	groupVersion, _ := schema.ParseGroupVersion(o.GroupVersionString)
+	tlsConfig, err := o.TLSOptions.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("initializing audit kafka: %v", err)
+	}
+	var password string
+	if o.SASLPasswordFile != "" {
+		content, err := os.ReadFile(o.SASLPasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("initializing audit kafka: failed to read SASL password: %v", err)
+		}
+		password = strings.TrimSpace(string(content))
+	}
+	producer, err := pluginkafka.NewProducer(pluginkafka.ProducerConfig{
+		Brokers:       o.Brokers,
+		TLSConfig:     tlsConfig,
+		SASLMechanism: o.SASLMechanism,
+		Username:      o.SASLUsername,
+		Password:      password,
+		Dial:          customDial,
+		Timeout:       o.Timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initializing audit kafka: %v", err)
+	}
+	backend, err := pluginkafka.NewBackend(producer, pluginkafka.Config{
+		Topic:        o.Topic,
+		PartitionKey: pluginkafka.PartitionKey(o.PartitionKey),
+		GroupVersion: groupVersion,
+	})
+	if err != nil {
+		producer.Close()
+		return nil, fmt.Errorf("initializing audit kafka: %v", err)
+	}
+	backend = o.BatchOptions.wrapBackend(backend)
+	return o.TruncateOptions.wrapBackend(backend, groupVersion), nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apiserver/pkg/audit"
+	pluginotlp "k8s.io/apiserver/plugin/pkg/audit/otlp"
+)
+
+// defaultOTLPTimeout bounds the export of a batch of events.
+const defaultOTLPTimeout = 10 * time.Second
+
+// AuditOTLPOptions control the export of audit events as OpenTelemetry log
+// records over OTLP/gRPC.
+type AuditOTLPOptions struct {
+	// Endpoint is the host:port address of the OTLP/gRPC receiver. The otlp
+	// backend is enabled if set.
+	Endpoint string
+	// ServiceName is the service.name resource attribute of the log records.
+	ServiceName string
+	// Timeout is the timeout of exporting a batch of events.
+	Timeout time.Duration
+
+	TLSOptions      AuditTLSOptions
+	BatchOptions    AuditBatchOptions
+	TruncateOptions AuditTruncateOptions
+
+	// API group version used for serializing audit events.
+	GroupVersionString string
+}
+
+func NewAuditOTLPOptions() AuditOTLPOptions {
+	return AuditOTLPOptions{
+		ServiceName: pluginotlp.DefaultServiceName,
+		Timeout:     defaultOTLPTimeout,
+		TLSOptions:  AuditTLSOptions{Enabled: true},
+		BatchOptions: AuditBatchOptions{
+			Mode:        ModeBatch,
+			BatchConfig: defaultWebhookBatchConfig(),
+		},
+		TruncateOptions:    NewAuditTruncateOptions(),
+		GroupVersionString: "audit.k8s.io/v1",
+	}
+}
+
+func (o *AuditOTLPOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Endpoint, "audit-otlp-endpoint", o.Endpoint,
+		"The host:port address of the OTLP/gRPC receiver audit events are exported to as OpenTelemetry log records, "+
+			"e.g. an OpenTelemetry collector. If set, the otlp audit backend is enabled.")
+	fs.StringVar(&o.ServiceName, "audit-otlp-service-name", o.ServiceName,
+		"The service.name resource attribute of the exported log records.")
+	fs.DurationVar(&o.Timeout, "audit-otlp-timeout", o.Timeout,
+		"The timeout of exporting a batch of audit events.")
+	fs.StringVar(&o.GroupVersionString, "audit-otlp-version", o.GroupVersionString,
+		"API group and version used for serializing audit events exported over OTLP.")
+}
+
+func (o *AuditOTLPOptions) Validate() []error {
+	if !o.enabled() {
+		return nil
+	}
+
+	var allErrors []error
+	if err := validateBackendBatchOptions(pluginotlp.PluginName, o.BatchOptions); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := o.TruncateOptions.Validate(pluginotlp.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := o.TLSOptions.Validate(pluginotlp.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := validateGroupVersionString(o.GroupVersionString); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
+	if _, _, err := net.SplitHostPort(o.Endpoint); err != nil {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit otlp endpoint %q, must be a host:port address: %v", o.Endpoint, err))
+	}
+	if o.Timeout <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit otlp timeout %v, must be a positive duration", o.Timeout))
+	}
+	return allErrors
+}
+
+func (o *AuditOTLPOptions) enabled() bool {
+	return o != nil && o.Endpoint != ""
+}
+
+// namedBackend returns the otlp backend, which events are routed to by name.
+func (o *AuditOTLPOptions) namedBackend(backend audit.Backend) audit.NamedBackend {
+	return audit.NamedBackend{
+		Name:    pluginotlp.PluginName,
+		Backend: backend,
+	}
+}
+
+// newBackend returns the otlp backend connecting to the receiver with the given
+// dialer, if set, wrapped in the batch and truncate options. The log records
+// are attributed to the apiserver of the given ID.
+func (o *AuditOTLPOptions) newBackend(customDial utilnet.DialFunc, apiServerID string) (audit.Backend, error) {
+	groupVersion, _ := schema.ParseGroupVersion(o.GroupVersionString)
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	tlsConfig, err := o.TLSOptions.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("initializing audit otlp: %v", err)
+	}
+	if tlsConfig != nil {
+		dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	}
+	if customDial != nil {
+		dialOptions = append(dialOptions, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return customDial(ctx, "tcp", addr)
+		}))
+	}
+	// The connection is established in the background and re-established
+	// whenever it fails.
+	conn, err := grpc.Dial(o.Endpoint, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("initializing audit otlp: %v", err)
+	}
+	var backend audit.Backend = &closingBackend{
+		Backend: pluginotlp.NewBackend(conn, pluginotlp.Config{
+			ServiceName:       o.ServiceName,
+			ServiceInstanceID: apiServerID,
+			GroupVersion:      groupVersion,
+			Timeout:           o.Timeout,
+		}),
+		close: conn.Close,
+	}
+	backend = o.BatchOptions.wrapBackend(backend)
+	return o.TruncateOptions.wrapBackend(backend, groupVersion), nil
+}
+
+// closingBackend closes the connection of a backend once it is shut down.
+type closingBackend struct {
+	audit.Backend
+	close func() error
+}
+
+func (c *closingBackend) Shutdown() {
+	c.Backend.Shutdown()
+	if err := c.close(); err != nil {
+		audit.HandlePluginError(c.Backend.String(), fmt.Errorf("failed to close connection: %v", err))
+	}
+}
+
+func (c *closingBackend) HealthCheck() error {
+	return audit.CheckHealth(c.Backend)
+}
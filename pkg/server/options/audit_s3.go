@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apiserver/pkg/audit"
+	plugins3 "k8s.io/apiserver/plugin/pkg/audit/s3"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const (
+	// defaultS3Region is the region of the default endpoint of the S3 API.
+	defaultS3Region = "us-east-1"
+	// defaultS3Timeout bounds the upload of a batch of events.
+	defaultS3Timeout = time.Minute
+
+	// The environment variables the credentials of the S3 API are read from,
+	// the same as the AWS SDKs and CLI.
+	s3AccessKeyIDEnv     = "AWS_ACCESS_KEY_ID"
+	s3SecretAccessKeyEnv = "AWS_SECRET_ACCESS_KEY"
+	s3SessionTokenEnv    = "AWS_SESSION_TOKEN"
+)
+
+// AuditS3Options control the archiving of audit events to an S3-compatible
+// object storage bucket.
+type AuditS3Options struct {
+	// Bucket is the bucket events are archived to, under Prefix. The s3
+	// backend is enabled if set.
+	Bucket string
+	Prefix string
+	// Endpoint is the URL of the S3 API. Defaults to the endpoint of AWS in Region.
+	Endpoint string
+	// Region is the region requests are signed for.
+	Region string
+	// PathStyle addresses the bucket in the path of requests instead of in
+	// the host name.
+	PathStyle bool
+	// CAFile, if set, is the CA bundle the endpoint is verified with instead
+	// of the system roots.
+	CAFile string
+	// MaxChunkSize is the maximum uncompressed size in bytes of an object.
+	// Objects are not limited if zero.
+	MaxChunkSize int64
+	// Timeout is the timeout of uploading an object.
+	Timeout time.Duration
+
+	BatchOptions AuditBatchOptions
+
+	// API group version used for serializing audit events.
+	GroupVersionString string
+}
+
+func NewAuditS3Options() AuditS3Options {
+	return AuditS3Options{
+		Region:  defaultS3Region,
+		Timeout: defaultS3Timeout,
+		BatchOptions: AuditBatchOptions{
+			Mode:        ModeBatch,
+			BatchConfig: defaultWebhookBatchConfig(),
+		},
+		GroupVersionString: "audit.k8s.io/v1",
+	}
+}
+
+func (o *AuditS3Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Bucket, "audit-s3-bucket", o.Bucket,
+		"The S3 bucket audit events are archived to, as gzip compressed JSON lines, one object per batch of events. "+
+			"The credentials are read from the "+s3AccessKeyIDEnv+", "+s3SecretAccessKeyEnv+" and "+s3SessionTokenEnv+
+			" environment variables. If set, the s3 audit backend is enabled.")
+	fs.StringVar(&o.Prefix, "audit-s3-prefix", o.Prefix,
+		"The prefix of the keys of the archived objects, e.g. the name of the cluster.")
+	fs.StringVar(&o.Endpoint, "audit-s3-endpoint", o.Endpoint,
+		"The URL of the S3 API, e.g. of an S3-compatible object storage. Defaults to the AWS endpoint of the region.")
+	fs.StringVar(&o.Region, "audit-s3-region", o.Region,
+		"The region of the S3 bucket.")
+	fs.BoolVar(&o.PathStyle, "audit-s3-path-style", o.PathStyle,
+		"If true, the bucket is addressed in the path of requests instead of in the host name, "+
+			"as some S3-compatible object storages require.")
+	fs.StringVar(&o.CAFile, "audit-s3-tls-ca-file", o.CAFile,
+		"Path to a PEM-encoded CA bundle the S3 endpoint is verified with instead of the system roots.")
+	fs.Int64Var(&o.MaxChunkSize, "audit-s3-max-object-size", o.MaxChunkSize,
+		"The maximum uncompressed size in bytes of an archived object. Larger batches of events are split "+
+			"into several objects. Objects are not limited if zero.")
+	fs.DurationVar(&o.Timeout, "audit-s3-timeout", o.Timeout,
+		"The timeout of uploading an object to S3.")
+	fs.StringVar(&o.GroupVersionString, "audit-s3-version", o.GroupVersionString,
+		"API group and version used for serializing audit events archived to S3.")
+}
+
+func (o *AuditS3Options) Validate() []error {
+	if !o.enabled() {
+		return nil
+	}
+
+	var allErrors []error
+	if err := validateBackendBatchOptions(plugins3.PluginName, o.BatchOptions); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := validateGroupVersionString(o.GroupVersionString); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
+	if o.Region == "" {
+		allErrors = append(allErrors, fmt.Errorf("--audit-s3-region must be specified with --audit-s3-bucket"))
+	}
+	if o.Endpoint != "" {
+		if u, err := url.Parse(o.Endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			allErrors = append(allErrors, fmt.Errorf("invalid audit s3 endpoint %q, must be an http or https URL", o.Endpoint))
+		}
+	}
+	if o.MaxChunkSize < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--audit-s3-max-object-size %v can't be a negative number", o.MaxChunkSize))
+	}
+	if o.Timeout <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit s3 timeout %v, must be a positive duration", o.Timeout))
+	}
+	return allErrors
+}
+
+func (o *AuditS3Options) enabled() bool {
+	return o != nil && o.Bucket != ""
+}
+
+// namedBackend returns the s3 backend, which events are routed to by name.
+func (o *AuditS3Options) namedBackend(backend audit.Backend) audit.NamedBackend {
+	return audit.NamedBackend{
+		Name:    plugins3.PluginName,
+		Backend: backend,
+	}
+}
+
+// endpoint returns the URL of the S3 API.
+func (o *AuditS3Options) endpoint() string {
+	if o.Endpoint != "" {
+		return o.Endpoint
+	}
+	return fmt.Sprintf("https://s3.%s.amazonaws.com", o.Region)
+}
+
+// newBackend returns the s3 backend connecting to the endpoint with the given
+// dialer, if set, wrapped in the batch options. The objects uploaded by the
+// apiserver are told apart by its ID.
+func (o *AuditS3Options) newBackend(customDial utilnet.DialFunc, apiServerID string) (audit.Backend, error) {
+	groupVersion, _ := schema.ParseGroupVersion(o.GroupVersionString)
+	var tlsConfig *tls.Config
+	if o.CAFile != "" {
+		pool, err := certutil.NewPool(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("initializing audit s3: failed to load CA bundle: %v", err)
+		}
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool}
+	}
+	store, err := plugins3.NewObjectStore(plugins3.StoreConfig{
+		Endpoint:  o.endpoint(),
+		Region:    o.Region,
+		PathStyle: o.PathStyle,
+		Credentials: plugins3.Credentials{
+			AccessKeyID:     os.Getenv(s3AccessKeyIDEnv),
+			SecretAccessKey: os.Getenv(s3SecretAccessKeyEnv),
+			SessionToken:    os.Getenv(s3SessionTokenEnv),
+		},
+		TLSConfig: tlsConfig,
+		Dial:      customDial,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initializing audit s3: %v", err)
+	}
+	backend, err := plugins3.NewBackend(store, plugins3.Config{
+		Bucket:       o.Bucket,
+		Prefix:       o.Prefix,
+		InstanceID:   apiServerID,
+		MaxChunkSize: o.MaxChunkSize,
+		GroupVersion: groupVersion,
+		Timeout:      o.Timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initializing audit s3: %v", err)
+	}
+	return o.BatchOptions.wrapBackend(backend), nil
+}
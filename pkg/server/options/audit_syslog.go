@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apiserver/pkg/audit"
+	pluginsyslog "k8s.io/apiserver/plugin/pkg/audit/syslog"
+)
+
+// AuditSyslogOptions control the forwarding of audit events to a syslog server
+// as RFC 5424 messages.
+type AuditSyslogOptions struct {
+	// Address is the host:port address of the syslog server. The syslog
+	// backend is enabled if set.
+	Address string
+	// AppName, Facility and StructuredDataID are the APP-NAME, the facility
+	// and the SD-ID of the structured data of the messages.
+	AppName          string
+	Facility         int
+	StructuredDataID string
+	// Timeout is the timeout of connecting and sending messages.
+	Timeout time.Duration
+
+	TLSOptions      AuditTLSOptions
+	BatchOptions    AuditBatchOptions
+	TruncateOptions AuditTruncateOptions
+
+	// API group version used for serializing audit events.
+	GroupVersionString string
+}
+
+func NewAuditSyslogOptions() AuditSyslogOptions {
+	return AuditSyslogOptions{
+		AppName:          pluginsyslog.DefaultAppName,
+		Facility:         pluginsyslog.DefaultFacility,
+		StructuredDataID: pluginsyslog.DefaultStructuredDataID,
+		Timeout:          pluginsyslog.DefaultTimeout,
+		BatchOptions: AuditBatchOptions{
+			Mode:        ModeBatch,
+			BatchConfig: defaultWebhookBatchConfig(),
+		},
+		TruncateOptions:    NewAuditTruncateOptions(),
+		GroupVersionString: "audit.k8s.io/v1",
+	}
+}
+
+func (o *AuditSyslogOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Address, "audit-syslog-address", o.Address,
+		"The host:port address of the syslog server audit events are forwarded to over TCP, as RFC 5424 messages. "+
+			"If set, the syslog audit backend is enabled.")
+	fs.StringVar(&o.AppName, "audit-syslog-app-name", o.AppName,
+		"The APP-NAME of the syslog messages.")
+	fs.IntVar(&o.Facility, "audit-syslog-facility", o.Facility,
+		"The facility of the syslog messages, between 0 and 23.")
+	fs.StringVar(&o.StructuredDataID, "audit-syslog-structured-data-id", o.StructuredDataID,
+		"The SD-ID of the structured data of the syslog messages.")
+	fs.DurationVar(&o.Timeout, "audit-syslog-timeout", o.Timeout,
+		"The timeout of connecting to the syslog server and of sending messages.")
+	fs.StringVar(&o.GroupVersionString, "audit-syslog-version", o.GroupVersionString,
+		"API group and version used for serializing audit events forwarded to syslog.")
+}
+
+func (o *AuditSyslogOptions) Validate() []error {
+	if !o.enabled() {
+		return nil
+	}
+
+	var allErrors []error
+	if err := validateBackendBatchOptions(pluginsyslog.PluginName, o.BatchOptions); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := o.TruncateOptions.Validate(pluginsyslog.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := o.TLSOptions.Validate(pluginsyslog.PluginName); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := validateGroupVersionString(o.GroupVersionString); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
+	if _, _, err := net.SplitHostPort(o.Address); err != nil {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit syslog address %q, must be a host:port address: %v", o.Address, err))
+	}
+	if o.Facility < 0 || o.Facility > 23 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit syslog facility %v, must be between 0 and 23", o.Facility))
+	}
+	if o.Timeout <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit syslog timeout %v, must be a positive duration", o.Timeout))
+	}
+	return allErrors
+}
+
+func (o *AuditSyslogOptions) enabled() bool {
+	return o != nil && o.Address != ""
+}
+
+// namedBackend returns the syslog backend, which events are routed to by name.
+func (o *AuditSyslogOptions) namedBackend(backend audit.Backend) audit.NamedBackend {
+	return audit.NamedBackend{
+		Name:    pluginsyslog.PluginName,
+		Backend: backend,
+	}
+}
+
+// newBackend returns the syslog backend connecting to the server with the given
+// dialer, if set, wrapped in the batch and truncate options.
+func (o *AuditSyslogOptions) newBackend(customDial utilnet.DialFunc) (audit.Backend, error) {
+	groupVersion, _ := schema.ParseGroupVersion(o.GroupVersionString)
+	tlsConfig, err := o.TLSOptions.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("initializing audit syslog: %v", err)
+	}
+	facility := o.Facility
+	backend, err := pluginsyslog.NewBackend(pluginsyslog.Config{
+		Address:          o.Address,
+		TLSConfig:        tlsConfig,
+		Dial:             customDial,
+		Timeout:          o.Timeout,
+		AppName:          o.AppName,
+		Facility:         &facility,
+		StructuredDataID: o.StructuredDataID,
+		GroupVersion:     groupVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initializing audit syslog: %v", err)
+	}
+	backend = o.BatchOptions.wrapBackend(backend)
+	return o.TruncateOptions.wrapBackend(backend, groupVersion), nil
+}
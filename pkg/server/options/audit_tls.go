@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// AuditTLSOptions configure the TLS connections of an audit backend to its servers.
+type AuditTLSOptions struct {
+	// Whether TLS is enabled or not.
+	Enabled bool
+
+	// CAFile, if set, is the CA bundle servers are verified with instead of
+	// the system roots.
+	CAFile string
+	// CertFile and KeyFile, if set, are the client certificate and key the
+	// backend authenticates to servers with.
+	CertFile string
+	KeyFile  string
+}
+
+func (o *AuditTLSOptions) AddFlags(pluginName string, fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enabled, fmt.Sprintf("audit-%s-tls", pluginName), o.Enabled,
+		"Whether the connections to the servers are secured by TLS.")
+	fs.StringVar(&o.CAFile, fmt.Sprintf("audit-%s-tls-ca-file", pluginName), o.CAFile,
+		"Path to a PEM-encoded CA bundle the servers are verified with instead of the system roots. Requires TLS.")
+	fs.StringVar(&o.CertFile, fmt.Sprintf("audit-%s-tls-cert-file", pluginName), o.CertFile,
+		"Path to a PEM-encoded client certificate authenticating the apiserver to the servers. Requires TLS.")
+	fs.StringVar(&o.KeyFile, fmt.Sprintf("audit-%s-tls-key-file", pluginName), o.KeyFile,
+		"Path to the PEM-encoded private key of the client certificate. Requires TLS.")
+}
+
+func (o *AuditTLSOptions) Validate(pluginName string) error {
+	if !o.Enabled {
+		if o.CAFile != "" || o.CertFile != "" || o.KeyFile != "" {
+			return fmt.Errorf("--audit-%s-tls-ca-file, --audit-%s-tls-cert-file and --audit-%s-tls-key-file require --audit-%s-tls",
+				pluginName, pluginName, pluginName, pluginName)
+		}
+		return nil
+	}
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return fmt.Errorf("--audit-%s-tls-cert-file and --audit-%s-tls-key-file must be specified together", pluginName, pluginName)
+	}
+	return nil
+}
+
+// tlsConfig returns the TLS configuration of the connections, or nil if TLS is
+// not enabled.
+func (o *AuditTLSOptions) tlsConfig() (*tls.Config, error) {
+	if !o.Enabled {
+		return nil, nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if o.CAFile != "" {
+		pool, err := certutil.NewPool(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CA bundle: %v", err)
+		}
+		config.RootCAs = pool
+	}
+	if o.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/audit"
+	pluginunixsocket "k8s.io/apiserver/plugin/pkg/audit/unixsocket"
+)
+
+// AuditUnixSocketOptions control the writing of audit events to a Unix domain
+// socket, e.g. of a collector running on the same host.
+type AuditUnixSocketOptions struct {
+	// Path is the path of the socket. The unixsocket backend is enabled if set.
+	Path string
+	// Format is the format events are written in.
+	Format string
+	// Timeout is the timeout of connecting and writing events.
+	Timeout time.Duration
+
+	BatchOptions AuditBatchOptions
+
+	// API group version used for serializing audit events.
+	GroupVersionString string
+}
+
+func NewAuditUnixSocketOptions() AuditUnixSocketOptions {
+	return AuditUnixSocketOptions{
+		Format:  pluginunixsocket.FormatJson,
+		Timeout: pluginunixsocket.DefaultTimeout,
+		BatchOptions: AuditBatchOptions{
+			Mode:        ModeBatch,
+			BatchConfig: defaultWebhookBatchConfig(),
+		},
+		GroupVersionString: "audit.k8s.io/v1",
+	}
+}
+
+func (o *AuditUnixSocketOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Path, "audit-unixsocket-path", o.Path,
+		"Path to a Unix domain socket audit events are written to. The socket is reconnected whenever "+
+			"writing fails, so the collector listening on it may be restarted. If set, the unixsocket audit backend is enabled.")
+	fs.StringVar(&o.Format, "audit-unixsocket-format", o.Format,
+		"Format of the audit events written to the socket. \"json\" writes newline-delimited JSON, \"protobuf\" "+
+			"writes protobuf messages prefixed by their length. Known formats are "+
+			strings.Join(pluginunixsocket.AllowedFormats, ",")+".")
+	fs.DurationVar(&o.Timeout, "audit-unixsocket-timeout", o.Timeout,
+		"The timeout of connecting to the socket and of writing audit events.")
+	fs.StringVar(&o.GroupVersionString, "audit-unixsocket-version", o.GroupVersionString,
+		"API group and version used for serializing audit events written to the socket.")
+}
+
+func (o *AuditUnixSocketOptions) Validate() []error {
+	if !o.enabled() {
+		return nil
+	}
+
+	var allErrors []error
+	if err := validateBackendBatchOptions(pluginunixsocket.PluginName, o.BatchOptions); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if err := validateGroupVersionString(o.GroupVersionString); err != nil {
+		allErrors = append(allErrors, err)
+	}
+
+	if !sets.NewString(pluginunixsocket.AllowedFormats...).Has(o.Format) {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit unixsocket format %s, allowed formats are %q", o.Format, strings.Join(pluginunixsocket.AllowedFormats, ",")))
+	}
+	if o.Timeout <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("invalid audit unixsocket timeout %v, must be a positive duration", o.Timeout))
+	}
+	return allErrors
+}
+
+func (o *AuditUnixSocketOptions) enabled() bool {
+	return o != nil && o.Path != ""
+}
+
+// namedBackend returns the unixsocket backend, which events are routed to by name.
+func (o *AuditUnixSocketOptions) namedBackend(backend audit.Backend) audit.NamedBackend {
+	return audit.NamedBackend{
+		Name:    pluginunixsocket.PluginName,
+		Backend: backend,
+	}
+}
+
+// newBackend returns the unixsocket backend wrapped in the batch options.
+func (o *AuditUnixSocketOptions) newBackend() (audit.Backend, error) {
+	groupVersion, _ := schema.ParseGroupVersion(o.GroupVersionString)
+	backend, err := pluginunixsocket.NewBackend(pluginunixsocket.Config{
+		Path:         o.Path,
+		Format:       o.Format,
+		GroupVersion: groupVersion,
+		Timeout:      o.Timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initializing audit unixsocket: %v", err)
+	}
+	return o.BatchOptions.wrapBackend(backend), nil
+}
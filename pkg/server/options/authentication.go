@@ -23,17 +23,30 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	oteltrace "go.opentelemetry.io/otel/trace"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/authentication/authenticatorfactory"
+	"k8s.io/apiserver/pkg/authentication/group"
+	"k8s.io/apiserver/pkg/authentication/request/anonymous"
 	"k8s.io/apiserver/pkg/authentication/request/headerrequest"
+	"k8s.io/apiserver/pkg/authentication/request/normalize"
+	"k8s.io/apiserver/pkg/authentication/request/spiffe"
+	"k8s.io/apiserver/pkg/authentication/request/throttle"
+	x509request "k8s.io/apiserver/pkg/authentication/request/x509"
+	"k8s.io/apiserver/pkg/authentication/token/shape"
+	"k8s.io/apiserver/pkg/authentication/token/tokenfile"
 	"k8s.io/apiserver/pkg/server"
 	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	execauthenticator "k8s.io/apiserver/plugin/pkg/authenticator/token/exec"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/grpcprovider"
+	webhooktoken "k8s.io/apiserver/plugin/pkg/authenticator/token/webhook"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/transport"
+	tracing "k8s.io/component-base/tracing"
 	"k8s.io/klog/v2"
 	openapicommon "k8s.io/kube-openapi/pkg/common"
 )
@@ -58,6 +71,13 @@ type RequestHeaderAuthenticationOptions struct {
 	GroupHeaders        []string
 	ExtraHeaderPrefixes []string
 	AllowedNames        []string
+
+	// ProxyAllowedUsernames, ProxyAllowedGroups and ProxyAllowedExtraKeys limit the identities
+	// individual front proxies may assert. Each entry has the form <client certificate common
+	// name>=<pattern>, where a pattern ending in "*" is a prefix match.
+	ProxyAllowedUsernames []string
+	ProxyAllowedGroups    []string
+	ProxyAllowedExtraKeys []string
 }
 
 func (s *RequestHeaderAuthenticationOptions) Validate() []error {
@@ -75,10 +95,68 @@ func (s *RequestHeaderAuthenticationOptions) Validate() []error {
 	if err := checkForWhiteSpaceOnly("requestheader-allowed-names", s.AllowedNames...); err != nil {
 		allErrors = append(allErrors, err)
 	}
+	if _, err := s.ProxyIdentityScopes(); err != nil {
+		allErrors = append(allErrors, err)
+	}
 
 	return allErrors
 }
 
+// ProxyIdentityScopes returns the identity scopes of the front proxies configured by
+// ProxyAllowedUsernames, ProxyAllowedGroups and ProxyAllowedExtraKeys. A proxy listed in
+// only some of them may not assert any value of the others.
+func (s *RequestHeaderAuthenticationOptions) ProxyIdentityScopes() (headerrequest.ProxyIdentityScopes, error) {
+	scopes := headerrequest.ProxyIdentityScopes{}
+	indexes := map[string]int{}
+	scopeFor := func(proxyName string) *headerrequest.ProxyIdentityScope {
+		i, ok := indexes[proxyName]
+		if !ok {
+			i = len(scopes)
+			indexes[proxyName] = i
+			scopes = append(scopes, headerrequest.ProxyIdentityScope{ProxyClientName: proxyName})
+		}
+		return &scopes[i]
+	}
+
+	for _, entry := range s.ProxyAllowedUsernames {
+		proxyName, pattern, err := splitProxyPattern("requestheader-proxy-allowed-usernames", entry)
+		if err != nil {
+			return nil, err
+		}
+		scope := scopeFor(proxyName)
+		scope.AllowedUsernames = append(scope.AllowedUsernames, pattern)
+	}
+	for _, entry := range s.ProxyAllowedGroups {
+		proxyName, pattern, err := splitProxyPattern("requestheader-proxy-allowed-groups", entry)
+		if err != nil {
+			return nil, err
+		}
+		scope := scopeFor(proxyName)
+		scope.AllowedGroups = append(scope.AllowedGroups, pattern)
+	}
+	for _, entry := range s.ProxyAllowedExtraKeys {
+		proxyName, pattern, err := splitProxyPattern("requestheader-proxy-allowed-extra-keys", entry)
+		if err != nil {
+			return nil, err
+		}
+		scope := scopeFor(proxyName)
+		scope.AllowedExtraKeys = append(scope.AllowedExtraKeys, pattern)
+	}
+
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+	return scopes, nil
+}
+
+func splitProxyPattern(flag, entry string) (string, string, error) {
+	parts := strings.SplitN(entry, "=", 2)
+	if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 || len(strings.TrimSpace(parts[1])) == 0 {
+		return "", "", fmt.Errorf("invalid value %q in %q, expected <client name>=<pattern>", entry, flag)
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
 func checkForWhiteSpaceOnly(flag string, headerNames ...string) error {
 	for _, headerName := range headerNames {
 		if len(strings.TrimSpace(headerName)) == 0 {
@@ -112,6 +190,20 @@ func (s *RequestHeaderAuthenticationOptions) AddFlags(fs *pflag.FlagSet) {
 		"List of client certificate common names to allow to provide usernames in headers "+
 		"specified by --requestheader-username-headers. If empty, any client certificate validated "+
 		"by the authorities in --requestheader-client-ca-file is allowed.")
+
+	fs.StringSliceVar(&s.ProxyAllowedUsernames, "requestheader-proxy-allowed-usernames", s.ProxyAllowedUsernames, ""+
+		"List of <client certificate common name>=<pattern> entries limiting the usernames a front proxy may provide "+
+		"in headers. A pattern ending in '*' matches every username with that prefix, e.g. front-proxy-a=oidc:*. "+
+		"A front proxy listed here, in --requestheader-proxy-allowed-groups or in --requestheader-proxy-allowed-extra-keys "+
+		"may only provide the usernames, groups and extra keys listed for it. Other front proxies are not limited.")
+
+	fs.StringSliceVar(&s.ProxyAllowedGroups, "requestheader-proxy-allowed-groups", s.ProxyAllowedGroups, ""+
+		"List of <client certificate common name>=<pattern> entries limiting the groups a front proxy may provide "+
+		"in headers. See --requestheader-proxy-allowed-usernames.")
+
+	fs.StringSliceVar(&s.ProxyAllowedExtraKeys, "requestheader-proxy-allowed-extra-keys", s.ProxyAllowedExtraKeys, ""+
+		"List of <client certificate common name>=<pattern> entries limiting the keys of the extra attributes a front "+
+		"proxy may provide in headers. See --requestheader-proxy-allowed-usernames.")
 }
 
 // ToAuthenticationRequestHeaderConfig returns a RequestHeaderConfig config object for these options
@@ -126,12 +218,18 @@ func (s *RequestHeaderAuthenticationOptions) ToAuthenticationRequestHeaderConfig
 		return nil, err
 	}
 
+	proxyScopes, err := s.ProxyIdentityScopes()
+	if err != nil {
+		return nil, err
+	}
+
 	return &authenticatorfactory.RequestHeaderConfig{
 		UsernameHeaders:     headerrequest.StaticStringSlice(s.UsernameHeaders),
 		GroupHeaders:        headerrequest.StaticStringSlice(s.GroupHeaders),
 		ExtraHeaderPrefixes: headerrequest.StaticStringSlice(s.ExtraHeaderPrefixes),
 		CAContentProvider:   caBundleProvider,
 		AllowedClientNames:  headerrequest.StaticStringSlice(s.AllowedNames),
+		ProxyIdentityScopes: proxyScopes,
 	}, nil
 }
 
@@ -145,6 +243,27 @@ type ClientCertAuthenticationOptions struct {
 	// Generally this is the CA bundle file used to authenticate client certificates
 	// If non-nil, this takes priority over the ClientCA file.
 	CAContentProvider dynamiccertificates.CAContentProvider
+
+	// CRLFile is a file with certificate revocation lists. Client certificates listed in a
+	// revocation list signed by their issuer are rejected. The file is reloaded when it changes.
+	CRLFile string
+
+	// OCSP enables checking the revocation status of client certificates with an OCSP responder.
+	OCSP bool
+
+	// OCSPResponder overrides the OCSP responder listed in the client certificates.
+	OCSPResponder string
+
+	// OCSPSoftFail accepts client certificates whose revocation status cannot be determined.
+	OCSPSoftFail bool
+
+	// ExpirationWarningThreshold, if positive, is the remaining lifetime of client certificates
+	// below which clients are sent a warning with the response.
+	ExpirationWarningThreshold time.Duration
+
+	// UserMapping configures how users are derived from client certificates.
+	// The zero value maps the CommonName to the username and Organizations to groups.
+	UserMapping x509request.UserMapping
 }
 
 // GetClientVerifyOptionFn provides verify options for your authenticator while respecting the preferred order of verifiers.
@@ -160,11 +279,135 @@ func (s *ClientCertAuthenticationOptions) GetClientCAContentProvider() (dynamicc
 	return dynamiccertificates.NewDynamicCAContentFromFile("client-ca-bundle", s.ClientCA)
 }
 
+// GetRevocationChecker returns the revocation checker for client certificates, or nil if
+// revocation checking is not enabled.
+func (s *ClientCertAuthenticationOptions) GetRevocationChecker() (x509request.RevocationChecker, error) {
+	var checkers x509request.RevocationCheckers
+	if len(s.CRLFile) > 0 {
+		crl, err := x509request.NewDynamicCRLFile(s.CRLFile)
+		if err != nil {
+			return nil, err
+		}
+		checkers = append(checkers, crl)
+	}
+	if s.OCSP {
+		checkers = append(checkers, x509request.NewOCSPChecker(s.OCSPResponder, nil, s.OCSPSoftFail))
+	}
+	if len(checkers) == 0 {
+		return nil, nil
+	}
+	return checkers, nil
+}
+
+// GetUserConversion returns the conversion from client certificates to users.
+func (s *ClientCertAuthenticationOptions) GetUserConversion() (x509request.UserConversion, error) {
+	if s.UserMapping == (x509request.UserMapping{}) {
+		return x509request.CommonNameUserConversion, nil
+	}
+	return x509request.NewUserConversion(s.UserMapping)
+}
+
+// Validate checks that the revocation and user mapping options are consistent.
+func (s *ClientCertAuthenticationOptions) Validate() []error {
+	var allErrors []error
+	if !s.OCSP && (len(s.OCSPResponder) > 0 || s.OCSPSoftFail) {
+		allErrors = append(allErrors, fmt.Errorf("--client-ocsp-responder and --client-ocsp-soft-fail require --client-ocsp"))
+	}
+	if s.ExpirationWarningThreshold < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--client-cert-expiration-warning-threshold must not be negative, but is: %v", s.ExpirationWarningThreshold))
+	}
+	if _, err := s.GetUserConversion(); err != nil {
+		allErrors = append(allErrors, fmt.Errorf("invalid client certificate user mapping: %v", err))
+	}
+	return allErrors
+}
+
 func (s *ClientCertAuthenticationOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ClientCA, "client-ca-file", s.ClientCA, ""+
 		"If set, any request presenting a client certificate signed by one of "+
 		"the authorities in the client-ca-file is authenticated with an identity "+
 		"corresponding to the CommonName of the client certificate.")
+	fs.StringVar(&s.CRLFile, "client-crl-file", s.CRLFile, ""+
+		"If set, client certificates listed in one of the PEM or DER encoded certificate revocation "+
+		"lists in this file are rejected. The file is reloaded when it changes.")
+	fs.BoolVar(&s.OCSP, "client-ocsp", s.OCSP, ""+
+		"If true, the revocation status of client certificates is checked with the OCSP responder "+
+		"listed in the certificate or set with --client-ocsp-responder.")
+	fs.StringVar(&s.OCSPResponder, "client-ocsp-responder", s.OCSPResponder, ""+
+		"The URL of the OCSP responder to use instead of the one listed in client certificates.")
+	fs.BoolVar(&s.OCSPSoftFail, "client-ocsp-soft-fail", s.OCSPSoftFail, ""+
+		"If true, client certificates whose revocation status cannot be determined are accepted. "+
+		"Revoked certificates are always rejected.")
+	fs.DurationVar(&s.ExpirationWarningThreshold, "client-cert-expiration-warning-threshold", s.ExpirationWarningThreshold, ""+
+		"If greater than zero, clients authenticating with a client certificate that expires within this duration "+
+		"are sent a warning with the response.")
+	fs.StringVar(&s.UserMapping.UsernameSource, "client-cert-username-source", s.UserMapping.UsernameSource, ""+
+		"The client certificate attribute the username is taken from: CommonName, URISAN, EmailSAN "+
+		"or the dotted object identifier of a subject attribute. Defaults to CommonName.")
+	fs.StringVar(&s.UserMapping.UsernamePattern, "client-cert-username-pattern", s.UserMapping.UsernamePattern, ""+
+		"If set, a regular expression the username must match for a client certificate to be authenticated.")
+	fs.StringVar(&s.UserMapping.UsernameReplacement, "client-cert-username-replacement", s.UserMapping.UsernameReplacement, ""+
+		"If set, the username is rewritten with the matches of --client-cert-username-pattern, e.g. \"$1\".")
+	fs.StringVar(&s.UserMapping.GroupsSource, "client-cert-groups-source", s.UserMapping.GroupsSource, ""+
+		"The client certificate subject attribute groups are taken from: Organization or OrganizationalUnit. "+
+		"Defaults to Organization.")
+	fs.StringVar(&s.UserMapping.GroupsPattern, "client-cert-groups-pattern", s.UserMapping.GroupsPattern, ""+
+		"If set, a regular expression values must match to become groups. Other values are ignored.")
+	fs.StringVar(&s.UserMapping.GroupsReplacement, "client-cert-groups-replacement", s.UserMapping.GroupsReplacement, ""+
+		"If set, each group is rewritten with the matches of --client-cert-groups-pattern.")
+}
+
+// SPIFFEAuthenticationOptions provides the options for authenticating SPIFFE X.509 SVIDs.
+type SPIFFEAuthenticationOptions struct {
+	// TrustBundleFile is the file with the CA certificates of the SPIFFE trust domain.
+	// SPIFFE authentication is enabled if set.
+	TrustBundleFile string
+
+	// TrustDomain is the SPIFFE trust domain of the accepted SVIDs.
+	TrustDomain string
+
+	// UsernameTemplate is a template producing the username of an SVID from its SPIFFE ID.
+	UsernameTemplate string
+
+	// GroupTemplates are templates producing the groups of an SVID from its SPIFFE ID.
+	GroupTemplates []string
+}
+
+// Validate checks that the SPIFFE options are complete and the templates parse.
+func (s *SPIFFEAuthenticationOptions) Validate() []error {
+	if len(s.TrustBundleFile) == 0 {
+		if len(s.TrustDomain) > 0 || len(s.UsernameTemplate) > 0 || len(s.GroupTemplates) > 0 {
+			return []error{fmt.Errorf("--spiffe-trust-bundle-file is required to configure SPIFFE authentication")}
+		}
+		return nil
+	}
+	if _, err := spiffe.NewUserConversion(s.config(nil)); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+func (s *SPIFFEAuthenticationOptions) config(verifyOptionsFn x509request.VerifyOptionFunc) spiffe.Config {
+	return spiffe.Config{
+		TrustDomain:      s.TrustDomain,
+		VerifyOptionsFn:  verifyOptionsFn,
+		UsernameTemplate: s.UsernameTemplate,
+		GroupTemplates:   s.GroupTemplates,
+	}
+}
+
+func (s *SPIFFEAuthenticationOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.TrustBundleFile, "spiffe-trust-bundle-file", s.TrustBundleFile, ""+
+		"If set, requests presenting an X.509 SVID of the --spiffe-trust-domain signed by one of the "+
+		"authorities in this file are authenticated with an identity derived from its SPIFFE ID.")
+	fs.StringVar(&s.TrustDomain, "spiffe-trust-domain", s.TrustDomain, ""+
+		"The SPIFFE trust domain of accepted SVIDs, e.g. example.org.")
+	fs.StringVar(&s.UsernameTemplate, "spiffe-username-template", s.UsernameTemplate, ""+
+		"A Go template producing the username of an SVID. The fields .ID, .TrustDomain, .Path and .Segments "+
+		"of its SPIFFE ID are available. Defaults to \"{{.ID}}\".")
+	fs.StringSliceVar(&s.GroupTemplates, "spiffe-group-templates", s.GroupTemplates, ""+
+		"Go templates producing the groups of an SVID, e.g. \"spiffe:ns:{{index .Segments 1}}\". "+
+		"Templates producing an empty string are ignored.")
 }
 
 // DelegatingAuthenticationOptions provides an easy way for composing API servers to delegate their authentication to
@@ -177,12 +420,32 @@ type DelegatingAuthenticationOptions struct {
 	// RemoteKubeConfigFileOptional is specifying whether not specifying the kubeconfig or
 	// a missing in-cluster config will be fatal.
 	RemoteKubeConfigFileOptional bool
+	// FailoverKubeConfigFiles are files to connect to further servers hosting the
+	// TokenAccessReview.authentication.k8s.io endpoint. They are tried in order when
+	// the server configured by RemoteKubeConfigFile is unavailable.
+	FailoverKubeConfigFiles []string
 
 	// CacheTTL is the length of time that a token authentication answer will be cached.
 	CacheTTL time.Duration
 
+	// CacheFailureTTL is the length of time that a failed token authentication answer will be cached.
+	// If zero, CacheTTL is used.
+	CacheFailureTTL time.Duration
+
+	// CacheMaxEntries bounds the number of cached token authentication answers. Zero means unbounded.
+	CacheMaxEntries int
+
+	// CacheJitter is the maximum fraction, in [0, 1), by which the TTL of each cached
+	// answer is randomly shortened to spread out revalidation.
+	CacheJitter float64
+
+	// NegativeCacheTTL is the length of time that rejected tokens are rejected again
+	// without consulting the token authenticators. Zero disables it.
+	NegativeCacheTTL time.Duration
+
 	ClientCert    ClientCertAuthenticationOptions
 	RequestHeader RequestHeaderAuthenticationOptions
+	SPIFFE        SPIFFEAuthenticationOptions
 
 	// SkipInClusterLookup indicates missing authentication configuration should not be retrieved from the cluster configmap
 	SkipInClusterLookup bool
@@ -196,6 +459,62 @@ type DelegatingAuthenticationOptions struct {
 	// before we fail the webhook call in order to limit the fan out that ensues when the system is degraded.
 	WebhookRetryBackoff *wait.Backoff
 
+	// WebhookCircuitBreakerFailureThreshold is the number of consecutive failed requests after which
+	// a token review endpoint is skipped for WebhookCircuitBreakerOpenDuration. Zero disables circuit breaking.
+	WebhookCircuitBreakerFailureThreshold int
+
+	// WebhookCircuitBreakerOpenDuration is how long a failing token review endpoint is skipped.
+	WebhookCircuitBreakerOpenDuration time.Duration
+
+	// WebhookMaxTokenLength is the maximum length of tokens sent for token review. Zero means unlimited.
+	WebhookMaxTokenLength int
+
+	// WebhookTokenAllowedCharacters are the characters tokens sent for token review may consist of.
+	// If empty, any character is allowed.
+	WebhookTokenAllowedCharacters string
+
+	// WebhookValidateJWTShape rejects tokens shaped like JWTs but not well formed before they are
+	// sent for token review.
+	WebhookValidateJWTShape bool
+
+	// TokenCertificateBinding only accepts bearer tokens bound to a client certificate by an x5t#S256
+	// confirmation claim from clients presenting that certificate over TLS. Tokens bound by other
+	// confirmation methods are rejected.
+	TokenCertificateBinding bool
+
+	// ThrottleMaxFailures is the number of failed authentication attempts per key allowed within
+	// ThrottleWindow before further requests of the key are refused with 429. Zero disables throttling.
+	ThrottleMaxFailures int
+
+	// ThrottleWindow is the duration of the sliding window in which failed authentication attempts are counted.
+	ThrottleWindow time.Duration
+
+	// ThrottleKeys are the keys by which failed authentication attempts are counted, "source-ip" or "credential".
+	ThrottleKeys []string
+
+	// LowercaseUsernames lowercases the names of authenticated users.
+	LowercaseUsernames bool
+
+	// GroupPrefixMappings rewrite the prefixes of the groups of authenticated users, as "<from>=<to>"
+	// entries. An entry with an empty <to> strips the prefix.
+	GroupPrefixMappings []string
+
+	// MaxUserExtraSize is the maximum total size in bytes of the extra fields of authenticated users.
+	// Zero means unlimited.
+	MaxUserExtraSize int
+
+	// UntrustedMechanisms lists the authentication mechanisms whose users must not have a name or
+	// group reserved for the system.
+	UntrustedMechanisms []string
+
+	// MechanismOrder lists the authentication mechanisms to try first, in order. The others follow in
+	// their default order.
+	MechanismOrder []string
+
+	// ShortCircuitMechanisms lists the authentication mechanisms whose failures end authentication
+	// without trying the remaining mechanisms.
+	ShortCircuitMechanisms []string
+
 	// TokenRequestTimeout specifies a time limit for requests made by the authorization webhook client.
 	// The default value is set to 10 seconds.
 	TokenRequestTimeout time.Duration
@@ -205,6 +524,52 @@ type DelegatingAuthenticationOptions struct {
 
 	// DisableAnonymous gives user an option to disable Anonymous authentication.
 	DisableAnonymous bool
+
+	// AnonymousPaths restricts anonymous authentication to the listed request paths, e.g. /healthz.
+	// A path ending in "*" is a prefix match. If empty, anonymous requests are allowed on every path.
+	AnonymousPaths []string
+
+	// AnonymousIdentityPaths assign anonymous requests to paths a synthetic user other than system:anonymous,
+	// as "<username>=<path>" entries. A path ending in "*" is a prefix match.
+	AnonymousIdentityPaths []string
+
+	// AnonymousIdentityGroups are the groups of the users of AnonymousIdentityPaths, as "<username>=<group>" entries.
+	AnonymousIdentityGroups []string
+
+	// AuthenticationProviderEndpoint is the unix:// endpoint of an external authentication provider
+	// implementing the gRPC AuthenticationProvider service. If empty, no provider is used.
+	AuthenticationProviderEndpoint string
+
+	// AuthenticationProviderTimeout is the time limit for calls to the external authentication provider.
+	AuthenticationProviderTimeout time.Duration
+
+	// ExecAuthenticatorCommand is a command run to authenticate bearer tokens, which it reads from its
+	// standard input, writing the status of a TokenReview as JSON to its standard output. If empty, no command is run.
+	ExecAuthenticatorCommand string
+
+	// ExecAuthenticatorArgs are the arguments of ExecAuthenticatorCommand.
+	ExecAuthenticatorArgs []string
+
+	// ExecAuthenticatorTimeout is the time limit for a run of ExecAuthenticatorCommand.
+	ExecAuthenticatorTimeout time.Duration
+
+	// AuthenticationConfigFile is the file with the structured authentication configuration.
+	// JWT issuers listed in it are reloaded at runtime when the file changes.
+	AuthenticationConfigFile string
+
+	// TokenFile is a CSV file with static bearer tokens, one "token,user,uid[,groups]" record per line.
+	// It is reloaded at runtime when the file changes.
+	TokenFile string
+
+	// TracerProvider, if set, records a span for each authenticator tried for a request and
+	// for the requests of the authentication webhook client.
+	TracerProvider oteltrace.TracerProvider
+
+	// GroupResolver, if set, resolves additional groups of authenticated users from an external source.
+	GroupResolver group.GroupResolver
+
+	// GroupResolverCacheTTL is the length of time that the groups resolved for a user are cached.
+	GroupResolverCacheTTL time.Duration
 }
 
 func NewDelegatingAuthenticationOptions() *DelegatingAuthenticationOptions {
@@ -217,8 +582,14 @@ func NewDelegatingAuthenticationOptions() *DelegatingAuthenticationOptions {
 			GroupHeaders:        []string{"x-remote-group"},
 			ExtraHeaderPrefixes: []string{"x-remote-extra-"},
 		},
-		WebhookRetryBackoff: DefaultAuthWebhookRetryBackoff(),
-		TokenRequestTimeout: 10 * time.Second,
+		WebhookRetryBackoff:               DefaultAuthWebhookRetryBackoff(),
+		WebhookCircuitBreakerOpenDuration: 30 * time.Second,
+		TokenRequestTimeout:               10 * time.Second,
+		AuthenticationProviderTimeout:     3 * time.Second,
+		ExecAuthenticatorTimeout:          3 * time.Second,
+		GroupResolverCacheTTL:             10 * time.Second,
+		ThrottleWindow:                    time.Minute,
+		ThrottleKeys:                      []string{string(throttle.KeySourceIP)},
 	}
 }
 
@@ -237,6 +608,95 @@ func (s *DelegatingAuthenticationOptions) WithCustomRoundTripper(rt transport.Wr
 	s.CustomRoundTripperFn = rt
 }
 
+// WithTracerProvider sets the tracer provider recording the spans of authentication.
+func (s *DelegatingAuthenticationOptions) WithTracerProvider(tp oteltrace.TracerProvider) {
+	s.TracerProvider = tp
+}
+
+// WithGroupResolver sets the resolver of additional groups of authenticated users.
+func (s *DelegatingAuthenticationOptions) WithGroupResolver(resolver group.GroupResolver) {
+	s.GroupResolver = resolver
+}
+
+// AnonymousIdentities returns the identities of anonymous requests to specific paths, in the order
+// their users first appear in AnonymousIdentityPaths.
+func (s *DelegatingAuthenticationOptions) AnonymousIdentities() ([]anonymous.PathIdentity, error) {
+	identities := []anonymous.PathIdentity{}
+	indexes := map[string]int{}
+	identityFor := func(username string) *anonymous.PathIdentity {
+		i, ok := indexes[username]
+		if !ok {
+			i = len(identities)
+			indexes[username] = i
+			identities = append(identities, anonymous.PathIdentity{Username: username})
+		}
+		return &identities[i]
+	}
+
+	for _, entry := range s.AnonymousIdentityPaths {
+		username, path, err := splitAnonymousIdentityEntry("authentication-anonymous-identity-paths", entry)
+		if err != nil {
+			return nil, err
+		}
+		identity := identityFor(username)
+		identity.Paths = append(identity.Paths, path)
+	}
+	for _, entry := range s.AnonymousIdentityGroups {
+		username, group, err := splitAnonymousIdentityEntry("authentication-anonymous-identity-groups", entry)
+		if err != nil {
+			return nil, err
+		}
+		identity := identityFor(username)
+		identity.Groups = append(identity.Groups, group)
+	}
+
+	if len(identities) == 0 {
+		return nil, nil
+	}
+	return identities, nil
+}
+
+// UserNormalization returns the normalization of authenticated users, or nil if users are not normalized.
+func (s *DelegatingAuthenticationOptions) UserNormalization() (*normalize.Config, error) {
+	if !s.LowercaseUsernames && len(s.GroupPrefixMappings) == 0 && s.MaxUserExtraSize == 0 {
+		return nil, nil
+	}
+	config := &normalize.Config{
+		LowercaseUsernames: s.LowercaseUsernames,
+		MaxExtraSize:       s.MaxUserExtraSize,
+	}
+	for _, entry := range s.GroupPrefixMappings {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
+			return nil, fmt.Errorf("invalid value %q in %q, expected <from>=<to>", entry, "authentication-group-prefix-mappings")
+		}
+		config.GroupPrefixMappings = append(config.GroupPrefixMappings, normalize.PrefixMapping{
+			From: strings.TrimSpace(parts[0]),
+			To:   strings.TrimSpace(parts[1]),
+		})
+	}
+	return config, nil
+}
+
+func splitAnonymousIdentityEntry(flag, entry string) (string, string, error) {
+	parts := strings.SplitN(entry, "=", 2)
+	if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 || len(strings.TrimSpace(parts[1])) == 0 {
+		return "", "", fmt.Errorf("invalid value %q in %q, expected <username>=<value>", entry, flag)
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
+func validateAnonymousPath(flag, path string) []error {
+	var errs []error
+	if !strings.HasPrefix(path, "/") {
+		errs = append(errs, fmt.Errorf("--%s entry %q must start with \"/\"", flag, path))
+	}
+	if i := strings.Index(path, "*"); i >= 0 && i != len(path)-1 {
+		errs = append(errs, fmt.Errorf("--%s entry %q may only contain \"*\" as its last character", flag, path))
+	}
+	return errs
+}
+
 func (s *DelegatingAuthenticationOptions) Validate() []error {
 	if s == nil {
 		return nil
@@ -244,10 +704,103 @@ func (s *DelegatingAuthenticationOptions) Validate() []error {
 
 	allErrors := []error{}
 	allErrors = append(allErrors, s.RequestHeader.Validate()...)
+	allErrors = append(allErrors, s.ClientCert.Validate()...)
+	allErrors = append(allErrors, s.SPIFFE.Validate()...)
 
 	if s.WebhookRetryBackoff != nil && s.WebhookRetryBackoff.Steps <= 0 {
 		allErrors = append(allErrors, fmt.Errorf("number of webhook retry attempts must be greater than 1, but is: %d", s.WebhookRetryBackoff.Steps))
 	}
+	if s.CacheFailureTTL < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-token-webhook-cache-failure-ttl must not be negative, but is: %v", s.CacheFailureTTL))
+	}
+	if s.CacheMaxEntries < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-token-webhook-cache-max-entries must not be negative, but is: %d", s.CacheMaxEntries))
+	}
+	if s.NegativeCacheTTL < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-token-negative-cache-ttl must not be negative, but is: %v", s.NegativeCacheTTL))
+	}
+	if s.GroupResolverCacheTTL < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-group-resolver-cache-ttl must not be negative, but is: %v", s.GroupResolverCacheTTL))
+	}
+	if s.CacheJitter < 0 || s.CacheJitter >= 1 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-token-webhook-cache-jitter must be in the range [0, 1), but is: %v", s.CacheJitter))
+	}
+	if s.WebhookCircuitBreakerFailureThreshold < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-token-webhook-circuit-breaker-failure-threshold must not be negative, but is: %d", s.WebhookCircuitBreakerFailureThreshold))
+	}
+	if s.WebhookCircuitBreakerFailureThreshold > 0 && s.WebhookCircuitBreakerOpenDuration <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-token-webhook-circuit-breaker-open-duration must be greater than zero, but is: %v", s.WebhookCircuitBreakerOpenDuration))
+	}
+	if s.WebhookMaxTokenLength < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-token-webhook-max-token-length must not be negative, but is: %d", s.WebhookMaxTokenLength))
+	}
+	if s.ThrottleMaxFailures < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-throttle-max-failures must not be negative, but is: %d", s.ThrottleMaxFailures))
+	}
+	if s.ThrottleMaxFailures > 0 {
+		if s.ThrottleWindow <= 0 {
+			allErrors = append(allErrors, fmt.Errorf("--authentication-throttle-window must be greater than zero, but is: %v", s.ThrottleWindow))
+		}
+		if len(s.ThrottleKeys) == 0 {
+			allErrors = append(allErrors, fmt.Errorf("--authentication-throttle-keys must not be empty"))
+		}
+		for _, key := range s.ThrottleKeys {
+			if key != string(throttle.KeySourceIP) && key != string(throttle.KeyCredential) {
+				allErrors = append(allErrors, fmt.Errorf("--authentication-throttle-keys must only contain %q or %q, but contains %q", throttle.KeySourceIP, throttle.KeyCredential, key))
+			}
+		}
+	}
+	if _, err := s.UserNormalization(); err != nil {
+		allErrors = append(allErrors, err)
+	}
+	if s.MaxUserExtraSize < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-max-user-extra-size must not be negative, but is: %d", s.MaxUserExtraSize))
+	}
+	if err := authenticatorfactory.ValidateMechanisms(s.UntrustedMechanisms); err != nil {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-untrusted-mechanisms: %v", err))
+	}
+	if err := authenticatorfactory.ValidateMechanisms(s.MechanismOrder); err != nil {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-mechanism-order: %v", err))
+	}
+	if err := authenticatorfactory.ValidateMechanisms(s.ShortCircuitMechanisms); err != nil {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-short-circuit-mechanisms: %v", err))
+	}
+	for _, file := range s.FailoverKubeConfigFiles {
+		if len(file) == 0 {
+			allErrors = append(allErrors, fmt.Errorf("--authentication-failover-kubeconfigs must not contain empty file names"))
+			break
+		}
+	}
+	if len(s.AuthenticationProviderEndpoint) > 0 && s.AuthenticationProviderTimeout <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-provider-timeout must be greater than zero, but is: %v", s.AuthenticationProviderTimeout))
+	}
+	if len(s.ExecAuthenticatorCommand) > 0 && s.ExecAuthenticatorTimeout <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-exec-timeout must be greater than zero, but is: %v", s.ExecAuthenticatorTimeout))
+	}
+	if len(s.ExecAuthenticatorCommand) == 0 && len(s.ExecAuthenticatorArgs) > 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-exec-args requires --authentication-exec-command"))
+	}
+	if s.DisableAnonymous && len(s.AnonymousPaths) > 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-anonymous-paths must not be set when anonymous authentication is disabled"))
+	}
+	for _, path := range s.AnonymousPaths {
+		allErrors = append(allErrors, validateAnonymousPath("authentication-anonymous-paths", path)...)
+	}
+	if s.DisableAnonymous && (len(s.AnonymousIdentityPaths) > 0 || len(s.AnonymousIdentityGroups) > 0) {
+		allErrors = append(allErrors, fmt.Errorf("--authentication-anonymous-identity-paths and --authentication-anonymous-identity-groups must not be set when anonymous authentication is disabled"))
+	}
+	if identities, err := s.AnonymousIdentities(); err != nil {
+		allErrors = append(allErrors, err)
+	} else {
+		for _, identity := range identities {
+			if len(identity.Paths) == 0 {
+				allErrors = append(allErrors, fmt.Errorf("--authentication-anonymous-identity-groups entries for %q require a --authentication-anonymous-identity-paths entry", identity.Username))
+			}
+			for _, path := range identity.Paths {
+				allErrors = append(allErrors, validateAnonymousPath("authentication-anonymous-identity-paths", path)...)
+			}
+		}
+	}
 
 	return allErrors
 }
@@ -264,12 +817,76 @@ func (s *DelegatingAuthenticationOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.RemoteKubeConfigFile, "authentication-kubeconfig", s.RemoteKubeConfigFile, ""+
 		"kubeconfig file pointing at the 'core' kubernetes server with enough rights to create "+
 		"tokenreviews.authentication.k8s.io."+optionalKubeConfigSentence)
+	fs.StringSliceVar(&s.FailoverKubeConfigFiles, "authentication-failover-kubeconfigs", s.FailoverKubeConfigFiles, ""+
+		"List of kubeconfig files pointing at further servers able to create tokenreviews.authentication.k8s.io. "+
+		"They are tried in order when the server of --authentication-kubeconfig is unavailable.")
 
 	fs.DurationVar(&s.CacheTTL, "authentication-token-webhook-cache-ttl", s.CacheTTL,
 		"The duration to cache responses from the webhook token authenticator.")
+	fs.DurationVar(&s.CacheFailureTTL, "authentication-token-webhook-cache-failure-ttl", s.CacheFailureTTL,
+		"The duration to cache failed responses from the webhook token authenticator. "+
+			"If 0, --authentication-token-webhook-cache-ttl is used.")
+	fs.IntVar(&s.CacheMaxEntries, "authentication-token-webhook-cache-max-entries", s.CacheMaxEntries,
+		"The maximum number of responses from the webhook token authenticator to cache. "+
+			"When exceeded, the least recently used responses are evicted. If 0, the cache is unbounded.")
+	fs.Float64Var(&s.CacheJitter, "authentication-token-webhook-cache-jitter", s.CacheJitter,
+		"The maximum fraction, in the range [0, 1), by which the cache duration of each response from "+
+			"the webhook token authenticator is randomly shortened to avoid revalidating many tokens at once.")
+	fs.DurationVar(&s.NegativeCacheTTL, "authentication-token-negative-cache-ttl", s.NegativeCacheTTL,
+		"The duration to reject recently rejected bearer tokens without consulting the token authenticators, "+
+			"protecting the authentication webhook from clients retrying with an invalid or expired token. If 0, rejections are not cached.")
+	fs.DurationVar(&s.GroupResolverCacheTTL, "authentication-group-resolver-cache-ttl", s.GroupResolverCacheTTL,
+		"The duration to cache the groups resolved for an authenticated user, if the server resolves groups from an external source. "+
+			"If 0, groups are resolved for every request.")
+	fs.IntVar(&s.WebhookCircuitBreakerFailureThreshold, "authentication-token-webhook-circuit-breaker-failure-threshold", s.WebhookCircuitBreakerFailureThreshold,
+		"The number of consecutive failed requests after which a token review endpoint is skipped for "+
+			"--authentication-token-webhook-circuit-breaker-open-duration. If 0, endpoints are never skipped.")
+	fs.DurationVar(&s.WebhookCircuitBreakerOpenDuration, "authentication-token-webhook-circuit-breaker-open-duration", s.WebhookCircuitBreakerOpenDuration,
+		"The duration a failing token review endpoint is skipped before a single request probes whether it is available again.")
+	fs.IntVar(&s.WebhookMaxTokenLength, "authentication-token-webhook-max-token-length", s.WebhookMaxTokenLength,
+		"The maximum length in bytes of bearer tokens sent to the webhook token authenticator. "+
+			"Longer tokens are rejected without a token review. If 0, the length is not limited.")
+	fs.StringVar(&s.WebhookTokenAllowedCharacters, "authentication-token-webhook-allowed-characters", s.WebhookTokenAllowedCharacters, ""+
+		"The characters bearer tokens sent to the webhook token authenticator may consist of, e.g. the characters of RFC 6750: "+
+		"'"+shape.Token68Characters+"'. Tokens with other characters are rejected without a token review. If empty, any character is allowed.")
+	fs.BoolVar(&s.WebhookValidateJWTShape, "authentication-token-webhook-validate-jwt-shape", s.WebhookValidateJWTShape, ""+
+		"If true, bearer tokens of three dot separated segments must be well formed JWTs, with base64url encoded segments "+
+		"and a JSON header, to be sent to the webhook token authenticator.")
+	fs.BoolVar(&s.TokenCertificateBinding, "authentication-token-certificate-binding", s.TokenCertificateBinding, ""+
+		"If true, bearer tokens bound to a client certificate by a cnf claim with an x5t#S256 thumbprint (RFC 8705) "+
+		"are only accepted over TLS connections presenting that client certificate. Tokens with a cnf claim of other "+
+		"confirmation methods are rejected.")
+
+	fs.IntVar(&s.ThrottleMaxFailures, "authentication-throttle-max-failures", s.ThrottleMaxFailures, ""+
+		"The number of rejected bearer tokens per key allowed within --authentication-throttle-window. Further requests "+
+		"of the key are refused with 429 without consulting the token authenticators until the oldest failure leaves the window. "+
+		"If 0, failed attempts are not throttled.")
+	fs.DurationVar(&s.ThrottleWindow, "authentication-throttle-window", s.ThrottleWindow,
+		"The duration of the sliding window in which rejected bearer tokens are counted.")
+	fs.StringSliceVar(&s.ThrottleKeys, "authentication-throttle-keys", s.ThrottleKeys, ""+
+		"The keys by which rejected bearer tokens are counted: source-ip for the remote address of the connection, "+
+		"credential for the presented credentials.")
+	fs.BoolVar(&s.LowercaseUsernames, "authentication-lowercase-usernames", s.LowercaseUsernames,
+		"If true, the names of authenticated users are lowercased.")
+	fs.StringSliceVar(&s.GroupPrefixMappings, "authentication-group-prefix-mappings", s.GroupPrefixMappings, ""+
+		"Rewrites of the prefixes of the groups of authenticated users, as <from>=<to> entries, e.g. oidc:=corp:. "+
+		"An entry with an empty <to> strips the prefix. The first entry matching a group is applied.")
+	fs.IntVar(&s.MaxUserExtraSize, "authentication-max-user-extra-size", s.MaxUserExtraSize, ""+
+		"The maximum total size in bytes of the keys and values of the extra fields of an authenticated user. "+
+		"Users exceeding it are rejected. If 0, the size is not limited.")
+	fs.StringSliceVar(&s.UntrustedMechanisms, "authentication-untrusted-mechanisms", s.UntrustedMechanisms, ""+
+		"The authentication mechanisms not trusted to authenticate users and groups reserved for the system: "+
+		"their users with a name or group starting with system: are rejected.")
+	fs.StringSliceVar(&s.MechanismOrder, "authentication-mechanism-order", s.MechanismOrder, ""+
+		"The authentication mechanisms to try first, in order. Mechanisms not listed are tried afterwards in the default order "+
+		strings.Join(authenticatorfactory.DefaultMechanismOrder, ",")+". Anonymous authentication is always tried last.")
+	fs.StringSliceVar(&s.ShortCircuitMechanisms, "authentication-short-circuit-mechanisms", s.ShortCircuitMechanisms, ""+
+		"The authentication mechanisms whose failures are definitive: a request presenting credentials of such a mechanism "+
+		"that fail to authenticate is rejected without trying the remaining mechanisms, e.g. x509 for invalid client certificates.")
 
 	s.ClientCert.AddFlags(fs)
 	s.RequestHeader.AddFlags(fs)
+	s.SPIFFE.AddFlags(fs)
 
 	fs.BoolVar(&s.SkipInClusterLookup, "authentication-skip-lookup", s.SkipInClusterLookup, ""+
 		"If false, the authentication-kubeconfig will be used to lookup missing authentication "+
@@ -277,6 +894,40 @@ func (s *DelegatingAuthenticationOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&s.TolerateInClusterLookupFailure, "authentication-tolerate-lookup-failure", s.TolerateInClusterLookupFailure, ""+
 		"If true, failures to look up missing authentication configuration from the cluster are not considered fatal. "+
 		"Note that this can result in authentication that treats all requests as anonymous.")
+
+	fs.StringSliceVar(&s.AnonymousPaths, "authentication-anonymous-paths", s.AnonymousPaths, ""+
+		"List of request paths on which anonymous requests are allowed, e.g. /healthz,/readyz,/livez. "+
+		"A path ending in '*' matches every path with that prefix. Anonymous requests to any other path are rejected. "+
+		"If empty, anonymous requests are allowed on every path.")
+	fs.StringSliceVar(&s.AnonymousIdentityPaths, "authentication-anonymous-identity-paths", s.AnonymousIdentityPaths, ""+
+		"List of <username>=<path> entries authenticating anonymous requests to the path as the given user instead of system:anonymous, "+
+		"e.g. system:probe=/healthz,system:probe=/readyz*, so that probes can be told apart from other anonymous requests. "+
+		"A path ending in '*' matches every path with that prefix. The first matching user applies. "+
+		"The users are members of system:unauthenticated. This does not allow anonymous requests to paths excluded by --authentication-anonymous-paths.")
+	fs.StringSliceVar(&s.AnonymousIdentityGroups, "authentication-anonymous-identity-groups", s.AnonymousIdentityGroups, ""+
+		"List of <username>=<group> entries adding groups to the users of --authentication-anonymous-identity-paths.")
+
+	fs.StringVar(&s.AuthenticationProviderEndpoint, "authentication-provider-endpoint", s.AuthenticationProviderEndpoint, ""+
+		"The unix:// endpoint of an external authentication provider implementing the gRPC AuthenticationProvider service. "+
+		"Bearer tokens are sent to it before they are sent to the token review webhook.")
+	fs.DurationVar(&s.AuthenticationProviderTimeout, "authentication-provider-timeout", s.AuthenticationProviderTimeout,
+		"The time limit for calls to the external authentication provider.")
+	fs.StringVar(&s.ExecAuthenticatorCommand, "authentication-exec-command", s.ExecAuthenticatorCommand, ""+
+		"A command run to authenticate bearer tokens, for servers for which running a token review webhook is impractical. "+
+		"It reads the token from its standard input and writes the status of an authentication.k8s.io/v1 TokenReview as JSON "+
+		"to its standard output. Its responses are cached like those of the token review webhook.")
+	fs.StringSliceVar(&s.ExecAuthenticatorArgs, "authentication-exec-args", s.ExecAuthenticatorArgs,
+		"The arguments of --authentication-exec-command.")
+	fs.DurationVar(&s.ExecAuthenticatorTimeout, "authentication-exec-timeout", s.ExecAuthenticatorTimeout,
+		"The time limit for a run of --authentication-exec-command.")
+
+	fs.StringVar(&s.AuthenticationConfigFile, "authentication-config", s.AuthenticationConfigFile, ""+
+		"File with the authentication configuration to configure the JWT token authenticators. "+
+		"Changes to the file are picked up without restarting the server.")
+
+	fs.StringVar(&s.TokenFile, "authentication-token-file", s.TokenFile, ""+
+		"File with static bearer tokens, one 'token,user,uid,\"group1,group2\"' record per line. "+
+		"Changes to the file are picked up without restarting the server.")
 }
 
 func (s *DelegatingAuthenticationOptions) ApplyTo(authenticationInfo *server.AuthenticationInfo, servingInfo *server.SecureServingInfo, openAPIConfig *openapicommon.Config) error {
@@ -285,11 +936,44 @@ func (s *DelegatingAuthenticationOptions) ApplyTo(authenticationInfo *server.Aut
 		return nil
 	}
 
+	anonymousIdentities, err := s.AnonymousIdentities()
+	if err != nil {
+		return err
+	}
+	userNormalization, err := s.UserNormalization()
+	if err != nil {
+		return err
+	}
+
 	cfg := authenticatorfactory.DelegatingAuthenticatorConfig{
 		Anonymous:                !s.DisableAnonymous,
+		AnonymousPaths:           s.AnonymousPaths,
+		AnonymousIdentities:      anonymousIdentities,
 		CacheTTL:                 s.CacheTTL,
+		CacheFailureTTL:          s.CacheFailureTTL,
+		CacheMaxEntries:          s.CacheMaxEntries,
+		CacheJitter:              s.CacheJitter,
+		NegativeCacheTTL:         s.NegativeCacheTTL,
 		WebhookRetryBackoff:      s.WebhookRetryBackoff,
 		TokenAccessReviewTimeout: s.TokenRequestTimeout,
+		TokenCertificateBinding:  s.TokenCertificateBinding,
+		UserNormalization:        userNormalization,
+		UntrustedMechanisms:      s.UntrustedMechanisms,
+		MechanismOrder:           s.MechanismOrder,
+		ShortCircuitMechanisms:   s.ShortCircuitMechanisms,
+		TracerProvider:           s.TracerProvider,
+		GroupResolver:            s.GroupResolver,
+		GroupResolverCacheTTL:    s.GroupResolverCacheTTL,
+	}
+
+	if s.ThrottleMaxFailures > 0 {
+		cfg.Throttle = &throttle.Config{
+			MaxFailures: s.ThrottleMaxFailures,
+			Window:      s.ThrottleWindow,
+		}
+		for _, key := range s.ThrottleKeys {
+			cfg.Throttle.Keys = append(cfg.Throttle.Keys, throttle.Key(key))
+		}
 	}
 
 	client, err := s.getClient()
@@ -300,10 +984,69 @@ func (s *DelegatingAuthenticationOptions) ApplyTo(authenticationInfo *server.Aut
 	// configure token review
 	if client != nil {
 		cfg.TokenAccessReviewClient = client.AuthenticationV1()
+
+		for _, file := range s.FailoverKubeConfigFiles {
+			failoverClient, err := s.clientFromKubeConfigFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to get delegated authentication failover kubeconfig: %v", err)
+			}
+			cfg.TokenAccessReviewFailoverClients = append(cfg.TokenAccessReviewFailoverClients, failoverClient.AuthenticationV1())
+		}
+		if s.WebhookCircuitBreakerFailureThreshold > 0 {
+			cfg.WebhookCircuitBreaker = &webhooktoken.CircuitBreakerConfig{
+				FailureThreshold: s.WebhookCircuitBreakerFailureThreshold,
+				OpenDuration:     s.WebhookCircuitBreakerOpenDuration,
+			}
+		}
+		if s.WebhookMaxTokenLength > 0 || len(s.WebhookTokenAllowedCharacters) > 0 || s.WebhookValidateJWTShape {
+			cfg.WebhookTokenShape = &shape.Config{
+				MaxLength:         s.WebhookMaxTokenLength,
+				AllowedCharacters: s.WebhookTokenAllowedCharacters,
+				ValidateJWTShape:  s.WebhookValidateJWTShape,
+			}
+		}
+	}
+
+	// configure the JWT authenticators
+	if len(s.AuthenticationConfigFile) > 0 {
+		jwtAuthenticator, err := authenticatorfactory.NewDynamicJWTAuthenticator(s.AuthenticationConfigFile)
+		if err != nil {
+			return fmt.Errorf("unable to load authentication configuration: %v", err)
+		}
+		cfg.JWTAuthenticator = jwtAuthenticator
+		authenticationInfo.ConfigReloaders = append(authenticationInfo.ConfigReloaders, jwtAuthenticator)
+	}
+
+	// configure the static token file
+	if len(s.TokenFile) > 0 {
+		tokenFileAuthenticator, err := tokenfile.NewDynamicCSV(s.TokenFile)
+		if err != nil {
+			return fmt.Errorf("unable to load token file: %v", err)
+		}
+		cfg.TokenFileAuthenticator = tokenFileAuthenticator
+		authenticationInfo.ConfigReloaders = append(authenticationInfo.ConfigReloaders, tokenFileAuthenticator)
+	}
+
+	// configure the external authentication provider, its connection lives as long as the process
+	if len(s.AuthenticationProviderEndpoint) > 0 {
+		provider, err := grpcprovider.New(context.Background(), s.AuthenticationProviderEndpoint, authenticationInfo.APIAudiences, s.AuthenticationProviderTimeout)
+		if err != nil {
+			return fmt.Errorf("unable to configure the authentication provider: %v", err)
+		}
+		cfg.AuthenticationProvider = provider
+	}
+
+	// configure the authentication command
+	if len(s.ExecAuthenticatorCommand) > 0 {
+		execAuthenticator, err := execauthenticator.New(s.ExecAuthenticatorCommand, s.ExecAuthenticatorArgs, authenticationInfo.APIAudiences, s.ExecAuthenticatorTimeout)
+		if err != nil {
+			return fmt.Errorf("unable to configure the authentication command: %v", err)
+		}
+		cfg.ExecAuthenticator = execAuthenticator
 	}
 
 	// get the clientCA information
-	clientCASpecified := s.ClientCert != ClientCertAuthenticationOptions{}
+	clientCASpecified := len(s.ClientCert.ClientCA) > 0 || s.ClientCert.CAContentProvider != nil
 	var clientCAProvider dynamiccertificates.CAContentProvider
 	if clientCASpecified {
 		clientCAProvider, err = s.ClientCert.GetClientCAContentProvider()
@@ -331,6 +1074,38 @@ func (s *DelegatingAuthenticationOptions) ApplyTo(authenticationInfo *server.Aut
 		}
 	}
 
+	if cfg.ClientCertificateCAContentProvider != nil {
+		cfg.ClientCertificateExpirationWarningThreshold = s.ClientCert.ExpirationWarningThreshold
+		cfg.ClientCertificateUserConversion, err = s.ClientCert.GetUserConversion()
+		if err != nil {
+			return fmt.Errorf("unable to create client certificate user mapping: %v", err)
+		}
+		revocationChecker, err := s.ClientCert.GetRevocationChecker()
+		if err != nil {
+			return fmt.Errorf("unable to load client certificate revocation checker: %v", err)
+		}
+		cfg.ClientCertificateRevocationChecker = revocationChecker
+		if checkers, ok := revocationChecker.(x509request.RevocationCheckers); ok {
+			for _, checker := range checkers {
+				if runner, ok := checker.(dynamiccertificates.ControllerRunner); ok {
+					authenticationInfo.ConfigReloaders = append(authenticationInfo.ConfigReloaders, runner)
+				}
+			}
+		}
+	}
+
+	if len(s.SPIFFE.TrustBundleFile) > 0 {
+		trustBundle, err := dynamiccertificates.NewDynamicCAContentFromFile("spiffe-trust-bundle", s.SPIFFE.TrustBundleFile)
+		if err != nil {
+			return fmt.Errorf("unable to load SPIFFE trust bundle: %v", err)
+		}
+		config := s.SPIFFE.config(trustBundle.VerifyOptions)
+		cfg.SPIFFE = &config
+		if err = authenticationInfo.ApplyClientCert(trustBundle, servingInfo); err != nil {
+			return fmt.Errorf("unable to assign SPIFFE trust bundle: %v", err)
+		}
+	}
+
 	requestHeaderCAFileSpecified := len(s.RequestHeader.ClientCAFile) > 0
 	var requestHeaderConfig *authenticatorfactory.RequestHeaderConfig
 	if requestHeaderCAFileSpecified {
@@ -385,6 +1160,12 @@ const (
 )
 
 func (s *DelegatingAuthenticationOptions) createRequestHeaderConfig(client kubernetes.Interface) (*authenticatorfactory.RequestHeaderConfig, error) {
+	// the proxy identity scopes are local configuration, they are not published in the cluster
+	proxyScopes, err := s.RequestHeader.ProxyIdentityScopes()
+	if err != nil {
+		return nil, err
+	}
+
 	dynamicRequestHeaderProvider, err := newDynamicRequestHeaderController(client)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create request header authentication config: %v", err)
@@ -404,6 +1185,7 @@ func (s *DelegatingAuthenticationOptions) createRequestHeaderConfig(client kuber
 		GroupHeaders:        headerrequest.StringSliceProvider(headerrequest.StringSliceProviderFunc(dynamicRequestHeaderProvider.GroupHeaders)),
 		ExtraHeaderPrefixes: headerrequest.StringSliceProvider(headerrequest.StringSliceProviderFunc(dynamicRequestHeaderProvider.ExtraHeaderPrefixes)),
 		AllowedClientNames:  headerrequest.StringSliceProvider(headerrequest.StringSliceProviderFunc(dynamicRequestHeaderProvider.AllowedClientNames)),
+		ProxyIdentityScopes: proxyScopes,
 	}, nil
 }
 
@@ -432,6 +1214,23 @@ func (s *DelegatingAuthenticationOptions) getClient() (kubernetes.Interface, err
 		return nil, fmt.Errorf("failed to get delegated authentication kubeconfig: %v", err)
 	}
 
+	return s.newClient(clientConfig)
+}
+
+// clientFromKubeConfigFile returns a Kubernetes clientset for the given kubeconfig file,
+// configured like the one returned by getClient.
+func (s *DelegatingAuthenticationOptions) clientFromKubeConfigFile(file string) (kubernetes.Interface, error) {
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: file}
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+
+	clientConfig, err := loader.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return s.newClient(clientConfig)
+}
+
+func (s *DelegatingAuthenticationOptions) newClient(clientConfig *rest.Config) (kubernetes.Interface, error) {
 	// set high qps/burst limits since this will effectively limit API server responsiveness
 	clientConfig.QPS = 200
 	clientConfig.Burst = 400
@@ -442,6 +1241,9 @@ func (s *DelegatingAuthenticationOptions) getClient() (kubernetes.Interface, err
 	if s.CustomRoundTripperFn != nil {
 		clientConfig.Wrap(s.CustomRoundTripperFn)
 	}
+	if s.TracerProvider != nil {
+		clientConfig.Wrap(tracing.WrapperFor(s.TracerProvider))
+	}
 
 	return kubernetes.NewForConfig(clientConfig)
 }
@@ -22,8 +22,10 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"k8s.io/apiserver/pkg/authentication/authenticatorfactory"
+	"k8s.io/apiserver/pkg/authentication/request/anonymous"
 	"k8s.io/apiserver/pkg/authentication/request/headerrequest"
 	"k8s.io/apiserver/pkg/server"
 	openapicommon "k8s.io/kube-openapi/pkg/common"
@@ -61,6 +63,30 @@ func TestToAuthenticationRequestHeaderConfig(t *testing.T) {
 				AllowedClientNames:  headerrequest.StaticStringSlice{"kube-aggregator"},
 			},
 		},
+		{
+			name: "test with proxy identity scopes",
+			testOptions: &RequestHeaderAuthenticationOptions{
+				ClientCAFile:          "testdata/root.pem",
+				UsernameHeaders:       headerrequest.StaticStringSlice{"x-remote-user"},
+				GroupHeaders:          headerrequest.StaticStringSlice{"x-remote-group"},
+				ExtraHeaderPrefixes:   headerrequest.StaticStringSlice{"x-remote-extra-"},
+				AllowedNames:          headerrequest.StaticStringSlice{"proxy-a", "proxy-b"},
+				ProxyAllowedUsernames: []string{"proxy-a=oidc:*", "proxy-b=bob", "proxy-a=alice"},
+				ProxyAllowedGroups:    []string{"proxy-a=oidc:*"},
+				ProxyAllowedExtraKeys: []string{"proxy-a=oidc.example.com/*"},
+			},
+			expectConfig: &authenticatorfactory.RequestHeaderConfig{
+				UsernameHeaders:     headerrequest.StaticStringSlice{"x-remote-user"},
+				GroupHeaders:        headerrequest.StaticStringSlice{"x-remote-group"},
+				ExtraHeaderPrefixes: headerrequest.StaticStringSlice{"x-remote-extra-"},
+				CAContentProvider:   nil, // this is nil because you can't compare functions
+				AllowedClientNames:  headerrequest.StaticStringSlice{"proxy-a", "proxy-b"},
+				ProxyIdentityScopes: headerrequest.ProxyIdentityScopes{
+					{ProxyClientName: "proxy-a", AllowedUsernames: []string{"oidc:*", "alice"}, AllowedGroups: []string{"oidc:*"}, AllowedExtraKeys: []string{"oidc.example.com/*"}},
+					{ProxyClientName: "proxy-b", AllowedUsernames: []string{"bob"}},
+				},
+			},
+		},
 	}
 
 	for _, testcase := range testCases {
@@ -108,6 +134,38 @@ current-context: cluster
 	}
 	remoteKubeconfig := f.Name()
 
+	authConfig, err := ioutil.TempFile("", "authconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(authConfig.Name())
+
+	if err := ioutil.WriteFile(authConfig.Name(), []byte(`
+apiVersion: apiserver.config.k8s.io/v1alpha1
+kind: AuthenticationConfiguration
+jwt:
+- issuer:
+    url: https://issuer.example.com
+    audiences:
+    - my-apiserver
+  claimMappings:
+    username:
+      claim: sub
+`), os.FileMode(0644)); err != nil {
+		t.Fatal(err)
+	}
+	authenticationConfigFile := authConfig.Name()
+
+	tokenFile, err := ioutil.TempFile("", "tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tokenFile.Name())
+
+	if err := ioutil.WriteFile(tokenFile.Name(), []byte("bar,user,uid\n"), os.FileMode(0644)); err != nil {
+		t.Fatal(err)
+	}
+
 	testcases := []struct {
 		name                 string
 		options              *DelegatingAuthenticationOptions
@@ -173,6 +231,52 @@ current-context: cluster
 			expectAuthenticator: true,  // anonymous auth
 			expectTokenErrors:   true,  // client fails making tokenreview calls
 		},
+		{
+			name: "authentication config, optional kubeconfig",
+			options: func() *DelegatingAuthenticationOptions {
+				opts := NewDelegatingAuthenticationOptions()
+				opts.RemoteKubeConfigFileOptional = true
+				opts.AuthenticationConfigFile = authenticationConfigFile
+				return opts
+			}(),
+			expectError:         false,
+			expectAuthenticator: true,
+			expectTokenErrors:   true, // "foo" is not a JWT of the configured issuer
+		},
+		{
+			name: "token file, optional kubeconfig",
+			options: func() *DelegatingAuthenticationOptions {
+				opts := NewDelegatingAuthenticationOptions()
+				opts.RemoteKubeConfigFileOptional = true
+				opts.TokenFile = tokenFile.Name()
+				return opts
+			}(),
+			expectError:         false,
+			expectAuthenticator: true,
+			expectTokenErrors:   true, // "foo" is not listed in the token file
+		},
+		{
+			name: "missing token file",
+			options: func() *DelegatingAuthenticationOptions {
+				opts := NewDelegatingAuthenticationOptions()
+				opts.RemoteKubeConfigFileOptional = true
+				opts.TokenFile = tokenFile.Name() + ".missing"
+				return opts
+			}(),
+			expectError:         true,
+			expectAuthenticator: false,
+		},
+		{
+			name: "missing authentication config",
+			options: func() *DelegatingAuthenticationOptions {
+				opts := NewDelegatingAuthenticationOptions()
+				opts.RemoteKubeConfigFileOptional = true
+				opts.AuthenticationConfigFile = authenticationConfigFile + ".missing"
+				return opts
+			}(),
+			expectError:         true,
+			expectAuthenticator: false,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -210,3 +314,300 @@ current-context: cluster
 		})
 	}
 }
+
+func TestDelegatingAuthenticationOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		modify      func(*DelegatingAuthenticationOptions)
+		expectError bool
+	}{
+		{
+			name:   "default",
+			modify: func(*DelegatingAuthenticationOptions) {},
+		},
+		{
+			name: "tuned cache",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.CacheFailureTTL = time.Second
+				o.CacheMaxEntries = 1024
+				o.CacheJitter = 0.2
+				o.NegativeCacheTTL = time.Second
+			},
+		},
+		{
+			name:        "negative failure ttl",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.CacheFailureTTL = -time.Second },
+			expectError: true,
+		},
+		{
+			name:        "negative max entries",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.CacheMaxEntries = -1 },
+			expectError: true,
+		},
+		{
+			name:        "negative negative cache ttl",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.NegativeCacheTTL = -time.Second },
+			expectError: true,
+		},
+		{
+			name:        "negative max token length",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.WebhookMaxTokenLength = -1 },
+			expectError: true,
+		},
+		{
+			name:        "negative group resolver cache ttl",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.GroupResolverCacheTTL = -time.Second },
+			expectError: true,
+		},
+		{
+			name: "webhook failover and circuit breaker",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.FailoverKubeConfigFiles = []string{"/etc/kubernetes/failover.kubeconfig"}
+				o.WebhookCircuitBreakerFailureThreshold = 3
+			},
+		},
+		{
+			name:        "negative circuit breaker failure threshold",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.WebhookCircuitBreakerFailureThreshold = -1 },
+			expectError: true,
+		},
+		{
+			name: "circuit breaker without open duration",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.WebhookCircuitBreakerFailureThreshold = 3
+				o.WebhookCircuitBreakerOpenDuration = 0
+			},
+			expectError: true,
+		},
+		{
+			name:        "empty failover kubeconfig",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.FailoverKubeConfigFiles = []string{""} },
+			expectError: true,
+		},
+		{
+			name: "invalid proxy allowed usernames",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.RequestHeader.ProxyAllowedUsernames = []string{"oidc:*"}
+			},
+			expectError: true,
+		},
+		{
+			name: "authentication provider",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.AuthenticationProviderEndpoint = "unix:///var/run/authn.sock"
+			},
+		},
+		{
+			name: "authentication provider without timeout",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.AuthenticationProviderEndpoint = "unix:///var/run/authn.sock"
+				o.AuthenticationProviderTimeout = 0
+			},
+			expectError: true,
+		},
+		{
+			name:   "anonymous paths",
+			modify: func(o *DelegatingAuthenticationOptions) { o.AnonymousPaths = []string{"/healthz", "/readyz*"} },
+		},
+		{
+			name:        "anonymous path without leading slash",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.AnonymousPaths = []string{"healthz"} },
+			expectError: true,
+		},
+		{
+			name:        "anonymous path with inner wildcard",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.AnonymousPaths = []string{"/api/*/v1"} },
+			expectError: true,
+		},
+		{
+			name: "anonymous paths with anonymous disabled",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.DisableAnonymous = true
+				o.AnonymousPaths = []string{"/healthz"}
+			},
+			expectError: true,
+		},
+		{
+			name: "anonymous identities",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.AnonymousIdentityPaths = []string{"system:probe=/healthz", "system:probe=/readyz*"}
+				o.AnonymousIdentityGroups = []string{"system:probe=system:probes"}
+			},
+		},
+		{
+			name: "anonymous identity without path",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.AnonymousIdentityGroups = []string{"system:probe=system:probes"}
+			},
+			expectError: true,
+		},
+		{
+			name:        "anonymous identity with invalid path",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.AnonymousIdentityPaths = []string{"system:probe=healthz"} },
+			expectError: true,
+		},
+		{
+			name:        "malformed anonymous identity",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.AnonymousIdentityPaths = []string{"/healthz"} },
+			expectError: true,
+		},
+		{
+			name: "anonymous identities with anonymous disabled",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.DisableAnonymous = true
+				o.AnonymousIdentityPaths = []string{"system:probe=/healthz"}
+			},
+			expectError: true,
+		},
+		{
+			name: "client certificate revocation",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.ClientCert.OCSP = true
+				o.ClientCert.OCSPResponder = "http://ocsp.example.com"
+				o.ClientCert.OCSPSoftFail = true
+			},
+		},
+		{
+			name:   "throttle",
+			modify: func(o *DelegatingAuthenticationOptions) { o.ThrottleMaxFailures = 10 },
+		},
+		{
+			name: "throttle without window",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.ThrottleMaxFailures = 10
+				o.ThrottleWindow = 0
+			},
+			expectError: true,
+		},
+		{
+			name: "throttle by unknown key",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.ThrottleMaxFailures = 10
+				o.ThrottleKeys = []string{"user-agent"}
+			},
+			expectError: true,
+		},
+		{
+			name:   "group prefix mappings",
+			modify: func(o *DelegatingAuthenticationOptions) { o.GroupPrefixMappings = []string{"oidc:=corp:", "ldap:="} },
+		},
+		{
+			name:        "group prefix mapping without prefix",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.GroupPrefixMappings = []string{"=corp:"} },
+			expectError: true,
+		},
+		{
+			name:        "negative max user extra size",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.MaxUserExtraSize = -1 },
+			expectError: true,
+		},
+		{
+			name:        "unknown untrusted mechanism",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.UntrustedMechanisms = []string{"oidc"} },
+			expectError: true,
+		},
+		{
+			name: "exec authenticator without timeout",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.ExecAuthenticatorCommand = "/usr/bin/authenticate"
+				o.ExecAuthenticatorTimeout = 0
+			},
+			expectError: true,
+		},
+		{
+			name:        "exec authenticator args without command",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.ExecAuthenticatorArgs = []string{"--verbose"} },
+			expectError: true,
+		},
+		{
+			name:   "mechanism order",
+			modify: func(o *DelegatingAuthenticationOptions) { o.MechanismOrder = []string{"bearertoken", "x509"} },
+		},
+		{
+			name:        "unknown mechanism in order",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.MechanismOrder = []string{"kerberos"} },
+			expectError: true,
+		},
+		{
+			name:        "repeated short-circuit mechanism",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.ShortCircuitMechanisms = []string{"x509", "x509"} },
+			expectError: true,
+		},
+		{
+			name:        "negative client certificate expiration warning threshold",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.ClientCert.ExpirationWarningThreshold = -time.Hour },
+			expectError: true,
+		},
+		{
+			name:        "ocsp responder without ocsp",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.ClientCert.OCSPResponder = "http://ocsp.example.com" },
+			expectError: true,
+		},
+		{
+			name: "client certificate user mapping",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.ClientCert.UserMapping.UsernameSource = "EmailSAN"
+				o.ClientCert.UserMapping.GroupsSource = "OrganizationalUnit"
+			},
+		},
+		{
+			name:        "invalid client certificate user mapping",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.ClientCert.UserMapping.UsernamePattern = "(" },
+			expectError: true,
+		},
+		{
+			name: "spiffe",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.SPIFFE.TrustBundleFile = "bundle.pem"
+				o.SPIFFE.TrustDomain = "example.org"
+				o.SPIFFE.GroupTemplates = []string{"spiffe:{{.TrustDomain}}"}
+			},
+		},
+		{
+			name:        "spiffe trust domain without bundle",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.SPIFFE.TrustDomain = "example.org" },
+			expectError: true,
+		},
+		{
+			name: "spiffe invalid template",
+			modify: func(o *DelegatingAuthenticationOptions) {
+				o.SPIFFE.TrustBundleFile = "bundle.pem"
+				o.SPIFFE.TrustDomain = "example.org"
+				o.SPIFFE.UsernameTemplate = "{{.ID"
+			},
+			expectError: true,
+		},
+		{
+			name:        "jitter too large",
+			modify:      func(o *DelegatingAuthenticationOptions) { o.CacheJitter = 1 },
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewDelegatingAuthenticationOptions()
+			tc.modify(o)
+			if errs := o.Validate(); (len(errs) > 0) != tc.expectError {
+				t.Errorf("expected error=%v, got %v", tc.expectError, errs)
+			}
+		})
+	}
+}
+
+func TestAnonymousIdentities(t *testing.T) {
+	o := NewDelegatingAuthenticationOptions()
+	o.AnonymousIdentityPaths = []string{"system:probe=/healthz", "system:scraper=/metrics", "system:probe=/readyz*"}
+	o.AnonymousIdentityGroups = []string{"system:probe=system:probes", "system:probe=probes"}
+
+	identities, err := o.AnonymousIdentities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []anonymous.PathIdentity{
+		{Username: "system:probe", Paths: []string{"/healthz", "/readyz*"}, Groups: []string{"system:probes", "probes"}},
+		{Username: "system:scraper", Paths: []string{"/metrics"}},
+	}
+	if !reflect.DeepEqual(identities, expected) {
+		t.Errorf("unexpected identities: %#v", identities)
+	}
+}
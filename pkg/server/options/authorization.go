@@ -17,17 +17,22 @@ limitations under the License.
 package options
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
 	"k8s.io/apiserver/pkg/authorization/path"
 	"k8s.io/apiserver/pkg/authorization/union"
 	"k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/plugin/pkg/authorizer/webhook"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -35,6 +40,26 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// The decisions of the webhook authorizer once WebhookTimeout expires.
+const (
+	timeoutDecisionDeny      = "deny"
+	timeoutDecisionNoOpinion = "no-opinion"
+)
+
+const (
+	// webhookQPS and webhookBurst limit the reviews sent to the 'core' kubernetes server. They are
+	// high since this effectively limits API server responsiveness.
+	webhookQPS   = 200
+	webhookBurst = 400
+)
+
+const (
+	// cacheWarmWorkers is the number of requests reviewed at a time to warm the authorization cache.
+	cacheWarmWorkers = 4
+	// cacheWarmTimeout bounds the warming of the authorization cache.
+	cacheWarmTimeout = 5 * time.Minute
+)
+
 // DelegatingAuthorizationOptions provides an easy way for composing API servers to delegate their authorization to
 // the root kube API server.
 // WARNING: never assume that every authenticated incoming request already does authorization.
@@ -49,6 +74,18 @@ type DelegatingAuthorizationOptions struct {
 	// a missing in-cluster config will be fatal.
 	RemoteKubeConfigFileOptional bool
 
+	// SecondaryRemoteKubeConfigFile is the file to use to connect to a kube API server reviewing the
+	// requests while the one of RemoteKubeConfigFile is unhealthy. It is optional.
+	SecondaryRemoteKubeConfigFile string
+
+	// WebhookFailoverThreshold is the number of consecutive failures after which a kube API server is
+	// considered unhealthy, and the requests fail over to the secondary one.
+	WebhookFailoverThreshold int
+
+	// WebhookProbeInterval is the minimum time between health probes of an unhealthy kube API server,
+	// after which the requests fail back to it.
+	WebhookProbeInterval time.Duration
+
 	// AllowCacheTTL is the length of time that a successful authorization response will be cached
 	AllowCacheTTL time.Duration
 
@@ -56,10 +93,21 @@ type DelegatingAuthorizationOptions struct {
 	// You generally want more responsive, "deny, try again" flows.
 	DenyCacheTTL time.Duration
 
+	// AllowCacheMaxEntries is the maximum number of successful authorization responses that will be cached.
+	AllowCacheMaxEntries int
+
+	// DenyCacheMaxEntries is the maximum number of unsuccessful authorization responses that will be cached.
+	DenyCacheMaxEntries int
+
 	// AlwaysAllowPaths are HTTP paths which are excluded from authorization. They can be plain
 	// paths or end in * in which case prefix-match is applied. A leading / is optional.
 	AlwaysAllowPaths []string
 
+	// AlwaysAllowRules are rules of paths which are excluded from authorization only for some verbs and
+	// from some networks, in the format of path.ParseRule. They complement AlwaysAllowPaths, which are
+	// excluded for all verbs and from anywhere.
+	AlwaysAllowRules []string
+
 	// AlwaysAllowGroups are groups which are allowed to take any actions.  In kube, this is system:masters.
 	AlwaysAllowGroups []string
 
@@ -74,15 +122,58 @@ type DelegatingAuthorizationOptions struct {
 
 	// CustomRoundTripperFn allows for specifying a middleware function for custom HTTP behaviour for the authorization webhook client.
 	CustomRoundTripperFn transport.WrapperFunc
+
+	// WebhookMatchConditions are CEL expressions over the SubjectAccessReview of a request, available as
+	// the variable "request". Only requests for which all of them evaluate to true are sent for review.
+	WebhookMatchConditions []string
+
+	// WebhookTimeout bounds the time the webhook authorizer takes to decide on a request, including
+	// retries, separately from the deadline of the request. Zero means no bound.
+	WebhookTimeout time.Duration
+
+	// WebhookTimeoutDecision is the decision of the webhook authorizer once WebhookTimeout expires,
+	// "deny" or "no-opinion".
+	WebhookTimeoutDecision string
+
+	// WebhookPriorityQueueing queues the reviews exceeding the rate limit of the webhook authorizer by the
+	// API Priority and Fairness priority level of their requests, rather than in order of arrival.
+	WebhookPriorityQueueing bool
+
+	// WebhookPriorityLevels orders the priority levels from the highest to the lowest priority for
+	// WebhookPriorityQueueing. If empty, webhook.DefaultPriorityLevels is used.
+	WebhookPriorityLevels []string
+
+	// WebhookCacheWarmAuditLog is the path to an audit log in JSON format. The distinct requests in it are
+	// reviewed in the background at start, to populate the cache of the webhook authorizer. It is optional.
+	WebhookCacheWarmAuditLog string
+
+	// DenyListFile is the path to a file of rules denying the requests they match before any other
+	// authorizer is consulted, see authorizerfactory.DenyList. It is optional.
+	DenyListFile string
+
+	// VerbClassAuthorizers select the authorizers consulted for the requests of a verb class, each of the
+	// form "class=name,...", e.g. "read=privileged-groups,always-allow-paths". The classes are those of
+	// authorizerfactory.VerbClass, the names those of authorizerNames. The selected authorizers are consulted
+	// in the order of the chain. The requests of verb classes without a selection are authorized by the
+	// whole chain. The deny list is consulted for every verb class, whether selected or not.
+	VerbClassAuthorizers []string
 }
 
+// authorizerNames are the names of the authorizers of the chain, which VerbClassAuthorizers select from.
+var authorizerNames = sets.NewString("deny-list", "privileged-groups", "always-allow-paths", "webhook")
+
 func NewDelegatingAuthorizationOptions() *DelegatingAuthorizationOptions {
 	return &DelegatingAuthorizationOptions{
 		// very low for responsiveness, but high enough to handle storms
-		AllowCacheTTL:       10 * time.Second,
-		DenyCacheTTL:        10 * time.Second,
-		ClientTimeout:       10 * time.Second,
-		WebhookRetryBackoff: DefaultAuthWebhookRetryBackoff(),
+		AllowCacheTTL:            10 * time.Second,
+		DenyCacheTTL:             10 * time.Second,
+		AllowCacheMaxEntries:     webhook.DefaultCacheMaxEntries,
+		DenyCacheMaxEntries:      webhook.DefaultCacheMaxEntries,
+		ClientTimeout:            10 * time.Second,
+		WebhookRetryBackoff:      DefaultAuthWebhookRetryBackoff(),
+		WebhookFailoverThreshold: webhook.DefaultFailoverThreshold,
+		WebhookProbeInterval:     webhook.DefaultProbeInterval,
+		WebhookTimeoutDecision:   timeoutDecisionNoOpinion,
 		// This allows the kubelet to always get health and readiness without causing an authorization check.
 		// This field can be cleared by callers if they don't want this behavior.
 		AlwaysAllowPaths: []string{"/healthz", "/readyz", "/livez"},
@@ -130,6 +221,35 @@ func (s *DelegatingAuthorizationOptions) Validate() []error {
 	if s.WebhookRetryBackoff != nil && s.WebhookRetryBackoff.Steps <= 0 {
 		allErrors = append(allErrors, fmt.Errorf("number of webhook retry attempts must be greater than 1, but is: %d", s.WebhookRetryBackoff.Steps))
 	}
+	if s.AllowCacheMaxEntries <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authorization-webhook-cache-authorized-max-entries must be greater than zero, but is: %d", s.AllowCacheMaxEntries))
+	}
+	if s.DenyCacheMaxEntries <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authorization-webhook-cache-unauthorized-max-entries must be greater than zero, but is: %d", s.DenyCacheMaxEntries))
+	}
+	for _, rule := range s.AlwaysAllowRules {
+		if _, err := path.ParseRule(rule); err != nil {
+			allErrors = append(allErrors, fmt.Errorf("--authorization-always-allow-rules: %v", err))
+		}
+	}
+	if s.WebhookTimeout < 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authorization-webhook-timeout must not be negative, but is: %v", s.WebhookTimeout))
+	}
+	if s.WebhookTimeoutDecision != timeoutDecisionDeny && s.WebhookTimeoutDecision != timeoutDecisionNoOpinion {
+		allErrors = append(allErrors, fmt.Errorf("--authorization-webhook-timeout-decision must be %q or %q, but is: %q", timeoutDecisionDeny, timeoutDecisionNoOpinion, s.WebhookTimeoutDecision))
+	}
+	if s.WebhookFailoverThreshold <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authorization-webhook-failover-threshold must be greater than zero, but is: %d", s.WebhookFailoverThreshold))
+	}
+	if s.WebhookProbeInterval <= 0 {
+		allErrors = append(allErrors, fmt.Errorf("--authorization-webhook-probe-interval must be greater than zero, but is: %v", s.WebhookProbeInterval))
+	}
+	if err := webhook.ValidateMatchConditions(s.matchConditions()); err != nil {
+		allErrors = append(allErrors, fmt.Errorf("--authorization-webhook-match-conditions: %v", err))
+	}
+	if _, err := parseVerbClassAuthorizers(s.VerbClassAuthorizers); err != nil {
+		allErrors = append(allErrors, fmt.Errorf("--authorization-verb-class-authorizers: %v", err))
+	}
 
 	return allErrors
 }
@@ -147,6 +267,19 @@ func (s *DelegatingAuthorizationOptions) AddFlags(fs *pflag.FlagSet) {
 		"kubeconfig file pointing at the 'core' kubernetes server with enough rights to create "+
 			"subjectaccessreviews.authorization.k8s.io."+optionalKubeConfigSentence)
 
+	fs.StringVar(&s.SecondaryRemoteKubeConfigFile, "authorization-secondary-kubeconfig", s.SecondaryRemoteKubeConfigFile,
+		"kubeconfig file pointing at a secondary 'core' kubernetes server with enough rights to create "+
+			"subjectaccessreviews.authorization.k8s.io. Requests are reviewed by it while the server of "+
+			"--authorization-kubeconfig is unhealthy. This is optional.")
+
+	fs.IntVar(&s.WebhookFailoverThreshold, "authorization-webhook-failover-threshold", s.WebhookFailoverThreshold,
+		"The number of consecutive failed reviews after which the 'core' kubernetes server is considered unhealthy, "+
+			"and requests fail over to the one of --authorization-secondary-kubeconfig.")
+
+	fs.DurationVar(&s.WebhookProbeInterval, "authorization-webhook-probe-interval", s.WebhookProbeInterval,
+		"The minimum time between health probes of an unhealthy 'core' kubernetes server. Requests fail back to "+
+			"the server of --authorization-kubeconfig once a probe succeeds.")
+
 	fs.DurationVar(&s.AllowCacheTTL, "authorization-webhook-cache-authorized-ttl",
 		s.AllowCacheTTL,
 		"The duration to cache 'authorized' responses from the webhook authorizer.")
@@ -155,9 +288,72 @@ func (s *DelegatingAuthorizationOptions) AddFlags(fs *pflag.FlagSet) {
 		"authorization-webhook-cache-unauthorized-ttl", s.DenyCacheTTL,
 		"The duration to cache 'unauthorized' responses from the webhook authorizer.")
 
+	fs.IntVar(&s.AllowCacheMaxEntries, "authorization-webhook-cache-authorized-max-entries", s.AllowCacheMaxEntries,
+		"The maximum number of 'authorized' responses from the webhook authorizer to cache.")
+
+	fs.IntVar(&s.DenyCacheMaxEntries, "authorization-webhook-cache-unauthorized-max-entries", s.DenyCacheMaxEntries,
+		"The maximum number of 'unauthorized' responses from the webhook authorizer to cache. It is separate from the "+
+			"'authorized' responses, so that a shorter --authorization-webhook-cache-unauthorized-ttl does not cost cached allowances.")
+
 	fs.StringSliceVar(&s.AlwaysAllowPaths, "authorization-always-allow-paths", s.AlwaysAllowPaths,
 		"A list of HTTP paths to skip during authorization, i.e. these are authorized without "+
 			"contacting the 'core' kubernetes server.")
+
+	fs.StringArrayVar(&s.AlwaysAllowRules, "authorization-always-allow-rules", s.AlwaysAllowRules, ""+
+		"A rule of HTTP paths to skip during authorization only for some verbs and from some networks, e.g. "+
+		"'paths=/healthz,/metrics;verbs=get;sources=127.0.0.0/8,::1/128' to allow GET requests on /healthz and "+
+		"/metrics from localhost only. The paths field is required, the others are optional and otherwise match "+
+		"anything. The source of a request is the address of its connection. May be repeated.")
+
+	fs.StringArrayVar(&s.WebhookMatchConditions, "authorization-webhook-match-conditions", s.WebhookMatchConditions, ""+
+		"A CEL expression over the SubjectAccessReview of a request, available as the variable request, e.g. "+
+		"has(request.resourceAttributes). Only requests for which all expressions evaluate to true are sent to "+
+		"the 'core' kubernetes server, the webhook authorizer has no opinion on the others. May be repeated.")
+
+	fs.DurationVar(&s.WebhookTimeout, "authorization-webhook-timeout", s.WebhookTimeout, ""+
+		"The maximum time the webhook authorizer takes to decide on a request, including retries, so that a slow "+
+		"'core' kubernetes server does not consume the whole request timeout. Zero means no maximum.")
+
+	fs.StringVar(&s.WebhookTimeoutDecision, "authorization-webhook-timeout-decision", s.WebhookTimeoutDecision, ""+
+		"The decision of the webhook authorizer once --authorization-webhook-timeout expires: 'deny' to forbid the "+
+		"request, or 'no-opinion' to leave the decision to the next authorizer.")
+
+	fs.BoolVar(&s.WebhookPriorityQueueing, "authorization-webhook-priority-queueing", s.WebhookPriorityQueueing, ""+
+		"If true, the reviews exceeding the rate limit of the webhook authorizer are queued by the API Priority and "+
+		"Fairness priority level of their requests, so that e.g. system and leader election requests are authorized "+
+		"ahead of bulk controllers, rather than in order of arrival.")
+
+	fs.StringSliceVar(&s.WebhookPriorityLevels, "authorization-webhook-priority-levels", s.WebhookPriorityLevels, ""+
+		"The priority levels from the highest to the lowest priority for --authorization-webhook-priority-queueing. "+
+		"Requests of other priority levels are authorized last. Defaults to the suggested priority levels: "+
+		strings.Join(webhook.DefaultPriorityLevels, ",")+".")
+
+	fs.StringVar(&s.WebhookCacheWarmAuditLog, "authorization-webhook-cache-warm-audit-log", s.WebhookCacheWarmAuditLog, ""+
+		"An audit log in JSON format, e.g. of a previous run. The distinct requests in it are reviewed in the background "+
+		"at start, so that the responses of the webhook authorizer are cached before the requests come in. The responses "+
+		"are cached for --authorization-webhook-cache-authorized-ttl and --authorization-webhook-cache-unauthorized-ttl.")
+
+	fs.StringVar(&s.DenyListFile, "authorization-deny-list-file", s.DenyListFile, ""+
+		"File with a list of named rules in YAML or JSON, each denying the requests of some users or groups for some "+
+		"verbs on resources or non-resource URLs, e.g. pods/exec or nodes/proxy. Matching requests are denied before "+
+		"any other authorizer is consulted, and the rule is recorded in the audit annotations.")
+
+	fs.StringArrayVar(&s.VerbClassAuthorizers, "authorization-verb-class-authorizers", s.VerbClassAuthorizers, ""+
+		"The authorizers consulted for the requests of a verb class, e.g. 'read=privileged-groups,always-allow-paths' to "+
+		"authorize reads locally while writes consult the webhook. The verb classes are read (get, list and watch), "+
+		"connect (proxy, exec, attach and portforward) and write (all others). The authorizers are "+
+		strings.Join(authorizerNames.List(), ", ")+", and are consulted in the order of the chain. Verb classes "+
+		"that are not given are authorized by all authorizers. The deny list of --authorization-deny-list-file is "+
+		"consulted for every verb class. May be repeated.")
+}
+
+// matchConditions returns WebhookMatchConditions as named match conditions.
+func (s *DelegatingAuthorizationOptions) matchConditions() []webhook.MatchCondition {
+	var conditions []webhook.MatchCondition
+	for i, expression := range s.WebhookMatchConditions {
+		conditions = append(conditions, webhook.MatchCondition{Name: fmt.Sprintf("condition[%d]", i), Expression: expression})
+	}
+	return conditions
 }
 
 func (s *DelegatingAuthorizationOptions) ApplyTo(c *server.AuthorizationInfo) error {
@@ -170,24 +366,52 @@ func (s *DelegatingAuthorizationOptions) ApplyTo(c *server.AuthorizationInfo) er
 	if err != nil {
 		return err
 	}
+	secondaryClient, err := s.getSecondaryClient()
+	if err != nil {
+		return err
+	}
 
-	c.Authorizer, err = s.toAuthorizer(client)
+	c.Authorizer, c.FlushCache, err = s.toAuthorizer(client, secondaryClient)
 	return err
 }
 
-func (s *DelegatingAuthorizationOptions) toAuthorizer(client kubernetes.Interface) (authorizer.Authorizer, error) {
+// toAuthorizer returns the authorizer, and a function flushing the cache of its webhook
+// authorizer, which is nil without a webhook authorizer.
+func (s *DelegatingAuthorizationOptions) toAuthorizer(client, secondaryClient kubernetes.Interface) (authorizer.Authorizer, func(), error) {
+	var flushCache func()
+	var names []string
 	var authorizers []authorizer.Authorizer
+	add := func(name string, a authorizer.Authorizer) {
+		names = append(names, name)
+		authorizers = append(authorizers, union.Named(name, a))
+	}
+
+	if len(s.DenyListFile) > 0 {
+		a, err := authorizerfactory.NewDenyListAuthorizerFromFile(s.DenyListFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		add("deny-list", a)
+	}
 
 	if len(s.AlwaysAllowGroups) > 0 {
-		authorizers = append(authorizers, authorizerfactory.NewPrivilegedGroups(s.AlwaysAllowGroups...))
+		add("privileged-groups", authorizerfactory.NewPrivilegedGroups(s.AlwaysAllowGroups...))
 	}
 
-	if len(s.AlwaysAllowPaths) > 0 {
-		a, err := path.NewAuthorizer(s.AlwaysAllowPaths)
+	if len(s.AlwaysAllowPaths) > 0 || len(s.AlwaysAllowRules) > 0 {
+		rules := []path.Rule{{Paths: s.AlwaysAllowPaths}}
+		for _, r := range s.AlwaysAllowRules {
+			rule, err := path.ParseRule(r)
+			if err != nil {
+				return nil, nil, err
+			}
+			rules = append(rules, rule)
+		}
+		a, err := path.NewScopedAuthorizer(rules)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		authorizers = append(authorizers, a)
+		add("always-allow-paths", a)
 	}
 
 	if client == nil {
@@ -197,16 +421,141 @@ func (s *DelegatingAuthorizationOptions) toAuthorizer(client kubernetes.Interfac
 			SubjectAccessReviewClient: client.AuthorizationV1(),
 			AllowCacheTTL:             s.AllowCacheTTL,
 			DenyCacheTTL:              s.DenyCacheTTL,
+			AllowCacheMaxEntries:      s.AllowCacheMaxEntries,
+			DenyCacheMaxEntries:       s.DenyCacheMaxEntries,
 			WebhookRetryBackoff:       s.WebhookRetryBackoff,
+			MatchConditions:           s.matchConditions(),
+			Failover: webhook.FailoverConfig{
+				FailureThreshold: s.WebhookFailoverThreshold,
+				ProbeInterval:    s.WebhookProbeInterval,
+			},
+			Timeout:           s.WebhookTimeout,
+			DecisionOnTimeout: authorizer.DecisionNoOpinion,
+		}
+		if s.WebhookTimeoutDecision == timeoutDecisionDeny {
+			cfg.DecisionOnTimeout = authorizer.DecisionDeny
+		}
+		if s.WebhookPriorityQueueing {
+			cfg.PriorityQueue = webhook.PriorityQueueConfig{
+				QPS:            webhookQPS,
+				Burst:          webhookBurst,
+				PriorityLevels: s.WebhookPriorityLevels,
+			}
+		}
+		if secondaryClient != nil {
+			cfg.SecondarySubjectAccessReviewClient = secondaryClient.AuthorizationV1()
 		}
 		delegatedAuthorizer, err := cfg.New()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if invalidator, ok := delegatedAuthorizer.(interface{ InvalidateAll() }); ok {
+			flushCache = invalidator.InvalidateAll
+		}
+		if len(s.WebhookCacheWarmAuditLog) > 0 {
+			if err := s.warmCache(delegatedAuthorizer); err != nil {
+				return nil, nil, err
+			}
+		}
+		add("webhook", delegatedAuthorizer)
+	}
+
+	if len(s.VerbClassAuthorizers) == 0 {
+		return union.New(authorizers...), flushCache, nil
+	}
+	selections, err := parseVerbClassAuthorizers(s.VerbClassAuthorizers)
+	if err != nil {
+		return nil, nil, err
+	}
+	configured := sets.NewString(names...)
+	classAuthorizers := map[authorizerfactory.VerbClass]authorizer.Authorizer{}
+	for _, class := range authorizerfactory.VerbClasses {
+		selection, ok := selections[class]
+		if !ok {
+			classAuthorizers[class] = union.New(authorizers...)
+			continue
+		}
+		// a selection must not bypass the deny list, which guards the connect requests in particular
+		if len(s.DenyListFile) > 0 {
+			selection = selection.Union(sets.NewString("deny-list"))
+		}
+		if missing := selection.Difference(configured); missing.Len() > 0 {
+			return nil, nil, fmt.Errorf("authorizers %q of verb class %q are not configured", missing.List(), class)
+		}
+		var selected []authorizer.Authorizer
+		for i, name := range names {
+			if selection.Has(name) {
+				selected = append(selected, authorizers[i])
+			}
+		}
+		classAuthorizers[class] = union.New(selected...)
+	}
+	return authorizerfactory.NewVerbClassAuthorizer(classAuthorizers), flushCache, nil
+}
+
+// parseVerbClassAuthorizers returns the names of the authorizers selected for each verb class by
+// selections, as given in VerbClassAuthorizers.
+func parseVerbClassAuthorizers(selections []string) (map[authorizerfactory.VerbClass]sets.String, error) {
+	classes := sets.NewString()
+	for _, class := range authorizerfactory.VerbClasses {
+		classes.Insert(string(class))
+	}
+
+	ret := map[authorizerfactory.VerbClass]sets.String{}
+	for _, selection := range selections {
+		class, list, ok := strings.Cut(selection, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not of the form class=name,...", selection)
+		}
+		if !classes.Has(class) {
+			return nil, fmt.Errorf("unknown verb class %q, must be one of %q", class, classes.List())
+		}
+		if _, ok := ret[authorizerfactory.VerbClass(class)]; ok {
+			return nil, fmt.Errorf("verb class %q is given more than once", class)
+		}
+		names := sets.NewString()
+		for _, name := range strings.Split(list, ",") {
+			if name = strings.TrimSpace(name); len(name) > 0 {
+				names.Insert(name)
+			}
+		}
+		if names.Len() == 0 {
+			return nil, fmt.Errorf("no authorizers given for verb class %q", class)
 		}
-		authorizers = append(authorizers, delegatedAuthorizer)
+		if unknown := names.Difference(authorizerNames); unknown.Len() > 0 {
+			return nil, fmt.Errorf("unknown authorizers %q for verb class %q, must be of %q", unknown.List(), class, authorizerNames.List())
+		}
+		ret[authorizerfactory.VerbClass(class)] = names
+	}
+	return ret, nil
+}
+
+// warmCache reviews the requests of WebhookCacheWarmAuditLog with the webhook authorizer a in the background.
+func (s *DelegatingAuthorizationOptions) warmCache(a authorizer.Authorizer) error {
+	warmer, ok := a.(interface {
+		WarmCache(context.Context, []authorizer.Attributes, int) int
+	})
+	if !ok {
+		return nil
+	}
+
+	f, err := os.Open(s.WebhookCacheWarmAuditLog)
+	if err != nil {
+		return fmt.Errorf("failed to open the audit log to warm the authorization cache with: %v", err)
+	}
+	defer f.Close()
+	requests, err := webhook.ReadAuditLogRequests(f)
+	if err != nil {
+		return fmt.Errorf("failed to read the audit log to warm the authorization cache with: %v", err)
 	}
 
-	return union.New(authorizers...), nil
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cacheWarmTimeout)
+		defer cancel()
+		warmed := warmer.WarmCache(ctx, requests, cacheWarmWorkers)
+		klog.InfoS("Warmed the authorization webhook cache", "requests", len(requests), "warmed", warmed)
+	}()
+	return nil
 }
 
 func (s *DelegatingAuthorizationOptions) getClient() (kubernetes.Interface, error) {
@@ -232,9 +581,30 @@ func (s *DelegatingAuthorizationOptions) getClient() (kubernetes.Interface, erro
 		return nil, fmt.Errorf("failed to get delegated authorization kubeconfig: %v", err)
 	}
 
-	// set high qps/burst limits since this will effectively limit API server responsiveness
-	clientConfig.QPS = 200
-	clientConfig.Burst = 400
+	return s.newClient(clientConfig)
+}
+
+// getSecondaryClient returns the client of SecondaryRemoteKubeConfigFile, or nil if it is not set.
+func (s *DelegatingAuthorizationOptions) getSecondaryClient() (kubernetes.Interface, error) {
+	if len(s.SecondaryRemoteKubeConfigFile) == 0 {
+		return nil, nil
+	}
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: s.SecondaryRemoteKubeConfigFile}
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	clientConfig, err := loader.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secondary delegated authorization kubeconfig: %v", err)
+	}
+	return s.newClient(clientConfig)
+}
+
+func (s *DelegatingAuthorizationOptions) newClient(clientConfig *rest.Config) (kubernetes.Interface, error) {
+	clientConfig.QPS = webhookQPS
+	clientConfig.Burst = webhookBurst
+	if s.WebhookPriorityQueueing {
+		// the webhook authorizer rate limits the reviews by priority instead
+		clientConfig.QPS = -1
+	}
 	clientConfig.Timeout = s.ClientTimeout
 	if s.CustomRoundTripperFn != nil {
 		clientConfig.Wrap(s.CustomRoundTripperFn)
@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+func TestVerbClassAuthorizersKeepDenyList(t *testing.T) {
+	denyList := filepath.Join(t.TempDir(), "deny-list.yaml")
+	if err := ioutil.WriteFile(denyList, []byte(`
+rules:
+- name: no-exec
+  groups: ["developers"]
+  verbs: ["create"]
+  resources: ["pods/exec"]
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewDelegatingAuthorizationOptions()
+	s.AlwaysAllowGroups = []string{"developers"}
+	s.DenyListFile = denyList
+	s.VerbClassAuthorizers = []string{"connect=privileged-groups", "write=privileged-groups"}
+	a, _, err := s.toAuthorizer(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	developer := &user.DefaultInfo{Name: "dave", Groups: []string{"developers"}}
+	exec := authorizer.AttributesRecord{User: developer, Verb: "create", Resource: "pods", Subresource: "exec", Namespace: "default", Name: "web", ResourceRequest: true}
+	if decision, _, _ := a.Authorize(context.Background(), exec); decision != authorizer.DecisionDeny {
+		t.Errorf("expected the deny list to deny exec, got %v", decision)
+	}
+	create := authorizer.AttributesRecord{User: developer, Verb: "create", Resource: "pods", Namespace: "default", Name: "web", ResourceRequest: true}
+	if decision, _, _ := a.Authorize(context.Background(), create); decision != authorizer.DecisionAllow {
+		t.Errorf("expected the privileged group to be allowed, got %v", decision)
+	}
+}
@@ -23,11 +23,14 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/audit/policy"
 	"k8s.io/apiserver/pkg/features"
 	"k8s.io/apiserver/pkg/server"
 	"k8s.io/apiserver/pkg/storage/storagebackend"
 	"k8s.io/apiserver/pkg/util/feature"
 	utilflowcontrol "k8s.io/apiserver/pkg/util/flowcontrol"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/component-base/featuregate"
 	"k8s.io/klog/v2"
@@ -113,6 +116,9 @@ func (o *RecommendedOptions) ApplyTo(config *server.RecommendedConfig) error {
 	if err := o.SecureServing.ApplyTo(&config.Config.SecureServing, &config.Config.LoopbackClientConfig); err != nil {
 		return err
 	}
+	if o.Authentication != nil && feature.DefaultFeatureGate.Enabled(features.APIServerTracing) {
+		o.Authentication.WithTracerProvider(config.TracerProvider)
+	}
 	if err := o.Authentication.ApplyTo(&config.Config.Authentication, config.SecureServing, config.OpenAPIConfig); err != nil {
 		return err
 	}
@@ -128,6 +134,9 @@ func (o *RecommendedOptions) ApplyTo(config *server.RecommendedConfig) error {
 	if err := o.CoreAPI.ApplyTo(config); err != nil {
 		return err
 	}
+	if err := setAuditNamespaceLister(config.AuditPolicyRuleEvaluator, o.Audit != nil && o.Audit.PolicyFileReload, config.SharedInformerFactory); err != nil {
+		return err
+	}
 	if initializers, err := o.ExtraAdmissionInitializers(config); err != nil {
 		return err
 	} else if err := o.Admission.ApplyTo(&config.Config, config.SharedInformerFactory, config.ClientConfig, o.FeatureGate, initializers...); err != nil {
@@ -167,3 +176,22 @@ func (o *RecommendedOptions) Validate() []error {
 
 	return errors
 }
+
+// setAuditNamespaceLister provides the audit policy rule evaluator with a namespace
+// lister if its policy has rules with namespace selectors or, if reload is enabled,
+// policies loaded later may have such rules.
+func setAuditNamespaceLister(evaluator audit.PolicyRuleEvaluator, reload bool, informerFactory informers.SharedInformerFactory) error {
+	w, ok := evaluator.(policy.WantsNamespaceLister)
+	if !ok || !(reload || w.NeedsNamespaceLister()) {
+		return nil
+	}
+	if informerFactory == nil {
+		if w.NeedsNamespaceLister() {
+			return fmt.Errorf("audit policy rules with namespace selectors require a kubeconfig or in-cluster configuration")
+		}
+		klog.Warning("Audit policy rules with namespace selectors loaded on reload will never match without a kubeconfig or in-cluster configuration")
+		return nil
+	}
+	w.SetNamespaceLister(informerFactory.Core().V1().Namespaces().Lister())
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit/policy"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const namespaceSelectorPolicy = `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+  - level: Metadata
+    namespaceSelector:
+      matchLabels:
+        audit: "true"
+`
+
+const plainPolicy = `
+apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+  - level: None
+`
+
+func TestSetAuditNamespaceListerOnReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(plainPolicy), 0644))
+	evaluator, err := policy.NewDynamicPolicyRuleEvaluator(path)
+	require.NoError(t, err)
+
+	client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "audited", Labels: map[string]string{"audit": "true"}}})
+	factory := informers.NewSharedInformerFactory(client, 0)
+	require.NoError(t, setAuditNamespaceLister(evaluator, true, factory))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	// a policy with namespace selectors arriving on reload must match.
+	require.NoError(t, os.WriteFile(path, []byte(namespaceSelectorPolicy), 0644))
+	require.NoError(t, evaluator.RunOnce(ctx))
+	attrs := &authorizer.AttributesRecord{Verb: "get", Namespace: "audited", Resource: "pods", ResourceRequest: true}
+	assert.Equal(t, auditinternal.LevelMetadata, evaluator.EvaluatePolicyRule(attrs).Level)
+}
+
+func TestSetAuditNamespaceListerWithoutInformers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(plainPolicy), 0644))
+	plain, err := policy.NewDynamicPolicyRuleEvaluator(path)
+	require.NoError(t, err)
+	assert.NoError(t, setAuditNamespaceLister(plain, true, nil), "reload must not require informers")
+
+	require.NoError(t, os.WriteFile(path, []byte(namespaceSelectorPolicy), 0644))
+	selecting, err := policy.NewDynamicPolicyRuleEvaluator(path)
+	require.NoError(t, err)
+	assert.Error(t, setAuditNamespaceLister(selecting, true, nil), "namespace selectors must require informers")
+}
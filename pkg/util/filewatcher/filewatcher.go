@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filewatcher implements a controller that reloads a file whenever it changes.
+package filewatcher // import "k8s.io/apiserver/pkg/util/filewatcher"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const workItemKey = "key"
+
+// FileWatcher calls a load function whenever the watched file changes, and
+// periodically in case a file event was missed. Failed loads are retried with
+// backoff.
+type FileWatcher struct {
+	// name identifies the watcher in logs and in the metrics of its queue.
+	name string
+
+	// filename is the name of the file to watch.
+	filename string
+
+	// load reads the file and applies its content.
+	load func() error
+
+	// queue only ever has one item, but it has nice error handling backoff/retry semantics
+	queue workqueue.RateLimitingInterface
+}
+
+// New creates a FileWatcher that calls load whenever the file at the given path
+// changes. Run must be called for the file to be watched.
+func New(name, path string, load func() error) *FileWatcher {
+	return &FileWatcher{
+		name:     name,
+		filename: path,
+		load:     load,
+		queue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), name),
+	}
+}
+
+// Run watches the file and blocks until ctx is done. The file is loaded again at
+// least every refresh interval.
+func (w *FileWatcher) Run(ctx context.Context, refresh time.Duration) {
+	defer utilruntime.HandleCrash()
+	defer w.queue.ShutDown()
+
+	klog.InfoS("Starting file watcher", "name", w.name, "file", w.filename)
+	defer klog.InfoS("Shutting down file watcher", "name", w.name, "file", w.filename)
+
+	// doesn't matter what workers say, only start one.
+	go wait.Until(w.runWorker, time.Second, ctx.Done())
+
+	// start the loop that watches the file until ctx is done.
+	go wait.Until(func() {
+		if err := w.watchFile(ctx.Done()); err != nil {
+			klog.ErrorS(err, "Failed to watch file, will retry later", "name", w.name)
+		}
+	}, time.Minute, ctx.Done())
+
+	// periodically re-read the file in case a file event was missed.
+	go wait.Until(func() { w.queue.Add(workItemKey) }, refresh, ctx.Done())
+
+	<-ctx.Done()
+}
+
+func (w *FileWatcher) watchFile(stopCh <-chan struct{}) error {
+	// Trigger a check here to ensure the content will be checked periodically even if the following watch fails.
+	w.queue.Add(workItemKey)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating fsnotify watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	if err = watcher.Add(w.filename); err != nil {
+		return fmt.Errorf("error adding watch for file %s: %v", w.filename, err)
+	}
+	// Trigger a check in case the file is updated before the watch starts.
+	w.queue.Add(workItemKey)
+
+	for {
+		select {
+		case ev := <-watcher.Events:
+			if err := w.handleWatchEvent(ev, watcher); err != nil {
+				return err
+			}
+		case err := <-watcher.Errors:
+			return fmt.Errorf("received fsnotify error: %v", err)
+		case <-stopCh:
+			return nil
+		}
+	}
+}
+
+// handleWatchEvent triggers reloading the file, and restarts a new watch if it's a Remove or Rename event.
+func (w *FileWatcher) handleWatchEvent(ev fsnotify.Event, watcher *fsnotify.Watcher) error {
+	// This should be executed after restarting the watch (if applicable) to ensure no file event will be missing.
+	defer w.queue.Add(workItemKey)
+	if ev.Op&(fsnotify.Remove|fsnotify.Rename) == 0 {
+		return nil
+	}
+	if err := watcher.Remove(w.filename); err != nil {
+		klog.InfoS("Failed to remove file watch, it may have been deleted", "file", w.filename, "err", err)
+	}
+	if err := watcher.Add(w.filename); err != nil {
+		return fmt.Errorf("error adding watch for file %s: %v", w.filename, err)
+	}
+	return nil
+}
+
+func (w *FileWatcher) runWorker() {
+	for w.processNextWorkItem() {
+	}
+}
+
+func (w *FileWatcher) processNextWorkItem() bool {
+	key, quit := w.queue.Get()
+	if quit {
+		return false
+	}
+	defer w.queue.Done(key)
+
+	err := w.load()
+	if err == nil {
+		w.queue.Forget(key)
+		return true
+	}
+
+	utilruntime.HandleError(fmt.Errorf("reloading file %s of %s failed: %v", w.filename, w.name, err))
+	w.queue.AddRateLimited(key)
+
+	return true
+}
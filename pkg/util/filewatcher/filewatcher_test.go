@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filewatcher
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestFileWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, ioutil.WriteFile(path, []byte("first"), 0644))
+
+	var loaded atomic.Value
+	load := func() error {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if string(content) == "invalid" {
+			return fmt.Errorf("invalid content")
+		}
+		loaded.Store(string(content))
+		return nil
+	}
+	w := New("test", path, load)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the refresh interval is long, so that changes are only picked up through file events.
+	go w.Run(ctx, time.Hour)
+
+	waitForContent := func(expected string) {
+		t.Helper()
+		require.Eventually(t, func() bool { return loaded.Load() == expected }, wait.ForeverTestTimeout, 10*time.Millisecond)
+	}
+	waitForContent("first")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("second"), 0644))
+	waitForContent("second")
+
+	// files replaced by a rename, e.g. by atomic writers, are still watched.
+	replacement := path + ".tmp"
+	require.NoError(t, ioutil.WriteFile(replacement, []byte("third"), 0644))
+	require.NoError(t, os.Rename(replacement, path))
+	waitForContent("third")
+
+	// failed loads keep the previous content and are retried.
+	require.NoError(t, ioutil.WriteFile(path, []byte("invalid"), 0644))
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, "third", loaded.Load())
+	require.NoError(t, ioutil.WriteFile(path, []byte("fourth"), 0644))
+	waitForContent("fourth")
+}
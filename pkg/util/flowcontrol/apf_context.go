@@ -28,6 +28,10 @@ const (
 	// initialization signal function for watch requests is stored
 	// in the context.
 	priorityAndFairnessInitializationSignalKey priorityAndFairnessKeyType = iota
+
+	// priorityAndFairnessPriorityLevelKey is a key under which the name
+	// of the priority level a request is executed at is stored in the context.
+	priorityAndFairnessPriorityLevelKey
 )
 
 // WithInitializationSignal creates a copy of parent context with
@@ -44,6 +48,20 @@ func initializationSignalFrom(ctx context.Context) (InitializationSignal, bool)
 	return signal, ok && signal != nil
 }
 
+// WithPriorityLevel creates a copy of parent context with the name
+// of the priority level the request is executed at.
+func WithPriorityLevel(ctx context.Context, priorityLevel string) context.Context {
+	return context.WithValue(ctx, priorityAndFairnessPriorityLevelKey, priorityLevel)
+}
+
+// PriorityLevelFrom returns the name of the priority level the request
+// of the context is executed at. It returns false if the request was
+// not classified by priority and fairness, e.g. when it is disabled.
+func PriorityLevelFrom(ctx context.Context) (string, bool) {
+	priorityLevel, ok := ctx.Value(priorityAndFairnessPriorityLevelKey).(string)
+	return priorityLevel, ok
+}
+
 // WatchInitialized sends a signal to priority and fairness dispatcher
 // that a given watch request has already been initialized.
 func WatchInitialized(ctx context.Context) {
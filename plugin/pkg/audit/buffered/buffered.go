@@ -17,6 +17,7 @@ limitations under the License.
 package buffered
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -31,6 +32,34 @@ import (
 // PluginName is the name reported in error metrics.
 const PluginName = "buffered"
 
+// bufferNearlyFullPercent is the percentage of the buffer filled from which
+// the backend reports itself unhealthy.
+const bufferNearlyFullPercent = 90
+
+// OverflowPolicy defines what happens to events that find the buffer full.
+type OverflowPolicy string
+
+const (
+	// OverflowPolicyDrop drops events that find the buffer full.
+	OverflowPolicyDrop OverflowPolicy = "drop"
+	// OverflowPolicyBlock blocks until events are buffered, for at most
+	// MaxBlockDuration, and drops them then.
+	OverflowPolicyBlock OverflowPolicy = "block"
+	// OverflowPolicySpillToDisk appends events to a write-ahead log, from which
+	// they are read into the buffer. Events exceeding the buffer are kept on
+	// disk, and events not sent to the delegate backend when the backend stops
+	// are sent once it is run again. The oldest events are dropped once the log
+	// exceeds WALMaxSize.
+	OverflowPolicySpillToDisk OverflowPolicy = "spill-to-disk"
+)
+
+// AllowedOverflowPolicies are the overflow policies known by the buffered backend.
+var AllowedOverflowPolicies = []string{
+	string(OverflowPolicyDrop),
+	string(OverflowPolicyBlock),
+	string(OverflowPolicySpillToDisk),
+}
+
 // BatchConfig represents batching delegate audit backend configuration.
 type BatchConfig struct {
 	// BufferSize defines a size of the buffering queue.
@@ -50,6 +79,17 @@ type BatchConfig struct {
 
 	// Whether the delegate backend should be called asynchronously.
 	AsyncDelegate bool
+
+	// OverflowPolicy defines what happens to events that find the buffer full.
+	// Defaults to OverflowPolicyDrop.
+	OverflowPolicy OverflowPolicy
+	// MaxBlockDuration is the maximum time OverflowPolicyBlock blocks a call for
+	// its events to be buffered. Zero blocks until they are buffered.
+	MaxBlockDuration time.Duration
+	// WALDir is the directory of the write-ahead log of OverflowPolicySpillToDisk.
+	WALDir string
+	// WALMaxSize is the maximum size of the write-ahead log in bytes.
+	WALMaxSize int64
 }
 
 type bufferedBackend struct {
@@ -80,9 +120,20 @@ type bufferedBackend struct {
 
 	// Limits the number of batches sent to the delegate backend per second.
 	throttle flowcontrol.RateLimiter
+
+	// What happens to events that find the buffer full, and how long blocking lasts.
+	overflowPolicy   OverflowPolicy
+	maxBlockDuration time.Duration
+
+	// Write-ahead log events are appended to and read into the buffer from, if
+	// enabled, or the error opening it. walReaderDone is closed once reading stopped.
+	wal           *wal
+	walErr        error
+	walReaderDone chan struct{}
 }
 
 var _ audit.Backend = &bufferedBackend{}
+var _ audit.HealthChecker = &bufferedBackend{}
 
 // NewBackend returns a buffered audit backend that wraps delegate backend.
 // Buffered backend automatically runs and shuts down the delegate backend.
@@ -91,19 +142,37 @@ func NewBackend(delegate audit.Backend, config BatchConfig) audit.Backend {
 	if config.ThrottleEnable {
 		throttle = flowcontrol.NewTokenBucketRateLimiter(config.ThrottleQPS, config.ThrottleBurst)
 	}
-	return &bufferedBackend{
-		delegateBackend: delegate,
-		buffer:          make(chan *auditinternal.Event, config.BufferSize),
-		maxBatchSize:    config.MaxBatchSize,
-		maxBatchWait:    config.MaxBatchWait,
-		asyncDelegate:   config.AsyncDelegate,
-		shutdownCh:      make(chan struct{}),
-		wg:              sync.WaitGroup{},
-		throttle:        throttle,
+	b := &bufferedBackend{
+		delegateBackend:  delegate,
+		buffer:           make(chan *auditinternal.Event, config.BufferSize),
+		maxBatchSize:     config.MaxBatchSize,
+		maxBatchWait:     config.MaxBatchWait,
+		asyncDelegate:    config.AsyncDelegate,
+		shutdownCh:       make(chan struct{}),
+		wg:               sync.WaitGroup{},
+		throttle:         throttle,
+		overflowPolicy:   config.OverflowPolicy,
+		maxBlockDuration: config.MaxBlockDuration,
 	}
+	if config.OverflowPolicy == OverflowPolicySpillToDisk {
+		// Errors are returned by Run, as the backend is run before serving.
+		b.wal, b.walErr = openWAL(config.WALDir, config.WALMaxSize)
+		b.walReaderDone = make(chan struct{})
+	}
+	return b
 }
 
 func (b *bufferedBackend) Run(stopCh <-chan struct{}) error {
+	if b.walErr != nil {
+		close(b.shutdownCh)
+		return fmt.Errorf("failed to open audit write-ahead log: %v", b.walErr)
+	}
+	if b.wal != nil {
+		go func() {
+			defer close(b.walReaderDone)
+			b.readWAL(stopCh)
+		}()
+	}
 	go func() {
 		// Signal that the working routine has exited.
 		defer close(b.shutdownCh)
@@ -149,10 +218,55 @@ func (b *bufferedBackend) Shutdown() {
 	b.delegateBackend.Shutdown()
 }
 
+// ShutdownWithContext shuts down the backend like Shutdown, but stops waiting
+// for the events to be sent to the delegate backend once the context is done.
+// Events still buffered then are reported as dropped.
+func (b *bufferedBackend) ShutdownWithContext(ctx context.Context) error {
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		<-b.shutdownCh
+		b.wg.Wait()
+	}()
+	select {
+	case <-sent:
+	case <-ctx.Done():
+		pending := len(b.buffer)
+		if pending > 0 {
+			audit.ObserveDroppedEvents(b.delegateBackend.String(), audit.DropReasonShutdown, pending)
+		}
+		return fmt.Errorf("audit backend %s shut down with %d events not sent: %w", b, pending, ctx.Err())
+	}
+	return audit.ShutdownWithContext(ctx, b.delegateBackend)
+}
+
+// readWAL reads the events of the write-ahead log into the buffer until stopCh
+// is closed. Events not read by then are kept in the log.
+func (b *bufferedBackend) readWAL(stopCh <-chan struct{}) {
+	for {
+		ev, ok := b.wal.next()
+		if !ok {
+			return
+		}
+		select {
+		case b.buffer <- ev:
+		case <-stopCh:
+			return
+		}
+	}
+}
+
 // processIncomingEvents runs a loop that collects events from the buffer. When
 // b.stopCh is closed, processIncomingEvents stops and closes the buffer.
 func (b *bufferedBackend) processIncomingEvents(stopCh <-chan struct{}) {
-	defer close(b.buffer)
+	defer func() {
+		if b.wal != nil {
+			// Stop reading the write-ahead log before closing the buffer it is read into.
+			b.wal.close()
+			<-b.walReaderDone
+		}
+		close(b.buffer)
+	}()
 
 	var (
 		maxWaitChan  <-chan time.Time
@@ -220,6 +334,7 @@ L:
 
 // processEvents process the batch events in a goroutine using delegateBackend's ProcessEvents.
 func (b *bufferedBackend) processEvents(events []*auditinternal.Event) {
+	observeBufferSize(b.delegateBackend.String(), len(b.buffer))
 	if len(events) == 0 {
 		return
 	}
@@ -238,7 +353,7 @@ func (b *bufferedBackend) processEvents(events []*auditinternal.Event) {
 
 			// Execute the real processing in a goroutine to keep it from blocking.
 			// This lets the batching routine continue draining the queue immediately.
-			b.delegateBackend.ProcessEvents(events...)
+			b.sendBatch(events)
 		}()
 	} else {
 		func() {
@@ -246,12 +361,39 @@ func (b *bufferedBackend) processEvents(events []*auditinternal.Event) {
 
 			// Execute the real processing in a goroutine to keep it from blocking.
 			// This lets the batching routine continue draining the queue immediately.
-			b.delegateBackend.ProcessEvents(events...)
+			b.sendBatch(events)
 		}()
 	}
 }
 
+// sendBatch sends the batch to the delegate backend.
+func (b *bufferedBackend) sendBatch(events []*auditinternal.Event) {
+	start := time.Now()
+	success := b.delegateBackend.ProcessEvents(events...)
+	observeBatch(b.delegateBackend.String(), time.Since(start))
+	b.ackWAL(events, success)
+}
+
+// ackWAL removes the events processed by the delegate backend from the
+// write-ahead log, if enabled. Events the delegate backend failed to process
+// are kept in the log, to be replayed once the backend is restarted.
+func (b *bufferedBackend) ackWAL(events []*auditinternal.Event, success bool) {
+	if b.wal == nil {
+		return
+	}
+	if success {
+		b.wal.ack(events)
+	} else {
+		b.wal.keep(events)
+	}
+}
+
 func (b *bufferedBackend) ProcessEvents(ev ...*auditinternal.Event) bool {
+	if b.wal != nil {
+		b.spillEvents(ev)
+		return true
+	}
+
 	// The following mechanism is in place to support the situation when audit
 	// events are still coming after the backend was stopped.
 	var sendErr error
@@ -267,8 +409,11 @@ func (b *bufferedBackend) ProcessEvents(ev ...*auditinternal.Event) bool {
 		if sendErr != nil {
 			audit.HandlePluginError(PluginName, sendErr, ev[evIndex:]...)
 		}
+		observeBufferSize(b.delegateBackend.String(), len(b.buffer))
 	}()
 
+	// Channel that fires once blocking for the events exceeded maxBlockDuration.
+	var blockTimeout <-chan time.Time
 	for i, e := range ev {
 		evIndex = i
 		// Per the audit.Backend interface these events are reused after being
@@ -277,14 +422,62 @@ func (b *bufferedBackend) ProcessEvents(ev ...*auditinternal.Event) bool {
 
 		select {
 		case b.buffer <- event:
+			continue
 		default:
-			sendErr = fmt.Errorf("audit buffer queue blocked")
-			return true
 		}
+		if b.overflowPolicy == OverflowPolicyBlock {
+			if blockTimeout == nil && b.maxBlockDuration > 0 {
+				timer := time.NewTimer(b.maxBlockDuration)
+				defer timer.Stop()
+				blockTimeout = timer.C
+			}
+			select {
+			case b.buffer <- event:
+				observeOverflow(b.delegateBackend.String(), overflowBlocked)
+				continue
+			case <-blockTimeout:
+			}
+		}
+		for range ev[i:] {
+			observeOverflow(b.delegateBackend.String(), overflowDropped)
+		}
+		audit.ObserveDroppedEvents(b.delegateBackend.String(), audit.DropReasonBufferFull, len(ev[i:]))
+		sendErr = fmt.Errorf("audit buffer queue blocked")
+		return true
 	}
 	return true
 }
 
+// spillEvents appends the events to the write-ahead log, from which they are
+// read into the buffer.
+func (b *bufferedBackend) spillEvents(events []*auditinternal.Event) {
+	for _, ev := range events {
+		full := len(b.buffer) == cap(b.buffer)
+		dropped, err := b.wal.append(ev)
+		for i := 0; i < dropped; i++ {
+			observeOverflow(b.delegateBackend.String(), overflowDropped)
+		}
+		if dropped > 0 {
+			audit.ObserveDroppedEvents(b.delegateBackend.String(), audit.DropReasonBufferFull, dropped)
+			audit.HandlePluginError(PluginName, fmt.Errorf("audit write-ahead log full, dropped %d oldest events", dropped))
+		}
+		if err != nil {
+			audit.HandlePluginError(PluginName, err, ev)
+		} else if full {
+			observeOverflow(b.delegateBackend.String(), overflowSpilled)
+		}
+	}
+}
+
+// HealthCheck reports the buffer being nearly full, or the delegate backend
+// being unhealthy.
+func (b *bufferedBackend) HealthCheck() error {
+	if size, capacity := len(b.buffer), cap(b.buffer); size >= capacity*bufferNearlyFullPercent/100 {
+		return fmt.Errorf("buffer nearly full, %d of %d events buffered", size, capacity)
+	}
+	return audit.CheckHealth(b.delegateBackend)
+}
+
 func (b *bufferedBackend) String() string {
 	return fmt.Sprintf("%s<%s>", PluginName, b.delegateBackend)
 }
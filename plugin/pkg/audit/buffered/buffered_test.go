@@ -17,7 +17,9 @@ limitations under the License.
 package buffered
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -28,6 +30,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/apiserver/plugin/pkg/audit/fake"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
 )
 
 var (
@@ -130,6 +134,32 @@ func TestUnbatchedBackendCollectEvents(t *testing.T) {
 	assert.Empty(t, batch, "Empty final batch")
 }
 
+func TestBufferedBackendShutdownWithContext(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	delegate := &fake.Backend{
+		OnRequest: func(_ []*auditinternal.Event) {
+			<-release
+		},
+	}
+	config := testBatchConfig()
+	config.MaxBatchSize = 1
+	backend := NewBackend(delegate, config)
+
+	stopCh := make(chan struct{})
+	require.NoError(t, backend.Run(stopCh))
+	backend.ProcessEvents(newEvents(3)...)
+	close(stopCh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, backend.(*bufferedBackend).ShutdownWithContext(ctx), "Expected shutdown to stop waiting for the blocked delegate")
+
+	close(release)
+	assert.NoError(t, backend.(*bufferedBackend).ShutdownWithContext(context.Background()), "Expected shutdown once the delegate is unblocked")
+}
+
 func TestBufferedBackendProcessEventsAfterStop(t *testing.T) {
 	t.Parallel()
 
@@ -157,6 +187,89 @@ func TestBufferedBackendProcessEventsBufferFull(t *testing.T) {
 	require.Len(t, backend.buffer, 1, "buffed contains more elements than it should")
 }
 
+func TestBufferedBackendHealthCheck(t *testing.T) {
+	var delegateErr error
+	config := testBatchConfig()
+	config.BufferSize = 10
+	backend := NewBackend(&fake.Backend{
+		OnHealthCheck: func() error { return delegateErr },
+	}, config).(*bufferedBackend)
+
+	backend.ProcessEvents(newEvents(8)...)
+	assert.NoError(t, backend.HealthCheck())
+	delegateErr = fmt.Errorf("unavailable")
+	assert.Equal(t, delegateErr, backend.HealthCheck(), "unhealthy delegate backend must be reported")
+	delegateErr = nil
+	backend.ProcessEvents(newEvents(1)...)
+	assert.Error(t, backend.HealthCheck(), "nearly full buffer must be reported")
+}
+
+func TestBufferedBackendOverflowPolicy(t *testing.T) {
+	overflowCounter.Reset()
+
+	config := testBatchConfig()
+	config.BufferSize = 1
+
+	t.Log("Drop policy drops events that find the buffer full.")
+	config.OverflowPolicy = OverflowPolicyDrop
+	backend := NewBackend(&fake.Backend{}, config).(*bufferedBackend)
+	backend.ProcessEvents(newEvents(3)...)
+	require.Len(t, backend.buffer, 1)
+
+	t.Log("Block policy drops events once the max block duration passed.")
+	config.OverflowPolicy = OverflowPolicyBlock
+	config.MaxBlockDuration = 10 * time.Millisecond
+	backend = NewBackend(&fake.Backend{}, config).(*bufferedBackend)
+	backend.ProcessEvents(newEvents(2)...)
+	require.Len(t, backend.buffer, 1)
+
+	t.Log("Block policy buffers events once the buffer has room.")
+	config.MaxBlockDuration = 0
+	backend = NewBackend(&fake.Backend{}, config).(*bufferedBackend)
+	backend.ProcessEvents(newEvents(1)...)
+	processedCh := make(chan struct{})
+	go func() {
+		backend.ProcessEvents(newEvents(1)...)
+		close(processedCh)
+	}()
+	// Wait for some time for the call to block. Can give false negative, but never false positive.
+	time.Sleep(100 * time.Millisecond)
+	<-backend.buffer
+	<-processedCh
+	require.Len(t, backend.buffer, 1)
+
+	expected := strings.NewReader(`
+		# HELP apiserver_audit_buffer_overflow_total [ALPHA] Counter of audit events that found the buffer of a backend full. Outcome is 'dropped' if the event was dropped, 'blocked' if it was buffered after blocking, or 'spilled' if it was kept on disk.
+		# TYPE apiserver_audit_buffer_overflow_total counter
+		apiserver_audit_buffer_overflow_total{outcome="blocked",plugin=""} 1
+		apiserver_audit_buffer_overflow_total{outcome="dropped",plugin=""} 3
+`)
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, expected, "apiserver_audit_buffer_overflow_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBufferedBackendMetrics(t *testing.T) {
+	bufferSizeGauge.Reset()
+	batchDuration.Reset()
+
+	config := testBatchConfig()
+	config.AsyncDelegate = false
+	backend := NewBackend(&fake.Backend{}, config).(*bufferedBackend)
+	backend.ProcessEvents(newEvents(config.MaxBatchSize)...)
+	size, err := testutil.GetGaugeMetricValue(bufferSizeGauge.WithLabelValues(""))
+	require.NoError(t, err)
+	assert.Equal(t, float64(config.MaxBatchSize), size)
+
+	backend.processEvents(backend.collectEvents(infiniteTimeCh, wait.NeverStop))
+	size, err = testutil.GetGaugeMetricValue(bufferSizeGauge.WithLabelValues(""))
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), size)
+	batches, err := testutil.GetHistogramMetricCount(batchDuration.WithLabelValues(""))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), batches)
+}
+
 func TestBufferedBackendShutdownWaitsForDelegatedCalls(t *testing.T) {
 	t.Parallel()
 
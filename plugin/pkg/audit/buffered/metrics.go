@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buffered
+
+import (
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	subsystem = "apiserver_audit"
+
+	// Outcomes of events that found the buffer full.
+	overflowDropped = "dropped"
+	overflowBlocked = "blocked"
+	overflowSpilled = "spilled"
+)
+
+/*
+ * By default, all the following metrics are defined as falling under
+ * ALPHA stability level https://github.com/kubernetes/enhancements/blob/master/keps/sig-instrumentation/1209-metrics-stability/kubernetes-control-plane-metrics-stability.md#stability-classes)
+ *
+ * Promoting the stability level of the metric is a responsibility of the component owner, since it
+ * involves explicitly acknowledging support for the metric across multiple releases, in accordance with
+ * the metric stability policy.
+ */
+var (
+	overflowCounter = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "buffer_overflow_total",
+			Help: "Counter of audit events that found the buffer of a backend full. " +
+				"Outcome is 'dropped' if the event was dropped, 'blocked' if it was buffered " +
+				"after blocking, or 'spilled' if it was kept on disk.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin", "outcome"},
+	)
+	bufferSizeGauge = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      subsystem,
+			Name:           "buffer_events",
+			Help:           "Number of audit events in the buffer of a backend.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin"},
+	)
+	batchDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      subsystem,
+			Name:           "batch_duration_seconds",
+			Help:           "Duration in seconds of a backend processing a batch of buffered audit events.",
+			Buckets:        metrics.ExponentialBuckets(0.005, 2, 14),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(overflowCounter)
+	legacyregistry.MustRegister(bufferSizeGauge)
+	legacyregistry.MustRegister(batchDuration)
+}
+
+// observeOverflow updates the relevant prometheus metrics for an event that
+// found the buffer of the delegate backend full.
+func observeOverflow(plugin, outcome string) {
+	overflowCounter.WithLabelValues(plugin, outcome).Inc()
+}
+
+// observeBufferSize updates the relevant prometheus metrics for the number of
+// events in the buffer of the delegate backend.
+func observeBufferSize(plugin string, size int) {
+	bufferSizeGauge.WithLabelValues(plugin).Set(float64(size))
+}
+
+// observeBatch updates the relevant prometheus metrics for a batch of events
+// processed by the delegate backend.
+func observeBatch(plugin string, elapsed time.Duration) {
+	batchDuration.WithLabelValues(plugin).Observe(elapsed.Seconds())
+}
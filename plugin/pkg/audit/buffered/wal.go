@@ -0,0 +1,425 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buffered
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/apis/audit/install"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// walSegmentExt is the file extension of write-ahead log segments.
+	walSegmentExt = ".wal"
+	// walHeaderSize is the size of the record header, the length and the
+	// CRC-32C checksum of the encoded event.
+	walHeaderSize = 8
+	// walSegmentsPerLog is the number of segments the maximum size of a
+	// write-ahead log is split into, and so the granularity of truncation.
+	walSegmentsPerLog = 4
+)
+
+var walChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+func init() {
+	install.Install(audit.Scheme)
+}
+
+// wal is a write-ahead log of audit events, stored as numbered segment files
+// in a directory. Events are appended to the last segment and read in order
+// into the buffer. A segment is removed once all of its events were processed
+// by the delegate backend, so segments left over when the apiserver stops,
+// including those of events the delegate backend failed to process, are
+// replayed on start. Events may thus be sent to the delegate backend twice.
+//
+// Events are not synced to disk, so they survive restarts of the apiserver
+// but not necessarily crashes of the node.
+type wal struct {
+	dir         string
+	maxSize     int64
+	segmentSize int64
+	encoder     runtime.Encoder
+	decoder     runtime.Decoder
+
+	mu sync.Mutex
+	// cond is broadcast when a record is appended or the log is closed.
+	cond *sync.Cond
+	// segments are the segments on disk, oldest first. The last segment is
+	// appended to unless the log is closed.
+	segments []*walSegment
+	// size is the total size of the segments.
+	size   int64
+	closed bool
+
+	// reading is the segment events are read from, and readFile its file.
+	reading  *walSegment
+	readFile *os.File
+	// pending are the events read and not yet processed, by their segment.
+	pending map[*auditinternal.Event]*walSegment
+}
+
+type walSegment struct {
+	seq  uint64
+	path string
+	size int64
+	// records is the number of records in the segment, read and done the
+	// number of them read and processed, and failed the number of them the
+	// delegate backend failed to process.
+	records int
+	read    int
+	done    int
+	failed  int
+	// file is the file the segment is appended to, nil once sealed.
+	file    *os.File
+	removed bool
+}
+
+// openWAL opens the write-ahead log in the directory, creating it if needed,
+// and starts a new segment. Segments of a previous run are kept for replay.
+func openWAL(dir string, maxSize int64) (*wal, error) {
+	if dir == "" {
+		return nil, errors.New("write-ahead log directory not specified")
+	}
+	if maxSize < walSegmentsPerLog*walHeaderSize {
+		return nil, fmt.Errorf("invalid write-ahead log max size %d", maxSize)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	w := &wal{
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: maxSize / walSegmentsPerLog,
+		encoder:     audit.Codecs.LegacyCodec(auditv1.SchemeGroupVersion),
+		decoder:     audit.Codecs.UniversalDecoder(auditv1.SchemeGroupVersion),
+		pending:     map[*auditinternal.Event]*walSegment{},
+	}
+	w.cond = sync.NewCond(&w.mu)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var seq uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, walSegmentExt) {
+			continue
+		}
+		s, err := strconv.ParseUint(strings.TrimSuffix(name, walSegmentExt), 16, 64)
+		if err != nil {
+			continue
+		}
+		seg, err := recoverWALSegment(filepath.Join(dir, name), s, maxSize)
+		if err != nil {
+			return nil, err
+		}
+		if s > seq {
+			seq = s
+		}
+		if seg.records == 0 {
+			os.Remove(seg.path)
+			continue
+		}
+		w.segments = append(w.segments, seg)
+		w.size += seg.size
+	}
+	sort.Slice(w.segments, func(i, j int) bool { return w.segments[i].seq < w.segments[j].seq })
+
+	if err := w.startSegment(seq + 1); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// recoverWALSegment counts the records of a segment of a previous run. A
+// trailing partial or corrupted record, left by a crash while appending, is
+// truncated.
+func recoverWALSegment(path string, seq uint64, limit int64) (*walSegment, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	seg := &walSegment{seq: seq, path: path}
+	for {
+		data, err := readWALRecord(f, limit)
+		if err == io.EOF {
+			return seg, nil
+		}
+		if err != nil {
+			klog.Warningf("Truncating audit write-ahead log segment %s at offset %d: %v", path, seg.size, err)
+			return seg, f.Truncate(seg.size)
+		}
+		seg.size += int64(walHeaderSize + len(data))
+		seg.records++
+	}
+}
+
+// startSegment seals the last segment, if any, and starts a new one.
+func (w *wal) startSegment(seq uint64) error {
+	path := filepath.Join(w.dir, fmt.Sprintf("%016x%s", seq, walSegmentExt))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if len(w.segments) > 0 {
+		w.seal(w.segments[len(w.segments)-1])
+	}
+	w.segments = append(w.segments, &walSegment{seq: seq, path: path, file: f})
+	return nil
+}
+
+// seal closes the file of the segment and removes it if all of its events
+// were already processed.
+func (w *wal) seal(seg *walSegment) {
+	if seg.file != nil {
+		seg.file.Close()
+		seg.file = nil
+	}
+	if seg.done == seg.records {
+		w.remove(seg)
+	}
+}
+
+// remove removes the segment from disk.
+func (w *wal) remove(seg *walSegment) {
+	if seg.removed {
+		return
+	}
+	if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+		klog.Errorf("Failed to remove audit write-ahead log segment %s: %v", seg.path, err)
+	}
+	seg.removed = true
+	w.size -= seg.size
+	for i, s := range w.segments {
+		if s == seg {
+			w.segments = append(w.segments[:i], w.segments[i+1:]...)
+			break
+		}
+	}
+}
+
+// append appends the event to the log. If the log would exceed its maximum
+// size, its oldest segments are dropped, and the number of events dropped is
+// returned.
+func (w *wal) append(ev *auditinternal.Event) (int, error) {
+	data, err := runtime.Encode(w.encoder, ev)
+	if err != nil {
+		return 0, err
+	}
+	size := int64(walHeaderSize + len(data))
+	if size > w.segmentSize {
+		return 0, fmt.Errorf("event of %d bytes exceeds audit write-ahead log segment size of %d bytes", size, w.segmentSize)
+	}
+	record := make([]byte, walHeaderSize, size)
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	binary.BigEndian.PutUint32(record[4:], crc32.Checksum(data, walChecksumTable))
+	record = append(record, data...)
+	return w.appendRecord(record)
+}
+
+func (w *wal) appendRecord(record []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, errors.New("audit backend shut down")
+	}
+	last := w.segments[len(w.segments)-1]
+	if last.records > 0 && last.size+int64(len(record)) > w.segmentSize {
+		if err := w.startSegment(last.seq + 1); err != nil {
+			return 0, err
+		}
+		last = w.segments[len(w.segments)-1]
+	}
+	dropped := 0
+	for w.size+int64(len(record)) > w.maxSize && len(w.segments) > 1 {
+		oldest := w.segments[0]
+		dropped += oldest.records - oldest.read + oldest.failed
+		w.remove(oldest)
+	}
+	if _, err := last.file.Write(record); err != nil {
+		// Drop what was written of the record, so the segment stays readable.
+		if terr := last.file.Truncate(last.size); terr == nil {
+			last.file.Seek(last.size, io.SeekStart)
+		}
+		return dropped, err
+	}
+	last.size += int64(len(record))
+	last.records++
+	w.size += int64(len(record))
+	w.cond.Broadcast()
+	return dropped, nil
+}
+
+// next returns the next event of the log, blocking until one is appended. It
+// returns false once the log is closed.
+func (w *wal) next() (*auditinternal.Event, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for {
+		if w.closed {
+			return nil, false
+		}
+		if w.reading == nil || w.reading.removed || (w.reading.read == w.reading.records && w.reading.file == nil) {
+			if !w.advance() {
+				w.cond.Wait()
+				continue
+			}
+		}
+		seg := w.reading
+		if seg.read == seg.records {
+			w.cond.Wait()
+			continue
+		}
+
+		data, err := readWALRecord(w.readFile, w.segmentSize)
+		seg.read++
+		var ev *auditinternal.Event
+		if err == nil {
+			ev = &auditinternal.Event{}
+			err = runtime.DecodeInto(w.decoder, data, ev)
+		}
+		if err != nil {
+			klog.Errorf("Failed to read audit write-ahead log segment %s: %v", seg.path, err)
+			seg.done++
+			continue
+		}
+		w.pending[ev] = seg
+		return ev, true
+	}
+}
+
+// advance starts reading the segment following the one read, returning false
+// if there is none.
+func (w *wal) advance() bool {
+	for _, seg := range w.segments {
+		if w.reading != nil && seg.seq <= w.reading.seq {
+			continue
+		}
+		f, err := os.Open(seg.path)
+		if err != nil {
+			klog.Errorf("Failed to open audit write-ahead log segment %s: %v", seg.path, err)
+			seg.read = seg.records
+			seg.done = seg.records
+			if seg.file == nil {
+				w.remove(seg)
+			}
+			continue
+		}
+		if w.readFile != nil {
+			w.readFile.Close()
+		}
+		w.reading, w.readFile = seg, f
+		return true
+	}
+	return false
+}
+
+// ack marks the events as processed, removing the segments all of whose
+// events were processed.
+func (w *wal) ack(events []*auditinternal.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ev := range events {
+		seg, ok := w.pending[ev]
+		if !ok {
+			continue
+		}
+		delete(w.pending, ev)
+		seg.done++
+		if seg.file == nil && seg.done == seg.records {
+			w.remove(seg)
+		}
+	}
+}
+
+// keep marks the events as failed to be processed, keeping their segments
+// until the log is reopened, when the events are replayed.
+func (w *wal) keep(events []*auditinternal.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ev := range events {
+		seg, ok := w.pending[ev]
+		if !ok {
+			continue
+		}
+		delete(w.pending, ev)
+		seg.failed++
+	}
+}
+
+// close stops appending and reading events. Events read may still be acked.
+func (w *wal) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+	w.closed = true
+	w.seal(w.segments[len(w.segments)-1])
+	if w.readFile != nil {
+		w.readFile.Close()
+		w.readFile = nil
+	}
+	w.cond.Broadcast()
+}
+
+// readWALRecord reads a record of at most limit bytes and returns the encoded
+// event.
+func readWALRecord(r io.Reader, limit int64) ([]byte, error) {
+	var header [walHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("partial record header")
+		}
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(header[:])
+	if int64(length)+walHeaderSize > limit {
+		return nil, fmt.Errorf("record length %d exceeds limit of %d bytes", length, limit)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("partial record: %v", err)
+	}
+	if crc32.Checksum(data, walChecksumTable) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, errors.New("checksum mismatch")
+	}
+	return data, nil
+}
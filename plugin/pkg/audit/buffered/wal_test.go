@@ -0,0 +1,320 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buffered
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/plugin/pkg/audit/fake"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func newIDEvents(first, number int) []*auditinternal.Event {
+	events := make([]*auditinternal.Event, number)
+	for i := range events {
+		events[i] = &auditinternal.Event{AuditID: types.UID(fmt.Sprintf("%02d", first+i))}
+	}
+	return events
+}
+
+func appendEvents(t *testing.T, w *wal, events []*auditinternal.Event) {
+	for _, ev := range events {
+		_, err := w.append(ev)
+		require.NoError(t, err)
+	}
+}
+
+// readEvents reads the number of events from the log and returns their audit IDs.
+func readEvents(t *testing.T, w *wal, number int) ([]*auditinternal.Event, []types.UID) {
+	var events []*auditinternal.Event
+	var ids []types.UID
+	for i := 0; i < number; i++ {
+		ev, ok := w.next()
+		require.True(t, ok)
+		events = append(events, ev)
+		ids = append(ids, ev.AuditID)
+	}
+	return events, ids
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+walSegmentExt))
+	require.NoError(t, err)
+	return files
+}
+
+func TestWAL(t *testing.T) {
+	dir := t.TempDir()
+	w, err := openWAL(dir, 1<<20)
+	require.NoError(t, err)
+
+	appendEvents(t, w, newIDEvents(0, 3))
+	events, ids := readEvents(t, w, 3)
+	assert.Equal(t, []types.UID{"00", "01", "02"}, ids)
+
+	w.ack(events)
+	w.close()
+	assert.Empty(t, segmentFiles(t, dir), "segments of processed events must be removed")
+}
+
+func TestWALNextBlocks(t *testing.T) {
+	w, err := openWAL(t.TempDir(), 1<<20)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, ids := readEvents(t, w, 1)
+		assert.Equal(t, []types.UID{"00"}, ids)
+		_, ok := w.next()
+		assert.False(t, ok, "next must return once the log is closed")
+	}()
+	appendEvents(t, w, newIDEvents(0, 1))
+	require.NoError(t, wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return len(w.pending) == 1, nil
+	}))
+	w.close()
+	wg.Wait()
+	_, err = w.append(newIDEvents(1, 1)[0])
+	assert.Error(t, err, "closed log must not be appended to")
+}
+
+func TestWALReplay(t *testing.T) {
+	dir := t.TempDir()
+	w, err := openWAL(dir, 1<<20)
+	require.NoError(t, err)
+	appendEvents(t, w, newIDEvents(0, 3))
+	events, _ := readEvents(t, w, 1)
+	w.ack(events)
+	w.close()
+
+	// Simulate a crash while appending.
+	files := segmentFiles(t, dir)
+	require.Len(t, files, 1)
+	f, err := os.OpenFile(files[0], os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 0, 42, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	w, err = openWAL(dir, 1<<20)
+	require.NoError(t, err)
+	appendEvents(t, w, newIDEvents(3, 1))
+	events, ids := readEvents(t, w, 4)
+	assert.Equal(t, []types.UID{"00", "01", "02", "03"}, ids, "events of partially processed segments must be replayed")
+
+	w.ack(events)
+	w.close()
+	assert.Empty(t, segmentFiles(t, dir))
+}
+
+func TestWALMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	w, err := openWAL(dir, 1<<20)
+	require.NoError(t, err)
+	appendEvents(t, w, newIDEvents(0, 1))
+	recordSize := w.size
+	w.close()
+	require.NoError(t, os.RemoveAll(dir))
+
+	// Four records per segment, four segments per log.
+	maxSize := 4 * walSegmentsPerLog * recordSize
+	w, err = openWAL(dir, maxSize)
+	require.NoError(t, err)
+	appendEvents(t, w, newIDEvents(0, 20))
+	assert.LessOrEqual(t, w.size, maxSize)
+	assert.Len(t, segmentFiles(t, dir), walSegmentsPerLog)
+
+	_, ids := readEvents(t, w, 1)
+	assert.Equal(t, []types.UID{"04"}, ids, "oldest segment must be dropped")
+	w.close()
+}
+
+func TestBufferedBackendWAL(t *testing.T) {
+	dir := t.TempDir()
+	config := testBatchConfig()
+	config.OverflowPolicy = OverflowPolicySpillToDisk
+	config.WALDir = dir
+	config.WALMaxSize = 1 << 20
+	config.MaxBatchWait = 10 * time.Millisecond
+
+	// Events appended before the backend is stopped are not lost.
+	backend := NewBackend(&fake.Backend{}, config)
+	backend.ProcessEvents(newIDEvents(0, 3)...)
+
+	var mu sync.Mutex
+	var ids []types.UID
+	delegate := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, ev := range events {
+				ids = append(ids, ev.AuditID)
+			}
+		},
+	}
+	backend = NewBackend(delegate, config)
+	stopCh := make(chan struct{})
+	require.NoError(t, backend.Run(stopCh))
+	backend.ProcessEvents(newIDEvents(3, 2)...)
+	require.NoError(t, wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(ids) == 5, nil
+	}))
+	close(stopCh)
+	backend.Shutdown()
+
+	assert.Equal(t, []types.UID{"00", "01", "02", "03", "04"}, ids)
+	assert.Empty(t, segmentFiles(t, dir))
+}
+
+// failingBackend is a delegate backend failing to process any event.
+type failingBackend struct {
+	fake.Backend
+	calls int32
+}
+
+func (b *failingBackend) ProcessEvents(...*auditinternal.Event) bool {
+	atomic.AddInt32(&b.calls, 1)
+	return false
+}
+
+func TestBufferedBackendWALDelegateFailure(t *testing.T) {
+	dir := t.TempDir()
+	config := testBatchConfig()
+	config.OverflowPolicy = OverflowPolicySpillToDisk
+	config.WALDir = dir
+	config.WALMaxSize = 1 << 20
+	config.MaxBatchWait = 10 * time.Millisecond
+
+	// The events the delegate backend fails to process are kept in the log.
+	failing := &failingBackend{}
+	backend := NewBackend(failing, config)
+	stopCh := make(chan struct{})
+	require.NoError(t, backend.Run(stopCh))
+	backend.ProcessEvents(newIDEvents(0, 3)...)
+	require.NoError(t, wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return atomic.LoadInt32(&failing.calls) > 0, nil
+	}))
+	close(stopCh)
+	backend.Shutdown()
+	assert.NotEmpty(t, segmentFiles(t, dir), "segments of failed events must be kept")
+
+	// They are replayed once the backend is restarted.
+	var mu sync.Mutex
+	var ids []types.UID
+	delegate := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, ev := range events {
+				ids = append(ids, ev.AuditID)
+			}
+		},
+	}
+	backend = NewBackend(delegate, config)
+	stopCh = make(chan struct{})
+	require.NoError(t, backend.Run(stopCh))
+	require.NoError(t, wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(ids) == 3, nil
+	}))
+	close(stopCh)
+	backend.Shutdown()
+
+	assert.Equal(t, []types.UID{"00", "01", "02"}, ids)
+	assert.Empty(t, segmentFiles(t, dir))
+}
+
+func TestBufferedBackendWALSpill(t *testing.T) {
+	overflowCounter.Reset()
+
+	config := testBatchConfig()
+	config.BufferSize = 1
+	config.MaxBatchSize = 1
+	config.AsyncDelegate = false
+	config.OverflowPolicy = OverflowPolicySpillToDisk
+	config.WALDir = t.TempDir()
+	config.WALMaxSize = 1 << 20
+
+	releaseCh := make(chan struct{})
+	var processed int32
+	delegate := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			<-releaseCh
+			atomic.AddInt32(&processed, int32(len(events)))
+		},
+	}
+	backend := NewBackend(delegate, config).(*bufferedBackend)
+	stopCh := make(chan struct{})
+	require.NoError(t, backend.Run(stopCh))
+
+	// The first event blocks the delegate backend and the second fills the buffer.
+	backend.ProcessEvents(newIDEvents(0, 2)...)
+	require.NoError(t, wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return len(backend.buffer) == 1, nil
+	}))
+	backend.ProcessEvents(newIDEvents(2, 1)...)
+
+	close(releaseCh)
+	require.NoError(t, wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return atomic.LoadInt32(&processed) == 3, nil
+	}))
+	close(stopCh)
+	backend.Shutdown()
+
+	expected := strings.NewReader(`
+		# HELP apiserver_audit_buffer_overflow_total [ALPHA] Counter of audit events that found the buffer of a backend full. Outcome is 'dropped' if the event was dropped, 'blocked' if it was buffered after blocking, or 'spilled' if it was kept on disk.
+		# TYPE apiserver_audit_buffer_overflow_total counter
+		apiserver_audit_buffer_overflow_total{outcome="spilled",plugin=""} 1
+`)
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, expected, "apiserver_audit_buffer_overflow_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBufferedBackendWALOpenError(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0600))
+	config := testBatchConfig()
+	config.OverflowPolicy = OverflowPolicySpillToDisk
+	config.WALDir = file
+	config.WALMaxSize = 1 << 20
+
+	backend := NewBackend(&fake.Backend{}, config)
+	assert.Error(t, backend.Run(wait.NeverStop))
+	backend.Shutdown()
+}
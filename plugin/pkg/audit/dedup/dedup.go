@@ -0,0 +1,240 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// PluginName is the name reported in error metrics.
+	PluginName = "dedup"
+
+	// CountAnnotationKey is the number of identical events an event sent to
+	// the delegate stands for, set if more than one.
+	CountAnnotationKey = "audit.k8s.io/coalesced-count"
+	// LastTimestampAnnotationKey is the stage timestamp of the last identical
+	// event coalesced, set if more than one.
+	LastTimestampAnnotationKey = "audit.k8s.io/coalesced-last-timestamp"
+)
+
+// Config represents deduplicating backend configuration.
+type Config struct {
+	// Window is the time identical events are coalesced for. The first event
+	// of a window is sent to the delegate once the window ends.
+	Window time.Duration
+	// MaxPending is the maximum number of distinct events coalesced in a
+	// window. Further distinct events are sent to the delegate immediately.
+	MaxPending int
+}
+
+// key identifies identical events. Only Metadata-level events are coalesced,
+// so the objects of the events do not need to be compared. The identity the
+// request was made with and its source are kept as audit evidence, so events
+// of different groups, impersonated users or source IPs are distinct.
+type key struct {
+	stage            auditinternal.Stage
+	user             string
+	groups           string
+	impersonatedUser string
+	sourceIPs        string
+	verb             string
+	apiGroup         string
+	apiVersion       string
+	resource         string
+	subresource      string
+	namespace        string
+	name             string
+	code             int32
+}
+
+type pending struct {
+	event *auditinternal.Event
+	count int
+	last  time.Time
+}
+
+type backend struct {
+	delegateBackend audit.Backend
+	c               Config
+	clock           clock.WithTicker
+
+	// mu protects pending.
+	mu      sync.Mutex
+	pending map[key]*pending
+	// order is the order the pending events were received in.
+	order []key
+
+	stopCh chan struct{}
+	// wg waits for the flushing goroutine on shutdown.
+	wg           sync.WaitGroup
+	shutdownOnce sync.Once
+}
+
+var _ audit.Backend = &backend{}
+
+// NewBackend returns a new deduplicating backend, using configuration passed
+// in the parameters. Identical Metadata-level events, i.e. of the same stage,
+// user and groups, impersonated user, source IPs, verb, object and response code,
+// received within a window are sent to the delegate as the first of them,
+// annotated with their count. Other events are sent to the delegate
+// immediately.
+// Deduplicating backend automatically runs and shut downs the delegate backend.
+func NewBackend(delegateBackend audit.Backend, config Config) audit.Backend {
+	return &backend{
+		delegateBackend: delegateBackend,
+		c:               config,
+		clock:           clock.RealClock{},
+		pending:         map[key]*pending{},
+		stopCh:          make(chan struct{}),
+	}
+}
+
+func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
+	var passed []*auditinternal.Event
+	b.mu.Lock()
+	for _, ev := range events {
+		k, ok := keyOf(ev)
+		if !ok {
+			passed = append(passed, ev)
+			continue
+		}
+		if p, ok := b.pending[k]; ok {
+			p.count++
+			p.last = ev.StageTimestamp.Time
+			continue
+		}
+		if len(b.pending) >= b.c.MaxPending {
+			passed = append(passed, ev)
+			continue
+		}
+		// The caller may modify the event once processed, e.g. to record the
+		// next stage of the request.
+		b.pending[k] = &pending{event: ev.DeepCopy(), count: 1, last: ev.StageTimestamp.Time}
+		b.order = append(b.order, k)
+	}
+	b.mu.Unlock()
+
+	if len(passed) == 0 {
+		return true
+	}
+	return b.delegateBackend.ProcessEvents(passed...)
+}
+
+func keyOf(ev *auditinternal.Event) (key, bool) {
+	if ev.Level != auditinternal.LevelMetadata {
+		return key{}, false
+	}
+	k := key{
+		stage:     ev.Stage,
+		user:      ev.User.Username,
+		groups:    fmt.Sprintf("%q", ev.User.Groups),
+		sourceIPs: fmt.Sprintf("%q", ev.SourceIPs),
+		verb:      ev.Verb,
+	}
+	if imp := ev.ImpersonatedUser; imp != nil {
+		k.impersonatedUser = fmt.Sprintf("%q %q", imp.Username, imp.Groups)
+	}
+	if ref := ev.ObjectRef; ref != nil {
+		k.apiGroup = ref.APIGroup
+		k.apiVersion = ref.APIVersion
+		k.resource = ref.Resource
+		k.subresource = ref.Subresource
+		k.namespace = ref.Namespace
+		k.name = ref.Name
+	} else {
+		k.resource = ev.RequestURI
+	}
+	if ev.ResponseStatus != nil {
+		k.code = ev.ResponseStatus.Code
+	}
+	return k, true
+}
+
+// flush sends the events coalesced so far to the delegate.
+func (b *backend) flush() {
+	b.mu.Lock()
+	if len(b.order) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	events := make([]*auditinternal.Event, 0, len(b.order))
+	for _, k := range b.order {
+		p := b.pending[k]
+		if p.count > 1 {
+			if p.event.Annotations == nil {
+				p.event.Annotations = map[string]string{}
+			}
+			p.event.Annotations[CountAnnotationKey] = strconv.Itoa(p.count)
+			p.event.Annotations[LastTimestampAnnotationKey] = p.last.UTC().Format(time.RFC3339Nano)
+		}
+		events = append(events, p.event)
+	}
+	b.pending = map[key]*pending{}
+	b.order = nil
+	b.mu.Unlock()
+
+	if !b.delegateBackend.ProcessEvents(events...) {
+		audit.HandlePluginError(PluginName, fmt.Errorf("delegate backend failed to process coalesced events"), events...)
+	}
+}
+
+func (b *backend) Run(stopCh <-chan struct{}) error {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := b.clock.NewTicker(b.c.Window)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				b.flush()
+			case <-stopCh:
+				return
+			case <-b.stopCh:
+				return
+			}
+		}
+	}()
+	return b.delegateBackend.Run(stopCh)
+}
+
+// Shutdown sends the events coalesced so far to the delegate before shutting
+// it down. Only the first call has an effect.
+func (b *backend) Shutdown() {
+	b.shutdownOnce.Do(func() {
+		close(b.stopCh)
+		b.wg.Wait()
+		b.flush()
+		b.delegateBackend.Shutdown()
+	})
+}
+
+func (b *backend) HealthCheck() error {
+	return audit.CheckHealth(b.delegateBackend)
+}
+
+func (b *backend) String() string {
+	return fmt.Sprintf("%s<%s>", PluginName, b.delegateBackend)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/plugin/pkg/audit/fake"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var defaultConfig = Config{
+	Window:     time.Minute,
+	MaxPending: 10,
+}
+
+func newEvent(id, user string, level auditinternal.Level, code int32, ts time.Time) *auditinternal.Event {
+	return &auditinternal.Event{
+		AuditID:        types.UID(id),
+		Level:          level,
+		Stage:          auditinternal.StageResponseComplete,
+		Verb:           "get",
+		User:           authnv1.UserInfo{Username: user},
+		ObjectRef:      &auditinternal.ObjectReference{Resource: "configmaps", Namespace: "default", Name: "leader"},
+		ResponseStatus: &metav1.Status{Code: code},
+		StageTimestamp: metav1.NewMicroTime(ts),
+	}
+}
+
+func TestCoalescingEvents(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	var (
+		mu  sync.Mutex
+		got []*auditinternal.Event
+	)
+	fb := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, events...)
+		},
+	}
+	b := NewBackend(fb, defaultConfig)
+
+	for i := 0; i < 5; i++ {
+		ev := newEvent(strconv.Itoa(i), "controller", auditinternal.LevelMetadata, 200, start.Add(time.Duration(i)*time.Second))
+		require.True(t, b.ProcessEvents(ev))
+		// The caller reusing the event must not modify the coalesced one.
+		ev.Stage = auditinternal.StagePanic
+	}
+	require.True(t, b.ProcessEvents(
+		newEvent("5", "controller", auditinternal.LevelMetadata, 409, start),
+		newEvent("6", "admin", auditinternal.LevelMetadata, 200, start),
+		newEvent("7", "controller", auditinternal.LevelRequest, 200, start),
+	))
+	require.Len(t, got, 1, "Only events above Metadata level should be sent immediately")
+	assert.Equal(t, "7", string(got[0].AuditID))
+
+	b.(*backend).flush()
+	require.Len(t, got, 4)
+	first := got[1]
+	assert.Equal(t, "0", string(first.AuditID))
+	assert.Equal(t, auditinternal.StageResponseComplete, first.Stage)
+	assert.Equal(t, "5", first.Annotations[CountAnnotationKey])
+	assert.Equal(t, start.Add(4*time.Second).Format(time.RFC3339Nano), first.Annotations[LastTimestampAnnotationKey])
+	for _, ev := range got[2:] {
+		assert.NotContains(t, ev.Annotations, CountAnnotationKey, "Distinct events should not be annotated")
+	}
+	assert.Equal(t, "5", string(got[2].AuditID))
+	assert.Equal(t, "6", string(got[3].AuditID))
+
+	b.(*backend).flush()
+	assert.Len(t, got, 4, "Flushed events should not be sent again")
+}
+
+func TestMaxPending(t *testing.T) {
+	var got []*auditinternal.Event
+	fb := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			got = append(got, events...)
+		},
+	}
+	b := NewBackend(fb, Config{Window: time.Minute, MaxPending: 1})
+
+	now := time.Now()
+	b.ProcessEvents(
+		newEvent("0", "a", auditinternal.LevelMetadata, 200, now),
+		newEvent("1", "b", auditinternal.LevelMetadata, 200, now),
+		newEvent("2", "a", auditinternal.LevelMetadata, 200, now),
+	)
+	require.Len(t, got, 1, "Distinct events beyond the maximum should be sent immediately")
+	assert.Equal(t, "1", string(got[0].AuditID))
+}
+
+func TestDistinctIdentities(t *testing.T) {
+	var got []*auditinternal.Event
+	fb := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			got = append(got, events...)
+		},
+	}
+	b := NewBackend(fb, defaultConfig)
+
+	now := time.Now()
+	events := []*auditinternal.Event{
+		newEvent("0", "a", auditinternal.LevelMetadata, 200, now),
+		newEvent("1", "a", auditinternal.LevelMetadata, 200, now),
+		newEvent("2", "a", auditinternal.LevelMetadata, 200, now),
+		newEvent("3", "a", auditinternal.LevelMetadata, 200, now),
+	}
+	events[1].User.Groups = []string{"system:masters"}
+	events[2].ImpersonatedUser = &authnv1.UserInfo{Username: "b"}
+	events[3].SourceIPs = []string{"10.0.0.1"}
+	b.ProcessEvents(events...)
+
+	b.(*backend).flush()
+	require.Len(t, got, 4, "Events of different identities or sources should not be coalesced")
+	for _, ev := range got {
+		assert.NotContains(t, ev.Annotations, CountAnnotationKey)
+	}
+}
+
+func TestFlushingWindows(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	sent := make(chan []*auditinternal.Event, 1)
+	fb := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			sent <- events
+		},
+	}
+	b := NewBackend(fb, defaultConfig)
+	b.(*backend).clock = fakeClock
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	require.NoError(t, b.Run(stopCh))
+
+	b.ProcessEvents(newEvent("0", "a", auditinternal.LevelMetadata, 200, fakeClock.Now()))
+	require.Eventually(t, fakeClock.HasWaiters, wait.ForeverTestTimeout, 10*time.Millisecond)
+	fakeClock.Step(defaultConfig.Window)
+	select {
+	case events := <-sent:
+		require.Len(t, events, 1)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("Events should be sent once the window ends")
+	}
+
+	b.ProcessEvents(newEvent("1", "a", auditinternal.LevelMetadata, 200, fakeClock.Now()))
+	b.Shutdown()
+	select {
+	case events := <-sent:
+		require.Len(t, events, 1, "Pending events should be sent on shutdown")
+	default:
+		t.Fatal("Pending events should be sent on shutdown")
+	}
+	// Shutting down again must not panic.
+	b.Shutdown()
+}
+
+// failingBackend fails to process any event.
+type failingBackend struct {
+	fake.Backend
+}
+
+func (b *failingBackend) ProcessEvents(...*auditinternal.Event) bool {
+	return false
+}
+
+func TestFlushFailure(t *testing.T) {
+	b := NewBackend(&failingBackend{}, defaultConfig)
+	now := time.Now()
+	b.ProcessEvents(
+		newEvent("0", "a", auditinternal.LevelMetadata, 200, now),
+		newEvent("1", "a", auditinternal.LevelMetadata, 200, now),
+		newEvent("2", "b", auditinternal.LevelMetadata, 200, now),
+	)
+	b.(*backend).flush()
+
+	expected := `
+		# HELP apiserver_audit_error_total [ALPHA] Counter of audit events that failed to be audited properly. Plugin identifies the plugin affected by the error.
+		# TYPE apiserver_audit_error_total counter
+		apiserver_audit_error_total{plugin="dedup"} 2
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "apiserver_audit_error_total"); err != nil {
+		t.Error(err)
+	}
+}
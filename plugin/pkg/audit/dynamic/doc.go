@@ -0,0 +1,21 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dynamic provides an implementation for the audit.Backend interface
+// that sends audit events to the webhooks of audit sinks, which are added,
+// changed and removed at runtime as the AuditSink objects they are read from,
+// either watched through the API or in a file, change.
+package dynamic // import "k8s.io/apiserver/plugin/pkg/audit/dynamic"
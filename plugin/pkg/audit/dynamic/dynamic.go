@@ -0,0 +1,405 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamic
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/apis/audit/validation"
+	"k8s.io/apiserver/pkg/audit"
+	pluginbuffered "k8s.io/apiserver/plugin/pkg/audit/buffered"
+	pluginwebhook "k8s.io/apiserver/plugin/pkg/audit/webhook"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// PluginName is the name of this plugin, to be used in help and logs.
+	PluginName = "dynamic"
+
+	// DefaultReloadInterval is the default interval the sinks file is
+	// re-read at.
+	DefaultReloadInterval = 10 * time.Second
+)
+
+// AuditSinksResource is the resource of the AuditSink objects watched by
+// NewBackend. The apiserver does not serve it itself, it is meant to be
+// served by a custom resource definition or an aggregated API server.
+var AuditSinksResource = auditv1.SchemeGroupVersion.WithResource("auditsinks")
+
+// Config represents dynamic backend configuration.
+type Config struct {
+	// ReloadInterval is the interval the sinks file is re-read at. It only
+	// applies to backends created with NewFileBackend.
+	ReloadInterval time.Duration
+	// BatchConfig is the batching configuration of the webhooks of the sinks.
+	// The throttle of a sink overrides ThrottleQPS and ThrottleBurst.
+	BatchConfig pluginbuffered.BatchConfig
+	// RetryConfig configures how the webhooks of the sinks retry sending
+	// a batch of events.
+	RetryConfig pluginwebhook.RetryConfig
+	// GroupVersion is the API group version the events are sent to the
+	// webhooks as.
+	GroupVersion schema.GroupVersion
+}
+
+type sink struct {
+	spec    auditinternal.AuditSinkSpec
+	backend audit.Backend
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+func (s *sink) stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+type backend struct {
+	config Config
+	// watch keeps the sinks up to date until stopCh is closed.
+	watch func(stopCh <-chan struct{})
+
+	// path is the sinks file of backends created with NewFileBackend.
+	path string
+	// newSinkBackend returns the backend sending events to the webhook of
+	// the sink, overridden in tests.
+	newSinkBackend func(sink *auditinternal.AuditSink) (audit.Backend, error)
+
+	// mu protects the fields below. ProcessEvents holds it for reading, so
+	// that the backends of removed sinks are only shut down once no events
+	// are sent to them anymore.
+	mu      sync.RWMutex
+	content []byte
+	sinks   map[string]*sink
+	union   audit.Backend
+	// running is true once Run has been called, so that the backends of
+	// sinks added later are run right away.
+	running bool
+}
+
+var _ audit.Backend = &backend{}
+
+// NewBackend returns an audit backend that sends events to the webhooks of the
+// AuditSink objects of AuditSinksResource, read with the given client. Once the
+// backend runs, the objects are watched and sinks are added, changed and removed
+// accordingly. The events sent to each sink are selected by its policy. Objects
+// that fail to decode or validate are ignored.
+func NewBackend(client dynamic.Interface, config Config) (audit.Backend, error) {
+	b := newBackend(config)
+	informer := dynamicinformer.NewFilteredDynamicInformer(client, AuditSinksResource, metav1.NamespaceAll, 0, cache.Indexers{}, nil).Informer()
+	// Every change is applied by setting all sinks, so it does not matter which object changed.
+	update := func() { b.setSinks(sinksOf(informer.GetStore().List())) }
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { update() },
+		UpdateFunc: func(interface{}, interface{}) { update() },
+		DeleteFunc: func(interface{}) { update() },
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch audit sinks: %v", err)
+	}
+	b.watch = informer.Run
+	return b, nil
+}
+
+// NewFileBackend returns an audit backend that sends events to the webhooks of
+// the audit sinks in the file at path, an AuditSinkList or a single AuditSink of
+// the audit.k8s.io API group. The file is re-read every reload interval once the
+// backend runs, and sinks are added, changed and removed accordingly. The events
+// sent to each sink are selected by its policy. A file that fails to load or
+// validate is rejected and the previously loaded sinks stay in effect, the
+// initial file must load successfully.
+func NewFileBackend(path string, config Config) (audit.Backend, error) {
+	b := newBackend(config)
+	b.path = path
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+	b.watch = func(stopCh <-chan struct{}) {
+		wait.Until(func() {
+			if err := b.load(); err != nil {
+				utilruntime.HandleError(fmt.Errorf("reloading audit sinks failed: %v", err))
+			}
+		}, b.config.ReloadInterval, stopCh)
+	}
+	return b, nil
+}
+
+func newBackend(config Config) *backend {
+	b := &backend{
+		config: config,
+		sinks:  map[string]*sink{},
+		union:  audit.RoutedUnion(),
+	}
+	b.newSinkBackend = b.newWebhookBackend
+	return b
+}
+
+// load reads the sinks file and updates the sinks if the content changed.
+func (b *backend) load() error {
+	content, err := ioutil.ReadFile(b.path)
+	if err != nil {
+		return fmt.Errorf("failed to read audit sinks file %q: %v", b.path, err)
+	}
+	b.mu.RLock()
+	unchanged := bytes.Equal(b.content, content)
+	b.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	sinks, err := decodeSinks(content)
+	if err != nil {
+		return fmt.Errorf("%v: from file %v", err, b.path)
+	}
+	b.setSinks(sinks)
+
+	b.mu.Lock()
+	b.content = content
+	b.mu.Unlock()
+	klog.V(2).InfoS("Loaded audit sinks", "file", b.path, "sinks", len(sinks))
+	return nil
+}
+
+func decodeSinks(content []byte) ([]auditinternal.AuditSink, error) {
+	obj, gvk, err := audit.Codecs.UniversalDecoder().Decode(content, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audit sinks: %v", err)
+	}
+	var list *auditinternal.AuditSinkList
+	switch o := obj.(type) {
+	case *auditinternal.AuditSinkList:
+		list = o
+	case *auditinternal.AuditSink:
+		list = &auditinternal.AuditSinkList{Items: []auditinternal.AuditSink{*o}}
+	default:
+		return nil, fmt.Errorf("unexpected kind %v, expected AuditSinkList or AuditSink", gvk)
+	}
+	if errs := validation.ValidateAuditSinkList(list); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid audit sinks: %v", errs.ToAggregate())
+	}
+	return list.Items, nil
+}
+
+// sinksOf returns the valid audit sinks of the given AuditSink objects.
+func sinksOf(objs []interface{}) []auditinternal.AuditSink {
+	sinks := make([]auditinternal.AuditSink, 0, len(objs))
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		sink, err := decodeSink(u)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("ignoring audit sink %q: %v", u.GetName(), err))
+			continue
+		}
+		sinks = append(sinks, *sink)
+	}
+	return sinks
+}
+
+func decodeSink(u *unstructured.Unstructured) (*auditinternal.AuditSink, error) {
+	content, err := u.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	obj, gvk, err := audit.Codecs.UniversalDecoder().Decode(content, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audit sink: %v", err)
+	}
+	sink, ok := obj.(*auditinternal.AuditSink)
+	if !ok {
+		return nil, fmt.Errorf("unexpected kind %v, expected AuditSink", gvk)
+	}
+	if errs := validation.ValidateAuditSink(sink); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid audit sink: %v", errs.ToAggregate())
+	}
+	return sink, nil
+}
+
+// setSinks adds, changes and removes the sinks to match the given sinks. Sinks
+// whose webhook cannot be configured are left as they are.
+func (b *backend) setSinks(sinks []auditinternal.AuditSink) {
+	b.mu.RLock()
+	current := b.sinks
+	running := b.running
+	b.mu.RUnlock()
+
+	updated := make(map[string]*sink, len(sinks))
+	var added []*sink
+	for i := range sinks {
+		name := sinks[i].Name
+		if s, ok := current[name]; ok && reflect.DeepEqual(s.spec, sinks[i].Spec) {
+			updated[name] = s
+			continue
+		}
+		delegate, err := b.newSinkBackend(&sinks[i])
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to configure audit sink %q: %v", name, err))
+			if s, ok := current[name]; ok {
+				updated[name] = s
+			}
+			continue
+		}
+		s := &sink{spec: sinks[i].Spec, backend: delegate, stopCh: make(chan struct{})}
+		updated[name] = s
+		added = append(added, s)
+	}
+	if running {
+		for _, s := range added {
+			b.runSink(s)
+		}
+	}
+
+	b.mu.Lock()
+	b.sinks = updated
+	b.union = newUnion(updated)
+	b.mu.Unlock()
+
+	// No events are sent to the removed sinks anymore.
+	for name, s := range current {
+		if updated[name] != s {
+			klog.V(2).InfoS("Removing audit sink", "sink", name)
+			go func(s *sink) {
+				s.stop()
+				s.backend.Shutdown()
+			}(s)
+		}
+	}
+}
+
+// newUnion returns a backend sending events to the sinks, selected by their
+// policies.
+func newUnion(sinks map[string]*sink) audit.Backend {
+	names := make([]string, 0, len(sinks))
+	for name := range sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	backends := make([]audit.NamedBackend, 0, len(names))
+	for _, name := range names {
+		s := sinks[name]
+		backends = append(backends, audit.NamedBackend{
+			Name:       name,
+			Backend:    s.backend,
+			OmitStages: s.spec.Policy.OmitStages,
+			MaxLevel:   s.spec.Policy.Level,
+			Verbs:      s.spec.Policy.Verbs,
+			Namespaces: s.spec.Policy.Namespaces,
+		})
+	}
+	return audit.RoutedUnion(backends...)
+}
+
+// newWebhookBackend returns a buffered backend sending events to the webhook
+// of the sink.
+func (b *backend) newWebhookBackend(s *auditinternal.AuditSink) (audit.Backend, error) {
+	clientConfig := &rest.Config{
+		Host: s.Spec.Webhook.ClientConfig.URL,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: s.Spec.Webhook.ClientConfig.CABundle,
+		},
+	}
+	delegate, err := pluginwebhook.NewSinkBackend(s.Name, clientConfig, b.config.GroupVersion, b.config.RetryConfig)
+	if err != nil {
+		return nil, err
+	}
+	batchConfig := b.config.BatchConfig
+	if throttle := s.Spec.Webhook.Throttle; throttle != nil {
+		batchConfig.ThrottleEnable = true
+		if throttle.QPS != nil {
+			batchConfig.ThrottleQPS = float32(*throttle.QPS)
+		}
+		if throttle.Burst != nil {
+			batchConfig.ThrottleBurst = int(*throttle.Burst)
+		}
+	}
+	return pluginbuffered.NewBackend(delegate, batchConfig), nil
+}
+
+func (b *backend) runSink(s *sink) {
+	if err := s.backend.Run(s.stopCh); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to run audit sink backend %s: %v", s.backend, err))
+	}
+}
+
+func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.union.ProcessEvents(events...)
+}
+
+func (b *backend) Run(stopCh <-chan struct{}) error {
+	b.mu.Lock()
+	b.running = true
+	for _, s := range b.sinks {
+		b.runSink(s)
+	}
+	b.mu.Unlock()
+
+	go b.watch(stopCh)
+	go func() {
+		<-stopCh
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+		for _, s := range b.sinks {
+			s.stop()
+		}
+	}()
+	return nil
+}
+
+// Shutdown shuts down the backends of the sinks. Backends that were never run
+// have nothing to shut down.
+func (b *backend) Shutdown() {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if !b.running {
+		return
+	}
+	for _, s := range b.sinks {
+		s.stop()
+		s.backend.Shutdown()
+	}
+}
+
+func (b *backend) HealthCheck() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return audit.CheckHealth(b.union)
+}
+
+func (b *backend) String() string {
+	return PluginName
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dynamic
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+	pluginbuffered "k8s.io/apiserver/plugin/pkg/audit/buffered"
+	"k8s.io/apiserver/plugin/pkg/audit/fake"
+	pluginwebhook "k8s.io/apiserver/plugin/pkg/audit/webhook"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+)
+
+const sinksFile = `
+apiVersion: audit.k8s.io/v1
+kind: AuditSinkList
+items:
+- metadata:
+    name: writes
+  spec:
+    policy:
+      level: RequestResponse
+      verbs: ["create"]
+    webhook:
+      clientConfig:
+        url: https://writes.example.com
+- metadata:
+    name: metadata
+  spec:
+    policy:
+      level: Metadata
+    webhook:
+      clientConfig:
+        url: https://metadata.example.com
+`
+
+// recorder records the events sent to the sinks and the sinks shut down.
+type recorder struct {
+	mu       sync.Mutex
+	events   map[string][]*auditinternal.Event
+	shutdown map[string]bool
+}
+
+func (r *recorder) newSinkBackend(sink *auditinternal.AuditSink) (audit.Backend, error) {
+	name := sink.Name
+	return &shutdownRecorder{
+		Backend: fake.Backend{OnRequest: func(events []*auditinternal.Event) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.events[name] = append(r.events[name], events...)
+		}},
+		onShutdown: func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.shutdown[name] = true
+		},
+	}, nil
+}
+
+type shutdownRecorder struct {
+	fake.Backend
+	onShutdown func()
+}
+
+func (b *shutdownRecorder) Shutdown() {
+	b.onShutdown()
+}
+
+func newRecorder() *recorder {
+	return &recorder{events: map[string][]*auditinternal.Event{}, shutdown: map[string]bool{}}
+}
+
+func newTestBackend(t *testing.T, path string) (*backend, *recorder) {
+	r := newRecorder()
+	b := newBackend(Config{ReloadInterval: time.Hour})
+	b.path = path
+	b.newSinkBackend = r.newSinkBackend
+	b.watch = func(<-chan struct{}) {}
+	require.NoError(t, b.load())
+	return b, r
+}
+
+func TestSinksFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sinks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(sinksFile), 0600))
+	b, r := newTestBackend(t, path)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	require.NoError(t, b.Run(stopCh))
+
+	b.ProcessEvents(&auditinternal.Event{
+		Level: auditinternal.LevelRequestResponse,
+		Verb:  "create",
+	}, &auditinternal.Event{
+		Level: auditinternal.LevelRequest,
+		Verb:  "get",
+	})
+	require.Len(t, r.events["writes"], 1, "only events selected by the sink policy must be sent to the sink")
+	require.Len(t, r.events["metadata"], 2)
+	for _, ev := range r.events["metadata"] {
+		assert.Equal(t, auditinternal.LevelMetadata, ev.Level, "events must be sent at most at the sink level")
+	}
+
+	// An invalid file is rejected and the sinks stay in effect.
+	require.NoError(t, os.WriteFile(path, []byte("kind: AuditSinkList\napiVersion: audit.k8s.io/v1\nitems:\n- metadata:\n    name: invalid\n"), 0600))
+	assert.Error(t, b.load())
+	assert.Len(t, b.sinks, 2)
+
+	// Changed sinks are replaced and removed sinks are shut down.
+	writes := b.sinks["writes"]
+	require.NoError(t, os.WriteFile(path, []byte(`
+apiVersion: audit.k8s.io/v1
+kind: AuditSink
+metadata:
+  name: writes
+spec:
+  policy:
+    level: Metadata
+  webhook:
+    clientConfig:
+      url: https://writes.example.com
+`), 0600))
+	require.NoError(t, b.load())
+	require.Len(t, b.sinks, 1)
+	assert.NotSame(t, writes, b.sinks["writes"], "changed sinks must be replaced")
+	assert.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.shutdown["metadata"] && r.shutdown["writes"]
+	}, wait.ForeverTestTimeout, 10*time.Millisecond, "replaced and removed sinks must be shut down")
+}
+
+func auditSinkObject(name string, level auditv1.Level) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": auditv1.SchemeGroupVersion.String(),
+		"kind":       "AuditSink",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"policy": map[string]interface{}{"level": string(level)},
+			"webhook": map[string]interface{}{
+				"clientConfig": map[string]interface{}{"url": "https://" + name + ".example.com"},
+			},
+		},
+	}}
+}
+
+func TestAuditSinkObjects(t *testing.T) {
+	invalid := auditSinkObject("invalid", auditv1.LevelMetadata)
+	unstructured.RemoveNestedField(invalid.Object, "spec", "webhook")
+	client := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{AuditSinksResource: "AuditSinkList"},
+		auditSinkObject("metadata", auditv1.LevelMetadata), invalid)
+
+	sinksBackend, err := NewBackend(client, Config{})
+	require.NoError(t, err)
+	b := sinksBackend.(*backend)
+	r := newRecorder()
+	b.newSinkBackend = r.newSinkBackend
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	require.NoError(t, b.Run(stopCh))
+
+	sinkNames := func() []string {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+		var names []string
+		for name := range b.sinks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	assert.Eventually(t, func() bool {
+		return reflect.DeepEqual(sinkNames(), []string{"metadata"})
+	}, wait.ForeverTestTimeout, 10*time.Millisecond, "valid sinks must be added and invalid ones ignored")
+
+	b.ProcessEvents(&auditinternal.Event{Level: auditinternal.LevelRequest, Verb: "get"})
+	r.mu.Lock()
+	assert.Len(t, r.events["metadata"], 1)
+	r.mu.Unlock()
+
+	sinks := client.Resource(AuditSinksResource)
+	_, err = sinks.Create(context.Background(), auditSinkObject("writes", auditv1.LevelRequestResponse), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, sinks.Delete(context.Background(), "metadata", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		return reflect.DeepEqual(sinkNames(), []string{"writes"})
+	}, wait.ForeverTestTimeout, 10*time.Millisecond, "sinks must follow the objects")
+	assert.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.shutdown["metadata"]
+	}, wait.ForeverTestTimeout, 10*time.Millisecond, "removed sinks must be shut down")
+}
+
+func TestWebhookSink(t *testing.T) {
+	received := make(chan auditv1.EventList, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var list auditv1.EventList
+		if err := json.NewDecoder(req.Body).Decode(&list); err != nil {
+			t.Errorf("Failed to decode events: %v", err)
+		}
+		received <- list
+	}))
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	var sink auditv1.AuditSink
+	sink.APIVersion, sink.Kind = auditv1.SchemeGroupVersion.String(), "AuditSink"
+	sink.Name = "siem"
+	sink.Spec.Policy.Level = auditv1.LevelMetadata
+	sink.Spec.Webhook.ClientConfig = auditv1.WebhookClientConfig{URL: server.URL, CABundle: caBundle}
+	content, err := json.Marshal(sink)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "sinks.json")
+	require.NoError(t, os.WriteFile(path, content, 0600))
+
+	b, err := NewFileBackend(path, Config{
+		ReloadInterval: time.Hour,
+		BatchConfig: pluginbuffered.BatchConfig{
+			BufferSize:   10,
+			MaxBatchSize: 1,
+			MaxBatchWait: time.Second,
+		},
+		RetryConfig:  pluginwebhook.DefaultRetryConfig(10 * time.Millisecond),
+		GroupVersion: auditv1.SchemeGroupVersion,
+	})
+	require.NoError(t, err)
+	stopCh := make(chan struct{})
+	require.NoError(t, b.Run(stopCh))
+	defer func() {
+		close(stopCh)
+		b.Shutdown()
+	}()
+
+	b.ProcessEvents(&auditinternal.Event{Level: auditinternal.LevelMetadata, AuditID: "1"})
+	select {
+	case list := <-received:
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "1", string(list.Items[0].AuditID))
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("Events should be sent to the webhook of the sink")
+	}
+}
@@ -22,10 +22,12 @@ import (
 )
 
 var _ audit.Backend = &Backend{}
+var _ audit.HealthChecker = &Backend{}
 
 // Backend is a fake audit backend for testing purposes.
 type Backend struct {
-	OnRequest func(events []*auditinternal.Event)
+	OnRequest     func(events []*auditinternal.Event)
+	OnHealthCheck func() error
 }
 
 // Run does nothing.
@@ -46,6 +48,14 @@ func (b *Backend) ProcessEvents(ev ...*auditinternal.Event) bool {
 	return true
 }
 
+// HealthCheck calls a callback, if present.
+func (b *Backend) HealthCheck() error {
+	if b.OnHealthCheck != nil {
+		return b.OnHealthCheck()
+	}
+	return nil
+}
+
 func (b *Backend) String() string {
 	return ""
 }
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kafka implements the audit.Backend interface by publishing events to
+// a Kafka topic, one JSON encoded event per message.
+//
+// Events are published through a Producer, which owns the connection to the
+// brokers. NewProducer returns a producer connecting over TLS and authenticating
+// with SASL if configured; callers may provide their own. Wrap the backend with
+// the buffered backend to publish events in batches.
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/apis/audit/install"
+	"k8s.io/apiserver/pkg/audit"
+)
+
+const (
+	// PluginName is the name of this plugin, to be used in help and logs.
+	PluginName = "kafka"
+)
+
+func init() {
+	install.Install(audit.Scheme)
+}
+
+// PartitionKey is the event attribute the messages of a topic are partitioned by.
+type PartitionKey string
+
+const (
+	// PartitionKeyAuditID partitions messages by the audit ID of their event, so
+	// the events of the stages of a request are in the same partition.
+	PartitionKeyAuditID PartitionKey = "auditID"
+	// PartitionKeyUser partitions messages by the user name of their event.
+	PartitionKeyUser PartitionKey = "user"
+	// PartitionKeyNamespace partitions messages by the namespace of their event.
+	// Events of non-namespaced requests have no key.
+	PartitionKeyNamespace PartitionKey = "namespace"
+)
+
+// AllowedPartitionKeys are the partition keys known by the kafka backend.
+var AllowedPartitionKeys = []string{
+	string(PartitionKeyAuditID),
+	string(PartitionKeyUser),
+	string(PartitionKeyNamespace),
+}
+
+// Message is a Kafka message holding an audit event.
+type Message struct {
+	// Topic is the topic the message is published to.
+	Topic string
+	// Key is the partitioning key of the message, or nil if it has none.
+	Key []byte
+	// Value is the JSON encoded audit event.
+	Value []byte
+}
+
+// Producer publishes messages to Kafka.
+type Producer interface {
+	// Produce publishes the messages and returns once they are acknowledged.
+	Produce(ctx context.Context, messages []Message) error
+	// Close flushes pending messages and closes the producer.
+	Close() error
+}
+
+// Config configures the kafka backend.
+type Config struct {
+	// Topic is the topic events are published to.
+	Topic string
+	// PartitionKey is the event attribute messages are partitioned by.
+	PartitionKey PartitionKey
+	// GroupVersion is the audit API version events are encoded in.
+	GroupVersion schema.GroupVersion
+}
+
+type backend struct {
+	producer     Producer
+	topic        string
+	partitionKey PartitionKey
+	encoder      runtime.Encoder
+}
+
+var _ audit.Backend = &backend{}
+
+// NewBackend returns an audit backend that publishes events to a Kafka topic
+// through the given producer. The backend closes the producer on shutdown.
+func NewBackend(producer Producer, config Config) (audit.Backend, error) {
+	if config.Topic == "" {
+		return nil, fmt.Errorf("topic not specified")
+	}
+	switch config.PartitionKey {
+	case PartitionKeyAuditID, PartitionKeyUser, PartitionKeyNamespace:
+	default:
+		return nil, fmt.Errorf("partition key %q is not in list of known partition keys (%s)",
+			config.PartitionKey, strings.Join(AllowedPartitionKeys, ","))
+	}
+	return &backend{
+		producer:     producer,
+		topic:        config.Topic,
+		partitionKey: config.PartitionKey,
+		encoder:      audit.Codecs.LegacyCodec(config.GroupVersion),
+	}, nil
+}
+
+func (b *backend) Run(stopCh <-chan struct{}) error {
+	return nil
+}
+
+func (b *backend) Shutdown() {
+	if err := b.producer.Close(); err != nil {
+		audit.HandlePluginError(PluginName, fmt.Errorf("failed to close producer: %v", err))
+	}
+}
+
+func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
+	messages := make([]Message, 0, len(events))
+	encoded := make([]*auditinternal.Event, 0, len(events))
+	success := true
+	for _, ev := range events {
+		value, err := runtime.Encode(b.encoder, ev)
+		if err != nil {
+			audit.HandlePluginError(PluginName, err, ev)
+			success = false
+			continue
+		}
+		messages = append(messages, Message{
+			Topic: b.topic,
+			Key:   b.key(ev),
+			Value: value,
+		})
+		encoded = append(encoded, ev)
+	}
+	if len(messages) == 0 {
+		return success
+	}
+	if err := b.producer.Produce(context.Background(), messages); err != nil {
+		audit.HandlePluginError(PluginName, err, encoded...)
+		return false
+	}
+	return success
+}
+
+// key returns the partitioning key of the event, or nil if it has none.
+func (b *backend) key(ev *auditinternal.Event) []byte {
+	var key string
+	switch b.partitionKey {
+	case PartitionKeyAuditID:
+		key = string(ev.AuditID)
+	case PartitionKeyUser:
+		key = ev.User.Username
+	case PartitionKeyNamespace:
+		if ev.ObjectRef != nil {
+			key = ev.ObjectRef.Namespace
+		}
+	}
+	if key == "" {
+		return nil
+	}
+	return []byte(key)
+}
+
+func (b *backend) String() string {
+	return PluginName
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+)
+
+type fakeProducer struct {
+	messages []Message
+	err      error
+	closed   bool
+}
+
+func (p *fakeProducer) Produce(_ context.Context, messages []Message) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, messages...)
+	return nil
+}
+
+func (p *fakeProducer) Close() error {
+	p.closed = true
+	return nil
+}
+
+var events = []*auditinternal.Event{{
+	AuditID:   types.UID("1"),
+	Stage:     auditinternal.StageResponseComplete,
+	User:      authnv1.UserInfo{Username: "admin"},
+	ObjectRef: &auditinternal.ObjectReference{Resource: "pods", Namespace: "default"},
+}, {
+	AuditID: types.UID("2"),
+	Stage:   auditinternal.StageResponseComplete,
+}}
+
+func TestProcessEvents(t *testing.T) {
+	for key, expected := range map[PartitionKey][][]byte{
+		PartitionKeyAuditID:   {[]byte("1"), []byte("2")},
+		PartitionKeyUser:      {[]byte("admin"), nil},
+		PartitionKeyNamespace: {[]byte("default"), nil},
+	} {
+		producer := &fakeProducer{}
+		b, err := NewBackend(producer, Config{Topic: "audit", PartitionKey: key, GroupVersion: auditv1.SchemeGroupVersion})
+		require.NoError(t, err)
+
+		require.True(t, b.ProcessEvents(events...), "partition key %s", key)
+		require.Len(t, producer.messages, len(events), "partition key %s", key)
+		for i, m := range producer.messages {
+			assert.Equal(t, "audit", m.Topic, "partition key %s", key)
+			assert.Equal(t, expected[i], m.Key, "partition key %s, event %d", key, i)
+
+			decoded := &auditinternal.Event{}
+			require.NoError(t, runtime.DecodeInto(audit.Codecs.UniversalDecoder(auditv1.SchemeGroupVersion), m.Value, decoded))
+			assert.Equal(t, events[i].AuditID, decoded.AuditID)
+		}
+	}
+}
+
+func TestProcessEventsProducerError(t *testing.T) {
+	producer := &fakeProducer{err: errors.New("broker unavailable")}
+	b, err := NewBackend(producer, Config{Topic: "audit", PartitionKey: PartitionKeyAuditID, GroupVersion: auditv1.SchemeGroupVersion})
+	require.NoError(t, err)
+
+	assert.False(t, b.ProcessEvents(events...))
+}
+
+func TestShutdownClosesProducer(t *testing.T) {
+	producer := &fakeProducer{}
+	b, err := NewBackend(producer, Config{Topic: "audit", PartitionKey: PartitionKeyAuditID, GroupVersion: auditv1.SchemeGroupVersion})
+	require.NoError(t, err)
+
+	b.Shutdown()
+	assert.True(t, producer.closed)
+}
+
+func TestNewBackendInvalidConfig(t *testing.T) {
+	_, err := NewBackend(&fakeProducer{}, Config{PartitionKey: PartitionKeyAuditID, GroupVersion: auditv1.SchemeGroupVersion})
+	assert.Error(t, err, "missing topic")
+	_, err = NewBackend(&fakeProducer{}, Config{Topic: "audit", PartitionKey: "verb", GroupVersion: auditv1.SchemeGroupVersion})
+	assert.Error(t, err, "unknown partition key")
+}
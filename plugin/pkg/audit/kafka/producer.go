@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+const (
+	// SASLMechanismPlain authenticates with a user name and password in clear
+	// text, which should only be used over TLS.
+	SASLMechanismPlain = "PLAIN"
+	// SASLMechanismScramSHA256 authenticates with a user name and password
+	// through a SCRAM-SHA-256 challenge.
+	SASLMechanismScramSHA256 = "SCRAM-SHA-256"
+	// SASLMechanismScramSHA512 authenticates with a user name and password
+	// through a SCRAM-SHA-512 challenge.
+	SASLMechanismScramSHA512 = "SCRAM-SHA-512"
+
+	// DefaultTimeout is the default timeout of connecting to a broker and of
+	// publishing a batch of messages, including retries.
+	DefaultTimeout = 10 * time.Second
+
+	// batchTimeout bounds the time the producer waits for more messages of a
+	// partition before publishing them. Events are already batched by the
+	// buffered backend, so it is kept short.
+	batchTimeout = 10 * time.Millisecond
+)
+
+// AllowedSASLMechanisms are the SASL mechanisms known by the producer.
+var AllowedSASLMechanisms = []string{
+	SASLMechanismPlain,
+	SASLMechanismScramSHA256,
+	SASLMechanismScramSHA512,
+}
+
+// ProducerConfig configures the connection of a producer to the Kafka brokers.
+type ProducerConfig struct {
+	// Brokers are the host:port addresses of the brokers the cluster metadata
+	// is bootstrapped from.
+	Brokers []string
+	// TLSConfig, if set, enables TLS.
+	TLSConfig *tls.Config
+	// SASLMechanism, if set, is the SASL mechanism authenticating the producer
+	// as Username with Password.
+	SASLMechanism string
+	Username      string
+	Password      string
+	// Dial, if set, is used to connect to the brokers.
+	Dial utilnet.DialFunc
+	// Timeout is the timeout of connecting to a broker and of publishing a
+	// batch of messages, including retries. Defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+type producer struct {
+	writer    *kafkago.Writer
+	transport *kafkago.Transport
+	timeout   time.Duration
+}
+
+var _ Producer = &producer{}
+
+// NewProducer returns a producer publishing messages to the Kafka cluster of the
+// given brokers. Messages are acknowledged once all in-sync replicas stored them.
+func NewProducer(config ProducerConfig) (Producer, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("brokers not specified")
+	}
+	mechanism, err := saslMechanism(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	transport := &kafkago.Transport{
+		DialTimeout: config.Timeout,
+		TLS:         config.TLSConfig,
+		SASL:        mechanism,
+		ClientID:    "kube-apiserver-audit",
+	}
+	if config.Dial != nil {
+		transport.Dial = config.Dial
+	}
+	return &producer{
+		writer: &kafkago.Writer{
+			Addr: kafkago.TCP(config.Brokers...),
+			// Partition by the key of the messages, the same way as sarama and
+			// librdkafka, and spread messages without a key evenly.
+			Balancer:     &kafkago.Hash{},
+			BatchTimeout: batchTimeout,
+			ReadTimeout:  config.Timeout,
+			WriteTimeout: config.Timeout,
+			RequiredAcks: kafkago.RequireAll,
+			Transport:    transport,
+		},
+		transport: transport,
+		timeout:   config.Timeout,
+	}, nil
+}
+
+// saslMechanism returns the SASL mechanism of the config, or nil if SASL is not enabled.
+func saslMechanism(config ProducerConfig) (sasl.Mechanism, error) {
+	if config.SASLMechanism == "" {
+		return nil, nil
+	}
+	if config.Username == "" {
+		return nil, fmt.Errorf("SASL mechanism %s requires a user name", config.SASLMechanism)
+	}
+	switch config.SASLMechanism {
+	case SASLMechanismPlain:
+		return plain.Mechanism{Username: config.Username, Password: config.Password}, nil
+	case SASLMechanismScramSHA256:
+		return scram.Mechanism(scram.SHA256, config.Username, config.Password)
+	case SASLMechanismScramSHA512:
+		return scram.Mechanism(scram.SHA512, config.Username, config.Password)
+	default:
+		return nil, fmt.Errorf("SASL mechanism %q is not in list of known SASL mechanisms (%s)",
+			config.SASLMechanism, strings.Join(AllowedSASLMechanisms, ","))
+	}
+}
+
+func (p *producer) Produce(ctx context.Context, messages []Message) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	kafkaMessages := make([]kafkago.Message, len(messages))
+	for i, m := range messages {
+		kafkaMessages[i] = kafkago.Message{
+			Topic: m.Topic,
+			Key:   m.Key,
+			Value: m.Value,
+		}
+	}
+	if err := p.writer.WriteMessages(ctx, kafkaMessages...); err != nil {
+		return fmt.Errorf("failed to publish %d messages: %v", len(messages), err)
+	}
+	return nil
+}
+
+func (p *producer) Close() error {
+	err := p.writer.Close()
+	p.transport.CloseIdleConnections()
+	return err
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProducer(t *testing.T) {
+	for _, mechanism := range append([]string{""}, AllowedSASLMechanisms...) {
+		p, err := NewProducer(ProducerConfig{
+			Brokers:       []string{"localhost:9092"},
+			SASLMechanism: mechanism,
+			Username:      "apiserver",
+			Password:      "secret",
+		})
+		require.NoError(t, err, "SASL mechanism %q", mechanism)
+		assert.NoError(t, p.Close(), "SASL mechanism %q", mechanism)
+	}
+}
+
+func TestNewProducerInvalidConfig(t *testing.T) {
+	for name, config := range map[string]ProducerConfig{
+		"no brokers":             {},
+		"unknown SASL mechanism": {Brokers: []string{"localhost:9092"}, SASLMechanism: "GSSAPI", Username: "apiserver"},
+		"SASL without user name": {Brokers: []string{"localhost:9092"}, SASLMechanism: SASLMechanismPlain},
+	} {
+		_, err := NewProducer(config)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestProduceUnreachableBroker(t *testing.T) {
+	// The broker closes every connection, so the cluster metadata is never fetched.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	var dials int32
+	p, err := NewProducer(ProducerConfig{
+		Brokers: []string{l.Addr().String()},
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		},
+		Timeout: 200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer p.Close()
+
+	start := time.Now()
+	err = p.Produce(context.Background(), []Message{{Topic: "audit", Value: []byte("{}")}})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second, "publishing must be bounded by the timeout")
+	assert.NotZero(t, atomic.LoadInt32(&dials), "brokers must be dialed with the configured dialer")
+}
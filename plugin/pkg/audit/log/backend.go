@@ -32,6 +32,8 @@ const (
 	FormatLegacy = "legacy"
 	// FormatJson saves event in structured json format.
 	FormatJson = "json"
+	// FormatLogger writes events through the logger of the component, see NewLoggerBackend.
+	FormatLogger = "logger"
 
 	// PluginName is the name of this plugin, to be used in help and logs.
 	PluginName = "log"
@@ -41,12 +43,15 @@ const (
 var AllowedFormats = []string{
 	FormatLegacy,
 	FormatJson,
+	FormatLogger,
 }
 
 type backend struct {
 	out     io.Writer
 	format  string
 	encoder runtime.Encoder
+	// chain, if set, links the events written in a hash chain.
+	chain *hashChain
 }
 
 var _ audit.Backend = &backend{}
@@ -59,6 +64,24 @@ func NewBackend(out io.Writer, format string, groupVersion schema.GroupVersion)
 	}
 }
 
+// NewChainedBackend returns a log backend which links the events written in a
+// hash chain, annotating each event with the hash of the previous one. Only the
+// json format is supported, since the legacy format does not include annotations.
+func NewChainedBackend(out io.Writer, format string, groupVersion schema.GroupVersion, config ChainConfig) (audit.Backend, error) {
+	if format != FormatJson {
+		return nil, fmt.Errorf("log format %q does not support hash chains, use %q", format, FormatJson)
+	}
+	if config.CheckpointInterval <= 0 {
+		config.CheckpointInterval = DefaultChainCheckpointInterval
+	}
+	return &backend{
+		out:     out,
+		format:  format,
+		encoder: audit.Codecs.LegacyCodec(groupVersion),
+		chain:   newHashChain(config),
+	}, nil
+}
+
 func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
 	success := true
 	for _, ev := range events {
@@ -68,6 +91,9 @@ func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
 }
 
 func (b *backend) logEvent(ev *auditinternal.Event) bool {
+	if b.chain != nil {
+		return b.logChainedEvent(ev)
+	}
 	line := ""
 	switch b.format {
 	case FormatLegacy:
@@ -91,6 +117,28 @@ func (b *backend) logEvent(ev *auditinternal.Event) bool {
 	return true
 }
 
+func (b *backend) logChainedEvent(ev *auditinternal.Event) bool {
+	b.chain.mu.Lock()
+	defer b.chain.mu.Unlock()
+
+	linked, err := b.chain.link(ev)
+	if err != nil {
+		audit.HandlePluginError(PluginName, err, ev)
+		return false
+	}
+	bs, err := runtime.Encode(b.encoder, linked)
+	if err != nil {
+		audit.HandlePluginError(PluginName, err, ev)
+		return false
+	}
+	if _, err := b.out.Write(bs); err != nil {
+		audit.HandlePluginError(PluginName, err, ev)
+		return false
+	}
+	b.chain.written(linked, bs)
+	return true
+}
+
 func (b *backend) Run(stopCh <-chan struct{}) error {
 	return nil
 }
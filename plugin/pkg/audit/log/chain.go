@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/utils/clock"
+)
+
+// Annotations linking the events of a hash chain.
+const (
+	// ChainSequenceAnnotationKey is the position of the event in the hash
+	// chain. The chain starts at 0 each time the backend starts.
+	ChainSequenceAnnotationKey = "audit.k8s.io/chain-sequence"
+	// ChainPreviousHashAnnotationKey is the hex-encoded SHA-256 hash of the
+	// line of the previous event of the chain, including the trailing newline.
+	// It is empty for the first event.
+	ChainPreviousHashAnnotationKey = "audit.k8s.io/chain-previous-hash"
+	// ChainCheckpointAnnotationKey is the base64-encoded signature of
+	// "<sequence>:<previous hash>" of the event, set on checkpoint events.
+	ChainCheckpointAnnotationKey = "audit.k8s.io/chain-checkpoint"
+)
+
+// DefaultChainCheckpointInterval is the default minimum time between
+// checkpoints of the hash chain.
+const DefaultChainCheckpointInterval = time.Minute
+
+// ChainConfig configures the hash chain of the log backend.
+type ChainConfig struct {
+	// SigningKey, if set, signs checkpoints of the chain. Ed25519 keys sign
+	// the checkpoint itself, other keys its SHA-256 digest.
+	SigningKey crypto.Signer
+	// CheckpointInterval is the minimum time between checkpoints. The first
+	// event of the chain is always a checkpoint.
+	CheckpointInterval time.Duration
+}
+
+// hashChain links each event written to the hash of the previous one, so that
+// modified or removed events can be detected. Signed checkpoints allow
+// detecting the chain being rewritten from some event on.
+type hashChain struct {
+	config ChainConfig
+	clock  clock.PassiveClock
+
+	// mu serializes writing the events of the chain.
+	mu       sync.Mutex
+	sequence uint64
+	// previousHash is the hash of the last event written.
+	previousHash string
+	// checkpointed is the time of the last checkpoint, zero if none.
+	checkpointed time.Time
+}
+
+func newHashChain(config ChainConfig) *hashChain {
+	return &hashChain{
+		config: config,
+		clock:  clock.RealClock{},
+	}
+}
+
+// link returns a copy of the event annotated as the next event of the chain.
+func (c *hashChain) link(ev *auditinternal.Event) (*auditinternal.Event, error) {
+	annotations := make(map[string]string, len(ev.Annotations)+3)
+	for k, v := range ev.Annotations {
+		annotations[k] = v
+	}
+	sequence := strconv.FormatUint(c.sequence, 10)
+	annotations[ChainSequenceAnnotationKey] = sequence
+	annotations[ChainPreviousHashAnnotationKey] = c.previousHash
+	if c.checkpointDue() {
+		signature, err := sign(c.config.SigningKey, []byte(sequence+":"+c.previousHash))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign audit hash chain checkpoint: %v", err)
+		}
+		annotations[ChainCheckpointAnnotationKey] = base64.StdEncoding.EncodeToString(signature)
+	}
+	e := *ev
+	e.Annotations = annotations
+	return &e, nil
+}
+
+// written advances the chain past the event linked, once its line was written.
+func (c *hashChain) written(ev *auditinternal.Event, line []byte) {
+	if _, ok := ev.Annotations[ChainCheckpointAnnotationKey]; ok {
+		c.checkpointed = c.clock.Now()
+	}
+	hash := sha256.Sum256(line)
+	c.previousHash = hex.EncodeToString(hash[:])
+	c.sequence++
+}
+
+func (c *hashChain) checkpointDue() bool {
+	if c.config.SigningKey == nil {
+		return false
+	}
+	return c.checkpointed.IsZero() || c.clock.Since(c.checkpointed) >= c.config.CheckpointInterval
+}
+
+func sign(key crypto.Signer, message []byte) ([]byte, error) {
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		return key.Sign(rand.Reader, message, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(message)
+	return key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestChainedBackend(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	b, err := NewChainedBackend(buf, FormatJson, auditv1.SchemeGroupVersion, ChainConfig{
+		SigningKey:         private,
+		CheckpointInterval: time.Minute,
+	})
+	require.NoError(t, err)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	b.(*backend).chain.clock = fakeClock
+
+	original := &auditinternal.Event{AuditID: "0", Annotations: map[string]string{"key": "value"}}
+	require.True(t, b.ProcessEvents(original, &auditinternal.Event{AuditID: "1"}))
+	fakeClock.Step(time.Minute)
+	require.True(t, b.ProcessEvents(&auditinternal.Event{AuditID: "2"}, &auditinternal.Event{AuditID: "3"}))
+	assert.Len(t, original.Annotations, 1, "events processed must not be modified")
+
+	lines := bytes.SplitAfter(buf.Bytes(), []byte("\n"))
+	require.Len(t, lines, 5)
+	assert.Empty(t, lines[4])
+	previousHash := ""
+	var checkpoints []types.UID
+	for i, line := range lines[:4] {
+		ev := &auditinternal.Event{}
+		require.NoError(t, runtime.DecodeInto(audit.Codecs.UniversalDecoder(auditv1.SchemeGroupVersion), line, ev))
+		assert.Equal(t, types.UID(strconv.Itoa(i)), ev.AuditID)
+		assert.Equal(t, strconv.Itoa(i), ev.Annotations[ChainSequenceAnnotationKey])
+		if i == 0 {
+			assert.Equal(t, "value", ev.Annotations["key"], "annotations of the event must be kept")
+		}
+		assert.Equal(t, previousHash, ev.Annotations[ChainPreviousHashAnnotationKey], "event %d must be linked to the previous one", i)
+		if checkpoint, ok := ev.Annotations[ChainCheckpointAnnotationKey]; ok {
+			signature, err := base64.StdEncoding.DecodeString(checkpoint)
+			require.NoError(t, err)
+			message := []byte(strconv.Itoa(i) + ":" + previousHash)
+			assert.True(t, ed25519.Verify(public, message, signature), "checkpoint of event %d must verify", i)
+			checkpoints = append(checkpoints, ev.AuditID)
+		}
+		hash := sha256.Sum256(line)
+		previousHash = hex.EncodeToString(hash[:])
+	}
+	assert.Equal(t, []types.UID{"0", "2"}, checkpoints)
+}
+
+func TestChainedBackendUnsigned(t *testing.T) {
+	buf := &bytes.Buffer{}
+	b, err := NewChainedBackend(buf, FormatJson, auditv1.SchemeGroupVersion, ChainConfig{})
+	require.NoError(t, err)
+	require.True(t, b.ProcessEvents(&auditinternal.Event{AuditID: "0"}))
+
+	ev := &auditinternal.Event{}
+	require.NoError(t, runtime.DecodeInto(audit.Codecs.UniversalDecoder(auditv1.SchemeGroupVersion), buf.Bytes(), ev))
+	assert.Equal(t, "0", ev.Annotations[ChainSequenceAnnotationKey])
+	assert.NotContains(t, ev.Annotations, ChainCheckpointAnnotationKey)
+}
+
+func TestChainedBackendLegacyFormat(t *testing.T) {
+	_, err := NewChainedBackend(&bytes.Buffer{}, FormatLegacy, auditv1.SchemeGroupVersion, ChainConfig{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+// encryptionContext is the authenticated data of encrypted log lines.
+var encryptionContext = value.DefaultContext("audit.k8s.io/events")
+
+type encryptingWriter struct {
+	out         io.Writer
+	transformer value.Transformer
+}
+
+// NewEncryptingWriter returns a writer which encrypts each write to out with
+// the transformer, writing the base64-encoded ciphertext as a line. The log
+// backend writes each event with a single write, so that each line of the log
+// holds an encrypted event, to be decrypted with DecryptLine.
+func NewEncryptingWriter(out io.Writer, transformer value.Transformer) io.Writer {
+	return &encryptingWriter{out: out, transformer: transformer}
+}
+
+func (w *encryptingWriter) Write(p []byte) (int, error) {
+	ciphertext, err := w.transformer.TransformToStorage(context.Background(), p, encryptionContext)
+	if err != nil {
+		return 0, err
+	}
+	line := make([]byte, base64.StdEncoding.EncodedLen(len(ciphertext))+1)
+	base64.StdEncoding.Encode(line, ciphertext)
+	line[len(line)-1] = '\n'
+	if _, err := w.out.Write(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// DecryptLine decrypts a line of a log written by a writer returned by
+// NewEncryptingWriter, returning the data written, including its trailing
+// newline.
+func DecryptLine(ctx context.Context, transformer value.Transformer, line []byte) ([]byte, error) {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	ciphertext := make([]byte, base64.StdEncoding.DecodedLen(len(line)))
+	n, err := base64.StdEncoding.Decode(ciphertext, line)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := transformer.TransformFromStorage(ctx, ciphertext[:n], encryptionContext)
+	return data, err
+}
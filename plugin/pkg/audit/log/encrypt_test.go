@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
+)
+
+func TestEncryptingWriter(t *testing.T) {
+	block, err := aes.NewCipher(bytes.Repeat([]byte("k"), 32))
+	require.NoError(t, err)
+	transformer := aestransformer.NewGCMTransformer(block)
+
+	encrypted, plain := &bytes.Buffer{}, &bytes.Buffer{}
+	events := []*auditinternal.Event{
+		{AuditID: "0", RequestURI: "/api/v1/namespaces/default/secrets"},
+		{AuditID: "1", RequestURI: "/api/v1/namespaces/default/secrets"},
+	}
+	require.True(t, NewBackend(NewEncryptingWriter(encrypted, transformer), FormatJson, auditv1.SchemeGroupVersion).ProcessEvents(events...))
+	require.True(t, NewBackend(plain, FormatJson, auditv1.SchemeGroupVersion).ProcessEvents(events...))
+	assert.NotContains(t, encrypted.String(), "secrets", "log must not be plaintext")
+
+	lines := bytes.SplitAfter(encrypted.Bytes(), []byte("\n"))
+	require.Len(t, lines, 3, "each event must be encrypted in a line")
+	decrypted := &bytes.Buffer{}
+	for _, line := range lines[:2] {
+		data, err := DecryptLine(context.Background(), transformer, line)
+		require.NoError(t, err)
+		decrypted.Write(data)
+	}
+	assert.Equal(t, plain.String(), decrypted.String())
+
+	_, err = DecryptLine(context.Background(), transformer, []byte("not encrypted\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/klog/v2"
+)
+
+// LoggerName is the name of the logger events are written through.
+const LoggerName = "audit"
+
+type loggerBackend struct {
+	logger  klog.Logger
+	encoder runtime.Encoder
+}
+
+var _ audit.Backend = &loggerBackend{}
+
+// NewLoggerBackend returns a log backend which writes events through the given
+// logger, typically the logger of the component, as the "event" value of one
+// structured log entry each. With the json logging format of the component,
+// each event is written as a JSON line to standard out, so that containerized
+// control planes can collect audit events with the container logs.
+func NewLoggerBackend(logger klog.Logger, groupVersion schema.GroupVersion) audit.Backend {
+	return &loggerBackend{
+		logger:  logger.WithName(LoggerName),
+		encoder: audit.Codecs.LegacyCodec(groupVersion),
+	}
+}
+
+func (b *loggerBackend) ProcessEvents(events ...*auditinternal.Event) bool {
+	success := true
+	for _, ev := range events {
+		success = b.logEvent(ev) && success
+	}
+	return success
+}
+
+func (b *loggerBackend) logEvent(ev *auditinternal.Event) bool {
+	bs, err := runtime.Encode(b.encoder, ev)
+	if err != nil {
+		audit.HandlePluginError(PluginName, err, ev)
+		return false
+	}
+	// Log the event as a generic JSON object rather than the API type, which
+	// loggers would format by its String method.
+	var event map[string]interface{}
+	if err := json.Unmarshal(bs, &event); err != nil {
+		audit.HandlePluginError(PluginName, err, ev)
+		return false
+	}
+	b.logger.Info("Audit event", "event", event)
+	return true
+}
+
+func (b *loggerBackend) Run(stopCh <-chan struct{}) error {
+	return nil
+}
+
+func (b *loggerBackend) Shutdown() {
+	// Nothing to do here.
+}
+
+func (b *loggerBackend) String() string {
+	return PluginName
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func TestLoggerBackend(t *testing.T) {
+	var lines []string
+	logger := funcr.NewJSON(func(obj string) {
+		lines = append(lines, obj)
+	}, funcr.Options{})
+
+	b := NewLoggerBackend(logger, auditv1.SchemeGroupVersion)
+	require.True(t, b.ProcessEvents(&auditinternal.Event{
+		AuditID: types.UID("1"),
+		Stage:   auditinternal.StageResponseComplete,
+		Verb:    "get",
+		ObjectRef: &auditinternal.ObjectReference{
+			Resource:  "pods",
+			Namespace: "default",
+		},
+	}))
+	require.Len(t, lines, 1)
+
+	var entry struct {
+		Logger string `json:"logger"`
+		Msg    string `json:"msg"`
+		Event  struct {
+			Kind       string `json:"kind"`
+			APIVersion string `json:"apiVersion"`
+			AuditID    string `json:"auditID"`
+			Stage      string `json:"stage"`
+			ObjectRef  struct {
+				Namespace string `json:"namespace"`
+			} `json:"objectRef"`
+		} `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry), "entry must be a JSON line: %s", lines[0])
+	assert.Equal(t, LoggerName, entry.Logger)
+	assert.Equal(t, "Audit event", entry.Msg)
+	assert.Equal(t, "Event", entry.Event.Kind)
+	assert.Equal(t, "audit.k8s.io/v1", entry.Event.APIVersion)
+	assert.Equal(t, "1", entry.Event.AuditID)
+	assert.Equal(t, "ResponseComplete", entry.Event.Stage)
+	assert.Equal(t, "default", entry.Event.ObjectRef.Namespace)
+}
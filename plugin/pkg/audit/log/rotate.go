@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	megabyte = 1024 * 1024
+
+	// DefaultRotateMaxSize is the maximum size in megabytes of the log file
+	// before it gets rotated if RotateConfig.MaxSize is 0.
+	DefaultRotateMaxSize = 100
+)
+
+// RotateConfig configures the rotation of the log file.
+type RotateConfig struct {
+	// MaxSize is the maximum size in megabytes of the log file before it gets
+	// rotated, DefaultRotateMaxSize if 0.
+	MaxSize int
+	// MaxAge is the maximum number of days to retain rotated log files, based
+	// on the timestamp encoded in their names. Rotated files are not removed
+	// based on their age if 0.
+	MaxAge int
+	// MaxBackups is the maximum number of rotated log files to retain. All of
+	// them are retained if 0.
+	MaxBackups int
+	// Compress compresses the rotated log files using gzip.
+	Compress bool
+
+	// OnRotate, if set, is called with the path of the log file after each
+	// rotation. It must not block, as writes wait for it.
+	OnRotate func(path string)
+}
+
+type rotatingWriter struct {
+	logger   *lumberjack.Logger
+	onRotate func(path string)
+
+	// mu guards size, the size of the current log file, so that rotations are
+	// detected.
+	mu   sync.Mutex
+	size int64
+	max  int64
+}
+
+// NewRotatingWriter returns a writer appending to the log file at path, which
+// rotates the file once it would exceed the maximum size. Rotated files are
+// named after the log file with the time of the rotation, and are removed based
+// on their age and number. Each rotation is counted by the
+// apiserver_audit_log_rotations_total metric.
+func NewRotatingWriter(path string, config RotateConfig) (io.WriteCloser, error) {
+	maxSize := config.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultRotateMaxSize
+	}
+	return newRotatingWriter(path, config, int64(maxSize)*megabyte)
+}
+
+func newRotatingWriter(path string, config RotateConfig, max int64) (*rotatingWriter, error) {
+	var size int64
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		size = info.Size()
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to get the size of the audit log file: %w", err)
+	}
+	return &rotatingWriter{
+		logger: &lumberjack.Logger{
+			Filename:   path,
+			MaxAge:     config.MaxAge,
+			MaxBackups: config.MaxBackups,
+			MaxSize:    config.MaxSize,
+			Compress:   config.Compress,
+		},
+		onRotate: config.OnRotate,
+		size:     size,
+		max:      max,
+	}, nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Rotate before the logger would, so that rotations are observed. Writes
+	// exceeding the maximum size on their own are rejected by the logger.
+	if n := int64(len(p)); n <= w.max && w.size+n >= w.max {
+		if err := w.logger.Rotate(); err != nil {
+			return 0, err
+		}
+		w.size = 0
+		observeRotation()
+		if w.onRotate != nil {
+			w.onRotate(w.logger.Filename)
+		}
+	}
+	n, err := w.logger.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotatingWriter) Close() error {
+	return w.logger.Close()
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	require.NoError(t, os.WriteFile(path, []byte("existing\n"), 0600))
+
+	var rotated []string
+	w, err := newRotatingWriter(path, RotateConfig{OnRotate: func(path string) {
+		rotated = append(rotated, path)
+	}}, 20)
+	require.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("event 1\n"))
+	require.NoError(t, err)
+	assert.Empty(t, rotated, "the log file must not be rotated below the maximum size")
+
+	_, err = w.Write([]byte("event 2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{path}, rotated, "the log file must be rotated once it would exceed the maximum size")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "event 2\n", string(data))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "the rotated log file must be kept")
+}
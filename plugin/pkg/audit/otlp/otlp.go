@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package otlp implements the audit.Backend interface by exporting events as
+// OpenTelemetry log records over OTLP/gRPC, e.g. to the collector that receives
+// the traces of the apiserver. Wrap the backend with the buffered backend to
+// export events in batches.
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	collectorlogsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/apis/audit/install"
+	"k8s.io/apiserver/pkg/audit"
+)
+
+const (
+	// PluginName is the name of this plugin, to be used in help and logs.
+	PluginName = "otlp"
+
+	// ScopeName is the instrumentation scope of the exported log records.
+	ScopeName = "k8s.io/apiserver/plugin/pkg/audit/otlp"
+
+	// DefaultServiceName is the default service name of the exported log records.
+	DefaultServiceName = "apiserver"
+)
+
+// Attribute keys of the exported log records.
+const (
+	AttributeAuditID   = "k8s.audit.id"
+	AttributeStage     = "k8s.audit.stage"
+	AttributeLevel     = "k8s.audit.level"
+	AttributeVerb      = "k8s.audit.verb"
+	AttributeUser      = "k8s.audit.user"
+	AttributeNamespace = "k8s.namespace.name"
+)
+
+func init() {
+	install.Install(audit.Scheme)
+}
+
+// Config configures the otlp backend.
+type Config struct {
+	// ServiceName is the service.name resource attribute of the exported log
+	// records. Defaults to DefaultServiceName.
+	ServiceName string
+	// ServiceInstanceID is the service.instance.id resource attribute of the
+	// exported log records, e.g. the ID of the apiserver.
+	ServiceInstanceID string
+	// GroupVersion is the audit API version the bodies of the log records are encoded in.
+	GroupVersion schema.GroupVersion
+	// Timeout is the timeout of an export. No timeout is applied if zero.
+	Timeout time.Duration
+}
+
+type backend struct {
+	client   collectorlogsv1.LogsServiceClient
+	resource *resourcev1.Resource
+	encoder  runtime.Encoder
+	timeout  time.Duration
+}
+
+var _ audit.Backend = &backend{}
+
+// NewBackend returns an audit backend that exports events as OpenTelemetry log
+// records over the given gRPC connection. The connection is not closed by the backend.
+func NewBackend(conn grpc.ClientConnInterface, config Config) audit.Backend {
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	attributes := []*commonv1.KeyValue{stringAttribute("service.name", serviceName)}
+	if config.ServiceInstanceID != "" {
+		attributes = append(attributes, stringAttribute("service.instance.id", config.ServiceInstanceID))
+	}
+	return &backend{
+		client:   collectorlogsv1.NewLogsServiceClient(conn),
+		resource: &resourcev1.Resource{Attributes: attributes},
+		encoder:  audit.Codecs.LegacyCodec(config.GroupVersion),
+		timeout:  config.Timeout,
+	}
+}
+
+func (b *backend) Run(stopCh <-chan struct{}) error {
+	return nil
+}
+
+func (b *backend) Shutdown() {
+	// Nothing to do here.
+}
+
+func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
+	records := make([]*logsv1.LogRecord, 0, len(events))
+	encoded := make([]*auditinternal.Event, 0, len(events))
+	success := true
+	observed := uint64(time.Now().UnixNano())
+	for _, ev := range events {
+		record, err := b.logRecord(ev, observed)
+		if err != nil {
+			audit.HandlePluginError(PluginName, err, ev)
+			success = false
+			continue
+		}
+		records = append(records, record)
+		encoded = append(encoded, ev)
+	}
+	if len(records) == 0 {
+		return success
+	}
+	if err := b.export(records); err != nil {
+		audit.HandlePluginError(PluginName, err, encoded...)
+		return false
+	}
+	return success
+}
+
+func (b *backend) export(records []*logsv1.LogRecord) error {
+	ctx := context.Background()
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+	resp, err := b.client.Export(ctx, &collectorlogsv1.ExportLogsServiceRequest{
+		ResourceLogs: []*logsv1.ResourceLogs{{
+			Resource: b.resource,
+			ScopeLogs: []*logsv1.ScopeLogs{{
+				Scope:      &commonv1.InstrumentationScope{Name: ScopeName},
+				LogRecords: records,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if rejected := resp.GetPartialSuccess().GetRejectedLogRecords(); rejected > 0 {
+		return fmt.Errorf("%d of %d log records rejected: %s", rejected, len(records), resp.GetPartialSuccess().GetErrorMessage())
+	}
+	return nil
+}
+
+// logRecord returns the log record of the event. The body of the record is the
+// JSON encoded event.
+func (b *backend) logRecord(ev *auditinternal.Event, observed uint64) (*logsv1.LogRecord, error) {
+	body, err := runtime.Encode(b.encoder, ev)
+	if err != nil {
+		return nil, err
+	}
+	attributes := []*commonv1.KeyValue{
+		stringAttribute(AttributeAuditID, string(ev.AuditID)),
+		stringAttribute(AttributeStage, string(ev.Stage)),
+		stringAttribute(AttributeLevel, string(ev.Level)),
+		stringAttribute(AttributeVerb, ev.Verb),
+		stringAttribute(AttributeUser, ev.User.Username),
+	}
+	if ev.ObjectRef != nil && ev.ObjectRef.Namespace != "" {
+		attributes = append(attributes, stringAttribute(AttributeNamespace, ev.ObjectRef.Namespace))
+	}
+	var timestamp uint64
+	if !ev.StageTimestamp.IsZero() {
+		timestamp = uint64(ev.StageTimestamp.UnixNano())
+	}
+	return &logsv1.LogRecord{
+		TimeUnixNano:         timestamp,
+		ObservedTimeUnixNano: observed,
+		SeverityNumber:       logsv1.SeverityNumber_SEVERITY_NUMBER_INFO,
+		SeverityText:         "INFO",
+		Body:                 &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: string(body)}},
+		Attributes:           attributes,
+	}, nil
+}
+
+func stringAttribute(key, value string) *commonv1.KeyValue {
+	return &commonv1.KeyValue{
+		Key:   key,
+		Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func (b *backend) String() string {
+	return PluginName
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collectorlogsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+)
+
+type fakeCollector struct {
+	collectorlogsv1.UnimplementedLogsServiceServer
+
+	mu       sync.Mutex
+	requests []*collectorlogsv1.ExportLogsServiceRequest
+	rejected int64
+}
+
+func (c *fakeCollector) Export(_ context.Context, req *collectorlogsv1.ExportLogsServiceRequest) (*collectorlogsv1.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	resp := &collectorlogsv1.ExportLogsServiceResponse{}
+	if c.rejected > 0 {
+		resp.PartialSuccess = &collectorlogsv1.ExportLogsPartialSuccess{RejectedLogRecords: c.rejected, ErrorMessage: "too large"}
+	}
+	return resp, nil
+}
+
+func newTestBackend(t *testing.T, collector *fakeCollector) audit.Backend {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	collectorlogsv1.RegisterLogsServiceServer(server, collector)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return NewBackend(conn, Config{ServiceInstanceID: "apiserver-1", GroupVersion: auditv1.SchemeGroupVersion, Timeout: 10 * time.Second})
+}
+
+func TestProcessEvents(t *testing.T) {
+	collector := &fakeCollector{}
+	b := newTestBackend(t, collector)
+
+	stageTimestamp := metav1.NewMicroTime(time.Unix(1600000000, 0))
+	events := []*auditinternal.Event{{
+		AuditID:        types.UID("1"),
+		Stage:          auditinternal.StageResponseComplete,
+		Level:          auditinternal.LevelMetadata,
+		Verb:           "create",
+		User:           authnv1.UserInfo{Username: "admin"},
+		ObjectRef:      &auditinternal.ObjectReference{Resource: "pods", Namespace: "default"},
+		StageTimestamp: stageTimestamp,
+	}, {
+		AuditID: types.UID("2"),
+		Stage:   auditinternal.StageRequestReceived,
+	}}
+	require.True(t, b.ProcessEvents(events...))
+
+	require.Len(t, collector.requests, 1)
+	resourceLogs := collector.requests[0].ResourceLogs
+	require.Len(t, resourceLogs, 1)
+	resource := map[string]string{}
+	for _, kv := range resourceLogs[0].Resource.Attributes {
+		resource[kv.Key] = kv.Value.GetStringValue()
+	}
+	assert.Equal(t, map[string]string{"service.name": DefaultServiceName, "service.instance.id": "apiserver-1"}, resource)
+	require.Len(t, resourceLogs[0].ScopeLogs, 1)
+	assert.Equal(t, ScopeName, resourceLogs[0].ScopeLogs[0].Scope.Name)
+
+	records := resourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, records, 2)
+	assert.Equal(t, uint64(stageTimestamp.UnixNano()), records[0].TimeUnixNano)
+	assert.Equal(t, logsv1.SeverityNumber_SEVERITY_NUMBER_INFO, records[0].SeverityNumber)
+	attributes := map[string]string{}
+	for _, kv := range records[0].Attributes {
+		attributes[kv.Key] = kv.Value.GetStringValue()
+	}
+	assert.Equal(t, map[string]string{
+		AttributeAuditID:   "1",
+		AttributeStage:     "ResponseComplete",
+		AttributeLevel:     "Metadata",
+		AttributeVerb:      "create",
+		AttributeUser:      "admin",
+		AttributeNamespace: "default",
+	}, attributes)
+
+	decoded := &auditinternal.Event{}
+	require.NoError(t, runtime.DecodeInto(audit.Codecs.UniversalDecoder(auditv1.SchemeGroupVersion), []byte(records[0].Body.GetStringValue()), decoded))
+	assert.Equal(t, events[0].AuditID, decoded.AuditID)
+	assert.Zero(t, records[1].TimeUnixNano, "events without a stage timestamp must have no time")
+}
+
+func TestProcessEventsRejected(t *testing.T) {
+	b := newTestBackend(t, &fakeCollector{rejected: 1})
+	assert.False(t, b.ProcessEvents(&auditinternal.Event{AuditID: types.UID("1")}))
+}
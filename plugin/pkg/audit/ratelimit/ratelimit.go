@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// PluginName is the name reported in error metrics.
+	PluginName = "ratelimit"
+
+	// SuppressedCountAnnotationKey is the number of events of the user of an
+	// event that were suppressed since the last report.
+	SuppressedCountAnnotationKey = "audit.k8s.io/suppressed-count"
+
+	// idleTimeout is the time after which users without events are forgotten,
+	// even if their limiter has not refilled yet, and requests without further
+	// events are forgotten, e.g. if their last stage was omitted.
+	idleTimeout = time.Hour
+)
+
+// Config represents rate limiting backend configuration.
+type Config struct {
+	// QPS is the maximum average number of requests per second of each user
+	// whose events are sent.
+	QPS float32
+	// Burst is the maximum number of requests of each user whose events are
+	// sent at once.
+	Burst int
+	// ReportInterval is the interval the number of suppressed events of each
+	// user is reported to the delegate at.
+	ReportInterval time.Duration
+}
+
+type user struct {
+	limiter *rate.Limiter
+	// last is the time of the last event of the user, used to forget users
+	// whose limiter has refilled.
+	last time.Time
+
+	// info and first are the user and the request time of the first event
+	// suppressed since the last report, suppressed the number of events
+	// suppressed since then.
+	info       authnv1.UserInfo
+	first      time.Time
+	suppressed int
+}
+
+// request is the decision on the events of a request, made on its first event.
+type request struct {
+	allowed bool
+	// last is the time of the last event of the request.
+	last time.Time
+}
+
+type backend struct {
+	delegateBackend audit.Backend
+	c               Config
+	clock           clock.WithTicker
+
+	// mu protects users and requests.
+	mu    sync.Mutex
+	users map[string]*user
+	// requests are the decisions on the requests whose last stage was not
+	// received yet, by audit ID.
+	requests map[types.UID]*request
+
+	stopCh chan struct{}
+	// wg waits for the reporting goroutine on shutdown.
+	wg           sync.WaitGroup
+	shutdownOnce sync.Once
+}
+
+var _ audit.Backend = &backend{}
+
+// NewBackend returns a new rate limiting backend, using configuration passed
+// in the parameters. Requests of each user, identified by username, are sent to
+// the delegate at most at the configured rate. The decision is made on the
+// first event of a request and applies to all of its stages, so requests are
+// either recorded completely or not at all. Events of requests exceeding the
+// rate are dropped, and their number is reported to the delegate every report
+// interval, in an event of the user annotated with the count.
+// Rate limiting backend automatically runs and shut downs the delegate backend.
+func NewBackend(delegateBackend audit.Backend, config Config) audit.Backend {
+	return &backend{
+		delegateBackend: delegateBackend,
+		c:               config,
+		clock:           clock.RealClock{},
+		users:           map[string]*user{},
+		requests:        map[types.UID]*request{},
+		stopCh:          make(chan struct{}),
+	}
+}
+
+func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
+	passed := make([]*auditinternal.Event, 0, len(events))
+	now := b.clock.Now()
+	b.mu.Lock()
+	for _, ev := range events {
+		u, ok := b.users[ev.User.Username]
+		if !ok {
+			u = &user{limiter: rate.NewLimiter(rate.Limit(b.c.QPS), b.c.Burst)}
+			b.users[ev.User.Username] = u
+		}
+		u.last = now
+		r, ok := b.requests[ev.AuditID]
+		if !ok {
+			r = &request{allowed: u.limiter.AllowN(now, 1)}
+			b.requests[ev.AuditID] = r
+		}
+		r.last = now
+		if ev.Stage == auditinternal.StageResponseComplete || ev.Stage == auditinternal.StagePanic {
+			delete(b.requests, ev.AuditID)
+		}
+		if r.allowed {
+			passed = append(passed, ev)
+			continue
+		}
+		if u.suppressed == 0 {
+			u.info = *ev.User.DeepCopy()
+			u.first = ev.RequestReceivedTimestamp.Time
+		}
+		u.suppressed++
+	}
+	b.mu.Unlock()
+
+	if suppressed := len(events) - len(passed); suppressed > 0 {
+		audit.ObserveDroppedEvents(PluginName, audit.DropReasonRateLimited, suppressed)
+	}
+	if len(passed) == 0 {
+		return true
+	}
+	return b.delegateBackend.ProcessEvents(passed...)
+}
+
+// report sends an event for each user with events suppressed since the last
+// report to the delegate, and forgets users whose limiter has refilled or who
+// were idle for idleTimeout, and requests idle for idleTimeout.
+func (b *backend) report() {
+	now := b.clock.Now()
+	refill := time.Duration(float64(b.c.Burst) / float64(b.c.QPS) * float64(time.Second))
+
+	var events []*auditinternal.Event
+	b.mu.Lock()
+	for name, u := range b.users {
+		if u.suppressed > 0 {
+			events = append(events, &auditinternal.Event{
+				Level:                    auditinternal.LevelMetadata,
+				AuditID:                  types.UID(uuid.New().String()),
+				Stage:                    auditinternal.StageResponseComplete,
+				User:                     u.info,
+				RequestReceivedTimestamp: metav1.NewMicroTime(u.first),
+				StageTimestamp:           metav1.NewMicroTime(now),
+				Annotations: map[string]string{
+					SuppressedCountAnnotationKey: strconv.Itoa(u.suppressed),
+				},
+			})
+			u.info = authnv1.UserInfo{}
+			u.suppressed = 0
+			continue
+		}
+		if idle := now.Sub(u.last); idle >= refill || idle >= idleTimeout {
+			delete(b.users, name)
+		}
+	}
+	for id, r := range b.requests {
+		if now.Sub(r.last) >= idleTimeout {
+			delete(b.requests, id)
+		}
+	}
+	b.mu.Unlock()
+
+	if len(events) > 0 {
+		b.delegateBackend.ProcessEvents(events...)
+	}
+}
+
+func (b *backend) Run(stopCh <-chan struct{}) error {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := b.clock.NewTicker(b.c.ReportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				b.report()
+			case <-stopCh:
+				return
+			case <-b.stopCh:
+				return
+			}
+		}
+	}()
+	return b.delegateBackend.Run(stopCh)
+}
+
+// Shutdown reports the events suppressed so far to the delegate before
+// shutting it down. Only the first call has an effect.
+func (b *backend) Shutdown() {
+	b.shutdownOnce.Do(func() {
+		close(b.stopCh)
+		b.wg.Wait()
+		b.report()
+		b.delegateBackend.Shutdown()
+	})
+}
+
+func (b *backend) HealthCheck() error {
+	return audit.CheckHealth(b.delegateBackend)
+}
+
+func (b *backend) String() string {
+	return fmt.Sprintf("%s<%s>", PluginName, b.delegateBackend)
+}
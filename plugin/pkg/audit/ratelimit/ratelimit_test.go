@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/plugin/pkg/audit/fake"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var defaultConfig = Config{
+	QPS:            1,
+	Burst:          2,
+	ReportInterval: time.Minute,
+}
+
+func newEvent(id, user string, ts time.Time) *auditinternal.Event {
+	return &auditinternal.Event{
+		AuditID:                  types.UID(id),
+		Level:                    auditinternal.LevelMetadata,
+		Stage:                    auditinternal.StageResponseComplete,
+		Verb:                     "get",
+		User:                     authnv1.UserInfo{Username: user},
+		RequestReceivedTimestamp: metav1.NewMicroTime(ts),
+		StageTimestamp:           metav1.NewMicroTime(ts),
+	}
+}
+
+func TestRateLimitingUsers(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	var got []*auditinternal.Event
+	fb := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			got = append(got, events...)
+		},
+	}
+	b := NewBackend(fb, defaultConfig)
+	b.(*backend).clock = fakeClock
+
+	for i := 0; i < 5; i++ {
+		b.ProcessEvents(newEvent(strconv.Itoa(i), "flood", fakeClock.Now()))
+	}
+	b.ProcessEvents(newEvent("5", "quiet", fakeClock.Now()))
+	require.Len(t, got, 3, "the events of each user must be limited to its burst")
+	assert.Equal(t, []types.UID{"0", "1", "5"}, []types.UID{got[0].AuditID, got[1].AuditID, got[2].AuditID})
+
+	fakeClock.Step(time.Second)
+	b.ProcessEvents(newEvent("6", "flood", fakeClock.Now()))
+	require.Len(t, got, 4, "the limit must refill at the configured rate")
+
+	got = nil
+	b.(*backend).report()
+	require.Len(t, got, 1, "only users with suppressed events must be reported")
+	assert.Equal(t, "flood", got[0].User.Username)
+	assert.Equal(t, "3", got[0].Annotations[SuppressedCountAnnotationKey])
+
+	got = nil
+	b.(*backend).report()
+	assert.Empty(t, got, "suppressed events must only be reported once")
+}
+
+func TestRateLimitingRequests(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	var got []*auditinternal.Event
+	fb := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			got = append(got, events...)
+		},
+	}
+	b := NewBackend(fb, Config{QPS: 1, Burst: 1, ReportInterval: time.Minute})
+	b.(*backend).clock = fakeClock
+
+	stage := func(id string, stage auditinternal.Stage) *auditinternal.Event {
+		ev := newEvent(id, "a", fakeClock.Now())
+		ev.Stage = stage
+		return ev
+	}
+	b.ProcessEvents(stage("0", auditinternal.StageRequestReceived))
+	b.ProcessEvents(stage("1", auditinternal.StageRequestReceived))
+	// The limiter refills, yet the suppressed request must stay suppressed.
+	fakeClock.Step(time.Second)
+	b.ProcessEvents(stage("1", auditinternal.StageResponseComplete))
+	b.ProcessEvents(stage("0", auditinternal.StageResponseComplete))
+
+	require.Len(t, got, 2, "all stages of a request must share the decision on its first stage")
+	assert.Equal(t, []types.UID{"0", "0"}, []types.UID{got[0].AuditID, got[1].AuditID})
+	assert.Empty(t, b.(*backend).requests, "requests must be forgotten once complete")
+}
+
+func TestForgettingIdleEntries(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	b := NewBackend(&fake.Backend{}, Config{QPS: 0.0001, Burst: 1, ReportInterval: time.Minute})
+	b.(*backend).clock = fakeClock
+
+	ev := newEvent("0", "a", fakeClock.Now())
+	ev.Stage = auditinternal.StageResponseStarted
+	b.ProcessEvents(ev)
+	b.(*backend).report()
+	assert.Len(t, b.(*backend).users, 1)
+	assert.Len(t, b.(*backend).requests, 1)
+
+	fakeClock.Step(idleTimeout)
+	b.(*backend).report()
+	assert.Empty(t, b.(*backend).users, "idle users must be forgotten before their limiter has refilled")
+	assert.Empty(t, b.(*backend).requests, "idle requests must be forgotten")
+}
+
+func TestForgettingUsers(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	b := NewBackend(&fake.Backend{}, defaultConfig)
+	b.(*backend).clock = fakeClock
+
+	b.ProcessEvents(newEvent("0", "a", fakeClock.Now()))
+	b.(*backend).report()
+	assert.Len(t, b.(*backend).users, 1, "users must be remembered until their limiter has refilled")
+
+	fakeClock.Step(2 * time.Second)
+	b.(*backend).report()
+	assert.Empty(t, b.(*backend).users, "users must be forgotten once their limiter has refilled")
+}
+
+func TestReportingSuppressedEvents(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	sent := make(chan []*auditinternal.Event, 1)
+	fb := &fake.Backend{
+		OnRequest: func(events []*auditinternal.Event) {
+			sent <- events
+		},
+	}
+	b := NewBackend(fb, Config{QPS: 1, Burst: 1, ReportInterval: time.Minute})
+	b.(*backend).clock = fakeClock
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	require.NoError(t, b.Run(stopCh))
+
+	b.ProcessEvents(newEvent("0", "a", fakeClock.Now()))
+	<-sent
+	b.ProcessEvents(newEvent("1", "a", fakeClock.Now()))
+	require.Eventually(t, fakeClock.HasWaiters, wait.ForeverTestTimeout, 10*time.Millisecond)
+	fakeClock.Step(time.Minute)
+	select {
+	case events := <-sent:
+		require.Len(t, events, 1)
+		assert.Equal(t, "1", events[0].Annotations[SuppressedCountAnnotationKey])
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("Suppressed events should be reported every report interval")
+	}
+
+	b.ProcessEvents(newEvent("2", "a", fakeClock.Now()))
+	<-sent
+	b.ProcessEvents(newEvent("3", "a", fakeClock.Now()))
+	b.Shutdown()
+	select {
+	case events := <-sent:
+		require.Len(t, events, 1, "Suppressed events should be reported on shutdown")
+	default:
+		t.Fatal("Suppressed events should be reported on shutdown")
+	}
+	// Shutting down again must not panic.
+	b.Shutdown()
+}
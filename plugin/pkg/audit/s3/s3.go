@@ -0,0 +1,232 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package s3 implements the audit.Backend interface by archiving events to an
+// S3-compatible object storage bucket, so that clusters without a log pipeline
+// can retain audit data durably.
+//
+// Each batch of events is uploaded as gzip compressed chunks of JSON lines, one
+// object per chunk, under keys partitioned by the hour of the upload. Objects are
+// uploaded through an ObjectStore, which owns the endpoint and credentials.
+// NewObjectStore returns a store uploading to the S3 API with requests signed by
+// AWS Signature Version 4; callers may provide their own. Wrap the backend with
+// the buffered backend to upload events in batches.
+package s3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"path"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/apis/audit/install"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// PluginName is the name of this plugin, to be used in help and logs.
+	PluginName = "s3"
+
+	// PartitionLayout is the time layout of the partition of the object keys,
+	// one partition per hour in UTC.
+	PartitionLayout = "2006/01/02/15"
+
+	// ContentType is the content type of the uploaded objects.
+	ContentType = "application/x-ndjson"
+	// ContentEncoding is the content encoding of the uploaded objects.
+	ContentEncoding = "gzip"
+)
+
+func init() {
+	install.Install(audit.Scheme)
+}
+
+// Object is an object uploaded to object storage.
+type Object struct {
+	// Bucket is the bucket the object is uploaded to.
+	Bucket string
+	// Key is the key of the object in the bucket.
+	Key string
+	// ContentType is the content type of the object.
+	ContentType string
+	// ContentEncoding is the content encoding of the object.
+	ContentEncoding string
+	// Body is the content of the object.
+	Body []byte
+}
+
+// ObjectStore uploads objects to an S3-compatible object storage.
+type ObjectStore interface {
+	// PutObject uploads the object and returns once it is stored durably.
+	PutObject(ctx context.Context, object Object) error
+}
+
+// Config configures the s3 backend.
+type Config struct {
+	// Bucket is the bucket events are archived to.
+	Bucket string
+	// Prefix is prepended to the keys of the objects, e.g. the name of the cluster.
+	Prefix string
+	// InstanceID tells apart the objects uploaded by different apiservers in the
+	// same partition, e.g. the ID of the apiserver. A random ID is used if empty.
+	InstanceID string
+	// MaxChunkSize is the maximum uncompressed size in bytes of a chunk. A batch
+	// exceeding it is split into several objects. Chunks are not limited if zero.
+	MaxChunkSize int64
+	// GroupVersion is the audit API version events are encoded in.
+	GroupVersion schema.GroupVersion
+	// Timeout is the timeout of an upload. No timeout is applied if zero.
+	Timeout time.Duration
+}
+
+type backend struct {
+	store        ObjectStore
+	bucket       string
+	prefix       string
+	instanceID   string
+	maxChunkSize int64
+	encoder      runtime.Encoder
+	timeout      time.Duration
+	clock        clock.Clock
+
+	// sequence numbers the objects uploaded by the backend, so that the keys
+	// of objects uploaded at the same time are unique.
+	sequence uint64
+}
+
+var _ audit.Backend = &backend{}
+
+// NewBackend returns an audit backend that archives events to a bucket through
+// the given object store.
+func NewBackend(store ObjectStore, config Config) (audit.Backend, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("bucket not specified")
+	}
+	if config.MaxChunkSize < 0 {
+		return nil, fmt.Errorf("max chunk size must not be negative, got %d", config.MaxChunkSize)
+	}
+	instanceID := config.InstanceID
+	if instanceID == "" {
+		instanceID = uuid.New().String()
+	}
+	return &backend{
+		store:        store,
+		bucket:       config.Bucket,
+		prefix:       config.Prefix,
+		instanceID:   instanceID,
+		maxChunkSize: config.MaxChunkSize,
+		encoder:      audit.Codecs.LegacyCodec(config.GroupVersion),
+		timeout:      config.Timeout,
+		clock:        clock.RealClock{},
+	}, nil
+}
+
+func (b *backend) Run(stopCh <-chan struct{}) error {
+	return nil
+}
+
+func (b *backend) Shutdown() {
+	// Nothing to do here.
+}
+
+// chunk is a chunk of JSON lines and the events encoded in it.
+type chunk struct {
+	lines  bytes.Buffer
+	events []*auditinternal.Event
+}
+
+func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
+	var chunks []*chunk
+	current := &chunk{}
+	success := true
+	for _, ev := range events {
+		line, err := runtime.Encode(b.encoder, ev)
+		if err != nil {
+			audit.HandlePluginError(PluginName, err, ev)
+			success = false
+			continue
+		}
+		if b.maxChunkSize > 0 && len(current.events) > 0 && int64(current.lines.Len()+len(line)) > b.maxChunkSize {
+			chunks = append(chunks, current)
+			current = &chunk{}
+		}
+		// The encoder terminates each event with a newline.
+		current.lines.Write(line)
+		current.events = append(current.events, ev)
+	}
+	if len(current.events) > 0 {
+		chunks = append(chunks, current)
+	}
+	for _, c := range chunks {
+		if err := b.upload(c); err != nil {
+			audit.HandlePluginError(PluginName, err, c.events...)
+			success = false
+		}
+	}
+	return success
+}
+
+// upload compresses the chunk and uploads it as a new object.
+func (b *backend) upload(c *chunk) error {
+	var body bytes.Buffer
+	w := gzip.NewWriter(&body)
+	if _, err := w.Write(c.lines.Bytes()); err != nil {
+		return fmt.Errorf("failed to compress chunk: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to compress chunk: %v", err)
+	}
+
+	ctx := context.Background()
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+	key := b.key(b.clock.Now())
+	if err := b.store.PutObject(ctx, Object{
+		Bucket:          b.bucket,
+		Key:             key,
+		ContentType:     ContentType,
+		ContentEncoding: ContentEncoding,
+		Body:            body.Bytes(),
+	}); err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %v", b.bucket, key, err)
+	}
+	return nil
+}
+
+// key returns the key of an object uploaded at the given time, e.g.
+// <prefix>/2022/06/30/14/<instance ID>-20220630T142501.123456789Z-1.jsonl.gz
+func (b *backend) key(now time.Time) string {
+	now = now.UTC()
+	sequence := atomic.AddUint64(&b.sequence, 1)
+	name := fmt.Sprintf("%s-%s-%d.jsonl.gz", b.instanceID, now.Format("20060102T150405.000000000Z"), sequence)
+	return path.Join(b.prefix, now.Format(PartitionLayout), name)
+}
+
+func (b *backend) String() string {
+	return PluginName
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+type fakeStore struct {
+	objects []Object
+	err     error
+}
+
+func (s *fakeStore) PutObject(_ context.Context, object Object) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.objects = append(s.objects, object)
+	return nil
+}
+
+var events = []*auditinternal.Event{{
+	AuditID: types.UID("1"),
+	Stage:   auditinternal.StageResponseComplete,
+}, {
+	AuditID: types.UID("2"),
+	Stage:   auditinternal.StageResponseComplete,
+}, {
+	AuditID: types.UID("3"),
+	Stage:   auditinternal.StageResponseComplete,
+}}
+
+func newTestBackend(t *testing.T, store ObjectStore, config Config) *backend {
+	config.GroupVersion = auditv1.SchemeGroupVersion
+	b, err := NewBackend(store, config)
+	require.NoError(t, err)
+	backend := b.(*backend)
+	backend.clock = testingclock.NewFakeClock(time.Date(2022, 6, 30, 14, 25, 1, 0, time.UTC))
+	return backend
+}
+
+// decodeObject returns the audit IDs of the events of the object.
+func decodeObject(t *testing.T, object Object) []types.UID {
+	r, err := gzip.NewReader(bytes.NewReader(object.Body))
+	require.NoError(t, err)
+	var ids []types.UID
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		ev := &auditinternal.Event{}
+		require.NoError(t, runtime.DecodeInto(audit.Codecs.UniversalDecoder(auditv1.SchemeGroupVersion), scanner.Bytes(), ev))
+		ids = append(ids, ev.AuditID)
+	}
+	require.NoError(t, scanner.Err())
+	return ids
+}
+
+func TestProcessEvents(t *testing.T) {
+	store := &fakeStore{}
+	b := newTestBackend(t, store, Config{Bucket: "audit", Prefix: "cluster", InstanceID: "apiserver-1"})
+
+	require.True(t, b.ProcessEvents(events...))
+	require.Len(t, store.objects, 1)
+	object := store.objects[0]
+	assert.Equal(t, "audit", object.Bucket)
+	assert.Equal(t, "cluster/2022/06/30/14/apiserver-1-20220630T142501.000000000Z-1.jsonl.gz", object.Key)
+	assert.Equal(t, ContentType, object.ContentType)
+	assert.Equal(t, ContentEncoding, object.ContentEncoding)
+	assert.Equal(t, []types.UID{"1", "2", "3"}, decodeObject(t, object))
+
+	require.True(t, b.ProcessEvents(events[0]))
+	require.Len(t, store.objects, 2)
+	assert.NotEqual(t, object.Key, store.objects[1].Key, "keys of objects uploaded at the same time must be unique")
+}
+
+func TestProcessEventsChunks(t *testing.T) {
+	line, err := runtime.Encode(audit.Codecs.LegacyCodec(auditv1.SchemeGroupVersion), events[0])
+	require.NoError(t, err)
+
+	store := &fakeStore{}
+	b := newTestBackend(t, store, Config{Bucket: "audit", MaxChunkSize: int64(2 * len(line))})
+
+	require.True(t, b.ProcessEvents(events...))
+	require.Len(t, store.objects, 2)
+	assert.Equal(t, []types.UID{"1", "2"}, decodeObject(t, store.objects[0]))
+	assert.Equal(t, []types.UID{"3"}, decodeObject(t, store.objects[1]))
+	for _, object := range store.objects {
+		assert.True(t, strings.HasPrefix(object.Key, "2022/06/30/14/"), "unexpected key %s", object.Key)
+	}
+}
+
+func TestProcessEventsStoreError(t *testing.T) {
+	store := &fakeStore{err: errors.New("access denied")}
+	b := newTestBackend(t, store, Config{Bucket: "audit"})
+
+	assert.False(t, b.ProcessEvents(events...))
+}
+
+func TestNewBackendInvalidConfig(t *testing.T) {
+	_, err := NewBackend(&fakeStore{}, Config{GroupVersion: auditv1.SchemeGroupVersion})
+	assert.Error(t, err, "missing bucket")
+	_, err = NewBackend(&fakeStore{}, Config{Bucket: "audit", MaxChunkSize: -1, GroupVersion: auditv1.SchemeGroupVersion})
+	assert.Error(t, err, "negative max chunk size")
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// signingAlgorithm is the algorithm of AWS Signature Version 4.
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	// signingService is the service requests are signed for.
+	signingService = "s3"
+	// amzDateLayout is the time layout of the X-Amz-Date header.
+	amzDateLayout = "20060102T150405Z"
+	// scopeDateLayout is the time layout of the date of the credential scope.
+	scopeDateLayout = "20060102"
+
+	// maxErrorBodySize bounds the part of an error response reported in errors.
+	maxErrorBodySize = 1024
+)
+
+// Credentials are the AWS credentials requests are signed with.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is the session token of temporary credentials, if any.
+	SessionToken string
+}
+
+// StoreConfig configures an object store uploading objects through the S3 API.
+type StoreConfig struct {
+	// Endpoint is the URL of the S3 API, e.g. https://s3.eu-west-1.amazonaws.com.
+	Endpoint string
+	// Region is the region requests are signed for, e.g. eu-west-1.
+	Region string
+	// PathStyle addresses buckets in the path of requests instead of in the
+	// host name, as some S3-compatible object storages require.
+	PathStyle bool
+	// Credentials are the credentials requests are signed with.
+	Credentials Credentials
+	// TLSConfig, if set, configures TLS connections to the endpoint.
+	TLSConfig *tls.Config
+	// Dial, if set, is used to connect to the endpoint.
+	Dial utilnet.DialFunc
+}
+
+type objectStore struct {
+	client      *http.Client
+	endpoint    *url.URL
+	region      string
+	pathStyle   bool
+	credentials Credentials
+	clock       clock.Clock
+}
+
+var _ ObjectStore = &objectStore{}
+
+// NewObjectStore returns an object store uploading objects through the S3 API
+// of the given endpoint, with requests signed by AWS Signature Version 4.
+func NewObjectStore(config StoreConfig) (ObjectStore, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %v", config.Endpoint, err)
+	}
+	if (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q, must be an http or https URL", config.Endpoint)
+	}
+	if config.Region == "" {
+		return nil, fmt.Errorf("region not specified")
+	}
+	if config.Credentials.AccessKeyID == "" || config.Credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("credentials not specified")
+	}
+	transport := &http.Transport{TLSClientConfig: config.TLSConfig}
+	if config.Dial != nil {
+		transport.DialContext = config.Dial
+	}
+	return &objectStore{
+		client:      &http.Client{Transport: utilnet.SetTransportDefaults(transport)},
+		endpoint:    endpoint,
+		region:      config.Region,
+		pathStyle:   config.PathStyle,
+		credentials: config.Credentials,
+		clock:       clock.RealClock{},
+	}, nil
+}
+
+func (s *objectStore) PutObject(ctx context.Context, object Object) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(object.Bucket, object.Key), bytes.NewReader(object.Body))
+	if err != nil {
+		return err
+	}
+	if object.ContentType != "" {
+		req.Header.Set("Content-Type", object.ContentType)
+	}
+	if object.ContentEncoding != "" {
+		req.Header.Set("Content-Encoding", object.ContentEncoding)
+	}
+	payloadHash := sha256.Sum256(object.Body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	if s.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.credentials.SessionToken)
+	}
+	signV4(req, hex.EncodeToString(payloadHash[:]), s.region, s.credentials, s.clock.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return fmt.Errorf("unexpected response %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// objectURL returns the URL of the object, with the bucket in the host name
+// unless buckets are addressed in the path.
+func (s *objectStore) objectURL(bucket, key string) string {
+	u := *s.endpoint
+	p := "/" + key
+	if s.pathStyle {
+		p = "/" + bucket + p
+	} else {
+		u.Host = bucket + "." + u.Host
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + p
+	// Escape the path the way it is signed.
+	u.RawPath = uriEncode(u.Path, false)
+	return u.String()
+}
+
+// signV4 signs the request with AWS Signature Version 4 at the given time, given
+// the hex encoded SHA-256 hash of its payload. The host and all headers of the
+// request are signed.
+func signV4(req *http.Request, payloadHash, region string, credentials Credentials, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(amzDateLayout)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		trimmed := make([]string, len(values))
+		for i, v := range values {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scopeDate := now.Format(scopeDateLayout)
+	scope := strings.Join([]string{scopeDate, region, signingService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), scopeDate)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, signingService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery returns the query parameters sorted by name and value, and
+// encoded the way they are signed.
+func canonicalQuery(query url.Values) string {
+	var params [][2]string
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, [2]string{uriEncode(name, true), uriEncode(value, true)})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i][0] != params[j][0] {
+			return params[i][0] < params[j][0]
+		}
+		return params[i][1] < params[j][1]
+	})
+	encoded := make([]string, len(params))
+	for i, p := range params {
+		encoded[i] = p[0] + "=" + p[1]
+	}
+	return strings.Join(encoded, "&")
+}
+
+// uriEncode percent-encodes all bytes of s but the unreserved characters of
+// RFC 3986, and slashes unless encodeSlash is set, as AWS Signature Version 4
+// requires.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
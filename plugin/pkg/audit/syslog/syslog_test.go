@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syslog
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+	certutil "k8s.io/client-go/util/cert"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var event = &auditinternal.Event{
+	AuditID:        types.UID("1"),
+	Stage:          auditinternal.StageResponseComplete,
+	Level:          auditinternal.LevelMetadata,
+	Verb:           "create",
+	RequestURI:     "/api/v1/namespaces/default/pods",
+	User:           authnv1.UserInfo{Username: `system:serviceaccount:default:"x]`},
+	SourceIPs:      []string{"10.0.0.1"},
+	ObjectRef:      &auditinternal.ObjectReference{Resource: "pods", Namespace: "default", Name: "web"},
+	ResponseStatus: &metav1.Status{Code: 201},
+	StageTimestamp: metav1.NewMicroTime(time.Date(2022, 10, 1, 12, 0, 0, 123456000, time.UTC)),
+}
+
+// listen returns a listener whose accepted connections send the messages
+// framed by octet counting to the returned channel.
+func listen(t *testing.T, listener net.Listener) <-chan string {
+	t.Cleanup(func() { listener.Close() })
+	messages := make(chan string, 100)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					length, err := r.ReadString(' ')
+					if err != nil {
+						return
+					}
+					n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+					if err != nil {
+						return
+					}
+					msg := make([]byte, n)
+					if _, err := io.ReadFull(r, msg); err != nil {
+						return
+					}
+					messages <- string(msg)
+				}
+			}()
+		}
+	}()
+	return messages
+}
+
+func receive(t *testing.T, messages <-chan string) string {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for message")
+		return ""
+	}
+}
+
+func newTestBackend(t *testing.T, config Config) *backend {
+	config.Hostname = "apiserver-1"
+	config.GroupVersion = auditv1.SchemeGroupVersion
+	b, err := NewBackend(config)
+	require.NoError(t, err)
+	t.Cleanup(b.Shutdown)
+	return b.(*backend)
+}
+
+func TestProcessEvents(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	messages := listen(t, listener)
+	b := newTestBackend(t, Config{Address: listener.Addr().String()})
+
+	require.True(t, b.ProcessEvents(event))
+	msg := receive(t, messages)
+
+	header := "<110>1 2022-10-01T12:00:00.123456Z apiserver-1 kube-apiserver " + b.procID + " ResponseComplete "
+	require.True(t, strings.HasPrefix(msg, header), "unexpected header: %s", msg)
+	sd := `[audit@32473 auditID="1" stage="ResponseComplete" level="Metadata" verb="create" ` +
+		`requestURI="/api/v1/namespaces/default/pods" user="system:serviceaccount:default:\"x\]" ` +
+		`sourceIP="10.0.0.1" namespace="default" resource="pods" name="web" code="201"] `
+	require.True(t, strings.HasPrefix(msg[len(header):], sd), "unexpected structured data: %s", msg)
+
+	decoded := &auditinternal.Event{}
+	require.NoError(t, runtime.DecodeInto(audit.Codecs.UniversalDecoder(auditv1.SchemeGroupVersion), []byte(msg[len(header)+len(sd):]), decoded))
+	assert.Equal(t, event.AuditID, decoded.AuditID)
+}
+
+func TestProcessEventsTLS(t *testing.T) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", []net.IP{net.ParseIP("127.0.0.1")}, nil)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	messages := listen(t, listener)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(certPEM))
+	b := newTestBackend(t, Config{Address: listener.Addr().String(), TLSConfig: &tls.Config{RootCAs: roots}})
+
+	require.True(t, b.ProcessEvents(event))
+	assert.Contains(t, receive(t, messages), `auditID="1"`)
+
+	untrusted := newTestBackend(t, Config{Address: listener.Addr().String(), TLSConfig: &tls.Config{}})
+	assert.False(t, untrusted.ProcessEvents(event), "server certificate must be verified")
+}
+
+func TestReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	messages := listen(t, listener)
+	b := newTestBackend(t, Config{Address: listener.Addr().String()})
+
+	require.True(t, b.ProcessEvents(event))
+	receive(t, messages)
+
+	// Break the connection, so the next write fails and the backend must reconnect.
+	b.mu.Lock()
+	b.conn.Close()
+	b.mu.Unlock()
+	require.True(t, b.ProcessEvents(event))
+	assert.Contains(t, receive(t, messages), `auditID="1"`)
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	dials := 0
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	b := newTestBackend(t, Config{
+		Address: "syslog.example.com:6514",
+		Dial: func(context.Context, string, string) (net.Conn, error) {
+			dials++
+			return nil, errors.New("connection refused")
+		},
+	})
+	b.clock = fakeClock
+
+	assert.False(t, b.ProcessEvents(event))
+	assert.Equal(t, 1, dials)
+	assert.False(t, b.ProcessEvents(event))
+	assert.Equal(t, 1, dials, "connecting must be backed off")
+
+	fakeClock.Step(initialReconnectBackoff)
+	assert.False(t, b.ProcessEvents(event))
+	assert.Equal(t, 2, dials)
+	fakeClock.Step(initialReconnectBackoff)
+	assert.False(t, b.ProcessEvents(event))
+	assert.Equal(t, 2, dials, "backoff must grow")
+	fakeClock.Step(initialReconnectBackoff)
+	assert.False(t, b.ProcessEvents(event))
+	assert.Equal(t, 3, dials)
+}
+
+func TestNewBackendInvalidConfig(t *testing.T) {
+	_, err := NewBackend(Config{})
+	assert.Error(t, err, "missing address")
+	facility := 24
+	_, err = NewBackend(Config{Address: "syslog.example.com:6514", Facility: &facility})
+	assert.Error(t, err, "invalid facility")
+	_, err = NewBackend(Config{Address: "syslog.example.com:6514", StructuredDataID: "audit id"})
+	assert.Error(t, err, "invalid structured data ID")
+}
@@ -36,6 +36,30 @@ const (
 	annotationValue = "true"
 )
 
+// Strategy is a way of reducing the size of an event larger than MaxEventSize.
+type Strategy string
+
+const (
+	// StrategyStripObjects removes the request and response objects.
+	StrategyStripObjects Strategy = "strip-objects"
+	// StrategyStripAnnotations removes the annotations, except for the one
+	// indicating truncation.
+	StrategyStripAnnotations Strategy = "strip-annotations"
+	// StrategyDowngradeToMetadata lowers the level of the event to Metadata,
+	// removing the request and response objects.
+	StrategyDowngradeToMetadata Strategy = "downgrade-to-metadata"
+)
+
+// AllowedStrategies are the truncation strategies known by the truncate backend.
+var AllowedStrategies = []string{
+	string(StrategyStripObjects),
+	string(StrategyStripAnnotations),
+	string(StrategyDowngradeToMetadata),
+}
+
+// DefaultStrategies are the truncation strategies applied if none are configured.
+var DefaultStrategies = []Strategy{StrategyStripObjects}
+
 // Config represents truncating backend configuration.
 type Config struct {
 	// MaxEventSize defines max allowed size of the event. If the event is larger,
@@ -46,6 +70,11 @@ type Config struct {
 	// If the total size of the batch is larger than this number, batch will be split. Actual
 	// size of the serialized request might be slightly higher, on the order of hundreds of bytes.
 	MaxBatchSize int64
+
+	// Strategies are applied in order to events larger than MaxEventSize,
+	// until they are small enough. Events still too large are discarded.
+	// Defaults to DefaultStrategies.
+	Strategies []Strategy
 }
 
 type backend struct {
@@ -64,6 +93,9 @@ var _ audit.Backend = &backend{}
 // NewBackend returns a new truncating backend, using configuration passed in the parameters.
 // Truncate backend automatically runs and shut downs the delegate backend.
 func NewBackend(delegateBackend audit.Backend, config Config, groupVersion schema.GroupVersion) audit.Backend {
+	if len(config.Strategies) == 0 {
+		config.Strategies = DefaultStrategies
+	}
 	return &backend{
 		delegateBackend: delegateBackend,
 		c:               config,
@@ -79,12 +111,16 @@ func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
 	success := true
 	for _, event := range events {
 		size, err := b.calcSize(event)
-		// If event was correctly serialized, but the size is more than allowed
-		// and it makes sense to do trimming, i.e. there's a request and/or
-		// response present, try to strip away request and response.
-		if err == nil && size > b.c.MaxEventSize && event.Level.GreaterOrEqual(auditinternal.LevelRequest) {
-			event = truncate(event)
-			size, err = b.calcSize(event)
+		// If event was correctly serialized, but the size is more than allowed,
+		// apply the strategies that make sense for it until it fits.
+		for _, strategy := range b.c.Strategies {
+			if err != nil || size <= b.c.MaxEventSize {
+				break
+			}
+			if truncated := truncate(event, strategy); truncated != nil {
+				event = truncated
+				size, err = b.calcSize(event)
+			}
 		}
 		if err != nil {
 			errors = append(errors, err)
@@ -94,6 +130,7 @@ func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
 		if size > b.c.MaxEventSize {
 			errors = append(errors, fmt.Errorf("event is too large even after truncating"))
 			impacted = append(impacted, event)
+			audit.ObserveDroppedEvents(b.delegateBackend.String(), audit.DropReasonTooLarge, 1)
 			continue
 		}
 
@@ -117,18 +154,48 @@ func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
 	return success
 }
 
-// truncate removed request and response objects from the audit events,
-// to try and keep at least metadata.
-func truncate(e *auditinternal.Event) *auditinternal.Event {
+// truncate applies the strategy to the audit event, returning nil if it does
+// not make sense for the event, i.e. would not reduce its size.
+func truncate(e *auditinternal.Event, strategy Strategy) *auditinternal.Event {
+	switch strategy {
+	case StrategyStripObjects:
+		if e.RequestObject == nil && e.ResponseObject == nil && e.ObjectDiff == nil {
+			return nil
+		}
+	case StrategyStripAnnotations:
+		if _, ok := e.Annotations[annotationKey]; len(e.Annotations) == 0 || (ok && len(e.Annotations) == 1) {
+			return nil
+		}
+	case StrategyDowngradeToMetadata:
+		if e.Level.Less(auditinternal.LevelRequest) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
 	// Make a shallow copy to avoid copying response/request objects.
 	newEvent := &auditinternal.Event{}
 	*newEvent = *e
 
-	newEvent.RequestObject = nil
-	newEvent.ResponseObject = nil
+	annotations := e.Annotations
+	switch strategy {
+	case StrategyStripObjects:
+		newEvent.RequestObject = nil
+		newEvent.ResponseObject = nil
+		newEvent.ObjectDiff = nil
+	case StrategyStripAnnotations:
+		annotations = nil
+	case StrategyDowngradeToMetadata:
+		newEvent.Level = auditinternal.LevelMetadata
+		newEvent.RequestObject = nil
+		newEvent.ResponseObject = nil
+		newEvent.ObjectDiff = nil
+	}
 
-	if newEvent.Annotations == nil {
-		newEvent.Annotations = make(map[string]string)
+	newEvent.Annotations = make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		newEvent.Annotations[k] = v
 	}
 	newEvent.Annotations[annotationKey] = annotationValue
 
@@ -151,6 +218,11 @@ func (b *backend) calcSize(e *auditinternal.Event) (int64, error) {
 	return s.Size, nil
 }
 
+// HealthCheck reports the health of the delegate backend.
+func (b *backend) HealthCheck() error {
+	return audit.CheckHealth(b.delegateBackend)
+}
+
 func (b *backend) String() string {
 	return fmt.Sprintf("%s<%s>", PluginName, b.delegateBackend)
 }
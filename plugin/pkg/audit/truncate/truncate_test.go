@@ -58,6 +58,16 @@ func TestTruncatingEvents(t *testing.T) {
 			},
 			wantTruncated: true,
 		},
+		{
+			desc: "Event with too large object diff should be truncated",
+			event: &auditinternal.Event{
+				Level: auditinternal.LevelRequestResponse,
+				ObjectDiff: &runtime.Unknown{
+					Raw: []byte("\"" + strings.Repeat("A", int(defaultConfig.MaxEventSize)) + "\""),
+				},
+			},
+			wantTruncated: true,
+		},
 		{
 			desc: "Event with too large metadata should be dropped",
 			event: &auditinternal.Event{
@@ -90,6 +100,7 @@ func TestTruncatingEvents(t *testing.T) {
 				require.Equal(t, annotationValue, event.Annotations[annotationKey], "Annotation should be present")
 				require.Nil(t, event.RequestObject, "After truncation request should be nil")
 				require.Nil(t, event.ResponseObject, "After truncation response should be nil")
+				require.Nil(t, event.ObjectDiff, "After truncation object diff should be nil")
 			}
 		})
 	}
@@ -139,3 +150,113 @@ func TestSplittingBatches(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncatingStrategies(t *testing.T) {
+	largeObject := &runtime.Unknown{
+		Raw: []byte("\"" + strings.Repeat("A", int(defaultConfig.MaxEventSize)) + "\""),
+	}
+	largeAnnotations := map[string]string{
+		"key": strings.Repeat("A", int(defaultConfig.MaxEventSize)),
+	}
+	testCases := []struct {
+		desc            string
+		strategies      []Strategy
+		event           *auditinternal.Event
+		wantDropped     bool
+		wantLevel       auditinternal.Level
+		wantAnnotations map[string]string
+	}{
+		{
+			desc:       "Stripping objects keeps annotations",
+			strategies: []Strategy{StrategyStripObjects, StrategyStripAnnotations},
+			event: &auditinternal.Event{
+				Level:         auditinternal.LevelRequest,
+				RequestObject: largeObject,
+				Annotations:   map[string]string{"key": "value"},
+			},
+			wantLevel:       auditinternal.LevelRequest,
+			wantAnnotations: map[string]string{"key": "value", annotationKey: annotationValue},
+		},
+		{
+			desc:       "Too large annotations should be stripped",
+			strategies: []Strategy{StrategyStripObjects, StrategyStripAnnotations},
+			event: &auditinternal.Event{
+				Level:         auditinternal.LevelRequest,
+				RequestObject: largeObject,
+				Annotations:   largeAnnotations,
+			},
+			wantLevel:       auditinternal.LevelRequest,
+			wantAnnotations: map[string]string{annotationKey: annotationValue},
+		},
+		{
+			desc:       "Too large annotations should be dropped without the strategy",
+			strategies: []Strategy{StrategyStripObjects},
+			event: &auditinternal.Event{
+				Level:       auditinternal.LevelMetadata,
+				Annotations: largeAnnotations,
+			},
+			wantDropped: true,
+		},
+		{
+			desc:       "Event should be downgraded to metadata",
+			strategies: []Strategy{StrategyDowngradeToMetadata},
+			event: &auditinternal.Event{
+				Level:          auditinternal.LevelRequestResponse,
+				ResponseObject: largeObject,
+			},
+			wantLevel:       auditinternal.LevelMetadata,
+			wantAnnotations: map[string]string{annotationKey: annotationValue},
+		},
+		{
+			desc:       "Stripping objects strips the object diff",
+			strategies: []Strategy{StrategyStripObjects},
+			event: &auditinternal.Event{
+				Level:      auditinternal.LevelRequestResponse,
+				ObjectDiff: largeObject,
+			},
+			wantLevel:       auditinternal.LevelRequestResponse,
+			wantAnnotations: map[string]string{annotationKey: annotationValue},
+		},
+		{
+			desc:       "Downgrading to metadata strips the object diff",
+			strategies: []Strategy{StrategyDowngradeToMetadata},
+			event: &auditinternal.Event{
+				Level:      auditinternal.LevelRequestResponse,
+				ObjectDiff: largeObject,
+			},
+			wantLevel:       auditinternal.LevelMetadata,
+			wantAnnotations: map[string]string{annotationKey: annotationValue},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var event *auditinternal.Event
+
+			fb := &fake.Backend{
+				OnRequest: func(events []*auditinternal.Event) {
+					require.Equal(t, 1, len(events), "Expected single event in batch")
+					event = events[0]
+				},
+			}
+			config := defaultConfig
+			config.Strategies = tc.strategies
+			b := NewBackend(fb, config, auditv1.SchemeGroupVersion)
+			b.ProcessEvents(tc.event)
+
+			require.Equal(t, !tc.wantDropped, event != nil, "Incorrect event presence")
+			if tc.wantDropped {
+				return
+			}
+			require.Equal(t, tc.wantLevel, event.Level)
+			require.Equal(t, tc.wantAnnotations, event.Annotations)
+			require.Nil(t, event.RequestObject, "After truncation request should be nil")
+			require.Nil(t, event.ResponseObject, "After truncation response should be nil")
+			require.Nil(t, event.ObjectDiff, "After truncation object diff should be nil")
+			require.NotEqual(t, tc.event.Annotations, event.Annotations, "Original event should not be modified")
+		})
+	}
+}
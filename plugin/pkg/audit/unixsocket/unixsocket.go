@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package unixsocket implements the audit.Backend interface by writing events
+// to a Unix domain socket, so node-local collectors can consume them without
+// tailing and rotating a log file.
+package unixsocket
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/apis/audit/install"
+	"k8s.io/apiserver/pkg/audit"
+)
+
+const (
+	// FormatJson writes events as newline-delimited JSON.
+	FormatJson = "json"
+	// FormatProtobuf writes events as protobuf messages, each prefixed by its
+	// length as a 4 byte big-endian unsigned integer.
+	FormatProtobuf = "protobuf"
+
+	// PluginName is the name of this plugin, to be used in help and logs.
+	PluginName = "unixsocket"
+
+	// DefaultTimeout is the default timeout of connecting and writing events.
+	DefaultTimeout = 5 * time.Second
+)
+
+// AllowedFormats are the formats known by unixsocket backend.
+var AllowedFormats = []string{
+	FormatJson,
+	FormatProtobuf,
+}
+
+func init() {
+	install.Install(audit.Scheme)
+}
+
+// Config configures the unixsocket backend.
+type Config struct {
+	// Path is the path of the socket.
+	Path string
+	// Format is the format events are written in.
+	Format string
+	// GroupVersion is the audit API version events are encoded in.
+	GroupVersion schema.GroupVersion
+	// Timeout is the timeout of connecting and writing events. Defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+type backend struct {
+	path    string
+	format  string
+	encoder runtime.Encoder
+	timeout time.Duration
+
+	// mu guards conn, which is nil until connected or after a failure.
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+var _ audit.Backend = &backend{}
+
+// NewBackend returns an audit backend that writes events to the Unix domain
+// socket at the given path. The socket is connected on the first event and
+// reconnected if writing fails, so the collector may be restarted.
+func NewBackend(config Config) (audit.Backend, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("socket path not specified")
+	}
+	var encoder runtime.Encoder
+	switch config.Format {
+	case FormatJson:
+		encoder = audit.Codecs.LegacyCodec(config.GroupVersion)
+	case FormatProtobuf:
+		encoder = audit.Codecs.EncoderForVersion(protobuf.NewRawSerializer(audit.Scheme, audit.Scheme), config.GroupVersion)
+	default:
+		return nil, fmt.Errorf("format %q is not in list of known formats (%s)",
+			config.Format, strings.Join(AllowedFormats, ","))
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return &backend{
+		path:    config.Path,
+		format:  config.Format,
+		encoder: encoder,
+		timeout: config.Timeout,
+	}, nil
+}
+
+func (b *backend) Run(stopCh <-chan struct{}) error {
+	return nil
+}
+
+func (b *backend) Shutdown() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.disconnect()
+}
+
+func (b *backend) ProcessEvents(events ...*auditinternal.Event) bool {
+	success := true
+	for _, ev := range events {
+		data, err := b.encode(ev)
+		if err == nil {
+			err = b.write(data)
+		}
+		if err != nil {
+			audit.HandlePluginError(PluginName, err, ev)
+			success = false
+		}
+	}
+	return success
+}
+
+// encode returns the event framed in the format of the backend.
+func (b *backend) encode(ev *auditinternal.Event) ([]byte, error) {
+	data, err := runtime.Encode(b.encoder, ev)
+	if err != nil {
+		return nil, err
+	}
+	if b.format == FormatProtobuf {
+		return append(binary.BigEndian.AppendUint32(nil, uint32(len(data))), data...), nil
+	}
+	// The JSON serializer terminates the event by a newline.
+	return data, nil
+}
+
+// write writes the data, reconnecting once if the connection failed.
+func (b *backend) write(data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if b.conn == nil {
+			if b.conn, err = net.DialTimeout("unix", b.path, b.timeout); err != nil {
+				b.conn = nil
+				return fmt.Errorf("failed to connect to %s: %v", b.path, err)
+			}
+		}
+		if err = b.conn.SetWriteDeadline(time.Now().Add(b.timeout)); err == nil {
+			_, err = b.conn.Write(data)
+		}
+		if err == nil {
+			return nil
+		}
+		b.disconnect()
+	}
+	return err
+}
+
+func (b *backend) disconnect() {
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+}
+
+func (b *backend) String() string {
+	return PluginName
+}
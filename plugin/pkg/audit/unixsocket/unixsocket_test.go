@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unixsocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+)
+
+var events = []*auditinternal.Event{{
+	AuditID: types.UID("1"),
+	Stage:   auditinternal.StageResponseComplete,
+}, {
+	AuditID: types.UID("2"),
+	Stage:   auditinternal.StageResponseComplete,
+}}
+
+// listen listens on a socket in a temporary directory and returns its path and
+// a channel receiving the connections accepted.
+func listen(t *testing.T) (string, <-chan net.Conn) {
+	path := filepath.Join(t.TempDir(), "audit.sock")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	conns := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+			conns <- conn
+		}
+	}()
+	return path, conns
+}
+
+func accept(t *testing.T, conns <-chan net.Conn) net.Conn {
+	select {
+	case conn := <-conns:
+		return conn
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for connection")
+		return nil
+	}
+}
+
+func TestProcessEventsJson(t *testing.T) {
+	path, conns := listen(t)
+	b, err := NewBackend(Config{Path: path, Format: FormatJson, GroupVersion: auditv1.SchemeGroupVersion})
+	require.NoError(t, err)
+	defer b.Shutdown()
+
+	require.True(t, b.ProcessEvents(events...))
+	r := bufio.NewReader(accept(t, conns))
+	for _, ev := range events {
+		line, err := r.ReadBytes('\n')
+		require.NoError(t, err)
+		decoded := &auditinternal.Event{}
+		require.NoError(t, runtime.DecodeInto(audit.Codecs.UniversalDecoder(auditv1.SchemeGroupVersion), line, decoded))
+		assert.Equal(t, ev.AuditID, decoded.AuditID)
+	}
+}
+
+func TestProcessEventsProtobuf(t *testing.T) {
+	path, conns := listen(t)
+	b, err := NewBackend(Config{Path: path, Format: FormatProtobuf, GroupVersion: auditv1.SchemeGroupVersion})
+	require.NoError(t, err)
+	defer b.Shutdown()
+
+	require.True(t, b.ProcessEvents(events...))
+	conn := accept(t, conns)
+	for _, ev := range events {
+		var length uint32
+		require.NoError(t, binary.Read(conn, binary.BigEndian, &length))
+		data := make([]byte, length)
+		_, err := io.ReadFull(conn, data)
+		require.NoError(t, err)
+		decoded := &auditv1.Event{}
+		require.NoError(t, decoded.Unmarshal(data))
+		assert.Equal(t, ev.AuditID, decoded.AuditID)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	path, conns := listen(t)
+	b, err := NewBackend(Config{Path: path, Format: FormatJson, GroupVersion: auditv1.SchemeGroupVersion})
+	require.NoError(t, err)
+	defer b.Shutdown()
+
+	require.True(t, b.ProcessEvents(events[0]))
+	accept(t, conns)
+
+	// Break the connection, so the next write fails and the backend must reconnect.
+	sb := b.(*backend)
+	sb.mu.Lock()
+	sb.conn.Close()
+	sb.mu.Unlock()
+	require.True(t, b.ProcessEvents(events[1]))
+	line, err := bufio.NewReader(accept(t, conns)).ReadBytes('\n')
+	require.NoError(t, err)
+	assert.Contains(t, string(line), `"auditID":"2"`)
+}
+
+func TestProcessEventsNoListener(t *testing.T) {
+	b, err := NewBackend(Config{Path: filepath.Join(t.TempDir(), "audit.sock"), Format: FormatJson, GroupVersion: auditv1.SchemeGroupVersion})
+	require.NoError(t, err)
+	assert.False(t, b.ProcessEvents(events...))
+}
+
+func TestNewBackendInvalidConfig(t *testing.T) {
+	_, err := NewBackend(Config{Format: FormatJson, GroupVersion: auditv1.SchemeGroupVersion})
+	assert.Error(t, err, "missing path")
+	_, err = NewBackend(Config{Path: "/run/audit.sock", Format: "yaml", GroupVersion: auditv1.SchemeGroupVersion})
+	assert.Error(t, err, "unknown format")
+}
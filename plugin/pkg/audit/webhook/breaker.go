@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// States of the circuit breaker.
+const (
+	// stateClosed sends batches.
+	stateClosed = "closed"
+	// stateOpen fails batches without sending them.
+	stateOpen = "open"
+	// stateHalfOpen sends a single batch to probe whether the webhook recovered.
+	stateHalfOpen = "half-open"
+)
+
+// CircuitBreakerConfig configures the circuit breaker of the webhook backend.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive batches failing to be sent,
+	// after all retries, that opens the circuit breaker. While open, batches
+	// fail without being sent.
+	FailureThreshold int
+	// OpenDuration is the time the circuit breaker stays open before a single
+	// batch is sent to probe whether the webhook recovered, closing it if so.
+	OpenDuration time.Duration
+}
+
+// circuitBreaker stops sending batches to a webhook that is down.
+type circuitBreaker struct {
+	name   string
+	config CircuitBreakerConfig
+	clock  clock.PassiveClock
+
+	mu    sync.Mutex
+	state string
+	// failures is the number of consecutive batches failed while closed.
+	failures int
+	// openedAt is the time the circuit breaker was last opened.
+	openedAt time.Time
+}
+
+func newCircuitBreaker(name string, config CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{
+		name:   name,
+		config: config,
+		clock:  clock.RealClock{},
+		state:  stateClosed,
+	}
+}
+
+// allow returns whether a batch may be sent. Once open for OpenDuration, the
+// circuit breaker is half-open and allows a single batch.
+func (c *circuitBreaker) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case stateClosed:
+		return true
+	case stateOpen:
+		if c.clock.Since(c.openedAt) < c.config.OpenDuration {
+			return false
+		}
+		c.transition(stateHalfOpen)
+		return true
+	default:
+		// The probing batch is in flight.
+		return false
+	}
+}
+
+// done records whether sending a batch allowed succeeded.
+func (c *circuitBreaker) done(success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case success:
+		c.failures = 0
+		if c.state != stateClosed {
+			c.transition(stateClosed)
+		}
+	case c.state == stateHalfOpen:
+		c.open()
+	case c.state == stateClosed:
+		c.failures++
+		if c.failures >= c.config.FailureThreshold {
+			c.open()
+		}
+	}
+}
+
+func (c *circuitBreaker) open() {
+	c.failures = 0
+	c.openedAt = c.clock.Now()
+	c.transition(stateOpen)
+}
+
+func (c *circuitBreaker) transition(state string) {
+	if state == stateOpen {
+		klog.Warningf("Audit webhook %s is down, not sending events for %v", c.name, c.config.OpenDuration)
+	} else {
+		klog.V(2).Infof("Audit webhook %s circuit breaker is %s", c.name, state)
+	}
+	c.state = state
+	observeCircuitBreakerTransition(c.name, state)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	circuitBreakerTransitionCounter.Reset()
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	c := newCircuitBreaker("webhook", CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute})
+	c.clock = fakeClock
+
+	assert.True(t, c.allow())
+	c.done(false)
+	assert.True(t, c.allow(), "must stay closed below the failure threshold")
+	c.done(true)
+	c.done(false)
+	assert.True(t, c.allow(), "success must reset the failures")
+	c.done(false)
+	assert.False(t, c.allow(), "must open at the failure threshold")
+
+	fakeClock.Step(time.Minute)
+	assert.True(t, c.allow(), "must allow a probe once half-open")
+	assert.False(t, c.allow(), "must allow a single probe")
+	c.done(false)
+	assert.False(t, c.allow(), "failed probe must open again")
+
+	fakeClock.Step(time.Minute)
+	assert.True(t, c.allow())
+	c.done(true)
+	assert.True(t, c.allow(), "successful probe must close")
+
+	expected := strings.NewReader(`
+		# HELP apiserver_audit_webhook_circuit_breaker_transitions_total [ALPHA] Counter of state transitions of the circuit breaker of audit webhooks. State is the state transitioned to, 'closed', 'open' or 'half-open'.
+		# TYPE apiserver_audit_webhook_circuit_breaker_transitions_total counter
+		apiserver_audit_webhook_circuit_breaker_transitions_total{plugin="webhook",state="closed"} 1
+		apiserver_audit_webhook_circuit_breaker_transitions_total{plugin="webhook",state="half-open"} 2
+		apiserver_audit_webhook_circuit_breaker_transitions_total{plugin="webhook",state="open"} 2
+`)
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, expected, "apiserver_audit_webhook_circuit_breaker_transitions_total"); err != nil {
+		t.Error(err)
+	}
+}
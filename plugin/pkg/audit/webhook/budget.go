@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+)
+
+const (
+	// maxTrackedRequests is the maximum number of requests whose spent request
+	// budget is tracked. The least recently sent requests are forgotten first.
+	maxTrackedRequests = 4096
+	// trackedRequestTTL is the time after which a request is forgotten if no
+	// more of its events are sent, e.g. if the audit policy omits its final stage.
+	trackedRequestTTL = time.Hour
+)
+
+// requestBudgets tracks the time spent sending the events of requests, so that
+// all the events of a request together are not sent for longer than a budget.
+type requestBudgets struct {
+	budget time.Duration
+	// spent is the time spent sending the events of requests by audit ID.
+	spent *utilcache.LRUExpireCache
+}
+
+func newRequestBudgets(budget time.Duration) *requestBudgets {
+	return &requestBudgets{
+		budget: budget,
+		spent:  utilcache.NewLRUExpireCache(maxTrackedRequests),
+	}
+}
+
+// remaining returns the smallest remaining budget of the requests of the events.
+func (r *requestBudgets) remaining(events []*auditinternal.Event) time.Duration {
+	remaining := r.budget
+	for _, ev := range events {
+		if left := r.budget - r.spentOn(ev.AuditID); left < remaining {
+			remaining = left
+		}
+	}
+	return remaining
+}
+
+// spend records the time spent sending the events against the budgets of their
+// requests. Requests are forgotten once their final event is sent.
+func (r *requestBudgets) spend(events []*auditinternal.Event, d time.Duration) {
+	spentOn := map[types.UID]bool{}
+	for _, ev := range events {
+		if ev.Stage == auditinternal.StageResponseComplete || ev.Stage == auditinternal.StagePanic {
+			r.spent.Remove(ev.AuditID)
+			continue
+		}
+		if spentOn[ev.AuditID] {
+			continue
+		}
+		spentOn[ev.AuditID] = true
+		r.spent.Add(ev.AuditID, r.spentOn(ev.AuditID)+d, trackedRequestTTL)
+	}
+}
+
+func (r *requestBudgets) spentOn(auditID types.UID) time.Duration {
+	if spent, ok := r.spent.Get(auditID); ok {
+		return spent.(time.Duration)
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/audit"
+)
+
+const (
+	// ContentTypeJSON sends batches of events as JSON.
+	ContentTypeJSON = runtime.ContentTypeJSON
+	// ContentTypeProtobuf sends batches of events as Kubernetes protobuf,
+	// which is cheaper to serialize and smaller than JSON.
+	ContentTypeProtobuf = runtime.ContentTypeProtobuf
+)
+
+const (
+	// CompressionGzip compresses batches of events with gzip.
+	CompressionGzip = "gzip"
+
+	// DefaultCompressionThreshold is the default size of the smallest batch
+	// of events compressed, in bytes.
+	DefaultCompressionThreshold = 1024
+)
+
+// AllowedContentTypes are the content types known by the webhook backend.
+var AllowedContentTypes = []string{
+	ContentTypeJSON,
+	ContentTypeProtobuf,
+}
+
+// AllowedCompressions are the compressions known by the webhook backend.
+var AllowedCompressions = []string{
+	CompressionGzip,
+}
+
+// EncodingConfig configures how the webhook backend encodes batches of events.
+type EncodingConfig struct {
+	// ContentType is the media type batches are sent as, JSON if empty.
+	ContentType string
+	// Compression is the content encoding batches are compressed with, none
+	// if empty.
+	Compression string
+	// CompressionThreshold is the size in bytes of the smallest batch that is
+	// compressed. Smaller batches are sent uncompressed.
+	CompressionThreshold int
+}
+
+// newEncoder returns the encoder of batches of events of the group version.
+func newEncoder(config EncodingConfig, groupVersion schema.GroupVersion) (runtime.Encoder, error) {
+	switch config.Compression {
+	case "", CompressionGzip:
+	default:
+		return nil, fmt.Errorf("compression %q is not in list of known compressions (%s)",
+			config.Compression, strings.Join(AllowedCompressions, ","))
+	}
+	switch config.ContentType {
+	case "", ContentTypeJSON:
+		return audit.Codecs.LegacyCodec(groupVersion), nil
+	case ContentTypeProtobuf:
+		info, ok := runtime.SerializerInfoForMediaType(audit.Codecs.SupportedMediaTypes(), ContentTypeProtobuf)
+		if !ok {
+			return nil, fmt.Errorf("no serializer for content type %q", ContentTypeProtobuf)
+		}
+		return audit.Codecs.EncoderForVersion(info.Serializer, groupVersion), nil
+	default:
+		return nil, fmt.Errorf("content type %q is not in list of known content types (%s)",
+			config.ContentType, strings.Join(AllowedContentTypes, ","))
+	}
+}
+
+// compress compresses the encoded batch, if configured and not smaller than
+// the threshold, returning the content encoding of the result.
+func compress(config EncodingConfig, body []byte) ([]byte, string, error) {
+	if config.Compression == "" || len(body) < config.CompressionThreshold {
+		return body, "", nil
+	}
+	var buf bytes.Buffer
+	// Favor speed, batches are compressed on the audit path.
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := zw.Write(body); err != nil {
+		return nil, "", err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), config.Compression, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// DefaultFailoverThreshold is the default number of consecutive batches
+	// failing to be sent to the primary webhook that fails over.
+	DefaultFailoverThreshold = 3
+	// DefaultFailoverProbeInterval is the default time between probes of the
+	// primary webhook while failed over.
+	DefaultFailoverProbeInterval = time.Minute
+
+	// secondaryName is the name of the secondary webhook in metrics and logs.
+	secondaryName = PluginName + "_secondary"
+)
+
+// FailoverConfig configures failing over from the primary webhook to the
+// secondary webhook.
+type FailoverConfig struct {
+	// FailureThreshold is the number of consecutive batches failing to be sent
+	// to the primary webhook, after all retries, after which batches are sent
+	// to the secondary webhook.
+	FailureThreshold int
+	// ProbeInterval is the time after which a batch is sent to the primary
+	// webhook again while failed over, failing back if it is sent.
+	ProbeInterval time.Duration
+}
+
+// failoverBackend sends batches to the primary webhook, failing over to the
+// secondary webhook while the primary is down.
+type failoverBackend struct {
+	primary   *backend
+	secondary *backend
+	config    FailoverConfig
+	clock     clock.PassiveClock
+
+	// mu serializes choosing the webhook batches are sent to.
+	mu sync.Mutex
+	// failures is the number of consecutive batches failed by the primary.
+	failures int
+	// failedOver is whether batches are sent to the secondary.
+	failedOver bool
+	// probedAt is the time the primary was last sent a batch while failed over.
+	probedAt time.Time
+}
+
+var _ audit.Backend = &failoverBackend{}
+
+// NewFailoverBackend returns an audit backend that sends events over HTTP to
+// the primary external service, or to the secondary external service after
+// failing to send to the primary, until the primary recovers.
+func NewFailoverBackend(kubeConfigFile, secondaryKubeConfigFile string, groupVersion schema.GroupVersion, encodingConfig EncodingConfig, retryConfig RetryConfig, failoverConfig FailoverConfig, customDial utilnet.DialFunc) (audit.Backend, error) {
+	primary, err := newStaticBackend(kubeConfigFile, PluginName, groupVersion, encodingConfig, retryConfig, customDial)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := newStaticBackend(secondaryKubeConfigFile, secondaryName, groupVersion, encodingConfig, retryConfig, customDial)
+	if err != nil {
+		return nil, fmt.Errorf("secondary webhook: %v", err)
+	}
+	return newFailoverBackend(primary, secondary, failoverConfig), nil
+}
+
+func newFailoverBackend(primary, secondary *backend, config FailoverConfig) *failoverBackend {
+	return &failoverBackend{
+		primary:   primary,
+		secondary: secondary,
+		config:    config,
+		clock:     clock.RealClock{},
+	}
+}
+
+func (b *failoverBackend) ProcessEvents(ev ...*auditinternal.Event) bool {
+	if err := b.send(ev...); err != nil {
+		audit.ObserveDroppedEvents(b.String(), audit.DropReasonSendFailed, len(ev))
+		audit.HandlePluginError(b.String(), err, ev...)
+		return false
+	}
+	return true
+}
+
+func (b *failoverBackend) send(ev ...*auditinternal.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failedOver && b.clock.Since(b.probedAt) < b.config.ProbeInterval {
+		return b.secondary.send(ev...)
+	}
+	if b.failedOver {
+		b.probedAt = b.clock.Now()
+	}
+	err := b.primary.send(ev...)
+	if err == nil {
+		if b.failedOver {
+			klog.InfoS("Audit webhook recovered, failing back", "webhook", b.primary.name)
+		}
+		b.failures = 0
+		b.failedOver = false
+		return nil
+	}
+	if !b.failedOver {
+		b.failures++
+		if b.failures < b.config.FailureThreshold {
+			return err
+		}
+		klog.InfoS("Audit webhook is down, failing over", "webhook", b.primary.name, "secondary", b.secondary.name, "failures", b.failures)
+		b.failedOver = true
+		b.probedAt = b.clock.Now()
+	}
+	if secondaryErr := b.secondary.send(ev...); secondaryErr != nil {
+		return fmt.Errorf("%v; secondary webhook: %v", err, secondaryErr)
+	}
+	return nil
+}
+
+func (b *failoverBackend) Run(stopCh <-chan struct{}) error {
+	return nil
+}
+
+func (b *failoverBackend) Shutdown() {
+	// nothing to do here
+}
+
+// HealthCheck reports the webhook batches are sent to failing.
+func (b *failoverBackend) HealthCheck() error {
+	b.mu.Lock()
+	failedOver := b.failedOver
+	b.mu.Unlock()
+	if failedOver {
+		return b.secondary.HealthCheck()
+	}
+	return b.primary.HealthCheck()
+}
+
+func (b *failoverBackend) String() string {
+	return b.primary.String()
+}
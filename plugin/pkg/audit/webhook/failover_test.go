@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+type testEndpoint struct {
+	down     int32
+	requests int32
+}
+
+func (e *testEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&e.requests, 1)
+	if atomic.LoadInt32(&e.down) == 1 {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (e *testEndpoint) setDown(down bool) {
+	var v int32
+	if down {
+		v = 1
+	}
+	atomic.StoreInt32(&e.down, v)
+}
+
+func (e *testEndpoint) takeRequests() int32 {
+	return atomic.SwapInt32(&e.requests, 0)
+}
+
+func TestFailoverBackend(t *testing.T) {
+	primary, secondary := &testEndpoint{}, &testEndpoint{}
+	ps, ss := httptest.NewServer(primary), httptest.NewServer(secondary)
+	defer ps.Close()
+	defer ss.Close()
+
+	retryConfig := RetryConfig{Backoff: wait.Backoff{Duration: time.Millisecond, Steps: 1}}
+	b := newFailoverBackend(
+		newWebhookWithRetryConfig(t, ps.URL, auditv1.SchemeGroupVersion, retryConfig),
+		newWebhookWithRetryConfig(t, ss.URL, auditv1.SchemeGroupVersion, retryConfig),
+		FailoverConfig{FailureThreshold: 2, ProbeInterval: time.Minute},
+	)
+	fakeClock := testingclock.NewFakePassiveClock(time.Now())
+	b.clock = fakeClock
+
+	assert.True(t, b.ProcessEvents(&auditinternal.Event{}))
+	assert.Equal(t, int32(1), primary.takeRequests())
+
+	primary.setDown(true)
+	assert.False(t, b.ProcessEvents(&auditinternal.Event{}), "batches must fail until the threshold is reached")
+	assert.Zero(t, secondary.takeRequests())
+	assert.True(t, b.ProcessEvents(&auditinternal.Event{}), "the batch reaching the threshold must be sent to the secondary")
+	assert.Equal(t, int32(1), secondary.takeRequests())
+	assert.NoError(t, b.HealthCheck(), "failed over backend must be healthy while the secondary is")
+
+	primary.takeRequests()
+	assert.True(t, b.ProcessEvents(&auditinternal.Event{}))
+	assert.Zero(t, primary.takeRequests(), "primary must not be sent batches until probed")
+	assert.Equal(t, int32(1), secondary.takeRequests())
+
+	fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+	assert.True(t, b.ProcessEvents(&auditinternal.Event{}), "batch failing the probe must be sent to the secondary")
+	assert.Equal(t, int32(1), primary.takeRequests())
+	assert.Equal(t, int32(1), secondary.takeRequests())
+
+	primary.setDown(false)
+	fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+	assert.True(t, b.ProcessEvents(&auditinternal.Event{}))
+	assert.True(t, b.ProcessEvents(&auditinternal.Event{}))
+	assert.Equal(t, int32(2), primary.takeRequests(), "primary must be sent batches once recovered")
+	assert.Zero(t, secondary.takeRequests())
+}
+
+func TestFailoverBackendBothDown(t *testing.T) {
+	primary, secondary := &testEndpoint{}, &testEndpoint{}
+	primary.setDown(true)
+	secondary.setDown(true)
+	ps, ss := httptest.NewServer(primary), httptest.NewServer(secondary)
+	defer ps.Close()
+	defer ss.Close()
+
+	retryConfig := RetryConfig{Backoff: wait.Backoff{Duration: time.Millisecond, Steps: 1}}
+	b := newFailoverBackend(
+		newWebhookWithRetryConfig(t, ps.URL, auditv1.SchemeGroupVersion, retryConfig),
+		newWebhookWithRetryConfig(t, ss.URL, auditv1.SchemeGroupVersion, retryConfig),
+		FailoverConfig{FailureThreshold: 1, ProbeInterval: time.Minute},
+	)
+	assert.False(t, b.ProcessEvents(&auditinternal.Event{}))
+	assert.Error(t, b.HealthCheck())
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	subsystem = "apiserver_audit"
+)
+
+/*
+ * By default, all the following metrics are defined as falling under
+ * ALPHA stability level https://github.com/kubernetes/enhancements/blob/master/keps/sig-instrumentation/1209-metrics-stability/kubernetes-control-plane-metrics-stability.md#stability-classes)
+ *
+ * Promoting the stability level of the metric is a responsibility of the component owner, since it
+ * involves explicitly acknowledging support for the metric across multiple releases, in accordance with
+ * the metric stability policy.
+ */
+var (
+	circuitBreakerTransitionCounter = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "webhook_circuit_breaker_transitions_total",
+			Help: "Counter of state transitions of the circuit breaker of audit webhooks. " +
+				"State is the state transitioned to, 'closed', 'open' or 'half-open'.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin", "state"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(circuitBreakerTransitionCounter)
+}
+
+// observeCircuitBreakerTransition updates the relevant prometheus metrics for
+// a state transition of the circuit breaker of a webhook.
+func observeCircuitBreakerTransition(plugin, state string) {
+	circuitBreakerTransitionCounter.WithLabelValues(plugin, state).Inc()
+}
@@ -19,9 +19,12 @@ package webhook
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -40,6 +43,15 @@ const (
 	// DefaultInitialBackoffDelay is the default amount of time to wait before
 	// retrying sending audit events through a webhook.
 	DefaultInitialBackoffDelay = 10 * time.Second
+	// DefaultBackoffFactor is the default factor the time to wait before
+	// retrying is multiplied by after each retry.
+	DefaultBackoffFactor = 1.5
+	// DefaultBackoffJitter is the default jitter of the time to wait before retrying.
+	DefaultBackoffJitter = 0.2
+	// DefaultMaxAttempts is the default maximum number of attempts to send a batch.
+	DefaultMaxAttempts = 5
+	// DefaultCircuitBreakerOpenDuration is the default time the circuit breaker stays open.
+	DefaultCircuitBreakerOpenDuration = 30 * time.Second
 )
 
 func init() {
@@ -77,31 +89,109 @@ func loadWebhook(configFile string, groupVersion schema.GroupVersion, retryBacko
 	return w, nil
 }
 
+// RetryConfig configures how the webhook backend retries sending a batch of events.
+type RetryConfig struct {
+	// Backoff is the exponential backoff between attempts to send a batch,
+	// including its jitter and the maximum number of attempts.
+	Backoff wait.Backoff
+	// Budget is the maximum time spent sending a batch, including retries. No
+	// limit if zero.
+	Budget time.Duration
+	// StageTimeouts, if set, override Budget for the events of the given stages.
+	// A batch of events of several stages is limited by the smallest of their
+	// limits. Meant for the blocking mode, in which each event is sent while
+	// serving its request.
+	StageTimeouts map[auditinternal.Stage]time.Duration
+	// RequestBudget, if set, is the maximum time spent sending the events of
+	// a request, over all of its stages, so that in the blocking mode audit
+	// delivery cannot consume the entire timeout of the request. No limit if zero.
+	RequestBudget time.Duration
+	// CircuitBreaker, if set, stops sending batches while the webhook is down.
+	CircuitBreaker *CircuitBreakerConfig
+}
+
+// DefaultRetryConfig returns a retry configuration with the default exponential
+// backoff, no retry budget and no circuit breaker.
+func DefaultRetryConfig(initialBackoffDelay time.Duration) RetryConfig {
+	return RetryConfig{
+		Backoff: webhook.DefaultRetryBackoffWithInitialDelay(initialBackoffDelay),
+	}
+}
+
 type backend struct {
-	w    *webhook.GenericWebhook
-	name string
+	w             *webhook.GenericWebhook
+	name          string
+	budget        time.Duration
+	stageTimeouts map[auditinternal.Stage]time.Duration
+	// requests is nil if there is no request budget.
+	requests *requestBudgets
+	// encoder encodes batches as encoding.ContentType, nil if the REST client
+	// encodes them.
+	encoder  runtime.Encoder
+	encoding EncodingConfig
+	// breaker is nil if the circuit breaker is disabled.
+	breaker *circuitBreaker
+
+	// mu guards lastErr, the error sending the last batch, nil if it was sent.
+	mu      sync.Mutex
+	lastErr error
+}
+
+var _ audit.HealthChecker = &backend{}
+
+func newBackend(w *webhook.GenericWebhook, name string, retryConfig RetryConfig) *backend {
+	b := &backend{w: w, name: name, budget: retryConfig.Budget, stageTimeouts: retryConfig.StageTimeouts}
+	if retryConfig.RequestBudget > 0 {
+		b.requests = newRequestBudgets(retryConfig.RequestBudget)
+	}
+	if retryConfig.CircuitBreaker != nil {
+		b.breaker = newCircuitBreaker(name, *retryConfig.CircuitBreaker)
+	}
+	return b
 }
 
 // NewDynamicBackend returns an audit backend configured from a REST client that
 // sends events over HTTP to an external service.
-func NewDynamicBackend(rc *rest.RESTClient, retryBackoff wait.Backoff) audit.Backend {
-	return &backend{
-		w: &webhook.GenericWebhook{
-			RestClient:   rc,
-			RetryBackoff: retryBackoff,
-			ShouldRetry:  retryOnError,
-		},
-		name: fmt.Sprintf("dynamic_%s", PluginName),
+func NewDynamicBackend(rc *rest.RESTClient, retryConfig RetryConfig) audit.Backend {
+	return newBackend(&webhook.GenericWebhook{
+		RestClient:   rc,
+		RetryBackoff: retryConfig.Backoff,
+		ShouldRetry:  retryOnError,
+	}, fmt.Sprintf("dynamic_%s", PluginName), retryConfig)
+}
+
+// NewSinkBackend returns an audit backend that sends events over HTTP to the
+// webhook of the audit sink of the given name, configured by clientConfig.
+func NewSinkBackend(name string, clientConfig *rest.Config, groupVersion schema.GroupVersion, retryConfig RetryConfig) (audit.Backend, error) {
+	w, err := webhook.NewGenericWebhook(audit.Scheme, audit.Codecs, clientConfig,
+		[]schema.GroupVersion{groupVersion}, retryConfig.Backoff)
+	if err != nil {
+		return nil, err
 	}
+	w.ShouldRetry = retryOnError
+	return newBackend(w, fmt.Sprintf("dynamic_%s_%s", PluginName, name), retryConfig), nil
 }
 
 // NewBackend returns an audit backend that sends events over HTTP to an external service.
-func NewBackend(kubeConfigFile string, groupVersion schema.GroupVersion, retryBackoff wait.Backoff, customDial utilnet.DialFunc) (audit.Backend, error) {
-	w, err := loadWebhook(kubeConfigFile, groupVersion, retryBackoff, customDial)
+func NewBackend(kubeConfigFile string, groupVersion schema.GroupVersion, encodingConfig EncodingConfig, retryConfig RetryConfig, customDial utilnet.DialFunc) (audit.Backend, error) {
+	return newStaticBackend(kubeConfigFile, PluginName, groupVersion, encodingConfig, retryConfig, customDial)
+}
+
+func newStaticBackend(kubeConfigFile, name string, groupVersion schema.GroupVersion, encodingConfig EncodingConfig, retryConfig RetryConfig, customDial utilnet.DialFunc) (*backend, error) {
+	encoder, err := newEncoder(encodingConfig, groupVersion)
+	if err != nil {
+		return nil, err
+	}
+	w, err := loadWebhook(kubeConfigFile, groupVersion, retryConfig.Backoff, customDial)
 	if err != nil {
 		return nil, err
 	}
-	return &backend{w: w, name: PluginName}, nil
+	b := newBackend(w, name, retryConfig)
+	if encodingConfig.ContentType == "" {
+		encodingConfig.ContentType = ContentTypeJSON
+	}
+	b.encoder, b.encoding = encoder, encodingConfig
+	return b, nil
 }
 
 func (b *backend) Run(stopCh <-chan struct{}) error {
@@ -113,19 +203,81 @@ func (b *backend) Shutdown() {
 }
 
 func (b *backend) ProcessEvents(ev ...*auditinternal.Event) bool {
-	if err := b.processEvents(ev...); err != nil {
+	if err := b.send(ev...); err != nil {
+		audit.ObserveDroppedEvents(b.String(), audit.DropReasonSendFailed, len(ev))
 		audit.HandlePluginError(b.String(), err, ev...)
 		return false
 	}
 	return true
 }
 
+// send sends the events, unless the circuit breaker is open, recording the
+// outcome for the circuit breaker and the health check.
+func (b *backend) send(ev ...*auditinternal.Event) error {
+	if b.breaker != nil && !b.breaker.allow() {
+		err := errors.New("circuit breaker open, webhook is down")
+		b.setLastErr(err)
+		return err
+	}
+	err := b.processEvents(ev...)
+	if b.breaker != nil {
+		b.breaker.done(err == nil)
+	}
+	b.setLastErr(err)
+	return err
+}
+
+func (b *backend) setLastErr(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastErr = err
+}
+
+// HealthCheck reports the webhook failing, until a batch is sent again.
+func (b *backend) HealthCheck() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.lastErr != nil {
+		return fmt.Errorf("failed to send the last batch of events: %v", b.lastErr)
+	}
+	return nil
+}
+
 func (b *backend) processEvents(ev ...*auditinternal.Event) error {
 	var list auditinternal.EventList
 	for _, e := range ev {
 		list.Items = append(list.Items, *e)
 	}
-	return b.w.WithExponentialBackoff(context.Background(), func() rest.Result {
+	var body []byte
+	var contentEncoding string
+	if b.encoder != nil {
+		// Encode the batch once for all attempts.
+		var err error
+		if body, err = runtime.Encode(b.encoder, &list); err != nil {
+			return err
+		}
+		if body, contentEncoding, err = compress(b.encoding, body); err != nil {
+			return err
+		}
+	}
+	if b.requests != nil {
+		// Also forgets the requests of final events that are not sent.
+		start := time.Now()
+		defer func() {
+			b.requests.spend(ev, time.Since(start))
+		}()
+	}
+	timeout, err := b.timeout(ev)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return b.w.WithExponentialBackoff(ctx, func() rest.Result {
 		trace := utiltrace.New("Call Audit Events webhook",
 			utiltrace.Field{"name", b.name},
 			utiltrace.Field{"event-count", len(list.Items)})
@@ -134,10 +286,42 @@ func (b *backend) processEvents(ev ...*auditinternal.Event) error {
 		// allow enough time for the serialization/deserialization of audit events, which
 		// contain nested request and response objects plus additional event fields.
 		defer trace.LogIfLong(time.Duration(50+25*len(list.Items)) * time.Millisecond)
-		return b.w.RestClient.Post().Body(&list).Do(context.TODO())
+		if b.encoder == nil {
+			return b.w.RestClient.Post().Body(&list).Do(ctx)
+		}
+		req := b.w.RestClient.Post().SetHeader("Content-Type", b.encoding.ContentType)
+		if contentEncoding != "" {
+			req.SetHeader("Content-Encoding", contentEncoding)
+		}
+		return req.Body(body).Do(ctx)
 	}).Error()
 }
 
+// timeout returns the maximum time spent sending the batch of events, zero
+// meaning no limit. It fails if the budget of a request of the events is spent.
+func (b *backend) timeout(ev []*auditinternal.Event) (time.Duration, error) {
+	var timeout time.Duration
+	for _, e := range ev {
+		t := b.budget
+		if stageTimeout, ok := b.stageTimeouts[e.Stage]; ok {
+			t = stageTimeout
+		}
+		if t > 0 && (timeout == 0 || t < timeout) {
+			timeout = t
+		}
+	}
+	if b.requests != nil {
+		remaining := b.requests.remaining(ev)
+		if remaining <= 0 {
+			return 0, fmt.Errorf("request budget of %v spent", b.requests.budget)
+		}
+		if timeout == 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+	return timeout, nil
+}
+
 func (b *backend) String() string {
 	return b.name
 }
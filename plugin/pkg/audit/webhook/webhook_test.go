@@ -17,6 +17,7 @@ limitations under the License.
 package webhook
 
 import (
+	"compress/gzip"
 	stdjson "encoding/json"
 	"fmt"
 	"io"
@@ -25,6 +26,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -34,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
@@ -91,6 +94,21 @@ func (t *testWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func newWebhook(t *testing.T, endpoint string, groupVersion schema.GroupVersion) *backend {
+	return newWebhookWithRetryConfig(t, endpoint, groupVersion, RetryConfig{
+		Backoff: wait.Backoff{
+			Duration: 500 * time.Millisecond,
+			Factor:   1.5,
+			Jitter:   0.2,
+			Steps:    5,
+		},
+	})
+}
+
+func newWebhookWithRetryConfig(t *testing.T, endpoint string, groupVersion schema.GroupVersion, retryConfig RetryConfig) *backend {
+	return newWebhookWithConfig(t, endpoint, groupVersion, EncodingConfig{}, retryConfig)
+}
+
+func newWebhookWithConfig(t *testing.T, endpoint string, groupVersion schema.GroupVersion, encodingConfig EncodingConfig, retryConfig RetryConfig) *backend {
 	config := v1.Config{
 		Clusters: []v1.NamedCluster{
 			{Cluster: v1.Cluster{Server: endpoint, InsecureSkipTLSVerify: true}},
@@ -107,13 +125,7 @@ func newWebhook(t *testing.T, endpoint string, groupVersion schema.GroupVersion)
 	// NOTE(ericchiang): Do we need to use a proper serializer?
 	require.NoError(t, stdjson.NewEncoder(f).Encode(config), "writing kubeconfig")
 
-	retryBackoff := wait.Backoff{
-		Duration: 500 * time.Millisecond,
-		Factor:   1.5,
-		Jitter:   0.2,
-		Steps:    5,
-	}
-	b, err := NewBackend(f.Name(), groupVersion, retryBackoff, nil)
+	b, err := NewBackend(f.Name(), groupVersion, encodingConfig, retryConfig, nil)
 	require.NoError(t, err, "initializing backend")
 
 	return b.(*backend)
@@ -137,3 +149,191 @@ func TestWebhook(t *testing.T) {
 		require.True(t, gotEvents, fmt.Sprintf("no events received, apiVersion: %s", version))
 	}
 }
+
+func TestWebhookProtobuf(t *testing.T) {
+	var contentType string
+	var ids []types.UID
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		list := &auditv1.EventList{}
+		if err := runtime.DecodeInto(audit.Codecs.UniversalDeserializer(), body, list); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, event := range list.Items {
+			ids = append(ids, event.AuditID)
+		}
+		io.WriteString(w, "{}")
+	}))
+	defer s.Close()
+
+	backend := newWebhookWithConfig(t, s.URL, auditv1.SchemeGroupVersion, EncodingConfig{ContentType: ContentTypeProtobuf}, RetryConfig{
+		Backoff: wait.Backoff{Duration: time.Millisecond, Steps: 1},
+	})
+	require.NoError(t, backend.processEvents(&auditinternal.Event{AuditID: "1"}, &auditinternal.Event{AuditID: "2"}))
+	assert.Equal(t, ContentTypeProtobuf, contentType)
+	assert.Equal(t, []types.UID{"1", "2"}, ids)
+}
+
+func TestWebhookUnknownContentType(t *testing.T) {
+	_, err := NewBackend("", auditv1.SchemeGroupVersion, EncodingConfig{ContentType: "application/xml"}, DefaultRetryConfig(time.Second), nil)
+	assert.Error(t, err)
+}
+
+func TestWebhookCompression(t *testing.T) {
+	var encodings []string
+	var sizes []int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get("Content-Encoding")
+		encodings = append(encodings, encoding)
+		body := r.Body
+		if encoding == CompressionGzip {
+			zr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = zr
+		}
+		data, err := ioutil.ReadAll(body)
+		require.NoError(t, err)
+		list := &auditv1.EventList{}
+		if err := runtime.DecodeInto(audit.Codecs.UniversalDeserializer(), data, list); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sizes = append(sizes, len(list.Items))
+		io.WriteString(w, "{}")
+	}))
+	defer s.Close()
+
+	backend := newWebhookWithConfig(t, s.URL, auditv1.SchemeGroupVersion, EncodingConfig{
+		Compression:          CompressionGzip,
+		CompressionThreshold: 1024,
+	}, RetryConfig{
+		Backoff: wait.Backoff{Duration: time.Millisecond, Steps: 1},
+	})
+	large := make([]*auditinternal.Event, 20)
+	for i := range large {
+		large[i] = &auditinternal.Event{AuditID: types.UID(fmt.Sprint(i)), RequestURI: "/api/v1/namespaces/default/pods"}
+	}
+	require.NoError(t, backend.processEvents(large...))
+	require.NoError(t, backend.processEvents(&auditinternal.Event{AuditID: "small"}))
+	assert.Equal(t, []string{CompressionGzip, ""}, encodings, "only batches above the threshold must be compressed")
+	assert.Equal(t, []int{20, 1}, sizes)
+}
+
+func TestWebhookUnknownCompression(t *testing.T) {
+	_, err := NewBackend("", auditv1.SchemeGroupVersion, EncodingConfig{Compression: "zstd"}, DefaultRetryConfig(time.Second), nil)
+	assert.Error(t, err)
+}
+
+func TestWebhookHealthCheck(t *testing.T) {
+	var fail int32 = 1
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "{}")
+	}))
+	defer s.Close()
+
+	backend := newWebhookWithRetryConfig(t, s.URL, auditv1.SchemeGroupVersion, RetryConfig{
+		Backoff: wait.Backoff{Duration: time.Millisecond, Steps: 1},
+	})
+	assert.NoError(t, backend.HealthCheck())
+	assert.False(t, backend.ProcessEvents(&auditinternal.Event{}))
+	assert.Error(t, backend.HealthCheck(), "failing webhook must be reported")
+	atomic.StoreInt32(&fail, 0)
+	assert.True(t, backend.ProcessEvents(&auditinternal.Event{}))
+	assert.NoError(t, backend.HealthCheck(), "webhook must be healthy once a batch is sent")
+}
+
+func TestWebhookRetryBudget(t *testing.T) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	backend := newWebhookWithRetryConfig(t, s.URL, auditv1.SchemeGroupVersion, RetryConfig{
+		Backoff: wait.Backoff{Duration: 50 * time.Millisecond, Factor: 1, Steps: 100},
+		Budget:  200 * time.Millisecond,
+	})
+	start := time.Now()
+	require.Error(t, backend.processEvents(&auditinternal.Event{}))
+	assert.Less(t, time.Since(start), wait.ForeverTestTimeout, "retries must stop once the budget is spent")
+	assert.Less(t, atomic.LoadInt32(&requests), int32(100))
+}
+
+func TestWebhookStageTimeouts(t *testing.T) {
+	b := &backend{
+		budget: time.Second,
+		stageTimeouts: map[auditinternal.Stage]time.Duration{
+			auditinternal.StageRequestReceived:  100 * time.Millisecond,
+			auditinternal.StageResponseComplete: 0,
+		},
+	}
+	for _, tc := range []struct {
+		stages   []auditinternal.Stage
+		expected time.Duration
+	}{
+		{[]auditinternal.Stage{auditinternal.StageRequestReceived}, 100 * time.Millisecond},
+		{[]auditinternal.Stage{auditinternal.StageResponseStarted}, time.Second},
+		{[]auditinternal.Stage{auditinternal.StageResponseComplete}, 0},
+		{[]auditinternal.Stage{auditinternal.StageResponseStarted, auditinternal.StageRequestReceived}, 100 * time.Millisecond},
+		{[]auditinternal.Stage{auditinternal.StageResponseComplete, auditinternal.StageResponseStarted}, time.Second},
+	} {
+		var events []*auditinternal.Event
+		for _, stage := range tc.stages {
+			events = append(events, &auditinternal.Event{Stage: stage})
+		}
+		timeout, err := b.timeout(events)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, timeout, "stages %v", tc.stages)
+	}
+}
+
+func TestWebhookRequestBudget(t *testing.T) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer s.Close()
+
+	backend := newWebhookWithRetryConfig(t, s.URL, auditv1.SchemeGroupVersion, RetryConfig{
+		Backoff:       wait.Backoff{Duration: 10 * time.Millisecond, Factor: 1, Steps: 1},
+		RequestBudget: 150 * time.Millisecond,
+	})
+	event := func(auditID string, stage auditinternal.Stage) *auditinternal.Event {
+		return &auditinternal.Event{AuditID: types.UID(auditID), Stage: stage}
+	}
+
+	require.NoError(t, backend.processEvents(event("1", auditinternal.StageRequestReceived)))
+	require.Error(t, backend.processEvents(event("1", auditinternal.StageResponseStarted)),
+		"sending must stop once the request budget is spent")
+	assert.EqualError(t, backend.processEvents(event("1", auditinternal.StageResponseComplete)), "request budget of 150ms spent")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests), "no event must be sent once the request budget is spent")
+
+	require.NoError(t, backend.processEvents(event("2", auditinternal.StageRequestReceived)), "requests have separate budgets")
+	assert.Equal(t, time.Duration(0), backend.requests.spentOn("1"), "requests must be forgotten after their final event")
+}
+
+func TestWebhookCircuitBreaker(t *testing.T) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	backend := newWebhookWithRetryConfig(t, s.URL, auditv1.SchemeGroupVersion, RetryConfig{
+		Backoff:        wait.Backoff{Duration: time.Millisecond, Steps: 1},
+		CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Hour},
+	})
+	assert.False(t, backend.ProcessEvents(&auditinternal.Event{}))
+	assert.False(t, backend.ProcessEvents(&auditinternal.Event{}))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "open circuit breaker must not send events")
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exec implements the authenticator.Token interface using a short-lived external
+// command, for servers for which running a token review webhook is impractical.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+)
+
+// AudiencesEnv is the environment variable through which the command is passed the comma
+// separated audiences a token is requested to be valid for. It is unset if the request is not
+// audience limited.
+const AudiencesEnv = "KUBERNETES_AUTHENTICATION_AUDIENCES"
+
+// maxStderrLength bounds the standard error of a failed command included in errors.
+const maxStderrLength = 256
+
+// Ensure Authenticator implements the authenticator.Token interface.
+var _ authenticator.Token = (*Authenticator)(nil)
+
+// Authenticator authenticates bearer tokens by running a command for each of them. The command
+// reads the token from its standard input and writes the status of a TokenReview of the
+// authentication.k8s.io/v1 API as JSON to its standard output, e.g.
+//
+//	{"authenticated": true, "user": {"username": "jane", "groups": ["developers"]}}
+//
+// A command exiting with a non-zero code fails the authentication with an error.
+type Authenticator struct {
+	command      string
+	args         []string
+	implicitAuds authenticator.Audiences
+	timeout      time.Duration
+}
+
+// New returns an authenticator that runs command with args for every token, limited to timeout.
+// It is recommend to wrap this authenticator with the token cache authenticator implemented in
+// k8s.io/apiserver/pkg/authentication/token/cache.
+func New(command string, args []string, implicitAuds authenticator.Audiences, timeout time.Duration) (*Authenticator, error) {
+	path, err := osexec.LookPath(command)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find authentication command %q: %v", command, err)
+	}
+	klog.V(4).Infof("Configure authentication command: %s", path)
+	return &Authenticator{command: path, args: args, implicitAuds: implicitAuds, timeout: timeout}, nil
+}
+
+// AuthenticateToken implements the authenticator.Token interface. Audiences are validated
+// like the webhook token authenticator does: if the request is audience limited, the
+// audiences returned by the command, or the implicit audiences if it returns none, must
+// intersect with the requested ones.
+func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	wantAuds, checkAuds := authenticator.AudiencesFrom(ctx)
+
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+
+	status, err := a.run(ctx, token, wantAuds)
+	if err != nil {
+		klog.Errorf("Failed to run authentication command: %v", err)
+		return nil, false, err
+	}
+
+	var auds authenticator.Audiences
+	if checkAuds {
+		gotAuds := a.implicitAuds
+		if len(status.Audiences) > 0 {
+			gotAuds = status.Audiences
+		}
+		auds = wantAuds.Intersect(gotAuds)
+		if len(auds) == 0 {
+			return nil, false, nil
+		}
+	}
+
+	if !status.Authenticated {
+		if len(status.Error) > 0 {
+			return nil, false, authenticator.NewTokenRejectedError(errors.New(status.Error))
+		}
+		return nil, false, nil
+	}
+	if len(status.User.Username) == 0 {
+		return nil, false, errors.New("authentication command returned no user for an authenticated token")
+	}
+
+	var extra map[string][]string
+	if status.User.Extra != nil {
+		extra = make(map[string][]string, len(status.User.Extra))
+		for k, v := range status.User.Extra {
+			extra[k] = v
+		}
+	}
+
+	return &authenticator.Response{
+		User: &user.DefaultInfo{
+			Name:   status.User.Username,
+			UID:    status.User.UID,
+			Groups: status.User.Groups,
+			Extra:  extra,
+		},
+		Audiences: auds,
+	}, true, nil
+}
+
+// run runs the command for token and decodes its output. The token is passed on standard input
+// only, so that it does not show up in the process list.
+func (a *Authenticator) run(ctx context.Context, token string, auds authenticator.Audiences) (*authenticationv1.TokenReviewStatus, error) {
+	cmd := osexec.CommandContext(ctx, a.command, a.args...)
+	cmd.Env = os.Environ()
+	if len(auds) > 0 {
+		cmd.Env = append(cmd.Env, AudiencesEnv+"="+strings.Join(auds, ","))
+	}
+	cmd.Stdin = strings.NewReader(token)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("authentication command %q did not finish in time: %v", a.command, ctx.Err())
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxStderrLength {
+			msg = msg[:maxStderrLength] + "..."
+		}
+		return nil, fmt.Errorf("authentication command %q failed: %v: %s", a.command, err, msg)
+	}
+
+	status := &authenticationv1.TokenReviewStatus{}
+	if err := json.Unmarshal(stdout.Bytes(), status); err != nil {
+		return nil, fmt.Errorf("unable to decode the output of authentication command %q: %v", a.command, err)
+	}
+	return status, nil
+}
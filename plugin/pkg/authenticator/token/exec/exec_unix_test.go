@@ -0,0 +1,152 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// plugin accepts the token "good" for any audiences, the token "api" only for the audience api,
+// and rejects other tokens. The token "fail" makes it exit with an error, "slow" makes it hang.
+const plugin = `#!/bin/sh
+token=$(cat)
+case "$token" in
+good)
+	echo '{"authenticated": true, "user": {"username": "jane", "uid": "1", "groups": ["developers"], "extra": {"scopes": ["read"]}}}' ;;
+api)
+	[ "$KUBERNETES_AUTHENTICATION_AUDIENCES" = "api,other" ] || exit 1
+	echo '{"authenticated": true, "user": {"username": "jane"}, "audiences": ["api"]}' ;;
+fail)
+	echo "backend unavailable" >&2
+	exit 1 ;;
+slow)
+	exec sleep 10 ;;
+*)
+	echo '{"authenticated": false, "error": "unknown token"}' ;;
+esac
+`
+
+func TestExecAuthenticator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plugin.sh")
+	if err := os.WriteFile(path, []byte(plugin), 0700); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := New(path, nil, authenticator.Audiences{"implicit"}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name         string
+		token        string
+		auds         authenticator.Audiences
+		expectUser   user.Info
+		expectAuds   authenticator.Audiences
+		expectReject bool
+		expectErr    bool
+	}{
+		{
+			name:       "authenticated",
+			token:      "good",
+			expectUser: &user.DefaultInfo{Name: "jane", UID: "1", Groups: []string{"developers"}, Extra: map[string][]string{"scopes": {"read"}}},
+		},
+		{
+			name:       "authenticated for implicit audience",
+			token:      "good",
+			auds:       authenticator.Audiences{"implicit"},
+			expectUser: &user.DefaultInfo{Name: "jane", UID: "1", Groups: []string{"developers"}, Extra: map[string][]string{"scopes": {"read"}}},
+			expectAuds: authenticator.Audiences{"implicit"},
+		},
+		{
+			name:  "not authenticated for other audience",
+			token: "good",
+			auds:  authenticator.Audiences{"other"},
+		},
+		{
+			name:       "audiences passed to the command",
+			token:      "api",
+			auds:       authenticator.Audiences{"api", "other"},
+			expectUser: &user.DefaultInfo{Name: "jane"},
+			expectAuds: authenticator.Audiences{"api"},
+		},
+		{
+			name:         "rejected",
+			token:        "bad",
+			expectReject: true,
+		},
+		{
+			name:      "command failure",
+			token:     "fail",
+			expectErr: true,
+		},
+		{
+			name:      "command timeout",
+			token:     "slow",
+			expectErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if len(tc.auds) > 0 {
+				ctx = authenticator.WithAudiences(ctx, tc.auds)
+			}
+			resp, ok, err := auth.AuthenticateToken(ctx, tc.token)
+			switch {
+			case tc.expectReject:
+				if ok || !authenticator.IsTokenRejected(err) {
+					t.Fatalf("expected the token to be rejected, got %v, %v", ok, err)
+				}
+			case tc.expectErr:
+				if ok || err == nil || authenticator.IsTokenRejected(err) {
+					t.Fatalf("expected an error, got %v, %v", ok, err)
+				}
+			case tc.expectUser == nil:
+				if ok || err != nil {
+					t.Fatalf("expected the token not to be authenticated, got %v, %v", ok, err)
+				}
+			default:
+				if !ok || err != nil {
+					t.Fatalf("expected the token to be authenticated, got %v, %v", ok, err)
+				}
+				if !reflect.DeepEqual(resp.User, tc.expectUser) {
+					t.Errorf("expected user %#v, got %#v", tc.expectUser, resp.User)
+				}
+				if !reflect.DeepEqual(resp.Audiences, tc.expectAuds) {
+					t.Errorf("expected audiences %v, got %v", tc.expectAuds, resp.Audiences)
+				}
+			}
+		})
+	}
+}
+
+func TestNewMissingCommand(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing"), nil, nil, time.Second); err == nil {
+		t.Error("expected an error for a missing command")
+	}
+}
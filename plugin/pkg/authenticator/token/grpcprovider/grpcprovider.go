@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package grpcprovider implements the authenticator.Token interface using an external
+// authentication provider reached over gRPC.
+package grpcprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	providerapi "k8s.io/apiserver/plugin/pkg/authenticator/token/grpcprovider/v1alpha1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// unixProtocol is the only supported protocol for authentication providers.
+	unixProtocol = "unix"
+	// Current version for the protocol interface definition.
+	providerAPIVersion = "v1alpha1"
+
+	versionErrorf = "authentication provider api version %s is not supported, only %s is supported now"
+)
+
+// Ensure Authenticator implements the authenticator.Token interface.
+var _ authenticator.Token = (*Authenticator)(nil)
+
+// Authenticator authenticates bearer tokens by sending them to an external
+// authentication provider listening on a unix socket.
+type Authenticator struct {
+	client       providerapi.AuthenticationProviderClient
+	connection   *grpc.ClientConn
+	implicitAuds authenticator.Audiences
+	callTimeout  time.Duration
+
+	mux            sync.RWMutex
+	versionChecked bool
+}
+
+// New returns an authenticator that sends tokens to the authentication provider at endpoint,
+// which must have the form unix:///path/to/socket. Every call is limited to callTimeout.
+// The connection is established lazily and closed when ctx is done. It is recommend to wrap
+// this authenticator with the token cache authenticator implemented in
+// k8s.io/apiserver/pkg/authentication/token/cache.
+func New(ctx context.Context, endpoint string, implicitAuds authenticator.Audiences, callTimeout time.Duration) (*Authenticator, error) {
+	klog.V(4).Infof("Configure authentication provider with endpoint: %s", endpoint)
+
+	addr, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Authenticator{implicitAuds: implicitAuds, callTimeout: callTimeout}
+	a.connection, err = grpc.Dial(
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(a.interceptor),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+		grpc.WithContextDialer(
+			func(ctx context.Context, _ string) (net.Conn, error) {
+				// the address comes from the closure
+				var d net.Dialer
+				return d.DialContext(ctx, unixProtocol, addr)
+			}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection to %s, error: %v", endpoint, err)
+	}
+	a.client = providerapi.NewAuthenticationProviderClient(a.connection)
+
+	go func() {
+		<-ctx.Done()
+		_ = a.connection.Close()
+	}()
+
+	return a, nil
+}
+
+// AuthenticateToken implements the authenticator.Token interface. Audiences are validated
+// like the webhook token authenticator does: if the request is audience limited, the
+// audiences returned by the provider, or the implicit audiences if it returns none, must
+// intersect with the requested ones.
+func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	wantAuds, checkAuds := authenticator.AudiencesFrom(ctx)
+
+	if a.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.callTimeout)
+		defer cancel()
+	}
+
+	resp, err := a.client.AuthenticateToken(ctx, &providerapi.AuthenticateTokenRequest{
+		Version:   providerAPIVersion,
+		Token:     token,
+		Audiences: wantAuds,
+	})
+	if err != nil {
+		klog.Errorf("Failed to make authentication provider request: %v", err)
+		return nil, false, err
+	}
+
+	var auds authenticator.Audiences
+	if checkAuds {
+		gotAuds := a.implicitAuds
+		if len(resp.Audiences) > 0 {
+			gotAuds = resp.Audiences
+		}
+		auds = wantAuds.Intersect(gotAuds)
+		if len(auds) == 0 {
+			return nil, false, nil
+		}
+	}
+
+	if !resp.Authenticated {
+		if len(resp.Error) > 0 {
+			return nil, false, authenticator.NewTokenRejectedError(errors.New(resp.Error))
+		}
+		return nil, false, nil
+	}
+	if resp.User == nil || len(resp.User.Username) == 0 {
+		return nil, false, errors.New("authentication provider returned no user for an authenticated token")
+	}
+
+	var extra map[string][]string
+	if resp.User.Extra != nil {
+		extra = make(map[string][]string, len(resp.User.Extra))
+		for k, v := range resp.User.Extra {
+			if v != nil {
+				extra[k] = v.Values
+			}
+		}
+	}
+
+	return &authenticator.Response{
+		User: &user.DefaultInfo{
+			Name:   resp.User.Username,
+			UID:    resp.User.Uid,
+			Groups: resp.User.Groups,
+			Extra:  extra,
+		},
+		Audiences: auds,
+	}, true, nil
+}
+
+func (a *Authenticator) checkAPIVersion(ctx context.Context) error {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if a.versionChecked {
+		return nil
+	}
+
+	response, err := a.client.Version(ctx, &providerapi.VersionRequest{Version: providerAPIVersion})
+	if err != nil {
+		return fmt.Errorf("failed get version from authentication provider: %v", err)
+	}
+	if response.Version != providerAPIVersion {
+		return fmt.Errorf(versionErrorf, response.Version, providerAPIVersion)
+	}
+	a.versionChecked = true
+
+	klog.V(4).Infof("Authentication provider %s has version %s", response.RuntimeName, response.RuntimeVersion)
+	return nil
+}
+
+func (a *Authenticator) interceptor(
+	ctx context.Context,
+	method string,
+	req interface{},
+	reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if !providerapi.IsVersionCheckMethod(method) {
+		if err := a.checkAPIVersion(ctx); err != nil {
+			return err
+		}
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// parseEndpoint returns the socket address of a unix:// endpoint.
+func parseEndpoint(endpoint string) (string, error) {
+	if len(endpoint) == 0 {
+		return "", fmt.Errorf("authentication provider can't use empty string as endpoint")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %q for authentication provider, error: %v", endpoint, err)
+	}
+	if u.Scheme != unixProtocol {
+		return "", fmt.Errorf("unsupported scheme %q for authentication provider", u.Scheme)
+	}
+
+	// Linux abstract namespace socket - no physical file required
+	if strings.HasPrefix(u.Path, "/@") {
+		return strings.TrimPrefix(u.Path, "/"), nil
+	}
+	return u.Path, nil
+}
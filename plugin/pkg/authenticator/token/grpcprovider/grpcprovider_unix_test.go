@@ -0,0 +1,203 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcprovider
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	providerapi "k8s.io/apiserver/plugin/pkg/authenticator/token/grpcprovider/v1alpha1"
+)
+
+type fakeProvider struct {
+	version   string
+	responses map[string]*providerapi.AuthenticateTokenResponse
+	requests  []*providerapi.AuthenticateTokenRequest
+}
+
+func (f *fakeProvider) Version(ctx context.Context, req *providerapi.VersionRequest) (*providerapi.VersionResponse, error) {
+	return &providerapi.VersionResponse{Version: f.version, RuntimeName: "fake", RuntimeVersion: "0.1.0"}, nil
+}
+
+func (f *fakeProvider) AuthenticateToken(ctx context.Context, req *providerapi.AuthenticateTokenRequest) (*providerapi.AuthenticateTokenResponse, error) {
+	f.requests = append(f.requests, req)
+	if resp, ok := f.responses[req.Token]; ok {
+		return resp, nil
+	}
+	return &providerapi.AuthenticateTokenResponse{}, nil
+}
+
+// startProvider serves the provider on a unix socket in a temporary directory and returns its endpoint.
+func startProvider(t *testing.T, provider providerapi.AuthenticationProviderServer) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "provider.sock")
+	listener, err := net.Listen(unixProtocol, socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	providerapi.RegisterAuthenticationProviderServer(server, provider)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return "unix://" + socket
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	provider := &fakeProvider{
+		version: providerAPIVersion,
+		responses: map[string]*providerapi.AuthenticateTokenResponse{
+			"valid": {
+				Authenticated: true,
+				User: &providerapi.UserInfo{
+					Username: "alice",
+					Uid:      "1",
+					Groups:   []string{"devs"},
+					Extra:    map[string]*providerapi.ExtraValue{"scopes": {Values: []string{"view"}}},
+				},
+			},
+			"valid-for-other": {
+				Authenticated: true,
+				User:          &providerapi.UserInfo{Username: "bob"},
+				Audiences:     []string{"other"},
+			},
+			"expired": {Error: "token expired"},
+		},
+	}
+	a, err := New(testContext(t), startProvider(t, provider), authenticator.Audiences{"api"}, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		token         string
+		auds          authenticator.Audiences
+		expectedUser  user.Info
+		expectedAuds  authenticator.Audiences
+		expectedError bool
+		expectReject  bool
+	}{
+		{
+			name:  "authenticated",
+			token: "valid",
+			expectedUser: &user.DefaultInfo{
+				Name:   "alice",
+				UID:    "1",
+				Groups: []string{"devs"},
+				Extra:  map[string][]string{"scopes": {"view"}},
+			},
+		},
+		{
+			name:  "implicit audiences",
+			token: "valid",
+			auds:  authenticator.Audiences{"api", "unknown"},
+			expectedUser: &user.DefaultInfo{
+				Name:   "alice",
+				UID:    "1",
+				Groups: []string{"devs"},
+				Extra:  map[string][]string{"scopes": {"view"}},
+			},
+			expectedAuds: authenticator.Audiences{"api"},
+		},
+		{
+			name:  "audience mismatch",
+			token: "valid-for-other",
+			auds:  authenticator.Audiences{"api"},
+		},
+		{
+			name:          "rejected",
+			token:         "expired",
+			expectedError: true,
+			expectReject:  true,
+		},
+		{
+			name:  "unknown token",
+			token: "unknown",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.auds != nil {
+				ctx = authenticator.WithAudiences(ctx, tc.auds)
+			}
+			resp, ok, err := a.AuthenticateToken(ctx, tc.token)
+			if tc.expectedError != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.expectedError, err)
+			}
+			if tc.expectReject != authenticator.IsTokenRejected(err) {
+				t.Errorf("expected rejection %v, got %v", tc.expectReject, err)
+			}
+			if ok != (tc.expectedUser != nil) {
+				t.Fatalf("expected ok %v, got %v", tc.expectedUser != nil, ok)
+			}
+			if !ok {
+				return
+			}
+			if !reflect.DeepEqual(resp.User, tc.expectedUser) {
+				t.Errorf("expected user %#v, got %#v", tc.expectedUser, resp.User)
+			}
+			if !reflect.DeepEqual(resp.Audiences, tc.expectedAuds) {
+				t.Errorf("expected audiences %v, got %v", tc.expectedAuds, resp.Audiences)
+			}
+		})
+	}
+
+	last := provider.requests[len(provider.requests)-1]
+	if last.Version != providerAPIVersion || last.Token != "unknown" {
+		t.Errorf("unexpected request %v", last)
+	}
+}
+
+func TestUnsupportedVersion(t *testing.T) {
+	provider := &fakeProvider{version: "v0"}
+	a, err := New(testContext(t), startProvider(t, provider), nil, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := a.AuthenticateToken(context.Background(), "token"); err == nil {
+		t.Fatal("expected an error for an unsupported provider version")
+	}
+	if len(provider.requests) != 0 {
+		t.Errorf("expected no token to be sent to a provider with an unsupported version")
+	}
+}
+
+func TestInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "tcp://localhost:1234", "unix//bad"} {
+		if _, err := New(testContext(t), endpoint, nil, time.Second); err == nil {
+			t.Errorf("expected an error for endpoint %q", endpoint)
+		}
+	}
+}
@@ -551,6 +551,19 @@ func (r *claimResolver) resolve(endpoint endpoint, allClaims claims) error {
 	return nil
 }
 
+// verifyFailureReason classifies the errors of the ID token verifier, which are
+// not typed, by their message.
+func verifyFailureReason(err error) (authenticator.FailureReason, bool) {
+	switch msg := err.Error(); {
+	case strings.Contains(msg, "oidc: token is expired"):
+		return authenticator.FailureReasonExpired, true
+	case strings.Contains(msg, "oidc: expected audience"):
+		return authenticator.FailureReasonInvalidAudience, true
+	default:
+		return "", false
+	}
+}
+
 func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
 	if !hasCorrectIssuer(a.issuerURL, token) {
 		return nil, false, nil
@@ -563,7 +576,11 @@ func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (*a
 
 	idToken, err := verifier.Verify(ctx, token)
 	if err != nil {
-		return nil, false, fmt.Errorf("oidc: verify token: %v", err)
+		err = fmt.Errorf("oidc: verify token: %v", err)
+		if reason, ok := verifyFailureReason(err); ok {
+			err = authenticator.NewFailureReasonError(reason, err)
+		}
+		return nil, false, err
 	}
 
 	var c claims
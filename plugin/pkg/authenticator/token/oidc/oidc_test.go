@@ -36,6 +36,7 @@ import (
 
 	"gopkg.in/square/go-jose.v2"
 
+	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/server/dynamiccertificates"
 	"k8s.io/klog/v2"
@@ -137,6 +138,7 @@ type claimsTest struct {
 	want               *user.DefaultInfo
 	wantSkip           bool
 	wantErr            string
+	wantFailureReason  authenticator.FailureReason
 	wantInitErr        string
 	claimToResponseMap map[string]string
 	openIDConfig       string
@@ -307,6 +309,9 @@ func (c *claimsTest) run(t *testing.T) {
 		if got := err.Error(); c.wantErr != got {
 			t.Fatalf("expected error %q when authenticating token but got %q", c.wantErr, got)
 		}
+		if reason, _ := authenticator.FailureReasonFromError(err); reason != c.wantFailureReason {
+			t.Fatalf("expected failure reason %q when authenticating token but got %q", c.wantFailureReason, reason)
+		}
 		return
 	}
 
@@ -1144,7 +1149,8 @@ func TestToken(t *testing.T) {
 				"username": "jane",
 				"exp": %d
 			}`, expired.Unix()),
-			wantErr: `oidc: verify token: oidc: token is expired (Token Expiry: {{.Expired}})`,
+			wantErr:           `oidc: verify token: oidc: token is expired (Token Expiry: {{.Expired}})`,
+			wantFailureReason: authenticator.FailureReasonExpired,
 		},
 		{
 			name: "invalid-aud",
@@ -1164,7 +1170,8 @@ func TestToken(t *testing.T) {
 				"username": "jane",
 				"exp": %d
 			}`, valid.Unix()),
-			wantErr: `oidc: verify token: oidc: expected audience "my-client" got ["not-my-client"]`,
+			wantErr:           `oidc: verify token: oidc: expected audience "my-client" got ["not-my-client"]`,
+			wantFailureReason: authenticator.FailureReasonInvalidAudience,
 		},
 		{
 			// ID tokens may contain multiple audiences:
@@ -1470,7 +1477,8 @@ func TestToken(t *testing.T) {
 				"username": "jane",
 				"exp": %d
 			}`, valid.Unix()),
-			wantErr: `oidc: verify token: oidc: expected audience "my-client" got ["my-wrong-client"]`,
+			wantErr:           `oidc: verify token: oidc: expected audience "my-client" got ["my-wrong-client"]`,
+			wantFailureReason: authenticator.FailureReasonInvalidAudience,
 		},
 	}
 	for _, test := range tests {
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+// errNoEndpointAvailable is returned when the circuits of all webhook endpoints are open.
+var errNoEndpointAvailable = errors.New("no authentication webhook endpoint is available")
+
+// CircuitBreakerConfig configures the circuit breaker guarding each webhook endpoint.
+// After FailureThreshold consecutive failed requests, an endpoint is skipped for
+// OpenDuration. Afterwards a single request is let through to probe the endpoint,
+// closing the circuit again on success.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures opening the circuit.
+	// If 0, the circuit never opens.
+	FailureThreshold int
+
+	// OpenDuration is how long an open circuit rejects requests before probing the endpoint again.
+	OpenDuration time.Duration
+}
+
+// NewFromInterfaces creates a webhook authenticator that sends token reviews to the first
+// available of the given clients, failing over to the next one when a request fails. Each
+// client is guarded by a circuit breaker configured by breaker, so that an endpoint which is
+// down is skipped instead of delaying every request. Failed attempts against all endpoints
+// are retried with retryBackoff. It is recommend to wrap this authenticator with the token
+// cache authenticator implemented in k8s.io/apiserver/pkg/authentication/token/cache.
+func NewFromInterfaces(tokenReviews []authenticationv1client.AuthenticationV1Interface, implicitAuds authenticator.Audiences, retryBackoff wait.Backoff, requestTimeout time.Duration, metrics AuthenticatorMetrics, breaker CircuitBreakerConfig) (*WebhookTokenAuthenticator, error) {
+	if len(tokenReviews) == 0 {
+		return nil, errors.New("at least one token review client is required")
+	}
+	reviewers := make([]tokenReviewer, 0, len(tokenReviews))
+	for _, tokenReview := range tokenReviews {
+		reviewers = append(reviewers, &tokenReviewV1Client{tokenReview.RESTClient()})
+	}
+	return newWithBackoff(newFailoverTokenReviewer(reviewers, breaker, clock.RealClock{}), retryBackoff, implicitAuds, requestTimeout, metrics)
+}
+
+// NewWithFailover creates a new WebhookTokenAuthenticator from the provided rest configs,
+// one per webhook endpoint in order of preference. See NewFromInterfaces for the failover
+// and circuit breaking behaviour.
+func NewWithFailover(configs []*rest.Config, version string, implicitAuds authenticator.Audiences, retryBackoff wait.Backoff, breaker CircuitBreakerConfig) (*WebhookTokenAuthenticator, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("at least one webhook config is required")
+	}
+	reviewers := make([]tokenReviewer, 0, len(configs))
+	for _, config := range configs {
+		tokenReview, err := tokenReviewInterfaceFromConfig(config, version, retryBackoff)
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, tokenReview)
+	}
+	return newWithBackoff(newFailoverTokenReviewer(reviewers, breaker, clock.RealClock{}), retryBackoff, implicitAuds, time.Duration(0), AuthenticatorMetrics{
+		RecordRequestTotal:   noopMetrics{}.RequestTotal,
+		RecordRequestLatency: noopMetrics{}.RequestLatency,
+	})
+}
+
+type failoverEndpoint struct {
+	reviewer tokenReviewer
+	breaker  *circuitBreaker
+}
+
+// failoverTokenReviewer sends a token review to the first endpoint whose circuit is not
+// open and moves on to the next endpoint if the request fails.
+type failoverTokenReviewer struct {
+	endpoints []failoverEndpoint
+}
+
+func newFailoverTokenReviewer(reviewers []tokenReviewer, config CircuitBreakerConfig, clock clock.PassiveClock) *failoverTokenReviewer {
+	f := &failoverTokenReviewer{}
+	for i, reviewer := range reviewers {
+		f.endpoints = append(f.endpoints, failoverEndpoint{
+			reviewer: reviewer,
+			breaker:  &circuitBreaker{config: config, clock: clock, endpoint: i},
+		})
+	}
+	return f
+}
+
+func (f *failoverTokenReviewer) Create(ctx context.Context, review *authenticationv1.TokenReview, opts metav1.CreateOptions) (*authenticationv1.TokenReview, int, error) {
+	var (
+		lastStatusCode int
+		lastErr        error = errNoEndpointAvailable
+	)
+	for _, endpoint := range f.endpoints {
+		if !endpoint.breaker.allow() {
+			continue
+		}
+		// every attempt gets its own copy, reviewers write the status into it
+		result, statusCode, err := endpoint.reviewer.Create(ctx, review.DeepCopy(), opts)
+		if err == nil {
+			endpoint.breaker.success()
+			return result, statusCode, nil
+		}
+		if ctx.Err() != nil {
+			// the caller gave up, this says nothing about the health of the endpoint
+			endpoint.breaker.abort()
+			return result, statusCode, err
+		}
+		if !isEndpointFailure(statusCode) {
+			// the endpoint is up and answered, other endpoints would not answer differently
+			endpoint.breaker.success()
+			return result, statusCode, err
+		}
+		endpoint.breaker.failure()
+		lastStatusCode, lastErr = statusCode, err
+	}
+	return nil, lastStatusCode, lastErr
+}
+
+// isEndpointFailure returns true if a request failing with the given status code means
+// the endpoint is unavailable rather than the request being invalid.
+func isEndpointFailure(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// circuitBreaker tracks consecutive failures of a single endpoint.
+type circuitBreaker struct {
+	config   CircuitBreakerConfig
+	clock    clock.PassiveClock
+	endpoint int
+
+	lock      sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// allow returns true if a request may be sent to the endpoint. Once an open circuit
+// expired, only a single probing request is allowed until its outcome is known.
+func (b *circuitBreaker) allow() bool {
+	if b.config.FailureThreshold <= 0 {
+		return true
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.failures < b.config.FailureThreshold {
+		return true
+	}
+	if b.probing || b.clock.Now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *circuitBreaker) success() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.config.FailureThreshold > 0 && b.failures >= b.config.FailureThreshold {
+		klog.Infof("Authentication webhook endpoint %d is available again, closing its circuit", b.endpoint)
+	}
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *circuitBreaker) failure() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.probing = false
+	b.failures++
+	if b.config.FailureThreshold > 0 && b.failures >= b.config.FailureThreshold {
+		if b.failures == b.config.FailureThreshold {
+			klog.Warningf("Authentication webhook endpoint %d failed %d times in a row, opening its circuit for %v", b.endpoint, b.failures, b.config.OpenDuration)
+		}
+		b.openUntil = b.clock.Now().Add(b.config.OpenDuration)
+	}
+}
+
+// abort releases a probe whose outcome is unknown.
+func (b *circuitBreaker) abort() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.probing = false
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+type fakeReviewer struct {
+	statusCode int
+	err        error
+	calls      int
+}
+
+func (f *fakeReviewer) Create(ctx context.Context, review *authenticationv1.TokenReview, _ metav1.CreateOptions) (*authenticationv1.TokenReview, int, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.statusCode, f.err
+	}
+	review.Status.Authenticated = true
+	return review, http.StatusOK, nil
+}
+
+func TestFailoverTokenReviewer(t *testing.T) {
+	testCases := []struct {
+		name          string
+		primary       *fakeReviewer
+		secondary     *fakeReviewer
+		expectErr     bool
+		expectPrimary int
+		expectSecond  int
+	}{
+		{
+			name:          "primary available",
+			primary:       &fakeReviewer{},
+			secondary:     &fakeReviewer{},
+			expectPrimary: 1,
+		},
+		{
+			name:          "primary unreachable",
+			primary:       &fakeReviewer{err: errors.New("connection refused")},
+			secondary:     &fakeReviewer{},
+			expectPrimary: 1,
+			expectSecond:  1,
+		},
+		{
+			name:          "primary internal error",
+			primary:       &fakeReviewer{statusCode: http.StatusInternalServerError, err: errors.New("internal error")},
+			secondary:     &fakeReviewer{},
+			expectPrimary: 1,
+			expectSecond:  1,
+		},
+		{
+			name:          "primary rejects request",
+			primary:       &fakeReviewer{statusCode: http.StatusForbidden, err: errors.New("forbidden")},
+			secondary:     &fakeReviewer{},
+			expectErr:     true,
+			expectPrimary: 1,
+		},
+		{
+			name:          "all unavailable",
+			primary:       &fakeReviewer{err: errors.New("connection refused")},
+			secondary:     &fakeReviewer{statusCode: http.StatusServiceUnavailable, err: errors.New("unavailable")},
+			expectErr:     true,
+			expectPrimary: 1,
+			expectSecond:  1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFailoverTokenReviewer([]tokenReviewer{tc.primary, tc.secondary}, CircuitBreakerConfig{}, testingclock.NewFakeClock(time.Now()))
+			result, _, err := f.Create(context.Background(), &authenticationv1.TokenReview{}, metav1.CreateOptions{})
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if err == nil && !result.Status.Authenticated {
+				t.Errorf("expected an authenticated review")
+			}
+			if tc.primary.calls != tc.expectPrimary || tc.secondary.calls != tc.expectSecond {
+				t.Errorf("expected %d and %d calls, got %d and %d", tc.expectPrimary, tc.expectSecond, tc.primary.calls, tc.secondary.calls)
+			}
+		})
+	}
+}
+
+func TestFailoverCircuitBreaker(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	primary := &fakeReviewer{err: errors.New("connection refused")}
+	secondary := &fakeReviewer{}
+	f := newFailoverTokenReviewer([]tokenReviewer{primary, secondary}, CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenDuration:     time.Minute,
+	}, clock)
+
+	create := func() {
+		t.Helper()
+		if _, _, err := f.Create(context.Background(), &authenticationv1.TokenReview{}, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	create()
+	create()
+	if primary.calls != 2 {
+		t.Fatalf("expected the primary to be tried until the threshold, got %d calls", primary.calls)
+	}
+
+	// the circuit is open, the primary is skipped
+	create()
+	if primary.calls != 2 {
+		t.Fatalf("expected the primary to be skipped while its circuit is open, got %d calls", primary.calls)
+	}
+
+	// a failed probe opens the circuit again
+	clock.Step(time.Minute)
+	create()
+	create()
+	if primary.calls != 3 {
+		t.Fatalf("expected a single probe of the primary, got %d calls", primary.calls)
+	}
+
+	// a successful probe closes the circuit
+	clock.Step(time.Minute)
+	primary.err = nil
+	create()
+	create()
+	if primary.calls != 5 {
+		t.Fatalf("expected the primary to be used again after a successful probe, got %d calls", primary.calls)
+	}
+	if secondary.calls != 5 {
+		t.Fatalf("expected the secondary to serve while the primary was unavailable, got %d calls", secondary.calls)
+	}
+}
+
+func TestFailoverAllCircuitsOpen(t *testing.T) {
+	primary := &fakeReviewer{err: errors.New("connection refused")}
+	f := newFailoverTokenReviewer([]tokenReviewer{primary}, CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenDuration:     time.Minute,
+	}, testingclock.NewFakeClock(time.Now()))
+
+	if _, _, err := f.Create(context.Background(), &authenticationv1.TokenReview{}, metav1.CreateOptions{}); err != primary.err {
+		t.Fatalf("expected the endpoint error, got %v", err)
+	}
+	if _, _, err := f.Create(context.Background(), &authenticationv1.TokenReview{}, metav1.CreateOptions{}); err != errNoEndpointAvailable {
+		t.Fatalf("expected %v, got %v", errNoEndpointAvailable, err)
+	}
+	if primary.calls != 1 {
+		t.Fatalf("expected a single call, got %d", primary.calls)
+	}
+}
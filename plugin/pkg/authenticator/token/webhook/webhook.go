@@ -170,7 +170,7 @@ func (w *WebhookTokenAuthenticator) AuthenticateToken(ctx context.Context, token
 	if !r.Status.Authenticated {
 		var err error
 		if len(r.Status.Error) != 0 {
-			err = errors.New(r.Status.Error)
+			err = authenticator.NewTokenRejectedError(errors.New(r.Status.Error))
 		}
 		return nil, false, err
 	}
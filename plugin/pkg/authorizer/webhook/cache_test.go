@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+func TestSeparateResponseCaches(t *testing.T) {
+	reviewer := &fakeSubjectAccessReviewer{denyUsers: map[string]bool{"mallory": true}}
+	var lookups []string
+	metrics := noopAuthorizerMetrics()
+	metrics.RecordCacheLookup = func(_ context.Context, result string) { lookups = append(lookups, result) }
+	wh, err := newWithBackoff(reviewer, time.Hour, time.Hour, testRetryBackoff, metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wh.SetCacheSizes(1, 1)
+
+	attributes := func(name, resource string) authorizer.Attributes {
+		return authorizer.AttributesRecord{User: &user.DefaultInfo{Name: name}, Verb: "get", Resource: resource, ResourceRequest: true}
+	}
+	authorize := func(attr authorizer.Attributes, expect authorizer.Decision) {
+		t.Helper()
+		if decision, _, err := wh.Authorize(context.Background(), attr); err != nil || decision != expect {
+			t.Fatalf("expected decision %v, got %v, %v", expect, decision, err)
+		}
+	}
+
+	authorize(attributes("mallory", "secrets"), authorizer.DecisionDeny)
+	authorize(attributes("jane", "pods"), authorizer.DecisionAllow)
+	// another allowed response evicts only the first one, not the denial
+	authorize(attributes("jane", "services"), authorizer.DecisionAllow)
+	authorize(attributes("mallory", "secrets"), authorizer.DecisionDeny)
+	authorize(attributes("jane", "services"), authorizer.DecisionAllow)
+	authorize(attributes("jane", "pods"), authorizer.DecisionAllow)
+	if reviewer.calls != 4 {
+		t.Errorf("expected 4 reviews, got %d", reviewer.calls)
+	}
+	expectLookups := []string{CacheLookupMiss, CacheLookupMiss, CacheLookupMiss, CacheLookupUnauthorized, CacheLookupAuthorized, CacheLookupMiss}
+	if !reflect.DeepEqual(lookups, expectLookups) {
+		t.Errorf("expected lookups %v, got %v", expectLookups, lookups)
+	}
+
+	wh.InvalidateAll()
+	authorize(attributes("mallory", "secrets"), authorizer.DecisionDeny)
+	if reviewer.calls != 5 {
+		t.Errorf("expected the flushed response to be reviewed again, got %d reviews", reviewer.calls)
+	}
+}
+
+func TestInvalidateUser(t *testing.T) {
+	reviewer := &fakeSubjectAccessReviewer{denyUsers: map[string]bool{"mallory": true}}
+	wh, err := newWithBackoff(reviewer, time.Hour, time.Hour, testRetryBackoff, noopAuthorizerMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authorize := func(name string) {
+		t.Helper()
+		attr := authorizer.AttributesRecord{User: &user.DefaultInfo{Name: name}, Verb: "get", Resource: "pods", ResourceRequest: true}
+		if _, _, err := wh.Authorize(context.Background(), attr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	authorize("jane")
+	authorize("mallory")
+	authorize("bob")
+	wh.InvalidateUser("jane")
+	wh.InvalidateUser("mallory")
+	wh.InvalidateUser("nobody")
+	authorize("jane")
+	authorize("mallory")
+	authorize("bob")
+	if reviewer.calls != 5 {
+		t.Errorf("expected the responses for jane and mallory to be reviewed again, got %d reviews", reviewer.calls)
+	}
+}
+
+func TestInvalidateDuringReview(t *testing.T) {
+	reviewer := &blockingSubjectAccessReviewer{release: make(chan struct{})}
+	wh, err := newWithBackoff(reviewer, time.Hour, time.Hour, testRetryBackoff, noopAuthorizerMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	attr := authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "jane"}, Verb: "get", Resource: "pods", ResourceRequest: true}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, _, err := wh.Authorize(context.Background(), attr); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return atomic.LoadInt32(&reviewer.calls) == 1, nil
+	}); err != nil {
+		t.Fatal("expected a review in flight")
+	}
+	wh.InvalidateUser("jane")
+	close(reviewer.release)
+	<-done
+
+	if _, _, err := wh.Authorize(context.Background(), attr); err != nil {
+		t.Fatal(err)
+	}
+	if calls := atomic.LoadInt32(&reviewer.calls); calls != 2 {
+		t.Errorf("expected the response of the review invalidated in flight not to be cached, got %d reviews", calls)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// blockingSubjectAccessReviewer allows all requests once release is closed, and counts the reviews.
+type blockingSubjectAccessReviewer struct {
+	release chan struct{}
+	calls   int32
+}
+
+func (f *blockingSubjectAccessReviewer) Create(_ context.Context, _ *authorizationv1.SubjectAccessReview, _ metav1.CreateOptions) (*authorizationv1.SubjectAccessReview, int, error) {
+	atomic.AddInt32(&f.calls, 1)
+	<-f.release
+	return &authorizationv1.SubjectAccessReview{Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true}}, 200, nil
+}
+
+func TestCoalesceConcurrentReviews(t *testing.T) {
+	reviewer := &blockingSubjectAccessReviewer{release: make(chan struct{})}
+	wh, err := newWithBackoff(reviewer, 0, 0, testRetryBackoff, noopAuthorizerMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attributes := func(name string) authorizer.Attributes {
+		return authorizer.AttributesRecord{User: &user.DefaultInfo{Name: name}, Verb: "get", Namespace: "default", Resource: "pods", ResourceRequest: true}
+	}
+	const checks = 10
+
+	var wg sync.WaitGroup
+	decisions := make(chan authorizer.Decision, 2*checks)
+	for i := 0; i < checks; i++ {
+		for _, name := range []string{"alice", "bob"} {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				decision, _, err := wh.Authorize(context.Background(), attributes(name))
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				decisions <- decision
+			}(name)
+		}
+	}
+
+	// Wait for one review per user to be in flight before letting them complete.
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return atomic.LoadInt32(&reviewer.calls) == 2, nil
+	}); err != nil {
+		t.Fatalf("expected 2 reviews in flight, got %d", atomic.LoadInt32(&reviewer.calls))
+	}
+	// Give the remaining checks time to join the reviews in flight.
+	time.Sleep(100 * time.Millisecond)
+	close(reviewer.release)
+	wg.Wait()
+	close(decisions)
+
+	for decision := range decisions {
+		if decision != authorizer.DecisionAllow {
+			t.Errorf("expected all checks to be allowed, got %v", decision)
+		}
+	}
+	if calls := atomic.LoadInt32(&reviewer.calls); calls != 2 {
+		t.Errorf("expected 1 review per user, got %d reviews", calls)
+	}
+}
+
+func TestCoalescedReviewWaiterCanceled(t *testing.T) {
+	reviewer := &blockingSubjectAccessReviewer{release: make(chan struct{})}
+	defer close(reviewer.release)
+	wh, err := newWithBackoff(reviewer, 0, 0, testRetryBackoff, noopAuthorizerMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	decision, _, err := wh.Authorize(ctx, authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "alice"}, Verb: "get", Path: "/healthz"})
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline of the waiting check to be exceeded, got %v", err)
+	}
+	if decision != authorizer.DecisionNoOpinion {
+		t.Errorf("expected no opinion, got %v", decision)
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// DefaultFailoverThreshold is the default number of consecutive failures after which an
+	// endpoint is considered unhealthy.
+	DefaultFailoverThreshold = 3
+
+	// DefaultProbeInterval is the default minimum time between health probes of an unhealthy endpoint.
+	DefaultProbeInterval = 10 * time.Second
+
+	// PrimaryEndpointName is the name of the endpoint an authorizer was created with, in the
+	// metrics of its endpoints.
+	PrimaryEndpointName = "primary"
+
+	// probeTimeout bounds a health probe of an unhealthy endpoint.
+	probeTimeout = 10 * time.Second
+)
+
+// probeReview is the review sent to unhealthy endpoints to probe their health. Any response
+// other than an error is taken as a sign of health, whatever the decision.
+var probeReview = &authorizationv1.SubjectAccessReview{
+	Spec: authorizationv1.SubjectAccessReviewSpec{
+		User: "system:authorization-webhook-probe",
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+			Path: "/",
+			Verb: "get",
+		},
+	},
+}
+
+// FailoverEndpoint is a remote service taking over the reviews when the preceding endpoints are unhealthy.
+type FailoverEndpoint struct {
+	// Name identifies the endpoint in the metrics and logs.
+	Name string
+	// Client creates SubjectAccessReviews at the endpoint.
+	Client authorizationv1client.AuthorizationV1Interface
+}
+
+// FailoverConfig tunes the failover between the endpoints of an authorizer.
+type FailoverConfig struct {
+	// FailureThreshold is the number of consecutive failures after which an endpoint is considered
+	// unhealthy, and reviews fail over to the next healthy endpoint. Zero means DefaultFailoverThreshold.
+	FailureThreshold int
+
+	// ProbeInterval is the minimum time between health probes of an unhealthy endpoint. Once a probe
+	// succeeds, the endpoint is healthy again and reviews fail back to it if it precedes the others.
+	// Probes are sent in the background when reviews skip an unhealthy endpoint. Zero means
+	// DefaultProbeInterval.
+	ProbeInterval time.Duration
+}
+
+// SetFailoverEndpoints adds endpoints that take over the reviews, in order, while the endpoint the
+// authorizer was created with is unhealthy. It must be called before the authorizer is used.
+func (w *WebhookAuthorizer) SetFailoverEndpoints(endpoints []FailoverEndpoint, config FailoverConfig) {
+	w.subjectAccessReview = newFailoverReviewer(w.subjectAccessReview, endpoints, config, w.metrics, clock.RealClock{})
+}
+
+// failoverEndpoint is an endpoint of a failoverReviewer and its health.
+type failoverEndpoint struct {
+	name     string
+	reviewer subjectAccessReviewer
+
+	lock sync.Mutex
+	// failures is the number of consecutive failures of reviews at the endpoint.
+	failures int
+	healthy  bool
+	// probing is true while a probe of the endpoint is in flight, lastProbe is when the last one started.
+	probing   bool
+	lastProbe time.Time
+}
+
+// failoverReviewer sends reviews to the first healthy of its endpoints.
+type failoverReviewer struct {
+	endpoints []*failoverEndpoint
+	config    FailoverConfig
+	metrics   AuthorizerMetrics
+	clock     clock.Clock
+}
+
+func newFailoverReviewer(primary subjectAccessReviewer, endpoints []FailoverEndpoint, config FailoverConfig, metrics AuthorizerMetrics, clock clock.Clock) *failoverReviewer {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailoverThreshold
+	}
+	if config.ProbeInterval <= 0 {
+		config.ProbeInterval = DefaultProbeInterval
+	}
+	f := &failoverReviewer{config: config, metrics: metrics, clock: clock}
+	f.endpoints = append(f.endpoints, &failoverEndpoint{name: PrimaryEndpointName, reviewer: primary, healthy: true})
+	for _, e := range endpoints {
+		f.endpoints = append(f.endpoints, &failoverEndpoint{name: e.Name, reviewer: &subjectAccessReviewV1Client{e.Client.RESTClient()}, healthy: true})
+	}
+	for _, e := range f.endpoints {
+		f.metrics.recordEndpointHealth(e.name, true)
+	}
+	return f
+}
+
+// Create sends the review to the first healthy endpoint, and to the next ones while the endpoints
+// it is sent to turn unhealthy. If all endpoints are unhealthy, it is sent to the primary endpoint.
+func (f *failoverReviewer) Create(ctx context.Context, review *authorizationv1.SubjectAccessReview, opts metav1.CreateOptions) (*authorizationv1.SubjectAccessReview, int, error) {
+	var (
+		result     *authorizationv1.SubjectAccessReview
+		statusCode int
+		err        error
+		sent       bool
+	)
+	for _, e := range f.endpoints {
+		if !e.isHealthy() {
+			f.maybeProbe(e)
+			continue
+		}
+		sent = true
+		result, statusCode, err = f.send(ctx, e, review, opts)
+		if err == nil || !isEndpointFailure(statusCode, err) || e.isHealthy() {
+			return result, statusCode, err
+		}
+	}
+	if !sent {
+		result, statusCode, err = f.send(ctx, f.endpoints[0], review, opts)
+	}
+	return result, statusCode, err
+}
+
+// send sends the review to e, and records the outcome in the health of e.
+func (f *failoverReviewer) send(ctx context.Context, e *failoverEndpoint, review *authorizationv1.SubjectAccessReview, opts metav1.CreateOptions) (*authorizationv1.SubjectAccessReview, int, error) {
+	result, statusCode, err := e.reviewer.Create(ctx, review, opts)
+	code := "<error>"
+	if statusCode != 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	f.metrics.recordEndpointRequest(ctx, e.name, code)
+	f.recordOutcome(e, !isEndpointFailure(statusCode, err))
+	return result, statusCode, err
+}
+
+// maybeProbe probes the health of the unhealthy endpoint e in the background, unless it is being
+// probed or was probed less than the probe interval ago.
+func (f *failoverReviewer) maybeProbe(e *failoverEndpoint) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	now := f.clock.Now()
+	if e.probing || now.Sub(e.lastProbe) < f.config.ProbeInterval {
+		return
+	}
+	e.probing = true
+	e.lastProbe = now
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		defer cancel()
+		_, statusCode, err := e.reviewer.Create(ctx, probeReview, metav1.CreateOptions{})
+		f.recordOutcome(e, !isEndpointFailure(statusCode, err))
+
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		e.probing = false
+	}()
+}
+
+// recordOutcome updates the health of e after a review or probe that succeeded or failed.
+func (f *failoverReviewer) recordOutcome(e *failoverEndpoint, succeeded bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	wasHealthy := e.healthy
+	if succeeded {
+		e.failures = 0
+		e.healthy = true
+	} else {
+		e.failures++
+		if e.failures >= f.config.FailureThreshold {
+			e.healthy = false
+		}
+	}
+
+	switch {
+	case wasHealthy && !e.healthy:
+		klog.Warningf("Authorization webhook endpoint %q is unhealthy after %d consecutive failures, failing over", e.name, e.failures)
+		f.metrics.recordEndpointHealth(e.name, false)
+	case !wasHealthy && e.healthy:
+		klog.Infof("Authorization webhook endpoint %q is healthy again", e.name)
+		f.metrics.recordEndpointHealth(e.name, true)
+	}
+}
+
+func (e *failoverEndpoint) isHealthy() bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.healthy
+}
+
+// isEndpointFailure returns whether the outcome of a review indicates that the endpoint is unable
+// to review, as opposed to rejecting a particular review.
+func isEndpointFailure(statusCode int, err error) bool {
+	if err == nil {
+		return false
+	}
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
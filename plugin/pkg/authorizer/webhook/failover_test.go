@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// fakeEndpointReviewer allows all reviews, or fails them with statusCode and err, and counts the reviews.
+type fakeEndpointReviewer struct {
+	lock       sync.Mutex
+	statusCode int
+	err        error
+	calls      int
+}
+
+func (f *fakeEndpointReviewer) Create(_ context.Context, _ *authorizationv1.SubjectAccessReview, _ metav1.CreateOptions) (*authorizationv1.SubjectAccessReview, int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.statusCode, f.err
+	}
+	return &authorizationv1.SubjectAccessReview{Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true}}, 200, nil
+}
+
+func (f *fakeEndpointReviewer) fail(statusCode int, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.statusCode, f.err = statusCode, err
+}
+
+func (f *fakeEndpointReviewer) takeCalls() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	calls := f.calls
+	f.calls = 0
+	return calls
+}
+
+func newTestFailoverReviewer(primary, secondary *fakeEndpointReviewer, clock *testingclock.FakeClock, health map[string]bool) *failoverReviewer {
+	var lock sync.Mutex
+	metrics := AuthorizerMetrics{
+		RecordEndpointHealth: func(endpoint string, healthy bool) {
+			lock.Lock()
+			defer lock.Unlock()
+			health[endpoint] = healthy
+		},
+	}
+	f := newFailoverReviewer(primary, nil, FailoverConfig{FailureThreshold: 2, ProbeInterval: time.Minute}, metrics, clock)
+	f.endpoints = append(f.endpoints, &failoverEndpoint{name: "secondary", reviewer: secondary, healthy: true})
+	return f
+}
+
+func TestFailoverReviewer(t *testing.T) {
+	primary, secondary := &fakeEndpointReviewer{}, &fakeEndpointReviewer{}
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	health := map[string]bool{}
+	f := newTestFailoverReviewer(primary, secondary, fakeClock, health)
+
+	create := func() error {
+		_, _, err := f.Create(context.Background(), &authorizationv1.SubjectAccessReview{}, metav1.CreateOptions{})
+		return err
+	}
+	expectCalls := func(step string, primaryCalls, secondaryCalls int) {
+		t.Helper()
+		if got := primary.takeCalls(); got != primaryCalls {
+			t.Errorf("%s: expected %d reviews at the primary endpoint, got %d", step, primaryCalls, got)
+		}
+		if got := secondary.takeCalls(); got != secondaryCalls {
+			t.Errorf("%s: expected %d reviews at the secondary endpoint, got %d", step, secondaryCalls, got)
+		}
+	}
+
+	if err := create(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectCalls("healthy", 1, 0)
+
+	// A rejected review does not count as a failure of the endpoint.
+	primary.fail(403, errors.New("forbidden"))
+	for i := 0; i < 3; i++ {
+		if err := create(); err == nil {
+			t.Fatal("expected the error of the primary endpoint")
+		}
+	}
+	expectCalls("rejected", 3, 0)
+
+	// The first failure is returned, the second one reaches the threshold and fails over.
+	primary.fail(0, errors.New("connection refused"))
+	if err := create(); err == nil {
+		t.Fatal("expected the error of the primary endpoint below the failure threshold")
+	}
+	expectCalls("first failure", 1, 0)
+	if err := create(); err != nil {
+		t.Fatalf("expected the review to fail over, got %v", err)
+	}
+	expectCalls("failover", 1, 1)
+	if health[PrimaryEndpointName] {
+		t.Error("expected the primary endpoint to be recorded unhealthy")
+	}
+
+	// Skipping the unhealthy endpoint probes it in the background, at most once per probe interval.
+	if err := create(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForProbe(t, f.endpoints[0])
+	if err := create(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectCalls("failed over", 1, 2)
+
+	// Once a probe succeeds, the reviews fail back to the primary endpoint.
+	primary.fail(0, nil)
+	fakeClock.Step(time.Minute)
+	if err := create(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForProbe(t, f.endpoints[0])
+	expectCalls("probe", 1, 1)
+	if !health[PrimaryEndpointName] {
+		t.Error("expected the primary endpoint to be recorded healthy")
+	}
+	if err := create(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectCalls("failback", 1, 0)
+}
+
+func TestFailoverReviewerAllUnhealthy(t *testing.T) {
+	primary, secondary := &fakeEndpointReviewer{}, &fakeEndpointReviewer{}
+	f := newTestFailoverReviewer(primary, secondary, testingclock.NewFakeClock(time.Now()), map[string]bool{})
+	for _, e := range f.endpoints {
+		e.healthy = false
+		e.lastProbe = time.Now()
+	}
+
+	if _, _, err := f.Create(context.Background(), &authorizationv1.SubjectAccessReview{}, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if primary.takeCalls() != 1 || secondary.takeCalls() != 0 {
+		t.Error("expected the review to be sent to the primary endpoint when all endpoints are unhealthy")
+	}
+	if !f.endpoints[0].isHealthy() {
+		t.Error("expected the primary endpoint to be healthy after a successful review")
+	}
+}
+
+// waitForProbe waits for the probe of e in flight to complete.
+func waitForProbe(t *testing.T, e *failoverEndpoint) {
+	t.Helper()
+	if err := wait.PollImmediate(time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		return !e.probing, nil
+	}); err != nil {
+		t.Fatalf("the probe of endpoint %q did not complete: %v", e.name, err)
+	}
+}
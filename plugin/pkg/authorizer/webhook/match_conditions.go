@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// MatchConditionRequestVarName is the name of the CEL variable holding the spec of the
+// SubjectAccessReview of a request, e.g. request.resourceAttributes.namespace or request.user.
+const MatchConditionRequestVarName = "request"
+
+// MatchCondition is a CEL expression evaluated locally against the SubjectAccessReview of a
+// request. Only requests matching all conditions are sent to the webhook.
+type MatchCondition struct {
+	// Name identifies the condition in errors.
+	Name string
+	// Expression must evaluate to a bool.
+	Expression string
+}
+
+var (
+	matchConditionEnvOnce sync.Once
+	matchConditionEnv     *cel.Env
+	matchConditionEnvErr  error
+)
+
+func getMatchConditionEnv() (*cel.Env, error) {
+	matchConditionEnvOnce.Do(func() {
+		matchConditionEnv, matchConditionEnvErr = cel.NewEnv(
+			cel.Variable(MatchConditionRequestVarName, cel.MapType(cel.StringType, cel.DynType)),
+		)
+	})
+	return matchConditionEnv, matchConditionEnvErr
+}
+
+// matchConditionMatcher evaluates compiled match conditions against SubjectAccessReviews.
+type matchConditionMatcher struct {
+	conditions []compiledMatchCondition
+}
+
+type compiledMatchCondition struct {
+	name    string
+	program cel.Program
+}
+
+// ValidateMatchConditions returns an error if any of the conditions fails to compile.
+func ValidateMatchConditions(conditions []MatchCondition) error {
+	_, err := compileMatchConditions(conditions)
+	return err
+}
+
+func compileMatchConditions(conditions []MatchCondition) (*matchConditionMatcher, error) {
+	env, err := getMatchConditionEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize CEL environment: %v", err)
+	}
+	m := &matchConditionMatcher{conditions: make([]compiledMatchCondition, 0, len(conditions))}
+	for _, c := range conditions {
+		ast, issues := env.Compile(c.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("match condition %q: compilation failed: %v", c.Name, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return nil, fmt.Errorf("match condition %q: expression must evaluate to bool, got %v", c.Name, ast.OutputType())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("match condition %q: program construction failed: %v", c.Name, err)
+		}
+		m.conditions = append(m.conditions, compiledMatchCondition{name: c.Name, program: program})
+	}
+	return m, nil
+}
+
+// match returns true if all conditions evaluate to true for spec. Evaluation stops at the first
+// condition that evaluates to false or fails to evaluate.
+func (m *matchConditionMatcher) match(spec *authorizationv1.SubjectAccessReviewSpec) (bool, error) {
+	if m == nil || len(m.conditions) == 0 {
+		return true, nil
+	}
+	request, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert the SubjectAccessReview for match conditions: %v", err)
+	}
+	activation := map[string]interface{}{MatchConditionRequestVarName: request}
+	for _, c := range m.conditions {
+		val, _, err := c.program.Eval(activation)
+		if err != nil {
+			return false, fmt.Errorf("match condition %q: %v", c.name, err)
+		}
+		matched, ok := val.Value().(bool)
+		if !ok {
+			return false, fmt.Errorf("match condition %q: expression evaluated to %v, not bool", c.name, val.Type())
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// fakeSubjectAccessReviewer allows the requests of all users but denyUsers and counts the reviews.
+type fakeSubjectAccessReviewer struct {
+	denyUsers map[string]bool
+	calls     int
+}
+
+func (f *fakeSubjectAccessReviewer) Create(_ context.Context, review *authorizationv1.SubjectAccessReview, _ metav1.CreateOptions) (*authorizationv1.SubjectAccessReview, int, error) {
+	f.calls++
+	allowed := !f.denyUsers[review.Spec.User]
+	return &authorizationv1.SubjectAccessReview{Status: authorizationv1.SubjectAccessReviewStatus{Allowed: allowed, Denied: !allowed}}, 200, nil
+}
+
+func TestMatchConditions(t *testing.T) {
+	resourceRequest := authorizer.AttributesRecord{
+		User:            &user.DefaultInfo{Name: "jane", Groups: []string{"developers"}},
+		Verb:            "get",
+		Namespace:       "kube-system",
+		Resource:        "pods",
+		ResourceRequest: true,
+	}
+	nonResourceRequest := authorizer.AttributesRecord{
+		User: &user.DefaultInfo{Name: "jane"},
+		Verb: "get",
+		Path: "/metrics",
+	}
+
+	testCases := []struct {
+		name           string
+		conditions     []MatchCondition
+		attributes     authorizer.Attributes
+		expectDecision authorizer.Decision
+		expectCalled   bool
+		expectErr      bool
+	}{
+		{
+			name:           "no conditions",
+			attributes:     nonResourceRequest,
+			expectDecision: authorizer.DecisionAllow,
+			expectCalled:   true,
+		},
+		{
+			name:           "resource requests only, resource request",
+			conditions:     []MatchCondition{{Name: "resources", Expression: "has(request.resourceAttributes)"}},
+			attributes:     resourceRequest,
+			expectDecision: authorizer.DecisionAllow,
+			expectCalled:   true,
+		},
+		{
+			name:           "resource requests only, non-resource request",
+			conditions:     []MatchCondition{{Name: "resources", Expression: "has(request.resourceAttributes)"}},
+			attributes:     nonResourceRequest,
+			expectDecision: authorizer.DecisionNoOpinion,
+		},
+		{
+			name: "all conditions must match",
+			conditions: []MatchCondition{
+				{Name: "resources", Expression: "has(request.resourceAttributes)"},
+				{Name: "namespace", Expression: "request.resourceAttributes.namespace != 'kube-system'"},
+			},
+			attributes:     resourceRequest,
+			expectDecision: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:           "user groups",
+			conditions:     []MatchCondition{{Name: "developers", Expression: "'developers' in request.groups"}},
+			attributes:     resourceRequest,
+			expectDecision: authorizer.DecisionAllow,
+			expectCalled:   true,
+		},
+		{
+			name:           "evaluation error",
+			conditions:     []MatchCondition{{Name: "missing", Expression: "request.resourceAttributes.namespace == ''"}},
+			attributes:     nonResourceRequest,
+			expectDecision: authorizer.DecisionNoOpinion,
+			expectErr:      true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reviewer := &fakeSubjectAccessReviewer{}
+			wh, err := newWithBackoff(reviewer, 0, 0, testRetryBackoff, noopAuthorizerMetrics())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := wh.SetMatchConditions(tc.conditions); err != nil {
+				t.Fatal(err)
+			}
+			decision, _, err := wh.Authorize(context.Background(), tc.attributes)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if decision != tc.expectDecision {
+				t.Errorf("expected decision %v, got %v", tc.expectDecision, decision)
+			}
+			if called := reviewer.calls > 0; called != tc.expectCalled {
+				t.Errorf("expected the webhook to be called %v, got %v", tc.expectCalled, called)
+			}
+		})
+	}
+}
+
+func TestValidateMatchConditions(t *testing.T) {
+	if err := ValidateMatchConditions([]MatchCondition{{Name: "valid", Expression: "has(request.nonResourceAttributes)"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateMatchConditions([]MatchCondition{{Name: "syntax", Expression: "request.user =="}}); err == nil {
+		t.Error("expected an error for an invalid expression")
+	}
+	if err := ValidateMatchConditions([]MatchCondition{{Name: "type", Expression: "request.user.size()"}}); err == nil {
+		t.Error("expected an error for an expression not evaluating to bool")
+	}
+}
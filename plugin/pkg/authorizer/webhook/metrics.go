@@ -18,6 +18,8 @@ package webhook
 
 import (
 	"context"
+
+	"k8s.io/apiserver/pkg/authorization/authorizer"
 )
 
 // AuthorizerMetrics specifies a set of methods that are used to register various metrics for the webhook authorizer
@@ -27,6 +29,68 @@ type AuthorizerMetrics struct {
 
 	// RecordRequestLatency measures request latency in seconds for webhooks. Broken down by status code.
 	RecordRequestLatency func(ctx context.Context, code string, latency float64)
+
+	// RecordCacheLookup increments the number of response cache lookups, broken down by result: the
+	// cache holding the response, or a miss. It can be nil.
+	RecordCacheLookup func(ctx context.Context, result string)
+
+	// RecordEndpointRequest increments the number of requests to an endpoint of the webhook
+	// authorizer, broken down by status code. It can be nil.
+	RecordEndpointRequest func(ctx context.Context, endpoint, code string)
+
+	// RecordEndpointHealth records whether an endpoint of the webhook authorizer is healthy. It can be nil.
+	RecordEndpointHealth func(endpoint string, healthy bool)
+
+	// RecordQueueWait measures the time in seconds a review waited for the rate limit of the webhook
+	// authorizer, broken down by the priority level of the request, or "" if it was not classified.
+	// It can be nil.
+	RecordQueueWait func(ctx context.Context, priorityLevel string, wait float64)
+
+	// RecordDecision increments the number of requests decided by the webhook authorizer, broken down
+	// by decision: allow, deny or no-opinion, and whether an error was returned. It can be nil.
+	RecordDecision func(ctx context.Context, decision string, failed bool)
+}
+
+func (m AuthorizerMetrics) recordCacheLookup(ctx context.Context, result string) {
+	if m.RecordCacheLookup != nil {
+		m.RecordCacheLookup(ctx, result)
+	}
+}
+
+func (m AuthorizerMetrics) recordEndpointRequest(ctx context.Context, endpoint, code string) {
+	if m.RecordEndpointRequest != nil {
+		m.RecordEndpointRequest(ctx, endpoint, code)
+	}
+}
+
+func (m AuthorizerMetrics) recordEndpointHealth(endpoint string, healthy bool) {
+	if m.RecordEndpointHealth != nil {
+		m.RecordEndpointHealth(endpoint, healthy)
+	}
+}
+
+func (m AuthorizerMetrics) recordQueueWait(ctx context.Context, priorityLevel string, wait float64) {
+	if m.RecordQueueWait != nil {
+		m.RecordQueueWait(ctx, priorityLevel, wait)
+	}
+}
+
+func (m AuthorizerMetrics) recordDecision(ctx context.Context, decision authorizer.Decision, err error) {
+	if m.RecordDecision != nil {
+		m.RecordDecision(ctx, decisionLabel(decision), err != nil)
+	}
+}
+
+// decisionLabel returns the label of a decision, see AuthorizerMetrics.RecordDecision.
+func decisionLabel(decision authorizer.Decision) string {
+	switch decision {
+	case authorizer.DecisionAllow:
+		return "allow"
+	case authorizer.DecisionDeny:
+		return "deny"
+	default:
+		return "no-opinion"
+	}
 }
 
 type noopMetrics struct{}
@@ -18,7 +18,10 @@ package webhook
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
@@ -119,3 +122,36 @@ func (f *fakeAuthorizerMetrics) RequestLatency(_ context.Context, code string, l
 	f.latency = latency
 	f.latencyCode = code
 }
+
+func TestAuthorizerDecisionMetrics(t *testing.T) {
+	reviewer := &fakeSubjectAccessReviewer{denyUsers: map[string]bool{"mallory": true}}
+	var decisions []string
+	metrics := noopAuthorizerMetrics()
+	metrics.RecordDecision = func(_ context.Context, decision string, failed bool) {
+		decisions = append(decisions, fmt.Sprintf("%s/%t", decision, failed))
+	}
+	wh, err := newWithBackoff(reviewer, time.Hour, time.Hour, testRetryBackoff, metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wh.SetMatchConditions([]MatchCondition{{Name: "authenticated", Expression: "request.user != 'system:anonymous'"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"jane", "mallory", "system:anonymous"} {
+		attr := authorizer.AttributesRecord{User: &user.DefaultInfo{Name: name}, Verb: "get", Resource: "pods", ResourceRequest: true}
+		if _, _, err := wh.Authorize(context.Background(), attr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// dry runs are not recorded
+	attr := authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "jane"}, Verb: "get", Resource: "pods", ResourceRequest: true}
+	if _, _, err := wh.Authorize(authorizer.WithDryRun(context.Background()), attr); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{"allow/false", "deny/false", "no-opinion/false"}
+	if !reflect.DeepEqual(decisions, expect) {
+		t.Errorf("expected decisions %v, got %v", expect, decisions)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	utilflowcontrol "k8s.io/apiserver/pkg/util/flowcontrol"
+)
+
+// DefaultPriorityLevels orders the priority levels of the suggested API Priority and Fairness
+// configuration from the highest to the lowest priority.
+var DefaultPriorityLevels = []string{
+	"exempt",
+	"system",
+	"node-high",
+	"leader-election",
+	"workload-high",
+	"workload-low",
+	"global-default",
+	"catch-all",
+}
+
+// PriorityQueueConfig configures the rate limit of the reviews sent to the webhook, and the order
+// in which the reviews waiting for the rate limit are sent.
+type PriorityQueueConfig struct {
+	// QPS and Burst are the rate and burst of reviews sent to the webhook.
+	QPS   float32
+	Burst int
+	// PriorityLevels orders the names of API Priority and Fairness priority levels from the highest
+	// to the lowest priority. Reviews of requests executed at a higher priority level are sent first,
+	// reviews of the same priority level in order of arrival. Reviews of requests at priority levels
+	// that are not listed, or that were not classified, are sent last. If empty, DefaultPriorityLevels
+	// is used.
+	PriorityLevels []string
+}
+
+// SetPriorityQueue rate limits the reviews sent to the webhook, queueing the reviews exceeding the
+// limit by the priority level of their requests, so that e.g. system and leader election traffic is
+// authorized ahead of bulk controllers once the limit is saturated. The client of the webhook should
+// not rate limit the reviews itself, which would queue them in order of arrival. A QPS of zero
+// removes the rate limit. It must be called before the authorizer is used.
+func (w *WebhookAuthorizer) SetPriorityQueue(config PriorityQueueConfig) {
+	if config.QPS <= 0 {
+		w.priorityQueue = nil
+		return
+	}
+	levels := config.PriorityLevels
+	if len(levels) == 0 {
+		levels = DefaultPriorityLevels
+	}
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	w.priorityQueue = newPriorityQueue(rate.NewLimiter(rate.Limit(config.QPS), burst), levels, w.metrics)
+}
+
+// priorityQueue hands out the tokens of a rate limiter to the waiters of the highest priority first.
+type priorityQueue struct {
+	limiter *rate.Limiter
+	// ranks maps the names of priority levels to their index in waiters.
+	ranks   map[string]int
+	metrics AuthorizerMetrics
+
+	lock sync.Mutex
+	// waiters holds the waiters of each priority level, in order of arrival, from the highest to the
+	// lowest priority. The last entry holds the waiters of unlisted or unclassified priority levels.
+	waiters [][]*priorityWaiter
+	// dispatching is true while a goroutine hands out tokens to the waiters.
+	dispatching bool
+}
+
+type priorityWaiter struct {
+	// ready is closed once the waiter is given a token.
+	ready chan struct{}
+	// canceled is set once the waiter stops waiting, so that it is skipped.
+	canceled bool
+}
+
+func newPriorityQueue(limiter *rate.Limiter, levels []string, metrics AuthorizerMetrics) *priorityQueue {
+	ranks := make(map[string]int, len(levels))
+	for i, level := range levels {
+		if _, ok := ranks[level]; !ok {
+			ranks[level] = i
+		}
+	}
+	return &priorityQueue{
+		limiter: limiter,
+		ranks:   ranks,
+		metrics: metrics,
+		waiters: make([][]*priorityWaiter, len(levels)+1),
+	}
+}
+
+// wait blocks until a review for the request of ctx may be sent, or ctx is done. Nobody waits while
+// the rate limit is not exceeded.
+func (q *priorityQueue) wait(ctx context.Context) error {
+	level, _ := utilflowcontrol.PriorityLevelFrom(ctx)
+	rank, ok := q.ranks[level]
+	if !ok {
+		rank = len(q.waiters) - 1
+	}
+
+	q.lock.Lock()
+	if !q.dispatching && q.limiter.Allow() {
+		q.lock.Unlock()
+		return nil
+	}
+	waiter := &priorityWaiter{ready: make(chan struct{})}
+	q.waiters[rank] = append(q.waiters[rank], waiter)
+	if !q.dispatching {
+		q.dispatching = true
+		go q.dispatch()
+	}
+	q.lock.Unlock()
+
+	start := time.Now()
+	defer func() {
+		q.metrics.recordQueueWait(ctx, level, time.Since(start).Seconds())
+	}()
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		q.lock.Lock()
+		defer q.lock.Unlock()
+		select {
+		case <-waiter.ready:
+			// given a token meanwhile, use it
+			return nil
+		default:
+			waiter.canceled = true
+			return ctx.Err()
+		}
+	}
+}
+
+// dispatch hands out a token to the waiter of the highest priority at a time, until nobody waits.
+func (q *priorityQueue) dispatch() {
+	for {
+		// Pick the waiter only once the token is available, so that waiters of a higher priority
+		// arriving meanwhile are served first. The limiter cannot fail, its burst is positive.
+		_ = q.limiter.Wait(context.Background())
+
+		q.lock.Lock()
+		waiter := q.next()
+		if waiter == nil {
+			q.dispatching = false
+			q.lock.Unlock()
+			return
+		}
+		close(waiter.ready)
+		q.lock.Unlock()
+	}
+}
+
+// next removes and returns the first waiter that did not cancel, of the highest priority, or nil if
+// nobody waits. It must be called with the lock held.
+func (q *priorityQueue) next() *priorityWaiter {
+	for rank, waiters := range q.waiters {
+		for len(waiters) > 0 {
+			waiter := waiters[0]
+			waiters[0] = nil
+			waiters = waiters[1:]
+			if !waiter.canceled {
+				q.waiters[rank] = waiters
+				return waiter
+			}
+		}
+		q.waiters[rank] = nil
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	utilflowcontrol "k8s.io/apiserver/pkg/util/flowcontrol"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	var lock sync.Mutex
+	waits := map[string]int{}
+	metrics := noopAuthorizerMetrics()
+	metrics.RecordQueueWait = func(_ context.Context, priorityLevel string, _ float64) {
+		lock.Lock()
+		defer lock.Unlock()
+		waits[priorityLevel]++
+	}
+	q := newPriorityQueue(rate.NewLimiter(rate.Every(200*time.Millisecond), 1), DefaultPriorityLevels, metrics)
+	atLevel := func(level string) context.Context {
+		return utilflowcontrol.WithPriorityLevel(context.Background(), level)
+	}
+
+	// the burst is not queued
+	if err := q.wait(atLevel("workload-low")); err != nil {
+		t.Fatal(err)
+	}
+
+	queued := func() int {
+		q.lock.Lock()
+		defer q.lock.Unlock()
+		n := 0
+		for _, waiters := range q.waiters {
+			n += len(waiters)
+		}
+		return n
+	}
+	var served []string
+	var wg sync.WaitGroup
+	wait := func(ctx context.Context, name string) {
+		expected := queued() + 1
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := q.wait(ctx); err != nil {
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			served = append(served, name)
+		}()
+		for queued() != expected {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	canceledCtx, cancel := context.WithCancel(atLevel("exempt"))
+	wait(context.Background(), "unclassified")
+	wait(atLevel("custom"), "custom")
+	wait(atLevel("workload-low"), "workload-low")
+	wait(canceledCtx, "canceled")
+	wait(atLevel("leader-election"), "leader-election")
+	wait(atLevel("workload-low"), "workload-low-2")
+	wait(atLevel("system"), "system")
+	cancel()
+	wg.Wait()
+
+	expected := []string{"system", "leader-election", "workload-low", "workload-low-2", "unclassified", "custom"}
+	if !reflect.DeepEqual(served, expected) {
+		t.Errorf("expected the waiters to be served in order %v, got %v", expected, served)
+	}
+	if expected := map[string]int{"": 1, "custom": 1, "exempt": 1, "leader-election": 1, "system": 1, "workload-low": 2}; !reflect.DeepEqual(waits, expected) {
+		t.Errorf("expected recorded waits %v, got %v", expected, waits)
+	}
+	if n := queued(); n != 0 {
+		t.Errorf("expected no waiters left, got %d", n)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// recordingSubjectAccessReviewer allows all requests and records the annotations of the reviews.
+type recordingSubjectAccessReviewer struct {
+	annotations []map[string]string
+}
+
+func (f *recordingSubjectAccessReviewer) Create(_ context.Context, review *authorizationv1.SubjectAccessReview, _ metav1.CreateOptions) (*authorizationv1.SubjectAccessReview, int, error) {
+	f.annotations = append(f.annotations, review.Annotations)
+	return &authorizationv1.SubjectAccessReview{Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true}}, 200, nil
+}
+
+func TestSelectorAnnotations(t *testing.T) {
+	reviewer := &recordingSubjectAccessReviewer{}
+	wh, err := newWithBackoff(reviewer, time.Hour, time.Hour, testRetryBackoff, noopAuthorizerMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := func(fieldSelector, labelSelector string) authorizer.AttributesRecord {
+		attr := authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "system:node:node1"}, Verb: "list", Resource: "pods", ResourceRequest: true}
+		if len(fieldSelector) > 0 {
+			selector, err := fields.ParseSelector(fieldSelector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			attr.FieldSelectorRequirements = selector.Requirements()
+		}
+		if len(labelSelector) > 0 {
+			selector, err := labels.Parse(labelSelector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			attr.LabelSelectorRequirements, _ = selector.Requirements()
+		}
+		return attr
+	}
+	unparsable := list("", "")
+	unparsable.Verb = "watch"
+	_, unparsable.FieldSelectorParsingErr = fields.ParseSelector("spec.nodeName")
+	_, unparsable.LabelSelectorParsingErr = labels.Parse("app in (web")
+
+	for _, attr := range []authorizer.AttributesRecord{
+		list("spec.nodeName=node1", ""),
+		list("spec.nodeName=node2", "app=web,tier!=db"),
+		list("", ""),
+		unparsable,
+		// cached under the same keys as the requests above
+		list("spec.nodeName=node1", ""),
+		list("spec.nodeName=node2", "app=web,tier!=db"),
+	} {
+		if decision, _, err := wh.Authorize(context.Background(), attr); err != nil || decision != authorizer.DecisionAllow {
+			t.Fatalf("expected the request to be allowed, got %v, %v", decision, err)
+		}
+	}
+
+	expected := []map[string]string{
+		{FieldSelectorAnnotationKey: "spec.nodeName=node1"},
+		{FieldSelectorAnnotationKey: "spec.nodeName=node2", LabelSelectorAnnotationKey: "app=web,tier!=db"},
+		nil,
+		nil,
+	}
+	if !reflect.DeepEqual(reviewer.annotations, expected) {
+		t.Errorf("expected reviews annotated with %v, got %v", expected, reviewer.annotations)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+func TestTimeout(t *testing.T) {
+	attributes := authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "alice"}, Verb: "get", Path: "/healthz"}
+
+	testCases := []struct {
+		name             string
+		blocking         bool
+		canceled         bool
+		decision         authorizer.Decision
+		expectedDecision authorizer.Decision
+		expectErr        bool
+	}{
+		{
+			name:             "timeout deny",
+			blocking:         true,
+			decision:         authorizer.DecisionDeny,
+			expectedDecision: authorizer.DecisionDeny,
+		},
+		{
+			name:             "timeout no opinion",
+			blocking:         true,
+			decision:         authorizer.DecisionNoOpinion,
+			expectedDecision: authorizer.DecisionNoOpinion,
+		},
+		{
+			name:             "request canceled",
+			blocking:         true,
+			canceled:         true,
+			decision:         authorizer.DecisionDeny,
+			expectedDecision: authorizer.DecisionNoOpinion,
+			expectErr:        true,
+		},
+		{
+			name:             "decided in time",
+			decision:         authorizer.DecisionDeny,
+			expectedDecision: authorizer.DecisionAllow,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reviewer := &blockingSubjectAccessReviewer{release: make(chan struct{})}
+			if tc.blocking {
+				defer close(reviewer.release)
+			} else {
+				close(reviewer.release)
+			}
+			wh, err := newWithBackoff(reviewer, 0, 0, testRetryBackoff, noopAuthorizerMetrics())
+			if err != nil {
+				t.Fatal(err)
+			}
+			wh.SetTimeout(50*time.Millisecond, tc.decision)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.canceled {
+				cancel()
+			}
+			decision, reason, err := wh.Authorize(ctx, attributes)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error=%v, got %v", tc.expectErr, err)
+			}
+			if decision != tc.expectedDecision {
+				t.Errorf("expected decision %v, got %v", tc.expectedDecision, decision)
+			}
+			if tc.blocking && !tc.canceled && !strings.Contains(reason, "did not decide within") {
+				t.Errorf("expected the reason to explain the timeout, got %q", reason)
+			}
+		})
+	}
+}
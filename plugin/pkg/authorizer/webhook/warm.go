@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/klog/v2"
+)
+
+// WarmCache reviews the given requests with the webhook and caches the responses, so that the first
+// requests after a start are not all delayed by reviews. It reviews up to workers requests at a time,
+// and blocks until all are reviewed or ctx is done; run it in a goroutine to warm the cache in the
+// background. It returns the number of requests that were reviewed or found in the cache.
+func (w *WebhookAuthorizer) WarmCache(ctx context.Context, requests []authorizer.Attributes, workers int) int {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var (
+		lock   sync.Mutex
+		warmed int
+		wg     sync.WaitGroup
+	)
+	queue := make(chan authorizer.Attributes)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for attr := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				if _, _, err := w.Authorize(ctx, attr); err != nil {
+					klog.V(4).InfoS("Failed to warm the webhook authorizer cache", "user", attr.GetUser().GetName(), "verb", attr.GetVerb(), "err", err)
+					continue
+				}
+				lock.Lock()
+				warmed++
+				lock.Unlock()
+			}
+		}()
+	}
+
+enqueue:
+	for _, attr := range requests {
+		select {
+		case queue <- attr:
+		case <-ctx.Done():
+			break enqueue
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return warmed
+}
+
+// ReadAuditLogRequests returns the distinct requests of the audit events in r, which holds audit.k8s.io/v1
+// events in the JSON format of the audit log backend. Requests without a user are skipped.
+func ReadAuditLogRequests(r io.Reader) ([]authorizer.Attributes, error) {
+	var requests []authorizer.Attributes
+	seen := sets.NewString()
+	decoder := json.NewDecoder(r)
+	for i := 0; ; i++ {
+		event := &auditv1.Event{}
+		if err := decoder.Decode(event); err == io.EOF {
+			return requests, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read audit event %d: %v", i, err)
+		}
+		if len(event.User.Username) == 0 {
+			continue
+		}
+
+		attr := attributesFromAuditEvent(event)
+		// label selector requirements have no exported fields, so the selectors are keyed as they are sent
+		key, err := json.Marshal(struct {
+			Attributes authorizer.AttributesRecord
+			Selectors  map[string]string
+		}{attr, selectorAnnotations(attr)})
+		if err != nil {
+			return nil, err
+		}
+		if seen.Has(string(key)) {
+			continue
+		}
+		seen.Insert(string(key))
+		requests = append(requests, attr)
+	}
+}
+
+// attributesFromAuditEvent returns the authorization attributes of the request of an audit event.
+func attributesFromAuditEvent(event *auditv1.Event) authorizer.AttributesRecord {
+	attr := authorizer.AttributesRecord{
+		User: &user.DefaultInfo{
+			Name:   event.User.Username,
+			UID:    event.User.UID,
+			Groups: event.User.Groups,
+			Extra:  convertFromAuditExtra(event.User.Extra),
+		},
+		Verb: event.Verb,
+	}
+	if ref := event.ObjectRef; ref != nil {
+		attr.ResourceRequest = true
+		attr.Namespace = ref.Namespace
+		attr.APIGroup = ref.APIGroup
+		attr.APIVersion = ref.APIVersion
+		attr.Resource = ref.Resource
+		attr.Subresource = ref.Subresource
+		attr.Name = ref.Name
+		if u, err := url.ParseRequestURI(event.RequestURI); err == nil {
+			setSelectors(&attr, u.Query())
+		}
+		return attr
+	}
+	attr.Path = event.RequestURI
+	if u, err := url.ParseRequestURI(event.RequestURI); err == nil {
+		attr.Path = u.Path
+	}
+	return attr
+}
+
+// setSelectors sets the selectors of a list, watch or deletecollection request from its query.
+func setSelectors(attr *authorizer.AttributesRecord, query url.Values) {
+	switch attr.Verb {
+	case "list", "watch", "deletecollection":
+	default:
+		return
+	}
+	if fieldSelector := query.Get("fieldSelector"); len(fieldSelector) > 0 {
+		if selector, err := fields.ParseSelector(fieldSelector); err != nil {
+			attr.FieldSelectorParsingErr = err
+		} else {
+			attr.FieldSelectorRequirements = selector.Requirements()
+		}
+	}
+	if labelSelector := query.Get("labelSelector"); len(labelSelector) > 0 {
+		if selector, err := labels.Parse(labelSelector); err != nil {
+			attr.LabelSelectorParsingErr = err
+		} else {
+			attr.LabelSelectorRequirements, _ = selector.Requirements()
+		}
+	}
+}
+
+func convertFromAuditExtra(extra map[string]authenticationv1.ExtraValue) map[string][]string {
+	if extra == nil {
+		return nil
+	}
+	ret := map[string][]string{}
+	for k, v := range extra {
+		ret[k] = []string(v)
+	}
+	return ret
+}
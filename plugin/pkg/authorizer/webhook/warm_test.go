@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+const testAuditLog = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"RequestReceived","requestURI":"/api/v1/namespaces/default/pods?limit=500","verb":"list","user":{"username":"jane","groups":["developers"]},"objectRef":{"resource":"pods","namespace":"default","apiVersion":"v1"}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/api/v1/namespaces/default/pods?limit=500","verb":"list","user":{"username":"jane","groups":["developers"]},"objectRef":{"resource":"pods","namespace":"default","apiVersion":"v1"}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/apis/apps/v1/namespaces/default/deployments/web/scale","verb":"update","user":{"username":"jane","groups":["developers"],"extra":{"scopes":["all"]}},"objectRef":{"resource":"deployments","namespace":"default","name":"web","apiGroup":"apps","apiVersion":"v1","subresource":"scale"}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/api/v1/pods?labelSelector=app%3Dweb&fieldSelector=spec.nodeName%3Dnode1","verb":"list","user":{"username":"system:node:node1"},"objectRef":{"resource":"pods","apiVersion":"v1"}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/api/v1/pods?labelSelector=app%3Ddb&fieldSelector=spec.nodeName%3Dnode1","verb":"list","user":{"username":"system:node:node1"},"objectRef":{"resource":"pods","apiVersion":"v1"}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/api/v1/pods?labelSelector=app%3Dweb&fieldSelector=spec.nodeName%3Dnode1","verb":"list","user":{"username":"system:node:node1"},"objectRef":{"resource":"pods","apiVersion":"v1"}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/metrics?format=text","verb":"get","user":{"username":"prometheus"}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","stage":"ResponseComplete","requestURI":"/healthz","verb":"get","user":{}}
+`
+
+func TestReadAuditLogRequests(t *testing.T) {
+	requests, err := ReadAuditLogRequests(strings.NewReader(testAuditLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jane := &user.DefaultInfo{Name: "jane", Groups: []string{"developers"}}
+	nodePods := func(labelSelector string) authorizer.AttributesRecord {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attr := authorizer.AttributesRecord{
+			User:                      &user.DefaultInfo{Name: "system:node:node1"},
+			Verb:                      "list",
+			APIVersion:                "v1",
+			Resource:                  "pods",
+			ResourceRequest:           true,
+			FieldSelectorRequirements: fields.OneTermEqualSelector("spec.nodeName", "node1").Requirements(),
+		}
+		attr.LabelSelectorRequirements, _ = selector.Requirements()
+		return attr
+	}
+	expected := []authorizer.Attributes{
+		authorizer.AttributesRecord{User: jane, Verb: "list", Namespace: "default", APIVersion: "v1", Resource: "pods", ResourceRequest: true},
+		authorizer.AttributesRecord{
+			User:            &user.DefaultInfo{Name: "jane", Groups: []string{"developers"}, Extra: map[string][]string{"scopes": {"all"}}},
+			Verb:            "update",
+			Namespace:       "default",
+			APIGroup:        "apps",
+			APIVersion:      "v1",
+			Resource:        "deployments",
+			Subresource:     "scale",
+			Name:            "web",
+			ResourceRequest: true,
+		},
+		nodePods("app=web"),
+		nodePods("app=db"),
+		authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "prometheus"}, Verb: "get", Path: "/metrics"},
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests\n\t%#v\ngot\n\t%#v", expected, requests)
+	}
+
+	if _, err := ReadAuditLogRequests(strings.NewReader(testAuditLog + "{not json")); err == nil {
+		t.Error("expected an error reading a malformed audit log")
+	}
+}
+
+func TestWarmCache(t *testing.T) {
+	reviewer := &fakeEndpointReviewer{}
+	wh, err := newWithBackoff(reviewer, time.Hour, time.Hour, testRetryBackoff, noopAuthorizerMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests, err := ReadAuditLogRequests(strings.NewReader(testAuditLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if warmed := wh.WarmCache(context.Background(), requests, 2); warmed != len(requests) {
+		t.Errorf("expected %d warmed requests, got %d", len(requests), warmed)
+	}
+	if calls := reviewer.takeCalls(); calls != len(requests) {
+		t.Errorf("expected %d reviews, got %d", len(requests), calls)
+	}
+
+	for _, attr := range requests {
+		if decision, _, err := wh.Authorize(context.Background(), attr); err != nil || decision != authorizer.DecisionAllow {
+			t.Errorf("unexpected result for %#v: decision=%v, err=%v", attr, decision, err)
+		}
+	}
+	if calls := reviewer.takeCalls(); calls != 0 {
+		t.Errorf("expected the warmed requests to be cached, got %d reviews", calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if warmed := wh.WarmCache(ctx, requests, 2); warmed != 0 {
+		t.Errorf("expected no requests to be warmed after the context is done, got %d", warmed)
+	}
+}
@@ -20,19 +20,29 @@ package webhook
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	goruntime "runtime"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+
 	authorizationv1 "k8s.io/api/authorization/v1"
 	authorizationv1beta1 "k8s.io/api/authorization/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	utilflowcontrol "k8s.io/apiserver/pkg/util/flowcontrol"
 	"k8s.io/apiserver/pkg/util/webhook"
 	"k8s.io/client-go/kubernetes/scheme"
 	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
@@ -43,6 +53,28 @@ import (
 const (
 	// The maximum length of requester-controlled attributes to allow caching.
 	maxControlledAttrCacheSize = 10000
+
+	// DefaultCacheMaxEntries is the default size of each of the authorized and unauthorized response caches.
+	DefaultCacheMaxEntries = 8192
+
+	// sharedReviewTimeout bounds a review shared by concurrent identical checks, which is
+	// detached from the contexts of the requests waiting for it.
+	sharedReviewTimeout = 30 * time.Second
+
+	// FieldSelectorAnnotationKey and LabelSelectorAnnotationKey annotate the reviews of list, watch and
+	// deletecollection requests with the selectors of the requests, which the SubjectAccessReview API
+	// has no fields for, so that webhooks can decide per selector.
+	FieldSelectorAnnotationKey = "authorization.k8s.io/field-selector"
+	LabelSelectorAnnotationKey = "authorization.k8s.io/label-selector"
+)
+
+var errReviewCrash = errors.New("webhook authorizer request failed unexpectedly")
+
+// The results of response cache lookups, see AuthorizerMetrics.RecordCacheLookup.
+const (
+	CacheLookupAuthorized   = "authorized"
+	CacheLookupUnauthorized = "unauthorized"
+	CacheLookupMiss         = "miss"
 )
 
 // DefaultRetryBackoff returns the default backoff parameters for webhook retry.
@@ -60,12 +92,27 @@ type subjectAccessReviewer interface {
 
 type WebhookAuthorizer struct {
 	subjectAccessReview subjectAccessReviewer
-	responseCache       *cache.LRUExpireCache
-	authorizedTTL       time.Duration
-	unauthorizedTTL     time.Duration
-	retryBackoff        wait.Backoff
-	decisionOnError     authorizer.Decision
-	metrics             AuthorizerMetrics
+	// authorizedCache and unauthorizedCache hold the allowing and the other responses apart, so
+	// that many allowed requests cannot evict denials and vice versa.
+	authorizedCache   *cache.LRUExpireCache
+	unauthorizedCache *cache.LRUExpireCache
+	authorizedTTL     time.Duration
+	unauthorizedTTL   time.Duration
+	retryBackoff      wait.Backoff
+	decisionOnError   authorizer.Decision
+	metrics           AuthorizerMetrics
+	// matchConditions select the requests sent to the webhook. It is nil if all requests are sent.
+	matchConditions *matchConditionMatcher
+	// inflight coalesces concurrent identical reviews into a single request to the webhook.
+	inflight singleflight.Group
+	// timeout bounds the authorization of a request, after which decisionOnTimeout is returned. Zero means no bound.
+	timeout           time.Duration
+	decisionOnTimeout authorizer.Decision
+	// priorityQueue rate limits the reviews, queueing them by priority. It is nil if they are not rate limited.
+	priorityQueue *priorityQueue
+	// cacheGeneration is incremented by each invalidation of the caches, so that reviews started
+	// before an invalidation do not cache their possibly stale responses.
+	cacheGeneration atomic.Uint64
 }
 
 // NewFromInterface creates a WebhookAuthorizer using the given subjectAccessReview client
@@ -107,7 +154,8 @@ func New(config *rest.Config, version string, authorizedTTL, unauthorizedTTL tim
 func newWithBackoff(subjectAccessReview subjectAccessReviewer, authorizedTTL, unauthorizedTTL time.Duration, retryBackoff wait.Backoff, metrics AuthorizerMetrics) (*WebhookAuthorizer, error) {
 	return &WebhookAuthorizer{
 		subjectAccessReview: subjectAccessReview,
-		responseCache:       cache.NewLRUExpireCache(8192),
+		authorizedCache:     cache.NewLRUExpireCache(DefaultCacheMaxEntries),
+		unauthorizedCache:   cache.NewLRUExpireCache(DefaultCacheMaxEntries),
 		authorizedTTL:       authorizedTTL,
 		unauthorizedTTL:     unauthorizedTTL,
 		retryBackoff:        retryBackoff,
@@ -116,6 +164,80 @@ func newWithBackoff(subjectAccessReview subjectAccessReviewer, authorizedTTL, un
 	}, nil
 }
 
+// SetCacheSizes sets the maximum number of entries of the authorized and of the unauthorized
+// response caches, dropping the cached responses. It must be called before the authorizer is used.
+func (w *WebhookAuthorizer) SetCacheSizes(authorizedMaxEntries, unauthorizedMaxEntries int) {
+	w.authorizedCache = cache.NewLRUExpireCache(authorizedMaxEntries)
+	w.unauthorizedCache = cache.NewLRUExpireCache(unauthorizedMaxEntries)
+}
+
+// InvalidateAll drops all cached responses, so that subsequent requests are reviewed by the webhook,
+// e.g. to make a revocation of permissions effective before the cached responses expire.
+func (w *WebhookAuthorizer) InvalidateAll() {
+	w.cacheGeneration.Add(1)
+	for _, c := range []*cache.LRUExpireCache{w.authorizedCache, w.unauthorizedCache} {
+		for _, key := range c.Keys() {
+			c.Remove(key)
+		}
+	}
+}
+
+// InvalidateUser drops the cached responses for the user with the given name, so that subsequent
+// requests of the user are reviewed by the webhook. Controllers watching the bindings of roles call
+// it when the permissions of a user change, to bound the staleness of cached decisions. A change of
+// the permissions of a group affects all its members, and calls for InvalidateAll instead.
+func (w *WebhookAuthorizer) InvalidateUser(name string) {
+	w.cacheGeneration.Add(1)
+	for _, c := range []*cache.LRUExpireCache{w.authorizedCache, w.unauthorizedCache} {
+		for _, key := range c.Keys() {
+			var review authorizationv1.SubjectAccessReview
+			if err := json.Unmarshal([]byte(key.(string)), &review); err != nil || review.Spec.User == name {
+				c.Remove(key)
+			}
+		}
+	}
+}
+
+// cachedStatus returns the cached response for key, recording the lookup.
+func (w *WebhookAuthorizer) cachedStatus(ctx context.Context, key string) (authorizationv1.SubjectAccessReviewStatus, bool) {
+	if entry, ok := w.authorizedCache.Get(key); ok {
+		w.metrics.recordCacheLookup(ctx, CacheLookupAuthorized)
+		return entry.(authorizationv1.SubjectAccessReviewStatus), true
+	}
+	if entry, ok := w.unauthorizedCache.Get(key); ok {
+		w.metrics.recordCacheLookup(ctx, CacheLookupUnauthorized)
+		return entry.(authorizationv1.SubjectAccessReviewStatus), true
+	}
+	w.metrics.recordCacheLookup(ctx, CacheLookupMiss)
+	return authorizationv1.SubjectAccessReviewStatus{}, false
+}
+
+// SetTimeout bounds the time the authorizer takes to decide on a request, including the retries of
+// the review, independently of the deadline of the request. If the timeout expires, decisionOnTimeout
+// is returned without an error, e.g. DecisionDeny to fail closed, or DecisionNoOpinion to leave the
+// decision to the next authorizer. A timeout of zero removes the bound. It must be called before the
+// authorizer is used.
+func (w *WebhookAuthorizer) SetTimeout(timeout time.Duration, decisionOnTimeout authorizer.Decision) {
+	w.timeout = timeout
+	w.decisionOnTimeout = decisionOnTimeout
+}
+
+// SetMatchConditions restricts the requests sent to the webhook to those matching all of the
+// given CEL conditions. The authorizer has no opinion on other requests, which saves the round
+// trip for requests the webhook can never decide. It must be called before the authorizer is used.
+func (w *WebhookAuthorizer) SetMatchConditions(conditions []MatchCondition) error {
+	if len(conditions) == 0 {
+		w.matchConditions = nil
+		return nil
+	}
+	matcher, err := compileMatchConditions(conditions)
+	if err != nil {
+		return err
+	}
+	w.matchConditions = matcher
+	return nil
+}
+
 // Authorize makes a REST request to the remote service describing the attempted action as a JSON
 // serialized api.authorization.v1beta1.SubjectAccessReview object. An example request body is
 // provided below.
@@ -164,6 +286,26 @@ func newWithBackoff(subjectAccessReview subjectAccessReviewer, authorizedTTL, un
 // encounter an error. We are failing open now to preserve backwards compatible
 // behavior.
 func (w *WebhookAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (decision authorizer.Decision, reason string, err error) {
+	if !authorizer.IsDryRun(ctx) {
+		defer func() { w.metrics.recordDecision(ctx, decision, err) }()
+	}
+	if w.timeout <= 0 {
+		return w.authorize(ctx, attr)
+	}
+
+	authorizeCtx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	decision, reason, err = w.authorize(authorizeCtx, attr)
+	// Only the expiry of the own timeout is a timeout, the request may also have been canceled or timed out.
+	if err != nil && authorizeCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		klog.V(2).InfoS("Webhook authorizer timed out", "timeout", w.timeout, "decision", w.decisionOnTimeout, "err", err)
+		return w.decisionOnTimeout, fmt.Sprintf("the authorization webhook did not decide within %v", w.timeout), nil
+	}
+	return decision, reason, err
+}
+
+// authorize authorizes a request as described by Authorize, without the timeout.
+func (w *WebhookAuthorizer) authorize(ctx context.Context, attr authorizer.Attributes) (decision authorizer.Decision, reason string, err error) {
 	r := &authorizationv1.SubjectAccessReview{}
 	if user := attr.GetUser(); user != nil {
 		r.Spec = authorizationv1.SubjectAccessReviewSpec{
@@ -190,48 +332,30 @@ func (w *WebhookAuthorizer) Authorize(ctx context.Context, attr authorizer.Attri
 			Verb: attr.GetVerb(),
 		}
 	}
-	key, err := json.Marshal(r.Spec)
+	r.Annotations = selectorAnnotations(attr)
+	if w.matchConditions != nil {
+		matched, err := w.matchConditions.match(&r.Spec)
+		if err != nil {
+			return w.decisionOnError, "", err
+		}
+		if !matched {
+			return authorizer.DecisionNoOpinion, "", nil
+		}
+	}
+	// the selectors are annotations, so the key covers the whole review
+	key, err := json.Marshal(r)
 	if err != nil {
 		return w.decisionOnError, "", err
 	}
-	if entry, ok := w.responseCache.Get(string(key)); ok {
-		r.Status = entry.(authorizationv1.SubjectAccessReviewStatus)
+	if status, ok := w.cachedStatus(ctx, string(key)); ok {
+		r.Status = status
 	} else {
-		var result *authorizationv1.SubjectAccessReview
-		// WithExponentialBackoff will return SAR create error (sarErr) if any.
-		if err := webhook.WithExponentialBackoff(ctx, w.retryBackoff, func() error {
-			var sarErr error
-			var statusCode int
-
-			start := time.Now()
-			result, statusCode, sarErr = w.subjectAccessReview.Create(ctx, r, metav1.CreateOptions{})
-			latency := time.Since(start)
-
-			if statusCode != 0 {
-				w.metrics.RecordRequestTotal(ctx, strconv.Itoa(statusCode))
-				w.metrics.RecordRequestLatency(ctx, strconv.Itoa(statusCode), latency.Seconds())
-				return sarErr
-			}
-
-			if sarErr != nil {
-				w.metrics.RecordRequestTotal(ctx, "<error>")
-				w.metrics.RecordRequestLatency(ctx, "<error>", latency.Seconds())
-			}
-
-			return sarErr
-		}, webhook.DefaultShouldRetry); err != nil {
+		status, err := w.coalescedReview(ctx, r, string(key), shouldCache(attr) && !authorizer.IsDryRun(ctx))
+		if err != nil {
 			klog.Errorf("Failed to make webhook authorizer request: %v", err)
 			return w.decisionOnError, "", err
 		}
-
-		r.Status = result.Status
-		if shouldCache(attr) {
-			if r.Status.Allowed {
-				w.responseCache.Add(string(key), r.Status, w.authorizedTTL)
-			} else {
-				w.responseCache.Add(string(key), r.Status, w.unauthorizedTTL)
-			}
-		}
+		r.Status = status
 	}
 	switch {
 	case r.Status.Denied && r.Status.Allowed:
@@ -246,6 +370,91 @@ func (w *WebhookAuthorizer) Authorize(ctx context.Context, attr authorizer.Attri
 
 }
 
+// coalescedReview reviews r with the webhook, sharing the review with the concurrent calls for the
+// same key, so that a burst of identical checks results in a single request to the webhook.
+func (w *WebhookAuthorizer) coalescedReview(ctx context.Context, r *authorizationv1.SubjectAccessReview, key string, cacheable bool) (authorizationv1.SubjectAccessReviewStatus, error) {
+	c := w.inflight.DoChan(key, func() (val interface{}, err error) {
+		// The review runs outside of the request handling stack of the callers, so handle crashes here.
+		defer func() {
+			if r := recover(); r != nil {
+				err = errReviewCrash
+				const size = 64 << 10
+				buf := make([]byte, size)
+				buf = buf[:goruntime.Stack(buf, false)]
+				klog.Errorf("%v\n%s", r, buf)
+			}
+		}()
+
+		// Detach the context because the review may be shared by multiple callers,
+		// however propagate the span and the priority level of the caller that started the review.
+		detached := trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+		if level, ok := utilflowcontrol.PriorityLevelFrom(ctx); ok {
+			detached = utilflowcontrol.WithPriorityLevel(detached, level)
+		}
+		ctx, cancel := context.WithTimeout(detached, sharedReviewTimeout)
+		defer cancel()
+
+		return w.review(ctx, r, key, cacheable)
+	})
+
+	select {
+	case result := <-c:
+		if result.Err != nil {
+			return authorizationv1.SubjectAccessReviewStatus{}, result.Err
+		}
+		return result.Val.(authorizationv1.SubjectAccessReviewStatus), nil
+	case <-ctx.Done():
+		return authorizationv1.SubjectAccessReviewStatus{}, ctx.Err()
+	}
+}
+
+// review sends r to the webhook, retrying with backoff, and caches the response under key if cacheable
+// and the caches were not invalidated meanwhile.
+func (w *WebhookAuthorizer) review(ctx context.Context, r *authorizationv1.SubjectAccessReview, key string, cacheable bool) (authorizationv1.SubjectAccessReviewStatus, error) {
+	generation := w.cacheGeneration.Load()
+	var result *authorizationv1.SubjectAccessReview
+	// WithExponentialBackoff will return SAR create error (sarErr) if any.
+	if err := webhook.WithExponentialBackoff(ctx, w.retryBackoff, func() error {
+		var sarErr error
+		var statusCode int
+
+		if w.priorityQueue != nil {
+			if err := w.priorityQueue.wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		start := time.Now()
+		result, statusCode, sarErr = w.subjectAccessReview.Create(ctx, r, metav1.CreateOptions{})
+		latency := time.Since(start)
+
+		if statusCode != 0 {
+			w.metrics.RecordRequestTotal(ctx, strconv.Itoa(statusCode))
+			w.metrics.RecordRequestLatency(ctx, strconv.Itoa(statusCode), latency.Seconds())
+			return sarErr
+		}
+
+		if sarErr != nil {
+			w.metrics.RecordRequestTotal(ctx, "<error>")
+			w.metrics.RecordRequestLatency(ctx, "<error>", latency.Seconds())
+		}
+
+		return sarErr
+	}, webhook.DefaultShouldRetry); err != nil {
+		return authorizationv1.SubjectAccessReviewStatus{}, err
+	}
+
+	// an invalidation during the review may have been meant for this response
+	if cacheable && w.cacheGeneration.Load() == generation {
+		if result.Status.Allowed {
+			w.authorizedCache.Add(key, result.Status, w.authorizedTTL)
+		} else {
+			w.unauthorizedCache.Add(key, result.Status, w.unauthorizedTTL)
+		}
+	}
+	return result.Status, nil
+}
+
 // TODO: need to finish the method to get the rules when using webhook mode
 func (w *WebhookAuthorizer) RulesFor(user user.Info, namespace string) ([]authorizer.ResourceRuleInfo, []authorizer.NonResourceRuleInfo, bool, error) {
 	var (
@@ -352,7 +561,10 @@ type subjectAccessReviewV1beta1ClientGW struct {
 
 func (t *subjectAccessReviewV1beta1ClientGW) Create(ctx context.Context, subjectAccessReview *authorizationv1.SubjectAccessReview, _ metav1.CreateOptions) (*authorizationv1.SubjectAccessReview, int, error) {
 	var statusCode int
-	v1beta1Review := &authorizationv1beta1.SubjectAccessReview{Spec: v1SpecToV1beta1Spec(&subjectAccessReview.Spec)}
+	v1beta1Review := &authorizationv1beta1.SubjectAccessReview{
+		ObjectMeta: metav1.ObjectMeta{Annotations: subjectAccessReview.Annotations},
+		Spec:       v1SpecToV1beta1Spec(&subjectAccessReview.Spec),
+	}
 	v1beta1Result := &authorizationv1beta1.SubjectAccessReview{}
 
 	restResult := t.client.Post().Body(v1beta1Review).Do(ctx)
@@ -376,9 +588,39 @@ func shouldCache(attr authorizer.Attributes) bool {
 		int64(len(attr.GetSubresource())) +
 		int64(len(attr.GetName())) +
 		int64(len(attr.GetPath()))
+	if requirements, err := attr.GetFieldSelector(); err == nil {
+		for _, r := range requirements {
+			controlledAttrSize += int64(len(r.Field)) + int64(len(r.Value))
+		}
+	}
+	if requirements, err := attr.GetLabelSelector(); err == nil {
+		for _, r := range requirements {
+			controlledAttrSize += int64(len(r.String()))
+		}
+	}
 	return controlledAttrSize < maxControlledAttrCacheSize
 }
 
+// selectorAnnotations returns the annotations of the review of a request holding its selectors, or
+// nil if it has none. Selectors that failed to parse are left out, webhooks must not rely on them.
+func selectorAnnotations(attr authorizer.Attributes) map[string]string {
+	var annotations map[string]string
+	if requirements, err := attr.GetFieldSelector(); err == nil && len(requirements) > 0 {
+		terms := make([]string, 0, len(requirements))
+		for _, r := range requirements {
+			terms = append(terms, r.Field+string(r.Operator)+fields.EscapeValue(r.Value))
+		}
+		annotations = map[string]string{FieldSelectorAnnotationKey: strings.Join(terms, ",")}
+	}
+	if requirements, err := attr.GetLabelSelector(); err == nil && len(requirements) > 0 {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[LabelSelectorAnnotationKey] = labels.NewSelector().Add(requirements...).String()
+	}
+	return annotations
+}
+
 func v1beta1StatusToV1Status(in *authorizationv1beta1.SubjectAccessReviewStatus) authorizationv1.SubjectAccessReviewStatus {
 	return authorizationv1.SubjectAccessReviewStatus{
 		Allowed:         in.Allowed,